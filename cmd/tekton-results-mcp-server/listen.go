@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket
+// activation (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// listen opens the HTTP listeners. Sockets passed by systemd socket
+// activation are used when present; otherwise addresses is a comma separated
+// list of addresses, each listened on separately, e.g. "127.0.0.1:8080,[::1]:8080".
+func listen(addresses string) ([]net.Listener, error) {
+	activated, err := activationListeners()
+	if err != nil || len(activated) > 0 {
+		return activated, err
+	}
+
+	var listeners []net.Listener
+	for _, addr := range strings.Split(addresses, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, fmt.Errorf("listen on %s: %w", addr, err)
+		}
+		listeners = append(listeners, l)
+	}
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("no listen address in %q", addresses)
+	}
+	return listeners, nil
+}
+
+// activationListeners returns the sockets passed by systemd socket
+// activation, following sd_listen_fds(3): LISTEN_PID must name this process
+// and LISTEN_FDS counts descriptors starting at 3. The variables are unset so
+// child processes do not inherit them.
+func activationListeners() ([]net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pid == "" || fds == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	os.Unsetenv("LISTEN_PID")     //nolint:errcheck // Unsetenv only fails on invalid names
+	os.Unsetenv("LISTEN_FDS")     //nolint:errcheck // Unsetenv only fails on invalid names
+	os.Unsetenv("LISTEN_FDNAMES") //nolint:errcheck // Unsetenv only fails on invalid names
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS value %q", fds)
+	}
+	var listeners []net.Listener
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		file := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(file)
+		file.Close() //nolint:errcheck // FileListener duplicates the descriptor
+		if err != nil {
+			closeListeners(listeners)
+			return nil, fmt.Errorf("use socket-activated descriptor %d: %w", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, l := range listeners {
+		l.Close() //nolint:errcheck // Best-effort cleanup after a failed listen
+	}
+}
@@ -7,41 +7,83 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
-	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/enarha/tekton-results-mcp-server/internal/config"
+	"github.com/enarha/tekton-results-mcp-server/internal/logging"
+	"github.com/enarha/tekton-results-mcp-server/internal/sessions"
+	"github.com/enarha/tekton-results-mcp-server/internal/stdioguard"
 	"github.com/enarha/tekton-results-mcp-server/internal/tools"
+	resultsserver "github.com/enarha/tekton-results-mcp-server/pkg/server"
 	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"knative.dev/pkg/signals"
 )
 
+//go:generate sh -c "go run . -print-tools > ../../docs/tools.md"
+//go:generate sh -c "go run . -print-tools -print-tools-format=json > ../../docs/tools.json"
+
 func main() {
-	var transport string
-	var httpAddr string
-	flag.StringVar(&transport, "transport", "http", "Transport type (stdio or http)")
-	flag.StringVar(&httpAddr, "address", ":8080", "Address to bind the HTTP server to")
+	var printTools bool
+	var printToolsFormat string
+	loader := config.NewLoader(flag.CommandLine, nil)
+	flag.BoolVar(&printTools, "print-tools", false, "Print documentation for every tool, including write tools, and exit")
+	flag.StringVar(&printToolsFormat, "print-tools-format", "markdown", "Format used by -print-tools (markdown or json)")
+	flag.Usage = usage
 	flag.Parse()
 
-	// For stdio mode, disable slog output to avoid polluting the JSON-RPC protocol
+	if printTools {
+		// A fixed default namespace keeps the generated docs independent of
+		// the local kubeconfig.
+		defs, err := tools.Definitions(tools.Dependencies{DefaultNamespace: "default", AllowWrites: true, LiveCluster: true})
+		if err == nil {
+			err = tools.WriteDocs(os.Stdout, defs, printToolsFormat)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "print tools: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	conf, err := loader.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	transport := conf.Transport
+
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(conf.Level())
+	// In stdio mode stdout carries JSON-RPC: disable log output and guard
+	// stdout against stray writes from dependencies.
+	logOut := io.Writer(os.Stderr)
 	if transport == "stdio" {
-		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		logOut = io.Discard
+	}
+	slog.SetDefault(slog.New(logging.NewHandler(logOut, levelVar)))
+	logging.BridgeKlog(slog.Default(), conf.KlogVerbosity)
+	for _, warning := range loader.Warnings() {
+		slog.Warn(warning)
+	}
+
+	protocolOut := os.Stdout
+	if transport == "stdio" {
+		out, restore, err := stdioguard.Install(&stdioguard.Writer{Strict: conf.StrictStdio, Report: os.Stderr})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to guard stdout: %v\n", err)
+			os.Exit(1)
+		}
+		defer restore()
+		protocolOut = out
 	}
 
-	if httpAddr == "" && transport == "http" {
+	if conf.Address == "" && transport == "http" && os.Getenv("LISTEN_FDS") == "" {
 		slog.Error("-address is required when transport is set to 'hhtp'")
 		os.Exit(1)
 	}
 
-	// Create MCP server
-	s := server.NewMCPServer(
-		"Tekton Results MCP Server",
-		"0.0.1", // FIXME get this from internal package
-		server.WithToolCapabilities(true),
-		server.WithLogging(),
-	)
-
 	ctx := signals.NewContext()
 
 	// Load kubernetes configuration
@@ -59,31 +101,26 @@ func main() {
 		namespace = "default"
 	}
 
-	overrides := tektonresults.Overrides{
-		Host:        os.Getenv("TEKTON_RESULTS_BASE_URL"),
-		BearerToken: os.Getenv("TEKTON_RESULTS_BEARER_TOKEN"),
-	}
-	if v := os.Getenv("TEKTON_RESULTS_INSECURE_SKIP_VERIFY"); v != "" {
-		if b, parseErr := strconv.ParseBool(v); parseErr == nil {
-			overrides.InsecureSkipVerify = b
-		} else {
-			slog.Warn("invalid TEKTON_RESULTS_INSECURE_SKIP_VERIFY value, ignoring", "value", v)
-		}
-	}
-
-	resultsSvc, err := tektonresults.NewService(cfg, overrides)
+	slog.Info("Adding tools to the server.")
+	srv, err := resultsserver.NewServer(serverConfig(conf, cfg, namespace))
 	if err != nil {
-		slog.Error(fmt.Sprintf("failed to initialize Tekton Results client: %v", err))
+		slog.Error(fmt.Sprintf("failed to create the MCP server: %v", err))
 		os.Exit(1)
 	}
-
-	slog.Info("Adding tools to the server.")
-	if err := tools.Add(s, tools.Dependencies{
-		Service:          resultsSvc,
-		DefaultNamespace: namespace,
-	}); err != nil {
-		slog.Error(fmt.Sprintf("failed to add tools: %v", err))
-		os.Exit(1)
+	go srv.Run(ctx)
+
+	if conf.ConfigFile != "" {
+		config.Watch(ctx, conf.ConfigFile, conf.ConfigPollInterval, func(file config.File) error {
+			reloaded, err := loader.Resolve(file)
+			if err != nil {
+				return err
+			}
+			if err := srv.Reconfigure(ctx, serverConfig(reloaded, cfg, namespace)); err != nil {
+				return err
+			}
+			levelVar.Set(reloaded.Level())
+			return nil
+		})
 	}
 
 	slog.Info("Starting the server.")
@@ -92,23 +129,39 @@ func main() {
 
 	switch transport {
 	case "http":
-		streamableHandler := server.NewStreamableHTTPServer(s)
+		sessionManager := sessions.New(conf.SessionIdleTimeout, conf.MaxSessions)
+		sessionManager.OnEnd(func(id string) { srv.UnregisterSession(ctx, id) })
+		go sessionManager.Run(ctx)
+		streamableHandler := sessionManager.Middleware(srv.CompletionHandler(server.NewStreamableHTTPServer(srv.MCPServer, server.WithSessionIdManager(sessionManager)), sessionManager))
+		metricsHandler := srv.MetricsHandler()
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/metrics" {
+				metricsHandler.ServeHTTP(w, r)
+				sessionManager.WritePrometheus(w)
+				return
+			}
 			streamableHandler.ServeHTTP(w, r.WithContext(ctx))
 		})
-		server := &http.Server{
-			Addr:              httpAddr,
+		listeners, err := listen(conf.Address)
+		if err != nil {
+			slog.Error(fmt.Sprintf("failed to listen: %v", err))
+			os.Exit(1)
+		}
+		httpServer := &http.Server{
 			Handler:           handler,
 			ReadHeaderTimeout: 3 * time.Second,
 		}
-		go func() {
-			errC <- server.ListenAndServe()
-		}()
-		slog.Info("Tekton Results MCP Server is listening at " + httpAddr)
+		errC = make(chan error, len(listeners))
+		for _, l := range listeners {
+			go func() {
+				errC <- httpServer.Serve(l)
+			}()
+			slog.Info("Tekton Results MCP Server is listening at " + l.Addr().String())
+		}
 	case "stdio":
-		stdioServer := server.NewStdioServer(s)
+		stdioServer := server.NewStdioServer(srv.MCPServer)
 		go func() {
-			in, out := io.Reader(os.Stdin), io.Writer(os.Stdout)
+			in, out := srv.CompletionStdio(ctx, os.Stdin, protocolOut)
 			errC <- stdioServer.Listen(ctx, in, out)
 		}()
 	default:
@@ -127,3 +180,63 @@ func main() {
 		}
 	}
 }
+
+// serverConfig returns the server configuration of the command
+// configuration conf, connecting through restConfig.
+func serverConfig(conf config.Config, restConfig *rest.Config, namespace string) resultsserver.Config {
+	shedWait := conf.ShedWait
+	if shedWait == 0 {
+		// Zero takes the default in resultsserver.Config.
+		shedWait = -1
+	}
+	return resultsserver.Config{
+		RESTConfig:         restConfig,
+		DefaultNamespace:   namespace,
+		ResultsURL:         conf.BaseURL,
+		BearerToken:        conf.BearerToken,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+		TokenStore:         conf.TokenStore,
+		NamespaceTokens:    conf.NamespaceTokens,
+		EnableWriteTools:   conf.EnableWriteTools,
+		EnableClusterTools: conf.EnableClusterTools,
+		ScanPageSize:       conf.ScanPageSize,
+		MaxScanPages:       conf.MaxScanPages,
+		UpstreamTimeout:    conf.UpstreamTimeout,
+		ShedWait:           shedWait,
+		MaxOutputSize:      conf.MaxOutputSize,
+		MaxResponseSize:    conf.MaxResponseSize,
+		DashboardURL:       conf.DashboardURL,
+		ValidateSchemas:    conf.ValidateSchemas,
+		LogArchive:         conf.LogArchive,
+		Teams:              conf.Teams,
+		GitHubToken:        conf.GitHubToken,
+		GitHubAPIURL:       conf.GitHubAPIURL,
+		AccessLog:          conf.AccessLog,
+		FaultInjection:     conf.FaultInjection,
+		Upstreams:          conf.Upstreams,
+		ExportSink:         conf.ExportSink,
+		ExportToken:        conf.ExportToken,
+		ExportInterval:     conf.ExportInterval,
+		ExportNamespace:    conf.ExportNamespace,
+		ExportState:        conf.ExportState,
+		Messages:           conf.Messages,
+		SigningKey:         []byte(conf.SigningKey),
+	}
+}
+
+// usage prints the command line help, skipping hidden flags.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	flag.VisitAll(func(f *flag.Flag) {
+		if config.Hidden(f.Name) {
+			return
+		}
+		name, usage := flag.UnquoteUsage(f)
+		fmt.Fprintf(out, "  -%s %s\n    \t%s", f.Name, name, usage)
+		if f.DefValue != "" {
+			fmt.Fprintf(out, " (default %q)", f.DefValue)
+		}
+		fmt.Fprintln(out)
+	})
+}
@@ -0,0 +1,111 @@
+#!/usr/bin/env bash
+# Spins up a kind cluster with Tekton Pipelines and Tekton Results, runs the
+# sample pipelines and executes the e2e test suite against the real APIs.
+#
+# Usage: hack/e2e.sh [up|test|down|all]   (default: all)
+
+set -euo pipefail
+
+CLUSTER_NAME="${E2E_CLUSTER_NAME:-tekton-results-mcp-e2e}"
+E2E_NAMESPACE="${E2E_NAMESPACE:-results-e2e}"
+PIPELINES_RELEASE="${PIPELINES_RELEASE:-https://storage.googleapis.com/tekton-releases/pipeline/latest/release.yaml}"
+RESULTS_RELEASE="${RESULTS_RELEASE:-https://storage.googleapis.com/tekton-releases/results/latest/release.yaml}"
+PORT_FORWARD_PORT="${PORT_FORWARD_PORT:-8443}"
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+WORK_DIR="${ROOT_DIR}/.e2e"
+
+log() { echo "==> $*"; }
+
+require() {
+	for bin in "$@"; do
+		command -v "${bin}" >/dev/null 2>&1 || { echo "missing required tool: ${bin}" >&2; exit 1; }
+	done
+}
+
+up() {
+	require kind kubectl openssl
+	mkdir -p "${WORK_DIR}"
+
+	if ! kind get clusters | grep -qx "${CLUSTER_NAME}"; then
+		log "Creating kind cluster ${CLUSTER_NAME}"
+		kind create cluster --name "${CLUSTER_NAME}" --wait 120s
+	fi
+
+	log "Installing Tekton Pipelines"
+	kubectl apply -f "${PIPELINES_RELEASE}"
+	kubectl wait --for=condition=Available deployment --all -n tekton-pipelines --timeout=300s
+
+	log "Preparing Tekton Results secrets"
+	if ! kubectl get secret tekton-results-postgres -n tekton-pipelines >/dev/null 2>&1; then
+		kubectl create secret generic tekton-results-postgres -n tekton-pipelines \
+			--from-literal=POSTGRES_USER=postgres \
+			--from-literal=POSTGRES_PASSWORD="$(openssl rand -hex 16)"
+	fi
+	if ! kubectl get secret tekton-results-tls -n tekton-pipelines >/dev/null 2>&1; then
+		local cn="tekton-results-api-service.tekton-pipelines.svc.cluster.local"
+		openssl req -x509 -newkey rsa:4096 -nodes -days 1 \
+			-keyout "${WORK_DIR}/key.pem" -out "${WORK_DIR}/cert.pem" \
+			-subj "/CN=${cn}" -addext "subjectAltName = DNS:${cn},DNS:localhost"
+		kubectl create secret tls tekton-results-tls -n tekton-pipelines \
+			--cert="${WORK_DIR}/cert.pem" --key="${WORK_DIR}/key.pem"
+	fi
+
+	log "Installing Tekton Results"
+	kubectl apply -f "${RESULTS_RELEASE}"
+	kubectl wait --for=condition=Available deployment --all -n tekton-pipelines --timeout=300s
+
+	log "Running sample pipelines in ${E2E_NAMESPACE}"
+	kubectl create namespace "${E2E_NAMESPACE}" --dry-run=client -o yaml | kubectl apply -f -
+	kubectl apply -n "${E2E_NAMESPACE}" -f "${ROOT_DIR}/test/e2e/testdata/"
+	kubectl wait --for=condition=Succeeded pipelinerun/e2e-sample-run -n "${E2E_NAMESPACE}" --timeout=300s
+	kubectl wait --for=condition=Succeeded taskrun/e2e-standalone-run -n "${E2E_NAMESPACE}" --timeout=300s
+	# Give the Results watcher time to archive the runs and their logs.
+	sleep 30
+}
+
+run_tests() {
+	require kubectl go
+	mkdir -p "${WORK_DIR}"
+
+	log "Building the MCP server"
+	go build -o "${WORK_DIR}/tekton-results-mcp-server" ./cmd/tekton-results-mcp-server
+
+	log "Port-forwarding the Results API to localhost:${PORT_FORWARD_PORT}"
+	kubectl port-forward -n tekton-pipelines service/tekton-results-api-service "${PORT_FORWARD_PORT}:8080" >"${WORK_DIR}/port-forward.log" 2>&1 &
+	local pf_pid=$!
+	trap 'kill ${pf_pid} 2>/dev/null || true' EXIT
+	sleep 5
+
+	kubectl create serviceaccount e2e-reader -n "${E2E_NAMESPACE}" --dry-run=client -o yaml | kubectl apply -f -
+	kubectl create clusterrolebinding e2e-reader-results --clusterrole=tekton-results-readonly \
+		--serviceaccount="${E2E_NAMESPACE}:e2e-reader" --dry-run=client -o yaml | kubectl apply -f -
+
+	log "Running e2e tests"
+	E2E_SERVER_BINARY="${WORK_DIR}/tekton-results-mcp-server" \
+	E2E_NAMESPACE="${E2E_NAMESPACE}" \
+	TEKTON_RESULTS_MCP_BASE_URL="https://localhost:${PORT_FORWARD_PORT}" \
+	TEKTON_RESULTS_MCP_BEARER_TOKEN="$(kubectl create token e2e-reader -n "${E2E_NAMESPACE}")" \
+	TEKTON_RESULTS_MCP_INSECURE_SKIP_VERIFY=true \
+		go test -v -count=1 -tags=e2e ./test/e2e/...
+}
+
+down() {
+	require kind
+	log "Deleting kind cluster ${CLUSTER_NAME}"
+	kind delete cluster --name "${CLUSTER_NAME}"
+	rm -rf "${WORK_DIR}"
+}
+
+case "${1:-all}" in
+up) up ;;
+test) run_tests ;;
+down) down ;;
+all)
+	up
+	run_tests
+	;;
+*)
+	echo "usage: $0 [up|test|down|all]" >&2
+	exit 1
+	;;
+esac
@@ -0,0 +1,146 @@
+// Package accounting accounts tool calls to the namespaces whose data they read,
+// so operators can see which teams' runs clients query most, and optionally
+// writes an access log line per call.
+package accounting
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// AllNamespaces is the namespace calls searching every namespace are
+// accounted to.
+const AllNamespaces = "-"
+
+// NoNamespace is the namespace calls that read no namespaced data, such as
+// server_info, are accounted to.
+const NoNamespace = "(none)"
+
+// Call is one finished tool call.
+type Call struct {
+	Tool    string
+	Session string   // MCP session ID, empty for the stdio transport
+	Spaces  []string // namespaces the call read; empty for NoNamespace
+	Bytes   int      // size of the text returned to the client
+	Elapsed time.Duration
+	Failed  bool
+}
+
+// Usage is the usage of one namespace since start.
+type Usage struct {
+	Namespace string         `json:"namespace"`
+	Queries   int            `json:"queries"`
+	Errors    int            `json:"errors"`
+	Bytes     int64          `json:"bytes"`
+	Tools     map[string]int `json:"tools"` // queries per tool
+}
+
+// Recorder accumulates usage per namespace. It is safe for concurrent use;
+// a nil *Recorder records nothing.
+type Recorder struct {
+	started   time.Time
+	accessLog bool
+
+	mu         sync.Mutex
+	namespaces map[string]*Usage
+}
+
+// New returns a Recorder. With accessLog set every call is also logged at
+// info level.
+func New(accessLog bool) *Recorder {
+	return &Recorder{started: time.Now(), accessLog: accessLog, namespaces: map[string]*Usage{}}
+}
+
+// Started returns when the recorder began counting.
+func (r *Recorder) Started() time.Time {
+	return r.started
+}
+
+// Record accounts c. A call reading several namespaces counts as a query of
+// each, and its bytes are split evenly between them so totals add up.
+func (r *Recorder) Record(c Call) {
+	if r == nil {
+		return
+	}
+	spaces := c.Spaces
+	if len(spaces) == 0 {
+		spaces = []string{NoNamespace}
+	}
+	if r.accessLog {
+		slog.Info("Tool call", "tool", c.Tool, "session", c.Session, "namespaces", spaces, "bytes", c.Bytes,
+			"duration", c.Elapsed.Round(time.Millisecond), "failed", c.Failed)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	share, rest := c.Bytes/len(spaces), c.Bytes%len(spaces)
+	for i, name := range spaces {
+		ns := r.namespaces[name]
+		if ns == nil {
+			ns = &Usage{Namespace: name, Tools: map[string]int{}}
+			r.namespaces[name] = ns
+		}
+		ns.Queries++
+		ns.Tools[c.Tool]++
+		if c.Failed {
+			ns.Errors++
+		}
+		ns.Bytes += int64(share)
+		if i < rest {
+			ns.Bytes++
+		}
+	}
+}
+
+// Snapshot returns the usage of every namespace, most bytes served first.
+func (r *Recorder) Snapshot() []Usage {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Usage, 0, len(r.namespaces))
+	for _, ns := range r.namespaces {
+		entry := *ns
+		entry.Tools = make(map[string]int, len(ns.Tools))
+		for tool, n := range ns.Tools {
+			entry.Tools[tool] = n
+		}
+		out = append(out, entry)
+	}
+	slices.SortFunc(out, func(a, b Usage) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(b.Queries, a.Queries), cmp.Compare(a.Namespace, b.Namespace))
+	})
+	return out
+}
+
+// WritePrometheus writes the usage counters in the Prometheus text
+// exposition format.
+func (r *Recorder) WritePrometheus(w io.Writer) {
+	usage := r.Snapshot()
+	slices.SortFunc(usage, func(a, b Usage) int { return cmp.Compare(a.Namespace, b.Namespace) })
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_namespace_queries_total Tool calls per namespace read and tool.\n# TYPE tekton_results_mcp_namespace_queries_total counter\n")
+	for _, ns := range usage {
+		tools := make([]string, 0, len(ns.Tools))
+		for tool := range ns.Tools {
+			tools = append(tools, tool)
+		}
+		slices.Sort(tools)
+		for _, tool := range tools {
+			fmt.Fprintf(w, "tekton_results_mcp_namespace_queries_total{namespace=%q,tool=%q} %d\n", ns.Namespace, tool, ns.Tools[tool])
+		}
+	}
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_namespace_errors_total Tool calls per namespace that failed or returned an error.\n# TYPE tekton_results_mcp_namespace_errors_total counter\n")
+	for _, ns := range usage {
+		fmt.Fprintf(w, "tekton_results_mcp_namespace_errors_total{namespace=%q} %d\n", ns.Namespace, ns.Errors)
+	}
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_namespace_bytes_served_total Bytes of tool results served per namespace read.\n# TYPE tekton_results_mcp_namespace_bytes_served_total counter\n")
+	for _, ns := range usage {
+		fmt.Fprintf(w, "tekton_results_mcp_namespace_bytes_served_total{namespace=%q} %d\n", ns.Namespace, ns.Bytes)
+	}
+}
@@ -0,0 +1,44 @@
+package accounting
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	r := New(false)
+	r.Record(Call{Tool: "pipelinerun_list", Spaces: []string{"ci"}, Bytes: 100})
+	r.Record(Call{Tool: "pipelinerun_logs", Spaces: []string{"ci"}, Bytes: 900, Failed: true})
+	r.Record(Call{Tool: "pipelinerun_list", Spaces: []string{"ci", "staging"}, Bytes: 11})
+	r.Record(Call{Tool: "server_info", Bytes: 50})
+
+	usage := r.Snapshot()
+	if len(usage) != 3 {
+		t.Fatalf("Expected 3 namespaces, got %+v", usage)
+	}
+	ci := usage[0]
+	if ci.Namespace != "ci" || ci.Queries != 3 || ci.Errors != 1 || ci.Bytes != 1006 || ci.Tools["pipelinerun_list"] != 2 {
+		t.Errorf("Unexpected usage of ci: %+v", ci)
+	}
+	if usage[1].Namespace != NoNamespace || usage[2].Namespace != "staging" || usage[2].Bytes != 5 {
+		t.Errorf("Expected calls without a namespace and split bytes, got %+v", usage[1:])
+	}
+
+	var b strings.Builder
+	r.WritePrometheus(&b)
+	for _, want := range []string{
+		`tekton_results_mcp_namespace_queries_total{namespace="ci",tool="pipelinerun_list"} 2`,
+		`tekton_results_mcp_namespace_errors_total{namespace="ci"} 1`,
+		`tekton_results_mcp_namespace_bytes_served_total{namespace="staging"} 5`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected %q in metrics:\n%s", want, b.String())
+		}
+	}
+
+	var nilRecorder *Recorder
+	nilRecorder.Record(Call{Tool: "x"})
+	if nilRecorder.Snapshot() != nil {
+		t.Error("Expected a nil recorder to record nothing")
+	}
+}
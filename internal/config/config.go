@@ -0,0 +1,460 @@
+// Package config resolves the server configuration from flags, environment
+// variables, the configuration file and defaults, in that order of
+// precedence.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/export"
+	"github.com/enarha/tekton-results-mcp-server/internal/logging"
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/proxy"
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// EnvPrefix starts the name of every environment variable owned by the
+// server.
+const EnvPrefix = "TEKTON_RESULTS_MCP_"
+
+// maxScanPageSize mirrors the largest page the Results API serves.
+const maxScanPageSize = 200
+
+// Config is the complete server configuration.
+type Config struct {
+	Transport          string
+	Address            string
+	SessionIdleTimeout time.Duration
+	MaxSessions        int
+	AccessLog          bool
+	LogLevel           string
+	KlogVerbosity      int
+	ScanPageSize       int
+	MaxScanPages       int
+	UpstreamTimeout    time.Duration
+	ShedWait           time.Duration
+	MaxResponseSize    string
+	MaxOutputSize      string
+	EnableWriteTools   bool
+	EnableClusterTools bool
+	FaultInjection     string
+	StrictStdio        bool
+	ConfigFile         string
+	ConfigPollInterval time.Duration
+	NamespaceTokens    []tektonresults.NamespaceToken // only set in the configuration file
+	Teams              []tektonresults.Team           // only set in the configuration file
+	Upstreams          []proxy.Upstream               // only set in the configuration file
+	Messages           map[string]string              // only set in the configuration file
+	GitHubAPIURL       string
+	DashboardURL       string
+	ValidateSchemas    bool
+	LogArchive         string
+	ExportSink         string
+	ExportInterval     time.Duration
+	ExportNamespace    string
+	ExportState        string
+
+	// Access to the Results API. These are read from the environment only,
+	// so credentials stay out of process listings.
+	BaseURL            string
+	BearerToken        string
+	InsecureSkipVerify bool
+	TokenStore         tektonresults.TokenStore
+	GitHubToken        string
+	ExportToken        string
+	SigningKey         string // signs tool results with HMAC-SHA256 when set
+}
+
+// Defaults returns the configuration used when no source sets a value.
+func Defaults() Config {
+	return Config{
+		Transport:          "http",
+		Address:            ":8080",
+		SessionIdleTimeout: 30 * time.Minute,
+		MaxSessions:        1000,
+		LogLevel:           "info",
+		ScanPageSize:       50,
+		MaxScanPages:       20,
+		UpstreamTimeout:    30 * time.Second,
+		ShedWait:           10 * time.Second,
+		MaxResponseSize:    "64Mi",
+		MaxOutputSize:      "64Ki",
+		ExportInterval:     5 * time.Minute,
+		ExportNamespace:    "-",
+	}
+}
+
+// option binds one Config field to its flag and environment variable.
+type option struct {
+	flag   string // empty for options that are only read from the environment
+	env    string
+	usage  string
+	hidden bool                // omitted from -help output
+	field  func(c *Config) any // pointer to the field: *string, *int, *bool or *time.Duration
+}
+
+var options = []option{
+	{flag: "transport", env: EnvPrefix + "TRANSPORT", usage: "Transport type (stdio or http)", field: func(c *Config) any { return &c.Transport }},
+	{flag: "address", env: EnvPrefix + "ADDRESS", usage: "Comma separated addresses to bind the HTTP server to, e.g. 127.0.0.1:8080,[::1]:8080; ignored when systemd passes listening sockets", field: func(c *Config) any { return &c.Address }},
+	{flag: "session-idle-timeout", env: EnvPrefix + "SESSION_IDLE_TIMEOUT", usage: "Expire HTTP MCP sessions that receive no request for this long; clients then initialize a new session (0 keeps sessions until the client ends them)", field: func(c *Config) any { return &c.SessionIdleTimeout }},
+	{flag: "max-sessions", env: EnvPrefix + "MAX_SESSIONS", usage: "Maximum number of open HTTP MCP sessions; further clients are refused with HTTP 503 until sessions end or expire (0 for no limit)", field: func(c *Config) any { return &c.MaxSessions }},
+	{flag: "access-log", env: EnvPrefix + "ACCESS_LOG", usage: "Log every tool call at info level with its session, the namespaces it read, the bytes served, its duration and outcome", field: func(c *Config) any { return &c.AccessLog }},
+	{flag: "fault-injection", env: EnvPrefix + "FAULT_INJECTION", hidden: true, usage: "Inject synthetic Results API faults, e.g. latency=200ms,errors=0.1,partial=0.2,malformed=0.05,seed=42 (testing only)", field: func(c *Config) any { return &c.FaultInjection }},
+	{flag: "scan-page-size", env: EnvPrefix + "SCAN_PAGE_SIZE", usage: "Records fetched per page when searching for a single run (1-200)", field: func(c *Config) any { return &c.ScanPageSize }},
+	{flag: "max-scan-pages", env: EnvPrefix + "MAX_SCAN_PAGES", usage: "Pages a single-run search, or a listing with filters applied after fetching, may scan before stopping with a request to narrow the query", field: func(c *Config) any { return &c.MaxScanPages }},
+	{flag: "upstream-timeout", env: EnvPrefix + "UPSTREAM_TIMEOUT", usage: "Deadline of each Tekton Results API request, including reading the response, so a stalled or slow upstream fails the call instead of holding it", field: func(c *Config) any { return &c.UpstreamTimeout }},
+	{flag: "shed-wait", env: EnvPrefix + "SHED_WAIT", usage: "How long calls of analytics tools such as failures_digest wait while the Tekton Results API is rate limiting or failing requests before they are deferred, keeping it free for lookups of single runs (0 defers them at once)", field: func(c *Config) any { return &c.ShedWait }},
+	{flag: "max-response-size", env: EnvPrefix + "MAX_RESPONSE_SIZE", usage: "Largest Tekton Results API response read, as a Kubernetes quantity such as 64Mi; larger responses fail with a request to narrow the query", field: func(c *Config) any { return &c.MaxResponseSize }},
+	{flag: "max-output-size", env: EnvPrefix + "MAX_OUTPUT_SIZE", usage: "Most text a tool result returns at once, as a Kubernetes quantity such as 64Ki; larger results return their first page and a resource link to the rest (0 returns results whole)", field: func(c *Config) any { return &c.MaxOutputSize }},
+	{flag: "enable-write-tools", env: EnvPrefix + "ENABLE_WRITE_TOOLS", usage: "Register tools that modify or delete data in Tekton Results, such as results_prune", field: func(c *Config) any { return &c.EnableWriteTools }},
+	{flag: "enable-cluster-tools", env: EnvPrefix + "ENABLE_CLUSTER_TOOLS", usage: "Register tools that act on live PipelineRuns through the Kubernetes API with the kubeconfig credentials, such as pipelinerun_rerun and pipelinerun_cancel; tools that change the cluster also require -enable-write-tools", field: func(c *Config) any { return &c.EnableClusterTools }},
+	{flag: "strict-stdio", env: EnvPrefix + "STRICT_STDIO", hidden: true, usage: "Panic at shutdown if anything but the stdio protocol wrote to stdout (testing only)", field: func(c *Config) any { return &c.StrictStdio }},
+	{flag: "log-level", env: EnvPrefix + "LOG_LEVEL", usage: "Minimum level of server logs (debug, info, warn or error)", field: func(c *Config) any { return &c.LogLevel }},
+	{flag: "klog-verbosity", env: EnvPrefix + "KLOG_VERBOSITY", usage: "Verbosity of Kubernetes client library logs routed into the server log; levels above 0 are logged at debug", field: func(c *Config) any { return &c.KlogVerbosity }},
+	{flag: "config", env: EnvPrefix + "CONFIG", usage: "Path to a YAML configuration file with log level, lookup limits, per-namespace bearer tokens, teams and upstream MCP servers; reloaded on SIGHUP", field: func(c *Config) any { return &c.ConfigFile }},
+	{flag: "config-poll-interval", env: EnvPrefix + "CONFIG_POLL_INTERVAL", usage: "Also reload the -config file when its content changes, checking at this interval (0 disables polling)", field: func(c *Config) any { return &c.ConfigPollInterval }},
+	{flag: "github-api-url", env: EnvPrefix + "GITHUB_API_URL", usage: "GitHub REST API to look up check runs of Pipelines as Code runs, for GitHub Enterprise Server (default https://api.github.com); requires the GitHub token in the environment", field: func(c *Config) any { return &c.GitHubAPIURL }},
+	{flag: "validate-schemas", env: EnvPrefix + "VALIDATE_SCHEMAS", usage: "Check runs fetched from Results against the Tekton v1 schema and warn about unknown or missing fields, e.g. data written by a newer Tekton release", field: func(c *Config) any { return &c.ValidateSchemas }},
+	{flag: "dashboard-url", env: EnvPrefix + "DASHBOARD_URL", usage: "URL template linking runs to a web UI, with {namespace}, {name}, {uid}, {kind} and {resource} placeholders, e.g. https://tekton.example.com/#/namespaces/{namespace}/{resource}/{name}", field: func(c *Config) any { return &c.DashboardURL }},
+	{flag: "log-archive", env: EnvPrefix + "LOG_ARCHIVE", usage: "Directory of exported Results logs, plain or compressed with zstd or gzip, read when the Results API cannot serve a log, e.g. after the cluster or Results instance was decommissioned", field: func(c *Config) any { return &c.LogArchive }},
+	{flag: "export-sink", env: EnvPrefix + "EXPORT_SINK", usage: "Periodically export summaries of finished runs to a warehouse: an https:// webhook receiving JSON batches or bigquery://<project>/<dataset>/<table>; the sink token is read from the environment", field: func(c *Config) any { return &c.ExportSink }},
+	{flag: "export-interval", env: EnvPrefix + "EXPORT_INTERVAL", usage: "Time between run exports to -export-sink", field: func(c *Config) any { return &c.ExportInterval }},
+	{flag: "export-namespace", env: EnvPrefix + "EXPORT_NAMESPACE", usage: "Namespace whose runs are exported to -export-sink, or - for all namespaces", field: func(c *Config) any { return &c.ExportNamespace }},
+	{flag: "export-state", env: EnvPrefix + "EXPORT_STATE", usage: "File keeping the export position across restarts; without it a restarted server exports from its most recent runs again", field: func(c *Config) any { return &c.ExportState }},
+
+	{env: EnvPrefix + "BASE_URL", field: func(c *Config) any { return &c.BaseURL }},
+	{env: EnvPrefix + "BEARER_TOKEN", field: func(c *Config) any { return &c.BearerToken }},
+	{env: EnvPrefix + "INSECURE_SKIP_VERIFY", field: func(c *Config) any { return &c.InsecureSkipVerify }},
+	{env: EnvPrefix + "BEARER_TOKEN_SECRET", field: func(c *Config) any { return &c.TokenStore.Secret }},
+	{env: EnvPrefix + "BEARER_TOKEN_VAULT", field: func(c *Config) any { return &c.TokenStore.Vault }},
+	{env: EnvPrefix + "BEARER_TOKEN_REFRESH", field: func(c *Config) any { return &c.TokenStore.Refresh }},
+	{env: EnvPrefix + "VAULT_ROLE", field: func(c *Config) any { return &c.TokenStore.VaultRole }},
+	{env: EnvPrefix + "VAULT_AUTH_PATH", field: func(c *Config) any { return &c.TokenStore.VaultAuthPath }},
+	{env: EnvPrefix + "GITHUB_TOKEN", field: func(c *Config) any { return &c.GitHubToken }},
+	{env: EnvPrefix + "EXPORT_TOKEN", field: func(c *Config) any { return &c.ExportToken }},
+	{env: EnvPrefix + "SIGNING_KEY", field: func(c *Config) any { return &c.SigningKey }},
+	// Vault's own variables, as understood by the vault CLI.
+	{env: "VAULT_ADDR", field: func(c *Config) any { return &c.TokenStore.VaultAddr }},
+	{env: "VAULT_TOKEN", field: func(c *Config) any { return &c.TokenStore.VaultToken }},
+	{env: "VAULT_NAMESPACE", field: func(c *Config) any { return &c.TokenStore.VaultNS }},
+}
+
+// legacyEnv maps environment variables from before every variable shared
+// EnvPrefix to their current names. They are still honored when the current
+// name is unset.
+var legacyEnv = map[string]string{
+	"TEKTON_RESULTS_BASE_URL":             EnvPrefix + "BASE_URL",
+	"TEKTON_RESULTS_BEARER_TOKEN":         EnvPrefix + "BEARER_TOKEN",
+	"TEKTON_RESULTS_INSECURE_SKIP_VERIFY": EnvPrefix + "INSECURE_SKIP_VERIFY",
+	"TEKTON_RESULTS_BEARER_TOKEN_SECRET":  EnvPrefix + "BEARER_TOKEN_SECRET",
+	"TEKTON_RESULTS_BEARER_TOKEN_VAULT":   EnvPrefix + "BEARER_TOKEN_VAULT",
+	"TEKTON_RESULTS_BEARER_TOKEN_REFRESH": EnvPrefix + "BEARER_TOKEN_REFRESH",
+	"TEKTON_RESULTS_VAULT_ROLE":           EnvPrefix + "VAULT_ROLE",
+	"TEKTON_RESULTS_VAULT_AUTH_PATH":      EnvPrefix + "VAULT_AUTH_PATH",
+}
+
+// Hidden reports whether the flag name is omitted from -help output.
+func Hidden(name string) bool {
+	for _, opt := range options {
+		if opt.flag == name {
+			return opt.hidden
+		}
+	}
+	return false
+}
+
+// Loader resolves a Config from its sources. Register its flags with
+// NewLoader before the flag set is parsed.
+type Loader struct {
+	fs      *flag.FlagSet
+	flagged Config
+	getenv  func(string) string
+}
+
+// NewLoader registers a flag for every option that has one on fs. getenv
+// looks up environment variables; nil uses os.Getenv.
+func NewLoader(fs *flag.FlagSet, getenv func(string) string) *Loader {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	l := &Loader{fs: fs, flagged: Defaults(), getenv: getenv}
+	for _, opt := range options {
+		if opt.flag == "" {
+			continue
+		}
+		usage := fmt.Sprintf("%s (env %s)", opt.usage, opt.env)
+		switch p := opt.field(&l.flagged).(type) {
+		case *string:
+			fs.StringVar(p, opt.flag, *p, usage)
+		case *int:
+			fs.IntVar(p, opt.flag, *p, usage)
+		case *bool:
+			fs.BoolVar(p, opt.flag, *p, usage)
+		case *time.Duration:
+			fs.DurationVar(p, opt.flag, *p, usage)
+		}
+	}
+	return l
+}
+
+// Load resolves the configuration, reading the configuration file named by
+// the -config flag or its environment variable.
+func (l *Loader) Load() (Config, error) {
+	base, err := l.Resolve(File{})
+	if err != nil {
+		return Config{}, err
+	}
+	file, err := LoadFile(base.ConfigFile)
+	if err != nil {
+		return Config{}, err
+	}
+	return l.Resolve(file)
+}
+
+// Resolve layers the sources over the defaults: file values, then
+// environment variables, then flags given on the command line. The result is
+// validated.
+func (l *Loader) Resolve(file File) (Config, error) {
+	cfg := Defaults()
+	file.apply(&cfg)
+
+	for _, opt := range options {
+		value, name := l.lookupEnv(opt.env)
+		if name == "" {
+			continue
+		}
+		if err := setField(opt.field(&cfg), value); err != nil {
+			return Config{}, fmt.Errorf("invalid %s value %q: %w", name, value, err)
+		}
+	}
+
+	given := map[string]bool{}
+	l.fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for _, opt := range options {
+		if opt.flag != "" && given[opt.flag] {
+			copyField(opt.field(&cfg), opt.field(&l.flagged))
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// lookupEnv returns the value of the variable env, falling back to its
+// legacy name. name is the variable the value came from, or empty when
+// neither is set.
+func (l *Loader) lookupEnv(env string) (value, name string) {
+	if value = l.getenv(env); value != "" {
+		return value, env
+	}
+	for legacy, current := range legacyEnv {
+		if current == env {
+			if value = l.getenv(legacy); value != "" {
+				return value, legacy
+			}
+		}
+	}
+	return "", ""
+}
+
+// Warnings describes the legacy environment variables in use, to be logged
+// once at startup.
+func (l *Loader) Warnings() []string {
+	var warnings []string
+	for legacy, current := range legacyEnv {
+		if l.getenv(legacy) == "" {
+			continue
+		}
+		if l.getenv(current) != "" {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored because %s is set", legacy, current))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated; rename it to %s", legacy, current))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+func setField(field any, value string) error {
+	switch p := field.(type) {
+	case *string:
+		*p = value
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("not an integer")
+		}
+		*p = n
+	case *bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("not a boolean")
+		}
+		*p = b
+	case *time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("not a duration")
+		}
+		*p = d
+	}
+	return nil
+}
+
+func copyField(dst, src any) {
+	switch p := dst.(type) {
+	case *string:
+		*p = *src.(*string)
+	case *int:
+		*p = *src.(*int)
+	case *bool:
+		*p = *src.(*bool)
+	case *time.Duration:
+		*p = *src.(*time.Duration)
+	}
+}
+
+// Validate checks values that every source could have set wrong.
+func (c Config) Validate() error {
+	if c.Transport != "http" && c.Transport != "stdio" {
+		return fmt.Errorf("invalid transport %q; must be http or stdio", c.Transport)
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		return err
+	}
+	if c.SessionIdleTimeout < 0 {
+		return fmt.Errorf("session idle timeout must not be negative")
+	}
+	if c.MaxSessions < 0 {
+		return fmt.Errorf("max sessions must not be negative")
+	}
+	if c.KlogVerbosity < 0 {
+		return fmt.Errorf("klog verbosity must not be negative")
+	}
+	if c.ScanPageSize < 1 || c.ScanPageSize > maxScanPageSize {
+		return fmt.Errorf("scan page size must be between 1 and %d", maxScanPageSize)
+	}
+	if c.MaxScanPages < 1 {
+		return fmt.Errorf("max scan pages must be positive")
+	}
+	if c.UpstreamTimeout <= 0 {
+		return fmt.Errorf("upstream timeout must be positive")
+	}
+	if c.ShedWait < 0 {
+		return fmt.Errorf("shed wait must not be negative")
+	}
+	if _, err := parseSize(c.MaxResponseSize); err != nil {
+		return fmt.Errorf("invalid max response size: %w", err)
+	}
+	if strings.TrimSpace(c.MaxOutputSize) != "0" {
+		if _, err := parseSize(c.MaxOutputSize); err != nil {
+			return fmt.Errorf("invalid max output size: %w", err)
+		}
+	}
+	if c.ConfigPollInterval < 0 {
+		return fmt.Errorf("config poll interval must not be negative")
+	}
+	if c.TokenStore.Refresh < 0 {
+		return fmt.Errorf("bearer token refresh interval must not be negative")
+	}
+	if _, err := tektonresults.ParseDashboardTemplate(c.DashboardURL); err != nil {
+		return fmt.Errorf("invalid dashboard URL: %w", err)
+	}
+	if c.ExportSink != "" {
+		if _, err := export.ParseSink(c.ExportSink, c.ExportToken); err != nil {
+			return err
+		}
+		if c.ExportInterval <= 0 {
+			return fmt.Errorf("export interval must be positive")
+		}
+	}
+	if err := proxy.Validate(c.Upstreams); err != nil {
+		return err
+	}
+	if err := messages.Validate(c.Messages); err != nil {
+		return fmt.Errorf("invalid messages: %w", err)
+	}
+	if c.SigningKey != "" {
+		if err := signing.ValidateKey([]byte(c.SigningKey)); err != nil {
+			return err
+		}
+	}
+	if c.FaultInjection != "" {
+		if _, err := tektonresults.ParseFaultConfig(c.FaultInjection); err != nil {
+			return fmt.Errorf("invalid fault injection: %w", err)
+		}
+	}
+	return nil
+}
+
+// Level returns the parsed log level. Validate has checked it.
+func (c Config) Level() slog.Level {
+	level, _ := logging.ParseLevel(c.LogLevel)
+	return level
+}
+
+// Settings returns the parts of the configuration the Results service can
+// apply while running.
+func (c Config) Settings() tektonresults.Settings {
+	return tektonresults.Settings{
+		ScanPageSize:    int32(c.ScanPageSize),
+		MaxScanPages:    c.MaxScanPages,
+		NamespaceTokens: c.NamespaceTokens,
+		Teams:           c.Teams,
+	}
+}
+
+// OutputLimit returns the most bytes of text a tool result returns at
+// once, or 0 when results are returned whole. Validate has checked it.
+func (c Config) OutputLimit() int {
+	n, _ := parseSize(c.MaxOutputSize)
+	return int(n)
+}
+
+// Overrides returns the options of the Results client.
+func (c Config) Overrides() tektonresults.Overrides {
+	overrides := tektonresults.Overrides{
+		Host:               c.BaseURL,
+		BearerToken:        c.BearerToken,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		ScanPageSize:       int32(c.ScanPageSize),
+		MaxScanPages:       c.MaxScanPages,
+		NamespaceTokens:    c.NamespaceTokens,
+		Teams:              c.Teams,
+		TokenStore:         c.TokenStore,
+		GitHub:             tektonresults.GitHubConfig{Token: c.GitHubToken, APIURL: c.GitHubAPIURL},
+		ValidateSchemas:    c.ValidateSchemas,
+		RequestTimeout:     c.UpstreamTimeout,
+		LogArchive:         c.LogArchive,
+	}
+	// Validate has parsed the spec, the template and the size already.
+	overrides.Faults, _ = tektonresults.ParseFaultConfig(c.FaultInjection)
+	overrides.MaxResponseBytes, _ = parseSize(c.MaxResponseSize)
+	overrides.Dashboard, _ = tektonresults.ParseDashboardTemplate(c.DashboardURL)
+	return overrides
+}
+
+// parseSize parses a positive byte count written as a Kubernetes quantity,
+// e.g. 64Mi or 100M.
+func parseSize(s string) (int64, error) {
+	q, err := resource.ParseQuantity(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	n, ok := q.AsInt64()
+	if !ok || n <= 0 {
+		return 0, fmt.Errorf("%q is not a positive number of bytes", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/proxy"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// newTestLoader returns a loader over a fresh flag set parsed from args,
+// reading environment variables from env.
+func newTestLoader(t *testing.T, env map[string]string, args ...string) *Loader {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	l := NewLoader(fs, func(name string) string { return env[name] })
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse(%v) error = %v", args, err)
+	}
+	return l
+}
+
+func TestResolve_Precedence(t *testing.T) {
+	file := File{LogLevel: "warn", ScanPageSize: 100, MaxScanPages: 40}
+	env := map[string]string{
+		EnvPrefix + "LOG_LEVEL":      "error",
+		EnvPrefix + "SCAN_PAGE_SIZE": "150",
+	}
+
+	cfg, err := newTestLoader(t, env, "-log-level=debug").Resolve(file)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("Expected the flag to win, got log level %q", cfg.LogLevel)
+	}
+	if cfg.ScanPageSize != 150 {
+		t.Errorf("Expected the environment to win over the file, got scan page size %d", cfg.ScanPageSize)
+	}
+	if cfg.MaxScanPages != 40 {
+		t.Errorf("Expected the file to win over the default, got max scan pages %d", cfg.MaxScanPages)
+	}
+	if cfg.Transport != "http" || cfg.Address != ":8080" {
+		t.Errorf("Expected defaults for unset options, got %q %q", cfg.Transport, cfg.Address)
+	}
+	if o := cfg.Overrides(); o.MaxResponseBytes != 64<<20 || o.RequestTimeout != 30*time.Second {
+		t.Errorf("Expected default upstream limits, got %d bytes and %s", o.MaxResponseBytes, o.RequestTimeout)
+	}
+
+	// A flag left at its default does not override other sources.
+	cfg, _ = newTestLoader(t, env).Resolve(file)
+	if cfg.LogLevel != "error" {
+		t.Errorf("Expected the environment without an explicit flag, got %q", cfg.LogLevel)
+	}
+}
+
+func TestResolve_EnvironmentOnlyOptions(t *testing.T) {
+	env := map[string]string{
+		EnvPrefix + "BASE_URL":             "https://results:8443",
+		EnvPrefix + "INSECURE_SKIP_VERIFY": "true",
+		EnvPrefix + "BEARER_TOKEN_VAULT":   "secret/data/results#token",
+		EnvPrefix + "BEARER_TOKEN_REFRESH": "1m",
+		"VAULT_ADDR":                       "https://vault:8200",
+		EnvPrefix + "ENABLE_WRITE_TOOLS":   "true",
+		EnvPrefix + "FAULT_INJECTION":      "errors=0.5",
+		EnvPrefix + "GITHUB_TOKEN":         "ghp_test",
+		EnvPrefix + "GITHUB_API_URL":       "https://github.example.com/api/v3",
+	}
+	cfg, err := newTestLoader(t, env).Resolve(File{})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	overrides := cfg.Overrides()
+	want := tektonresults.TokenStore{Vault: "secret/data/results#token", VaultAddr: "https://vault:8200", Refresh: time.Minute}
+	if overrides.Host != "https://results:8443" || !overrides.InsecureSkipVerify || overrides.TokenStore != want {
+		t.Errorf("Unexpected overrides %+v", overrides)
+	}
+	if !cfg.EnableWriteTools || !overrides.Faults.Enabled() {
+		t.Errorf("Expected boolean and fault options from the environment, got %+v", cfg)
+	}
+	if overrides.GitHub != (tektonresults.GitHubConfig{Token: "ghp_test", APIURL: "https://github.example.com/api/v3"}) {
+		t.Errorf("Unexpected GitHub config %+v", overrides.GitHub)
+	}
+}
+
+func TestResolve_LegacyEnvironment(t *testing.T) {
+	env := map[string]string{
+		"TEKTON_RESULTS_BASE_URL":     "https://legacy:8443",
+		"TEKTON_RESULTS_BEARER_TOKEN": "old-token",
+		EnvPrefix + "BEARER_TOKEN":    "new-token",
+	}
+	l := newTestLoader(t, env)
+	cfg, err := l.Resolve(File{})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if cfg.BaseURL != "https://legacy:8443" || cfg.BearerToken != "new-token" {
+		t.Errorf("Expected legacy names as a fallback only, got %q %q", cfg.BaseURL, cfg.BearerToken)
+	}
+	warnings := strings.Join(l.Warnings(), "\n")
+	for _, want := range []string{
+		"TEKTON_RESULTS_BASE_URL is deprecated; rename it to TEKTON_RESULTS_MCP_BASE_URL",
+		"TEKTON_RESULTS_BEARER_TOKEN is ignored because TEKTON_RESULTS_MCP_BEARER_TOKEN is set",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected warning %q, got:\n%s", want, warnings)
+		}
+	}
+	if w := newTestLoader(t, nil).Warnings(); len(w) != 0 {
+		t.Errorf("Expected no warnings without legacy variables, got %v", w)
+	}
+}
+
+func TestResolve_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		file File
+		want string
+	}{
+		{"malformed integer", map[string]string{EnvPrefix + "MAX_SCAN_PAGES": "many"}, nil, File{}, "invalid TEKTON_RESULTS_MCP_MAX_SCAN_PAGES value"},
+		{"malformed legacy boolean", map[string]string{"TEKTON_RESULTS_INSECURE_SKIP_VERIFY": "yes please"}, nil, File{}, "invalid TEKTON_RESULTS_INSECURE_SKIP_VERIFY value"},
+		{"malformed duration", map[string]string{EnvPrefix + "CONFIG_POLL_INTERVAL": "30"}, nil, File{}, "not a duration"},
+		{"transport", nil, []string{"-transport=sse"}, File{}, "invalid transport"},
+		{"log level in file", nil, nil, File{LogLevel: "loud"}, "invalid log level"},
+		{"page size", nil, []string{"-scan-page-size=500"}, File{}, "scan page size must be between 1 and 200"},
+		{"scan pages", nil, []string{"-max-scan-pages=0"}, File{}, "max scan pages must be positive"},
+		{"upstream timeout", nil, []string{"-upstream-timeout=0s"}, File{}, "upstream timeout must be positive"},
+		{"response size", map[string]string{EnvPrefix + "MAX_RESPONSE_SIZE": "lots"}, nil, File{}, "invalid max response size"},
+		{"negative response size", nil, []string{"-max-response-size=-1Mi"}, File{}, "not a positive number of bytes"},
+		{"output size", nil, []string{"-max-output-size=-64Ki"}, File{}, "invalid max output size"},
+		{"session idle timeout", nil, []string{"-session-idle-timeout=-1m"}, File{}, "session idle timeout must not be negative"},
+		{"max sessions", map[string]string{EnvPrefix + "MAX_SESSIONS": "-1"}, nil, File{}, "max sessions must not be negative"},
+		{"fault spec", map[string]string{EnvPrefix + "FAULT_INJECTION": "chaos"}, nil, File{}, "invalid fault injection"},
+		{"export sink", nil, []string{"-export-sink=postgres://warehouse/runs"}, File{}, "Postgres is not supported directly"},
+		{"export interval", nil, []string{"-export-sink=bigquery://proj/ci/runs", "-export-interval=0s"}, File{}, "export interval must be positive"},
+		{"dashboard URL", nil, []string{"-dashboard-url=https://tekton.example.com/{pipelinerun}"}, File{}, "invalid dashboard URL"},
+		{"upstream URL", nil, nil, File{Upstreams: []proxy.Upstream{{Name: "k8s", URL: "localhost:8081"}}}, "url must be an http(s) URL"},
+		{"message ID", nil, nil, File{Messages: map[string]string{"runNotFoud": "gone"}}, "unknown message \"runNotFoud\""},
+		{"message template", nil, nil, File{Messages: map[string]string{"errorFooter": "See {{.Tool"}}, "invalid messages: message errorFooter"},
+		{"signing key", map[string]string{EnvPrefix + "SIGNING_KEY": "hunter2"}, nil, File{}, "signing key must be at least 32 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestLoader(t, tt.env, tt.args...).Resolve(tt.file)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_ReadsConfigFileFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("maxScanPages: 7\nnamespaceTokens:\n  - namespaces: [ci]\n    token: abc\nteams:\n  - name: Payments\n    selectors: [team=payments]\nupstreams:\n  - name: k8s\n    url: http://localhost:8081/mcp\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := newTestLoader(t, map[string]string{EnvPrefix + "CONFIG": path}).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ConfigFile != path || cfg.MaxScanPages != 7 || len(cfg.NamespaceTokens) != 1 || len(cfg.Upstreams) != 1 {
+		t.Errorf("Unexpected configuration %+v", cfg)
+	}
+	if settings := cfg.Settings(); settings.MaxScanPages != 7 || settings.ScanPageSize != 50 || len(settings.Teams) != 1 || settings.Teams[0].Name != "Payments" {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+}
+
+func TestParseFile_RejectsUnknownFields(t *testing.T) {
+	if _, err := ParseFile("config.yaml", []byte("maxScanPage: 7\n")); err == nil || !strings.Contains(err.Error(), "config.yaml") {
+		t.Errorf("Expected unknown fields to be rejected, got %v", err)
+	}
+}
+
+func TestNewLoader_FlagUsage(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	NewLoader(fs, nil)
+	f := fs.Lookup("log-level")
+	if f == nil || !strings.Contains(f.Usage, "(env TEKTON_RESULTS_MCP_LOG_LEVEL)") || f.DefValue != "info" {
+		t.Errorf("Unexpected log-level flag %+v", f)
+	}
+	if !Hidden("fault-injection") || Hidden("log-level") {
+		t.Error("Expected only testing flags to be hidden")
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/proxy"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// File is the YAML configuration file passed with -config. Every field can
+// be changed by reloading the file; environment variables and flags take
+// precedence over it.
+type File struct {
+	LogLevel     string `json:"logLevel,omitempty"`
+	ScanPageSize int    `json:"scanPageSize,omitempty"`
+	MaxScanPages int    `json:"maxScanPages,omitempty"`
+	// NamespaceTokens maps namespaces to the bearer tokens used for requests
+	// targeting them; other namespaces use the default credential.
+	NamespaceTokens []tektonresults.NamespaceToken `json:"namespaceTokens"`
+	// Teams name the owners of runs by label selectors, for the team filter
+	// of the list tools.
+	Teams []tektonresults.Team `json:"teams,omitempty"`
+	// Upstreams are MCP servers whose tools are served under their name.
+	Upstreams []proxy.Upstream `json:"upstreams,omitempty"`
+	// Messages replace the wording of tool errors, by message ID.
+	Messages map[string]string `json:"messages,omitempty"`
+}
+
+// apply sets the fields of cfg the file sets.
+func (f File) apply(cfg *Config) {
+	if f.LogLevel != "" {
+		cfg.LogLevel = f.LogLevel
+	}
+	if f.ScanPageSize != 0 {
+		cfg.ScanPageSize = f.ScanPageSize
+	}
+	if f.MaxScanPages != 0 {
+		cfg.MaxScanPages = f.MaxScanPages
+	}
+	cfg.NamespaceTokens = f.NamespaceTokens
+	cfg.Teams = f.Teams
+	cfg.Upstreams = f.Upstreams
+	cfg.Messages = f.Messages
+}
+
+// LoadFile reads the configuration file at path. An empty path yields the
+// zero configuration.
+func LoadFile(path string) (File, error) {
+	if path == "" {
+		return File{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("read config file: %w", err)
+	}
+	return ParseFile(path, data)
+}
+
+// ParseFile decodes a configuration file. Unknown fields are rejected so
+// typos do not silently fall back to defaults.
+func ParseFile(path string, data []byte) (File, error) {
+	var f File
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return File{}, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Watch reloads the configuration file at path on SIGHUP and, when interval
+// is positive, whenever its content changes, until ctx is done. apply
+// receives every file that parses; when it fails, or the file cannot be read
+// or parsed, the running configuration is kept and the error logged. The
+// signal handler is installed before Watch returns.
+func Watch(ctx context.Context, path string, interval time.Duration, apply func(File) error) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	last, _ := os.ReadFile(path)
+
+	go func() {
+		defer signal.Stop(hup)
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			forced := false
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				forced = true
+			case <-tick:
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				slog.Warn("failed to reload config file, keeping the running configuration", "path", path, "error", err)
+				continue
+			}
+			if !forced && bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+
+			f, err := ParseFile(path, data)
+			if err == nil {
+				err = apply(f)
+			}
+			if err != nil {
+				slog.Warn("failed to reload config file, keeping the running configuration", "path", path, "error", err)
+				continue
+			}
+			slog.Info("reloaded config file", "path", path)
+		}
+	}()
+}
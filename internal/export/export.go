@@ -0,0 +1,373 @@
+// Package export copies summaries of finished runs from Tekton Results to a
+// data warehouse, so they stay available for analytics after Results prunes
+// them. Logs are not exported.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const (
+	defaultBatchSize = 200
+	// maxPending is how long a run seen unfinished is re-checked before it is
+	// given up on, e.g. because it was deleted before completing.
+	maxPending = 24 * time.Hour
+)
+
+// kinds are the run kinds exported, in the order they are synced.
+var kinds = []string{"pipelinerun", "taskrun"}
+
+// Source lists new runs and looks up runs by record. *tektonresults.Service
+// implements it.
+type Source interface {
+	RunsSince(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error)
+	GetRunByRecord(ctx context.Context, recordName string) (*tektonresults.RunDetail, error)
+}
+
+var _ Source = (*tektonresults.Service)(nil)
+
+// Row is one exported run. The JSON names are the warehouse column names.
+type Row struct {
+	Kind            string     `json:"kind"` // PipelineRun or TaskRun
+	Namespace       string     `json:"namespace"`
+	Name            string     `json:"name"`
+	UID             string     `json:"uid"`
+	Pipeline        string     `json:"pipeline,omitempty"`
+	Task            string     `json:"task,omitempty"`
+	PipelineTask    string     `json:"pipeline_task,omitempty"`
+	Status          string     `json:"status"`
+	Reason          string     `json:"reason,omitempty"`
+	Team            string     `json:"team,omitempty"`
+	StartTime       *time.Time `json:"start_time,omitempty"`
+	CompletionTime  *time.Time `json:"completion_time,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	RecordName      string     `json:"record_name"`
+	ExportedAt      time.Time  `json:"exported_at"`
+}
+
+func newRow(kind string, s tektonresults.RunSummary, now time.Time) Row {
+	row := Row{
+		Kind:         kind,
+		Namespace:    s.Namespace,
+		Name:         s.Name,
+		UID:          s.UID,
+		Pipeline:     s.Labels["tekton.dev/pipeline"],
+		Task:         s.Labels["tekton.dev/task"],
+		PipelineTask: s.PipelineTask,
+		Status:       s.Status,
+		Reason:       s.Reason,
+		Team:         s.Team,
+		RecordName:   s.RecordName,
+		ExportedAt:   now.UTC(),
+	}
+	if s.StartTime != nil {
+		t := s.StartTime.UTC()
+		row.StartTime = &t
+	}
+	if s.CompletionTime != nil {
+		t := s.CompletionTime.UTC()
+		row.CompletionTime = &t
+	}
+	if d, ok := s.Duration(); ok {
+		row.DurationSeconds = d.Seconds()
+	}
+	return row
+}
+
+// Options configure an Exporter.
+type Options struct {
+	Interval  time.Duration // between syncs
+	Namespace string        // a single namespace, or "-" or empty for all
+	StateFile string        // where cursors survive restarts; empty keeps them in memory
+	BatchSize int           // runs per sink write; 200 when zero
+}
+
+// state is what the exporter remembers between syncs.
+type state struct {
+	Cursors map[string]string    `json:"cursors"`           // RunsSince cursor per kind
+	Pending map[string]time.Time `json:"pending,omitempty"` // record name of an unfinished run -> first seen
+}
+
+// Exporter periodically writes the runs that finished since the previous
+// sync to a Sink. Runs are discovered in creation order through RunsSince;
+// those still running are remembered and exported once they finish. Writes
+// are at least once: a failed write is retried at the next sync, so sinks
+// should deduplicate on uid.
+type Exporter struct {
+	source Source
+	sink   Sink
+	opts   Options
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    state
+	exported int64
+	failures int64
+	lastSync time.Time // last sync that wrote everything it found
+}
+
+// New returns an exporter of runs from source to sink. State saved by a
+// previous process in opts.StateFile is resumed; a missing file starts with
+// the most recent runs.
+func New(source Source, sink Sink, opts Options) (*Exporter, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = "-"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	e := &Exporter{source: source, sink: sink, opts: opts, now: time.Now}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Run syncs at once and then at every interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if n, err := e.Sync(ctx); err != nil {
+			slog.Warn("run export failed; retrying at the next sync", "sink", e.sink.Name(), "exported", n, "error", err)
+		} else if n > 0 {
+			slog.Debug("exported runs", "sink", e.sink.Name(), "count", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync exports the runs that finished since the previous sync and returns
+// how many were written. On error, runs written before it stay exported and
+// the rest are retried by the next sync.
+func (e *Exporter) Sync(ctx context.Context) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	total := 0
+	n, err := e.syncPending(ctx)
+	total += n
+	if err != nil {
+		return total, e.fail(err)
+	}
+	for _, kind := range kinds {
+		n, err := e.syncKind(ctx, kind)
+		total += n
+		if err != nil {
+			return total, e.fail(fmt.Errorf("%s: %w", kind, err))
+		}
+	}
+	e.lastSync = e.now()
+	return total, nil
+}
+
+func (e *Exporter) fail(err error) error {
+	e.failures++
+	return err
+}
+
+// syncKind pages through the runs of kind created since its cursor. The
+// cursor is saved after each page has been written.
+func (e *Exporter) syncKind(ctx context.Context, kind string) (int, error) {
+	total := 0
+	for {
+		res, err := e.source.RunsSince(ctx, tektonresults.SinceOptions{
+			Kind:      kind,
+			Namespace: e.opts.Namespace,
+			Cursor:    e.state.Cursors[kind],
+			Limit:     e.opts.BatchSize,
+		})
+		if err != nil {
+			return total, err
+		}
+		var rows []Row
+		pending := map[string]bool{}
+		for _, run := range res.Runs {
+			if run.CompletionTime == nil {
+				pending[run.RecordName] = true
+				continue
+			}
+			rows = append(rows, newRow(runKind(kind), run, e.now()))
+		}
+		if err := e.write(ctx, rows); err != nil {
+			return total, err
+		}
+		total += len(rows)
+		for name := range pending {
+			if _, ok := e.state.Pending[name]; !ok {
+				e.state.Pending[name] = e.now()
+			}
+		}
+		e.state.Cursors[kind] = res.Cursor
+		if err := e.save(); err != nil {
+			return total, err
+		}
+		if !res.More {
+			return total, nil
+		}
+	}
+}
+
+// syncPending exports the runs seen unfinished that have finished since,
+// and gives up on those pending for longer than maxPending. Each batch
+// leaves Pending, and the state is saved, as soon as it is written, so a
+// failing batch does not export the earlier ones again.
+func (e *Exporter) syncPending(ctx context.Context) (int, error) {
+	var rows []Row
+	var names []string // record of each row
+	expired := false
+	for name, seen := range e.state.Pending {
+		detail, err := e.source.GetRunByRecord(ctx, name)
+		switch {
+		case err == nil && detail.Completed():
+			rows = append(rows, newRow(detailKind(detail), detail.Summary, e.now()))
+			names = append(names, name)
+		case e.now().Sub(seen) > maxPending:
+			slog.Warn("run did not finish in time to be exported; giving up on it", "record", name, "since", seen, "error", err)
+			delete(e.state.Pending, name)
+			expired = true
+		}
+	}
+	if expired {
+		if err := e.save(); err != nil {
+			return 0, err
+		}
+	}
+	for i := 0; i < len(rows); i += e.opts.BatchSize {
+		end := min(i+e.opts.BatchSize, len(rows))
+		if err := e.write(ctx, rows[i:end]); err != nil {
+			return i, err
+		}
+		for _, name := range names[i:end] {
+			delete(e.state.Pending, name)
+		}
+		if err := e.save(); err != nil {
+			return end, err
+		}
+	}
+	return len(rows), nil
+}
+
+func (e *Exporter) write(ctx context.Context, rows []Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := e.sink.Write(ctx, rows); err != nil {
+		return fmt.Errorf("write to %s: %w", e.sink.Name(), err)
+	}
+	e.exported += int64(len(rows))
+	return nil
+}
+
+func runKind(kind string) string {
+	if kind == "taskrun" {
+		return "TaskRun"
+	}
+	return "PipelineRun"
+}
+
+// detailKind reads the kind of a run looked up by record.
+func detailKind(detail *tektonresults.RunDetail) string {
+	var meta struct {
+		Kind string `json:"kind"`
+	}
+	if json.Unmarshal(detail.Raw, &meta) == nil && meta.Kind != "" {
+		return meta.Kind
+	}
+	return runKind("")
+}
+
+func (e *Exporter) load() error {
+	e.state = state{Cursors: map[string]string{}, Pending: map[string]time.Time{}}
+	if e.opts.StateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(e.opts.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &e.state)
+	}
+	if err != nil {
+		return fmt.Errorf("read export state %s: %w", e.opts.StateFile, err)
+	}
+	if e.state.Cursors == nil {
+		e.state.Cursors = map[string]string{}
+	}
+	if e.state.Pending == nil {
+		e.state.Pending = map[string]time.Time{}
+	}
+	return nil
+}
+
+// save writes the state file atomically, so a crash leaves either the old
+// or the new cursors.
+func (e *Exporter) save() error {
+	if e.opts.StateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(e.state)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(e.opts.StateFile), ".export-state-*")
+	if err != nil {
+		return fmt.Errorf("save export state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save export state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save export state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), e.opts.StateFile); err != nil {
+		return fmt.Errorf("save export state: %w", err)
+	}
+	return nil
+}
+
+// Stats summarizes the exporter's progress.
+type Stats struct {
+	Exported int64     // rows written
+	Failures int64     // syncs that stopped on an error
+	Pending  int       // unfinished runs waiting to be exported
+	LastSync time.Time // last sync that wrote everything it found
+}
+
+func (e *Exporter) Stats() Stats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return Stats{Exported: e.exported, Failures: e.failures, Pending: len(e.state.Pending), LastSync: e.lastSync}
+}
+
+// WritePrometheus writes the exporter metrics in the Prometheus text format.
+func (e *Exporter) WritePrometheus(w io.Writer) {
+	stats := e.Stats()
+	sink := e.sink.Name()
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_export_rows_total Runs written to the export sink.\n# TYPE tekton_results_mcp_export_rows_total counter\ntekton_results_mcp_export_rows_total{sink=%q} %d\n", sink, stats.Exported)
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_export_failures_total Export syncs that stopped on an error.\n# TYPE tekton_results_mcp_export_failures_total counter\ntekton_results_mcp_export_failures_total{sink=%q} %d\n", sink, stats.Failures)
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_export_pending Unfinished runs waiting to be exported.\n# TYPE tekton_results_mcp_export_pending gauge\ntekton_results_mcp_export_pending{sink=%q} %d\n", sink, stats.Pending)
+	var last int64
+	if !stats.LastSync.IsZero() {
+		last = stats.LastSync.Unix()
+	}
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_export_last_success_timestamp_seconds Time of the last complete export sync.\n# TYPE tekton_results_mcp_export_last_success_timestamp_seconds gauge\ntekton_results_mcp_export_last_success_timestamp_seconds{sink=%q} %d\n", sink, last)
+}
@@ -0,0 +1,246 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// fakeSource serves pages of runs per kind, each page answering one cursor.
+type fakeSource struct {
+	pages   map[string][]*tektonresults.SinceResult
+	calls   []tektonresults.SinceOptions
+	records map[string]*tektonresults.RunDetail
+}
+
+func (f *fakeSource) RunsSince(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error) {
+	f.calls = append(f.calls, opts)
+	i := 0
+	if opts.Cursor != "" {
+		fmt.Sscanf(opts.Cursor, opts.Kind+"-%d", &i)
+	}
+	pages := f.pages[opts.Kind]
+	if i >= len(pages) {
+		return &tektonresults.SinceResult{Cursor: opts.Cursor}, nil
+	}
+	return pages[i], nil
+}
+
+func (f *fakeSource) GetRunByRecord(ctx context.Context, recordName string) (*tektonresults.RunDetail, error) {
+	if d, ok := f.records[recordName]; ok {
+		return d, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeSink struct {
+	rows   []Row
+	err    error
+	writes int
+	failAt int // write that fails with err, counting from 1; 0 fails every write while err is set
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Write(ctx context.Context, rows []Row) error {
+	s.writes++
+	if s.err != nil && (s.failAt == 0 || s.failAt == s.writes) {
+		return s.err
+	}
+	s.rows = append(s.rows, rows...)
+	return nil
+}
+
+func run(name string, finished bool) tektonresults.RunSummary {
+	start := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	s := tektonresults.RunSummary{
+		Name:       name,
+		Namespace:  "ci",
+		UID:        "uid-" + name,
+		Labels:     map[string]string{"tekton.dev/pipeline": "build"},
+		StartTime:  &start,
+		Status:     "Unknown",
+		RecordName: "ci/results/r/records/" + name,
+	}
+	if finished {
+		end := metav1.NewTime(start.Add(90 * time.Second))
+		s.CompletionTime, s.Status, s.Reason = &end, "True", "Succeeded"
+	}
+	return s
+}
+
+func names(rows []Row) []string {
+	var out []string
+	for _, r := range rows {
+		out = append(out, r.Kind+"/"+r.Name)
+	}
+	return out
+}
+
+func TestExporter_Sync(t *testing.T) {
+	source := &fakeSource{pages: map[string][]*tektonresults.SinceResult{
+		"pipelinerun": {
+			{Runs: []tektonresults.RunSummary{run("a", true), run("b", false)}, Cursor: "pipelinerun-1", More: true},
+			{Runs: []tektonresults.RunSummary{run("c", true)}, Cursor: "pipelinerun-2"},
+		},
+		"taskrun": {
+			{Runs: []tektonresults.RunSummary{run("t", true)}, Cursor: "taskrun-1"},
+		},
+	}}
+	sink := &fakeSink{}
+	state := filepath.Join(t.TempDir(), "state.json")
+	e, err := New(source, sink, Options{Interval: time.Minute, StateFile: state})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	n, err := e.Sync(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("Sync() = %d, %v; want 3 runs", n, err)
+	}
+	if got := names(sink.rows); !slices.Equal(got, []string{"PipelineRun/a", "PipelineRun/c", "TaskRun/t"}) {
+		t.Errorf("Unexpected exported runs %v", got)
+	}
+	if row := sink.rows[0]; row.Pipeline != "build" || row.DurationSeconds != 90 || row.Status != "True" || row.StartTime == nil {
+		t.Errorf("Unexpected row %+v", row)
+	}
+	if source.calls[0].Namespace != "-" {
+		t.Errorf("Expected all namespaces by default, got %q", source.calls[0].Namespace)
+	}
+
+	// The unfinished run is exported once it finishes, and a restarted
+	// exporter continues after the saved cursors.
+	detail := &tektonresults.RunDetail{Summary: run("b", true), Raw: json.RawMessage(`{"kind":"PipelineRun"}`)}
+	source.records = map[string]*tektonresults.RunDetail{"ci/results/r/records/b": detail}
+	source.calls = nil
+	e, err = New(source, sink, Options{Interval: time.Minute, StateFile: state})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if stats := e.Stats(); stats.Pending != 1 {
+		t.Errorf("Expected the pending run to be restored, got %+v", stats)
+	}
+	if n, err := e.Sync(context.Background()); err != nil || n != 1 {
+		t.Fatalf("second Sync() = %d, %v; want the finished run", n, err)
+	}
+	if got := names(sink.rows); got[len(got)-1] != "PipelineRun/b" {
+		t.Errorf("Expected the finished run last, got %v", got)
+	}
+	if source.calls[0].Cursor != "pipelinerun-2" || source.calls[1].Cursor != "taskrun-1" {
+		t.Errorf("Expected the saved cursors, got %+v", source.calls)
+	}
+	if stats := e.Stats(); stats.Pending != 0 || stats.Exported != 1 || stats.LastSync.IsZero() {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestExporter_SyncRetriesFailedWrites(t *testing.T) {
+	source := &fakeSource{pages: map[string][]*tektonresults.SinceResult{
+		"pipelinerun": {{Runs: []tektonresults.RunSummary{run("a", true)}, Cursor: "pipelinerun-1"}},
+	}}
+	sink := &fakeSink{err: errors.New("warehouse unavailable")}
+	e, err := New(source, sink, Options{Interval: time.Minute})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := e.Sync(context.Background()); err == nil || !strings.Contains(err.Error(), "warehouse unavailable") {
+		t.Fatalf("Expected the sink error, got %v", err)
+	}
+
+	sink.err = nil
+	if n, err := e.Sync(context.Background()); err != nil || n != 1 {
+		t.Errorf("Expected the run to be exported on retry, got %d, %v", n, err)
+	}
+	if stats := e.Stats(); stats.Failures != 1 || stats.Exported != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestExporter_GivesUpOnStalePendingRuns(t *testing.T) {
+	source := &fakeSource{pages: map[string][]*tektonresults.SinceResult{
+		"pipelinerun": {{Runs: []tektonresults.RunSummary{run("stuck", false)}, Cursor: "pipelinerun-1"}},
+	}}
+	e, err := New(source, &fakeSink{}, Options{Interval: time.Minute})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	e.Sync(context.Background())
+	if e.Stats().Pending != 1 {
+		t.Fatalf("Expected the unfinished run to be pending, got %+v", e.Stats())
+	}
+	now = now.Add(maxPending + time.Minute)
+	e.Sync(context.Background())
+	if e.Stats().Pending != 0 {
+		t.Errorf("Expected the run to be dropped after %s, got %+v", maxPending, e.Stats())
+	}
+}
+
+func TestExporter_WritePrometheus(t *testing.T) {
+	e, err := New(&fakeSource{}, &fakeSink{}, Options{Interval: time.Minute})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var b strings.Builder
+	e.WritePrometheus(&b)
+	for _, want := range []string{
+		`tekton_results_mcp_export_rows_total{sink="fake"} 0`,
+		`tekton_results_mcp_export_pending{sink="fake"} 0`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestExporter_SyncPendingKeepsWrittenBatches(t *testing.T) {
+	source := &fakeSource{pages: map[string][]*tektonresults.SinceResult{
+		"pipelinerun": {{Runs: []tektonresults.RunSummary{run("a", false), run("b", false)}, Cursor: "pipelinerun-1"}},
+	}}
+	state := filepath.Join(t.TempDir(), "state.json")
+	e, err := New(source, &fakeSink{}, Options{Interval: time.Minute, StateFile: state, BatchSize: 1})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := e.Sync(context.Background()); err != nil || e.Stats().Pending != 2 {
+		t.Fatalf("Expected two pending runs, got %+v, %v", e.Stats(), err)
+	}
+
+	// Both runs finish, and the second batch fails to be written.
+	source.records = map[string]*tektonresults.RunDetail{}
+	for _, name := range []string{"a", "b"} {
+		source.records["ci/results/r/records/"+name] = &tektonresults.RunDetail{Summary: run(name, true)}
+	}
+	sink := &fakeSink{err: errors.New("warehouse unavailable"), failAt: 2}
+	e, err = New(source, sink, Options{Interval: time.Minute, StateFile: state, BatchSize: 1})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if n, err := e.Sync(context.Background()); err == nil || n != 1 {
+		t.Fatalf("Sync() = %d, %v; want one run written before the error", n, err)
+	}
+
+	// A restart retries only the run that was not written.
+	sink.err = nil
+	e, err = New(source, sink, Options{Interval: time.Minute, StateFile: state, BatchSize: 1})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if stats := e.Stats(); stats.Pending != 1 {
+		t.Fatalf("Expected the written run to have left the saved state, got %+v", stats)
+	}
+	if n, err := e.Sync(context.Background()); err != nil || n != 1 || len(sink.rows) != 2 || sink.rows[0].Name == sink.rows[1].Name {
+		t.Errorf("Expected each run exported once, got %d, %v, %v", n, err, names(sink.rows))
+	}
+}
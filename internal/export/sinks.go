@@ -0,0 +1,213 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	sinkTimeout = 30 * time.Second
+	// maxErrorBody bounds how much of a failed response is quoted in errors.
+	maxErrorBody = 512
+
+	bigQueryAPI = "https://bigquery.googleapis.com/bigquery/v2"
+	// metadataTokenURL serves access tokens of the service account attached
+	// to a Google Cloud VM or GKE workload.
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// Sink receives batches of exported runs.
+type Sink interface {
+	Name() string // kind of sink, for logs and metrics
+	Write(ctx context.Context, rows []Row) error
+}
+
+// ParseSink returns the sink described by spec:
+//
+//   - https://host/path posts each batch as JSON to a webhook, authenticated
+//     with token as a bearer token when it is set.
+//   - bigquery://project/dataset/table streams rows into an existing
+//     BigQuery table, authenticated with token as an OAuth access token, or
+//     else with the credentials of the Google Cloud workload.
+func ParseSink(spec, token string) (Sink, error) {
+	u, err := url.Parse(strings.TrimSpace(spec))
+	if err != nil {
+		return nil, fmt.Errorf("invalid export sink %q: %w", spec, err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid export sink %q: missing host", spec)
+		}
+		return &webhookSink{url: u.String(), token: token, client: &http.Client{Timeout: sinkTimeout}}, nil
+	case "bigquery":
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if u.Host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid export sink %q: expected bigquery://<project>/<dataset>/<table>", spec)
+		}
+		sink := &bigQuerySink{
+			insertURL: fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s/insertAll", bigQueryAPI,
+				url.PathEscape(u.Host), url.PathEscape(parts[0]), url.PathEscape(parts[1])),
+		}
+		var tokens oauth2.TokenSource = metadataTokenSource{client: &http.Client{Timeout: sinkTimeout}}
+		if token != "" {
+			tokens = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		}
+		sink.client = &http.Client{Timeout: sinkTimeout, Transport: &oauth2.Transport{Source: oauth2.ReuseTokenSource(nil, tokens)}}
+		return sink, nil
+	case "postgres", "postgresql":
+		return nil, fmt.Errorf("invalid export sink %q: Postgres is not supported directly; post batches to a webhook that writes them to Postgres", spec)
+	default:
+		return nil, fmt.Errorf("invalid export sink %q: expected an https:// webhook or bigquery://<project>/<dataset>/<table>", spec)
+	}
+}
+
+// webhookSink posts batches as {"runs": [...]}.
+type webhookSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *webhookSink) Name() string { return "webhook" }
+
+func (s *webhookSink) Write(ctx context.Context, rows []Row) error {
+	body, err := json.Marshal(struct {
+		Runs []Row `json:"runs"`
+	}{rows})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	_, err = send(s.client, req)
+	return err
+}
+
+// bigQuerySink streams rows with tabledata.insertAll. The run UID is the
+// insert ID, so BigQuery drops rows that a retried batch sends again.
+type bigQuerySink struct {
+	insertURL string
+	client    *http.Client
+}
+
+func (s *bigQuerySink) Name() string { return "bigquery" }
+
+func (s *bigQuerySink) Write(ctx context.Context, rows []Row) error {
+	type insertRow struct {
+		InsertID string `json:"insertId"`
+		JSON     Row    `json:"json"`
+	}
+	payload := struct {
+		Rows []insertRow `json:"rows"`
+	}{}
+	for _, row := range rows {
+		payload.Rows = append(payload.Rows, insertRow{InsertID: row.UID, JSON: row})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.insertURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	data, err := send(s.client, req)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		InsertErrors []struct {
+			Index  int `json:"index"`
+			Errors []struct {
+				Reason  string `json:"reason"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"insertErrors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("decode insertAll response: %w", err)
+	}
+	if len(resp.InsertErrors) > 0 {
+		first := resp.InsertErrors[0]
+		msg := "unknown error"
+		if len(first.Errors) > 0 {
+			msg = first.Errors[0].Reason + ": " + first.Errors[0].Message
+		}
+		return fmt.Errorf("BigQuery rejected %d of %d rows, e.g. row %d: %s", len(resp.InsertErrors), len(rows), first.Index, msg)
+	}
+	return nil
+}
+
+// send performs req and returns the response body, or an error quoting it
+// when the status is not 2xx.
+func send(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		if len(data) > maxErrorBody {
+			data = data[:maxErrorBody]
+		}
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// metadataTokenSource fetches access tokens from the Google Cloud metadata
+// server.
+type metadataTokenSource struct {
+	client *http.Client
+	url    string // metadataTokenURL when empty
+}
+
+func (m metadataTokenSource) Token() (*oauth2.Token, error) {
+	tokenURL := m.url
+	if tokenURL == "" {
+		tokenURL = metadataTokenURL
+	}
+	req, err := http.NewRequest(http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	data, err := send(m.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("get a Google Cloud access token from the metadata server (set the export token outside Google Cloud): %w", err)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.Unmarshal(data, &tok); err != nil || tok.AccessToken == "" {
+		return nil, fmt.Errorf("invalid token from the metadata server")
+	}
+	return &oauth2.Token{
+		AccessToken: tok.AccessToken,
+		TokenType:   tok.TokenType,
+		Expiry:      time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseSink(t *testing.T) {
+	tests := []struct {
+		spec, name, wantErr string
+	}{
+		{"https://hooks.example.com/runs", "webhook", ""},
+		{"bigquery://my-project/ci/runs", "bigquery", ""},
+		{"bigquery://my-project/ci", "", "expected bigquery://<project>/<dataset>/<table>"},
+		{"postgres://db/runs", "", "Postgres is not supported directly"},
+		{"s3://bucket", "", "expected an https:// webhook"},
+		{"https:///runs", "", "missing host"},
+	}
+	for _, tt := range tests {
+		sink, err := ParseSink(tt.spec, "")
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSink(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || sink.Name() != tt.name {
+			t.Errorf("ParseSink(%q) = %v, %v; want a %s sink", tt.spec, sink, err, tt.name)
+		}
+	}
+	sink, _ := ParseSink("bigquery://my-project/ci/runs", "")
+	if got := sink.(*bigQuerySink).insertURL; got != bigQueryAPI+"/projects/my-project/datasets/ci/tables/runs/insertAll" {
+		t.Errorf("Unexpected insertAll URL %s", got)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got struct {
+		Runs []Row `json:"runs"`
+	}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	sink, err := ParseSink(srv.URL, "s3cret")
+	if err != nil {
+		t.Fatalf("ParseSink() error = %v", err)
+	}
+	if err := sink.Write(context.Background(), []Row{{Name: "a", UID: "uid-a"}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if auth != "Bearer s3cret" || len(got.Runs) != 1 || got.Runs[0].UID != "uid-a" {
+		t.Errorf("Unexpected request: auth %q, body %+v", auth, got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+	sink, _ = ParseSink(failing.URL, "")
+	if err := sink.Write(context.Background(), []Row{{Name: "a"}}); err == nil || !strings.Contains(err.Error(), "429 Too Many Requests: quota exceeded") {
+		t.Errorf("Expected the webhook error, got %v", err)
+	}
+}
+
+func TestBigQuerySink(t *testing.T) {
+	var body string
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body, auth = string(data), r.Header.Get("Authorization")
+		if strings.Contains(body, "uid-bad") {
+			io.WriteString(w, `{"insertErrors":[{"index":1,"errors":[{"reason":"invalid","message":"no such field: color"}]}]}`)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	sink, err := ParseSink("bigquery://p/d/t", "ya29.token")
+	if err != nil {
+		t.Fatalf("ParseSink() error = %v", err)
+	}
+	sink.(*bigQuerySink).insertURL = srv.URL
+	if err := sink.Write(context.Background(), []Row{{Name: "a", UID: "uid-a"}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if auth != "Bearer ya29.token" || !strings.Contains(body, `"insertId":"uid-a"`) {
+		t.Errorf("Unexpected request: auth %q, body %s", auth, body)
+	}
+
+	err = sink.Write(context.Background(), []Row{{UID: "uid-a"}, {UID: "uid-bad"}})
+	if err == nil || !strings.Contains(err.Error(), "rejected 1 of 2 rows, e.g. row 1: invalid: no such field: color") {
+		t.Errorf("Expected the insert errors, got %v", err)
+	}
+}
+
+func TestMetadataTokenSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing header", http.StatusForbidden)
+			return
+		}
+		io.WriteString(w, `{"access_token":"ya29.meta","expires_in":3599,"token_type":"Bearer"}`)
+	}))
+	defer srv.Close()
+
+	tok, err := metadataTokenSource{client: srv.Client(), url: srv.URL}.Token()
+	if err != nil || tok.AccessToken != "ya29.meta" || !tok.Valid() {
+		t.Errorf("Token() = %+v, %v", tok, err)
+	}
+}
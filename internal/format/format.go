@@ -0,0 +1,93 @@
+// Package format renders durations, sizes and timestamps for human oriented
+// tool output, so every tool presents them the same way.
+package format
+
+import (
+	"fmt"
+	"time"
+)
+
+// Placeholder is printed in place of values that are unknown, such as the
+// duration of a run that has not completed yet.
+const Placeholder = "-"
+
+var durationUnits = []struct {
+	size   time.Duration
+	suffix string
+}{
+	{24 * time.Hour, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+}
+
+// Duration renders d using its two most significant units, e.g. "1h 4m",
+// "4m 30s" or "12s". Durations under a second are shown in milliseconds and
+// negative durations are treated as zero.
+func Duration(d time.Duration) string {
+	if d <= 0 {
+		return "0s"
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+
+	d = d.Round(time.Second)
+	for i, unit := range durationUnits {
+		if d < unit.size {
+			continue
+		}
+		out := fmt.Sprintf("%d%s", d/unit.size, unit.suffix)
+		if i+1 < len(durationUnits) {
+			next := durationUnits[i+1]
+			if minor := (d % unit.size) / next.size; minor > 0 {
+				out += fmt.Sprintf(" %d%s", minor, next.suffix)
+			}
+		}
+		return out
+	}
+	return "0s"
+}
+
+// Elapsed renders the duration between start and end, or Placeholder when
+// either bound is unset. Kubernetes stamps most times with whole-second
+// precision, so a span under a second between two such times renders as
+// "<1s" rather than a misleadingly exact "0s".
+func Elapsed(start, end time.Time) string {
+	if start.IsZero() || end.IsZero() {
+		return Placeholder
+	}
+	d := end.Sub(start)
+	if d < time.Second && start.Nanosecond() == 0 && end.Nanosecond() == 0 {
+		return "<1s"
+	}
+	return Duration(d)
+}
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB"}
+
+// Bytes renders n with binary (IEC) units and one decimal, e.g. "512 B",
+// "3.2 MiB".
+func Bytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", max(n, 0))
+	}
+	value := float64(n)
+	unit := ""
+	for _, u := range byteUnits {
+		value /= 1024
+		unit = u
+		if value < 1024 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
+
+// Timestamp renders t in UTC as RFC 3339, or Placeholder for the zero time.
+func Timestamp(t time.Time) string {
+	if t.IsZero() {
+		return Placeholder
+	}
+	return t.UTC().Format(time.RFC3339)
+}
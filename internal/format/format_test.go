@@ -0,0 +1,76 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{-time.Second, "0s"},
+		{0, "0s"},
+		{250 * time.Millisecond, "250ms"},
+		{12 * time.Second, "12s"},
+		{12*time.Second + 600*time.Millisecond, "13s"},
+		{4*time.Minute + 30*time.Second, "4m 30s"},
+		{5 * time.Minute, "5m"},
+		{time.Hour + 4*time.Minute + 59*time.Second, "1h 4m"},
+		{time.Hour + 30*time.Second, "1h"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.in); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestElapsed(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	if got := Elapsed(start, start.Add(90*time.Second)); got != "1m 30s" {
+		t.Errorf("Elapsed() = %q, want 1m 30s", got)
+	}
+	if got := Elapsed(start, time.Time{}); got != Placeholder {
+		t.Errorf("Elapsed() with open end = %q, want %q", got, Placeholder)
+	}
+	// Whole-second timestamps cannot resolve spans under a second.
+	if got := Elapsed(start, start); got != "<1s" {
+		t.Errorf("Elapsed() between equal whole-second times = %q, want <1s", got)
+	}
+	if got := Elapsed(start.Add(100*time.Millisecond), start.Add(400*time.Millisecond)); got != "300ms" {
+		t.Errorf("Elapsed() with sub-second precision = %q, want 300ms", got)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{-1, "0 B"},
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1.0 KiB"},
+		{3355443, "3.2 MiB"},
+		{5 * 1024 * 1024 * 1024, "5.0 GiB"},
+		{3 << 50, "3072.0 TiB"},
+	}
+	for _, tt := range tests {
+		if got := Bytes(tt.in); got != tt.want {
+			t.Errorf("Bytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	if got := Timestamp(ts); got != "2024-01-01T11:00:00Z" {
+		t.Errorf("Timestamp() = %q, want UTC RFC 3339", got)
+	}
+	if got := Timestamp(time.Time{}); got != Placeholder {
+		t.Errorf("Timestamp(zero) = %q, want %q", got, Placeholder)
+	}
+}
@@ -0,0 +1,69 @@
+package format
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// sparkBars are the block characters used by Sparkline, lowest first.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders one bar per duration, scaled between the shortest and
+// longest, e.g. "▁▃▂█▅". When all durations are equal every bar is drawn at
+// mid height.
+func Sparkline(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return ""
+	}
+	lo, hi := slices.Min(durations), slices.Max(durations)
+	var b strings.Builder
+	for _, d := range durations {
+		i := len(sparkBars) / 2
+		if hi > lo {
+			i = int(float64(d-lo) / float64(hi-lo) * float64(len(sparkBars)-1))
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
+
+// Histogram renders durations in at most buckets equal-width ranges between
+// the shortest and longest, one line per range with a bar of up to width
+// cells and the count, e.g. "1m 0s - 2m 30s  ██████  6".
+func Histogram(durations []time.Duration, buckets, width int) string {
+	if len(durations) == 0 || buckets < 1 || width < 1 {
+		return ""
+	}
+	lo, hi := slices.Min(durations), slices.Max(durations)
+	if hi == lo {
+		buckets = 1
+	}
+	buckets = min(buckets, len(durations))
+	step := (hi - lo) / time.Duration(buckets)
+
+	counts := make([]int, buckets)
+	for _, d := range durations {
+		i := buckets - 1
+		if step > 0 {
+			i = min(int((d-lo)/step), buckets-1)
+		}
+		counts[i]++
+	}
+	peak := slices.Max(counts)
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for i, n := range counts {
+		upper := lo + step*time.Duration(i+1)
+		if i == buckets-1 {
+			upper = hi
+		}
+		bar := strings.Repeat("█", (n*width+peak-1)/peak)
+		fmt.Fprintf(w, "%s - %s\t%s\t%d\n", Duration(lo+step*time.Duration(i)), Duration(upper), bar, n)
+	}
+	_ = w.Flush()
+	return b.String()
+}
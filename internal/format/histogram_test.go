@@ -0,0 +1,44 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		in   []time.Duration
+		want string
+	}{
+		{nil, ""},
+		{[]time.Duration{time.Minute}, "▅"},
+		{[]time.Duration{time.Minute, time.Minute}, "▅▅"},
+		{[]time.Duration{time.Minute, 8 * time.Minute, 4*time.Minute + 30*time.Second}, "▁█▄"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.in); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	durations := []time.Duration{time.Minute, 90 * time.Second, 2 * time.Minute, 5 * time.Minute, 80 * time.Second}
+	got := Histogram(durations, 2, 4)
+	want := "1m - 3m  ████  4\n3m - 5m  █     1\n"
+	if got != want {
+		t.Errorf("Histogram() =\n%q\nwant\n%q", got, want)
+	}
+
+	lines := strings.Split(strings.TrimSpace(Histogram(durations, 10, 4)), "\n")
+	if len(lines) != len(durations) {
+		t.Errorf("Expected buckets capped at the number of durations, got %d lines", len(lines))
+	}
+	if got := Histogram([]time.Duration{time.Minute, time.Minute}, 5, 4); got != "1m - 1m  ████  2\n" {
+		t.Errorf("Histogram() of equal durations = %q", got)
+	}
+	if got := Histogram(nil, 5, 4); got != "" {
+		t.Errorf("Histogram(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package logging configures the server's slog output and routes klog, which
+// client-go logs through, into the same handler so every log line shares one
+// format, destination and redaction policy.
+package logging
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// Redacted replaces secret values in log output.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute key fragments whose values are never logged.
+var sensitiveKeys = []string{"token", "password", "secret", "authorization", "credential"}
+
+// bearerPattern matches bearer credentials embedded in free text, such as
+// request dumps logged by client-go at high verbosity.
+var bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+
+// ParseLevel parses a level name: debug, info, warn or error.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q; use debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// NewHandler returns the text handler used for all server logs, writing
+// records at or above level to w with secrets redacted. Pass a
+// *slog.LevelVar to change the level while the server runs.
+func NewHandler(w io.Writer, level slog.Leveler) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(key, fragment) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+	if a.Value.Kind() == slog.KindString {
+		if s := a.Value.String(); bearerPattern.MatchString(s) {
+			return slog.String(a.Key, RedactText(s))
+		}
+	}
+	return a
+}
+
+// RedactText masks bearer credentials in s.
+func RedactText(s string) string {
+	return bearerPattern.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// BridgeKlog routes klog output into logger. klog's V(0) messages are logged
+// at Info, V(1) and above at Debug, and messages above maxVerbosity are not
+// produced at all. klog errors keep the Error level.
+func BridgeKlog(logger *slog.Logger, maxVerbosity int) {
+	// klog checks its own -v flag before calling the logger, so it has to be
+	// set even though klog flags are not exposed on our command line.
+	fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(fs)
+	_ = fs.Set("v", strconv.Itoa(maxVerbosity))
+
+	klog.SetSlogLogger(slog.New(&klogHandler{next: logger.Handler()}))
+}
+
+// klogHandler maps the levels logr derives from klog verbosity (V(n) becomes
+// slog.Level(-n)) onto the standard slog levels and tags records with their
+// origin.
+type klogHandler struct {
+	next slog.Handler
+}
+
+func (h *klogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, klogLevel(level))
+}
+
+func (h *klogHandler) Handle(ctx context.Context, record slog.Record) error {
+	mapped := slog.NewRecord(record.Time, klogLevel(record.Level), RedactText(record.Message), record.PC)
+	mapped.AddAttrs(slog.String("logger", "klog"))
+	record.Attrs(func(a slog.Attr) bool {
+		mapped.AddAttrs(a)
+		return true
+	})
+	return h.next.Handle(ctx, mapped)
+}
+
+func (h *klogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &klogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *klogHandler) WithGroup(name string) slog.Handler {
+	return &klogHandler{next: h.next.WithGroup(name)}
+}
+
+func klogLevel(level slog.Level) slog.Level {
+	if level < slog.LevelInfo {
+		return slog.LevelDebug
+	}
+	return level
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"k8s.io/klog/v2"
+)
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, " warn ": slog.LevelWarn, "error": slog.LevelError} {
+		got, err := ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestNewHandler_Redacts(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, slog.LevelInfo))
+
+	logger.Info("configured", "bearerToken", "abc123", "Authorization", "Bearer xyz", "header", "Bearer eyJhbGciOi.payload.sig", "namespace", "ci")
+	logger.Debug("hidden")
+
+	out := buf.String()
+	for _, secret := range []string{"abc123", "xyz", "eyJhbGciOi"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Secret %q leaked: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "namespace=ci") || !strings.Contains(out, "header=\"Bearer [REDACTED]\"") {
+		t.Errorf("Unexpected output: %s", out)
+	}
+	if strings.Contains(out, "hidden") {
+		t.Error("Debug record should be filtered at Info level")
+	}
+}
+
+func TestBridgeKlog(t *testing.T) {
+	var buf bytes.Buffer
+	t.Cleanup(klog.ClearLogger)
+	BridgeKlog(slog.New(NewHandler(&buf, slog.LevelDebug)), 2)
+
+	klog.Info("plain info")
+	klog.V(2).Info("verbose detail")
+	klog.V(3).Info("too verbose")
+	klog.ErrorS(errors.New("boom"), "request failed", "token", "s3cr3t")
+	klog.Info("GET /apis with Authorization: Bearer abc.def.ghi")
+
+	out := buf.String()
+	for _, want := range []string{
+		`level=INFO msg="plain info" logger=klog`,
+		`level=DEBUG msg="verbose detail" logger=klog`,
+		`level=ERROR msg="request failed" logger=klog`,
+		"token=[REDACTED]",
+		"Bearer [REDACTED]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in output:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"too verbose", "s3cr3t", "abc.def.ghi"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("Unexpected %q in output:\n%s", unwanted, out)
+		}
+	}
+}
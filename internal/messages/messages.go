@@ -0,0 +1,135 @@
+// Package messages holds the wording of the errors tools return to clients.
+// Each message is a text/template; the configuration file can replace any of
+// them, e.g. to point users at internal runbooks, without changing handlers.
+package messages
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"text/template"
+)
+
+// ID names a message of the catalog. IDs are the keys of the messages map of
+// the configuration file.
+type ID string
+
+// Messages of the catalog, with the fields their templates receive.
+const (
+	// RunNotFound is returned when no run matches the filters of a tool
+	// acting on one run. Fields: Kind.
+	RunNotFound ID = "runNotFound"
+	// MissingSelector is returned when a tool acting on one run was called
+	// without anything identifying the run. Fields: Kind.
+	MissingSelector ID = "missingSelector"
+	// LogsNotCompleted is returned for logs of a run still going. Fields:
+	// Kind.
+	LogsNotCompleted ID = "logsNotCompleted"
+	// LogsDisabled is returned when the Results server stores no logs.
+	LogsDisabled ID = "logsDisabled"
+	// Deferred is returned instead of running an analytics tool while the
+	// Results API is throttling or failing requests. Fields: Tool, Reason,
+	// RetryAfter.
+	Deferred ID = "deferred"
+	// ErrorFooter is appended, on a line of its own, to every error a tool
+	// returns. Empty by default. Fields: Tool.
+	ErrorFooter ID = "errorFooter"
+)
+
+var defaults = map[ID]string{
+	RunNotFound:      "no run found that matches the provided filters",
+	MissingSelector:  "provide at least one of name, prefix, nameRegex, uid, labelSelector or annotationSelector to identify a {{.Kind}}",
+	LogsNotCompleted: "logs are only available after the {{.Kind}} has completed",
+	LogsDisabled:     "log storage is not enabled on this Results server (LOGS_API is not set to true in its configuration), so logs of runs cannot be fetched; the run's status and step states are still available",
+	Deferred:         "{{.Tool}} was deferred because {{.Reason}}. It reads many runs, so it is held back while the Results API is under pressure to keep lookups of single runs and their logs responsive. Retry in {{.RetryAfter}}, or look up the runs you need one at a time.",
+	ErrorFooter:      "",
+}
+
+// IDs returns the IDs of every message, sorted.
+func IDs() []ID {
+	return slices.Sorted(maps.Keys(defaults))
+}
+
+// Default returns the built-in wording of id.
+func Default(id ID) string {
+	return defaults[id]
+}
+
+// Catalog renders messages, with the overrides it was last set to. The zero
+// value and a nil Catalog render the built-in wording. It is safe for
+// concurrent use.
+type Catalog struct {
+	templates atomic.Pointer[map[ID]*template.Template]
+}
+
+// New returns a catalog with overrides applied.
+func New(overrides map[string]string) (*Catalog, error) {
+	c := &Catalog{}
+	if err := c.Set(overrides); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Set replaces the overrides of the catalog. Unknown IDs and templates that
+// do not parse are rejected, leaving the catalog unchanged.
+func (c *Catalog) Set(overrides map[string]string) error {
+	templates, err := parse(overrides)
+	if err != nil {
+		return err
+	}
+	c.templates.Store(&templates)
+	return nil
+}
+
+// Validate checks overrides the way Set would, without a catalog.
+func Validate(overrides map[string]string) error {
+	_, err := parse(overrides)
+	return err
+}
+
+func parse(overrides map[string]string) (map[ID]*template.Template, error) {
+	templates := make(map[ID]*template.Template, len(overrides))
+	for key, text := range overrides {
+		id := ID(key)
+		if _, ok := defaults[id]; !ok {
+			return nil, fmt.Errorf("unknown message %q; known messages are %s", key, joinIDs(IDs()))
+		}
+		t, err := template.New(key).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("message %s: %w", key, err)
+		}
+		templates[id] = t
+	}
+	return templates, nil
+}
+
+// Format renders id with data, which holds the fields the message
+// documents. An override that fails to render falls back to the built-in
+// wording.
+func (c *Catalog) Format(id ID, data map[string]string) string {
+	if c != nil {
+		if templates := c.templates.Load(); templates != nil {
+			if t, ok := (*templates)[id]; ok {
+				var b strings.Builder
+				if err := t.Execute(&b, data); err == nil {
+					return b.String()
+				}
+			}
+		}
+	}
+	var b strings.Builder
+	t := template.Must(template.New(string(id)).Option("missingkey=zero").Parse(defaults[id]))
+	_ = t.Execute(&b, data)
+	return b.String()
+}
+
+func joinIDs(ids []ID) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = string(id)
+	}
+	return strings.Join(names, ", ")
+}
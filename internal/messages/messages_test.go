@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatalog_Format(t *testing.T) {
+	var defaults *Catalog
+	if got := defaults.Format(LogsNotCompleted, map[string]string{"Kind": "TaskRun"}); got != "logs are only available after the TaskRun has completed" {
+		t.Errorf("Format() on a nil catalog = %q", got)
+	}
+
+	c, err := New(map[string]string{
+		string(RunNotFound): "No {{.Kind}} matches; see https://runbooks.example.com/tekton#not-found",
+		string(ErrorFooter): "{{.Missing}}{{.Tool}} failed",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := c.Format(RunNotFound, map[string]string{"Kind": "PipelineRun"}); got != "No PipelineRun matches; see https://runbooks.example.com/tekton#not-found" {
+		t.Errorf("Format(RunNotFound) = %q", got)
+	}
+	if got := c.Format(ErrorFooter, map[string]string{"Tool": "taskrun_logs"}); got != "taskrun_logs failed" {
+		t.Errorf("Format(ErrorFooter) = %q, want missing fields left empty", got)
+	}
+	if got := c.Format(LogsDisabled, nil); got != Default(LogsDisabled) {
+		t.Errorf("Format(LogsDisabled) = %q, want the built-in wording", got)
+	}
+
+	if err := c.Set(map[string]string{"unknown": "x"}); err == nil || !strings.Contains(err.Error(), "known messages are deferred, errorFooter, logsDisabled") {
+		t.Errorf("Set() error = %v, want the known messages listed", err)
+	}
+	if got := c.Format(RunNotFound, map[string]string{"Kind": "TaskRun"}); !strings.HasPrefix(got, "No TaskRun") {
+		t.Errorf("Expected a rejected Set to keep the overrides, got %q", got)
+	}
+}
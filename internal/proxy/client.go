@@ -0,0 +1,269 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/oauth2"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/version"
+)
+
+const (
+	// maxMessageSize bounds one JSON-RPC message read from an upstream.
+	maxMessageSize = 16 << 20
+	// maxErrorBody bounds how much of a failed response is quoted in errors.
+	maxErrorBody = 512
+)
+
+// client speaks the MCP streamable HTTP transport to one upstream server. It
+// keeps a single session, initialized on first use and again when the
+// upstream forgets it.
+type client struct {
+	url    string
+	tokens oauth2.TokenSource // nil when the upstream needs no credential
+	http   *http.Client
+	nextID atomic.Int64
+
+	mu        sync.Mutex
+	sessionID string
+	ready     bool
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// errSessionExpired is returned when the upstream no longer knows the
+// session, which it signals with HTTP 404.
+var errSessionExpired = errors.New("MCP session expired")
+
+// remoteTool is a tool definition as listed by an upstream. The input schema
+// is kept verbatim, so no detail of it is lost in translation.
+type remoteTool struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	InputSchema json.RawMessage    `json:"inputSchema"`
+	Annotations mcp.ToolAnnotation `json:"annotations"`
+}
+
+// listTools returns every tool the upstream serves, following pagination.
+func (c *client) listTools(ctx context.Context) ([]remoteTool, error) {
+	var tools []remoteTool
+	cursor := ""
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		raw, err := c.call(ctx, string(mcp.MethodToolsList), params)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Tools      []remoteTool `json:"tools"`
+			NextCursor string       `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("decode tools/list result: %w", err)
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// callTool calls the upstream tool name with args and returns its result.
+// Tool failures are part of the result; errors are transport or protocol
+// failures.
+func (c *client) callTool(ctx context.Context, name string, args any) (*mcp.CallToolResult, error) {
+	raw, err := c.call(ctx, string(mcp.MethodToolsCall), map[string]any{"name": name, "arguments": args})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.ParseCallToolResult(&raw)
+}
+
+// call sends a request in the current session, initializing one first when
+// needed and once more when the upstream has expired it.
+func (c *client) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	for attempt := 0; ; attempt++ {
+		sessionID, err := c.session(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, err := c.send(ctx, sessionID, method, params)
+		if errors.Is(err, errSessionExpired) && attempt == 0 {
+			c.reset(sessionID)
+			continue
+		}
+		return result, err
+	}
+}
+
+// session returns the ID of an initialized session, which is empty when the
+// upstream does not use sessions.
+func (c *client) session(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ready {
+		return c.sessionID, nil
+	}
+
+	params := map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": clientName, "version": version.String()},
+	}
+	resp, err := c.post(ctx, "", rpcRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: c.nextID.Add(1), Method: string(mcp.MethodInitialize), Params: params})
+	if err != nil {
+		return "", fmt.Errorf("initialize: %w", err)
+	}
+	sessionID := resp.Header.Get(server.HeaderKeySessionID)
+	if _, err := readResult(resp); err != nil {
+		return "", fmt.Errorf("initialize: %w", err)
+	}
+
+	resp, err = c.post(ctx, sessionID, rpcRequest{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/initialized"})
+	if err != nil {
+		return "", fmt.Errorf("initialized notification: %w", err)
+	}
+	_ = resp.Body.Close()
+	c.sessionID, c.ready = sessionID, true
+	return sessionID, nil
+}
+
+// reset forgets the session sessionID, unless another call replaced it
+// already.
+func (c *client) reset(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sessionID == sessionID {
+		c.sessionID, c.ready = "", false
+	}
+}
+
+func (c *client) send(ctx context.Context, sessionID, method string, params any) (json.RawMessage, error) {
+	resp, err := c.post(ctx, sessionID, rpcRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: c.nextID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	result, err := readResult(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return result, nil
+}
+
+// post sends one JSON-RPC message. The response body is left to the caller
+// unless an error is returned.
+func (c *client) post(ctx context.Context, sessionID string, msg rpcRequest) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(server.HeaderKeySessionID, sessionID)
+	}
+	if c.tokens != nil {
+		tok, err := c.tokens.Token()
+		if err != nil {
+			return nil, fmt.Errorf("read upstream token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && sessionID != "" {
+		return nil, errSessionExpired
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+}
+
+// readResult reads the response to a request from a JSON body or, when the
+// upstream streams, from the first event carrying a response. Requests and
+// notifications the upstream interleaves are skipped.
+func readResult(resp *http.Response) (json.RawMessage, error) {
+	defer resp.Body.Close()
+	body := io.LimitReader(resp.Body, maxMessageSize)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		var msg rpcResponse
+		if err := json.NewDecoder(body).Decode(&msg); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		return msg.result()
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxMessageSize)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(value, " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+		var msg rpcResponse
+		err := json.Unmarshal([]byte(data.String()), &msg)
+		data.Reset()
+		if err != nil || len(msg.ID) == 0 || (msg.Result == nil && msg.Error == nil) {
+			continue
+		}
+		return msg.result()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read event stream: %w", err)
+	}
+	return nil, fmt.Errorf("event stream ended without a response")
+}
+
+func (m rpcResponse) result() (json.RawMessage, error) {
+	if m.Error != nil {
+		return nil, fmt.Errorf("upstream error %d: %s", m.Error.Code, m.Error.Message)
+	}
+	return m.Result, nil
+}
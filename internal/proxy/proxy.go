@@ -0,0 +1,241 @@
+// Package proxy mounts the tools of other MCP servers, such as a Kubernetes
+// MCP server, in this one, so a single endpoint serves archived Results data
+// and live cluster tools. Each upstream is reached over the streamable HTTP
+// transport and its tools are registered under its name as a prefix.
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/transport"
+)
+
+const (
+	clientName = "tekton-results-mcp-server"
+
+	// callTimeout bounds one request to an upstream, including tool calls
+	// that stream logs.
+	callTimeout = 2 * time.Minute
+	// SyncInterval is how often Run lists the tools of the upstreams again,
+	// so tools of an upstream that was down appear once it is reachable.
+	SyncInterval = 5 * time.Minute
+)
+
+// Upstream is an MCP server whose tools are mounted. It is configured in
+// the configuration file.
+type Upstream struct {
+	// Name prefixes the mounted tools: with name "k8s", the upstream tool
+	// pods_list is served as k8s_pods_list.
+	Name string `json:"name"`
+	// URL is the streamable HTTP endpoint, e.g. http://localhost:8081/mcp.
+	URL string `json:"url"`
+	// Token is sent as a bearer token. Set at most one of Token and
+	// TokenFile.
+	Token string `json:"token,omitempty"`
+	// TokenFile is read periodically, so rotated tokens are picked up.
+	TokenFile string `json:"tokenFile,omitempty"`
+	// Tools limits the mounted tools to these upstream names; empty mounts
+	// every tool.
+	Tools []string `json:"tools,omitempty"`
+}
+
+var upstreamName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// Validate checks the upstream definitions without contacting them.
+func Validate(upstreams []Upstream) error {
+	seen := map[string]bool{}
+	for i, u := range upstreams {
+		if !upstreamName.MatchString(u.Name) {
+			return fmt.Errorf("upstream %d: invalid name %q; use up to 32 lowercase letters, digits and dashes, starting with a letter", i+1, u.Name)
+		}
+		if seen[u.Name] {
+			return fmt.Errorf("upstream %d: name %q is already used", i+1, u.Name)
+		}
+		seen[u.Name] = true
+		parsed, err := url.Parse(u.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("upstream %s: url must be an http(s) URL, got %q", u.Name, u.URL)
+		}
+		if u.Token != "" && u.TokenFile != "" {
+			return fmt.Errorf("upstream %s: set either token or tokenFile, not both", u.Name)
+		}
+		if slices.Contains(u.Tools, "") {
+			return fmt.Errorf("upstream %s: tool names must not be empty", u.Name)
+		}
+	}
+	return nil
+}
+
+// mount is a configured upstream and its client.
+type mount struct {
+	Upstream
+	client *client
+}
+
+// Proxy keeps the tools of the configured upstreams registered with an MCP
+// server.
+type Proxy struct {
+	server      *server.MCPServer
+	allowWrites bool
+	wrap        func([]server.ServerTool) []server.ServerTool
+
+	mu         sync.Mutex
+	mounts     []*mount
+	generation int             // incremented by Configure, so syncs of replaced upstreams are dropped
+	mounted    map[string]bool // names of the tools the proxy registered
+}
+
+// New returns a proxy registering tools with s. Upstream tools that are not
+// annotated read-only are only mounted when allowWrites is set, like the
+// write tools of this server. wrap, when set, wraps the mounted tools in the
+// handlers of the tools s already serves.
+func New(s *server.MCPServer, allowWrites bool, wrap func([]server.ServerTool) []server.ServerTool) *Proxy {
+	return &Proxy{server: s, allowWrites: allowWrites, wrap: wrap, mounted: map[string]bool{}}
+}
+
+// Configure replaces the upstreams and mounts their tools. Upstreams that
+// cannot be reached are logged and retried by Run.
+func (p *Proxy) Configure(ctx context.Context, upstreams []Upstream) error {
+	if err := Validate(upstreams); err != nil {
+		return err
+	}
+	mounts := make([]*mount, 0, len(upstreams))
+	for _, u := range upstreams {
+		var tokens oauth2.TokenSource
+		switch {
+		case u.Token != "":
+			tokens = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: u.Token})
+		case u.TokenFile != "":
+			tokens = transport.NewCachedFileTokenSource(u.TokenFile)
+		}
+		mounts = append(mounts, &mount{Upstream: u, client: &client{
+			url:    u.URL,
+			tokens: tokens,
+			http:   &http.Client{Timeout: callTimeout},
+		}})
+	}
+
+	p.mu.Lock()
+	p.mounts = mounts
+	p.generation++
+	p.mu.Unlock()
+	p.Sync(ctx)
+	return nil
+}
+
+// Run syncs the mounted tools every interval until ctx is done.
+func (p *Proxy) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Sync(ctx)
+		}
+	}
+}
+
+// Sync lists the tools of every upstream and registers them, removing tools
+// the upstreams no longer serve. The tools of an upstream that fails to list
+// them are removed until it recovers, so calls fail fast instead of timing
+// out. The upstreams are listed without holding the lock, so a slow upstream
+// does not hold up Configure; a sync that Configure overtook is dropped.
+func (p *Proxy) Sync(ctx context.Context) {
+	p.mu.Lock()
+	mounts, generation := p.mounts, p.generation
+	p.mu.Unlock()
+
+	var tools []server.ServerTool
+	for _, m := range mounts {
+		remote, err := m.client.listTools(ctx)
+		if err != nil {
+			slog.Warn("Failed to list the tools of an MCP upstream", "upstream", m.Name, "url", m.URL, "error", err)
+			continue
+		}
+		tools = append(tools, p.serverTools(m, remote)...)
+	}
+	if p.wrap != nil && len(tools) > 0 {
+		tools = p.wrap(tools)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.generation != generation {
+		return
+	}
+	existing := p.server.ListTools()
+	next := map[string]bool{}
+	var add []server.ServerTool
+	for _, tool := range tools {
+		name := tool.Tool.Name
+		if _, taken := existing[name]; taken && !p.mounted[name] {
+			slog.Warn("Skipping an upstream tool whose name is already served", "tool", name)
+			continue
+		}
+		next[name] = true
+		add = append(add, tool)
+	}
+	var remove []string
+	for name := range p.mounted {
+		if !next[name] {
+			remove = append(remove, name)
+		}
+	}
+	if len(remove) > 0 {
+		p.server.DeleteTools(remove...)
+	}
+	if len(add) > 0 {
+		p.server.AddTools(add...)
+	}
+	p.mounted = next
+}
+
+// serverTools translates the tools of m into prefixed tools forwarding calls
+// to it.
+func (p *Proxy) serverTools(m *mount, remote []remoteTool) []server.ServerTool {
+	var tools []server.ServerTool
+	for _, rt := range remote {
+		if len(m.Tools) > 0 && !slices.Contains(m.Tools, rt.Name) {
+			continue
+		}
+		readOnly := rt.Annotations.ReadOnlyHint != nil && *rt.Annotations.ReadOnlyHint
+		if !readOnly && !p.allowWrites {
+			slog.Debug("Not mounting an upstream tool that is not read-only", "upstream", m.Name, "tool", rt.Name)
+			continue
+		}
+		tool := mcp.Tool{
+			Name:           m.Name + "_" + rt.Name,
+			Description:    fmt.Sprintf("[%s] %s", m.Name, rt.Description),
+			RawInputSchema: rt.InputSchema,
+			Annotations:    rt.Annotations,
+		}
+		if len(tool.RawInputSchema) == 0 {
+			tool.RawInputSchema = []byte(`{"type":"object"}`)
+		}
+		name, c := rt.Name, m.client
+		tools = append(tools, server.ServerTool{
+			Tool: tool,
+			Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				result, err := c.callTool(ctx, name, req.Params.Arguments)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("call %s on upstream %s: %v", name, m.Name, err)), nil
+				}
+				return result, nil
+			},
+		})
+	}
+	return tools
+}
@@ -0,0 +1,307 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newUpstream serves a read-only pods_list tool and a pods_delete tool over
+// the streamable HTTP transport.
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := server.NewMCPServer("upstream", "1.0", server.WithToolCapabilities(true))
+	s.AddTool(mcp.NewTool("pods_list",
+		mcp.WithDescription("List pods"),
+		mcp.WithString("namespace"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pods in " + req.GetString("namespace", "")), nil
+	})
+	s.AddTool(mcp.NewTool("pods_delete",
+		mcp.WithDescription("Delete a pod"),
+		mcp.WithReadOnlyHintAnnotation(false),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("deleted"), nil
+	})
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(s))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func callTool(t *testing.T, s *server.MCPServer, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	tool := s.GetTool(name)
+	if tool == nil {
+		t.Fatalf("Tool %s is not registered", name)
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	return result
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func TestProxy_MountsReadOnlyTools(t *testing.T) {
+	upstream := newUpstream(t)
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	p := New(s, false, nil)
+	if err := p.Configure(context.Background(), []Upstream{{Name: "k8s", URL: upstream.URL}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	tools := s.ListTools()
+	if len(tools) != 1 || tools["k8s_pods_list"] == nil {
+		t.Fatalf("Expected only k8s_pods_list to be mounted, got %v", tools)
+	}
+	if desc := tools["k8s_pods_list"].Tool.Description; desc != "[k8s] List pods" {
+		t.Errorf("Unexpected description %q", desc)
+	}
+
+	result := callTool(t, s, "k8s_pods_list", map[string]any{"namespace": "ci"})
+	if result.IsError || resultText(result) != "pods in ci" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestProxy_WriteToolsAndAllowList(t *testing.T) {
+	upstream := newUpstream(t)
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	p := New(s, true, nil)
+	if err := p.Configure(context.Background(), []Upstream{{Name: "k8s", URL: upstream.URL}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if tools := s.ListTools(); len(tools) != 2 || tools["k8s_pods_delete"] == nil {
+		t.Fatalf("Expected write tools to be mounted, got %v", tools)
+	}
+
+	// Reconfiguring removes the tools the allow list excludes.
+	if err := p.Configure(context.Background(), []Upstream{{Name: "k8s", URL: upstream.URL, Tools: []string{"pods_delete"}}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if tools := s.ListTools(); len(tools) != 1 || tools["k8s_pods_delete"] == nil {
+		t.Fatalf("Expected only k8s_pods_delete to remain, got %v", tools)
+	}
+}
+
+func TestProxy_KeepsLocalTools(t *testing.T) {
+	upstream := newUpstream(t)
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	s.AddTool(mcp.NewTool("k8s_pods_list"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("local"), nil
+	})
+	p := New(s, false, nil)
+	if err := p.Configure(context.Background(), []Upstream{{Name: "k8s", URL: upstream.URL}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if text := resultText(callTool(t, s, "k8s_pods_list", nil)); text != "local" {
+		t.Errorf("Expected the local tool to win, got %q", text)
+	}
+
+	// Removing the upstream must not remove the local tool.
+	if err := p.Configure(context.Background(), nil); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if s.GetTool("k8s_pods_list") == nil {
+		t.Error("Expected the local tool to stay registered")
+	}
+}
+
+func TestProxy_UnreachableUpstream(t *testing.T) {
+	upstream := newUpstream(t)
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	p := New(s, false, nil)
+	if err := p.Configure(context.Background(), []Upstream{{Name: "k8s", URL: upstream.URL}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	upstream.Close()
+
+	result := callTool(t, s, "k8s_pods_list", nil)
+	if !result.IsError || !strings.Contains(resultText(result), "upstream k8s") {
+		t.Errorf("Expected a tool error naming the upstream, got %+v", result)
+	}
+	p.Sync(context.Background())
+	if len(s.ListTools()) != 0 {
+		t.Errorf("Expected the tools of an unreachable upstream to be removed, got %v", s.ListTools())
+	}
+}
+
+func TestProxy_WrapsMountedTools(t *testing.T) {
+	upstream := newUpstream(t)
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	var wrapped []string
+	p := New(s, false, func(tools []server.ServerTool) []server.ServerTool {
+		for i, st := range tools {
+			wrapped = append(wrapped, st.Tool.Name)
+			next := st.Handler
+			tools[i].Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				result, err := next(ctx, req)
+				if err == nil {
+					result.Content = append(result.Content, mcp.NewTextContent("wrapped"))
+				}
+				return result, err
+			}
+		}
+		return tools
+	})
+	if err := p.Configure(context.Background(), []Upstream{{Name: "k8s", URL: upstream.URL}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if strings.Join(wrapped, ",") != "k8s_pods_list" {
+		t.Errorf("Expected the mounted tool to be wrapped, got %v", wrapped)
+	}
+	if text := resultText(callTool(t, s, "k8s_pods_list", map[string]any{"namespace": "ci"})); text != "pods in ci\nwrapped" {
+		t.Errorf("Expected the wrapped handler to run, got %q", text)
+	}
+}
+
+func TestProxy_SlowUpstreamDoesNotBlockConfigure(t *testing.T) {
+	upstream := newUpstream(t)
+	release, requested := make(chan struct{}), make(chan struct{}, 1)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requested <- struct{}{}:
+		default:
+		}
+		<-release
+		req, _ := http.NewRequestWithContext(r.Context(), r.Method, upstream.URL, r.Body)
+		req.Header = r.Header.Clone()
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(true))
+	p := New(s, false, nil)
+	p.mounts = []*mount{{Upstream: Upstream{Name: "k8s", URL: slow.URL}, client: &client{url: slow.URL, http: http.DefaultClient}}}
+	synced := make(chan struct{})
+	go func() {
+		p.Sync(context.Background())
+		close(synced)
+	}()
+	<-requested
+
+	configured := make(chan error)
+	go func() { configured <- p.Configure(context.Background(), nil) }()
+	select {
+	case err := <-configured:
+		if err != nil {
+			t.Fatalf("Configure() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Configure not to wait for a slow upstream")
+	}
+
+	close(release)
+	<-synced
+	if tools := s.ListTools(); len(tools) != 0 {
+		t.Errorf("Expected the sync of the replaced upstream to be dropped, got %v", tools)
+	}
+}
+
+func TestClient_ReinitializesExpiredSession(t *testing.T) {
+	upstream := newUpstream(t)
+	var expired atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(server.HeaderKeySessionID) != "" && expired.CompareAndSwap(true, false) {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		req, _ := http.NewRequestWithContext(r.Context(), r.Method, upstream.URL, r.Body)
+		req.Header = r.Header.Clone()
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	defer ts.Close()
+
+	c := &client{url: ts.URL, http: http.DefaultClient}
+	if _, err := c.listTools(context.Background()); err != nil {
+		t.Fatalf("listTools() error = %v", err)
+	}
+	first := c.sessionID
+	expired.Store(true)
+	result, err := c.callTool(context.Background(), "pods_list", map[string]any{"namespace": "ci"})
+	if err != nil {
+		t.Fatalf("callTool() error = %v", err)
+	}
+	if resultText(result) != "pods in ci" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if c.sessionID == "" || c.sessionID == first {
+		t.Errorf("Expected a new session, got %q after %q", c.sessionID, first)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		upstreams []Upstream
+		want      string
+	}{
+		{"valid", []Upstream{{Name: "k8s", URL: "https://k8s-mcp.example.com/mcp", TokenFile: "/var/run/token"}}, ""},
+		{"name", []Upstream{{Name: "K8s", URL: "http://localhost/mcp"}}, "invalid name"},
+		{"duplicate", []Upstream{{Name: "k8s", URL: "http://a/mcp"}, {Name: "k8s", URL: "http://b/mcp"}}, "already used"},
+		{"url", []Upstream{{Name: "k8s", URL: "stdio://kubectl"}}, "http(s) URL"},
+		{"token", []Upstream{{Name: "k8s", URL: "http://a/mcp", Token: "x", TokenFile: "/y"}}, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.upstreams)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// Package sessions tracks the MCP sessions of the streamable HTTP transport,
+// expiring idle ones and bounding how many may be open at once.
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const idPrefix = "mcp-session-"
+
+// headerSessionID carries the session ID in streamable HTTP requests.
+const headerSessionID = "Mcp-Session-Id"
+
+// Manager issues and validates session IDs for server.WithSessionIdManager.
+// A session expires once no request has used it for the idle timeout, and
+// new sessions are refused while the maximum is open. Requests for expired,
+// terminated or unknown sessions are answered with 404, which tells clients
+// to initialize a new session. It is safe for concurrent use.
+type Manager struct {
+	idle time.Duration // zero keeps sessions until terminated
+	max  int           // zero allows any number of sessions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	lastSeen  map[string]time.Time // open sessions by ID
+	admitting int                  // initialize requests admitted but not yet answered
+	onEnd     []func(id string)
+
+	created, expired, terminated, rejected int64
+}
+
+// New returns a Manager. A zero idle timeout or maximum disables that limit.
+func New(idle time.Duration, max int) *Manager {
+	return &Manager{
+		idle:     idle,
+		max:      max,
+		now:      time.Now,
+		lastSeen: map[string]time.Time{},
+	}
+}
+
+// OnEnd registers a function called with the ID of every session that
+// expires or is terminated, to release state kept elsewhere.
+func (m *Manager) OnEnd(fn func(id string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEnd = append(m.onEnd, fn)
+}
+
+// Generate opens a session and returns its ID.
+func (m *Manager) Generate() string {
+	id := idPrefix + uuid.New().String()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastSeen[id] = m.now()
+	m.created++
+	return id
+}
+
+// Validate records activity on an open session. Well-formed IDs of sessions
+// that are not open, because they expired, were terminated or predate a
+// restart, are reported as terminated.
+func (m *Manager) Validate(sessionID string) (isTerminated bool, err error) {
+	if err := checkID(sessionID); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen, ok := m.lastSeen[sessionID]
+	if !ok {
+		return true, nil
+	}
+	if now := m.now(); m.idle <= 0 || now.Sub(seen) < m.idle {
+		m.lastSeen[sessionID] = now
+		return false, nil
+	}
+	m.endLocked(sessionID)
+	m.expired++
+	return true, nil
+}
+
+// Terminate closes a session at the client's request.
+func (m *Manager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	if err := checkID(sessionID); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.lastSeen[sessionID]; ok {
+		m.endLocked(sessionID)
+		m.terminated++
+	}
+	return false, nil
+}
+
+func checkID(sessionID string) error {
+	if !strings.HasPrefix(sessionID, idPrefix) {
+		return fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	if _, err := uuid.Parse(sessionID[len(idPrefix):]); err != nil {
+		return fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	return nil
+}
+
+// endLocked closes a session and notifies the OnEnd functions. The functions
+// run in their own goroutine so they may call back into the HTTP handler.
+func (m *Manager) endLocked(id string) {
+	delete(m.lastSeen, id)
+	for _, fn := range m.onEnd {
+		go fn(id)
+	}
+}
+
+// Sweep expires the sessions that have been idle for the timeout and
+// returns how many it expired.
+func (m *Manager) Sweep() int {
+	if m.idle <= 0 {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	n := 0
+	for id, seen := range m.lastSeen {
+		if now.Sub(seen) >= m.idle {
+			m.endLocked(id)
+			n++
+		}
+	}
+	m.expired += int64(n)
+	return n
+}
+
+// Run sweeps idle sessions until ctx is done, so sessions that clients
+// abandon without terminating them do not pile up.
+func (m *Manager) Run(ctx context.Context) {
+	if m.idle <= 0 {
+		return
+	}
+	interval := max(m.idle/4, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := m.Sweep(); n > 0 {
+				slog.Debug("expired idle MCP sessions", "count", n, "idleTimeout", m.idle)
+			}
+		}
+	}
+}
+
+// Middleware refuses requests that would open a session while the maximum
+// is open, with 503 and a Retry-After header. Requests that carry a session
+// ID pass through.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	if m.max <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get(headerSessionID) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !m.admit() {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, fmt.Sprintf("too many open MCP sessions (maximum %d); retry later", m.max), http.StatusServiceUnavailable)
+			return
+		}
+		defer m.release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// admit reserves room for a session the request may open. Requests still in
+// flight count against the maximum, so concurrent initializations cannot
+// overshoot it.
+func (m *Manager) admit() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.lastSeen)+m.admitting >= m.max {
+		m.rejected++
+		return false
+	}
+	m.admitting++
+	return true
+}
+
+func (m *Manager) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.admitting--
+}
+
+// Stats counts open sessions and how sessions ended since start.
+type Stats struct {
+	Active     int   `json:"active"`
+	Created    int64 `json:"created"`
+	Expired    int64 `json:"expired"`
+	Terminated int64 `json:"terminated"`
+	Rejected   int64 `json:"rejected"` // initialize requests refused at the maximum
+}
+
+// Stats returns the current session counts.
+func (m *Manager) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return Stats{
+		Active:     len(m.lastSeen),
+		Created:    m.created,
+		Expired:    m.expired,
+		Terminated: m.terminated,
+		Rejected:   m.rejected,
+	}
+}
+
+// WritePrometheus writes the session counts in the Prometheus text
+// exposition format.
+func (m *Manager) WritePrometheus(w io.Writer) {
+	stats := m.Stats()
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_sessions_active MCP sessions currently open.\n# TYPE tekton_results_mcp_sessions_active gauge\ntekton_results_mcp_sessions_active %d\n", stats.Active)
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_sessions_max Maximum number of open MCP sessions, 0 when unbounded.\n# TYPE tekton_results_mcp_sessions_max gauge\ntekton_results_mcp_sessions_max %d\n", m.max)
+	fmt.Fprintf(w, "# HELP tekton_results_mcp_sessions_total MCP sessions by how they ended; created counts every session opened.\n# TYPE tekton_results_mcp_sessions_total counter\n")
+	for _, s := range []struct {
+		state string
+		n     int64
+	}{
+		{"created", stats.Created},
+		{"expired", stats.Expired},
+		{"rejected", stats.Rejected},
+		{"terminated", stats.Terminated},
+	} {
+		fmt.Fprintf(w, "tekton_results_mcp_sessions_total{state=%q} %d\n", s.state, s.n)
+	}
+}
@@ -0,0 +1,154 @@
+package sessions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestManager(idle time.Duration, max int) (*Manager, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+	m := New(idle, max)
+	m.now = clock.now
+	return m, clock
+}
+
+func TestManager_ExpiresIdleSessions(t *testing.T) {
+	m, clock := newTestManager(10*time.Minute, 0)
+	var mu sync.Mutex
+	var ended []string
+	done := make(chan struct{}, 2)
+	m.OnEnd(func(id string) {
+		mu.Lock()
+		ended = append(ended, id)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	active, idle := m.Generate(), m.Generate()
+	clock.advance(6 * time.Minute)
+	if terminated, err := m.Validate(active); terminated || err != nil {
+		t.Fatalf("Validate() = %v, %v for an active session", terminated, err)
+	}
+	clock.advance(6 * time.Minute)
+
+	if n := m.Sweep(); n != 1 {
+		t.Errorf("Sweep() expired %d sessions, want 1", n)
+	}
+	if terminated, _ := m.Validate(idle); !terminated {
+		t.Error("Expected the idle session to be reported as terminated")
+	}
+	if terminated, _ := m.Validate(active); terminated {
+		t.Error("Expected the session used 6 minutes ago to stay open")
+	}
+
+	// Expiry is also noticed on use, between sweeps.
+	clock.advance(10 * time.Minute)
+	if terminated, _ := m.Validate(active); !terminated {
+		t.Error("Expected the session to expire on use after the idle timeout")
+	}
+
+	<-done
+	<-done
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ended) != 2 {
+		t.Errorf("Expected OnEnd for both sessions, got %v", ended)
+	}
+	if stats := m.Stats(); stats != (Stats{Active: 0, Created: 2, Expired: 2}) {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestManager_Validate(t *testing.T) {
+	m, _ := newTestManager(0, 0)
+	if _, err := m.Validate("not-a-session"); err == nil {
+		t.Error("Expected an error for a malformed session ID")
+	}
+	// An ID issued before a restart makes the client initialize again.
+	if terminated, err := m.Validate("mcp-session-7a1f5f8e-3c1d-4c55-9f1c-6b7b1a3e2d10"); !terminated || err != nil {
+		t.Errorf("Validate() = %v, %v for an unknown session, want terminated", terminated, err)
+	}
+
+	id := m.Generate()
+	if _, err := m.Terminate(id); err != nil {
+		t.Fatalf("Terminate() error = %v", err)
+	}
+	if terminated, _ := m.Validate(id); !terminated {
+		t.Error("Expected a terminated session to stay terminated")
+	}
+	if stats := m.Stats(); stats.Terminated != 1 || stats.Active != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestManager_MiddlewareBoundsSessions(t *testing.T) {
+	m, _ := newTestManager(0, 1)
+	srv := httptest.NewServer(m.Middleware(server.NewStreamableHTTPServer(
+		server.NewMCPServer("test", "0.0.0"), server.WithSessionIdManager(m))))
+	defer srv.Close()
+
+	initialize := func() *http.Response {
+		t.Helper()
+		body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`
+		resp, err := http.Post(srv.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("initialize: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	first := initialize()
+	id := first.Header.Get(headerSessionID)
+	if first.StatusCode != http.StatusOK || id == "" {
+		t.Fatalf("Expected a session, got status %d and ID %q", first.StatusCode, id)
+	}
+	if second := initialize(); second.StatusCode != http.StatusServiceUnavailable || second.Header.Get("Retry-After") == "" {
+		t.Errorf("Expected 503 with Retry-After beyond the maximum, got %d", second.StatusCode)
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodDelete, srv.URL, nil)
+	req.Header.Set(headerSessionID, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("delete session: %v", err)
+	}
+	resp.Body.Close()
+
+	if third := initialize(); third.StatusCode != http.StatusOK {
+		t.Errorf("Expected a session once the first one ended, got %d", third.StatusCode)
+	}
+	if stats := m.Stats(); stats.Rejected != 1 || stats.Active != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestManager_WritePrometheus(t *testing.T) {
+	m, _ := newTestManager(time.Minute, 5)
+	m.Generate()
+
+	var b strings.Builder
+	m.WritePrometheus(&b)
+	for _, want := range []string{
+		"tekton_results_mcp_sessions_active 1\n",
+		"tekton_results_mcp_sessions_max 5\n",
+		`tekton_results_mcp_sessions_total{state="created"} 1`,
+		`tekton_results_mcp_sessions_total{state="expired"} 0`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package signing signs tool results with HMAC-SHA256, so systems that
+// ingest CI evidence gathered through the server can verify it came from the
+// server unmodified.
+//
+// The signature covers a canonical form of the result that any language can
+// rebuild: the lines "tekton-results-mcp-signature-v1", the tool name, the
+// timestamp and "error" or "ok", each ending in a newline, followed by every
+// content item as its type, a space, the byte length of its payload, a
+// newline, the payload and a newline. The payload of text content is its
+// text, of a text resource its text, of a resource link its URI, and of
+// image, audio and blob content its base64 data as sent.
+//
+// Pages of a result too large to return at once are read as resources and
+// signed on their own: the outcome line reads "page", and the content items
+// are the link of the page, with its canonical URI, and its text.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MetaKey is the key of the signature in the _meta object of a result.
+const MetaKey = "io.github.enarha.tekton-results-mcp/signature"
+
+// Algorithm names the signature scheme in the signature.
+const Algorithm = "HMAC-SHA256"
+
+// MinKeyLength is the shortest key accepted, in bytes.
+const MinKeyLength = 32
+
+const version = "tekton-results-mcp-signature-v1"
+
+// Signature is the value of MetaKey.
+type Signature struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"keyId"` // first bytes of the SHA-256 of the key, to tell rotated keys apart
+	Tool      string `json:"tool"`
+	Timestamp string `json:"timestamp"` // RFC 3339 in UTC, when the result was signed
+	Value     string `json:"value"`     // base64 HMAC of the canonical form
+}
+
+// Signer signs results with one key.
+type Signer struct {
+	key   []byte
+	keyID string
+	now   func() time.Time
+}
+
+// New returns a Signer for key, which must be at least MinKeyLength bytes.
+func New(key []byte) (*Signer, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &Signer{key: key, keyID: hex.EncodeToString(sum[:4]), now: time.Now}, nil
+}
+
+// KeyID identifies the key in signatures without revealing it.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// ValidateKey checks that key is long enough to sign with.
+func ValidateKey(key []byte) error {
+	if len(key) < MinKeyLength {
+		return fmt.Errorf("signing key must be at least %d bytes, got %d", MinKeyLength, len(key))
+	}
+	return nil
+}
+
+// Sign adds the signature of result, returned by tool, to its _meta.
+func (s *Signer) Sign(tool string, result *mcp.CallToolResult) {
+	sig := Signature{
+		Algorithm: Algorithm,
+		KeyID:     s.keyID,
+		Tool:      tool,
+		Timestamp: s.now().UTC().Format(time.RFC3339),
+	}
+	sig.Value = base64.StdEncoding.EncodeToString(s.resultMAC(sig, result))
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = map[string]any{}
+	}
+	result.Meta.AdditionalFields[MetaKey] = sig
+}
+
+// Verify reports whether sig is a valid signature of result by this key.
+func (s *Signer) Verify(sig Signature, result *mcp.CallToolResult) bool {
+	want, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil || sig.Algorithm != Algorithm {
+		return false
+	}
+	return hmac.Equal(want, s.resultMAC(sig, result))
+}
+
+// SignPage returns the signature of the page at uri, with text, of a result
+// returned by tool.
+func (s *Signer) SignPage(tool, uri, text string) Signature {
+	sig := Signature{
+		Algorithm: Algorithm,
+		KeyID:     s.keyID,
+		Tool:      tool,
+		Timestamp: s.now().UTC().Format(time.RFC3339),
+	}
+	sig.Value = base64.StdEncoding.EncodeToString(s.mac(sig, "page", pageContent(uri, text)))
+	return sig
+}
+
+// VerifyPage reports whether sig is a valid signature of the page at uri by
+// this key.
+func (s *Signer) VerifyPage(sig Signature, uri, text string) bool {
+	want, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil || sig.Algorithm != Algorithm {
+		return false
+	}
+	return hmac.Equal(want, s.mac(sig, "page", pageContent(uri, text)))
+}
+
+func pageContent(uri, text string) []mcp.Content {
+	return []mcp.Content{mcp.NewResourceLink(uri, "", "", ""), mcp.NewTextContent(text)}
+}
+
+func (s *Signer) resultMAC(sig Signature, result *mcp.CallToolResult) []byte {
+	outcome := "ok"
+	if result.IsError {
+		outcome = "error"
+	}
+	return s.mac(sig, outcome, result.Content)
+}
+
+func (s *Signer) mac(sig Signature, outcome string, contents []mcp.Content) []byte {
+	h := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n", version, sig.Tool, sig.Timestamp, outcome)
+	for _, content := range contents {
+		writeContent(h, content)
+	}
+	return h.Sum(nil)
+}
+
+func writeContent(w io.Writer, content mcp.Content) {
+	kind, payload := "unknown", ""
+	switch c := content.(type) {
+	case mcp.TextContent:
+		kind, payload = "text", c.Text
+	case mcp.ImageContent:
+		kind, payload = "image", c.Data
+	case mcp.AudioContent:
+		kind, payload = "audio", c.Data
+	case mcp.ResourceLink:
+		kind, payload = "link", c.URI
+	case mcp.EmbeddedResource:
+		switch r := c.Resource.(type) {
+		case mcp.TextResourceContents:
+			kind, payload = "resource", r.Text
+		case mcp.BlobResourceContents:
+			kind, payload = "resource", r.Blob
+		}
+	}
+	fmt.Fprintf(w, "%s %d\n%s\n", kind, len(payload), payload)
+}
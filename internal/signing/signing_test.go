@@ -0,0 +1,82 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var testKey = []byte(strings.Repeat("k", MinKeyLength))
+
+func TestSign(t *testing.T) {
+	s, err := New(testKey)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	s.now = func() time.Time { return time.Date(2025, 3, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600)) }
+
+	result := mcp.NewToolResultText("[]")
+	result.Content = append(result.Content, mcp.NewTextContent("Note: more runs match."))
+	s.Sign("pipelinerun_list", result)
+
+	sig, ok := result.Meta.AdditionalFields[MetaKey].(Signature)
+	if !ok {
+		t.Fatalf("Expected a signature in _meta, got %+v", result.Meta)
+	}
+	if sig.Algorithm != Algorithm || sig.Tool != "pipelinerun_list" || sig.Timestamp != "2025-03-01T09:00:00Z" || sig.KeyID != s.KeyID() || len(sig.KeyID) != 8 {
+		t.Errorf("Unexpected signature %+v", sig)
+	}
+
+	// The documented canonical form, rebuilt independently.
+	mac := hmac.New(sha256.New, testKey)
+	mac.Write([]byte("tekton-results-mcp-signature-v1\npipelinerun_list\n2025-03-01T09:00:00Z\nok\ntext 2\n[]\ntext 22\nNote: more runs match.\n"))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); sig.Value != want {
+		t.Errorf("Expected signature %s, got %s", want, sig.Value)
+	}
+	if !s.Verify(sig, result) {
+		t.Error("Expected the signature to verify")
+	}
+
+	result.Content[0] = mcp.NewTextContent(`[{"name":"forged"}]`)
+	if s.Verify(sig, result) {
+		t.Error("Expected a modified result to fail verification")
+	}
+	result.Content[0] = mcp.NewTextContent("[]")
+	result.IsError = true
+	if s.Verify(sig, result) {
+		t.Error("Expected a result turned into an error to fail verification")
+	}
+}
+
+func TestSignPage(t *testing.T) {
+	s, err := New(testKey)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	s.now = func() time.Time { return time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC) }
+
+	uri := "tekton-results://outputs/abc?page=2"
+	sig := s.SignPage("taskrun_logs", uri, "line\n")
+	mac := hmac.New(sha256.New, testKey)
+	mac.Write([]byte("tekton-results-mcp-signature-v1\ntaskrun_logs\n2025-03-01T09:00:00Z\npage\nlink 35\n" + uri + "\ntext 5\nline\n\n"))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); sig.Value != want || sig.Tool != "taskrun_logs" {
+		t.Errorf("Expected signature %s, got %+v", want, sig)
+	}
+	if !s.VerifyPage(sig, uri, "line\n") {
+		t.Error("Expected the page signature to verify")
+	}
+	if s.VerifyPage(sig, "tekton-results://outputs/abc?page=3", "line\n") || s.VerifyPage(sig, uri, "forged\n") {
+		t.Error("Expected another page or text to fail verification")
+	}
+}
+
+func TestNew_ShortKey(t *testing.T) {
+	if _, err := New([]byte("short")); err == nil {
+		t.Error("Expected a short key to be rejected")
+	}
+}
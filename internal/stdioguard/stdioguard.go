@@ -0,0 +1,115 @@
+// Package stdioguard keeps stray writes to stdout from corrupting the
+// JSON-RPC stream of the stdio transport. Third party libraries sometimes
+// print to os.Stdout; with the guard installed those writes are dropped, or
+// cause a panic in strict mode so tests catch them.
+package stdioguard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// maxReported bounds how much of a dropped write is echoed to Report.
+const maxReported = 120
+
+// Writer absorbs writes that are not part of the protocol.
+type Writer struct {
+	// Strict makes every write panic. Enable it in tests to find the code
+	// that wrote to stdout. Writes through the pipe Install puts in place of
+	// os.Stdout reach the Writer on another goroutine, so they are recorded
+	// instead and restore panics with the first of them.
+	Strict bool
+	// Report, when set, receives a short note for each dropped write. It
+	// must not be stdout; stderr is the usual choice.
+	Report io.Writer
+
+	dropped atomic.Int64
+	first   atomic.Pointer[string] // first write recorded in strict mode
+}
+
+// Write drops p, or panics in strict mode. It never fails so writers that
+// treat errors as fatal keep running.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.Strict {
+		panic(strictMessage(truncate(p)))
+	}
+	return w.drop(p), nil
+}
+
+// drop drops p, recording it in strict mode rather than panicking on the
+// goroutine draining the pipe.
+func (w *Writer) drop(p []byte) int {
+	if w.Strict {
+		written := truncate(p)
+		w.first.CompareAndSwap(nil, &written)
+	}
+	w.dropped.Add(int64(len(p)))
+	if w.Report != nil {
+		fmt.Fprintf(w.Report, "stdioguard: dropped %d bytes written to stdout: %q\n", len(p), truncate(p))
+	}
+	return len(p)
+}
+
+func strictMessage(written string) string {
+	return fmt.Sprintf("stdioguard: unexpected write to stdout in stdio mode: %q", written)
+}
+
+// Dropped returns the number of bytes dropped so far.
+func (w *Writer) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+func truncate(p []byte) string {
+	if len(p) > maxReported {
+		return string(p[:maxReported]) + "..."
+	}
+	return string(p)
+}
+
+// Install points os.Stdout at a pipe drained into guard and returns the
+// original stdout, which only the protocol should write to from then on.
+// restore reinstates os.Stdout and waits for pending writes to drain. In
+// strict mode it then panics if anything was written.
+//
+// Only writes made through the os.Stdout variable are intercepted; code that
+// writes to file descriptor 1 directly, or captured os.Stdout before Install
+// ran, still reaches the real stdout. Call it before creating any clients.
+func Install(guard *Writer) (protocol *os.File, restore func(), err error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("create stdout pipe: %w", err)
+	}
+
+	protocol = os.Stdout
+	os.Stdout = w
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		_, _ = io.Copy(drain{guard}, r)
+	}()
+
+	var once sync.Once
+	restore = func() {
+		once.Do(func() {
+			os.Stdout = protocol
+			_ = w.Close()
+			<-drained
+			_ = r.Close()
+			if written := guard.first.Load(); written != nil {
+				panic(strictMessage(*written))
+			}
+		})
+	}
+	return protocol, restore, nil
+}
+
+// drain is the Writer as the goroutine draining the pipe sees it.
+type drain struct{ guard *Writer }
+
+func (d drain) Write(p []byte) (int, error) {
+	return d.guard.drop(p), nil
+}
@@ -0,0 +1,92 @@
+package stdioguard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriter_Drops(t *testing.T) {
+	var report bytes.Buffer
+	guard := &Writer{Report: &report}
+
+	n, err := guard.Write([]byte("hello\n"))
+	if err != nil || n != 6 {
+		t.Fatalf("Write() = %d, %v; want 6, nil", n, err)
+	}
+	if guard.Dropped() != 6 {
+		t.Errorf("Dropped() = %d, want 6", guard.Dropped())
+	}
+	if !strings.Contains(report.String(), `dropped 6 bytes written to stdout: "hello\n"`) {
+		t.Errorf("unexpected report: %q", report.String())
+	}
+
+	report.Reset()
+	_, _ = guard.Write(bytes.Repeat([]byte("x"), 500))
+	if !strings.Contains(report.String(), "...") || report.Len() > 300 {
+		t.Errorf("expected a truncated report, got %d bytes", report.Len())
+	}
+}
+
+func TestWriter_StrictPanics(t *testing.T) {
+	guard := &Writer{Strict: true}
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "unexpected write to stdout") {
+			t.Errorf("expected strict panic, got %v", r)
+		}
+	}()
+	_, _ = guard.Write([]byte("stray"))
+}
+
+func TestInstall(t *testing.T) {
+	original := os.Stdout
+	guard := &Writer{}
+
+	protocol, restore, err := Install(guard)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if protocol != original {
+		t.Error("expected the original stdout to be returned for the protocol")
+	}
+	if os.Stdout == original {
+		t.Fatal("expected os.Stdout to be replaced")
+	}
+
+	fmt.Println("stray output from a dependency")
+	restore()
+	restore() // idempotent
+
+	if os.Stdout != original {
+		t.Error("expected os.Stdout to be restored")
+	}
+	if want := int64(len("stray output from a dependency\n")); guard.Dropped() != want {
+		t.Errorf("Dropped() = %d, want %d", guard.Dropped(), want)
+	}
+}
+
+func TestInstall_StrictPanicsOnRestore(t *testing.T) {
+	original := os.Stdout
+	guard := &Writer{Strict: true}
+
+	_, restore, err := Install(guard)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	// The drain goroutine must not panic; the write is reported by restore.
+	fmt.Println("stray output from a dependency")
+	fmt.Println("and more")
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), `"stray output from a dependency\n`) {
+			t.Errorf("expected restore to panic with the first write, got %v", r)
+		}
+		if os.Stdout != original {
+			t.Error("expected os.Stdout to be restored")
+		}
+	}()
+	restore()
+}
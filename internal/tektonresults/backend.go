@@ -0,0 +1,145 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// installNamespaces are the namespaces Tekton Results is commonly installed
+// in, tried in order when none is given.
+var installNamespaces = []string{"tekton-pipelines", "openshift-pipelines", "tekton-results"}
+
+// ConfigMaps of a Tekton Results installation.
+const (
+	infoConfigMap      = "tekton-results-info"
+	apiConfigMap       = "tekton-results-api-config"
+	retentionConfigMap = "tekton-results-config-results-retention-policy"
+)
+
+// BackendInfo describes the Tekton Results installation behind the API, as
+// far as the credentials of the server can discover it. Parts that cannot be
+// read are left empty and explained in Notes.
+type BackendInfo struct {
+	Namespace        string      `json:"namespace,omitempty"` // where Tekton Results is installed
+	Version          string      `json:"version,omitempty"`
+	ServedVersions   []string    `json:"servedVersions,omitempty"` // API versions registered with the aggregated API
+	PreferredVersion string      `json:"preferredVersion,omitempty"`
+	Logs             *LogStorage `json:"logs,omitempty"`
+	Retention        *Retention  `json:"retention,omitempty"`
+	Notes            []string    `json:"notes,omitempty"`
+	APIVersion       string      `json:"apiVersion"` // the API version this server queries
+}
+
+// LogStorage is the log configuration of the Results API server.
+type LogStorage struct {
+	Enabled   bool   `json:"enabled"`             // LOGS_API; without it no logs can be fetched
+	Type      string `json:"type,omitempty"`      // File, S3, GCS or blob
+	Location  string `json:"location,omitempty"`  // path or bucket of the configured type
+	PluginURL string `json:"pluginURL,omitempty"` // external log provider the API forwards to, e.g. Loki
+}
+
+// Retention is the configuration of the Results retention policy agent.
+type Retention struct {
+	MaxRetention     string `json:"maxRetention,omitempty"`
+	DefaultRetention string `json:"defaultRetention,omitempty"`
+	RunAt            string `json:"runAt,omitempty"` // cron schedule of the pruning job
+}
+
+// BackendInfo inspects the Tekton Results installation: the API versions it
+// serves, its release, the log storage it is configured with and its
+// retention policy. namespace is where Tekton Results is installed; when
+// empty, the usual namespaces are tried.
+func (s *Service) BackendInfo(ctx context.Context, namespace string) BackendInfo {
+	info := BackendInfo{APIVersion: resultsGroup + "/" + resultsVersion}
+	if s.cluster == nil {
+		info.Notes = append(info.Notes, "No Kubernetes configuration is available, so the installation cannot be inspected.")
+		return info
+	}
+
+	var group struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+		PreferredVersion struct {
+			Version string `json:"version"`
+		} `json:"preferredVersion"`
+	}
+	switch found, err := s.cluster.get(ctx, "/apis/"+resultsGroup, &group); {
+	case err != nil:
+		info.Notes = append(info.Notes, fmt.Sprintf("API discovery failed: %v", err))
+	case !found:
+		info.Notes = append(info.Notes, "The Results API is not registered with the Kubernetes aggregated API; it is only reachable directly.")
+	default:
+		for _, v := range group.Versions {
+			info.ServedVersions = append(info.ServedVersions, v.Version)
+		}
+		info.PreferredVersion = group.PreferredVersion.Version
+	}
+
+	candidates := installNamespaces
+	if namespace != "" {
+		candidates = []string{namespace}
+	}
+	var apiConfig map[string]string
+	for _, ns := range candidates {
+		cm, err := s.cluster.configMap(ctx, ns, apiConfigMap)
+		if err != nil {
+			info.Notes = append(info.Notes, err.Error())
+			continue
+		}
+		if cm != nil {
+			info.Namespace, apiConfig = ns, cm
+			break
+		}
+	}
+	if info.Namespace == "" {
+		info.Notes = append(info.Notes, fmt.Sprintf("ConfigMap %s was not found in %s; pass the namespace Tekton Results is installed in.", apiConfigMap, strings.Join(candidates, ", ")))
+		return info
+	}
+	info.Logs = logStorage(apiConfig)
+
+	if cm, err := s.cluster.configMap(ctx, info.Namespace, infoConfigMap); err != nil {
+		info.Notes = append(info.Notes, err.Error())
+	} else if cm["version"] != "" {
+		info.Version = cm["version"]
+	} else {
+		info.Notes = append(info.Notes, fmt.Sprintf("The release is unknown: ConfigMap %s/%s has no version.", info.Namespace, infoConfigMap))
+	}
+
+	if cm, err := s.cluster.configMap(ctx, info.Namespace, retentionConfigMap); err != nil {
+		info.Notes = append(info.Notes, err.Error())
+	} else if cm == nil {
+		info.Notes = append(info.Notes, "No retention policy is configured; records are kept until deleted.")
+	} else {
+		info.Retention = &Retention{MaxRetention: cm["maxRetention"], DefaultRetention: cm["defaultRetention"], RunAt: cm["runAt"]}
+	}
+	return info
+}
+
+// logStorage reads the log settings from the API server configuration. The
+// configuration is an env file under the "config" key; only log settings are
+// read, so database credentials are never exposed.
+func logStorage(cm map[string]string) *LogStorage {
+	env := map[string]string{}
+	for _, line := range strings.Split(cm["config"], "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && !strings.HasPrefix(key, "#") {
+			env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	logs := &LogStorage{
+		Enabled:   strings.EqualFold(env["LOGS_API"], "true"),
+		Type:      env["LOGS_TYPE"],
+		PluginURL: env["LOGGING_PLUGIN_API_URL"],
+	}
+	switch strings.ToUpper(logs.Type) {
+	case "FILE":
+		logs.Location = env["LOGS_PATH"]
+	case "S3":
+		logs.Location = env["S3_BUCKET_NAME"]
+	case "GCS":
+		logs.Location = env["GCS_BUCKET_NAME"]
+	}
+	return logs
+}
@@ -0,0 +1,78 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestBackendInfo(t *testing.T) {
+	objects := map[string]string{
+		"/apis/results.tekton.dev": `{"versions":[{"version":"v1alpha2"},{"version":"v1alpha3"}],"preferredVersion":{"version":"v1alpha2"}}`,
+		"/api/v1/namespaces/openshift-pipelines/configmaps/tekton-results-api-config": fmt.Sprintf(`{"data":{"config":%q}}`,
+			"DB_USER=\nDB_PASSWORD=hunter2\nLOGS_API=true\nLOGS_TYPE=S3\n# LOGS_PATH=/logs\nS3_BUCKET_NAME=\"results-logs\"\n"),
+		"/api/v1/namespaces/openshift-pipelines/configmaps/tekton-results-info":                            `{"data":{"version":"v0.14.0"}}`,
+		"/api/v1/namespaces/openshift-pipelines/configmaps/tekton-results-config-results-retention-policy": `{"data":{"runAt":"5 5 * * 0","maxRetention":"30"}}`,
+	}
+	var forbidden bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if forbidden && strings.Contains(r.URL.Path, "/configmaps/") {
+			http.Error(w, `{"reason":"Forbidden"}`, http.StatusForbidden)
+			return
+		}
+		body, ok := objects[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	cluster, err := newClusterClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("newClusterClient() error = %v", err)
+	}
+	svc := &Service{cluster: cluster}
+	ctx := context.Background()
+
+	info := svc.BackendInfo(ctx, "")
+	if info.Namespace != "openshift-pipelines" || info.Version != "v0.14.0" || info.PreferredVersion != "v1alpha2" || len(info.ServedVersions) != 2 {
+		t.Errorf("Unexpected backend info: %+v", info)
+	}
+	if info.Logs == nil || !info.Logs.Enabled || info.Logs.Type != "S3" || info.Logs.Location != "results-logs" {
+		t.Errorf("Unexpected log storage: %+v", info.Logs)
+	}
+	if info.Retention == nil || info.Retention.MaxRetention != "30" || info.Retention.RunAt != "5 5 * * 0" {
+		t.Errorf("Unexpected retention: %+v", info.Retention)
+	}
+	if len(info.Notes) != 0 {
+		t.Errorf("Expected no notes, got %v", info.Notes)
+	}
+	if out := fmt.Sprintf("%+v", info); strings.Contains(out, "hunter2") {
+		t.Errorf("Backend info leaks the database password: %s", out)
+	}
+
+	info = svc.BackendInfo(ctx, "tekton-results")
+	if info.Namespace != "" || info.Logs != nil || len(info.Notes) != 1 || !strings.Contains(info.Notes[0], "was not found in tekton-results") {
+		t.Errorf("Expected a note about the missing installation, got %+v", info)
+	}
+
+	forbidden = true
+	info = svc.BackendInfo(ctx, "openshift-pipelines")
+	if len(info.ServedVersions) != 2 || len(info.Notes) != 2 || !strings.Contains(info.Notes[0], "forbidden") {
+		t.Errorf("Expected discovery results and notes about forbidden ConfigMaps, got %+v", info)
+	}
+}
+
+func TestBackendInfo_WithoutKubeconfig(t *testing.T) {
+	info := (&Service{}).BackendInfo(context.Background(), "")
+	if info.APIVersion != "results.tekton.dev/v1alpha2" || len(info.Notes) != 1 {
+		t.Errorf("Unexpected backend info: %+v", info)
+	}
+}
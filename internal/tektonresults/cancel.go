@@ -0,0 +1,130 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CancelMode is the spec.status a cancellation sets on a PipelineRun.
+type CancelMode string
+
+const (
+	// CancelNow stops all running TaskRuns without running finally tasks.
+	CancelNow CancelMode = "Cancelled"
+	// CancelRunFinally cancels running TaskRuns, then runs finally tasks.
+	CancelRunFinally CancelMode = "CancelledRunFinally"
+	// StopRunFinally lets running TaskRuns finish, schedules no new ones,
+	// then runs finally tasks.
+	StopRunFinally CancelMode = "StoppedRunFinally"
+)
+
+// CancelModes lists the supported modes, the default first.
+var CancelModes = []CancelMode{CancelNow, CancelRunFinally, StopRunFinally}
+
+// CancelResult describes a PipelineRun cancelled by CancelPipelineRun.
+type CancelResult struct {
+	Name         string     `json:"name"`
+	Namespace    string     `json:"namespace"`
+	UID          string     `json:"uid"`
+	Mode         CancelMode `json:"mode"`
+	FoundIn      string     `json:"foundIn"` // "results" or "cluster"
+	DashboardURL string     `json:"dashboardUrl,omitempty"`
+}
+
+// livePipelineRun is the part of a PipelineRun in the cluster a cancellation
+// checks.
+type livePipelineRun struct {
+	Metadata struct {
+		Name            string `json:"name"`
+		Namespace       string `json:"namespace"`
+		UID             string `json:"uid"`
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Spec struct {
+		Status string `json:"status"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// CancelPipelineRun cancels a running PipelineRun by setting its spec.status
+// to mode. The run is looked up in Tekton Results; a run too new to be
+// archived yet is looked up in the cluster by name. The live object must
+// have the archived run's UID, so a newer run reusing the name is never
+// cancelled by mistake, and the patch is conditional on the version read, so
+// it fails rather than act on a run that changed meanwhile.
+func (s *Service) CancelPipelineRun(ctx context.Context, selector RunSelector, mode CancelMode) (*CancelResult, error) {
+	if s.cluster == nil {
+		return nil, fmt.Errorf("cancelling requires access to the Kubernetes API")
+	}
+	if mode == "" {
+		mode = CancelNow
+	}
+	if !isCancelMode(mode) {
+		return nil, fmt.Errorf("unsupported cancel mode %q", mode)
+	}
+
+	namespace, name, uid, foundIn := selector.Namespace, selector.Name, "", "cluster"
+	detail, err := s.GetPipelineRun(ctx, selector)
+	switch {
+	case err == nil:
+		namespace, name, uid, foundIn = detail.Summary.Namespace, detail.Summary.Name, detail.Summary.UID, "results"
+	case name == "" || namespace == "" || namespace == "-" || strings.Contains(namespace, ","):
+		return nil, err
+	}
+
+	runPath := fmt.Sprintf("/apis/tekton.dev/v1/namespaces/%s/pipelineruns/%s", url.PathEscape(namespace), url.PathEscape(name))
+	var live livePipelineRun
+	found, getErr := s.cluster.get(ctx, runPath, &live)
+	switch {
+	case getErr != nil:
+		return nil, fmt.Errorf("read PipelineRun %s/%s: %w", namespace, name, getErr)
+	case !found && foundIn == "results":
+		return nil, fmt.Errorf("PipelineRun %s/%s is archived in Tekton Results but no longer exists in the cluster, so it is not running", namespace, name)
+	case !found:
+		return nil, fmt.Errorf("PipelineRun %s/%s was found neither in Tekton Results (%v) nor in the cluster", namespace, name, err)
+	case uid != "" && live.Metadata.UID != uid:
+		return nil, fmt.Errorf("PipelineRun %s/%s in the cluster has UID %s, not %s: the archived run is gone and a newer run reuses its name; select that run to cancel it", namespace, name, live.Metadata.UID, uid)
+	}
+	for _, c := range live.Status.Conditions {
+		if c.Type == "Succeeded" && c.Status != "Unknown" {
+			return nil, fmt.Errorf("PipelineRun %s/%s already finished (%s)", namespace, name, c.Reason)
+		}
+	}
+	if isCancelMode(CancelMode(live.Spec.Status)) {
+		return nil, fmt.Errorf("PipelineRun %s/%s is already being cancelled (%s)", namespace, name, live.Spec.Status)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"resourceVersion": live.Metadata.ResourceVersion},
+		"spec":     map[string]any{"status": mode},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.cluster.do(ctx, http.MethodPatch, runPath, "application/merge-patch+json", patch, &live); err != nil {
+		return nil, fmt.Errorf("cancel PipelineRun %s/%s: %w", namespace, name, err)
+	}
+	return &CancelResult{
+		Name:         live.Metadata.Name,
+		Namespace:    live.Metadata.Namespace,
+		UID:          live.Metadata.UID,
+		Mode:         mode,
+		FoundIn:      foundIn,
+		DashboardURL: s.dashboard.URL("PipelineRun", live.Metadata.Namespace, live.Metadata.Name, live.Metadata.UID),
+	}, nil
+}
+
+func isCancelMode(mode CancelMode) bool {
+	return slices.Contains(CancelModes, mode)
+}
@@ -0,0 +1,115 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// fakePipelineRuns serves one live PipelineRun, ci/nightly-x7k2p, and
+// records the patches sent to it.
+type fakePipelineRuns struct {
+	uid       string
+	condition string // status of the Succeeded condition
+	specState string
+	patches   []map[string]any
+}
+
+func (f *fakePipelineRuns) start(t *testing.T) *Service {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/tekton.dev/v1/namespaces/ci/pipelineruns/nightly-x7k2p" || f.uid == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPatch {
+			if ct := r.Header.Get("Content-Type"); ct != "application/merge-patch+json" {
+				t.Errorf("Unexpected content type %s", ct)
+			}
+			body, _ := io.ReadAll(r.Body)
+			var patch map[string]any
+			if err := json.Unmarshal(body, &patch); err != nil {
+				t.Fatalf("decode patch: %v", err)
+			}
+			f.patches = append(f.patches, patch)
+		}
+		fmt.Fprintf(w, `{"metadata":{"name":"nightly-x7k2p","namespace":"ci","uid":%q,"resourceVersion":"42"},"spec":{"status":%q},"status":{"conditions":[{"type":"Succeeded","status":%q,"reason":"Failed"}]}}`, f.uid, f.specState, f.condition)
+	}))
+	t.Cleanup(server.Close)
+	cluster, _ := newClusterClient(&rest.Config{Host: server.URL})
+	return &Service{client: nightlyRecords(), cluster: cluster}
+}
+
+func TestCancelPipelineRun(t *testing.T) {
+	live := &fakePipelineRuns{uid: "pr-uid", condition: "Unknown"}
+	svc := live.start(t)
+	result, err := svc.CancelPipelineRun(context.Background(), RunSelector{Namespace: "ci", UID: "pr-uid"}, StopRunFinally)
+	if err != nil {
+		t.Fatalf("CancelPipelineRun() error = %v", err)
+	}
+	if result.Name != "nightly-x7k2p" || result.Mode != StopRunFinally || result.FoundIn != "results" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if len(live.patches) != 1 {
+		t.Fatalf("Expected one patch, got %v", live.patches)
+	}
+	patch := live.patches[0]
+	if patch["spec"].(map[string]any)["status"] != "StoppedRunFinally" || patch["metadata"].(map[string]any)["resourceVersion"] != "42" {
+		t.Errorf("Unexpected patch %v", patch)
+	}
+}
+
+func TestCancelPipelineRun_NotYetArchived(t *testing.T) {
+	live := &fakePipelineRuns{uid: "live-uid", condition: "Unknown"}
+	svc := live.start(t)
+	svc.client = &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{}, nil
+		},
+	}
+	result, err := svc.CancelPipelineRun(context.Background(), RunSelector{Namespace: "ci", Name: "nightly-x7k2p"}, "")
+	if err != nil {
+		t.Fatalf("CancelPipelineRun() error = %v", err)
+	}
+	if result.FoundIn != "cluster" || result.UID != "live-uid" || result.Mode != CancelNow {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestCancelPipelineRun_Refusals(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		live fakePipelineRuns
+		mode CancelMode
+		want string
+	}{
+		{"finished", fakePipelineRuns{uid: "pr-uid", condition: "False"}, CancelNow, "already finished (Failed)"},
+		{"already cancelling", fakePipelineRuns{uid: "pr-uid", condition: "Unknown", specState: "Cancelled"}, CancelNow, "already being cancelled"},
+		{"name reused", fakePipelineRuns{uid: "other-uid", condition: "Unknown"}, CancelNow, "a newer run reuses its name"},
+		{"deleted", fakePipelineRuns{}, CancelNow, "no longer exists in the cluster"},
+		{"unknown mode", fakePipelineRuns{uid: "pr-uid", condition: "Unknown"}, "Stop", "unsupported cancel mode"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			live := tt.live
+			svc := live.start(t)
+			_, err := svc.CancelPipelineRun(context.Background(), RunSelector{Namespace: "ci", UID: "pr-uid"}, tt.mode)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+			if len(live.patches) != 0 {
+				t.Errorf("Expected no patch, got %v", live.patches)
+			}
+		})
+	}
+
+	if _, err := (&Service{client: nightlyRecords()}).CancelPipelineRun(context.Background(), RunSelector{Namespace: "ci", UID: "pr-uid"}, CancelNow); err == nil {
+		t.Error("Expected an error without cluster access")
+	}
+}
@@ -0,0 +1,171 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// pacPrefix starts the labels and annotations Pipelines as Code sets on the
+// runs it creates.
+const pacPrefix = "pipelinesascode.tekton.dev/"
+
+const defaultGitHubAPI = "https://api.github.com"
+
+// SourceChange is the change that triggered a run, as recorded by Pipelines
+// as Code.
+type SourceChange struct {
+	Provider       string       `json:"provider,omitempty"` // git-provider, e.g. "github" or "gitlab"
+	Repository     string       `json:"repository,omitempty"`
+	SHA            string       `json:"sha,omitempty"`
+	CommitURL      string       `json:"commitURL,omitempty"`
+	PullRequest    int          `json:"pullRequest,omitempty"`
+	PullRequestURL string       `json:"pullRequestURL,omitempty"`
+	EventType      string       `json:"eventType,omitempty"` // e.g. "pull_request" or "push"
+	CheckRunID     int64        `json:"checkRunID,omitempty"`
+	Check          *CheckStatus `json:"check,omitempty"`      // set by CheckStatus when the GitHub integration is enabled
+	CheckError     string       `json:"checkError,omitempty"` // why Check could not be looked up
+}
+
+// CheckStatus is the state GitHub reports for the check run or the commit
+// status of a run.
+type CheckStatus struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`               // queued, in_progress or completed; the combined state for commit statuses
+	Conclusion string `json:"conclusion,omitempty"` // success, failure, ... once completed
+	URL        string `json:"url,omitempty"`
+}
+
+// sourceChange reads the change of a run from its Pipelines as Code labels
+// and annotations. It returns nil for runs not created by Pipelines as Code.
+func sourceChange(labels, annotations map[string]string) *SourceChange {
+	get := func(key string) string {
+		if v := annotations[pacPrefix+key]; v != "" {
+			return v
+		}
+		return labels[pacPrefix+key]
+	}
+	repoURL := strings.TrimSuffix(strings.TrimSuffix(get("repo-url"), "/"), ".git")
+	sha := get("sha")
+	if repoURL == "" && sha == "" {
+		return nil
+	}
+	change := &SourceChange{
+		Provider:   get("git-provider"),
+		Repository: repoURL,
+		SHA:        sha,
+		CommitURL:  get("sha-url"),
+		EventType:  get("event-type"),
+	}
+	if change.CommitURL == "" && repoURL != "" && sha != "" {
+		change.CommitURL = repoURL + "/commit/" + sha
+	}
+	if n, err := strconv.Atoi(get("pull-request")); err == nil && n > 0 {
+		change.PullRequest = n
+		if repoURL != "" {
+			if change.Provider == "gitlab" {
+				change.PullRequestURL = fmt.Sprintf("%s/-/merge_requests/%d", repoURL, n)
+			} else {
+				change.PullRequestURL = fmt.Sprintf("%s/pull/%d", repoURL, n)
+			}
+		}
+	}
+	if id, err := strconv.ParseInt(get("check-run-id"), 10, 64); err == nil && id > 0 {
+		change.CheckRunID = id
+	}
+	return change
+}
+
+// GitHubConfig enables looking up the check runs of runs triggered on GitHub.
+type GitHubConfig struct {
+	Token  string // token with read access to checks and commit statuses
+	APIURL string // REST API of GitHub Enterprise Server; empty uses api.github.com
+}
+
+type githubClient struct {
+	httpClient *http.Client
+	apiURL     string
+	host       string // web host repositories must be on, e.g. "github.com"
+	token      string
+}
+
+func newGitHubClient(cfg GitHubConfig) (*githubClient, error) {
+	if cfg.Token == "" {
+		return nil, nil
+	}
+	apiURL := strings.TrimSuffix(cfg.APIURL, "/")
+	if apiURL == "" {
+		apiURL = defaultGitHubAPI
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid GitHub API URL %q", cfg.APIURL)
+	}
+	// GitHub Enterprise Server serves the API under /api/v3 of the web host.
+	host := u.Hostname()
+	if apiURL == defaultGitHubAPI {
+		host = "github.com"
+	}
+	return &githubClient{httpClient: &http.Client{Timeout: defaultTimeout}, apiURL: apiURL, host: host, token: cfg.Token}, nil
+}
+
+// CheckStatus looks up what GitHub reports for the change: the check run
+// Pipelines as Code created when its id is known, otherwise the combined
+// commit status. It returns nil when the GitHub integration is disabled or
+// the change is not on the configured GitHub host. A check run that belongs
+// to another commit is rejected, so a stale or tampered annotation never
+// links to an unrelated change.
+func (s *Service) CheckStatus(ctx context.Context, change SourceChange) (*CheckStatus, error) {
+	if s.github == nil || change.Repository == "" || change.SHA == "" {
+		return nil, nil
+	}
+	repo, err := url.Parse(change.Repository)
+	if err != nil || !strings.EqualFold(repo.Hostname(), s.github.host) {
+		return nil, nil
+	}
+	ownerRepo := strings.Trim(repo.Path, "/")
+	if strings.Count(ownerRepo, "/") != 1 {
+		return nil, fmt.Errorf("repository URL %s does not name an owner and repository", change.Repository)
+	}
+
+	if change.CheckRunID > 0 {
+		var run struct {
+			Name       string `json:"name"`
+			HeadSHA    string `json:"head_sha"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		}
+		if err := s.github.get(ctx, fmt.Sprintf("/repos/%s/check-runs/%d", ownerRepo, change.CheckRunID), &run); err != nil {
+			return nil, fmt.Errorf("check run %d: %w", change.CheckRunID, err)
+		}
+		if !strings.EqualFold(run.HeadSHA, change.SHA) {
+			return nil, fmt.Errorf("check run %d belongs to commit %s, not %s", change.CheckRunID, run.HeadSHA, change.SHA)
+		}
+		return &CheckStatus{Name: run.Name, Status: run.Status, Conclusion: run.Conclusion, URL: run.HTMLURL}, nil
+	}
+
+	var combined struct {
+		State string `json:"state"`
+		SHA   string `json:"sha"`
+	}
+	if err := s.github.get(ctx, fmt.Sprintf("/repos/%s/commits/%s/status", ownerRepo, url.PathEscape(change.SHA)), &combined); err != nil {
+		return nil, fmt.Errorf("commit status of %s: %w", change.SHA, err)
+	}
+	return &CheckStatus{Name: "combined commit status", Status: combined.State, URL: change.CommitURL}, nil
+}
+
+func (g *githubClient) get(ctx context.Context, apiPath string, out any) error {
+	data, err := getJSON(ctx, g.httpClient, g.apiURL+apiPath, map[string]string{
+		"Authorization":        "Bearer " + g.token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,93 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSourceChange(t *testing.T) {
+	change := sourceChange(
+		map[string]string{
+			"pipelinesascode.tekton.dev/sha":          "abc123",
+			"pipelinesascode.tekton.dev/pull-request": "42",
+			"pipelinesascode.tekton.dev/event-type":   "pull_request",
+			"pipelinesascode.tekton.dev/git-provider": "github",
+		},
+		map[string]string{
+			"pipelinesascode.tekton.dev/repo-url":     "https://github.com/acme/widgets/",
+			"pipelinesascode.tekton.dev/check-run-id": "987",
+		},
+	)
+	if change == nil {
+		t.Fatal("Expected a source change")
+	}
+	if change.CommitURL != "https://github.com/acme/widgets/commit/abc123" || change.PullRequestURL != "https://github.com/acme/widgets/pull/42" || change.CheckRunID != 987 {
+		t.Errorf("Unexpected source change: %+v", change)
+	}
+
+	gitlab := sourceChange(nil, map[string]string{
+		"pipelinesascode.tekton.dev/repo-url":     "https://gitlab.com/acme/widgets",
+		"pipelinesascode.tekton.dev/git-provider": "gitlab",
+		"pipelinesascode.tekton.dev/pull-request": "7",
+		"pipelinesascode.tekton.dev/sha-url":      "https://gitlab.com/acme/widgets/-/commit/def",
+	})
+	if gitlab.PullRequestURL != "https://gitlab.com/acme/widgets/-/merge_requests/7" || gitlab.CommitURL != "https://gitlab.com/acme/widgets/-/commit/def" {
+		t.Errorf("Unexpected GitLab change: %+v", gitlab)
+	}
+
+	if change := sourceChange(map[string]string{"app": "web"}, nil); change != nil {
+		t.Errorf("Expected no change for a run not created by Pipelines as Code, got %+v", change)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/v3/repos/acme/widgets/check-runs/987":
+			fmt.Fprint(w, `{"name":"widgets-on-pull-request","head_sha":"abc123","status":"completed","conclusion":"failure","html_url":"https://github.example.com/acme/widgets/runs/987"}`)
+		case "/api/v3/repos/acme/widgets/commits/abc123/status":
+			fmt.Fprint(w, `{"state":"pending","sha":"abc123"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	github, err := newGitHubClient(GitHubConfig{Token: "ghp_test", APIURL: server.URL + "/api/v3"})
+	if err != nil {
+		t.Fatalf("newGitHubClient() error = %v", err)
+	}
+	svc := &Service{github: github}
+	ctx := context.Background()
+	repo := "https://" + github.host + "/acme/widgets"
+
+	check, err := svc.CheckStatus(ctx, SourceChange{Repository: repo, SHA: "abc123", CheckRunID: 987})
+	if err != nil || check == nil || check.Conclusion != "failure" || check.Name != "widgets-on-pull-request" {
+		t.Fatalf("CheckStatus() = %+v, %v", check, err)
+	}
+	if auth != "Bearer ghp_test" {
+		t.Errorf("Expected the token to be sent, got %q", auth)
+	}
+
+	if _, err := svc.CheckStatus(ctx, SourceChange{Repository: repo, SHA: "fff000", CheckRunID: 987}); err == nil || !strings.Contains(err.Error(), "belongs to commit abc123") {
+		t.Errorf("Expected a check run of another commit to be rejected, got %v", err)
+	}
+
+	check, err = svc.CheckStatus(ctx, SourceChange{Repository: repo, SHA: "abc123", CommitURL: repo + "/commit/abc123"})
+	if err != nil || check == nil || check.Status != "pending" {
+		t.Errorf("Expected the combined commit status, got %+v, %v", check, err)
+	}
+
+	if check, err := svc.CheckStatus(ctx, SourceChange{Repository: "https://gitlab.com/acme/widgets", SHA: "abc123"}); check != nil || err != nil {
+		t.Errorf("Expected repositories on other hosts to be skipped, got %+v, %v", check, err)
+	}
+	if check, err := (&Service{}).CheckStatus(ctx, SourceChange{Repository: repo, SHA: "abc123"}); check != nil || err != nil {
+		t.Errorf("Expected nothing without the GitHub integration, got %+v, %v", check, err)
+	}
+}
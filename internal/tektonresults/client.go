@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,10 +13,13 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
 )
 
 const (
@@ -24,23 +28,57 @@ const (
 	resultsVersion = "v1alpha2"
 	defaultTimeout = 30 * time.Second
 	customAPIPath  = "/apis/results.tekton.dev/v1alpha2"
+
+	// DefaultMaxResponseBytes bounds a single Results API response unless
+	// Overrides.MaxResponseBytes says otherwise.
+	DefaultMaxResponseBytes int64 = 64 << 20
 )
 
 type restClient struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 	authToken  string
+	store      *storeTokenSource               // optional; replaces authToken with a token read from a secret store
+	tokens     atomic.Pointer[namespaceTokens] // optional; per-namespace tokens replacing authToken
+	metrics    *clientMetrics                  // optional; counts upstream requests
+	timeout    time.Duration                   // deadline of a request, including reading its response; defaultTimeout when zero
+	maxBytes   int64                           // largest response accepted; DefaultMaxResponseBytes when zero
+	// retryUnauthorized repeats a request once after HTTP 401 when the
+	// transport manages credentials. Exec credential plugins are re-run
+	// after a 401, so the retry carries a fresh token.
+	retryUnauthorized bool
 }
 
 type Overrides struct {
 	Host               string
 	BearerToken        string
 	InsecureSkipVerify bool
+	Faults             FaultConfig // synthetic failures for chaos testing; disabled by default
+	ScanPageSize       int32       // page size for single-run lookups; 0 uses the default of 50
+	MaxScanPages       int         // pages a single-run lookup may scan; 0 uses the default of 20
+	NamespaceTokens    []NamespaceToken
+	Teams              []Team
+	TokenStore         TokenStore   // read the bearer token from a Kubernetes Secret or Vault instead of BearerToken
+	GitHub             GitHubConfig // look up check runs of Pipelines as Code runs; disabled without a token
+	Dashboard          DashboardTemplate
+	ValidateSchemas    bool          // check fetched runs against the Tekton v1 schema and report drift
+	RequestTimeout     time.Duration // deadline of a Results API request, including its response body; 0 uses 30s
+	MaxResponseBytes   int64         // largest Results API response read; 0 uses DefaultMaxResponseBytes
+	LogArchive         string        // directory of exported logs read when the Results API cannot serve a log
 }
 
 // newRESTClient creates a lightweight HTTP client that reuses the Kubernetes
 // rest.Config for authentication while targeting the Tekton Results aggregated API.
 func newRESTClient(cfg *rest.Config, overrides Overrides) (*restClient, error) {
+	rc, err := newBaseRESTClient(cfg, overrides)
+	if err != nil {
+		return nil, err
+	}
+	rc.timeout, rc.maxBytes = overrides.RequestTimeout, overrides.MaxResponseBytes
+	return rc, nil
+}
+
+func newBaseRESTClient(cfg *rest.Config, overrides Overrides) (*restClient, error) {
 	if overrides.Host != "" {
 		return newCustomClient(cfg, overrides)
 	}
@@ -50,9 +88,8 @@ func newRESTClient(cfg *rest.Config, overrides Overrides) (*restClient, error) {
 	}
 
 	rc := rest.CopyConfig(cfg)
-	if rc.Timeout == 0 {
-		rc.Timeout = defaultTimeout
-	}
+	// Requests carry their own deadline; see send.
+	rc.Timeout = 0
 	rc.APIPath = path.Join(rc.APIPath, apiPathSegment)
 	gv := schema.GroupVersion{
 		Group:   resultsGroup,
@@ -72,8 +109,9 @@ func newRESTClient(cfg *rest.Config, overrides Overrides) (*restClient, error) {
 	baseURL.Path = versionedPath
 
 	return &restClient{
-		baseURL:    baseURL,
-		httpClient: httpClient,
+		baseURL:           baseURL,
+		httpClient:        httpClient,
+		retryUnauthorized: rc.ExecProvider != nil,
 	}, nil
 }
 
@@ -100,8 +138,10 @@ type listResultsRequest struct {
 }
 
 type result struct {
-	Name string `json:"name"`
-	UID  string `json:"uid"`
+	Name       string    `json:"name"`
+	UID        string    `json:"uid"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
 }
 
 type listResultsResponse struct {
@@ -110,15 +150,19 @@ type listResultsResponse struct {
 }
 
 type record struct {
-	Name string `json:"name"`
-	Uid  string `json:"uid"`
-	Data struct {
+	Name       string    `json:"name"`
+	Uid        string    `json:"uid"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+	Data       struct {
+		Type         string          `json:"type"`
 		Value        json.RawMessage `json:"value"`
 		valueDecoded json.RawMessage // cached decoded value
 	} `json:"data"`
 }
 
-// GetValue returns the decoded value, handling base64 encoding if present
+// GetValue returns the decoded value, handling base64 encoding and protobuf
+// Any wrappers if present
 func (r *record) GetValue() (json.RawMessage, error) {
 	if r.Data.valueDecoded != nil {
 		return r.Data.valueDecoded, nil
@@ -130,9 +174,14 @@ func (r *record) GetValue() (json.RawMessage, error) {
 	var test interface{}
 	if err := json.Unmarshal(r.Data.Value, &test); err == nil {
 		// If it's already valid JSON (object or array), use it directly
+		// unless it is the JSON mapping of a protobuf Any
 		if _, isString := test.(string); !isString {
-			r.Data.valueDecoded = r.Data.Value
-			return r.Data.Value, nil
+			value, err := unwrapValue(r.Data.Value)
+			if err != nil {
+				return nil, err
+			}
+			r.Data.valueDecoded = value
+			return value, nil
 		}
 		// It's a JSON string, extract it for base64 decoding
 		if err := json.Unmarshal(r.Data.Value, &base64Str); err != nil {
@@ -148,7 +197,11 @@ func (r *record) GetValue() (json.RawMessage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("decode base64: %w", err)
 	}
-	r.Data.valueDecoded = json.RawMessage(decoded)
+	value, err := unwrapValue(decoded)
+	if err != nil {
+		return nil, err
+	}
+	r.Data.valueDecoded = value
 	return r.Data.valueDecoded, nil
 }
 
@@ -239,6 +292,16 @@ func (c *restClient) listRecords(ctx context.Context, req listRecordsRequest) (*
 	return &resp, nil
 }
 
+// deleteResult removes a Result together with its records and logs.
+func (c *restClient) deleteResult(ctx context.Context, resultName string) error {
+	if resultName == "" {
+		return fmt.Errorf("result name is required")
+	}
+	relative := fmt.Sprintf("parents/%s", strings.TrimPrefix(resultName, "/"))
+	_, err := c.do(ctx, http.MethodDelete, relative, nil)
+	return err
+}
+
 func (c *restClient) getLog(ctx context.Context, logPath string) ([]byte, error) {
 	if logPath == "" {
 		return nil, fmt.Errorf("log path is required")
@@ -254,47 +317,135 @@ func (c *restClient) do(ctx context.Context, method, relPath string, params url.
 		u.RawQuery = params.Encode()
 	}
 
+	token, err := c.tokens.Load().tokenFor(namespaceOf(relPath))
+	if err != nil {
+		return nil, err
+	}
+	fromStore := token == "" && c.store != nil
+	switch {
+	case fromStore:
+		if token, err = c.store.token(ctx); err != nil {
+			return nil, err
+		}
+	case token == "":
+		token = c.authToken
+	}
+
+	status, data, err := c.send(ctx, method, relPath, u, token)
+	if err == nil && status == http.StatusUnauthorized {
+		switch {
+		case fromStore:
+			// The token may have been rotated in the store since it was read.
+			slog.Debug("Results API rejected the stored token, reading it again", "path", u.Path)
+			c.store.expire()
+			if token, err = c.store.token(ctx); err != nil {
+				return nil, err
+			}
+			status, data, err = c.send(ctx, method, relPath, u, token)
+		case token == "" && c.retryUnauthorized:
+			// The credential expired; the 401 made the transport refresh it.
+			slog.Debug("Results API rejected the credential, retrying with a refreshed one", "path", u.Path)
+			status, data, err = c.send(ctx, method, relPath, u, token)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status < 200 || status >= 300 {
+		return nil, &apiError{method: method, path: u.Path, status: status, body: strings.TrimSpace(string(data))}
+	}
+
+	return data, nil
+}
+
+// apiError is a response from the Results API with an unsuccessful status.
+type apiError struct {
+	method, path string
+	status       int
+	body         string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("results API %s %s: %s", e.method, e.path, e.body)
+}
+
+// send performs one request and returns the response status and body. The
+// request deadline also bounds reading the body, so an upstream that sends it
+// slowly cannot hold the caller past the timeout, and bodies larger than
+// maxBytes are refused with a *ResponseTooLargeError.
+func (c *restClient) send(ctx context.Context, method, relPath string, u url.URL, token string) (int, []byte, error) {
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("create %s request: %w", method, err)
+		return 0, nil, fmt.Errorf("create %s request: %w", method, err)
 	}
 	req.Header.Set("Accept", "application/json")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.authToken))
+	// The Kubernetes auth round trippers leave an existing Authorization
+	// header alone, so a scoped token also overrides the kubeconfig
+	// credential on the aggregated API path.
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("perform %s request: %w", method, err)
+		c.metrics.record(endpointFor(method, relPath), 0, time.Since(start))
+		return 0, nil, fmt.Errorf("perform %s request: %w", method, err)
 	}
+	c.metrics.record(endpointFor(method, relPath), resp.StatusCode, time.Since(start))
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
 			slog.Warn("failed to close response body", "error", closeErr)
 		}
 	}()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response body: %w", err)
+	limit := c.maxBytes
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("results API %s %s: %s", method, u.Path, strings.TrimSpace(string(data)))
+	if resp.ContentLength > limit {
+		return 0, nil, &ResponseTooLargeError{Endpoint: endpointFor(method, relPath), Limit: limit}
 	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return 0, nil, fmt.Errorf("read response body: the Results API did not finish sending it within %s: %w", timeout, err)
+	case err != nil:
+		return 0, nil, fmt.Errorf("read response body: %w", err)
+	case int64(len(data)) > limit:
+		return 0, nil, &ResponseTooLargeError{Endpoint: endpointFor(method, relPath), Limit: limit}
+	}
+	return resp.StatusCode, data, nil
+}
 
-	return data, nil
+// ResponseTooLargeError reports a Results API response that exceeded the
+// configured size limit and was abandoned.
+type ResponseTooLargeError struct {
+	Endpoint string // operation, as named in UpstreamStats, e.g. listRecords
+	Limit    int64  // in bytes
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("the Results API response to %s exceeds the %s limit; narrow the query (a smaller limit or page size, or more specific filters) or raise -max-response-size", e.Endpoint, format.Bytes(e.Limit))
 }
 
 func newCustomClient(cfg *rest.Config, overrides Overrides) (*restClient, error) {
 	baseURL, err := url.Parse(overrides.Host)
 	if err != nil {
-		return nil, fmt.Errorf("parse TEKTON_RESULTS_BASE_URL: %w", err)
+		return nil, fmt.Errorf("parse TEKTON_RESULTS_MCP_BASE_URL: %w", err)
 	}
 	if baseURL.Scheme == "" {
 		baseURL.Scheme = "https"
 	}
 	if baseURL.Host == "" {
-		return nil, fmt.Errorf("TEKTON_RESULTS_BASE_URL must include host")
+		return nil, fmt.Errorf("TEKTON_RESULTS_MCP_BASE_URL must include host")
 	}
 	if !strings.Contains(baseURL.Path, resultsGroup) {
 		baseURL.Path = path.Join(baseURL.Path, customAPIPath)
@@ -305,16 +456,25 @@ func newCustomClient(cfg *rest.Config, overrides Overrides) (*restClient, error)
 		token = cfg.BearerToken
 	}
 
-	transport := http.DefaultTransport.(*http.Transport).Clone()
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
 	if overrides.InsecureSkipVerify {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+		baseTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
 	}
+	var rt http.RoundTripper = baseTransport
 
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   defaultTimeout,
+	// In-cluster configs point at a projected service account token that the
+	// kubelet rotates. Re-read it periodically instead of pinning the token
+	// seen at startup, unless an explicit token override was given.
+	if overrides.BearerToken == "" && cfg != nil && cfg.BearerTokenFile != "" {
+		rt, err = transport.NewBearerAuthWithRefreshRoundTripper(cfg.BearerToken, cfg.BearerTokenFile, baseTransport)
+		if err != nil {
+			return nil, fmt.Errorf("read bearer token file %s: %w", cfg.BearerTokenFile, err)
+		}
+		token = ""
 	}
 
+	client := &http.Client{Transport: rt}
+
 	return &restClient{
 		baseURL:    baseURL,
 		httpClient: client,
@@ -3,13 +3,21 @@ package tektonresults
 import (
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 func TestRecordGetValue(t *testing.T) {
@@ -49,6 +57,35 @@ func TestRecordGetValue(t *testing.T) {
 			want:    "",
 			wantErr: false,
 		},
+		{
+			name: "base64 encoded protobuf Any",
+			record: func() record {
+				r := record{}
+				r.Data.Value = json.RawMessage(`"` + base64.StdEncoding.EncodeToString(protoAny("type.googleapis.com/tekton.results.v1alpha2.Any", `{"metadata":{"name":"wrapped"}}`)) + `"`)
+				return r
+			}(),
+			want:    `{"metadata":{"name":"wrapped"}}`,
+			wantErr: false,
+		},
+		{
+			name: "JSON mapping of a protobuf Any",
+			record: func() record {
+				r := record{}
+				r.Data.Value = json.RawMessage(`{"@type":"type.googleapis.com/google.protobuf.BytesValue","value":"` + base64.StdEncoding.EncodeToString([]byte(`{"metadata":{"name":"mapped"}}`)) + `"}`)
+				return r
+			}(),
+			want:    `{"metadata":{"name":"mapped"}}`,
+			wantErr: false,
+		},
+		{
+			name: "protobuf Any of a non-JSON message",
+			record: func() record {
+				r := record{}
+				r.Data.Value = json.RawMessage(`"` + base64.StdEncoding.EncodeToString(protoAny("type.googleapis.com/tekton.v1.PipelineRun", "\x08\x01")) + `"`)
+				return r
+			}(),
+			wantErr: true,
+		},
 		{
 			name: "plain JSON array",
 			record: func() record {
@@ -75,6 +112,19 @@ func TestRecordGetValue(t *testing.T) {
 	}
 }
 
+// protoAny encodes a google.protobuf.Any holding value in the protobuf wire
+// format, with an unknown varint field in between.
+func protoAny(typeURL, value string) []byte {
+	b := binary.AppendUvarint(nil, 1<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(typeURL)))
+	b = append(b, typeURL...)
+	b = binary.AppendUvarint(b, 3<<3|0)
+	b = binary.AppendUvarint(b, 300)
+	b = binary.AppendUvarint(b, 2<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
 func TestRestClient_GetRecord(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -281,3 +331,197 @@ func TestRestClient_ListResults_MinimumPageSize(t *testing.T) {
 		t.Errorf("Expected 1 result, got %d", len(resp.Results))
 	}
 }
+
+func TestNewCustomClient_BearerToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("projected-token"), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cfg       *rest.Config
+		overrides Overrides
+		want      string
+	}{
+		{
+			name: "projected token file is read",
+			cfg:  &rest.Config{BearerTokenFile: tokenFile},
+			want: "Bearer projected-token",
+		},
+		{
+			name:      "explicit override wins over token file",
+			cfg:       &rest.Config{BearerTokenFile: tokenFile},
+			overrides: Overrides{BearerToken: "override-token"},
+			want:      "Bearer override-token",
+		},
+		{
+			name: "static config token",
+			cfg:  &rest.Config{BearerToken: "static-token"},
+			want: "Bearer static-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				//nolint:errcheck // Writing to test HTTP response writer
+				w.Write([]byte(`{"name":"foo/results/uid/records/uid"}`))
+			}))
+			defer server.Close()
+
+			overrides := tt.overrides
+			overrides.Host = server.URL
+			client, err := newCustomClient(tt.cfg, overrides)
+			if err != nil {
+				t.Fatalf("newCustomClient() error = %v", err)
+			}
+			if _, err := client.getRecord(context.Background(), "foo/results/uid/records/uid"); err != nil {
+				t.Fatalf("getRecord() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCustomClient_MissingTokenFile(t *testing.T) {
+	cfg := &rest.Config{BearerTokenFile: filepath.Join(t.TempDir(), "missing")}
+	if _, err := newCustomClient(cfg, Overrides{Host: "https://results.example.com"}); err == nil {
+		t.Fatal("Expected error for unreadable token file")
+	}
+}
+
+func TestRestClient_DeleteResult(t *testing.T) {
+	var method, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, gotPath = r.Method, r.URL.Path
+		//nolint:errcheck // Writing to test HTTP response writer
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := newCustomClient(nil, Overrides{Host: server.URL})
+	if err != nil {
+		t.Fatalf("newCustomClient() error = %v", err)
+	}
+	if err := client.deleteResult(context.Background(), "ci/results/old-1"); err != nil {
+		t.Fatalf("deleteResult() error = %v", err)
+	}
+	if method != http.MethodDelete || gotPath != "/apis/results.tekton.dev/v1alpha2/parents/ci/results/old-1" {
+		t.Errorf("Unexpected request %s %s", method, gotPath)
+	}
+	if err := client.deleteResult(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty result name")
+	}
+}
+
+func TestRestClient_ResponseLimits(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/logs/big"):
+			// Chunked, so the size is only known while reading.
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, strings.Repeat("x", 2048))
+		case strings.HasSuffix(r.URL.Path, "/logs/declared"):
+			w.Header().Set("Content-Length", "4096")
+			fmt.Fprint(w, strings.Repeat("x", 4096))
+		case strings.HasSuffix(r.URL.Path, "/logs/slow"):
+			fmt.Fprint(w, "partial")
+			w.(http.Flusher).Flush()
+			<-release
+		default:
+			fmt.Fprint(w, "small")
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := newRESTClient(nil, Overrides{Host: server.URL, MaxResponseBytes: 1024, RequestTimeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("newRESTClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if data, err := client.getLog(ctx, "ci/results/r/logs/ok"); err != nil || string(data) != "small" {
+		t.Errorf("getLog() = %q, %v", data, err)
+	}
+	for _, name := range []string{"big", "declared"} {
+		_, err := client.getLog(ctx, "ci/results/r/logs/"+name)
+		var tooLarge *ResponseTooLargeError
+		if !errors.As(err, &tooLarge) || tooLarge.Limit != 1024 || tooLarge.Endpoint != "getLog" {
+			t.Errorf("getLog(%s) error = %v, want a ResponseTooLargeError", name, err)
+		} else if !strings.Contains(err.Error(), "exceeds the 1.0 KiB limit") {
+			t.Errorf("Unexpected message: %v", err)
+		}
+	}
+
+	start := time.Now()
+	_, err = client.getLog(ctx, "ci/results/r/logs/slow")
+	if err == nil || !strings.Contains(err.Error(), "did not finish sending it within 200ms") {
+		t.Errorf("getLog(slow) error = %v, want a read timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Slow body held the call for %s", elapsed)
+	}
+}
+
+func TestNewRESTClient_ExecCredentialRefresh(t *testing.T) {
+	// The plugin hands out token-1, token-2, ... on successive runs; only
+	// token-2 is accepted, as if token-1 had expired mid-session.
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "plugin.sh")
+	script := `#!/bin/sh
+n=$(($(cat "$0.count" 2>/dev/null || echo 0) + 1))
+echo "$n" > "$0.count"
+printf '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"token-%s"}}' "$n"
+`
+	if err := os.WriteFile(plugin, []byte(script), 0o700); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+
+	var seen []string
+	accepted := "Bearer token-2"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		seen = append(seen, auth)
+		if auth != accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":16,"message":"Unauthorized"}`)) //nolint:errcheck // Writing to test HTTP response writer
+			return
+		}
+		w.Write([]byte(`{"records":[]}`)) //nolint:errcheck // Writing to test HTTP response writer
+	}))
+	defer server.Close()
+
+	client, err := newRESTClient(&rest.Config{
+		Host: server.URL,
+		ExecProvider: &clientcmdapi.ExecConfig{
+			Command:         plugin,
+			APIVersion:      "client.authentication.k8s.io/v1",
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		},
+	}, Overrides{})
+	if err != nil {
+		t.Fatalf("newRESTClient() error = %v", err)
+	}
+	if _, err := client.listRecords(context.Background(), listRecordsRequest{Parent: "ci/results/-"}); err != nil {
+		t.Fatalf("listRecords() error = %v", err)
+	}
+	if want := []string{"Bearer token-1", "Bearer token-2"}; strings.Join(seen, ",") != strings.Join(want, ",") {
+		t.Errorf("Authorization headers = %v, want %v", seen, want)
+	}
+
+	// A credential that stays rejected is reported after a single retry.
+	seen, accepted = nil, "none"
+	if _, err := client.listRecords(context.Background(), listRecordsRequest{Parent: "ci/results/-"}); err == nil {
+		t.Fatal("Expected an error for a rejected credential")
+	}
+	if want := []string{"Bearer token-2", "Bearer token-3"}; strings.Join(seen, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected a single retry with a refreshed token, got %v", seen)
+	}
+}
@@ -0,0 +1,111 @@
+package tektonresults
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// clusterClient reads and changes Kubernetes objects with the credentials of
+// the kubeconfig: the Tekton Results installation and, for the live cluster
+// tools, PipelineRuns.
+type clusterClient struct {
+	httpClient *http.Client
+	host       string
+}
+
+func newClusterClient(cfg *rest.Config) (*clusterClient, error) {
+	if cfg == nil || cfg.Host == "" {
+		return nil, nil
+	}
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes http client: %w", err)
+	}
+	return &clusterClient{httpClient: httpClient, host: strings.TrimSuffix(cfg.Host, "/")}, nil
+}
+
+// verbs names the RBAC verb of each method, for permission errors.
+var verbs = map[string]string{
+	http.MethodGet:   "get",
+	http.MethodPost:  "create",
+	http.MethodPatch: "patch",
+}
+
+// do sends body, when not nil, to apiPath and decodes the response into out.
+// found is false when the object does not exist.
+func (c *clusterClient) do(ctx context.Context, method, apiPath, contentType string, body []byte, out any) (found bool, err error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.host+apiPath, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode == http.StatusForbidden:
+		return false, fmt.Errorf("forbidden; grant the server %s access to it", verbs[method])
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return false, fmt.Errorf("HTTP %d: %s", resp.StatusCode, statusMessage(data))
+	}
+	return true, json.Unmarshal(data, out)
+}
+
+// get decodes the object at apiPath into out. found is false when the
+// object does not exist.
+func (c *clusterClient) get(ctx context.Context, apiPath string, out any) (found bool, err error) {
+	return c.do(ctx, http.MethodGet, apiPath, "", nil, out)
+}
+
+// statusMessage returns the message of a Kubernetes Status response, or the
+// raw body when it is not one.
+func statusMessage(data []byte) string {
+	var status struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &status) == nil && status.Message != "" {
+		return status.Message
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// configMap returns the data of a ConfigMap, or nil when it does not exist.
+func (c *clusterClient) configMap(ctx context.Context, namespace, name string) (map[string]string, error) {
+	var cm struct {
+		Data map[string]string `json:"data"`
+	}
+	found, err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/configmaps/%s", url.PathEscape(namespace), url.PathEscape(name)), &cm)
+	if err != nil {
+		return nil, fmt.Errorf("read ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	return cm.Data, nil
+}
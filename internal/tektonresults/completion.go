@@ -0,0 +1,169 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CompletionField names a kind of value that CompletionValues can suggest.
+type CompletionField string
+
+const (
+	CompleteNamespace CompletionField = "namespace"
+	CompletePipeline  CompletionField = "pipeline" // tekton.dev/pipeline label values
+	CompleteTask      CompletionField = "task"     // tekton.dev/task label values
+	CompleteLabelKey  CompletionField = "labelKey" // label keys seen on recent runs
+	CompleteReason    CompletionField = "reason"   // CommonReasons and reasons seen on recent runs
+	CompleteTeam      CompletionField = "team"     // teams from the configuration
+)
+
+const (
+	pipelineLabel = "tekton.dev/pipeline"
+	taskLabel     = "tekton.dev/task"
+
+	completionTTL            = time.Minute
+	completionSampleRuns     = 200 // recent runs sampled for label based values
+	completionNamespacePages = 2   // Result pages scanned for namespaces
+)
+
+// completionCache keeps discovered values for completionTTL so interactive
+// clients asking on every keystroke do not reach the Results API each time.
+// The zero value is ready to use.
+type completionCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]completionEntry
+	counter cacheCounter
+}
+
+type completionEntry struct {
+	values  []string
+	expires time.Time
+}
+
+func (c *completionCache) get(key string, load func() ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	if entry, ok := c.entries[key]; ok && now().Before(entry.expires) {
+		c.mu.Unlock()
+		c.counter.observe(true)
+		return entry.values, nil
+	}
+	c.mu.Unlock()
+	c.counter.observe(false)
+
+	// Loads run unlocked; concurrent misses may both load, which is harmless.
+	values, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]completionEntry{}
+	}
+	c.entries[key] = completionEntry{values: values, expires: now().Add(completionTTL)}
+	return values, nil
+}
+
+// CompletionValues returns the known values for field in sorted order,
+// discovered from recent data in Tekton Results and cached briefly. Values
+// for label based fields are sampled from the most recent runs in namespace,
+// so rarely used values may be missing.
+func (s *Service) CompletionValues(ctx context.Context, field CompletionField, namespace string) ([]string, error) {
+	if namespace == "" {
+		namespace = "-"
+	}
+	switch field {
+	case CompleteNamespace:
+		return s.completions.get(string(field), func() ([]string, error) {
+			namespaces, _, err := s.scanNamespaces(ctx, completionNamespacePages)
+			if err != nil && len(namespaces) == 0 {
+				return nil, err
+			}
+			return namespaces, nil
+		})
+	case CompletePipeline:
+		return s.completions.get(string(field)+"/"+namespace, func() ([]string, error) {
+			return s.sampleLabels(ctx, []resourceKind{resourceKindPipelineRun}, namespace, pipelineLabel)
+		})
+	case CompleteTask:
+		return s.completions.get(string(field)+"/"+namespace, func() ([]string, error) {
+			return s.sampleLabels(ctx, []resourceKind{resourceKindTaskRun}, namespace, taskLabel)
+		})
+	case CompleteLabelKey:
+		return s.completions.get(string(field)+"/"+namespace, func() ([]string, error) {
+			return s.sampleLabels(ctx, []resourceKind{resourceKindPipelineRun, resourceKindTaskRun}, namespace, "")
+		})
+	case CompleteReason:
+		return s.completions.get(string(field)+"/"+namespace, func() ([]string, error) {
+			return s.sampleReasons(ctx, namespace)
+		})
+	case CompleteTeam:
+		return s.teams.Load().names(), nil
+	default:
+		return nil, fmt.Errorf("unsupported completion field %q", field)
+	}
+}
+
+// sampleLabels collects the values of label, or every label key when label
+// is empty, from the most recent runs of the given kinds.
+func (s *Service) sampleLabels(ctx context.Context, kinds []resourceKind, namespace, label string) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, kind := range kinds {
+		runs, err := s.listRuns(ctx, kind, ListOptions{Namespace: namespace, Limit: completionSampleRuns})
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs {
+			if label != "" {
+				if v := run.Labels[label]; v != "" {
+					seen[v] = struct{}{}
+				}
+				continue
+			}
+			for key := range run.Labels {
+				seen[key] = struct{}{}
+			}
+		}
+	}
+	values := make([]string, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// sampleReasons returns CommonReasons together with the Succeeded condition
+// reasons of the most recent runs, which may include reasons set by custom
+// task controllers.
+func (s *Service) sampleReasons(ctx context.Context, namespace string) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, reason := range CommonReasons {
+		seen[reason] = struct{}{}
+	}
+	for _, kind := range []resourceKind{resourceKindPipelineRun, resourceKindTaskRun} {
+		runs, err := s.listRuns(ctx, kind, ListOptions{Namespace: namespace, Limit: completionSampleRuns})
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs {
+			if run.Reason != "" {
+				seen[run.Reason] = struct{}{}
+			}
+		}
+	}
+	values := make([]string, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values, nil
+}
@@ -0,0 +1,120 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_CompletionValues(t *testing.T) {
+	labels := map[string][]string{
+		"PipelineRun": {`{"tekton.dev/pipeline":"build","app":"web"}`, `{"tekton.dev/pipeline":"deploy"}`, `{"tekton.dev/pipeline":"build"}`},
+		"TaskRun":     {`{"tekton.dev/task":"compile","tekton.dev/pipelineTask":"compile"}`},
+	}
+	var recordCalls int
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			recordCalls++
+			if req.Parent != "ci/results/-" {
+				t.Errorf("Expected namespace ci, got parent %s", req.Parent)
+			}
+			kind := "TaskRun"
+			if strings.Contains(req.Filter, "PipelineRun") {
+				kind = "PipelineRun"
+			}
+			var records []record
+			for i, l := range labels[kind] {
+				uid := fmt.Sprintf("%s-%d", kind, i)
+				rec := record{Name: "ci/results/" + uid + "/records/" + uid, Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":"%s","namespace":"ci","uid":"%s","labels":%s}}`, uid, uid, l))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+		listResultsFunc: func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+			return &listResultsResponse{Results: []result{{Name: "staging/results/a"}, {Name: "ci/results/b"}, {Name: "ci/results/c"}}}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+	ctx := context.Background()
+
+	tests := []struct {
+		field CompletionField
+		want  []string
+	}{
+		{CompleteNamespace, []string{"ci", "staging"}},
+		{CompletePipeline, []string{"build", "deploy"}},
+		{CompleteTask, []string{"compile"}},
+		{CompleteLabelKey, []string{"app", "tekton.dev/pipeline", "tekton.dev/pipelineTask", "tekton.dev/task"}},
+	}
+	for _, tt := range tests {
+		got, err := service.CompletionValues(ctx, tt.field, "ci")
+		if err != nil {
+			t.Fatalf("CompletionValues(%s) error = %v", tt.field, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CompletionValues(%s) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+
+	calls := recordCalls
+	if _, err := service.CompletionValues(ctx, CompletePipeline, "ci"); err != nil {
+		t.Fatalf("CompletionValues() error = %v", err)
+	}
+	if recordCalls != calls {
+		t.Error("Expected cached pipeline names to be served without another API call")
+	}
+
+	if _, err := service.CompletionValues(ctx, "color", "ci"); err == nil {
+		t.Error("Expected error for unsupported field")
+	}
+}
+
+func TestCompletionCache_Expires(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := completionCache{now: func() time.Time { return now }}
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"a"}, nil
+	}
+
+	_, _ = cache.get("k", load)
+	_, _ = cache.get("k", load)
+	if loads != 1 {
+		t.Fatalf("Expected one load while fresh, got %d", loads)
+	}
+	now = now.Add(completionTTL)
+	_, _ = cache.get("k", load)
+	if loads != 2 {
+		t.Errorf("Expected a reload after the TTL, got %d loads", loads)
+	}
+
+	if _, err := cache.get("err", func() ([]string, error) { return nil, fmt.Errorf("boom") }); err == nil {
+		t.Error("Expected load error to be returned")
+	}
+	if _, ok := cache.entries["err"]; ok {
+		t.Error("Failed loads must not be cached")
+	}
+}
+
+func TestCompletionValues_Team(t *testing.T) {
+	service := &Service{client: &mockRestClient{}}
+	if values, err := service.CompletionValues(context.Background(), CompleteTeam, ""); err != nil || len(values) != 0 {
+		t.Errorf("Expected no teams, got %v, %v", values, err)
+	}
+	if err := service.Reconfigure(Settings{Teams: []Team{
+		{Name: "Platform", Selectors: []string{"team=platform"}},
+		{Name: "Payments", Selectors: []string{"team=payments"}},
+	}}); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	values, err := service.CompletionValues(context.Background(), CompleteTeam, "")
+	if err != nil || strings.Join(values, ",") != "Payments,Platform" {
+		t.Errorf("CompletionValues(team) = %v, %v", values, err)
+	}
+}
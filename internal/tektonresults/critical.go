@@ -0,0 +1,280 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// Dependency kinds of a pipeline task on an earlier one.
+const (
+	// DependsOnResults marks a task consuming results of the earlier task,
+	// which it cannot run without.
+	DependsOnResults = "results"
+	// DependsOnOrder marks a runAfter ordering with no data flowing between
+	// the tasks, which may be removable.
+	DependsOnOrder = "runAfter"
+)
+
+// CriticalTask is one pipeline task of a critical path analysis.
+type CriticalTask struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`          // from the first start to the last completion of its TaskRuns
+	Finally  bool          `json:"finally,omitempty"` // runs after every other task
+	NotRun   bool          `json:"notRun,omitempty"`  // no TaskRun completed, e.g. skipped; counted as taking no time
+	// Critical reports a task on the critical path, which gates the total
+	// duration.
+	Critical bool `json:"critical"`
+	// Saving is, for a critical task, how much shorter the critical path
+	// would be if the task took no time: the margin by which it gates the
+	// pipeline over the next longest path.
+	Saving time.Duration `json:"saving,omitempty"`
+	// Slack is, for other tasks, how much longer the task could take before
+	// it gated the total duration.
+	Slack time.Duration `json:"slack,omitempty"`
+	// After lists the tasks it waits for, each with the kind of dependency.
+	After map[string]string `json:"after,omitempty"`
+}
+
+// CriticalPath weights the task graph of a PipelineRun with the durations of
+// its TaskRuns and reports the longest chain of dependent tasks, which bounds
+// how fast the pipeline can run however many tasks run in parallel.
+type CriticalPath struct {
+	Path  []string      `json:"path"`  // critical tasks in execution order
+	Total time.Duration `json:"total"` // sum of the durations along the path
+	// Wall is how long the PipelineRun took; the difference to Total is time
+	// spent scheduling pods and waiting between tasks.
+	Wall  time.Duration  `json:"wall,omitempty"`
+	Tasks []CriticalTask `json:"tasks"` // every pipeline task, in declaration order
+}
+
+// pipelineGraph holds the parts of a PipelineRun manifest the task graph is
+// read from. Tekton copies the resolved Pipeline into status.pipelineSpec;
+// spec.pipelineSpec covers runs stored before that happened.
+type pipelineGraph struct {
+	Spec struct {
+		PipelineSpec *pipelineTasks `json:"pipelineSpec"`
+	} `json:"spec"`
+	Status struct {
+		PipelineSpec *pipelineTasks `json:"pipelineSpec"`
+	} `json:"status"`
+}
+
+type pipelineTasks struct {
+	Tasks   []pipelineTask `json:"tasks"`
+	Finally []pipelineTask `json:"finally"`
+}
+
+type pipelineTask struct {
+	Name     string          `json:"name"`
+	RunAfter []string        `json:"runAfter"`
+	Params   json.RawMessage `json:"params"`
+	When     json.RawMessage `json:"when"`
+	Matrix   json.RawMessage `json:"matrix"`
+}
+
+// resultReference matches references to results of other pipeline tasks in
+// params and when expressions, such as $(tasks.build.results.digest).
+var resultReference = regexp.MustCompile(`\$\(tasks\.([a-z0-9]([-a-z0-9]*[a-z0-9])?)\.results\.`)
+
+// dependencies returns the earlier tasks t waits for and why.
+func (t pipelineTask) dependencies() map[string]string {
+	deps := map[string]string{}
+	for _, name := range t.RunAfter {
+		deps[name] = DependsOnOrder
+	}
+	for _, raw := range []json.RawMessage{t.Params, t.When, t.Matrix} {
+		for _, m := range resultReference.FindAllSubmatch(raw, -1) {
+			deps[string(m[1])] = DependsOnResults
+		}
+	}
+	return deps
+}
+
+// CriticalPath analyzes the PipelineRun d with its TaskRuns. TaskRuns are
+// matched to pipeline tasks by PipelineTask; several TaskRuns of one task,
+// as a matrix creates, span from the first start to the last completion.
+func (d RunDetail) CriticalPath(taskRuns []RunSummary) (*CriticalPath, error) {
+	var run pipelineGraph
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil, fmt.Errorf("decode PipelineRun: %w", err)
+	}
+	spec := run.Status.PipelineSpec
+	if spec == nil {
+		spec = run.Spec.PipelineSpec
+	}
+	if spec == nil || len(spec.Tasks) == 0 {
+		return nil, fmt.Errorf("PipelineRun %s has no resolved pipeline spec to analyze; it may not have started", d.Summary.Name)
+	}
+
+	spans := map[string][2]time.Time{}
+	for _, tr := range taskRuns {
+		if tr.PipelineTask == "" || tr.StartTime == nil || tr.CompletionTime == nil {
+			continue
+		}
+		start, end := tr.StartTime.Time, tr.CompletionTime.Time
+		if span, ok := spans[tr.PipelineTask]; ok {
+			start, end = minTime(start, span[0]), maxTime(end, span[1])
+		}
+		spans[tr.PipelineTask] = [2]time.Time{start, end}
+	}
+
+	out := &CriticalPath{}
+	if wall, ok := d.Summary.Duration(); ok && !d.Summary.ClockSkew {
+		out.Wall = wall
+	}
+	index := map[string]int{}
+	for i, t := range append(slices.Clone(spec.Tasks), spec.Finally...) {
+		task := CriticalTask{Name: t.Name, Finally: i >= len(spec.Tasks), After: t.dependencies()}
+		if span, ok := spans[t.Name]; ok {
+			task.Duration, _ = Elapsed(span[0], span[1])
+		} else {
+			task.NotRun = true
+		}
+		index[t.Name] = i
+		out.Tasks = append(out.Tasks, task)
+	}
+	// Finally tasks wait for every task; references to unknown tasks, which
+	// Tekton rejects, are dropped.
+	for i := range out.Tasks {
+		t := &out.Tasks[i]
+		for name := range t.After {
+			if _, ok := index[name]; !ok || name == t.Name {
+				delete(t.After, name)
+			}
+		}
+		if t.Finally {
+			for _, dag := range spec.Tasks {
+				if _, ok := t.After[dag.Name]; !ok {
+					t.After[dag.Name] = DependsOnOrder
+				}
+			}
+		}
+		if len(t.After) == 0 {
+			t.After = nil
+		}
+	}
+
+	order, err := topologicalOrder(out.Tasks)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]time.Duration, len(out.Tasks))
+	for i, t := range out.Tasks {
+		weights[i] = t.Duration
+	}
+	finish, prev := longestPaths(out.Tasks, index, order, weights)
+	last := 0
+	for i := range finish {
+		if finish[i] > finish[last] {
+			last = i
+		}
+	}
+	out.Total = finish[last]
+	for i := last; i >= 0; i = prev[i] {
+		out.Tasks[i].Critical = true
+		out.Path = append(out.Path, out.Tasks[i].Name)
+	}
+	slices.Reverse(out.Path)
+
+	// The longest path through each task is its earliest finish plus the
+	// longest chain of tasks waiting for it.
+	tail := make([]time.Duration, len(out.Tasks))
+	for _, i := range slices.Backward(order) {
+		tail[i] = weights[i]
+		for _, j := range order {
+			if _, ok := out.Tasks[j].After[out.Tasks[i].Name]; ok {
+				tail[i] = max(tail[i], weights[i]+tail[j])
+			}
+		}
+	}
+	for i := range out.Tasks {
+		t := &out.Tasks[i]
+		if !t.Critical {
+			t.Slack = out.Total - (finish[i] + tail[i] - weights[i])
+			continue
+		}
+		reduced := slices.Clone(weights)
+		reduced[i] = 0
+		f, _ := longestPaths(out.Tasks, index, order, reduced)
+		t.Saving = out.Total - slices.Max(f)
+	}
+	return out, nil
+}
+
+// topologicalOrder orders tasks so every task follows the tasks it waits
+// for, keeping declaration order among independent tasks.
+func topologicalOrder(tasks []CriticalTask) ([]int, error) {
+	waiting := make([]int, len(tasks))
+	for i, t := range tasks {
+		waiting[i] = len(t.After)
+	}
+	done := make([]bool, len(tasks))
+	order := make([]int, 0, len(tasks))
+	for len(order) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if !done[i] && waiting[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("the pipeline tasks depend on each other in a cycle")
+		}
+		done[next] = true
+		order = append(order, next)
+		for i, t := range tasks {
+			if _, ok := t.After[tasks[next].Name]; ok {
+				waiting[i]--
+			}
+		}
+	}
+	return order, nil
+}
+
+// longestPaths returns, for each task, the earliest time it could finish if
+// every task started as soon as the tasks it waits for finished, and the
+// task on that chain before it, or -1.
+func longestPaths(tasks []CriticalTask, index map[string]int, order []int, weights []time.Duration) ([]time.Duration, []int) {
+	finish := make([]time.Duration, len(tasks))
+	prev := make([]int, len(tasks))
+	for _, i := range order {
+		prev[i] = -1
+		var start time.Duration
+		// Dependencies are visited in declaration order, so ties pick the
+		// same predecessor on every call.
+		for _, j := range sortedDependencies(tasks[i], index) {
+			if finish[j] > start || prev[i] < 0 {
+				start, prev[i] = max(start, finish[j]), j
+			}
+		}
+		finish[i] = start + weights[i]
+	}
+	return finish, prev
+}
+
+func sortedDependencies(t CriticalTask, index map[string]int) []int {
+	deps := make([]int, 0, len(t.After))
+	for name := range t.After {
+		deps = append(deps, index[name])
+	}
+	slices.Sort(deps)
+	return deps
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
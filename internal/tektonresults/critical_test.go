@@ -0,0 +1,101 @@
+package tektonresults
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// criticalRun is a PipelineRun whose Pipeline clones, then builds and lints
+// in parallel, then tests after build (through runAfter only) and pushes the
+// image digest build reports, with a finally task.
+const criticalRun = `{
+  "kind": "PipelineRun",
+  "status": {"pipelineSpec": {
+    "tasks": [
+      {"name": "clone"},
+      {"name": "build", "runAfter": ["clone"]},
+      {"name": "lint", "runAfter": ["clone"]},
+      {"name": "test", "runAfter": ["build"]},
+      {"name": "push", "params": [{"name": "digest", "value": "$(tasks.build.results.digest)"}]},
+      {"name": "skipped", "runAfter": ["clone"]}
+    ],
+    "finally": [{"name": "notify"}]
+  }}
+}`
+
+func TestCriticalPath(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	taskRun := func(task string, start, minutes int) RunSummary {
+		s, e := metav1.NewTime(base.Add(time.Duration(start)*time.Minute)), metav1.NewTime(base.Add(time.Duration(start+minutes)*time.Minute))
+		return RunSummary{PipelineTask: task, StartTime: &s, CompletionTime: &e}
+	}
+	end := metav1.NewTime(base.Add(30 * time.Minute))
+	detail := RunDetail{
+		Summary: RunSummary{Name: "build-x7k2p", StartTime: &metav1.Time{Time: base}, CompletionTime: &end},
+		Raw:     []byte(criticalRun),
+	}
+
+	path, err := detail.CriticalPath([]RunSummary{
+		taskRun("clone", 0, 2),
+		taskRun("build", 2, 10),
+		taskRun("lint", 2, 4),
+		// A matrix fan-out: the task spans both TaskRuns.
+		taskRun("test", 12, 6),
+		taskRun("test", 13, 8),
+		taskRun("push", 12, 3),
+		taskRun("notify", 21, 1),
+	})
+	if err != nil {
+		t.Fatalf("CriticalPath() error = %v", err)
+	}
+
+	want := []string{"clone", "build", "test", "notify"}
+	if len(path.Path) != len(want) {
+		t.Fatalf("Path = %v, want %v", path.Path, want)
+	}
+	for i := range want {
+		if path.Path[i] != want[i] {
+			t.Fatalf("Path = %v, want %v", path.Path, want)
+		}
+	}
+	if path.Total != 22*time.Minute || path.Wall != 30*time.Minute {
+		t.Errorf("Total = %s, Wall = %s; want 22m and 30m", path.Total, path.Wall)
+	}
+
+	tasks := map[string]CriticalTask{}
+	for _, task := range path.Tasks {
+		tasks[task.Name] = task
+	}
+	// Without build, clone -> test takes 11m before notify, so build gates
+	// the run by its whole 10m.
+	if got := tasks["build"].Saving; got != 10*time.Minute {
+		t.Errorf("build saving = %s, want 10m", got)
+	}
+	if got := tasks["test"].Duration; got != 9*time.Minute {
+		t.Errorf("test duration = %s, want the 9m span of its TaskRuns", got)
+	}
+	if got := tasks["lint"].Slack; got != 15*time.Minute {
+		t.Errorf("lint slack = %s, want 15m", got)
+	}
+	if tasks["push"].After["build"] != DependsOnResults || tasks["test"].After["build"] != DependsOnOrder {
+		t.Errorf("Unexpected dependencies: push %v, test %v", tasks["push"].After, tasks["test"].After)
+	}
+	if !tasks["skipped"].NotRun || !tasks["notify"].Finally || len(tasks["notify"].After) != 6 {
+		t.Errorf("Unexpected skipped or finally task: %+v, %+v", tasks["skipped"], tasks["notify"])
+	}
+}
+
+func TestCriticalPath_Cycle(t *testing.T) {
+	detail := RunDetail{Raw: []byte(`{"status": {"pipelineSpec": {"tasks": [
+		{"name": "a", "runAfter": ["b"]},
+		{"name": "b", "runAfter": ["a"]}
+	]}}}`)}
+	if _, err := detail.CriticalPath(nil); err == nil {
+		t.Error("Expected a cycle to be rejected")
+	}
+	if _, err := (RunDetail{Raw: []byte(`{"status": {}}`)}).CriticalPath(nil); err == nil {
+		t.Error("Expected a run without a pipeline spec to be rejected")
+	}
+}
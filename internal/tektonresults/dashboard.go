@@ -0,0 +1,60 @@
+package tektonresults
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// dashboardPlaceholders are the values a dashboard URL template can refer
+// to, e.g. "https://tekton.example.com/#/namespaces/{namespace}/{resource}/{name}".
+var dashboardPlaceholders = map[string]func(kind, namespace, name, uid string) string{
+	"namespace": func(_, namespace, _, _ string) string { return namespace },
+	"name":      func(_, _, name, _ string) string { return name },
+	"uid":       func(_, _, _, uid string) string { return uid },
+	"kind":      func(kind, _, _, _ string) string { return kind },                        // PipelineRun or TaskRun
+	"resource":  func(kind, _, _, _ string) string { return strings.ToLower(kind) + "s" }, // pipelineruns or taskruns
+}
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// DashboardTemplate builds links to runs in a web UI, such as the Tekton
+// Dashboard or the OpenShift console, from a URL with {namespace}, {name},
+// {uid}, {kind} and {resource} placeholders. The zero value builds no links.
+type DashboardTemplate struct {
+	tmpl string
+}
+
+// ParseDashboardTemplate validates a dashboard URL template. An empty
+// template disables dashboard links.
+func ParseDashboardTemplate(tmpl string) (DashboardTemplate, error) {
+	tmpl = strings.TrimSpace(tmpl)
+	if tmpl == "" {
+		return DashboardTemplate{}, nil
+	}
+	for _, p := range placeholderPattern.FindAllString(tmpl, -1) {
+		if _, ok := dashboardPlaceholders[strings.Trim(p, "{}")]; !ok {
+			return DashboardTemplate{}, fmt.Errorf("unknown placeholder %s; use {namespace}, {name}, {uid}, {kind} or {resource}", p)
+		}
+	}
+	if !strings.Contains(tmpl, "{name}") && !strings.Contains(tmpl, "{uid}") {
+		return DashboardTemplate{}, fmt.Errorf("template must contain {name} or {uid} to identify the run")
+	}
+	u, err := url.Parse(placeholderPattern.ReplaceAllString(tmpl, "x"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return DashboardTemplate{}, fmt.Errorf("template %q is not an http or https URL", tmpl)
+	}
+	return DashboardTemplate{tmpl: tmpl}, nil
+}
+
+// URL returns the link to a run, or "" when no template is set or the run
+// cannot be identified.
+func (t DashboardTemplate) URL(kind, namespace, name, uid string) string {
+	if t.tmpl == "" || kind == "" || namespace == "" || name == "" {
+		return ""
+	}
+	return placeholderPattern.ReplaceAllStringFunc(t.tmpl, func(p string) string {
+		return url.PathEscape(dashboardPlaceholders[strings.Trim(p, "{}")](kind, namespace, name, uid))
+	})
+}
@@ -0,0 +1,62 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDashboardTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		tmpl, want string
+	}{
+		{"https://tekton.example.com/#/namespaces/{namespace}/{resource}/{name}", "https://tekton.example.com/#/namespaces/ci/pipelineruns/build%20x"},
+		{"https://console.apps.example.com/k8s/ns/{namespace}/tekton.dev~v1~{kind}/{name}", "https://console.apps.example.com/k8s/ns/ci/tekton.dev~v1~PipelineRun/build%20x"},
+		{"https://ui.example.com/runs/{uid}", "https://ui.example.com/runs/uid-1"},
+	} {
+		tmpl, err := ParseDashboardTemplate(tt.tmpl)
+		if err != nil {
+			t.Fatalf("ParseDashboardTemplate(%q) error = %v", tt.tmpl, err)
+		}
+		if got := tmpl.URL("PipelineRun", "ci", "build x", "uid-1"); got != tt.want {
+			t.Errorf("URL() = %q, want %q", got, tt.want)
+		}
+	}
+
+	if got := (DashboardTemplate{}).URL("PipelineRun", "ci", "build", "uid-1"); got != "" {
+		t.Errorf("Expected no URL without a template, got %q", got)
+	}
+
+	for tmpl, want := range map[string]string{
+		"https://tekton.example.com/{namespace}/{run}": "unknown placeholder {run}",
+		"https://tekton.example.com/{namespace}":       "must contain {name} or {uid}",
+		"tekton.example.com/{name}":                    "not an http or https URL",
+	} {
+		if _, err := ParseDashboardTemplate(tmpl); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseDashboardTemplate(%q) error = %v, want %q", tmpl, err, want)
+		}
+	}
+}
+
+func TestService_Summarize_DashboardURL(t *testing.T) {
+	dashboard, _ := ParseDashboardTemplate("https://tekton.example.com/#/namespaces/{namespace}/{resource}/{name}")
+	service := &Service{dashboard: dashboard}
+
+	// The kind comes from the manifest, or from the record type when the
+	// manifest has none.
+	rec := record{Name: "ci/results/uid/records/uid"}
+	rec.Data.Type = "tekton.dev/v1.TaskRun"
+	rec.Data.Value = json.RawMessage(`{"metadata":{"name":"compile","namespace":"ci"}}`)
+	run, err := decodeRun(rec)
+	if err != nil {
+		t.Fatalf("decodeRun() error = %v", err)
+	}
+	if got := service.summarize(run, rec).DashboardURL; got != "https://tekton.example.com/#/namespaces/ci/taskruns/compile" {
+		t.Errorf("Unexpected dashboard URL %q", got)
+	}
+
+	run.Kind = "PipelineRun"
+	if got := service.summarize(run, rec).DashboardURL; got != "https://tekton.example.com/#/namespaces/ci/pipelineruns/compile" {
+		t.Errorf("Unexpected dashboard URL %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// configFields maps reasons of runs rejected before any step ran to the spec
+// field they point at, for PipelineRuns and TaskRuns. Pipeline fields are
+// reported relative to the Pipeline, which is either spec.pipelineSpec or the
+// one named by spec.pipelineRef.
+var configFields = map[string]struct{ pipelineRun, taskRun string }{
+	"CouldntGetPipeline":         {pipelineRun: "spec.pipelineRef"},
+	"CouldntGetTask":             {pipelineRun: "pipeline spec.tasks[].taskRef", taskRun: "spec.taskRef"},
+	"TaskRunResolutionFailed":    {taskRun: "spec.taskRef"},
+	"InvalidWorkspaceBindings":   {pipelineRun: "spec.workspaces", taskRun: "spec.workspaces"},
+	"ParameterMissing":           {pipelineRun: "spec.params", taskRun: "spec.params"},
+	"ParameterTypeMismatch":      {pipelineRun: "spec.params", taskRun: "spec.params"},
+	"InvalidTaskResultReference": {pipelineRun: "pipeline spec.tasks[].params"},
+	"PipelineInvalidGraph":       {pipelineRun: "pipeline spec.tasks[].runAfter"},
+	"PipelineValidationFailed":   {pipelineRun: "pipeline spec"},
+	"TaskRunValidationFailed":    {taskRun: "spec.params, spec.workspaces or the Task spec"},
+}
+
+// ConfigFailure describes a run that failed because of its configuration: a
+// reference that could not be resolved, or params and workspaces that did not
+// validate. Such runs never start a pod, so they have no logs and the
+// condition message is all there is to go on.
+type ConfigFailure struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`       // Succeeded condition message, verbatim
+	Field   string `json:"field"`         // spec field the reason points at
+	Ref     string `json:"ref,omitempty"` // pipelineRef or taskRef of the run, when set
+}
+
+// String renders the failure for tool output, quoting the message unchanged.
+func (f ConfigFailure) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Configuration failure: %s\n", f.Reason)
+	fmt.Fprintf(&b, "Field: %s\n", f.Field)
+	if f.Ref != "" {
+		fmt.Fprintf(&b, "Reference: %s\n", f.Ref)
+	}
+	b.WriteString("Message:\n")
+	for _, line := range strings.Split(strings.TrimRight(f.Message, "\n"), "\n") {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+	b.WriteString("No logs exist for this run: it was rejected before any step ran. Fix the field above and rerun.")
+	return b.String()
+}
+
+// configRun holds the parts of a run manifest a ConfigFailure is built from.
+type configRun struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		PipelineRef *runRef `json:"pipelineRef"`
+		TaskRef     *runRef `json:"taskRef"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+type runRef struct {
+	Name     string `json:"name"`
+	Resolver string `json:"resolver"`
+	Params   []struct {
+		Name  string `json:"name"`
+		Value any    `json:"value"`
+	} `json:"params"`
+}
+
+func (r *runRef) String() string {
+	if r == nil {
+		return ""
+	}
+	if r.Resolver == "" {
+		return r.Name
+	}
+	params := make([]string, 0, len(r.Params))
+	for _, p := range r.Params {
+		params = append(params, fmt.Sprintf("%s=%v", p.Name, p.Value))
+	}
+	if len(params) == 0 {
+		return fmt.Sprintf("%s resolver", r.Resolver)
+	}
+	return fmt.Sprintf("%s resolver (%s)", r.Resolver, strings.Join(params, ", "))
+}
+
+// ConfigFailure returns the configuration failure of the run, or nil when
+// the run did not fail or failed for another reason.
+func (d RunDetail) ConfigFailure() *ConfigFailure {
+	var run configRun
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil
+	}
+	for _, cond := range run.Status.Conditions {
+		if cond.Type != "Succeeded" || cond.Status != "False" {
+			continue
+		}
+		fields, ok := configFields[cond.Reason]
+		if !ok {
+			return nil
+		}
+		failure := &ConfigFailure{Reason: cond.Reason, Message: cond.Message}
+		if run.Kind == "TaskRun" || run.Kind == "" && run.Spec.TaskRef != nil {
+			failure.Field, failure.Ref = fields.taskRun, run.Spec.TaskRef.String()
+		} else {
+			failure.Field, failure.Ref = fields.pipelineRun, run.Spec.PipelineRef.String()
+		}
+		if failure.Field == "" {
+			failure.Field = "spec"
+		}
+		return failure
+	}
+	return nil
+}
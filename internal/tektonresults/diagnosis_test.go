@@ -0,0 +1,58 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunDetail_ConfigFailure(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		raw   string
+		field string
+		ref   string
+	}{
+		{
+			name:  "missing task in a pipeline",
+			raw:   `{"kind":"PipelineRun","spec":{"pipelineRef":{"name":"build"}},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"CouldntGetTask","message":"Pipeline ci/build can't be Run; it contains Tasks that don't exist: Couldn't retrieve Task \"compile\": tasks.tekton.dev \"compile\" not found"}]}}`,
+			field: "pipeline spec.tasks[].taskRef",
+			ref:   "build",
+		},
+		{
+			name:  "workspace binding",
+			raw:   `{"kind":"PipelineRun","spec":{"pipelineRef":{"resolver":"git","params":[{"name":"pathInRepo","value":"build.yaml"}]}},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"InvalidWorkspaceBindings","message":"pipeline requires workspace with name \"source\" be provided by pipelinerun"}]}}`,
+			field: "spec.workspaces",
+			ref:   "git resolver (pathInRepo=build.yaml)",
+		},
+		{
+			name:  "taskrun without kind",
+			raw:   `{"spec":{"taskRef":{"name":"compile"}},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"CouldntGetTask","message":"error when listing tasks"}]}}`,
+			field: "spec.taskRef",
+			ref:   "compile",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := RunDetail{Raw: json.RawMessage(tt.raw)}.ConfigFailure()
+			if failure == nil {
+				t.Fatal("Expected a configuration failure")
+			}
+			if failure.Field != tt.field || failure.Ref != tt.ref {
+				t.Errorf("Got field %q ref %q, want %q and %q", failure.Field, failure.Ref, tt.field, tt.ref)
+			}
+			if !strings.Contains(failure.String(), "  "+failure.Message+"\n") {
+				t.Errorf("Expected the message to be quoted verbatim, got:\n%s", failure)
+			}
+		})
+	}
+
+	for _, raw := range []string{
+		`{"kind":"PipelineRun","status":{"conditions":[{"type":"Succeeded","status":"False","reason":"Failed","message":"Tasks Completed: 1 (Failed: 1)"}]}}`,
+		`{"kind":"PipelineRun","status":{"conditions":[{"type":"Succeeded","status":"True","reason":"Succeeded"}]}}`,
+		`not json`,
+	} {
+		if failure := (RunDetail{Raw: json.RawMessage(raw)}).ConfigFailure(); failure != nil {
+			t.Errorf("Expected no configuration failure for %s, got %+v", raw, failure)
+		}
+	}
+}
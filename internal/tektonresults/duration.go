@@ -0,0 +1,73 @@
+package tektonresults
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clockSkewTolerance is how far an end time may precede its start time
+// before the pair is reported as skewed. Start and end times are stamped by
+// different clocks (the controller, the kubelet, the Results watcher) and
+// usually have whole-second precision, so a small negative span is rounding,
+// not a broken clock.
+const clockSkewTolerance = time.Second
+
+// Elapsed returns the time from start to end. Spans where end precedes start
+// are clamped to zero; skewed reports those that precede it by more than
+// clockSkewTolerance, which only disagreeing clocks can produce.
+func Elapsed(start, end time.Time) (d time.Duration, skewed bool) {
+	d = end.Sub(start)
+	if d < 0 {
+		return 0, -d > clockSkewTolerance
+	}
+	return d, false
+}
+
+// elapsed is Elapsed for the optional timestamps of Tekton manifests. ok is
+// false when either bound is unset.
+func elapsed(start, end *metav1.Time) (d time.Duration, skewed, ok bool) {
+	if start == nil || end == nil || start.IsZero() || end.IsZero() {
+		return 0, false, false
+	}
+	d, skewed = Elapsed(start.Time, end.Time)
+	return d, skewed, true
+}
+
+// Duration returns how long a completed run took. ok is false while the run
+// has not started or completed. Runs whose completion time precedes their
+// start time report zero; ClockSkew tells those apart from runs that really
+// took no time.
+func (s RunSummary) Duration() (d time.Duration, ok bool) {
+	d, _, ok = elapsed(s.StartTime, s.CompletionTime)
+	return d, ok
+}
+
+// durationRange matches runs by how long they took. A zero bound is unset;
+// with any bound set, runs without a reliable duration, because they are
+// still running or their timestamps are skewed, do not match.
+type durationRange struct {
+	min, max time.Duration
+}
+
+func newDurationRange(min, max time.Duration) (durationRange, error) {
+	if min < 0 || max < 0 {
+		return durationRange{}, fmt.Errorf("duration bounds must not be negative")
+	}
+	if max > 0 && min > max {
+		return durationRange{}, fmt.Errorf("the minimum duration (%s) exceeds the maximum duration (%s)", min, max)
+	}
+	return durationRange{min: min, max: max}, nil
+}
+
+func (r durationRange) matches(s RunSummary) bool {
+	if r.min == 0 && r.max == 0 {
+		return true
+	}
+	d, ok := s.Duration()
+	if !ok || s.ClockSkew {
+		return false
+	}
+	return d >= r.min && (r.max == 0 || d <= r.max)
+}
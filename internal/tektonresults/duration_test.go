@@ -0,0 +1,104 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestElapsed(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name   string
+		end    time.Time
+		want   time.Duration
+		skewed bool
+	}{
+		{"forward", start.Add(90 * time.Second), 90 * time.Second, false},
+		{"sub-second", start.Add(250 * time.Millisecond), 250 * time.Millisecond, false},
+		{"equal", start, 0, false},
+		{"rounding", start.Add(-time.Second), 0, false},
+		{"skewed", start.Add(-5 * time.Second), 0, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, skewed := Elapsed(start, tt.end)
+			if d != tt.want || skewed != tt.skewed {
+				t.Errorf("Elapsed() = %v, skewed=%v; want %v, skewed=%v", d, skewed, tt.want, tt.skewed)
+			}
+		})
+	}
+}
+
+func TestSummarizeRun_Duration(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		status string
+		want   time.Duration
+		ok     bool
+		skewed bool
+	}{
+		{"completed", `{"startTime":"2025-01-01T10:00:00Z","completionTime":"2025-01-01T10:04:30Z"}`, 4*time.Minute + 30*time.Second, true, false},
+		{"fractional seconds", `{"startTime":"2025-01-01T10:00:00.250Z","completionTime":"2025-01-01T10:00:01.000Z"}`, 750 * time.Millisecond, true, false},
+		{"running", `{"startTime":"2025-01-01T10:00:00Z"}`, 0, false, false},
+		{"completion before start", `{"startTime":"2025-01-01T10:00:30Z","completionTime":"2025-01-01T10:00:00Z"}`, 0, true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := record{Name: "ci/results/r1/records/r1", Uid: "r1"}
+			rec.Data.Value = json.RawMessage(`{"metadata":{"name":"pr","namespace":"ci"},"status":` + tt.status + `}`)
+			run, err := decodeRun(rec)
+			if err != nil {
+				t.Fatalf("decodeRun() error = %v", err)
+			}
+			summary := summarizeRun(run, rec)
+			d, ok := summary.Duration()
+			if d != tt.want || ok != tt.ok || summary.ClockSkew != tt.skewed {
+				t.Errorf("Duration() = %v, %v with clockSkew=%v; want %v, %v with clockSkew=%v", d, ok, summary.ClockSkew, tt.want, tt.ok, tt.skewed)
+			}
+		})
+	}
+}
+
+func TestRunDetail_Steps_ClockSkew(t *testing.T) {
+	raw := `{"kind":"TaskRun","status":{"steps":[
+		{"name":"build","terminated":{"exitCode":0,"startedAt":"2025-01-01T10:00:30Z","finishedAt":"2025-01-01T10:00:00Z"}}
+	]}}`
+	steps := RunDetail{Raw: json.RawMessage(raw)}.Steps()
+	if len(steps) != 1 || steps[0].Duration != 0 || !steps[0].ClockSkew {
+		t.Errorf("Expected a skewed step with zero duration, got %+v", steps)
+	}
+}
+
+func TestDurationRange(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	run := func(d time.Duration) RunSummary {
+		end := metav1.NewTime(start.Add(d))
+		return RunSummary{StartTime: &start, CompletionTime: &end}
+	}
+	r, err := newDurationRange(30*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("newDurationRange() error = %v", err)
+	}
+	for _, tt := range []struct {
+		run  RunSummary
+		want bool
+	}{
+		{run(10 * time.Minute), false},
+		{run(30 * time.Minute), true},
+		{run(time.Hour), true},
+		{run(2 * time.Hour), false},
+		{RunSummary{StartTime: &start}, false}, // still running
+	} {
+		if got := r.matches(tt.run); got != tt.want {
+			d, _ := tt.run.Duration()
+			t.Errorf("matches(%s) = %v, want %v", d, got, tt.want)
+		}
+	}
+	if !(durationRange{}).matches(RunSummary{}) {
+		t.Error("Expected the empty range to match every run")
+	}
+	if _, err := newDurationRange(time.Hour, time.Minute); err == nil {
+		t.Error("Expected a minimum above the maximum to be rejected")
+	}
+}
@@ -0,0 +1,191 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultConfig describes the synthetic failures injected in front of the
+// Tekton Results API. It is meant for tests and chaos experiments only.
+type FaultConfig struct {
+	MaxLatency    time.Duration // upper bound of the random delay added to every call
+	ErrorRate     float64       // probability that a call fails with a 5xx-style error
+	PartialRate   float64       // probability that a list page is truncated
+	MalformedRate float64       // probability that a returned record is corrupted
+	Seed          uint64        // seed for the random source; 0 picks a time based seed
+}
+
+// Enabled reports whether the configuration injects any fault at all.
+func (c FaultConfig) Enabled() bool {
+	return c.MaxLatency > 0 || c.ErrorRate > 0 || c.PartialRate > 0 || c.MalformedRate > 0
+}
+
+// ParseFaultConfig parses a comma separated key=value specification such as
+// "latency=200ms,errors=0.1,partial=0.2,malformed=0.05,seed=42".
+func ParseFaultConfig(spec string) (FaultConfig, error) {
+	var cfg FaultConfig
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return FaultConfig{}, fmt.Errorf("invalid fault spec %q: expected key=value pairs", pair)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "latency":
+			cfg.MaxLatency, err = time.ParseDuration(value)
+		case "errors":
+			cfg.ErrorRate, err = parseRate(value)
+		case "partial":
+			cfg.PartialRate, err = parseRate(value)
+		case "malformed":
+			cfg.MalformedRate, err = parseRate(value)
+		case "seed":
+			cfg.Seed, err = strconv.ParseUint(value, 10, 64)
+		default:
+			return FaultConfig{}, fmt.Errorf("invalid fault spec %q: unknown key %q", pair, key)
+		}
+		if err != nil {
+			return FaultConfig{}, fmt.Errorf("invalid fault spec %q: %w", pair, err)
+		}
+	}
+	return cfg, nil
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate must be between 0 and 1")
+	}
+	return rate, nil
+}
+
+// faultInjectingClient decorates a resultsClient with random latency, upstream
+// errors, truncated pages and corrupted records.
+type faultInjectingClient struct {
+	next resultsClient
+	cfg  FaultConfig
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newFaultInjectingClient(next resultsClient, cfg FaultConfig) *faultInjectingClient {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	return &faultInjectingClient{
+		next: next,
+		cfg:  cfg,
+		rnd:  rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+func (f *faultInjectingClient) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rnd.Float64() < rate
+}
+
+// before applies the latency and error faults shared by every call.
+func (f *faultInjectingClient) before(ctx context.Context, op string) error {
+	if f.cfg.MaxLatency > 0 {
+		f.mu.Lock()
+		delay := time.Duration(f.rnd.Int64N(int64(f.cfg.MaxLatency) + 1))
+		f.mu.Unlock()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if f.chance(f.cfg.ErrorRate) {
+		return fmt.Errorf(`results API %s: {"code":14,"message":"injected fault: service unavailable"}`, op)
+	}
+	return nil
+}
+
+func (f *faultInjectingClient) corrupt(rec record) record {
+	if f.chance(f.cfg.MalformedRate) {
+		rec.Data.Value = json.RawMessage(`{"metadata":"malformed","status":[]}`)
+		rec.Data.valueDecoded = nil
+	}
+	return rec
+}
+
+func (f *faultInjectingClient) getRecord(ctx context.Context, recordName string) (*record, error) {
+	if err := f.before(ctx, "getRecord"); err != nil {
+		return nil, err
+	}
+	rec, err := f.next.getRecord(ctx, recordName)
+	if err != nil || rec == nil {
+		return rec, err
+	}
+	corrupted := f.corrupt(*rec)
+	return &corrupted, nil
+}
+
+func (f *faultInjectingClient) listResults(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+	if err := f.before(ctx, "listResults"); err != nil {
+		return nil, err
+	}
+	resp, err := f.next.listResults(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if len(resp.Results) > 1 && f.chance(f.cfg.PartialRate) {
+		resp.Results = resp.Results[:len(resp.Results)/2]
+	}
+	return resp, nil
+}
+
+func (f *faultInjectingClient) listRecords(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+	if err := f.before(ctx, "listRecords"); err != nil {
+		return nil, err
+	}
+	resp, err := f.next.listRecords(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if len(resp.Records) > 1 && f.chance(f.cfg.PartialRate) {
+		resp.Records = resp.Records[:len(resp.Records)/2]
+	}
+	for i := range resp.Records {
+		resp.Records[i] = f.corrupt(resp.Records[i])
+	}
+	return resp, nil
+}
+
+func (f *faultInjectingClient) getLog(ctx context.Context, logPath string) ([]byte, error) {
+	if err := f.before(ctx, "getLog"); err != nil {
+		return nil, err
+	}
+	return f.next.getLog(ctx, logPath)
+}
+
+func (f *faultInjectingClient) deleteResult(ctx context.Context, resultName string) error {
+	if err := f.before(ctx, "deleteResult"); err != nil {
+		return err
+	}
+	return f.next.deleteResult(ctx, resultName)
+}
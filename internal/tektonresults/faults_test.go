@@ -0,0 +1,126 @@
+package tektonresults
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFaultConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    FaultConfig
+		wantErr bool
+	}{
+		{
+			name: "empty spec",
+			spec: "",
+			want: FaultConfig{},
+		},
+		{
+			name: "all keys",
+			spec: "latency=200ms, errors=0.1,partial=0.2,malformed=0.05,seed=42",
+			want: FaultConfig{
+				MaxLatency:    200 * time.Millisecond,
+				ErrorRate:     0.1,
+				PartialRate:   0.2,
+				MalformedRate: 0.05,
+				Seed:          42,
+			},
+		},
+		{
+			name:    "unknown key",
+			spec:    "explode=1",
+			wantErr: true,
+		},
+		{
+			name:    "rate out of range",
+			spec:    "errors=1.5",
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			spec:    "latency",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFaultConfig(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFaultConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseFaultConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaultInjectingClient_UpstreamErrors(t *testing.T) {
+	inner := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			t.Error("inner client should not be called when the fault fires")
+			return nil, nil
+		},
+	}
+	service := &Service{client: newFaultInjectingClient(inner, FaultConfig{ErrorRate: 1, Seed: 1})}
+
+	_, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo"})
+	if err == nil {
+		t.Fatal("Expected injected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "injected fault") {
+		t.Errorf("Expected injected fault error, got: %v", err)
+	}
+}
+
+func TestFaultInjectingClient_PartialPages(t *testing.T) {
+	inner := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords("foo", make([]testRun, 4)...)}, nil
+		},
+	}
+	service := &Service{client: newFaultInjectingClient(inner, FaultConfig{PartialRate: 1, Seed: 1})}
+
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", Limit: 10})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() failed: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Errorf("Expected truncated page of 2 runs, got %d", len(summaries))
+	}
+}
+
+func TestFaultInjectingClient_MalformedRecords(t *testing.T) {
+	inner := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords("foo", make([]testRun, 1)...)}, nil
+		},
+	}
+	service := &Service{client: newFaultInjectingClient(inner, FaultConfig{MalformedRate: 1, Seed: 1})}
+
+	_, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo"})
+	if err == nil {
+		t.Fatal("Expected decode error for malformed record, got nil")
+	}
+	if !strings.Contains(err.Error(), "decode Tekton resource") {
+		t.Errorf("Expected decode error, got: %v", err)
+	}
+}
+
+func TestFaultInjectingClient_LatencyHonorsContext(t *testing.T) {
+	inner := &mockRestClient{}
+	client := newFaultInjectingClient(inner, FaultConfig{MaxLatency: time.Hour, Seed: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.getLog(ctx, "foo/results/r/logs/r")
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
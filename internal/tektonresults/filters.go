@@ -2,11 +2,42 @@ package tektonresults
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-func parseLabelSelector(selector string) (map[string]string, error) {
-	result := make(map[string]string)
+// labelSelector is a parsed label or annotation selector. Equality clauses
+// are pushed into the CEL filter; negative clauses are only evaluated in
+// memory.
+type labelSelector struct {
+	equals    map[string]string // key=value
+	notEquals map[string]string // key!=value
+	absent    []string          // !key
+}
+
+func parseLabelSelector(selector string) (labelSelector, error) {
+	return parseSelector("label", selector)
+}
+
+// parseAnnotationSelector parses an annotation selector, which has the
+// syntax of a label selector. Values cannot contain commas.
+func parseAnnotationSelector(selector string) (labelSelector, error) {
+	return parseSelector("annotation", selector)
+}
+
+// parseSelector parses comma separated key=value, key!=value and !key
+// clauses; what names the selector in errors.
+func parseSelector(what, selector string) (labelSelector, error) {
+	result := labelSelector{
+		equals:    make(map[string]string),
+		notEquals: make(map[string]string),
+	}
 	if strings.TrimSpace(selector) == "" {
 		return result, nil
 	}
@@ -16,54 +47,337 @@ func parseLabelSelector(selector string) (map[string]string, error) {
 		if pair == "" {
 			continue
 		}
-		parts := strings.SplitN(pair, "=", 2)
+		if key, ok := strings.CutPrefix(pair, "!"); ok {
+			key = strings.TrimSpace(key)
+			if key == "" || strings.ContainsAny(key, "=!") {
+				return labelSelector{}, fmt.Errorf("invalid %s selector %q: expected !key", what, pair)
+			}
+			result.absent = append(result.absent, key)
+			continue
+		}
+		target := result.equals
+		parts := strings.SplitN(pair, "!=", 2)
+		if len(parts) == 2 {
+			target = result.notEquals
+		} else {
+			parts = strings.SplitN(pair, "=", 2)
+		}
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid label selector %q: expected key=value pairs", pair)
+			return labelSelector{}, fmt.Errorf("invalid %s selector %q: expected key=value, key!=value or !key", what, pair)
 		}
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 		if key == "" || value == "" {
-			return nil, fmt.Errorf("invalid label selector %q: empty key or value", pair)
+			return labelSelector{}, fmt.Errorf("invalid %s selector %q: empty key or value", what, pair)
 		}
-		result[key] = value
+		target[key] = value
 	}
 	return result, nil
 }
 
-func matchesLabels(actual map[string]string, expected map[string]string) bool {
-	if len(expected) == 0 {
-		return true
+// empty reports whether the selector has no clauses and so matches every run.
+func (s labelSelector) empty() bool {
+	return len(s.equals) == 0 && len(s.notEquals) == 0 && len(s.absent) == 0
+}
+
+// excludes reports whether the selector has clauses the Results API cannot
+// apply, which are only checked in memory.
+func (s labelSelector) excludes() bool {
+	return len(s.notEquals) > 0 || len(s.absent) > 0
+}
+
+// matchesLabels reports whether the labels or annotations in actual satisfy
+// the selector.
+func matchesLabels(actual map[string]string, expected labelSelector) bool {
+	for key, want := range expected.equals {
+		if actual[key] != want {
+			return false
+		}
 	}
-	for key, want := range expected {
-		if actual == nil {
+	// A missing label satisfies key!=value, as in Kubernetes selectors.
+	for key, unwanted := range expected.notEquals {
+		if value, ok := actual[key]; ok && value == unwanted {
 			return false
 		}
-		if actual[key] != want {
+	}
+	for _, key := range expected.absent {
+		if _, ok := actual[key]; ok {
 			return false
 		}
 	}
 	return true
 }
 
-func buildFilterExpression(kind resourceKind, labels map[string]string, exactName string, uid string) string {
-	var parts []string
+// maxRawFilterLength bounds caller supplied CEL snippets.
+const maxRawFilterLength = 2048
+
+// filterBuilder assembles the CEL filter sent to ListRecords. Every value that
+// originates from a caller is validated before it is quoted into the
+// expression; the first error is kept and returned by build.
+type filterBuilder struct {
+	parts []string
+	err   error
+}
+
+// newFilterBuilder starts a filter restricted to the record types of kind.
+func newFilterBuilder(kind resourceKind) *filterBuilder {
+	b := &filterBuilder{}
 	if types, ok := resourceTypeFilters[kind]; ok && len(types) > 0 {
 		var clauses []string
 		for _, t := range types {
-			clauses = append(clauses, fmt.Sprintf(`data_type=="%s"`, escapeCELString(t)))
+			clauses = append(clauses, fmt.Sprintf(`data_type==%s`, quoteCEL(t)))
+		}
+		b.parts = append(b.parts, fmt.Sprintf("(%s)", strings.Join(clauses, " || ")))
+	}
+	return b
+}
+
+// labels adds one equality clause per label, in key order so the resulting
+// filter is stable.
+func (b *filterBuilder) labels(labels map[string]string) *filterBuilder {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := labels[key]
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			b.fail(fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; ")))
+			continue
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			b.fail(fmt.Errorf("invalid value %q for label %s: %s", value, key, strings.Join(errs, "; ")))
+			continue
+		}
+		b.parts = append(b.parts, fmt.Sprintf(`data.metadata.labels[%s]==%s`, quoteCEL(key), quoteCEL(value)))
+	}
+	return b
+}
+
+// annotations adds one equality clause per annotation, in key order.
+// Annotation values are free text, so they are only checked for control
+// characters and length.
+func (b *filterBuilder) annotations(annotations map[string]string) *filterBuilder {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := annotations[key]
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			b.fail(fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, "; ")))
+			continue
+		}
+		if err := checkFilterText("annotation "+key, value); err != nil {
+			b.fail(err)
+			continue
+		}
+		if len(value) > maxRawFilterLength {
+			b.fail(fmt.Errorf("value of annotation %s is longer than %d characters", key, maxRawFilterLength))
+			continue
+		}
+		b.parts = append(b.parts, fmt.Sprintf(`data.metadata.annotations[%s]==%s`, quoteCEL(key), quoteCEL(value)))
+	}
+	return b
+}
+
+// anyOf adds a clause matching runs that carry all equality labels of at
+// least one selector. A selector without equality clauses cannot be narrowed
+// in CEL, so nothing is added and the caller filters in memory.
+func (b *filterBuilder) anyOf(selectors []labelSelector) *filterBuilder {
+	alternatives := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		if len(selector.equals) == 0 {
+			return b
+		}
+		sub := (&filterBuilder{}).labels(selector.equals)
+		if sub.err != nil {
+			b.fail(sub.err)
+			return b
+		}
+		alternatives = append(alternatives, "("+strings.Join(sub.parts, " && ")+")")
+	}
+	if len(alternatives) > 0 {
+		b.parts = append(b.parts, "("+strings.Join(alternatives, " || ")+")")
+	}
+	return b
+}
+
+// name adds an exact run name clause. An empty name adds nothing.
+func (b *filterBuilder) name(name string) *filterBuilder {
+	if name == "" {
+		return b
+	}
+	if err := checkFilterText("name", name); err != nil {
+		b.fail(err)
+		return b
+	}
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		b.fail(fmt.Errorf("name %q is longer than %d characters", name, validation.DNS1123SubdomainMaxLength))
+		return b
+	}
+	b.parts = append(b.parts, fmt.Sprintf(`data.metadata.name==%s`, quoteCEL(name)))
+	return b
+}
+
+// ref adds a clause matching runs of kind that reference the Pipeline or
+// Task name, or carry it in the label Tekton sets for runs without a
+// reference name. An empty name adds nothing.
+func (b *filterBuilder) ref(kind resourceKind, name string) *filterBuilder {
+	if name == "" {
+		return b
+	}
+	if err := checkFilterText("pipeline or task name", name); err != nil {
+		b.fail(err)
+		return b
+	}
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		b.fail(fmt.Errorf("pipeline or task name %q is longer than %d characters", name, validation.DNS1123SubdomainMaxLength))
+		return b
+	}
+	field, label := "data.spec.pipelineRef.name", pipelineLabel
+	if kind == resourceKindTaskRun {
+		field, label = "data.spec.taskRef.name", taskLabel
+	}
+	b.parts = append(b.parts, fmt.Sprintf(`(%s==%s || data.metadata.labels[%s]==%s)`, field, quoteCEL(name), quoteCEL(label), quoteCEL(name)))
+	return b
+}
+
+// statuses adds a clause matching runs with any of the statuses. An empty
+// filter adds nothing.
+func (b *filterBuilder) statuses(f statusFilter) *filterBuilder {
+	if len(f) == 0 {
+		return b
+	}
+	clauses := make([]string, len(f))
+	for i, status := range f {
+		clauses[i] = statusClause(status)
+	}
+	if len(clauses) == 1 {
+		b.parts = append(b.parts, clauses[0])
+	} else {
+		b.parts = append(b.parts, "("+strings.Join(clauses, " || ")+")")
+	}
+	return b
+}
+
+// createdSince matches records created at or after t.
+func (b *filterBuilder) createdSince(t time.Time) *filterBuilder {
+	if t.IsZero() {
+		return b
+	}
+	b.parts = append(b.parts, fmt.Sprintf("create_time>=timestamp(%s)", quoteCEL(t.UTC().Format(time.RFC3339Nano))))
+	return b
+}
+
+// createdBefore matches records created before t.
+func (b *filterBuilder) createdBefore(t time.Time) *filterBuilder {
+	if t.IsZero() {
+		return b
+	}
+	b.parts = append(b.parts, fmt.Sprintf("create_time<timestamp(%s)", quoteCEL(t.UTC().Format(time.RFC3339Nano))))
+	return b
+}
+
+// raw adds a caller supplied CEL snippet, wrapped in parentheses so it cannot
+// change the meaning of the surrounding clauses. The snippet must be free of
+// control characters and have terminated string literals and balanced
+// brackets.
+func (b *filterBuilder) raw(snippet string) *filterBuilder {
+	snippet = strings.TrimSpace(snippet)
+	if snippet == "" {
+		return b
+	}
+	if len(snippet) > maxRawFilterLength {
+		b.fail(fmt.Errorf("filter is longer than %d characters", maxRawFilterLength))
+		return b
+	}
+	if err := checkFilterText("filter", snippet); err != nil {
+		b.fail(err)
+		return b
+	}
+	if err := checkBalanced(snippet); err != nil {
+		b.fail(fmt.Errorf("invalid filter %q: %w", snippet, err))
+		return b
+	}
+	b.parts = append(b.parts, fmt.Sprintf("(%s)", snippet))
+	return b
+}
+
+// build returns the filter expression, or the first validation error.
+func (b *filterBuilder) build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return strings.Join(b.parts, " && "), nil
+}
+
+func (b *filterBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// checkFilterText rejects control characters and invalid UTF-8, which have no
+// business in names or filters and could otherwise smuggle line breaks into
+// the query.
+func checkFilterText(what, value string) error {
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("%s contains invalid UTF-8", what)
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%s must not contain control characters", what)
+		}
+	}
+	return nil
+}
+
+// checkBalanced verifies that string literals are terminated and brackets are
+// balanced outside of them.
+func checkBalanced(expr string) error {
+	var stack []rune
+	closing := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	var quote rune
+	escaped := false
+	for _, r := range expr {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch r {
+		case '"', '\'':
+			quote = r
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closing[r] {
+				return fmt.Errorf("unbalanced %q", r)
+			}
+			stack = stack[:len(stack)-1]
 		}
-		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(clauses, " || ")))
 	}
-	// Note: UID filtering is not supported in CEL filter expressions for Records.
-	// UID filtering is handled in-memory after fetching records.
-	// The uid parameter is kept for API consistency but not used in the CEL expression.
-	for key, value := range labels {
-		parts = append(parts, fmt.Sprintf(`data.metadata.labels["%s"]=="%s"`, escapeCELString(key), escapeCELString(value)))
+	if quote != 0 {
+		return fmt.Errorf("unterminated string literal")
 	}
-	if exactName != "" {
-		parts = append(parts, fmt.Sprintf(`data.metadata.name=="%s"`, escapeCELString(exactName)))
+	if len(stack) > 0 {
+		return fmt.Errorf("unclosed %q", stack[len(stack)-1])
 	}
-	return strings.Join(parts, " && ")
+	return nil
+}
+
+// quoteCEL renders in as a double quoted CEL string literal.
+func quoteCEL(in string) string {
+	return `"` + escapeCELString(in) + `"`
 }
 
 func escapeCELString(in string) string {
@@ -72,6 +386,32 @@ func escapeCELString(in string) string {
 	return in
 }
 
+// maxNameRegex bounds the length of name patterns. Go regular expressions
+// match in linear time, so the bound only keeps compilation cheap.
+const maxNameRegex = 512
+
+// compileNameRegex compiles a run name pattern. It is unanchored, like
+// regexp.MatchString; the empty pattern compiles to nil and matches every
+// name.
+func compileNameRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	if len(expr) > maxNameRegex {
+		return nil, fmt.Errorf("invalid nameRegex: longer than %d characters", maxNameRegex)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nameRegex %q: %w", expr, err)
+	}
+	return re, nil
+}
+
+// matchesName reports whether name matches re; a nil re matches any name.
+func matchesName(re *regexp.Regexp, name string) bool {
+	return re == nil || re.MatchString(name)
+}
+
 func parentForNamespace(ns string) string {
 	ns = strings.TrimSpace(ns)
 	switch strings.ToLower(ns) {
@@ -81,3 +421,32 @@ func parentForNamespace(ns string) string {
 		return fmt.Sprintf("%s/results/-", ns)
 	}
 }
+
+// MaxNamespaces is the most namespaces a comma separated list may name. Each
+// is queried on its own, so longer lists should search all namespaces ('-')
+// with a labelSelector instead.
+const MaxNamespaces = 20
+
+// splitNamespaces splits a comma separated namespace list, dropping blanks and
+// duplicates. A list that mentions "all namespaces" collapses to "-". Lists
+// of more than MaxNamespaces namespaces are rejected.
+func splitNamespaces(ns string) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, part := range strings.Split(ns, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || seen[part] {
+			continue
+		}
+		switch strings.ToLower(part) {
+		case "-", "all", "*":
+			return []string{"-"}, nil
+		}
+		seen[part] = true
+		out = append(out, part)
+	}
+	if len(out) > MaxNamespaces {
+		return nil, fmt.Errorf("namespace lists are limited to %d namespaces, got %d; use '-' for all namespaces with a labelSelector instead", MaxNamespaces, len(out))
+	}
+	return out, nil
+}
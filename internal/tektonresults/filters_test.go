@@ -0,0 +1,249 @@
+package tektonresults
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitNamespaces(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"ci", []string{"ci"}},
+		{" ci , staging,,ci ", []string{"ci", "staging"}},
+		{"ci,all", []string{"-"}},
+		{"-", []string{"-"}},
+	}
+	for _, tt := range tests {
+		if got, err := splitNamespaces(tt.in); err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitNamespaces(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	long := make([]string, MaxNamespaces+1)
+	for i := range long {
+		long[i] = fmt.Sprintf("ns-%d", i)
+	}
+	if _, err := splitNamespaces(strings.Join(long, ",")); err == nil || !strings.Contains(err.Error(), "limited to") {
+		t.Errorf("Expected a list of %d namespaces to be rejected, got %v", len(long), err)
+	}
+	if got, err := splitNamespaces(strings.Join(long[:MaxNamespaces], ",")); err != nil || len(got) != MaxNamespaces {
+		t.Errorf("Expected %d namespaces to be accepted, got %d, %v", MaxNamespaces, len(got), err)
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	sel, err := parseLabelSelector("app=web, env!=dogfood, !experimental")
+	if err != nil {
+		t.Fatalf("parseLabelSelector() error = %v", err)
+	}
+	if !reflect.DeepEqual(sel.equals, map[string]string{"app": "web"}) {
+		t.Errorf("Unexpected equals: %v", sel.equals)
+	}
+	if !reflect.DeepEqual(sel.notEquals, map[string]string{"env": "dogfood"}) {
+		t.Errorf("Unexpected notEquals: %v", sel.notEquals)
+	}
+	if !reflect.DeepEqual(sel.absent, []string{"experimental"}) {
+		t.Errorf("Unexpected absent: %v", sel.absent)
+	}
+
+	for _, invalid := range []string{"app", "app=", "=web", "env!=", "!", "!a=b"} {
+		if _, err := parseLabelSelector(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestMatchesLabels(t *testing.T) {
+	sel, err := parseLabelSelector("app=web,env!=dogfood,!experimental")
+	if err != nil {
+		t.Fatalf("parseLabelSelector() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"matches", map[string]string{"app": "web", "env": "prod"}, true},
+		{"missing negated key matches", map[string]string{"app": "web"}, true},
+		{"negated value", map[string]string{"app": "web", "env": "dogfood"}, false},
+		{"forbidden key present", map[string]string{"app": "web", "experimental": "true"}, false},
+		{"equality mismatch", map[string]string{"app": "api"}, false},
+		{"nil labels", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesLabels(tt.labels, sel); got != tt.want {
+				t.Errorf("matchesLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterBuilder(t *testing.T) {
+	filter, err := newFilterBuilder(resourceKindPipelineRun).
+		labels(map[string]string{"tekton.dev/pipeline": "build", "app": "web"}).
+		name(`run-"1"`).
+		build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	want := `(data_type=="tekton.dev/v1.PipelineRun" || data_type=="tekton.dev/v1beta1.PipelineRun")` +
+		` && data.metadata.labels["app"]=="web"` +
+		` && data.metadata.labels["tekton.dev/pipeline"]=="build"` +
+		` && data.metadata.name=="run-\"1\""`
+	if filter != want {
+		t.Errorf("build() =\n%s\nwant\n%s", filter, want)
+	}
+}
+
+func TestFilterBuilder_Annotations(t *testing.T) {
+	filter, err := newFilterBuilder(resourceKindPipelineRun).
+		annotations(map[string]string{"pipelinesascode.tekton.dev/repo-url": `https://github.com/org/"repo"`}).
+		build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	if !strings.HasSuffix(filter, ` && data.metadata.annotations["pipelinesascode.tekton.dev/repo-url"]=="https://github.com/org/\"repo\""`) {
+		t.Errorf("Expected an escaped annotation clause, got %s", filter)
+	}
+}
+
+func TestFilterBuilder_Ref(t *testing.T) {
+	filter, err := newFilterBuilder(resourceKindTaskRun).ref(resourceKindTaskRun, "git-clone").build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	if !strings.HasSuffix(filter, ` && (data.spec.taskRef.name=="git-clone" || data.metadata.labels["tekton.dev/task"]=="git-clone")`) {
+		t.Errorf("Expected a taskRef clause falling back to the label, got %s", filter)
+	}
+}
+
+func TestFilterBuilder_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *filterBuilder
+	}{
+		{"invalid label key", newFilterBuilder(resourceKindTaskRun).labels(map[string]string{`bad"key`: "v"})},
+		{"invalid label value", newFilterBuilder(resourceKindTaskRun).labels(map[string]string{"app": `x") || true || ("`})},
+		{"invalid annotation key", newFilterBuilder(resourceKindTaskRun).annotations(map[string]string{"a b": "v"})},
+		{"control character in annotation", newFilterBuilder(resourceKindTaskRun).annotations(map[string]string{"note": "x\n&& true"})},
+		{"control character in name", newFilterBuilder(resourceKindTaskRun).name("run\n&& true")},
+		{"invalid utf-8 in name", newFilterBuilder(resourceKindTaskRun).name("run\xff")},
+		{"overlong name", newFilterBuilder(resourceKindTaskRun).name(strings.Repeat("a", 254))},
+		{"control character in ref", newFilterBuilder(resourceKindTaskRun).ref(resourceKindTaskRun, "clone\n|| true")},
+		{"overlong ref", newFilterBuilder(resourceKindPipelineRun).ref(resourceKindPipelineRun, strings.Repeat("a", 254))},
+		{"unterminated string", newFilterBuilder(resourceKindTaskRun).raw(`data.metadata.name=="x`)},
+		{"unbalanced parenthesis", newFilterBuilder(resourceKindTaskRun).raw(`true) || (true`)},
+		{"overlong raw filter", newFilterBuilder(resourceKindTaskRun).raw(strings.Repeat("a", maxRawFilterLength+1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if filter, err := tt.b.build(); err == nil {
+				t.Errorf("Expected error, got filter %s", filter)
+			}
+		})
+	}
+}
+
+func TestFilterBuilder_Raw(t *testing.T) {
+	filter, err := newFilterBuilder(resourceKindTaskRun).raw(` data.status.conditions[0].reason=="Failed (OOM)" `).build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	if !strings.HasSuffix(filter, ` && (data.status.conditions[0].reason=="Failed (OOM)")`) {
+		t.Errorf("Expected raw snippet to be parenthesized, got %s", filter)
+	}
+}
+
+func TestFilterBuilder_CreatedSince(t *testing.T) {
+	since := time.Date(2025, 1, 1, 10, 0, 0, 500, time.FixedZone("CET", 3600))
+	filter, err := newFilterBuilder(resourceKindTaskRun).createdSince(since).build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	if !strings.HasSuffix(filter, ` && create_time>=timestamp("2025-01-01T09:00:00.0000005Z")`) {
+		t.Errorf("Expected UTC create_time bound, got %s", filter)
+	}
+
+	unbounded, _ := newFilterBuilder(resourceKindTaskRun).createdSince(time.Time{}).build()
+	if strings.Contains(unbounded, "create_time") {
+		t.Errorf("Expected zero time to add no clause, got %s", unbounded)
+	}
+}
+
+func TestFilterBuilder_CreatedRange(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter, err := newFilterBuilder(resourceKindPipelineRun).createdSince(from).createdBefore(from.Add(24 * time.Hour)).build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	if !strings.HasSuffix(filter, ` && create_time>=timestamp("2025-01-01T00:00:00Z") && create_time<timestamp("2025-01-02T00:00:00Z")`) {
+		t.Errorf("Expected both create_time bounds, got %s", filter)
+	}
+}
+
+// unquoteCEL reverses quoteCEL, failing on any unescaped quote inside the
+// literal.
+func unquoteCEL(t *testing.T, literal string) string {
+	t.Helper()
+	if len(literal) < 2 || literal[0] != '"' || literal[len(literal)-1] != '"' {
+		t.Fatalf("not a quoted literal: %q", literal)
+	}
+	var out strings.Builder
+	body := literal[1 : len(literal)-1]
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+			if i == len(body) {
+				t.Fatalf("dangling escape in %q", literal)
+			}
+			out.WriteByte(body[i])
+		case '"':
+			t.Fatalf("unescaped quote in %q", literal)
+		default:
+			out.WriteByte(body[i])
+		}
+	}
+	return out.String()
+}
+
+func FuzzEscapeCELString(f *testing.F) {
+	for _, seed := range []string{"", "plain", `a"b`, `a\b`, `\"`, `"); true || ("`, `\\\"`} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		if got := unquoteCEL(t, quoteCEL(in)); got != in {
+			t.Errorf("round trip of %q produced %q", in, got)
+		}
+	})
+}
+
+func FuzzFilterBuilderName(f *testing.F) {
+	for _, seed := range []string{"run-1", `x" || true`, "a\nb", "\x00", "ünïcode"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		filter, err := newFilterBuilder(resourceKindPipelineRun).name(name).build()
+		if err != nil {
+			return
+		}
+		if name == "" {
+			return
+		}
+		clause := filter[strings.LastIndex(filter, " && ")+len(" && "):]
+		literal, ok := strings.CutPrefix(clause, "data.metadata.name==")
+		if !ok {
+			t.Fatalf("unexpected name clause %q", clause)
+		}
+		if got := unquoteCEL(t, literal); got != name {
+			t.Errorf("name %q rendered as %q", name, got)
+		}
+	})
+}
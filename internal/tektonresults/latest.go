@@ -0,0 +1,43 @@
+package tektonresults
+
+import (
+	"cmp"
+	"time"
+)
+
+// latestRuns collapses the runs of one listing that share a name to the most
+// recent one. Runs created from a generateName, such as those of triggers
+// and the tkn CLI, are grouped by the generateName prefix instead, since
+// each gets a new random suffix.
+type latestRuns map[string]int
+
+// collapse reports whether run repeats a name already on page. The run
+// replaces the listed one if it started later, carrying over the count of
+// the duplicates left out.
+func (l latestRuns) collapse(page *RunPage, run RunSummary) bool {
+	key := run.Namespace + "/" + cmp.Or(run.GenerateName, run.Name)
+	i, ok := l[key]
+	if !ok {
+		l[key] = len(page.Runs)
+		return false
+	}
+	kept := &page.Runs[i]
+	if runTime(run).After(runTime(*kept)) {
+		run.Duplicates = kept.Duplicates
+		*kept = run
+	}
+	kept.Duplicates++
+	return true
+}
+
+// runTime is when a run started, or completed if it never started. Pending
+// runs count as the most recent.
+func runTime(run RunSummary) time.Time {
+	switch {
+	case run.StartTime != nil:
+		return run.StartTime.Time
+	case run.CompletionTime != nil:
+		return run.CompletionTime.Time
+	}
+	return time.Unix(1<<62, 0)
+}
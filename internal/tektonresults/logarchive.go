@@ -0,0 +1,137 @@
+package tektonresults
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+)
+
+// ErrNotArchived is returned by LogArchive.FetchLogs when the archive holds
+// no log for the record.
+var ErrNotArchived = errors.New("no archived log for this record")
+
+// logArchiveExtensions are the file name suffixes tried for an archived log.
+// The content, not the suffix, decides how a file is decompressed.
+var logArchiveExtensions = []string{"", ".log", ".txt", ".zst", ".log.zst", ".gz", ".log.gz"}
+
+var (
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// LogArchive reads logs from a directory of exported Results logs, so runs
+// can still be investigated after their cluster or Results instance is
+// gone. The log of the record <namespace>/results/<result>/records/<record>
+// is looked up, in this order, as:
+//
+//	<dir>/<namespace>/results/<result>/logs/<record>  (the Results log name)
+//	<dir>/<namespace>/<result>/<record>               (Results file log storage)
+//	<dir>/<record>                                    (flat exports)
+//
+// each optionally ending in .log, .txt, .zst or .gz. Files may be plain
+// text or compressed with zstd or gzip.
+type LogArchive struct {
+	dir      string
+	maxBytes int64
+}
+
+// OpenLogArchive returns the archive in dir. Logs larger than maxBytes once
+// decompressed are refused; zero uses DefaultMaxResponseBytes.
+func OpenLogArchive(dir string, maxBytes int64) (*LogArchive, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open log archive: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("open log archive: %s is not a directory", dir)
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxResponseBytes
+	}
+	return &LogArchive{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// FetchLogs returns the archived log of recordName, or ErrNotArchived.
+func (a *LogArchive) FetchLogs(_ context.Context, recordName string) (string, error) {
+	for _, candidate := range archivePaths(recordName) {
+		for _, ext := range logArchiveExtensions {
+			path := filepath.Join(append([]string{a.dir}, candidate...)...) + ext
+			text, err := a.read(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return text, err
+		}
+	}
+	return "", ErrNotArchived
+}
+
+// archivePaths returns the paths, as segments below the archive directory,
+// where the log of recordName may be. Names with segments that could leave
+// the directory have none.
+func archivePaths(recordName string) [][]string {
+	parts := nameSegments(strings.Trim(recordName, "/"))
+	if len(parts) == 5 && parts[1] == "results" && (parts[3] == "records" || parts[3] == "logs") {
+		namespace, result, record := parts[0], parts[2], parts[4]
+		return [][]string{
+			{namespace, "results", result, "logs", record},
+			{namespace, result, record},
+			{record},
+		}
+	}
+	if len(parts) == 1 {
+		return [][]string{parts}
+	}
+	return nil
+}
+
+func (a *LogArchive) read(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		return "", fs.ErrNotExist
+	}
+
+	buffered := bufio.NewReader(f)
+	head, _ := buffered.Peek(len(zstdMagic))
+	var r io.Reader = buffered
+	switch {
+	case bytes.HasPrefix(head, zstdMagic):
+		dec, err := zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return "", fmt.Errorf("read archived log %s: %w", path, err)
+		}
+		defer dec.Close()
+		r = dec
+	case bytes.HasPrefix(head, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return "", fmt.Errorf("read archived log %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	data, err := io.ReadAll(io.LimitReader(r, a.maxBytes+1))
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("read archived log %s: %w", path, err)
+	case int64(len(data)) > a.maxBytes:
+		return "", fmt.Errorf("archived log %s exceeds the %s limit", path, format.Bytes(a.maxBytes))
+	}
+	return string(data), nil
+}
@@ -0,0 +1,91 @@
+package tektonresults
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func writeArchived(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogArchive(t *testing.T) {
+	dir := t.TempDir()
+	writeArchived(t, filepath.Join(dir, "ci", "results", "r1", "logs", "plain"), []byte("plain log\n"))
+
+	enc, _ := zstd.NewWriter(nil)
+	writeArchived(t, filepath.Join(dir, "ci", "r1", "zst.log.zst"), enc.EncodeAll([]byte("zstd log\n"), nil))
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("gzip log\n"))
+	w.Close()
+	writeArchived(t, filepath.Join(dir, "flat.gz"), gz.Bytes())
+	writeArchived(t, filepath.Join(dir, "big"), bytes.Repeat([]byte("x"), 64))
+
+	archive, err := OpenLogArchive(dir, 32)
+	if err != nil {
+		t.Fatalf("OpenLogArchive() error = %v", err)
+	}
+	for record, want := range map[string]string{
+		"ci/results/r1/records/plain": "plain log\n",
+		"ci/results/r1/records/zst":   "zstd log\n",
+		"ci/results/r1/logs/flat":     "gzip log\n",
+		"flat":                        "gzip log\n",
+	} {
+		if got, err := archive.FetchLogs(context.Background(), record); err != nil || got != want {
+			t.Errorf("FetchLogs(%q) = %q, %v, want %q", record, got, err, want)
+		}
+	}
+	for _, record := range []string{"ci/results/r1/records/missing", "ci/results/../records/plain", "../etc/passwd", "ci/results/r1"} {
+		if _, err := archive.FetchLogs(context.Background(), record); !errors.Is(err, ErrNotArchived) {
+			t.Errorf("FetchLogs(%q) error = %v, want ErrNotArchived", record, err)
+		}
+	}
+	if _, err := archive.FetchLogs(context.Background(), "big"); err == nil || !strings.Contains(err.Error(), "exceeds the 32 B limit") {
+		t.Errorf("Expected a log over the limit to be refused, got %v", err)
+	}
+
+	if _, err := OpenLogArchive(filepath.Join(dir, "flat.gz"), 0); err == nil {
+		t.Error("Expected a file to be refused as an archive")
+	}
+}
+
+func TestFetchLogs_ArchiveFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeArchived(t, filepath.Join(dir, "ci", "results", "r", "logs", "tr"), []byte("archived\n"))
+	archive, _ := OpenLogArchive(dir, 0)
+
+	upstream := fmt.Errorf("perform GET request: connection refused")
+	svc := &Service{archive: archive, client: &mockRestClient{
+		getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) { return nil, upstream },
+	}}
+	if got, err := svc.FetchLogs(context.Background(), logRecord); err != nil || got != "archived\n" {
+		t.Errorf("FetchLogs() = %q, %v, want the archived log", got, err)
+	}
+	if _, err := svc.FetchLogs(context.Background(), "ci/results/r/records/other"); err != upstream {
+		t.Errorf("FetchLogs() error = %v, want the upstream error for a record not archived", err)
+	}
+
+	svc.client = &mockRestClient{
+		getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) { return []byte("live\n"), nil },
+	}
+	if got, _ := svc.FetchLogs(context.Background(), logRecord); got != "live\n" {
+		t.Errorf("Expected the Results API to be preferred, got %q", got)
+	}
+}
@@ -0,0 +1,156 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRunNotFound is returned by the methods that look up one run when no run
+// matches the selector.
+var ErrRunNotFound = errors.New("no run found that matches the provided filters")
+
+// ErrLogsDisabled is returned by FetchLogs when the Results API server was
+// deployed without log storage, so no run has logs to fetch.
+var ErrLogsDisabled = errors.New("log storage is not enabled on this Results server (LOGS_API is not set to true in its configuration), so logs of runs cannot be fetched; the run's status and step states are still available")
+
+// logsRecheck is how long a finding that log storage is disabled is trusted
+// before the next fetch tries the Results API again, in case it was enabled.
+const logsRecheck = 10 * time.Minute
+
+// logSupport remembers whether the Results API serves logs, learned from the
+// first fetch that answered the question.
+type logSupport struct {
+	mu       sync.Mutex
+	enabled  bool      // a log was fetched; never reconsidered
+	disabled time.Time // when log storage was found disabled; zero when not
+}
+
+// known returns ErrLogsDisabled while a recent fetch found log storage
+// disabled.
+func (l *logSupport) known(now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.disabled.IsZero() && now.Sub(l.disabled) < logsRecheck {
+		return ErrLogsDisabled
+	}
+	return nil
+}
+
+func (l *logSupport) set(enabled bool, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enabled = enabled
+	l.disabled = time.Time{}
+	if !enabled {
+		l.disabled = now
+	}
+}
+
+func (l *logSupport) confirmed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enabled
+}
+
+// FetchLogs downloads the log payload referenced by the record name. When
+// the Results API cannot serve it, the log is read from the log archive, if
+// one is configured. It returns ErrLogsDisabled when the Results server
+// stores no logs and the archive holds none for the record.
+func (s *Service) FetchLogs(ctx context.Context, recordName string) (string, error) {
+	text, err := s.fetchLogs(ctx, recordName)
+	if err == nil || s.archive == nil || ctx.Err() != nil {
+		return text, err
+	}
+	archived, archiveErr := s.archive.FetchLogs(ctx, recordName)
+	switch {
+	case archiveErr == nil:
+		slog.Debug("Read logs from the log archive", "record", recordName, "reason", err)
+		return archived, nil
+	case errors.Is(archiveErr, ErrNotArchived):
+		return "", err
+	default:
+		return "", fmt.Errorf("%w; the log archive could not be read either: %v", err, archiveErr)
+	}
+}
+
+func (s *Service) fetchLogs(ctx context.Context, recordName string) (string, error) {
+	if err := s.logs.known(time.Now()); err != nil {
+		return "", err
+	}
+	logPath := strings.Replace(recordName, "/records/", "/logs/", 1)
+	if logPath == recordName {
+		logPath = strings.Replace(recordName, "records", "logs", 1)
+	}
+	data, err := s.client.getLog(ctx, logPath)
+	if err == nil {
+		s.logs.set(true, time.Now())
+		return string(data), nil
+	}
+	if s.logsDisabled(ctx, err) {
+		s.logs.set(false, time.Now())
+		return "", ErrLogsDisabled
+	}
+	return "", err
+}
+
+// LogsDisabled reports whether a recent fetch found that the Results server
+// stores no logs, so callers can skip fetching them.
+func (s *Service) LogsDisabled() bool {
+	return s.logs.known(time.Now()) != nil
+}
+
+// logsDisabled decides whether a failed log fetch means the Results server
+// has no log storage rather than that this run has no log. Only a 404 can
+// mean that. The API server configuration settles it when it is readable;
+// otherwise a 404 for the route itself, rather than for a missing record or
+// log, gives it away: without LOGS_API the log endpoints are not registered.
+func (s *Service) logsDisabled(ctx context.Context, err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.status != http.StatusNotFound || s.logs.confirmed() {
+		return false
+	}
+	if logs := s.logStorageConfig(ctx); logs != nil {
+		if logs.Enabled {
+			s.logs.set(true, time.Now())
+		}
+		return !logs.Enabled
+	}
+	return routeNotFound(apiErr.body)
+}
+
+// logStorageConfig reads the log settings of the Results API server from its
+// ConfigMap, or returns nil when it cannot be read.
+func (s *Service) logStorageConfig(ctx context.Context) *LogStorage {
+	if s.cluster == nil {
+		return nil
+	}
+	for _, ns := range installNamespaces {
+		if cm, err := s.cluster.configMap(ctx, ns, apiConfigMap); err == nil && cm != nil {
+			return logStorage(cm)
+		}
+	}
+	return nil
+}
+
+// routeNotFound reports whether a 404 body is the generic answer for a path
+// no handler serves, as opposed to one naming a missing resource.
+func routeNotFound(body string) bool {
+	var status struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal([]byte(body), &status) == nil && status.Message != "" {
+		body = status.Message
+	}
+	switch strings.ToLower(strings.TrimSpace(body)) {
+	case "not found", "404 page not found", "":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package tektonresults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+const logRecord = "ci/results/r/records/tr"
+
+func notFound(body string) error {
+	return &apiError{method: http.MethodGet, path: "/logs", status: http.StatusNotFound, body: body}
+}
+
+func TestFetchLogs_DisabledRoute(t *testing.T) {
+	calls := 0
+	svc := &Service{client: &mockRestClient{
+		getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) {
+			calls++
+			return nil, notFound(`{"code":5,"message":"Not Found","details":[]}`)
+		},
+	}}
+
+	for range 2 {
+		if _, err := svc.FetchLogs(context.Background(), logRecord); !errors.Is(err, ErrLogsDisabled) {
+			t.Fatalf("FetchLogs() error = %v, want ErrLogsDisabled", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the finding to be cached after one request, got %d requests", calls)
+	}
+	if !svc.LogsDisabled() {
+		t.Error("Expected LogsDisabled() to report the finding")
+	}
+
+	// The finding is rechecked eventually, in case log storage was enabled.
+	svc.logs.set(false, time.Now().Add(-logsRecheck))
+	if svc.LogsDisabled() {
+		t.Error("Expected the finding to expire")
+	}
+}
+
+func TestFetchLogs_MissingLog(t *testing.T) {
+	for _, err := range []error{
+		notFound(`{"code":5,"message":"record not found"}`),
+		fmt.Errorf("perform GET request: connection refused"),
+		&apiError{status: http.StatusForbidden, body: "Not Found"},
+	} {
+		svc := &Service{client: &mockRestClient{
+			getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) { return nil, err },
+		}}
+		if _, got := svc.FetchLogs(context.Background(), logRecord); got == nil || errors.Is(got, ErrLogsDisabled) {
+			t.Errorf("FetchLogs() error = %v for %v, want the original error", got, err)
+		}
+	}
+}
+
+func TestFetchLogs_ConfigSettlesIt(t *testing.T) {
+	logsAPI := "false"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/tekton-pipelines/configmaps/tekton-results-api-config" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"config":%q}}`, "LOGS_API="+logsAPI+"\n")
+	}))
+	defer server.Close()
+	cluster, _ := newClusterClient(&rest.Config{Host: server.URL})
+
+	// A log missing for one run looks like a disabled route to the
+	// heuristic; the configuration tells them apart.
+	missing := &mockRestClient{
+		getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) { return nil, notFound("Not Found") },
+	}
+	svc := &Service{client: missing, cluster: cluster}
+	if _, err := svc.FetchLogs(context.Background(), logRecord); !errors.Is(err, ErrLogsDisabled) {
+		t.Errorf("FetchLogs() error = %v with LOGS_API=false, want ErrLogsDisabled", err)
+	}
+
+	logsAPI = "true"
+	svc = &Service{client: missing, cluster: cluster}
+	if _, err := svc.FetchLogs(context.Background(), logRecord); err == nil || errors.Is(err, ErrLogsDisabled) {
+		t.Errorf("FetchLogs() error = %v with LOGS_API=true, want the original error", err)
+	}
+	if !svc.logs.confirmed() {
+		t.Error("Expected the configuration to confirm log storage")
+	}
+}
+
+func TestFetchLogs_Success(t *testing.T) {
+	svc := &Service{client: &mockRestClient{
+		getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) {
+			if logPath != "ci/results/r/logs/tr" {
+				t.Errorf("Unexpected log path %s", logPath)
+			}
+			return []byte("hello"), nil
+		},
+	}}
+	if logs, err := svc.FetchLogs(context.Background(), logRecord); err != nil || logs != "hello" {
+		t.Errorf("FetchLogs() = %q, %v", logs, err)
+	}
+	if !svc.logs.confirmed() {
+		t.Error("Expected a fetched log to confirm log storage")
+	}
+}
@@ -0,0 +1,117 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Container name prefixes Tekton gives the containers of a TaskRun pod.
+const (
+	stepContainerPrefix    = "step-"
+	sidecarContainerPrefix = "sidecar-"
+)
+
+// LogSection is the output of one container in a stored log, in the order
+// the container was read.
+type LogSection struct {
+	Task      string   `json:"task,omitempty"` // pipeline task, in the logs of a PipelineRun
+	Container string   `json:"container"`      // step or sidecar name, without the step- or sidecar- prefix
+	Sidecar   bool     `json:"sidecar,omitempty"`
+	Lines     []string `json:"lines"`
+}
+
+// SplitLogs splits a stored log into sections per container. The Results
+// watcher writes logs the way tkn prints them, each line prefixed with
+// "[step] " or, for PipelineRuns, "[task : step] "; lines without a prefix
+// continue the section before them. Containers named sidecar-*, or listed in
+// sidecars, are marked as sidecars. It returns nil for logs without any
+// prefix, which then cannot be split.
+func SplitLogs(logs string, sidecars []string) []LogSection {
+	var sections []LogSection
+	for line := range strings.Lines(logs) {
+		line = strings.TrimSuffix(line, "\n")
+		task, container, rest, ok := cutLogPrefix(line)
+		if !ok {
+			if len(sections) == 0 {
+				sections = append(sections, LogSection{})
+			}
+			last := &sections[len(sections)-1]
+			last.Lines = append(last.Lines, line)
+			continue
+		}
+		sidecar := false
+		switch {
+		case strings.HasPrefix(container, sidecarContainerPrefix):
+			container, sidecar = strings.TrimPrefix(container, sidecarContainerPrefix), true
+		case strings.HasPrefix(container, stepContainerPrefix):
+			container = strings.TrimPrefix(container, stepContainerPrefix)
+		}
+		sidecar = sidecar || slices.Contains(sidecars, container)
+		if n := len(sections); n == 0 || sections[n-1].Task != task || sections[n-1].Container != container || sections[n-1].Sidecar != sidecar {
+			sections = append(sections, LogSection{Task: task, Container: container, Sidecar: sidecar})
+		}
+		last := &sections[len(sections)-1]
+		last.Lines = append(last.Lines, rest)
+	}
+	for _, s := range sections {
+		if s.Container != "" {
+			return sections
+		}
+	}
+	return nil
+}
+
+// cutLogPrefix splits a "[step] text" or "[task : step] text" line.
+func cutLogPrefix(line string) (task, container, rest string, ok bool) {
+	if !strings.HasPrefix(line, "[") {
+		return "", "", "", false
+	}
+	prefix, rest, ok := strings.Cut(line[1:], "] ")
+	if !ok {
+		if prefix, ok = strings.CutSuffix(line[1:], "]"); !ok {
+			return "", "", "", false
+		}
+	}
+	container = prefix
+	if t, c, found := strings.Cut(prefix, " : "); found {
+		task, container = t, c
+		if !containerName(task) {
+			return "", "", "", false
+		}
+	}
+	if !containerName(container) {
+		return "", "", "", false
+	}
+	return task, container, rest, true
+}
+
+// containerName reports whether s can be a container or pipeline task name,
+// which are DNS labels. This keeps lines that merely start with a bracket,
+// such as "[INFO] done", from being read as prefixes.
+func containerName(s string) bool {
+	return dnsLabel.MatchString(s)
+}
+
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Sidecars returns the names of the sidecars a TaskRun ran, from its status.
+// It returns nil for PipelineRuns and TaskRuns without sidecars.
+func (d RunDetail) Sidecars() []string {
+	var run struct {
+		Status struct {
+			Sidecars []struct {
+				Name string `json:"name"`
+			} `json:"sidecars"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil
+	}
+	var names []string
+	for _, s := range run.Status.Sidecars {
+		names = append(names, s.Name)
+	}
+	return names
+}
@@ -0,0 +1,43 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSplitLogs(t *testing.T) {
+	logs := "[build : git-clone] cloning\n" +
+		"  continued\n" +
+		"[build : sidecar-dind] daemon ready\n" +
+		"[build : step-compile] [INFO] compiling\n" +
+		"[build : registry] serving\n"
+	sections := SplitLogs(logs, []string{"registry"})
+	want := []LogSection{
+		{Task: "build", Container: "git-clone", Lines: []string{"cloning", "  continued"}},
+		{Task: "build", Container: "dind", Sidecar: true, Lines: []string{"daemon ready"}},
+		{Task: "build", Container: "compile", Lines: []string{"[INFO] compiling"}},
+		{Task: "build", Container: "registry", Sidecar: true, Lines: []string{"serving"}},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("SplitLogs() = %+v, want %+v", sections, want)
+	}
+	for i, w := range want {
+		got := sections[i]
+		if got.Task != w.Task || got.Container != w.Container || got.Sidecar != w.Sidecar || len(got.Lines) != len(w.Lines) || got.Lines[0] != w.Lines[0] {
+			t.Errorf("section %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	for _, logs := range []string{"", "plain output\n[INFO] not a prefix\n"} {
+		if sections := SplitLogs(logs, nil); sections != nil {
+			t.Errorf("SplitLogs(%q) = %+v, want nil", logs, sections)
+		}
+	}
+}
+
+func TestRunDetail_Sidecars(t *testing.T) {
+	detail := RunDetail{Raw: json.RawMessage(`{"kind":"TaskRun","status":{"sidecars":[{"name":"dind","container":"sidecar-dind"}]}}`)}
+	if got := detail.Sidecars(); len(got) != 1 || got[0] != "dind" {
+		t.Errorf("Sidecars() = %v, want [dind]", got)
+	}
+}
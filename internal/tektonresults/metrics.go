@@ -0,0 +1,259 @@
+package tektonresults
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	metricsWindow      = 5 * time.Minute
+	metricsBucketWidth = 10 * time.Second
+	pressureWindow     = 30 * time.Second
+)
+
+// UpstreamStats reports requests made to the Results API per endpoint, both
+// within the rolling window and since the server started.
+type UpstreamStats struct {
+	Window         string            `json:"window"`
+	Requests       map[string]int    `json:"requests"`            // per endpoint, within the window
+	Throttled      map[string]int    `json:"throttled,omitempty"` // HTTP 429 responses per endpoint, within the window
+	TotalRequests  map[string]int    `json:"totalRequests"`       // per endpoint, since start
+	TotalThrottled map[string]int    `json:"totalThrottled,omitempty"`
+	AvgLatency     map[string]string `json:"avgLatency,omitempty"` // per endpoint, since start
+}
+
+// clientMetrics counts upstream requests in fixed width time buckets so that
+// recent activity can be reported without keeping every request. It is safe
+// for concurrent use; a nil *clientMetrics records nothing.
+type clientMetrics struct {
+	mu             sync.Mutex
+	now            func() time.Time
+	buckets        []metricsBucket // ring indexed by slot modulo length
+	totalRequests  map[string]int
+	totalThrottled map[string]int
+	totalLatency   map[string]time.Duration
+}
+
+type metricsBucket struct {
+	slot      int64
+	requests  map[string]int
+	throttled map[string]int
+	failed    int // requests without a response or with HTTP 5xx
+}
+
+func newClientMetrics() *clientMetrics {
+	return &clientMetrics{
+		now:            time.Now,
+		buckets:        make([]metricsBucket, int(metricsWindow/metricsBucketWidth)),
+		totalRequests:  map[string]int{},
+		totalThrottled: map[string]int{},
+		totalLatency:   map[string]time.Duration{},
+	}
+}
+
+// record counts one request to endpoint that completed with status after
+// elapsed; a zero status means the request failed before a response arrived.
+func (m *clientMetrics) record(endpoint string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	throttled := status == http.StatusTooManyRequests
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	slot := m.now().UnixNano() / int64(metricsBucketWidth)
+	b := &m.buckets[slot%int64(len(m.buckets))]
+	if b.slot != slot || b.requests == nil {
+		*b = metricsBucket{slot: slot, requests: map[string]int{}, throttled: map[string]int{}}
+	}
+	b.requests[endpoint]++
+	m.totalRequests[endpoint]++
+	m.totalLatency[endpoint] += elapsed
+	if throttled {
+		b.throttled[endpoint]++
+		m.totalThrottled[endpoint]++
+	}
+	if status == 0 || status >= http.StatusInternalServerError {
+		b.failed++
+	}
+}
+
+// UpstreamPressure summarizes how the Results API answered in the last
+// moments, so callers can hold back optional work while it is overloaded.
+type UpstreamPressure struct {
+	Window    time.Duration
+	Requests  int
+	Throttled int // HTTP 429 responses
+	Failed    int // requests without a response or with HTTP 5xx
+}
+
+// pressure sums the buckets of the last pressureWindow.
+func (m *clientMetrics) pressure() UpstreamPressure {
+	p := UpstreamPressure{Window: pressureWindow}
+	if m == nil {
+		return p
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current := m.now().UnixNano() / int64(metricsBucketWidth)
+	oldest := current - int64(pressureWindow/metricsBucketWidth) + 1
+	for _, b := range m.buckets {
+		if b.requests == nil || b.slot < oldest || b.slot > current {
+			continue
+		}
+		for _, n := range b.requests {
+			p.Requests += n
+		}
+		for _, n := range b.throttled {
+			p.Throttled += n
+		}
+		p.Failed += b.failed
+	}
+	return p
+}
+
+// snapshot sums the buckets that fall inside the rolling window.
+func (m *clientMetrics) snapshot() UpstreamStats {
+	stats := UpstreamStats{
+		Window:         metricsWindow.String(),
+		Requests:       map[string]int{},
+		Throttled:      map[string]int{},
+		TotalRequests:  map[string]int{},
+		TotalThrottled: map[string]int{},
+		AvgLatency:     map[string]string{},
+	}
+	if m == nil {
+		return stats
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current := m.now().UnixNano() / int64(metricsBucketWidth)
+	oldest := current - int64(len(m.buckets)) + 1
+	for _, b := range m.buckets {
+		if b.requests == nil || b.slot < oldest || b.slot > current {
+			continue
+		}
+		for endpoint, n := range b.requests {
+			stats.Requests[endpoint] += n
+		}
+		for endpoint, n := range b.throttled {
+			stats.Throttled[endpoint] += n
+		}
+	}
+	for endpoint, n := range m.totalRequests {
+		stats.TotalRequests[endpoint] = n
+	}
+	for endpoint, n := range m.totalThrottled {
+		stats.TotalThrottled[endpoint] = n
+	}
+	for endpoint, d := range m.totalLatency {
+		stats.AvgLatency[endpoint] = (d / time.Duration(m.totalRequests[endpoint])).Round(time.Millisecond).String()
+	}
+	return stats
+}
+
+// endpointFor names the Results API endpoint a request targets, so metrics
+// are grouped by operation rather than by individual resource path.
+func endpointFor(method, relPath string) string {
+	switch {
+	case method == http.MethodDelete:
+		return "deleteResult"
+	case strings.Contains(relPath, "/logs/"):
+		return "getLog"
+	case strings.HasSuffix(relPath, "/records"):
+		return "listRecords"
+	case strings.HasSuffix(relPath, "/results"):
+		return "listResults"
+	case strings.Contains(relPath, "/records/"):
+		return "getRecord"
+	default:
+		return "other"
+	}
+}
+
+// UpstreamStats returns request counts against the Results API.
+func (s *Service) UpstreamStats() UpstreamStats {
+	return s.metrics.snapshot()
+}
+
+// UpstreamPressure reports how the Results API answered recently.
+func (s *Service) UpstreamPressure() UpstreamPressure {
+	return s.metrics.pressure()
+}
+
+// MetricsHandler serves the upstream request counters in the Prometheus text
+// exposition format.
+func (s *Service) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheus(w, s.UpstreamStats())
+	})
+}
+
+func writePrometheus(w io.Writer, stats UpstreamStats) {
+	series := []struct {
+		name, kind, help string
+		values           map[string]int
+	}{
+		{"tekton_results_upstream_requests_total", "counter", "Requests sent to the Tekton Results API.", stats.TotalRequests},
+		{"tekton_results_upstream_throttled_total", "counter", "Tekton Results API responses with HTTP status 429.", stats.TotalThrottled},
+		{"tekton_results_upstream_requests_window", "gauge", "Requests sent to the Tekton Results API within the last " + stats.Window + ".", stats.Requests},
+		{"tekton_results_upstream_throttled_window", "gauge", "HTTP 429 responses within the last " + stats.Window + ".", stats.Throttled},
+	}
+	for _, m := range series {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		endpoints := make([]string, 0, len(m.values))
+		for endpoint := range m.values {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(w, "%s{endpoint=%q} %d\n", m.name, endpoint, m.values[endpoint])
+		}
+	}
+}
+
+// CacheStats counts lookups in one of the service caches since start.
+type CacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hitRate"` // hits over lookups, 0 before the first lookup
+}
+
+// cacheCounter counts cache hits and misses. It is safe for concurrent use.
+type cacheCounter struct {
+	hits, misses atomic.Int64
+}
+
+func (c *cacheCounter) observe(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+func (c *cacheCounter) stats() CacheStats {
+	stats := CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// CacheStats reports the hit rates of the completion cache and of the index
+// of nested TaskRun records.
+func (s *Service) CacheStats() map[string]CacheStats {
+	return map[string]CacheStats{
+		"completions": s.completions.counter.stats(),
+		"runIndex":    s.runs.counter.stats(),
+	}
+}
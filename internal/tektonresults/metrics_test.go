@@ -0,0 +1,186 @@
+package tektonresults
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientMetrics_RollingWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := newClientMetrics()
+	m.now = func() time.Time { return now }
+
+	m.record("listRecords", http.StatusOK, 0)
+	m.record("listRecords", http.StatusTooManyRequests, 0)
+	m.record("getLog", 0, 0)
+
+	stats := m.snapshot()
+	if stats.Requests["listRecords"] != 2 || stats.Requests["getLog"] != 1 {
+		t.Fatalf("unexpected window requests: %v", stats.Requests)
+	}
+	if stats.Throttled["listRecords"] != 1 || len(stats.Throttled) != 1 {
+		t.Fatalf("unexpected window throttled: %v", stats.Throttled)
+	}
+
+	// Still inside the window.
+	now = now.Add(metricsWindow - metricsBucketWidth)
+	m.record("getRecord", http.StatusOK, 0)
+	if stats := m.snapshot(); stats.Requests["listRecords"] != 2 || stats.Requests["getRecord"] != 1 {
+		t.Fatalf("expected earlier requests to remain in the window, got %v", stats.Requests)
+	}
+
+	// The first bucket has aged out; totals are kept.
+	now = now.Add(metricsBucketWidth)
+	stats = m.snapshot()
+	if _, ok := stats.Requests["listRecords"]; ok {
+		t.Fatalf("expected listRecords to leave the window, got %v", stats.Requests)
+	}
+	if stats.Requests["getRecord"] != 1 {
+		t.Fatalf("expected getRecord to remain, got %v", stats.Requests)
+	}
+	if stats.TotalRequests["listRecords"] != 2 || stats.TotalThrottled["listRecords"] != 1 {
+		t.Fatalf("unexpected totals: %v %v", stats.TotalRequests, stats.TotalThrottled)
+	}
+
+	// A reused ring slot must not carry counts from a previous lap.
+	now = now.Add(metricsWindow)
+	m.record("getLog", http.StatusOK, 0)
+	if stats := m.snapshot(); len(stats.Requests) != 1 || stats.Requests["getLog"] != 1 {
+		t.Fatalf("expected only the latest request in the window, got %v", stats.Requests)
+	}
+}
+
+func TestClientMetrics_Pressure(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := newClientMetrics()
+	m.now = func() time.Time { return now }
+
+	m.record("listRecords", http.StatusTooManyRequests, 0)
+	m.record("getLog", 0, 0)
+	m.record("getRecord", http.StatusServiceUnavailable, 0)
+	m.record("getRecord", http.StatusNotFound, 0)
+	if p := m.pressure(); p.Requests != 4 || p.Throttled != 1 || p.Failed != 2 || p.Window != pressureWindow {
+		t.Fatalf("unexpected pressure %+v", p)
+	}
+
+	// The pressure window is shorter than the metrics window.
+	now = now.Add(pressureWindow)
+	m.record("getRecord", http.StatusOK, 0)
+	if p := m.pressure(); p.Requests != 1 || p.Throttled != 0 || p.Failed != 0 {
+		t.Fatalf("expected earlier requests to leave the pressure window, got %+v", p)
+	}
+	if stats := m.snapshot(); stats.Requests["getRecord"] != 3 {
+		t.Fatalf("expected them to stay in the metrics window, got %v", stats.Requests)
+	}
+
+	var none *clientMetrics
+	if p := none.pressure(); p.Requests != 0 {
+		t.Fatalf("unexpected pressure from nil metrics: %+v", p)
+	}
+}
+
+func TestClientMetrics_Nil(t *testing.T) {
+	var m *clientMetrics
+	m.record("getLog", http.StatusOK, 0)
+	if stats := m.snapshot(); len(stats.Requests) != 0 || stats.Window == "" {
+		t.Fatalf("unexpected snapshot from nil metrics: %+v", stats)
+	}
+}
+
+func TestEndpointFor(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "parents/ns/results/-/records", "listRecords"},
+		{http.MethodGet, "parents/ns/results/abc/records/def", "getRecord"},
+		{http.MethodGet, "parents/ns/results", "listResults"},
+		{http.MethodGet, "parents/ns/results/abc/logs/def", "getLog"},
+		{http.MethodDelete, "parents/ns/results/abc", "deleteResult"},
+		{http.MethodGet, "healthz", "other"},
+	}
+	for _, tt := range tests {
+		if got := endpointFor(tt.method, tt.path); got != tt.want {
+			t.Errorf("endpointFor(%s, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRestClient_RecordsMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/records") {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL)
+	client := &restClient{baseURL: baseURL, httpClient: server.Client(), metrics: newClientMetrics()}
+	svc := &Service{client: client, metrics: client.metrics}
+
+	if _, err := client.listResults(context.Background(), listResultsRequest{Parent: "ns"}); err != nil {
+		t.Fatalf("listResults: %v", err)
+	}
+	if _, err := client.listRecords(context.Background(), listRecordsRequest{Parent: "ns/results/-"}); err == nil {
+		t.Fatal("expected error for throttled listRecords")
+	}
+
+	stats := svc.UpstreamStats()
+	if stats.Requests["listResults"] != 1 || stats.Requests["listRecords"] != 1 {
+		t.Fatalf("unexpected requests: %v", stats.Requests)
+	}
+	if stats.Throttled["listRecords"] != 1 {
+		t.Fatalf("expected one throttled listRecords, got %v", stats.Throttled)
+	}
+	if info := svc.ServerInfo(context.Background(), false); info.Upstream == nil || info.Upstream.TotalRequests["listResults"] < 1 {
+		t.Fatalf("expected server info to carry upstream stats, got %+v", info.Upstream)
+	}
+
+	rec := httptest.NewRecorder()
+	svc.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE tekton_results_upstream_requests_total counter",
+		`tekton_results_upstream_requests_total{endpoint="listRecords"} 1`,
+		`tekton_results_upstream_throttled_total{endpoint="listRecords"} 1`,
+		`tekton_results_upstream_throttled_window{endpoint="listRecords"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestClientMetrics_AvgLatency(t *testing.T) {
+	m := newClientMetrics()
+	m.record("getLog", http.StatusOK, 100*time.Millisecond)
+	m.record("getLog", http.StatusOK, 300*time.Millisecond)
+	if got := m.snapshot().AvgLatency["getLog"]; got != "200ms" {
+		t.Errorf("AvgLatency[getLog] = %q, want 200ms", got)
+	}
+}
+
+func TestService_CacheStats(t *testing.T) {
+	var svc Service
+	svc.runs.observe([]record{nestedTaskRun("ci", "pr-1", "tr-1")})
+	svc.runs.lookup("ci", "tr-1")
+	svc.runs.lookup("ci", "tr-2")
+	svc.runs.lookup("dev", "tr-1")
+	load := func() ([]string, error) { return []string{"build"}, nil }
+	_, _ = svc.completions.get("pipeline", load)
+	_, _ = svc.completions.get("pipeline", load)
+
+	stats := svc.CacheStats()
+	if got := stats["runIndex"]; got.Hits != 1 || got.Misses != 2 {
+		t.Errorf("Unexpected run index stats %+v", got)
+	}
+	if got := stats["completions"]; got.Hits != 1 || got.Misses != 1 || got.HitRate != 0.5 {
+		t.Errorf("Unexpected completion stats %+v", got)
+	}
+}
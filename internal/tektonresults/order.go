@@ -0,0 +1,99 @@
+package tektonresults
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrderFields lists the fields run listings can be ordered by.
+var OrderFields = []string{"create_time", "update_time", "completion_time"}
+
+// defaultOrder lists the newest records first.
+var defaultOrder = listOrder{field: "create_time"}
+
+// listOrder is a parsed ListOptions.OrderBy.
+type listOrder struct {
+	field     string
+	ascending bool
+}
+
+// parseOrder parses "<field> [asc|desc]", ignoring case. The direction
+// defaults to descending and the empty string to create_time desc.
+func parseOrder(input string) (listOrder, error) {
+	parts := strings.Fields(strings.ToLower(input))
+	if len(parts) == 0 {
+		return defaultOrder, nil
+	}
+	order := listOrder{field: parts[0]}
+	if !slices.Contains(OrderFields, order.field) || len(parts) > 2 {
+		return listOrder{}, fmt.Errorf("invalid orderBy %q: expected one of %s, optionally followed by asc or desc", input, strings.Join(OrderFields, ", "))
+	}
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "asc":
+			order.ascending = true
+		case "desc":
+		default:
+			return listOrder{}, fmt.Errorf("invalid orderBy %q: the direction must be asc or desc", input)
+		}
+	}
+	return order, nil
+}
+
+func (o listOrder) String() string {
+	if o.ascending {
+		return o.field + " asc"
+	}
+	return o.field + " desc"
+}
+
+// upstream returns the order_by clause sent to the Results API. Records do
+// not carry the completion time of their run, so completion order is
+// requested as update order: the watcher updates a record for the last time
+// when its run completes.
+func (o listOrder) upstream() string {
+	if o.field == "completion_time" {
+		return listOrder{field: "update_time", ascending: o.ascending}.String()
+	}
+	return o.String()
+}
+
+// key returns the run timestamp summaries are ordered by locally. Summaries
+// do not carry record times, so create_time is approximated by the start
+// time and update_time by the completion time.
+func (o listOrder) key(s RunSummary) *metav1.Time {
+	if o.field == "create_time" {
+		return s.StartTime
+	}
+	return s.CompletionTime
+}
+
+// before reports whether a sorts before b. Runs without the timestamp have
+// not started or completed yet, so they count as the newest.
+func (o listOrder) before(a, b RunSummary) bool {
+	if o.ascending {
+		a, b = b, a
+	}
+	ka, kb := o.key(a), o.key(b)
+	switch {
+	case ka == nil:
+		return kb != nil
+	case kb == nil:
+		return false
+	default:
+		return ka.After(kb.Time)
+	}
+}
+
+// sortPage orders the runs of one page by completion time, which the Results
+// API cannot sort by. Other orders are already applied upstream.
+func (o listOrder) sortPage(runs []RunSummary) {
+	if o.field != "completion_time" {
+		return
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return o.before(runs[i], runs[j]) })
+}
@@ -0,0 +1,82 @@
+package tektonresults
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		input, want, upstream, wantErr string
+	}{
+		{"", "create_time desc", "create_time desc", ""},
+		{"update_time", "update_time desc", "update_time desc", ""},
+		{" Create_Time  ASC ", "create_time asc", "create_time asc", ""},
+		{"completion_time asc", "completion_time asc", "update_time asc", ""},
+		{"start_time", "", "", "expected one of create_time"},
+		{"create_time up", "", "", "must be asc or desc"},
+		{"create_time asc desc", "", "", "expected one of"},
+	}
+	for _, tt := range tests {
+		order, err := parseOrder(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseOrder(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || order.String() != tt.want || order.upstream() != tt.upstream {
+			t.Errorf("parseOrder(%q) = %s (upstream %s), %v; want %s (upstream %s)", tt.input, order, order.upstream(), err, tt.want, tt.upstream)
+		}
+	}
+}
+
+func TestService_ListRuns_CompletionOrder(t *testing.T) {
+	var orderBy string
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			orderBy = req.OrderBy
+			records := testRecords("foo", namedRuns("nightly", 3)...)
+			records[0].Data.Value = []byte(`{"metadata":{"name":"a","uid":"uid-0"},"status":{"completionTime":"2024-05-01T10:00:00Z"}}`)
+			records[1].Data.Value = []byte(`{"metadata":{"name":"b","uid":"uid-1"},"status":{"completionTime":"2024-05-01T09:00:00Z"}}`)
+			records[2].Data.Value = []byte(`{"metadata":{"name":"c","uid":"uid-2"},"status":{}}`)
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	runs, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", OrderBy: "completion_time asc"})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if orderBy != "update_time asc" {
+		t.Errorf("Expected completion order to be fetched in update order, got %q", orderBy)
+	}
+	var names []string
+	for _, run := range runs {
+		names = append(names, run.Name)
+	}
+	// Runs still going have not completed, so they come last.
+	if got := strings.Join(names, ","); got != "b,a,c" {
+		t.Errorf("Expected runs by completion time, got %s", got)
+	}
+}
+
+func TestListOrder_Before(t *testing.T) {
+	at := func(hour int) *metav1.Time {
+		tm := metav1.NewTime(time.Date(2024, 5, 1, hour, 0, 0, 0, time.UTC))
+		return &tm
+	}
+	early, late := RunSummary{StartTime: at(9)}, RunSummary{StartTime: at(10)}
+	if !defaultOrder.before(late, early) || defaultOrder.before(early, late) {
+		t.Error("Expected the default order to put the newest run first")
+	}
+	asc := listOrder{field: "create_time", ascending: true}
+	if !asc.before(early, late) || !asc.before(late, RunSummary{}) {
+		t.Error("Expected ascending order to put the oldest run first and pending runs last")
+	}
+}
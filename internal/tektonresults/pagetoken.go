@@ -0,0 +1,89 @@
+package tektonresults
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Page tokens of the Results API continue one specific query: reusing a
+// token with other filters returns pages of a different listing, silently
+// skipping or repeating runs. Tokens handed to clients are therefore wrapped
+// with a hash of the query that produced them and rejected when presented
+// with another query.
+
+// pageQuery identifies a paginated query for the purpose of token validation.
+type pageQuery struct {
+	Kind          string
+	Namespace     string
+	LabelSelector string
+	Filters       []string // any further filters that change the listing, e.g. "reason=Failed"
+}
+
+// hash returns a short digest of the query. Label selector clauses and
+// filters are order independent.
+func (q pageQuery) hash() string {
+	parts := []string{q.Kind, q.Namespace, strings.Join(sortedClauses(q.LabelSelector), ","), strings.Join(slices.Sorted(slices.Values(q.Filters)), ",")}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// sortedClauses splits a label selector into trimmed, sorted clauses.
+func sortedClauses(selector string) []string {
+	var clauses []string
+	for _, clause := range strings.Split(selector, ",") {
+		if clause = strings.TrimSpace(clause); clause != "" {
+			clauses = append(clauses, clause)
+		}
+	}
+	slices.Sort(clauses)
+	return clauses
+}
+
+// pageToken is the decoded form of a wrapped page token. A listing that
+// stops in the middle of an upstream page resumes by fetching that page again
+// with the same size and skipping the records already returned.
+type pageToken struct {
+	Query  string    `json:"q"`
+	Token  string    `json:"p,omitempty"` // upstream token of the page; empty for the first page
+	Skip   int       `json:"s,omitempty"` // records of the page already returned
+	Size   int32     `json:"z,omitempty"` // page size the page was fetched with; set along with Skip
+	After  time.Time `json:"a,omitzero"`  // creation time bounds of the listing, fixed when it started
+	Before time.Time `json:"b,omitzero"`
+}
+
+// encodePageToken wraps pt for q. A token that neither continues an upstream
+// page nor skips records marks the last page and stays empty.
+func encodePageToken(q pageQuery, pt pageToken) string {
+	if pt.Token == "" && pt.Skip == 0 {
+		return ""
+	}
+	pt.Query = q.hash()
+	payload, _ := json.Marshal(pt)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodePageToken unwraps token, failing when it was issued for a query
+// other than q. An empty token decodes to the zero pageToken, the first page.
+func decodePageToken(q pageQuery, token string) (pageToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return pageToken{}, nil
+	}
+	var pt pageToken
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(payload, &pt)
+	}
+	if err != nil || pt.Token == "" && pt.Skip == 0 || pt.Skip < 0 || pt.Skip > 0 && pt.Size <= 0 {
+		return pageToken{}, fmt.Errorf("invalid page token; pass the page token returned by a previous call unchanged")
+	}
+	if pt.Query != q.hash() {
+		return pageToken{}, fmt.Errorf("page token was issued for a different query; repeat the same namespace and filters, or start again without a page token")
+	}
+	return pt, nil
+}
@@ -0,0 +1,61 @@
+package tektonresults
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	q := pageQuery{Kind: "pipelinerun", Namespace: "ci", LabelSelector: "app=web,env=prod", Filters: []string{"reason=Failed"}}
+	token := encodePageToken(q, pageToken{Token: "upstream-token"})
+	if token == "" || strings.Contains(token, "upstream-token") {
+		t.Fatalf("Expected an opaque wrapped token, got %q", token)
+	}
+
+	// Clause order and whitespace do not change the query.
+	same := pageQuery{Kind: "pipelinerun", Namespace: "ci", LabelSelector: " env=prod, app=web", Filters: []string{"reason=Failed"}}
+	got, err := decodePageToken(same, token)
+	if err != nil || got.Token != "upstream-token" {
+		t.Errorf("decodePageToken() = %+v, %v; want upstream-token", got, err)
+	}
+
+	// A listing that stopped inside the first page resumes without an
+	// upstream token.
+	got, err = decodePageToken(q, encodePageToken(q, pageToken{Skip: 3, Size: 10}))
+	if err != nil || got.Token != "" || got.Skip != 3 || got.Size != 10 {
+		t.Errorf("decodePageToken() = %+v, %v; want skip 3 of a page of 10", got, err)
+	}
+
+	if encodePageToken(q, pageToken{}) != "" {
+		t.Error("Expected the last page to yield an empty token")
+	}
+	if got, err := decodePageToken(q, ""); got != (pageToken{}) || err != nil {
+		t.Errorf("Expected an empty token to start from the first page, got %+v, %v", got, err)
+	}
+}
+
+func TestPageToken_Mismatch(t *testing.T) {
+	q := pageQuery{Kind: "pipelinerun", Namespace: "ci", LabelSelector: "app=web"}
+	token := encodePageToken(q, pageToken{Token: "upstream-token"})
+
+	tests := []struct {
+		name  string
+		query pageQuery
+		token string
+		want  string
+	}{
+		{"kind", pageQuery{Kind: "taskrun", Namespace: "ci", LabelSelector: "app=web"}, token, "different query"},
+		{"namespace", pageQuery{Kind: "pipelinerun", Namespace: "dev", LabelSelector: "app=web"}, token, "different query"},
+		{"selector", pageQuery{Kind: "pipelinerun", Namespace: "ci", LabelSelector: "app=api"}, token, "different query"},
+		{"filters", pageQuery{Kind: "pipelinerun", Namespace: "ci", LabelSelector: "app=web", Filters: []string{"reason=Failed"}}, token, "different query"},
+		{"raw upstream token", q, "upstream-token", "invalid page token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePageToken(tt.query, tt.token)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
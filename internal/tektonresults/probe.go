@@ -0,0 +1,209 @@
+package tektonresults
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// probeNamespacePages bounds how many pages of Results the startup probe
+// scans when counting namespaces.
+const probeNamespacePages = 5
+
+// ServerInfo summarizes the Tekton Results endpoint the server talks to, as
+// observed by the most recent probe.
+type ServerInfo struct {
+	Endpoint            string    `json:"endpoint"`
+	APIVersion          string    `json:"apiVersion"`
+	Identity            string    `json:"identity,omitempty"`
+	Namespaces          int       `json:"namespaces"`
+	NamespacesTruncated bool      `json:"namespacesTruncated,omitempty"` // the count is a lower bound
+	Latency             string    `json:"latency"`
+	CheckedAt           time.Time `json:"checkedAt"`
+	Error               string    `json:"error,omitempty"`
+
+	Upstream *UpstreamStats `json:"upstream,omitempty"` // live request counters, not part of the probe
+}
+
+// identityFunc resolves the user the server authenticates as.
+type identityFunc func(ctx context.Context) (string, error)
+
+// Probe performs one authenticated round trip against the Results API and
+// records what it learns. Failures are reported in ServerInfo.Error rather
+// than returned, so callers can always log or display the outcome.
+func (s *Service) Probe(ctx context.Context) ServerInfo {
+	info := ServerInfo{
+		Endpoint:   s.endpoint,
+		APIVersion: fmt.Sprintf("%s/%s", resultsGroup, resultsVersion),
+		CheckedAt:  time.Now().UTC(),
+	}
+
+	start := time.Now()
+	namespaces, truncated, err := s.scanNamespaces(ctx, probeNamespacePages)
+	if err != nil {
+		info.Error = err.Error()
+	}
+	info.Latency = time.Since(start).Round(time.Millisecond).String()
+	info.Namespaces = len(namespaces)
+	info.NamespacesTruncated = truncated
+
+	if s.whoami != nil {
+		if identity, err := s.whoami(ctx); err == nil {
+			info.Identity = identity
+		}
+	}
+
+	s.infoMu.Lock()
+	s.info = &info
+	s.infoMu.Unlock()
+	return info
+}
+
+// scanNamespaces lists Results across all namespaces, reading at most
+// maxPages pages, and returns the distinct namespaces seen in sorted order.
+// truncated reports that more pages were left unread. On error the
+// namespaces seen so far are returned along with it.
+func (s *Service) scanNamespaces(ctx context.Context, maxPages int) (namespaces []string, truncated bool, err error) {
+	seen := map[string]struct{}{}
+	req := listResultsRequest{Parent: "-", PageSize: maxPageSize}
+	for page := 0; ; page++ {
+		if page == maxPages {
+			truncated = true
+			break
+		}
+		resp, listErr := s.client.listResults(ctx, req)
+		if listErr != nil {
+			err = listErr
+			break
+		}
+		for _, res := range resp.Results {
+			ns, _, _ := strings.Cut(res.Name, "/")
+			seen[ns] = struct{}{}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, truncated, err
+}
+
+// ServerInfo returns the result of the last probe, probing first when none
+// has run yet or refresh is requested.
+func (s *Service) ServerInfo(ctx context.Context, refresh bool) ServerInfo {
+	s.infoMu.Lock()
+	cached := s.info
+	s.infoMu.Unlock()
+
+	var info ServerInfo
+	if cached != nil && !refresh {
+		info = *cached
+	} else {
+		info = s.Probe(ctx)
+	}
+	if s.metrics != nil {
+		stats := s.metrics.snapshot()
+		info.Upstream = &stats
+	}
+	return info
+}
+
+// newIdentityFunc picks how to discover the authenticated identity: the
+// subject of a JWT bearer token when one is known, otherwise a Kubernetes
+// SelfSubjectReview against the cluster the config points at.
+func newIdentityFunc(cfg *rest.Config, overrides Overrides) identityFunc {
+	return func(ctx context.Context) (string, error) {
+		token := overrides.BearerToken
+		if token == "" && cfg != nil {
+			token = cfg.BearerToken
+			if cfg.BearerTokenFile != "" {
+				if data, err := os.ReadFile(cfg.BearerTokenFile); err == nil {
+					token = strings.TrimSpace(string(data))
+				}
+			}
+		}
+		if subject, ok := jwtSubject(token); ok {
+			return subject, nil
+		}
+		if overrides.Host != "" || cfg == nil {
+			return "", fmt.Errorf("identity is not discoverable for this endpoint")
+		}
+		return selfSubjectReview(ctx, cfg)
+	}
+}
+
+// jwtSubject extracts the "sub" claim from a JWT without verifying it. It is
+// only used for display.
+func jwtSubject(token string) (string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", false
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return "", false
+	}
+	return claims.Subject, true
+}
+
+// selfSubjectReview asks the Kubernetes API server who the config
+// authenticates as.
+func selfSubjectReview(ctx context.Context, cfg *rest.Config) (string, error) {
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return "", fmt.Errorf("create http client: %w", err)
+	}
+	body := []byte(`{"apiVersion":"authentication.k8s.io/v1","kind":"SelfSubjectReview"}`)
+	endpoint := strings.TrimSuffix(cfg.Host, "/") + "/apis/authentication.k8s.io/v1/selfsubjectreviews"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create self subject review request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("perform self subject review: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read self subject review: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("self subject review: %s", strings.TrimSpace(string(data)))
+	}
+
+	var review struct {
+		Status struct {
+			UserInfo struct {
+				Username string `json:"username"`
+			} `json:"userInfo"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &review); err != nil {
+		return "", fmt.Errorf("decode self subject review: %w", err)
+	}
+	return review.Status.UserInfo.Username, nil
+}
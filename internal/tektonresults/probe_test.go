@@ -0,0 +1,105 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestService_Probe(t *testing.T) {
+	pages := map[string]*listResultsResponse{
+		"": {
+			Results:       []result{{Name: "foo/results/a"}, {Name: "foo/results/b"}, {Name: "bar/results/c"}},
+			NextPageToken: "next",
+		},
+		"next": {
+			Results: []result{{Name: "baz/results/d"}},
+		},
+	}
+	mockClient := &mockRestClient{
+		listResultsFunc: func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+			if req.Parent != "-" {
+				t.Errorf("Expected parent '-', got %s", req.Parent)
+			}
+			return pages[req.PageToken], nil
+		},
+	}
+	service := &Service{
+		client:   mockClient,
+		endpoint: "https://results.example.com/apis/results.tekton.dev/v1alpha2",
+		whoami: func(ctx context.Context) (string, error) {
+			return "system:serviceaccount:tekton:mcp", nil
+		},
+	}
+
+	info := service.Probe(context.Background())
+	if info.Error != "" {
+		t.Fatalf("Unexpected probe error: %s", info.Error)
+	}
+	if info.Namespaces != 3 || info.NamespacesTruncated {
+		t.Errorf("Expected 3 namespaces, got %d (truncated=%v)", info.Namespaces, info.NamespacesTruncated)
+	}
+	if info.Identity != "system:serviceaccount:tekton:mcp" {
+		t.Errorf("Unexpected identity %q", info.Identity)
+	}
+	if info.APIVersion != "results.tekton.dev/v1alpha2" || info.Endpoint == "" {
+		t.Errorf("Unexpected endpoint info: %+v", info)
+	}
+
+	// A cached result is served without another API call.
+	mockClient.listResultsFunc = func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+		t.Error("Expected cached server info")
+		return nil, fmt.Errorf("unexpected call")
+	}
+	if cached := service.ServerInfo(context.Background(), false); cached.Namespaces != 3 {
+		t.Errorf("Expected cached namespace count, got %d", cached.Namespaces)
+	}
+}
+
+func TestService_Probe_Error(t *testing.T) {
+	service := &Service{client: &mockRestClient{
+		listResultsFunc: func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+			return nil, fmt.Errorf(`results API GET /parents/-/results: {"code":16,"message":"unauthenticated"}`)
+		},
+	}}
+
+	info := service.ServerInfo(context.Background(), true)
+	if !strings.Contains(info.Error, "unauthenticated") {
+		t.Errorf("Expected probe error to be reported, got %+v", info)
+	}
+}
+
+func TestService_Probe_TruncatesNamespaceScan(t *testing.T) {
+	calls := 0
+	service := &Service{client: &mockRestClient{
+		listResultsFunc: func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+			calls++
+			return &listResultsResponse{
+				Results:       []result{{Name: fmt.Sprintf("ns-%d/results/x", calls)}},
+				NextPageToken: "more",
+			}, nil
+		},
+	}}
+
+	info := service.Probe(context.Background())
+	if calls != probeNamespacePages {
+		t.Errorf("Expected %d pages to be scanned, got %d", probeNamespacePages, calls)
+	}
+	if !info.NamespacesTruncated || info.Namespaces != probeNamespacePages {
+		t.Errorf("Expected truncated count of %d, got %+v", probeNamespacePages, info)
+	}
+}
+
+func TestJWTSubject(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:ci:reader"}`))
+	if sub, ok := jwtSubject("header." + payload + ".sig"); !ok || sub != "system:serviceaccount:ci:reader" {
+		t.Errorf("jwtSubject() = %q, %v", sub, ok)
+	}
+	for _, token := range []string{"", "opaque-token", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".c"} {
+		if _, ok := jwtSubject(token); ok {
+			t.Errorf("Expected no subject for %q", token)
+		}
+	}
+}
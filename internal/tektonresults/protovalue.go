@@ -0,0 +1,104 @@
+package tektonresults
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// Some Results deployments store record data as a google.protobuf.Any
+// wrapping the JSON of the run instead of the JSON itself. The API then
+// returns either the binary Any, base64 encoded like any other value, or its
+// JSON mapping {"@type": ..., "value": <base64>}. The wrapper is small
+// enough to decode by hand, which keeps the protobuf runtime and the Results
+// proto types out of the build.
+
+// maxAnyDepth bounds how many Any wrappers are removed from one value.
+const maxAnyDepth = 4
+
+// unwrapValue returns the JSON inside a record value, removing Any wrappers.
+// Values that are neither JSON nor an Any are returned unchanged.
+func unwrapValue(value []byte) (json.RawMessage, error) {
+	for range maxAnyDepth {
+		typeURL, inner, ok := decodeAny(value)
+		if !ok {
+			return value, nil
+		}
+		if len(inner) > 0 && !json.Valid(inner) {
+			if _, _, nested := decodeAny(inner); !nested {
+				return nil, fmt.Errorf("record value is a protobuf %s message; only JSON payloads can be decoded", typeURL)
+			}
+		}
+		value = inner
+	}
+	return nil, fmt.Errorf("record value is wrapped in more than %d protobuf Any messages", maxAnyDepth)
+}
+
+// decodeAny decodes value as a google.protobuf.Any in its JSON mapping or
+// binary encoding. ok is false when value is neither.
+func decodeAny(value []byte) (typeURL string, inner []byte, ok bool) {
+	if json.Valid(value) {
+		if !bytes.Contains(value, []byte(`"@type"`)) {
+			return "", nil, false
+		}
+		var wrapper struct {
+			Type  string `json:"@type"`
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(value, &wrapper); err != nil || wrapper.Type == "" {
+			return "", nil, false
+		}
+		inner, err := base64.StdEncoding.DecodeString(wrapper.Value)
+		if err != nil {
+			return "", nil, false
+		}
+		return wrapper.Type, inner, true
+	}
+	return decodeBinaryAny(value)
+}
+
+// decodeBinaryAny decodes the protobuf wire format of an Any: field 1 is the
+// type URL and field 2 the payload, both length delimited. Unknown fields are
+// skipped. A type URL is required, which tells an Any from arbitrary bytes.
+func decodeBinaryAny(b []byte) (typeURL string, inner []byte, ok bool) {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return "", nil, false
+		}
+		b = b[n:]
+		field, wireType := tag>>3, tag&7
+		var data []byte
+		switch wireType {
+		case 0: // varint
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return "", nil, false
+			}
+		case 1: // fixed64
+			n = 8
+		case 2: // length delimited
+			size, m := binary.Uvarint(b)
+			if m <= 0 || size > uint64(len(b)-m) {
+				return "", nil, false
+			}
+			data, n = b[m:m+int(size)], m+int(size)
+		case 5: // fixed32
+			n = 4
+		default:
+			return "", nil, false
+		}
+		if n > len(b) {
+			return "", nil, false
+		}
+		b = b[n:]
+		switch {
+		case field == 1 && wireType == 2:
+			typeURL = string(data)
+		case field == 2 && wireType == 2:
+			inner = data
+		}
+	}
+	return typeURL, inner, typeURL != ""
+}
@@ -0,0 +1,115 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPruneLimit = 100
+	maxPruneLimit     = 1000
+)
+
+// PruneOptions selects the Results removed by PruneResults.
+type PruneOptions struct {
+	Namespace string        // a single namespace; pruning across all namespaces is not allowed
+	OlderThan time.Duration // Results last updated before now-OlderThan are pruned
+	DryRun    bool          // report candidates without deleting anything
+	Limit     int           // maximum number of Results to prune in one call
+	// Progress, when set, is called after each deletion with the number of
+	// Results processed so far and the total number of candidates.
+	Progress func(done, total int)
+}
+
+// PruneCandidate is a Result selected for pruning.
+type PruneCandidate struct {
+	Name       string    `json:"name"`
+	UpdateTime time.Time `json:"updateTime"`
+	Error      string    `json:"error,omitempty"` // set when deletion failed
+}
+
+// PruneReport describes the outcome of PruneResults.
+type PruneReport struct {
+	Namespace  string           `json:"namespace"`
+	Cutoff     time.Time        `json:"cutoff"`
+	DryRun     bool             `json:"dryRun"`
+	Candidates []PruneCandidate `json:"candidates"`
+	Deleted    int              `json:"deleted"`
+	Failed     int              `json:"failed"`
+	Truncated  bool             `json:"truncated,omitempty"` // more Results match than Limit allowed
+}
+
+// PruneResults deletes Results, including their records and logs, that were
+// last updated before the cutoff. With DryRun it only reports the candidates.
+func (s *Service) PruneResults(ctx context.Context, opts PruneOptions) (*PruneReport, error) {
+	ns := strings.TrimSpace(opts.Namespace)
+	if namespaces, _ := splitNamespaces(ns); len(namespaces) != 1 || namespaces[0] == "-" {
+		return nil, fmt.Errorf("pruning requires a single namespace")
+	}
+	if opts.OlderThan <= 0 {
+		return nil, fmt.Errorf("olderThan must be a positive duration")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultPruneLimit
+	}
+	if limit > maxPruneLimit {
+		limit = maxPruneLimit
+	}
+
+	report := &PruneReport{
+		Namespace: ns,
+		Cutoff:    time.Now().UTC().Add(-opts.OlderThan).Truncate(time.Second),
+		DryRun:    opts.DryRun,
+	}
+
+	req := listResultsRequest{
+		Parent:   ns,
+		Filter:   fmt.Sprintf(`update_time<timestamp(%s)`, quoteCEL(report.Cutoff.Format(time.RFC3339))),
+		OrderBy:  "update_time asc",
+		PageSize: maxPageSize,
+	}
+	for {
+		resp, err := s.client.listResults(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resp.Results {
+			// Double check in memory in case the server ignored the filter.
+			if res.UpdateTime.IsZero() || !res.UpdateTime.Before(report.Cutoff) {
+				continue
+			}
+			if len(report.Candidates) == limit {
+				report.Truncated = true
+				break
+			}
+			report.Candidates = append(report.Candidates, PruneCandidate{Name: res.Name, UpdateTime: res.UpdateTime})
+		}
+		if report.Truncated || resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for i := range report.Candidates {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := s.client.deleteResult(ctx, report.Candidates[i].Name); err != nil {
+			report.Candidates[i].Error = err.Error()
+			report.Failed++
+		} else {
+			report.Deleted++
+		}
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(report.Candidates))
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,112 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pruneTestClient(t *testing.T, deleted *[]string) *mockRestClient {
+	now := time.Now().UTC()
+	return &mockRestClient{
+		listResultsFunc: func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error) {
+			if req.Parent != "ci" {
+				t.Errorf("Expected parent ci, got %s", req.Parent)
+			}
+			if !strings.HasPrefix(req.Filter, `update_time<timestamp("`) {
+				t.Errorf("Expected update_time filter, got %s", req.Filter)
+			}
+			if req.PageToken == "" {
+				return &listResultsResponse{
+					Results: []result{
+						{Name: "ci/results/old-1", UpdateTime: now.Add(-90 * 24 * time.Hour)},
+						{Name: "ci/results/old-2", UpdateTime: now.Add(-60 * 24 * time.Hour)},
+					},
+					NextPageToken: "page-2",
+				}, nil
+			}
+			return &listResultsResponse{
+				Results: []result{
+					{Name: "ci/results/old-3", UpdateTime: now.Add(-45 * 24 * time.Hour)},
+					// Not old enough; the server filter should have excluded it.
+					{Name: "ci/results/recent", UpdateTime: now.Add(-time.Hour)},
+				},
+			}, nil
+		},
+		deleteResultFunc: func(ctx context.Context, name string) error {
+			if deleted == nil {
+				t.Errorf("Unexpected delete of %s", name)
+				return nil
+			}
+			*deleted = append(*deleted, name)
+			if name == "ci/results/old-2" {
+				return fmt.Errorf(`results API DELETE: {"code":7,"message":"permission denied"}`)
+			}
+			return nil
+		},
+	}
+}
+
+func TestService_PruneResults_DryRun(t *testing.T) {
+	service := &Service{client: pruneTestClient(t, nil)}
+
+	report, err := service.PruneResults(context.Background(), PruneOptions{Namespace: "ci", OlderThan: 30 * 24 * time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("PruneResults() error = %v", err)
+	}
+	if len(report.Candidates) != 3 || report.Deleted != 0 || !report.DryRun {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+}
+
+func TestService_PruneResults_Delete(t *testing.T) {
+	var deleted []string
+	service := &Service{client: pruneTestClient(t, &deleted)}
+
+	var progress []int
+	report, err := service.PruneResults(context.Background(), PruneOptions{
+		Namespace: "ci",
+		OlderThan: 30 * 24 * time.Hour,
+		Progress:  func(done, total int) { progress = append(progress, done) },
+	})
+	if err != nil {
+		t.Fatalf("PruneResults() error = %v", err)
+	}
+	if len(deleted) != 3 || report.Deleted != 2 || report.Failed != 1 {
+		t.Errorf("Unexpected report %+v (deleted %v)", report, deleted)
+	}
+	if report.Candidates[1].Error == "" {
+		t.Errorf("Expected failure to be recorded on the candidate")
+	}
+	if len(progress) != 3 || progress[2] != 3 {
+		t.Errorf("Expected progress after each deletion, got %v", progress)
+	}
+}
+
+func TestService_PruneResults_Limit(t *testing.T) {
+	service := &Service{client: pruneTestClient(t, nil)}
+
+	report, err := service.PruneResults(context.Background(), PruneOptions{Namespace: "ci", OlderThan: 24 * time.Hour, DryRun: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("PruneResults() error = %v", err)
+	}
+	if len(report.Candidates) != 2 || !report.Truncated {
+		t.Errorf("Expected 2 candidates and truncation, got %+v", report)
+	}
+}
+
+func TestService_PruneResults_Validation(t *testing.T) {
+	service := &Service{client: &mockRestClient{}}
+	for _, opts := range []PruneOptions{
+		{Namespace: "", OlderThan: time.Hour},
+		{Namespace: "-", OlderThan: time.Hour},
+		{Namespace: "ci,staging", OlderThan: time.Hour},
+		{Namespace: "ci"},
+	} {
+		if _, err := service.PruneResults(context.Background(), opts); err == nil {
+			t.Errorf("Expected validation error for %+v", opts)
+		}
+	}
+}
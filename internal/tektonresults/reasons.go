@@ -0,0 +1,67 @@
+package tektonresults
+
+import "strings"
+
+// CommonReasons lists frequent reasons of the Succeeded condition set by the
+// Tekton controllers, offered as completions for reason filters.
+var CommonReasons = []string{
+	// Outcomes shared by PipelineRuns and TaskRuns.
+	"Succeeded",
+	"Failed",
+	"Running",
+	"Started",
+	"Pending",
+	"CouldntGetTask",
+	// PipelineRuns.
+	"Completed",
+	"Cancelled",
+	"CancelledRunningFinally",
+	"StoppedRunningFinally",
+	"PipelineRunTimeout",
+	"PipelineRunPending",
+	"CouldntGetPipeline",
+	"InvalidWorkspaceBindings",
+	"InvalidTaskResultReference",
+	"ParameterMissing",
+	"ParameterTypeMismatch",
+	"PipelineValidationFailed",
+	"PipelineInvalidGraph",
+	"CreateRunFailed",
+	// TaskRuns.
+	"TaskRunCancelled",
+	"TaskRunTimeout",
+	"TaskRunImagePullFailed",
+	"TaskRunResolutionFailed",
+	"TaskRunValidationFailed",
+	"ExceededNodeResources",
+	"ExceededResourceQuota",
+	"PodCreationFailed",
+	"CreateContainerConfigError",
+}
+
+// reasonFilter matches the Succeeded condition reason of a run against a
+// comma separated list of reasons, ignoring case. The empty filter matches
+// every run.
+type reasonFilter []string
+
+func parseReasonFilter(input string) reasonFilter {
+	var reasons reasonFilter
+	for _, part := range strings.Split(input, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			reasons = append(reasons, part)
+		}
+	}
+	return reasons
+}
+
+func (f reasonFilter) matches(reason string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, want := range f {
+		if strings.EqualFold(want, reason) {
+			return true
+		}
+	}
+	return false
+}
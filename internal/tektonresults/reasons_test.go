@@ -0,0 +1,66 @@
+package tektonresults
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestService_ListRuns_Reason(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords("ci",
+				testRun{Condition: "False/Succeeded"}, testRun{Condition: "False/PipelineRunTimeout"}, testRun{},
+				testRun{Condition: "False/CouldntGetTask"}, testRun{Condition: "False/Failed"})}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	tests := []struct {
+		reason string
+		want   []string
+	}{
+		{"", []string{"uid-0", "uid-1", "uid-2", "uid-3", "uid-4"}},
+		{"pipelineruntimeout", []string{"uid-1"}},
+		{"PipelineRunTimeout, CouldntGetTask", []string{"uid-1", "uid-3"}},
+		{"Cancelled", nil},
+	}
+	for _, tt := range tests {
+		summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "ci", Reason: tt.reason})
+		if err != nil {
+			t.Fatalf("ListPipelineRuns(%q) error = %v", tt.reason, err)
+		}
+		var got []string
+		for _, s := range summaries {
+			got = append(got, s.UID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ListPipelineRuns(%q) = %v, want %v", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestService_CompletionValues_Reason(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords("ci", testRun{Condition: "False/Failed"}, testRun{Condition: "False/ApprovalDenied"})}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	got, err := service.CompletionValues(context.Background(), CompleteReason, "ci")
+	if err != nil {
+		t.Fatalf("CompletionValues() error = %v", err)
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("Expected sorted values, got %v", got)
+	}
+	for _, want := range []string{"ApprovalDenied", "CouldntGetTask", "PipelineRunTimeout", "TaskRunTimeout"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Expected %s among %v", want, got)
+		}
+	}
+	if n := len(got); n != len(CommonReasons)+1 {
+		t.Errorf("Expected common reasons plus one custom reason, got %d values", n)
+	}
+}
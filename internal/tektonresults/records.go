@@ -0,0 +1,115 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxResultRecords bounds the records listed for a single Result. Results
+// hold a few records per TaskRun, so this only trips on pathological runs.
+const maxResultRecords = 1000
+
+// RecordInfo describes one record stored under a Result.
+type RecordInfo struct {
+	Name       string    `json:"name"`
+	UID        string    `json:"uid,omitempty"`
+	Type       string    `json:"type"`                 // data_type, e.g. tekton.dev/v1.TaskRun or results.tekton.dev/v1alpha3.Log
+	Size       int       `json:"size"`                 // bytes of stored data
+	Kind       string    `json:"kind,omitempty"`       // kind of the stored object, when it has one
+	ObjectName string    `json:"objectName,omitempty"` // metadata.name of the stored object
+	LogSize    int64     `json:"logSize,omitempty"`    // bytes of log content, for log records
+	CreateTime time.Time `json:"createTime,omitempty"`
+	UpdateTime time.Time `json:"updateTime,omitempty"`
+}
+
+// ResultRecords lists the records stored under one Result.
+type ResultRecords struct {
+	Result    string         `json:"result"`
+	Records   []RecordInfo   `json:"records"`
+	Types     map[string]int `json:"types"`               // record count per type
+	Truncated bool           `json:"truncated,omitempty"` // more than maxResultRecords records exist
+}
+
+// ListResultRecords lists every record under a Result: run manifests, log
+// metadata, events and any custom types. name is either a Result name
+// ("<namespace>/results/<result>") or the name of any record under it.
+func (s *Service) ListResultRecords(ctx context.Context, name string) (*ResultRecords, error) {
+	name = strings.Trim(strings.TrimSpace(name), "/")
+	resultName := name
+	if strings.Contains(name, "/records/") {
+		resultName, _ = splitRecordName(name)
+	}
+	namespace, id, found := strings.Cut(resultName, "/results/")
+	if !found || namespace == "-" || id == "-" || len(nameSegments(resultName)) != 3 {
+		return nil, fmt.Errorf("invalid name %q: expected <namespace>/results/<result> or a record name under it", name)
+	}
+
+	out := &ResultRecords{Result: resultName, Records: []RecordInfo{}, Types: map[string]int{}}
+	req := listRecordsRequest{Parent: resultName, PageSize: maxPageSize}
+	for {
+		resp, err := s.client.listRecords(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		s.runs.observe(resp.Records)
+		for _, rec := range resp.Records {
+			if len(out.Records) == maxResultRecords {
+				out.Truncated = true
+				break
+			}
+			info := describeRecord(rec)
+			out.Records = append(out.Records, info)
+			out.Types[info.Type]++
+		}
+		if out.Truncated || resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	sort.SliceStable(out.Records, func(i, j int) bool {
+		return out.Records[i].CreateTime.Before(out.Records[j].CreateTime)
+	})
+	return out, nil
+}
+
+// describeRecord summarizes a record without keeping its data. Records whose
+// data cannot be decoded are still listed with their stored size.
+func describeRecord(rec record) RecordInfo {
+	info := RecordInfo{
+		Name:       rec.Name,
+		UID:        rec.Uid,
+		Type:       rec.Data.Type,
+		Size:       len(rec.Data.Value),
+		CreateTime: rec.CreateTime,
+		UpdateTime: rec.UpdateTime,
+	}
+	if info.Type == "" {
+		info.Type = "unknown"
+	}
+	value, err := rec.GetValue()
+	if err != nil {
+		return info
+	}
+	info.Size = len(value)
+	var object struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			Size int64 `json:"size"`
+		} `json:"status"`
+	}
+	if json.Unmarshal(value, &object) == nil {
+		info.Kind = object.Kind
+		info.ObjectName = object.Metadata.Name
+		if strings.HasSuffix(info.Type, ".Log") {
+			info.LogSize = object.Status.Size
+		}
+	}
+	return info
+}
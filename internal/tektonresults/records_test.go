@@ -0,0 +1,102 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_ListResultRecords(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRecord := func(id, dataType, value string, created time.Time) record {
+		rec := record{Name: "ci/results/pr-1/records/" + id, Uid: id, CreateTime: created}
+		rec.Data.Type = dataType
+		encoded, _ := json.Marshal(base64.StdEncoding.EncodeToString([]byte(value)))
+		rec.Data.Value = encoded
+		return rec
+	}
+	pipelineRun := `{"kind":"PipelineRun","metadata":{"name":"build-x7k2p"}}`
+	logValue := `{"kind":"Log","metadata":{"name":"build-x7k2p-compile-log"},"status":{"path":"logs/x","size":4096}}`
+
+	var parents []string
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			parents = append(parents, req.Parent)
+			if req.Filter != "" {
+				t.Errorf("Expected no filter, got %s", req.Filter)
+			}
+			if req.PageToken == "" {
+				return &listRecordsResponse{
+					Records: []record{
+						newRecord("log-1", "results.tekton.dev/v1alpha3.Log", logValue, base.Add(2*time.Minute)),
+						newRecord("pr-1", "tekton.dev/v1.PipelineRun", pipelineRun, base),
+					},
+					NextPageToken: "next",
+				}, nil
+			}
+			events := newRecord("ev-1", "dev.example/v1.AuditEvent", `not json`, base.Add(time.Minute))
+			return &listRecordsResponse{Records: []record{events}}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	got, err := service.ListResultRecords(context.Background(), "ci/results/pr-1/records/log-1")
+	if err != nil {
+		t.Fatalf("ListResultRecords() error = %v", err)
+	}
+	if got.Result != "ci/results/pr-1" || parents[0] != "ci/results/pr-1" || len(parents) != 2 {
+		t.Errorf("Expected every page of the parent Result to be listed, got %s via %v", got.Result, parents)
+	}
+	if len(got.Records) != 3 || got.Records[0].UID != "pr-1" || got.Records[1].UID != "ev-1" || got.Records[2].UID != "log-1" {
+		t.Fatalf("Expected records ordered by creation time, got %+v", got.Records)
+	}
+	pr, custom, log := got.Records[0], got.Records[1], got.Records[2]
+	if pr.Kind != "PipelineRun" || pr.ObjectName != "build-x7k2p" || pr.Size != len(pipelineRun) {
+		t.Errorf("Unexpected PipelineRun record: %+v", pr)
+	}
+	if log.LogSize != 4096 || log.ObjectName != "build-x7k2p-compile-log" {
+		t.Errorf("Unexpected log record: %+v", log)
+	}
+	if custom.Kind != "" || custom.Size != len("not json") || custom.Type != "dev.example/v1.AuditEvent" {
+		t.Errorf("Unexpected custom record: %+v", custom)
+	}
+	if got.Types["tekton.dev/v1.PipelineRun"] != 1 || got.Types["results.tekton.dev/v1alpha3.Log"] != 1 || len(got.Types) != 3 {
+		t.Errorf("Unexpected type counts: %v", got.Types)
+	}
+}
+
+func TestService_ListResultRecords_Truncated(t *testing.T) {
+	pages := 0
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			pages++
+			var records []record
+			for i := 0; i < int(req.PageSize); i++ {
+				records = append(records, record{Name: fmt.Sprintf("ci/results/r/records/%d-%d", pages, i)})
+			}
+			return &listRecordsResponse{Records: records, NextPageToken: "more"}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	got, err := service.ListResultRecords(context.Background(), "ci/results/r")
+	if err != nil {
+		t.Fatalf("ListResultRecords() error = %v", err)
+	}
+	if !got.Truncated || len(got.Records) != maxResultRecords || got.Types["unknown"] != maxResultRecords {
+		t.Errorf("Expected %d records and truncation, got %d truncated=%v", maxResultRecords, len(got.Records), got.Truncated)
+	}
+}
+
+func TestService_ListResultRecords_InvalidName(t *testing.T) {
+	service := &Service{client: &mockRestClient{}}
+	for _, name := range []string{"", "ci", "ci/results/", "-/results/-", "ci/results/a/b", "ci/records/x", "../../api/v1/results/x"} {
+		if _, err := service.ListResultRecords(context.Background(), name); err == nil || !strings.Contains(err.Error(), "invalid name") {
+			t.Errorf("ListResultRecords(%q): expected invalid name error, got %v", name, err)
+		}
+	}
+}
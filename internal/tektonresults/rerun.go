@@ -0,0 +1,139 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Annotations the server sets on the PipelineRuns it creates, pointing back
+// to the archived run they repeat.
+const (
+	rerunOfAnnotation    = "tekton-results-mcp-server/rerun-of"     // <namespace>/<name>
+	rerunOfUIDAnnotation = "tekton-results-mcp-server/rerun-of-uid" // UID of the archived run
+)
+
+// RerunResult describes the PipelineRun created by RerunPipelineRun.
+type RerunResult struct {
+	Name         string     `json:"name"`
+	Namespace    string     `json:"namespace"`
+	UID          string     `json:"uid,omitempty"`
+	DashboardURL string     `json:"dashboardUrl,omitempty"`
+	RerunOf      RunSummary `json:"rerunOf"`
+}
+
+// RerunPipelineRun creates a new PipelineRun in the cluster from the spec of
+// the archived PipelineRun matching selector. The new run gets a generated
+// name; its status, the spec.status that cancelled or pended the original,
+// and the labels and annotations Tekton and its integrations manage are
+// dropped, so controllers treat it as a fresh run. Pipeline references are
+// resolved again, so the run uses the current Pipeline definition.
+func (s *Service) RerunPipelineRun(ctx context.Context, selector RunSelector) (*RerunResult, error) {
+	if s.cluster == nil {
+		return nil, fmt.Errorf("re-running requires access to the Kubernetes API")
+	}
+	detail, err := s.GetPipelineRun(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := rerunManifest(detail)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("encode PipelineRun: %w", err)
+	}
+
+	var created struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+			UID       string `json:"uid"`
+		} `json:"metadata"`
+	}
+	apiPath := fmt.Sprintf("/apis/%s/namespaces/%s/pipelineruns", manifest["apiVersion"], url.PathEscape(detail.Summary.Namespace))
+	found, err := s.cluster.do(ctx, http.MethodPost, apiPath, "application/json", body, &created)
+	if err != nil {
+		return nil, fmt.Errorf("create PipelineRun in %s: %w", detail.Summary.Namespace, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("create PipelineRun in %s: the PipelineRun API %s is not served by the cluster", detail.Summary.Namespace, manifest["apiVersion"])
+	}
+	return &RerunResult{
+		Name:         created.Metadata.Name,
+		Namespace:    created.Metadata.Namespace,
+		UID:          created.Metadata.UID,
+		DashboardURL: s.dashboard.URL("PipelineRun", created.Metadata.Namespace, created.Metadata.Name, created.Metadata.UID),
+		RerunOf:      detail.Summary,
+	}, nil
+}
+
+// rerunManifest builds the PipelineRun that repeats an archived one.
+func rerunManifest(detail *RunDetail) (map[string]any, error) {
+	var run struct {
+		APIVersion string `json:"apiVersion"`
+		Metadata   struct {
+			Name         string            `json:"name"`
+			GenerateName string            `json:"generateName"`
+			Labels       map[string]string `json:"labels"`
+			Annotations  map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec map[string]any `json:"spec"`
+	}
+	if err := json.Unmarshal(detail.Raw, &run); err != nil {
+		return nil, fmt.Errorf("decode PipelineRun %s: %w", detail.Summary.Name, err)
+	}
+	if len(run.Spec) == 0 {
+		return nil, fmt.Errorf("the archived PipelineRun %s/%s has no spec to run again", detail.Summary.Namespace, detail.Summary.Name)
+	}
+	apiVersion := run.APIVersion
+	if !strings.HasPrefix(apiVersion, "tekton.dev/") {
+		apiVersion = "tekton.dev/v1"
+	}
+	generateName := run.Metadata.GenerateName
+	if generateName == "" {
+		generateName = run.Metadata.Name + "-"
+	}
+	delete(run.Spec, "status")
+
+	annotations := userKeys(run.Metadata.Annotations)
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	annotations[rerunOfAnnotation] = detail.Summary.Namespace + "/" + detail.Summary.Name
+	if detail.Summary.UID != "" {
+		annotations[rerunOfUIDAnnotation] = detail.Summary.UID
+	}
+	metadata := map[string]any{
+		"generateName": generateName,
+		"namespace":    detail.Summary.Namespace,
+		"annotations":  annotations,
+	}
+	if labels := userKeys(run.Metadata.Labels); len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	return map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       "PipelineRun",
+		"metadata":   metadata,
+		"spec":       run.Spec,
+	}, nil
+}
+
+// userKeys returns the labels or annotations not managed by Tekton or its
+// integrations (tekton.dev, results.tekton.dev, pipelinesascode.tekton.dev,
+// triggers.tekton.dev, chains.tekton.dev and so on), which would otherwise
+// tie the new run to the old run's Result, trigger or signatures.
+func userKeys(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		domain, _, ok := strings.Cut(key, "/")
+		if ok && (domain == "tekton.dev" || strings.HasSuffix(domain, ".tekton.dev")) {
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+const archivedNightly = `{
+	"apiVersion": "tekton.dev/v1",
+	"kind": "PipelineRun",
+	"metadata": {
+		"name": "nightly-x7k2p",
+		"generateName": "nightly-",
+		"namespace": "ci",
+		"uid": "pr-uid",
+		"labels": {"app": "web", "tekton.dev/pipeline": "nightly", "results.tekton.dev/result": "r", "pipelinesascode.tekton.dev/sha": "abc"},
+		"annotations": {"owner": "payments", "results.tekton.dev/record": "rec", "kubectl.kubernetes.io/last-applied-configuration": "{}"}
+	},
+	"spec": {"pipelineRef": {"name": "nightly"}, "params": [{"name": "revision", "value": "main"}], "status": "Cancelled"},
+	"status": {"conditions": [{"type": "Succeeded", "status": "False", "reason": "Failed"}]}
+}`
+
+func nightlyRecords() *mockRestClient {
+	return &mockRestClient{
+		getRecordFunc: func(ctx context.Context, recordName string) (*record, error) {
+			rec := &record{Name: recordName, Uid: "pr-uid"}
+			rec.Data.Value = json.RawMessage(archivedNightly)
+			return rec, nil
+		},
+	}
+}
+
+func TestRerunPipelineRun(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/apis/tekton.dev/v1/namespaces/ci/pipelineruns" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &created); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		fmt.Fprint(w, `{"metadata":{"name":"nightly-q9z4d","namespace":"ci","uid":"new-uid"}}`)
+	}))
+	defer server.Close()
+
+	cluster, _ := newClusterClient(&rest.Config{Host: server.URL})
+	svc := &Service{client: nightlyRecords(), cluster: cluster}
+	result, err := svc.RerunPipelineRun(context.Background(), RunSelector{Namespace: "ci", UID: "pr-uid"})
+	if err != nil {
+		t.Fatalf("RerunPipelineRun() error = %v", err)
+	}
+	if result.Name != "nightly-q9z4d" || result.UID != "new-uid" || result.RerunOf.Name != "nightly-x7k2p" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	metadata := created["metadata"].(map[string]any)
+	if metadata["generateName"] != "nightly-" || metadata["name"] != nil {
+		t.Errorf("Expected a generated name, got metadata %v", metadata)
+	}
+	labels := metadata["labels"].(map[string]any)
+	if len(labels) != 1 || labels["app"] != "web" {
+		t.Errorf("Expected only user labels, got %v", labels)
+	}
+	annotations := metadata["annotations"].(map[string]any)
+	if len(annotations) != 3 || annotations["owner"] != "payments" || annotations[rerunOfAnnotation] != "ci/nightly-x7k2p" || annotations[rerunOfUIDAnnotation] != "pr-uid" {
+		t.Errorf("Unexpected annotations %v", annotations)
+	}
+	spec := created["spec"].(map[string]any)
+	if _, ok := spec["status"]; ok || spec["pipelineRef"] == nil || spec["params"] == nil {
+		t.Errorf("Expected the spec without spec.status, got %v", spec)
+	}
+	if _, ok := created["status"]; ok {
+		t.Error("The status of the archived run must not be copied")
+	}
+}
+
+func TestRerunPipelineRun_Errors(t *testing.T) {
+	svc := &Service{client: nightlyRecords()}
+	if _, err := svc.RerunPipelineRun(context.Background(), RunSelector{Namespace: "ci", UID: "pr-uid"}); err == nil || !strings.Contains(err.Error(), "Kubernetes API") {
+		t.Errorf("Expected an error without cluster access, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"kind":"Status","message":"pipelineruns.tekton.dev is forbidden"}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+	svc.cluster, _ = newClusterClient(&rest.Config{Host: server.URL})
+	if _, err := svc.RerunPipelineRun(context.Background(), RunSelector{Namespace: "ci", UID: "pr-uid"}); err == nil || !strings.Contains(err.Error(), "grant the server create access") {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+}
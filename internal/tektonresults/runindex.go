@@ -0,0 +1,75 @@
+package tektonresults
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxRunIndexEntries bounds the run index; the oldest entries are evicted
+// first.
+const maxRunIndexEntries = 4096
+
+// runIndex remembers the record names of runs stored under another run's
+// Result, typically TaskRuns of a PipelineRun. Looking such a run up by UID
+// cannot guess its record name and falls back to a namespace wide search; the
+// index, filled from list responses, lets repeated lookups fetch the record
+// directly. The zero value is ready to use.
+type runIndex struct {
+	mu      sync.Mutex
+	records map[string]string // run UID -> record name
+	order   []string          // UIDs in insertion order, for eviction
+	counter cacheCounter
+}
+
+// observe indexes the records of a list response. Records stored under a
+// Result of their own are skipped, since their name follows from the UID.
+func (x *runIndex) observe(records []record) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, rec := range records {
+		_, resultID := splitRecordName(rec.Name)
+		_, uid, _ := strings.Cut(rec.Name, "/records/")
+		if resultID == "" || uid == "" || uid == resultID {
+			continue
+		}
+		if x.records == nil {
+			x.records = map[string]string{}
+		}
+		if _, ok := x.records[uid]; !ok {
+			x.order = append(x.order, uid)
+		}
+		x.records[uid] = rec.Name
+		for len(x.order) > maxRunIndexEntries {
+			delete(x.records, x.order[0])
+			x.order = x.order[1:]
+		}
+	}
+}
+
+// lookup returns the record name of the run with uid, if it was indexed in
+// namespace ("-" or empty matches any namespace).
+func (x *runIndex) lookup(namespace, uid string) (string, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	name, ok := x.records[uid]
+	if ok && namespace != "" && namespace != "-" && !strings.HasPrefix(name, namespace+"/results/") {
+		ok = false
+	}
+	x.counter.observe(ok)
+	if !ok {
+		return "", false
+	}
+	return name, true
+}
+
+// forget drops a stale entry, e.g. after its Result was deleted.
+func (x *runIndex) forget(uid string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.records[uid]; !ok {
+		return
+	}
+	delete(x.records, uid)
+	x.order = slices.DeleteFunc(x.order, func(u string) bool { return u == uid })
+}
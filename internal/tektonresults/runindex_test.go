@@ -0,0 +1,128 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// nestedTaskRun returns a TaskRun record stored under the Result of
+// PipelineRun prUID.
+func nestedTaskRun(namespace, prUID, trUID string) record {
+	rec := record{Name: fmt.Sprintf("%s/results/%s/records/%s", namespace, prUID, trUID), Uid: trUID}
+	rec.Data.Type = "tekton.dev/v1.TaskRun"
+	rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"kind":"TaskRun","metadata":{"name":"tr-%s","namespace":"%s","uid":"%s"}}`, trUID, namespace, trUID))
+	return rec
+}
+
+func TestRunIndex(t *testing.T) {
+	var x runIndex
+	x.observe([]record{
+		nestedTaskRun("ci", "pr-1", "tr-1"),
+		{Name: "ci/results/pr-1/records/pr-1"}, // has a Result of its own
+		{Name: "malformed"},
+	})
+
+	if got, ok := x.lookup("ci", "tr-1"); !ok || got != "ci/results/pr-1/records/tr-1" {
+		t.Errorf("lookup(ci, tr-1) = %q, %v", got, ok)
+	}
+	if _, ok := x.lookup("-", "tr-1"); !ok {
+		t.Error("Expected '-' to match any namespace")
+	}
+	if _, ok := x.lookup("dev", "tr-1"); ok {
+		t.Error("Expected no match in another namespace")
+	}
+	if _, ok := x.lookup("ci", "pr-1"); ok {
+		t.Error("Runs with a Result of their own must not be indexed")
+	}
+
+	x.forget("tr-1")
+	if _, ok := x.lookup("ci", "tr-1"); ok {
+		t.Error("Expected forgotten entry to be gone")
+	}
+}
+
+func TestRunIndex_Evicts(t *testing.T) {
+	var x runIndex
+	for i := range maxRunIndexEntries + 10 {
+		x.observe([]record{nestedTaskRun("ci", "pr", fmt.Sprintf("tr-%d", i))})
+	}
+	if len(x.records) != maxRunIndexEntries || len(x.order) != maxRunIndexEntries {
+		t.Errorf("Expected %d entries, got %d (order %d)", maxRunIndexEntries, len(x.records), len(x.order))
+	}
+	if _, ok := x.lookup("ci", "tr-0"); ok {
+		t.Error("Expected the oldest entry to be evicted")
+	}
+	if _, ok := x.lookup("ci", fmt.Sprintf("tr-%d", maxRunIndexEntries+9)); !ok {
+		t.Error("Expected the newest entry to be kept")
+	}
+}
+
+func TestService_GetTaskRun_UsesRunIndex(t *testing.T) {
+	var fetched []string
+	searches := 0
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			searches++
+			return &listRecordsResponse{Records: []record{nestedTaskRun("ci", "pr-1", "tr-1")}}, nil
+		},
+		getRecordFunc: func(ctx context.Context, recordName string) (*record, error) {
+			fetched = append(fetched, recordName)
+			if recordName == "ci/results/pr-1/records/tr-1" {
+				rec := nestedTaskRun("ci", "pr-1", "tr-1")
+				return &rec, nil
+			}
+			return nil, fmt.Errorf(`results API GET %s: {"code":5,"message":"record not found"}`, recordName)
+		},
+	}
+	service := &Service{client: mockClient}
+	ctx := context.Background()
+
+	// The first lookup has to search, and indexes what it sees.
+	if _, err := service.GetTaskRun(ctx, RunSelector{Namespace: "ci", UID: "tr-1"}); err != nil {
+		t.Fatalf("GetTaskRun() error = %v", err)
+	}
+	if searches != 1 {
+		t.Fatalf("Expected the first lookup to search, got %d searches", searches)
+	}
+
+	fetched = nil
+	detail, err := service.GetTaskRun(ctx, RunSelector{Namespace: "ci", UID: "tr-1"})
+	if err != nil {
+		t.Fatalf("GetTaskRun() error = %v", err)
+	}
+	if searches != 1 || len(fetched) != 1 || fetched[0] != "ci/results/pr-1/records/tr-1" {
+		t.Errorf("Expected a direct fetch of the indexed record, got fetches %v and %d searches", fetched, searches)
+	}
+	if detail.RecordName != "ci/results/pr-1/records/tr-1" {
+		t.Errorf("Unexpected record name %s", detail.RecordName)
+	}
+}
+
+func TestService_GetTaskRun_StaleRunIndex(t *testing.T) {
+	searches := 0
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			searches++
+			return &listRecordsResponse{}, nil
+		},
+		getRecordFunc: func(ctx context.Context, recordName string) (*record, error) {
+			return nil, fmt.Errorf(`results API GET %s: {"code":5,"message":"record not found"}`, recordName)
+		},
+	}
+	service := &Service{client: mockClient}
+	service.runs.observe([]record{nestedTaskRun("ci", "pr-1", "tr-1")})
+
+	_, err := service.GetTaskRun(context.Background(), RunSelector{Namespace: "ci", UID: "tr-1"})
+	if err == nil || !strings.Contains(err.Error(), "no run found") {
+		t.Errorf("Expected the search fallback to report no run, got %v", err)
+	}
+	if searches != 1 {
+		t.Errorf("Expected a stale entry to fall back to searching, got %d searches", searches)
+	}
+	if _, ok := service.runs.lookup("ci", "tr-1"); ok {
+		t.Error("Expected the stale entry to be dropped")
+	}
+}
@@ -0,0 +1,105 @@
+package tektonresults
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// RunParam is a parameter value a run executed with.
+type RunParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Value is a JSON string for string parameters, an array of strings for
+	// array parameters and an object of strings for object parameters.
+	Value json.RawMessage `json:"value"`
+	// Default is set when the run did not pass the parameter and it took
+	// the default the Pipeline declares.
+	Default     bool   `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Params returns the parameters a PipelineRun executed with: those of
+// spec.params in their order, then the parameters of the resolved Pipeline
+// in status.pipelineSpec that the run left to their default. The second
+// result reports whether the resolved Pipeline was recorded; without it
+// defaults are unknown and only spec.params are returned.
+func (d RunDetail) Params() ([]RunParam, bool) {
+	var run struct {
+		Spec struct {
+			Params []struct {
+				Name  string          `json:"name"`
+				Value json.RawMessage `json:"value"`
+			} `json:"params"`
+		} `json:"spec"`
+		Status struct {
+			PipelineSpec *struct {
+				Params []struct {
+					Name        string          `json:"name"`
+					Type        string          `json:"type"`
+					Description string          `json:"description"`
+					Default     json.RawMessage `json:"default"`
+				} `json:"params"`
+			} `json:"pipelineSpec"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil, false
+	}
+
+	type declared struct{ typ, description string }
+	decls := map[string]declared{}
+	resolved := run.Status.PipelineSpec != nil
+	if resolved {
+		for _, p := range run.Status.PipelineSpec.Params {
+			decls[p.Name] = declared{p.Type, p.Description}
+		}
+	}
+	var params []RunParam
+	set := map[string]bool{}
+	for _, p := range run.Spec.Params {
+		set[p.Name] = true
+		d := decls[p.Name]
+		params = append(params, newRunParam(p.Name, d.typ, p.Value, d.description))
+	}
+	if resolved {
+		for _, p := range run.Status.PipelineSpec.Params {
+			if set[p.Name] || len(p.Default) == 0 {
+				continue
+			}
+			param := newRunParam(p.Name, p.Type, p.Default, p.Description)
+			param.Default = true
+			params = append(params, param)
+		}
+	}
+	return params, resolved
+}
+
+func newRunParam(name, typ string, value json.RawMessage, description string) RunParam {
+	value = normalizeValue(value)
+	if typ == "" {
+		typ = valueType(value)
+	}
+	return RunParam{Name: name, Type: typ, Value: value, Description: description}
+}
+
+// normalizeValue returns value with missing and null values as an empty
+// string.
+func normalizeValue(value json.RawMessage) json.RawMessage {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return json.RawMessage(`""`)
+	}
+	return value
+}
+
+// valueType infers the Tekton type of a normalized value.
+func valueType(value json.RawMessage) string {
+	switch value[0] {
+	case '[':
+		return ResultTypeArray
+	case '{':
+		return ResultTypeObject
+	default:
+		return ResultTypeString
+	}
+}
@@ -0,0 +1,38 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRunDetail_Params(t *testing.T) {
+	raw := `{"kind":"PipelineRun",
+		"spec":{"params":[{"name":"revision","value":"main"},{"name":"tags","value":["v1","latest"]},{"name":"extra","value":"x"}]},
+		"status":{"pipelineSpec":{"params":[
+			{"name":"revision","type":"string","description":"Git revision"},
+			{"name":"tags","type":"array"},
+			{"name":"skip-tests","type":"string","default":"false","description":"Skip the test task"},
+			{"name":"required"}
+		]}}}`
+	params, resolved := RunDetail{Raw: json.RawMessage(raw)}.Params()
+	if !resolved || len(params) != 4 {
+		t.Fatalf("Expected 4 resolved params, got %v %+v", resolved, params)
+	}
+	if p := params[0]; p.Name != "revision" || p.Type != ResultTypeString || string(p.Value) != `"main"` || p.Default || p.Description != "Git revision" {
+		t.Errorf("Unexpected passed param %+v", p)
+	}
+	if p := params[1]; p.Type != ResultTypeArray || string(p.Value) != `["v1","latest"]` {
+		t.Errorf("Unexpected array param %+v", p)
+	}
+	if p := params[2]; p.Name != "extra" || p.Type != ResultTypeString {
+		t.Errorf("Expected an undeclared param to keep its inferred type, got %+v", p)
+	}
+	if p := params[3]; p.Name != "skip-tests" || !p.Default || string(p.Value) != `"false"` {
+		t.Errorf("Unexpected default param %+v", p)
+	}
+
+	params, resolved = RunDetail{Raw: json.RawMessage(`{"spec":{"params":[{"name":"revision","value":"main"}]},"status":{}}`)}.Params()
+	if resolved || len(params) != 1 || params[0].Type != ResultTypeString {
+		t.Errorf("Expected only the passed params without a resolved Pipeline, got %v %+v", resolved, params)
+	}
+}
@@ -0,0 +1,113 @@
+package tektonresults
+
+import (
+	"encoding/json"
+)
+
+// Types of a run result or parameter value, as Tekton names them.
+const (
+	ResultTypeString = "string"
+	ResultTypeArray  = "array"
+	ResultTypeObject = "object"
+)
+
+// RunResult is a result a run emitted, such as the IMAGE_DIGEST of a build.
+type RunResult struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Value is a JSON string for string results, an array of strings for
+	// array results and an object of strings for object results.
+	Value json.RawMessage `json:"value"`
+}
+
+// String returns the value of a string result, and the value as JSON for
+// array and object results.
+func (r RunResult) String() string {
+	var s string
+	if err := json.Unmarshal(r.Value, &s); err == nil {
+		return s
+	}
+	return string(r.Value)
+}
+
+// Results returns the results a run emitted, in the order it reports them:
+// status.results of v1 runs, or status.pipelineResults and
+// status.taskResults of v1beta1 PipelineRuns and TaskRuns. It returns nil
+// for runs that emitted none, such as runs that failed before their results
+// were written.
+func (d RunDetail) Results() []RunResult {
+	var run struct {
+		Status struct {
+			Results         []rawRunResult `json:"results"`
+			PipelineResults []rawRunResult `json:"pipelineResults"`
+			TaskResults     []rawRunResult `json:"taskResults"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil
+	}
+	raw := run.Status.Results
+	if len(raw) == 0 {
+		raw = append(run.Status.PipelineResults, run.Status.TaskResults...)
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	results := make([]RunResult, 0, len(raw))
+	for _, r := range raw {
+		results = append(results, r.result())
+	}
+	return results
+}
+
+// StepResults are the results one step of a TaskRun emitted.
+type StepResults struct {
+	Step    string      `json:"step"`
+	Results []RunResult `json:"results"`
+}
+
+// StepResults returns the results the steps of a TaskRun emitted, from
+// status.steps[].results, in step order. Steps without results are left out,
+// and it returns nil when no step emitted any, as for TaskRuns of Tekton
+// releases before step results.
+func (d RunDetail) StepResults() []StepResults {
+	var run struct {
+		Status struct {
+			Steps []struct {
+				Name    string         `json:"name"`
+				Results []rawRunResult `json:"results"`
+			} `json:"steps"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil
+	}
+	var steps []StepResults
+	for _, step := range run.Status.Steps {
+		if len(step.Results) == 0 {
+			continue
+		}
+		results := make([]RunResult, 0, len(step.Results))
+		for _, r := range step.Results {
+			results = append(results, r.result())
+		}
+		steps = append(steps, StepResults{Step: step.Name, Results: results})
+	}
+	return steps
+}
+
+type rawRunResult struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (r rawRunResult) result() RunResult {
+	value := normalizeValue(r.Value)
+	// TaskRuns record the type; PipelineRun results only carry the value.
+	typ := r.Type
+	if typ == "" {
+		typ = valueType(value)
+	}
+	return RunResult{Name: r.Name, Type: typ, Value: value}
+}
@@ -0,0 +1,54 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRunDetail_Results(t *testing.T) {
+	raw := `{"kind":"PipelineRun","status":{"results":[
+		{"name":"IMAGE_DIGEST","value":"sha256:abc"},
+		{"name":"TAGS","value":["v1","latest"]},
+		{"name":"SBOM","value":{"url":"oci://sbom","format":"spdx"}},
+		{"name":"EMPTY"}
+	]}}`
+	results := RunDetail{Raw: json.RawMessage(raw)}.Results()
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %+v", results)
+	}
+	if results[0].Type != ResultTypeString || results[0].String() != "sha256:abc" {
+		t.Errorf("Unexpected string result %+v", results[0])
+	}
+	if results[1].Type != ResultTypeArray || results[1].String() != `["v1","latest"]` {
+		t.Errorf("Unexpected array result %+v", results[1])
+	}
+	if results[2].Type != ResultTypeObject {
+		t.Errorf("Unexpected object result %+v", results[2])
+	}
+	if results[3].Type != ResultTypeString || results[3].String() != "" {
+		t.Errorf("Expected a missing value to read as an empty string, got %+v", results[3])
+	}
+
+	v1beta1 := `{"kind":"TaskRun","status":{"taskResults":[{"name":"commit","type":"string","value":"3f2a1c9"}]}}`
+	if results := (RunDetail{Raw: json.RawMessage(v1beta1)}).Results(); len(results) != 1 || results[0].String() != "3f2a1c9" {
+		t.Errorf("Expected v1beta1 task results, got %+v", results)
+	}
+	if results := (RunDetail{Raw: json.RawMessage(`{"status":{}}`)}).Results(); results != nil {
+		t.Errorf("Expected no results, got %+v", results)
+	}
+}
+
+func TestRunDetail_StepResults(t *testing.T) {
+	raw := `{"kind":"TaskRun","status":{"steps":[
+		{"name":"clone","results":[{"name":"commit","type":"string","value":"3f2a1c9"}]},
+		{"name":"build"},
+		{"name":"scan","results":[{"name":"findings","type":"array","value":["CVE-1"]}]}
+	]}}`
+	steps := RunDetail{Raw: json.RawMessage(raw)}.StepResults()
+	if len(steps) != 2 || steps[0].Step != "clone" || steps[0].Results[0].String() != "3f2a1c9" || steps[1].Step != "scan" || steps[1].Results[0].Type != ResultTypeArray {
+		t.Errorf("Unexpected step results %+v", steps)
+	}
+	if steps := (RunDetail{Raw: json.RawMessage(`{"status":{"steps":[{"name":"build"}]}}`)}).StepResults(); steps != nil {
+		t.Errorf("Expected no step results, got %+v", steps)
+	}
+}
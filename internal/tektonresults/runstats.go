@@ -0,0 +1,228 @@
+package tektonresults
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// DefaultMaxStatsRuns bounds the runs one aggregation reads when
+// StatsOptions.MaxRuns is zero.
+const DefaultMaxStatsRuns = 5000
+
+// StatsOptions selects the runs PipelineRunStats and TaskRunStats aggregate. Limit,
+// PageToken and OrderBy of the embedded ListOptions are ignored.
+type StatsOptions struct {
+	ListOptions
+	MaxRuns int // runs read before the statistics are reported as truncated; zero uses DefaultMaxStatsRuns
+}
+
+// RunStats summarizes the outcomes and durations of a set of runs. Rates and
+// durations are computed over finished runs: those that succeeded, failed or
+// timed out. Cancelled and running runs are only counted.
+type RunStats struct {
+	Runs        int            `json:"runs"`
+	Succeeded   int            `json:"succeeded"`
+	Failed      int            `json:"failed"`
+	TimedOut    int            `json:"timedOut"`
+	Cancelled   int            `json:"cancelled"`
+	Running     int            `json:"running"`
+	Unknown     int            `json:"unknown,omitempty"`    // stored before the run reported a status
+	SuccessRate *float64       `json:"successRate"`          // succeeded / finished; null without finished runs
+	FailureRate *float64       `json:"failureRate"`          // failed or timed out / finished
+	Duration    *DurationStats `json:"duration,omitempty"`   // unset without finished runs of known duration
+	Truncated   bool           `json:"truncated,omitempty"`  // more than MaxRuns runs matched; the statistics cover the first MaxRuns, newest first per namespace
+	MaxRuns     int            `json:"maxRuns,omitempty"`    // set when Truncated
+	Namespaces  []string       `json:"namespaces,omitempty"` // namespaces listed, when more than one
+}
+
+// DurationStats describes how long runs took, in seconds.
+type DurationStats struct {
+	Samples        int     `json:"samples"`
+	AverageSeconds float64 `json:"averageSeconds"`
+	MedianSeconds  float64 `json:"medianSeconds"`
+	P95Seconds     float64 `json:"p95Seconds"`
+	MinSeconds     float64 `json:"minSeconds"`
+	MaxSeconds     float64 `json:"maxSeconds"`
+}
+
+// TaskRunStats is RunStats over TaskRuns, broken down by task.
+type TaskRunStats struct {
+	*RunStats
+	Tasks []TaskStats `json:"tasks"` // ordered by task name
+}
+
+// TaskStats is the RunStats of the TaskRuns of one task.
+type TaskStats struct {
+	Task string `json:"task"` // pipeline task name, or the referenced Task for runs outside a pipeline
+	*RunStats
+}
+
+// UnnamedTask groups the TaskRuns TaskRunStats cannot name: runs outside a
+// pipeline with an embedded task spec.
+const UnnamedTask = "(unnamed)"
+
+// PipelineRunStats pages through the summaries of the PipelineRuns matching
+// opts and aggregates their outcomes and durations.
+func (s *Service) PipelineRunStats(ctx context.Context, opts StatsOptions) (*RunStats, error) {
+	return aggregateRuns(ctx, func(ctx context.Context, opts ListOptions) (*RunPage, error) {
+		return s.listRunPage(ctx, resourceKindPipelineRun, opts)
+	}, opts, nil)
+}
+
+// TaskRunStats pages through the summaries of the TaskRuns matching opts and
+// aggregates their outcomes and durations, overall and per task. A TaskRun
+// belongs to its pipeline task, from the tekton.dev/pipelineTask label, or
+// else to the Task it references.
+func (s *Service) TaskRunStats(ctx context.Context, opts StatsOptions) (*TaskRunStats, error) {
+	return aggregateTaskRuns(ctx, func(ctx context.Context, opts ListOptions) (*RunPage, error) {
+		return s.listRunPage(ctx, resourceKindTaskRun, opts)
+	}, opts)
+}
+
+func aggregateTaskRuns(ctx context.Context, list func(context.Context, ListOptions) (*RunPage, error), opts StatsOptions) (*TaskRunStats, error) {
+	tasks := map[string]*runTally{}
+	stats, err := aggregateRuns(ctx, list, opts, func(run RunSummary) {
+		task := cmp.Or(run.PipelineTask, run.Labels[taskLabel], UnnamedTask)
+		if tasks[task] == nil {
+			tasks[task] = &runTally{}
+		}
+		tasks[task].add(run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &TaskRunStats{RunStats: stats, Tasks: make([]TaskStats, 0, len(tasks))}
+	for _, task := range slices.Sorted(maps.Keys(tasks)) {
+		out.Tasks = append(out.Tasks, TaskStats{Task: task, RunStats: tasks[task].result()})
+	}
+	return out, nil
+}
+
+// aggregateRuns folds the runs list returns for opts into RunStats, one
+// namespace of a comma separated list after the other, since page tokens do
+// not span namespaces. each, when set, is also called with every run counted.
+func aggregateRuns(ctx context.Context, list func(context.Context, ListOptions) (*RunPage, error), opts StatsOptions, each func(RunSummary)) (*RunStats, error) {
+	maxRuns := opts.MaxRuns
+	if maxRuns <= 0 {
+		maxRuns = DefaultMaxStatsRuns
+	}
+	total := &runTally{}
+	stats := &total.stats
+	namespaces, err := splitNamespaces(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{opts.Namespace}
+	}
+	if len(namespaces) > 1 {
+		stats.Namespaces = namespaces
+	}
+
+	for _, ns := range namespaces {
+		if stats.Truncated {
+			break
+		}
+		pageOpts := opts.ListOptions
+		pageOpts.Namespace, pageOpts.Limit, pageOpts.PageToken, pageOpts.OrderBy = ns, int(maxPageSize), "", ""
+		for !stats.Truncated {
+			page, err := list(ctx, pageOpts)
+			if err != nil {
+				return nil, err
+			}
+			for _, run := range page.Runs {
+				if stats.Runs == maxRuns {
+					stats.Truncated, stats.MaxRuns = true, maxRuns
+					break
+				}
+				total.add(run)
+				if each != nil {
+					each(run)
+				}
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			pageOpts.PageToken = page.NextPageToken
+		}
+	}
+
+	return total.result(), nil
+}
+
+// runTally accumulates the RunStats of a set of runs.
+type runTally struct {
+	stats     RunStats
+	durations []time.Duration
+}
+
+func (t *runTally) add(run RunSummary) {
+	if d, ok := t.stats.add(run); ok {
+		t.durations = append(t.durations, d)
+	}
+}
+
+// result computes the rates and durations of the runs added.
+func (t *runTally) result() *RunStats {
+	stats := &t.stats
+	if finished := stats.Succeeded + stats.Failed + stats.TimedOut; finished > 0 {
+		success := float64(stats.Succeeded) / float64(finished)
+		failure := float64(stats.Failed+stats.TimedOut) / float64(finished)
+		stats.SuccessRate, stats.FailureRate = &success, &failure
+	}
+	stats.Duration = summarizeDurations(t.durations)
+	return stats
+}
+
+// add counts run and returns its duration when it finished with a duration
+// both clocks agree on.
+func (s *RunStats) add(run RunSummary) (time.Duration, bool) {
+	s.Runs++
+	switch run.Outcome() {
+	case "succeeded":
+		s.Succeeded++
+	case "failed":
+		s.Failed++
+	case "timedout":
+		s.TimedOut++
+	case "cancelled":
+		s.Cancelled++
+		return 0, false
+	case "running":
+		s.Running++
+		return 0, false
+	default:
+		s.Unknown++
+		return 0, false
+	}
+	d, ok := run.Duration()
+	return d, ok && !run.ClockSkew
+}
+
+// summarizeDurations returns the statistics of durations, or nil when there
+// are none. Percentiles use the nearest rank.
+func summarizeDurations(durations []time.Duration) *DurationStats {
+	if len(durations) == 0 {
+		return nil
+	}
+	slices.Sort(durations)
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	rank := func(p float64) time.Duration {
+		return durations[max(int(math.Ceil(p*float64(len(durations))))-1, 0)]
+	}
+	return &DurationStats{
+		Samples:        len(durations),
+		AverageSeconds: (total / time.Duration(len(durations))).Seconds(),
+		MedianSeconds:  rank(0.5).Seconds(),
+		P95Seconds:     rank(0.95).Seconds(),
+		MinSeconds:     durations[0].Seconds(),
+		MaxSeconds:     durations[len(durations)-1].Seconds(),
+	}
+}
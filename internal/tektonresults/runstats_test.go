@@ -0,0 +1,131 @@
+package tektonresults
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func statsRun(status, reason string, minutes int) RunSummary {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	run := RunSummary{Status: status, Reason: reason, StartTime: &start}
+	if status != "Unknown" {
+		end := metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute))
+		run.CompletionTime = &end
+	}
+	return run
+}
+
+func TestAggregateRuns(t *testing.T) {
+	pages := map[string][]RunPage{
+		"ci": {
+			{Runs: []RunSummary{statsRun("True", "Succeeded", 10), statsRun("True", "Succeeded", 20)}, NextPageToken: "next"},
+			{Runs: []RunSummary{statsRun("False", "Failed", 5), statsRun("False", "PipelineRunTimeout", 60)}},
+		},
+		"staging": {
+			{Runs: []RunSummary{statsRun("False", "Cancelled", 1), statsRun("Unknown", "Running", 0), statsRun("True", "Succeeded", 30)}},
+		},
+	}
+	var listed []string
+	list := func(ctx context.Context, opts ListOptions) (*RunPage, error) {
+		listed = append(listed, opts.Namespace+":"+opts.PageToken)
+		if opts.Limit != int(maxPageSize) || opts.RefName != "build" {
+			return nil, fmt.Errorf("unexpected options %+v", opts)
+		}
+		i := 0
+		if opts.PageToken == "next" {
+			i = 1
+		}
+		return &pages[opts.Namespace][i], nil
+	}
+
+	stats, err := aggregateRuns(context.Background(), list, StatsOptions{ListOptions: ListOptions{Namespace: "ci, staging", RefName: "build", PageToken: "stale"}}, nil)
+	if err != nil {
+		t.Fatalf("aggregateRuns() error = %v", err)
+	}
+	if got := strings.Join(listed, ","); got != "ci:,ci:next,staging:" {
+		t.Errorf("Expected each namespace to be paged through, got %s", got)
+	}
+	if stats.Runs != 7 || stats.Succeeded != 3 || stats.Failed != 1 || stats.TimedOut != 1 || stats.Cancelled != 1 || stats.Running != 1 {
+		t.Errorf("Unexpected counts %+v", stats)
+	}
+	if stats.SuccessRate == nil || *stats.SuccessRate != 0.6 || stats.FailureRate == nil || *stats.FailureRate != 0.4 {
+		t.Errorf("Unexpected rates %v, %v", stats.SuccessRate, stats.FailureRate)
+	}
+	// Durations of the five finished runs: 5, 10, 20, 30 and 60 minutes.
+	want := DurationStats{Samples: 5, AverageSeconds: 25 * 60, MedianSeconds: 20 * 60, P95Seconds: 60 * 60, MinSeconds: 5 * 60, MaxSeconds: 60 * 60}
+	if stats.Duration == nil || *stats.Duration != want {
+		t.Errorf("Duration = %+v, want %+v", stats.Duration, want)
+	}
+	if stats.Truncated || strings.Join(stats.Namespaces, ",") != "ci,staging" {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestAggregateRuns_Truncated(t *testing.T) {
+	calls := 0
+	list := func(ctx context.Context, opts ListOptions) (*RunPage, error) {
+		calls++
+		return &RunPage{Runs: []RunSummary{statsRun("True", "Succeeded", 1), statsRun("False", "Failed", 2)}, NextPageToken: "more"}, nil
+	}
+	stats, err := aggregateRuns(context.Background(), list, StatsOptions{ListOptions: ListOptions{Namespace: "ci"}, MaxRuns: 3}, nil)
+	if err != nil {
+		t.Fatalf("aggregateRuns() error = %v", err)
+	}
+	if !stats.Truncated || stats.MaxRuns != 3 || stats.Runs != 3 || calls != 2 {
+		t.Errorf("Expected the aggregation to stop after 3 runs, got %+v after %d pages", stats, calls)
+	}
+}
+
+func TestAggregateRuns_NoFinishedRuns(t *testing.T) {
+	list := func(ctx context.Context, opts ListOptions) (*RunPage, error) {
+		return &RunPage{Runs: []RunSummary{statsRun("Unknown", "Running", 0)}}, nil
+	}
+	stats, err := aggregateRuns(context.Background(), list, StatsOptions{ListOptions: ListOptions{Namespace: "-"}}, nil)
+	if err != nil {
+		t.Fatalf("aggregateRuns() error = %v", err)
+	}
+	if stats.Runs != 1 || stats.SuccessRate != nil || stats.FailureRate != nil || stats.Duration != nil {
+		t.Errorf("Expected counts only, got %+v", stats)
+	}
+}
+
+func TestAggregateTaskRuns(t *testing.T) {
+	task := func(run RunSummary, pipelineTask, taskRef string) RunSummary {
+		run.PipelineTask = pipelineTask
+		if taskRef != "" {
+			run.Labels = map[string]string{taskLabel: taskRef}
+		}
+		return run
+	}
+	list := func(ctx context.Context, opts ListOptions) (*RunPage, error) {
+		return &RunPage{Runs: []RunSummary{
+			task(statsRun("True", "Succeeded", 10), "test", "go-test"),
+			task(statsRun("False", "Failed", 30), "test", "go-test"),
+			task(statsRun("True", "Succeeded", 2), "clone", "git-clone"),
+			task(statsRun("True", "Succeeded", 4), "", "git-clone"),
+			task(statsRun("False", "Failed", 1), "", ""),
+		}}, nil
+	}
+	stats, err := aggregateTaskRuns(context.Background(), list, StatsOptions{ListOptions: ListOptions{Namespace: "ci"}})
+	if err != nil {
+		t.Fatalf("aggregateTaskRuns() error = %v", err)
+	}
+	if stats.Runs != 5 || stats.Failed != 2 {
+		t.Errorf("Unexpected totals %+v", stats.RunStats)
+	}
+	var got []string
+	for _, ts := range stats.Tasks {
+		got = append(got, fmt.Sprintf("%s:%d/%d", ts.Task, ts.Failed, ts.Runs))
+	}
+	if strings.Join(got, ",") != "(unnamed):1/1,clone:0/1,git-clone:0/1,test:1/2" {
+		t.Errorf("Unexpected tasks %v", got)
+	}
+	if test := stats.Tasks[3]; test.Duration == nil || test.Duration.AverageSeconds != 20*60 || *test.FailureRate != 0.5 {
+		t.Errorf("Unexpected stats for test %+v", test.RunStats)
+	}
+}
@@ -0,0 +1,204 @@
+package tektonresults
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxSchemaWarnings caps the drift warnings reported for a single run.
+const maxSchemaWarnings = 20
+
+//go:embed schemas/tekton-v1.json
+var tektonV1Schema []byte
+
+// schemaNode is the subset of OpenAPI v3 the drift check understands.
+type schemaNode struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*schemaNode `json:"properties,omitempty"`
+	Items                *schemaNode            `json:"items,omitempty"`
+	AdditionalProperties *schemaNode            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	PreserveUnknown      bool                   `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+}
+
+// schemaValidator compares decoded run manifests against the Tekton v1
+// schemas to notice data written by newer Tekton releases.
+type schemaValidator struct {
+	kinds map[string]*schemaNode // by kind, e.g. PipelineRun
+
+	reported sync.Map // warnings already logged, so each is logged once
+}
+
+func newSchemaValidator() (*schemaValidator, error) {
+	var doc struct {
+		Definitions map[string]*schemaNode `json:"definitions"`
+	}
+	if err := json.Unmarshal(tektonV1Schema, &doc); err != nil {
+		return nil, fmt.Errorf("parse embedded Tekton schema: %w", err)
+	}
+	resolving := map[*schemaNode]bool{}
+	var resolve func(n *schemaNode) (*schemaNode, error)
+	resolve = func(n *schemaNode) (*schemaNode, error) {
+		if n == nil {
+			return nil, nil
+		}
+		if n.Ref != "" {
+			target, ok := doc.Definitions[strings.TrimPrefix(n.Ref, "#/definitions/")]
+			if !ok {
+				return nil, fmt.Errorf("embedded Tekton schema: unknown reference %s", n.Ref)
+			}
+			return resolve(target)
+		}
+		if resolving[n] {
+			return n, nil
+		}
+		resolving[n] = true
+		var err error
+		for name, p := range n.Properties {
+			if n.Properties[name], err = resolve(p); err != nil {
+				return nil, err
+			}
+		}
+		if n.Items, err = resolve(n.Items); err != nil {
+			return nil, err
+		}
+		if n.AdditionalProperties, err = resolve(n.AdditionalProperties); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	v := &schemaValidator{kinds: map[string]*schemaNode{}}
+	for _, kind := range []string{"PipelineRun", "TaskRun"} {
+		node, err := resolve(doc.Definitions[kind])
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			return nil, fmt.Errorf("embedded Tekton schema: no definition of %s", kind)
+		}
+		v.kinds[kind] = node
+	}
+	return v, nil
+}
+
+// check returns the kind and the drift warnings of a run manifest: fields the
+// schema does not know, required fields that are missing and values of the
+// wrong type.
+// Paths use [] for any array element, so repeated findings collapse into one.
+// Only tekton.dev/v1 manifests are checked; v1beta1 data predates the
+// schema and an unknown newer version is reported as such.
+func (v *schemaValidator) check(raw json.RawMessage) (kind string, warnings []string) {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", nil
+	}
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ = doc["kind"].(string)
+	node, ok := v.kinds[kind]
+	switch {
+	case apiVersion == "tekton.dev/v1beta1", !ok:
+		return kind, nil
+	case apiVersion != "tekton.dev/v1":
+		return kind, []string{fmt.Sprintf("apiVersion %s is newer than the tekton.dev/v1 schema this server knows; fields were not checked", apiVersion)}
+	}
+
+	found := map[string]bool{}
+	walkSchema(node, doc, "", found)
+	warnings = make([]string, 0, len(found))
+	for w := range found {
+		warnings = append(warnings, w)
+	}
+	sort.Strings(warnings)
+	if len(warnings) > maxSchemaWarnings {
+		more := len(warnings) - maxSchemaWarnings
+		warnings = append(warnings[:maxSchemaWarnings], fmt.Sprintf("... and %d more", more))
+	}
+	return kind, warnings
+}
+
+func walkSchema(node *schemaNode, value any, path string, found map[string]bool) {
+	if node == nil || node.PreserveUnknown || value == nil {
+		return
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if node.Type != "" && node.Type != "object" {
+			found[fmt.Sprintf("%s: expected %s, got object", path, node.Type)] = true
+			return
+		}
+		for _, name := range node.Required {
+			if _, ok := v[name]; !ok {
+				found[fmt.Sprintf("%s: missing required field", joinPath(path, name))] = true
+			}
+		}
+		for name, child := range v {
+			if prop, ok := node.Properties[name]; ok {
+				walkSchema(prop, child, joinPath(path, name), found)
+			} else if node.AdditionalProperties != nil {
+				walkSchema(node.AdditionalProperties, child, joinPath(path, name), found)
+			} else {
+				found[fmt.Sprintf("%s: unknown field", joinPath(path, name))] = true
+			}
+		}
+	case []any:
+		if node.Type != "" && node.Type != "array" {
+			found[fmt.Sprintf("%s: expected %s, got array", path, node.Type)] = true
+			return
+		}
+		for _, item := range v {
+			walkSchema(node.Items, item, path+"[]", found)
+		}
+	default:
+		if got := jsonType(v); node.Type != "" && node.Type != got && !(node.Type == "integer" && got == "number") {
+			found[fmt.Sprintf("%s: expected %s, got %s", path, node.Type, got)] = true
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// log writes each distinct warning of a kind once per process, so a drifted
+// field shows up in the server log without repeating on every lookup.
+func (v *schemaValidator) log(kind string, warnings []string, rec string) {
+	for _, w := range warnings {
+		if _, seen := v.reported.LoadOrStore(kind+" "+w, true); !seen {
+			slog.Warn("stored run does not match the Tekton schema", "kind", kind, "warning", w, "record", rec)
+		}
+	}
+}
+
+// checked adds schema drift warnings to a fetched run when validation is
+// enabled.
+func (s *Service) checked(detail *RunDetail, err error) (*RunDetail, error) {
+	if err != nil || detail == nil || s.schemas == nil {
+		return detail, err
+	}
+	kind, warnings := s.schemas.check(detail.Raw)
+	detail.SchemaWarnings = warnings
+	s.schemas.log(kind, warnings, detail.RecordName)
+	return detail, nil
+}
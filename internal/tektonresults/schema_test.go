@@ -0,0 +1,105 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchemaValidator_Check(t *testing.T) {
+	v, err := newSchemaValidator()
+	if err != nil {
+		t.Fatalf("newSchemaValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			name: "known fields",
+			raw:  archivedNightly,
+		},
+		{
+			name: "fields from a newer release",
+			raw: `{"apiVersion":"tekton.dev/v1","kind":"TaskRun","metadata":{"name":"t","namespace":"ci"},
+				"spec":{"taskRef":{"name":"x"},"quota":"small"},
+				"status":{"conditions":[{"type":"Succeeded","status":"True"}],
+					"steps":[{"name":"a","heartbeat":1},{"name":"b","heartbeat":2}],"retries":"3"}}`,
+			want: []string{"spec.quota: unknown field", "status.retries: unknown field", "status.steps[].heartbeat: unknown field"},
+		},
+		{
+			name: "missing and mistyped fields",
+			raw: `{"apiVersion":"tekton.dev/v1","kind":"PipelineRun","metadata":{"name":"p","namespace":"ci","labels":{"n":1}},
+				"status":{"conditions":[{"type":"Succeeded"}],"startTime":5}}`,
+			want: []string{"metadata.labels.n: expected string, got number", "spec: missing required field", "status.conditions[].status: missing required field", "status.startTime: expected string, got number"},
+		},
+		{
+			name: "v1beta1 is not checked",
+			raw:  `{"apiVersion":"tekton.dev/v1beta1","kind":"PipelineRun","spec":{"serviceAccountName":"sa"}}`,
+		},
+		{
+			name: "newer version",
+			raw:  `{"apiVersion":"tekton.dev/v2","kind":"PipelineRun"}`,
+			want: []string{"apiVersion tekton.dev/v2 is newer than the tekton.dev/v1 schema this server knows; fields were not checked"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := v.check(json.RawMessage(tt.raw))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_CapsWarnings(t *testing.T) {
+	v, err := newSchemaValidator()
+	if err != nil {
+		t.Fatalf("newSchemaValidator() error = %v", err)
+	}
+	fields := make([]string, 30)
+	for i := range fields {
+		fields[i] = `"extra` + string(rune('a'+i%26)) + strings.Repeat("x", i/26) + `":1`
+	}
+	raw := `{"apiVersion":"tekton.dev/v1","kind":"PipelineRun","metadata":{"name":"p","namespace":"ci"},"spec":{` + strings.Join(fields, ",") + `}}`
+
+	_, got := v.check(json.RawMessage(raw))
+	if len(got) != maxSchemaWarnings+1 || got[maxSchemaWarnings] != "... and 10 more" {
+		t.Errorf("Expected %d warnings and a count of the rest, got %q", maxSchemaWarnings, got)
+	}
+}
+
+func TestGetRunByRecord_SchemaWarnings(t *testing.T) {
+	client := &mockRestClient{
+		getRecordFunc: func(ctx context.Context, recordName string) (*record, error) {
+			rec := &record{Name: recordName}
+			rec.Data.Value = json.RawMessage(`{"apiVersion":"tekton.dev/v1","kind":"PipelineRun","metadata":{"name":"p","namespace":"ci"},"spec":{"pipelineRef":{"name":"x"},"quota":"small"}}`)
+			return rec, nil
+		},
+	}
+
+	detail, err := (&Service{client: client}).GetRunByRecord(context.Background(), "ci/results/r/records/r")
+	if err != nil {
+		t.Fatalf("GetRunByRecord() error = %v", err)
+	}
+	if detail.SchemaWarnings != nil {
+		t.Errorf("Expected no validation unless enabled, got %q", detail.SchemaWarnings)
+	}
+
+	schemas, err := newSchemaValidator()
+	if err != nil {
+		t.Fatalf("newSchemaValidator() error = %v", err)
+	}
+	detail, err = (&Service{client: client, schemas: schemas}).GetRunByRecord(context.Background(), "ci/results/r/records/r")
+	if err != nil {
+		t.Fatalf("GetRunByRecord() error = %v", err)
+	}
+	if !slices.Equal(detail.SchemaWarnings, []string{"spec.quota: unknown field"}) {
+		t.Errorf("Unexpected warnings %q", detail.SchemaWarnings)
+	}
+}
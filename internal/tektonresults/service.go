@@ -2,11 +2,18 @@ package tektonresults
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
@@ -14,11 +21,12 @@ import (
 )
 
 const (
-	listFields                = "records.name,records.uid,records.data.value.metadata,records.data.value.status,next_page_token"
+	listFields                = "records.name,records.uid,records.data.value.metadata,records.data.value.spec.pipelineRef.name,records.data.value.spec.taskRef.name,records.data.value.spec.serviceAccountName,records.data.value.spec.taskRunTemplate.serviceAccountName,records.data.value.spec.workspaces,records.data.value.status,next_page_token"
 	nameUIDAndDataField       = "records.name,records.uid,records.data.value"
 	defaultListLimit    int   = 50
 	maxPageSize         int32 = 200
 	describePageSize    int32 = 50
+	defaultMaxScanPages int   = 20 // pages a single-run lookup may scan before giving up
 )
 
 type resourceKind string
@@ -40,10 +48,32 @@ type resultsClient interface {
 	listResults(ctx context.Context, req listResultsRequest) (*listResultsResponse, error)
 	listRecords(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error)
 	getLog(ctx context.Context, logPath string) ([]byte, error)
+	deleteResult(ctx context.Context, resultName string) error
 }
 
 type Service struct {
-	client resultsClient
+	client   resultsClient
+	endpoint string         // base URL of the Results API, for diagnostics
+	whoami   identityFunc   // optional; resolves the authenticated identity
+	cluster  *clusterClient // optional; the Tekton Results installation and live PipelineRuns
+	github   *githubClient  // optional; looks up the check runs of Pipelines as Code runs
+
+	dashboard DashboardTemplate // links summaries to a web UI; none when zero
+	schemas   *schemaValidator  // optional; reports drift from the Tekton v1 schema
+
+	rest         *restClient    // unwrapped client, for Reconfigure; nil in tests
+	metrics      *clientMetrics // upstream request counters; nil in tests
+	scanPageSize atomic.Int32   // page size for single-run lookups; describePageSize when zero
+	maxScanPages atomic.Int64   // page budget for single-run lookups; defaultMaxScanPages when zero
+	teams        atomic.Pointer[teamMap]
+	logs         logSupport  // whether the Results API serves logs, once known
+	archive      *LogArchive // optional; logs the Results API cannot serve
+
+	infoMu sync.Mutex
+	info   *ServerInfo // last probe result
+
+	completions completionCache
+	runs        runIndex // record names of runs nested under another Result
 }
 
 // NewService constructs a Service using the Kubernetes REST config for auth.
@@ -52,7 +82,65 @@ func NewService(cfg *rest.Config, overrides Overrides) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Service{client: rc}, nil
+	rc.metrics = newClientMetrics()
+	if overrides.TokenStore.Enabled() {
+		if overrides.BearerToken != "" {
+			return nil, fmt.Errorf("set either a bearer token or a token store, not both")
+		}
+		if rc.store, err = newStoreTokenSource(cfg, overrides.TokenStore); err != nil {
+			return nil, err
+		}
+		// Fail at startup rather than on the first tool call.
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+		_, err := rc.store.token(ctx)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+	cluster, err := newClusterClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	github, err := newGitHubClient(overrides.GitHub)
+	if err != nil {
+		return nil, err
+	}
+	svc := &Service{
+		client:    rc,
+		cluster:   cluster,
+		github:    github,
+		dashboard: overrides.Dashboard,
+		rest:      rc,
+		metrics:   rc.metrics,
+		endpoint:  rc.baseURL.String(),
+		whoami:    newIdentityFunc(cfg, overrides),
+	}
+	if overrides.LogArchive != "" {
+		if svc.archive, err = OpenLogArchive(overrides.LogArchive, overrides.MaxResponseBytes); err != nil {
+			return nil, err
+		}
+		slog.Info("Reading logs the Results API cannot serve from the log archive", "dir", overrides.LogArchive)
+	}
+	if overrides.ValidateSchemas {
+		if svc.schemas, err = newSchemaValidator(); err != nil {
+			return nil, err
+		}
+	}
+	if err := svc.Reconfigure(Settings{
+		ScanPageSize:    overrides.ScanPageSize,
+		MaxScanPages:    overrides.MaxScanPages,
+		NamespaceTokens: overrides.NamespaceTokens,
+		Teams:           overrides.Teams,
+	}); err != nil {
+		return nil, err
+	}
+	if overrides.Faults.Enabled() {
+		slog.Warn("fault injection is enabled for the Tekton Results client", "config", fmt.Sprintf("%+v", overrides.Faults))
+		svc.client = newFaultInjectingClient(rc, overrides.Faults)
+	}
+	svc.client = tracingClient{next: svc.client}
+	return svc, nil
 }
 
 // ListPipelineRuns returns summaries of PipelineRuns.
@@ -65,64 +153,162 @@ func (s *Service) ListTaskRuns(ctx context.Context, opts ListOptions) ([]RunSumm
 	return s.listRuns(ctx, resourceKindTaskRun, opts)
 }
 
+// ListPipelineRunPage returns a page of PipelineRun summaries, continuing
+// the listing at opts.PageToken.
+func (s *Service) ListPipelineRunPage(ctx context.Context, opts ListOptions) (*RunPage, error) {
+	return s.listRunPage(ctx, resourceKindPipelineRun, opts)
+}
+
+// ListTaskRunPage returns a page of TaskRun summaries, continuing the
+// listing at opts.PageToken.
+func (s *Service) ListTaskRunPage(ctx context.Context, opts ListOptions) (*RunPage, error) {
+	return s.listRunPage(ctx, resourceKindTaskRun, opts)
+}
+
 // GetPipelineRun returns the detailed Run representation.
 func (s *Service) GetPipelineRun(ctx context.Context, selector RunSelector) (*RunDetail, error) {
-	return s.getRun(ctx, resourceKindPipelineRun, selector)
+	return s.checked(s.getRun(ctx, resourceKindPipelineRun, selector))
 }
 
 // GetTaskRun returns the detailed Run representation.
 func (s *Service) GetTaskRun(ctx context.Context, selector RunSelector) (*RunDetail, error) {
-	return s.getRun(ctx, resourceKindTaskRun, selector)
+	return s.checked(s.getRun(ctx, resourceKindTaskRun, selector))
 }
 
-// FetchLogs downloads the log payload referenced by the record name.
-func (s *Service) FetchLogs(ctx context.Context, recordName string) (string, error) {
-	logPath := strings.Replace(recordName, "/records/", "/logs/", 1)
-	if logPath == recordName {
-		logPath = strings.Replace(recordName, "records", "logs", 1)
+// GetRunByRecord fetches a PipelineRun or TaskRun by its record name, as
+// reported in RunSummary.RecordName, without any searching.
+func (s *Service) GetRunByRecord(ctx context.Context, recordName string) (*RunDetail, error) {
+	recordName = strings.Trim(strings.TrimSpace(recordName), "/")
+	// The name becomes the request path, so it must not climb out of the
+	// record it names.
+	if parts := nameSegments(recordName); len(parts) != 5 || parts[1] != "results" || parts[3] != "records" {
+		return nil, fmt.Errorf("invalid record name %q: expected <namespace>/results/<result>/records/<record>", recordName)
 	}
-	data, err := s.client.getLog(ctx, logPath)
+	rec, err := s.client.getRecord(ctx, recordName)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("get record %s: %w", recordName, err)
 	}
-	return string(data), nil
+	return s.checked(s.detailFromRecord(*rec))
 }
 
 type ListOptions struct {
-	Namespace     string
-	LabelSelector string
-	Prefix        string
-	Limit         int
+	Namespace          string
+	LabelSelector      string
+	AnnotationSelector string // label selector syntax, matched against annotations
+	RefName            string // name of the Pipeline, for PipelineRuns, or Task, for TaskRuns, the runs reference
+	ServiceAccount     string // service account the runs executed as
+	Prefix             string
+	NameRegex          string        // regular expression the run name must match, unanchored
+	Reason             string        // comma separated Succeeded condition reasons, matched ignoring case
+	Status             string        // comma separated RunStatuses, matched ignoring case
+	MinDuration        time.Duration // only completed runs that took at least this long; zero for no bound
+	MaxDuration        time.Duration // only completed runs that took at most this long; zero for no bound
+	LatestOnly         bool          // keep only the most recent run of each name, or generateName for generated names
+	CreatedAfter       time.Time     // only runs whose record was created at or after this time; zero for no bound
+	CreatedBefore      time.Time     // only runs whose record was created before this time; zero for no bound
+	Team               string        // name of a configured team whose selectors the runs must match
+	Limit              int
+	OrderBy            string // "<field> [asc|desc]" with a field from OrderFields; empty for create_time desc
+	PageToken          string // RunPage.NextPageToken of a previous call with the same options; empty for the first page
+}
+
+// RunPage is one page of a run listing.
+type RunPage struct {
+	Runs          []RunSummary
+	NextPageToken string // continues the listing after Runs; empty when no runs are left
+	Partial       bool   // the page budget ran out before Limit runs matched filters applied in memory; without a NextPageToken across several namespaces
 }
 
 // RunSelector specifies filters for finding a single PipelineRun or TaskRun.
 type RunSelector struct {
-	Namespace     string // Kubernetes namespace; use "-" for all namespaces
-	LabelSelector string // Comma-separated key=value label filters
-	Prefix        string // Name prefix filter
-	Name          string // Exact name match (not unique in Results history)
-	UID           string // Exact UID match (unique identifier in Tekton Results database)
-	SelectLast    bool   // If true, automatically select the most recent match when multiple runs match the filters.
+	Namespace          string // Kubernetes namespace; use "-" for all namespaces
+	LabelSelector      string // Comma-separated key=value label filters
+	AnnotationSelector string // Annotation filters in label selector syntax
+	Prefix             string // Name prefix filter
+	NameRegex          string // Regular expression the name must match, unanchored
+	Name               string // Exact name match (not unique in Results history)
+	UID                string // Exact UID match (unique identifier in Tekton Results database)
+	SelectLast         bool   // If true, automatically select the most recent match when multiple runs match the filters.
 	// Defaults to true. When false, returns an error if multiple matches are found.
 	// Useful because run names are not unique in Tekton Results history.
+	Index int // Position among matches ordered newest first: 0 = latest, 1 = previous, ...
+	// A positive index always selects explicitly and ignores SelectLast.
+	CreatedAfter  time.Time // Only runs created at or after this time; zero means no bound
+	CreatedBefore time.Time // Only runs created before this time; zero means no bound
 }
 
 type RunSummary struct {
 	Name           string            `json:"name"`
+	GenerateName   string            `json:"generateName,omitempty"`
 	Namespace      string            `json:"namespace"`
 	UID            string            `json:"uid,omitempty"`
 	Labels         map[string]string `json:"labels,omitempty"`
+	PipelineTask   string            `json:"pipelineTask,omitempty"` // pipeline task name, for TaskRuns owned by a PipelineRun
 	StartTime      *metav1.Time      `json:"startTime,omitempty"`
 	CompletionTime *metav1.Time      `json:"completionTime,omitempty"`
 	Status         string            `json:"status,omitempty"`
 	Reason         string            `json:"reason,omitempty"`
+	Message        string            `json:"message,omitempty"`    // Succeeded condition message, on one line and truncated to maxSummaryMessage
+	Incomplete     bool              `json:"incomplete,omitempty"` // stored before the run reported any status; Status is "Unknown"
+	ClockSkew      bool              `json:"clockSkew,omitempty"`  // CompletionTime precedes StartTime, so the duration is unreliable and reported as zero
 	RecordName     string            `json:"recordName"`
+	ResultName     string            `json:"resultName,omitempty"` // parent Result, "<namespace>/results/<id>"
+	ResultUID      string            `json:"resultUID,omitempty"`  // id segment of ResultName; the UID of the top-level run that owns the Result
+	Change         *SourceChange     `json:"change,omitempty"`     // commit and pull request that triggered the run, for Pipelines as Code runs
+	Team           string            `json:"team,omitempty"`       // configured team whose selectors match the run's labels
+	DashboardURL   string            `json:"dashboardUrl,omitempty"`
+	Duplicates     int               `json:"duplicates,omitempty"` // older runs of the same name a LatestOnly listing left out
+	// Workspaces are the workspace bindings of the run. They are left out of
+	// list output, which they would crowd, and read by workspace_usage.
+	Workspaces []Workspace `json:"-"`
+	// Ref is the Pipeline a PipelineRun or the Task a TaskRun references,
+	// from its spec or else its label, as read by groupBy.
+	Ref string `json:"-"`
 }
 
 type RunDetail struct {
 	Summary    RunSummary
 	Raw        json.RawMessage
 	RecordName string
+	History    *MatchHistory // set when the run was picked among several matches
+
+	// SchemaWarnings lists where Raw departs from the Tekton v1 schema, such
+	// as fields added by a newer Tekton release. Only set when schema
+	// validation is enabled.
+	SchemaWarnings []string
+}
+
+// MatchHistory summarizes the historical runs that matched a selector when one
+// of them was picked automatically.
+type MatchHistory struct {
+	Matches   int          `json:"matches"`
+	Truncated bool         `json:"truncated,omitempty"` // more pages were left unread, so Matches is a lower bound
+	Selected  int          `json:"selected"`            // position of the returned run, newest first
+	Oldest    *metav1.Time `json:"oldest,omitempty"`
+	Newest    *metav1.Time `json:"newest,omitempty"`
+}
+
+func (h *MatchHistory) observe(summary RunSummary) {
+	h.Matches++
+	if summary.StartTime == nil {
+		return
+	}
+	if h.Oldest == nil || summary.StartTime.Before(h.Oldest) {
+		h.Oldest = summary.StartTime
+	}
+	if h.Newest == nil || h.Newest.Before(summary.StartTime) {
+		h.Newest = summary.StartTime
+	}
+}
+
+// attach returns a copy of detail carrying the history when more than one run
+// matched.
+func (h MatchHistory) attach(detail RunDetail, selected int) *RunDetail {
+	if h.Matches > 1 {
+		h.Selected = selected
+		detail.History = &h
+	}
+	return &detail
 }
 
 func (d RunDetail) Completed() bool {
@@ -149,12 +335,28 @@ func (d RunDetail) Format(output string) (string, error) {
 }
 
 type tektonRun struct {
+	Kind     string `json:"kind"`
 	Metadata struct {
-		Name      string            `json:"name"`
-		Namespace string            `json:"namespace"`
-		UID       string            `json:"uid"`
-		Labels    map[string]string `json:"labels"`
+		Name         string            `json:"name"`
+		GenerateName string            `json:"generateName"`
+		Namespace    string            `json:"namespace"`
+		UID          string            `json:"uid"`
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
 	} `json:"metadata"`
+	Spec struct {
+		PipelineRef struct {
+			Name string `json:"name"`
+		} `json:"pipelineRef"`
+		TaskRef struct {
+			Name string `json:"name"`
+		} `json:"taskRef"`
+		ServiceAccountName string `json:"serviceAccountName"` // TaskRuns and v1beta1 PipelineRuns
+		TaskRunTemplate    struct {
+			ServiceAccountName string `json:"serviceAccountName"`
+		} `json:"taskRunTemplate"` // v1 PipelineRuns
+		Workspaces []workspaceBinding `json:"workspaces"`
+	} `json:"spec"`
 	Status struct {
 		StartTime      *metav1.Time `json:"startTime"`
 		CompletionTime *metav1.Time `json:"completionTime"`
@@ -167,13 +369,112 @@ type tektonRun struct {
 	} `json:"status"`
 }
 
+// refName returns the name of the Pipeline or Task a run of kind
+// references. Runs with an embedded spec or a spec fetched by a resolver have
+// no reference name; the tekton.dev/pipeline or tekton.dev/task label Tekton
+// sets names them instead.
+func (r tektonRun) refName(kind resourceKind) string {
+	if kind == resourceKindPipelineRun {
+		return cmp.Or(r.Spec.PipelineRef.Name, r.Metadata.Labels[pipelineLabel])
+	}
+	return cmp.Or(r.Spec.TaskRef.Name, r.Metadata.Labels[taskLabel])
+}
+
+// serviceAccount returns the service account a run executes as. PipelineRuns
+// of the v1 API set it in the TaskRun template; v1beta1 PipelineRuns and all
+// TaskRuns set it in the spec.
+func (r tektonRun) serviceAccount() string {
+	return cmp.Or(r.Spec.TaskRunTemplate.ServiceAccountName, r.Spec.ServiceAccountName)
+}
+
 func (s *Service) listRuns(ctx context.Context, kind resourceKind, opts ListOptions) ([]RunSummary, error) {
+	page, err := s.listRunPage(ctx, kind, opts)
+	if err != nil {
+		return nil, err
+	}
+	// A partial page holds the runs found within the page budget. Callers
+	// that tell the user so list pages instead.
+	return page.Runs, nil
+}
+
+// listQuery identifies the listing opts select, for binding page tokens to
+// it. The creation time bounds travel in the token instead, so relative
+// bounds such as "the last 24 hours" keep the window of the first page.
+func listQuery(kind resourceKind, opts ListOptions) pageQuery {
+	filters := []string{"annotations=" + opts.AnnotationSelector, "ref=" + opts.RefName, "serviceAccount=" + opts.ServiceAccount, "prefix=" + opts.Prefix, "nameRegex=" + opts.NameRegex, "reason=" + opts.Reason, "status=" + opts.Status, "duration=" + opts.MinDuration.String() + "-" + opts.MaxDuration.String(), "latestOnly=" + strconv.FormatBool(opts.LatestOnly), "team=" + opts.Team, "orderBy=" + opts.OrderBy}
+	if !opts.CreatedAfter.IsZero() {
+		filters = append(filters, "createdAfter")
+	}
+	if !opts.CreatedBefore.IsZero() {
+		filters = append(filters, "createdBefore")
+	}
+	return pageQuery{Kind: string(kind), Namespace: opts.Namespace, LabelSelector: opts.LabelSelector, Filters: filters}
+}
+
+func (s *Service) listRunPage(ctx context.Context, kind resourceKind, opts ListOptions) (*RunPage, error) {
+	namespaces, err := splitNamespaces(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespaces) > 1 {
+		if opts.PageToken != "" {
+			return nil, fmt.Errorf("page tokens are not supported when listing several namespaces; list each namespace on its own or use '-' for all namespaces")
+		}
+		return s.listRunsAcross(ctx, kind, namespaces, opts)
+	} else if len(namespaces) == 1 {
+		opts.Namespace = namespaces[0]
+	}
+
+	query := listQuery(kind, opts)
+	resume, err := decodePageToken(query, opts.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if opts.PageToken != "" {
+		opts.CreatedAfter, opts.CreatedBefore = resume.After, resume.Before
+	}
+
 	labelFilters, err := parseLabelSelector(opts.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
+	annotationFilters, err := parseAnnotationSelector(opts.AnnotationSelector)
+	if err != nil {
+		return nil, err
+	}
+	reasons := parseReasonFilter(opts.Reason)
+	statuses, err := parseStatusFilter(opts.Status)
+	if err != nil {
+		return nil, err
+	}
+	durations, err := newDurationRange(opts.MinDuration, opts.MaxDuration)
+	if err != nil {
+		return nil, err
+	}
+	order, err := parseOrder(opts.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	nameRegex, err := compileNameRegex(opts.NameRegex)
+	if err != nil {
+		return nil, err
+	}
+	var owner *team
+	if opts.Team != "" {
+		if owner, err = s.teams.Load().lookup(opts.Team); err != nil {
+			return nil, err
+		}
+	}
 
-	filter := buildFilterExpression(kind, labelFilters, "", "")
+	builder := newFilterBuilder(kind).labels(labelFilters.equals).annotations(annotationFilters.equals).ref(kind, opts.RefName).statuses(statuses).
+		createdSince(opts.CreatedAfter).createdBefore(opts.CreatedBefore)
+	if owner != nil {
+		builder.anyOf(owner.selectors)
+	}
+	filter, err := builder.build()
+	if err != nil {
+		return nil, err
+	}
 	parent := parentForNamespace(opts.Namespace)
 
 	limit := opts.Limit
@@ -181,45 +482,99 @@ func (s *Service) listRuns(ctx context.Context, kind resourceKind, opts ListOpti
 		limit = defaultListLimit
 	}
 	pageSize := int32(limit)
-	if pageSize > maxPageSize {
+	// Filters applied in memory can drop most records of a page, so they read
+	// full pages and stop after the page budget instead of shrinking the page
+	// to the runs still missing.
+	inMemory := opts.ServiceAccount != "" || opts.Prefix != "" || nameRegex != nil || len(reasons) > 0 ||
+		durations != (durationRange{}) || opts.LatestOnly || labelFilters.excludes() || annotationFilters.excludes()
+	if pageSize > maxPageSize || inMemory {
 		pageSize = maxPageSize
 	}
 
 	req := listRecordsRequest{
-		Parent:   parent,
-		Filter:   filter,
-		OrderBy:  "create_time desc",
-		PageSize: pageSize,
-		Fields:   listFields,
+		Parent:    parent,
+		Filter:    filter,
+		OrderBy:   order.upstream(),
+		PageSize:  pageSize,
+		PageToken: resume.Token,
+		Fields:    listFields,
+	}
+	// A page the previous call stopped inside is fetched again with the same
+	// size, so skipping the returned records lands on the next one.
+	skip := resume.Skip
+	if skip > 0 {
+		req.PageSize = resume.Size
 	}
+	next := pageToken{After: opts.CreatedAfter, Before: opts.CreatedBefore}
 
-	var summaries []RunSummary
-	for {
+	page := &RunPage{}
+	latest := latestRuns{}
+	for pages := 1; ; pages++ {
 		resp, err := s.client.listRecords(ctx, req)
 		if err != nil {
 			return nil, err
 		}
-		for _, rec := range resp.Records {
+		s.runs.observe(resp.Records)
+		for i, rec := range resp.Records {
+			if i < skip {
+				continue
+			}
 			run, err := decodeRun(rec)
 			if err != nil {
 				return nil, err
 			}
-			if !matchesLabels(run.Metadata.Labels, labelFilters) {
+			if !matchesLabels(run.Metadata.Labels, labelFilters) || !matchesLabels(run.Metadata.Annotations, annotationFilters) {
+				continue
+			}
+			if owner != nil && !owner.matches(run.Metadata.Labels) {
+				continue
+			}
+			if opts.RefName != "" && run.refName(kind) != opts.RefName {
+				continue
+			}
+			if opts.ServiceAccount != "" && run.serviceAccount() != opts.ServiceAccount {
 				continue
 			}
 			if opts.Prefix != "" && !strings.HasPrefix(run.Metadata.Name, opts.Prefix) {
 				continue
 			}
-			summaries = append(summaries, summarizeRun(run, rec))
-			if len(summaries) >= limit {
-				return summaries, nil
+			if !matchesName(nameRegex, run.Metadata.Name) {
+				continue
+			}
+			summary := s.summarize(run, rec)
+			// Statuses are checked again in case the server ignored the filter.
+			if !reasons.matches(summary.Reason) || !statuses.matches(summary) || !durations.matches(summary) {
+				continue
+			}
+			if opts.LatestOnly && latest.collapse(page, summary) {
+				continue
+			}
+			page.Runs = append(page.Runs, summary)
+			if len(page.Runs) >= limit {
+				if i+1 < len(resp.Records) {
+					next.Token, next.Skip, next.Size = req.PageToken, i+1, req.PageSize
+				} else {
+					next.Token = resp.NextPageToken
+				}
+				page.NextPageToken = encodePageToken(query, next)
+				order.sortPage(page.Runs)
+				return page, nil
 			}
 		}
+		skip = 0
 		if resp.NextPageToken == "" {
 			break
 		}
+		if inMemory && pages >= s.lookupPageBudget() {
+			next.Token = resp.NextPageToken
+			page.NextPageToken, page.Partial = encodePageToken(query, next), true
+			break
+		}
 		req.PageToken = resp.NextPageToken
-		remaining := limit - len(summaries)
+		if inMemory {
+			continue
+		}
+		remaining := limit - len(page.Runs)
 		if remaining <= 0 {
 			break
 		}
@@ -228,7 +583,66 @@ func (s *Service) listRuns(ctx context.Context, kind resourceKind, opts ListOpti
 		}
 	}
 
-	return summaries, nil
+	order.sortPage(page.Runs)
+	return page, nil
+}
+
+// namespaceParallelism is the most namespaces of a list queried at once.
+const namespaceParallelism = 4
+
+// listRunsAcross queries the namespaces in parallel, namespaceParallelism at
+// a time, and merges the results in the requested order, up to the requested
+// limit. The page is partial when the scan of any namespace ran out of its
+// page budget; it has no page token.
+func (s *Service) listRunsAcross(ctx context.Context, kind resourceKind, namespaces []string, opts ListOptions) (*RunPage, error) {
+	order, err := parseOrder(opts.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	type namespaceResult struct {
+		page *RunPage
+		err  error
+	}
+	results := make([]namespaceResult, len(namespaces))
+
+	sem := make(chan struct{}, namespaceParallelism)
+	var wg sync.WaitGroup
+	for i, ns := range namespaces {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			nsOpts := opts
+			nsOpts.Namespace = ns
+			page, err := s.listRunPage(ctx, kind, nsOpts)
+			results[i] = namespaceResult{page: page, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var merged []RunSummary
+	partial := false
+	for i, res := range results {
+		if res.err != nil {
+			return nil, fmt.Errorf("namespace %s: %w", namespaces[i], res.err)
+		}
+		merged = append(merged, res.page.Runs...)
+		partial = partial || res.page.Partial
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return order.before(merged[i], merged[j])
+	})
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return &RunPage{Runs: merged, Partial: partial}, nil
 }
 
 func (s *Service) getRun(ctx context.Context, kind resourceKind, selector RunSelector) (*RunDetail, error) {
@@ -236,6 +650,13 @@ func (s *Service) getRun(ctx context.Context, kind resourceKind, selector RunSel
 	if err != nil {
 		return nil, err
 	}
+	annotationFilters, err := parseAnnotationSelector(selector.AnnotationSelector)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := compileNameRegex(selector.NameRegex); err != nil {
+		return nil, err
+	}
 
 	// Optimized UID lookup: try direct GetRecord first
 	if selector.UID != "" {
@@ -243,23 +664,21 @@ func (s *Service) getRun(ctx context.Context, kind resourceKind, selector RunSel
 		if ns == "" {
 			ns = "default"
 		}
+		// TaskRuns of a PipelineRun seen in an earlier listing are fetched
+		// from their known record instead of searching for them.
+		if kind == resourceKindTaskRun {
+			if recordName, ok := s.runs.lookup(selector.Namespace, selector.UID); ok {
+				if rec, err := s.client.getRecord(ctx, recordName); err == nil {
+					return s.detailFromRecord(*rec)
+				}
+				s.runs.forget(selector.UID)
+			}
+		}
 		recordName := fmt.Sprintf("%s/results/%s/records/%s", ns, selector.UID, selector.UID)
 		rec, err := s.client.getRecord(ctx, recordName)
 		if err == nil {
 			// Found directly, decode and return
-			run, err := decodeRun(*rec)
-			if err != nil {
-				return nil, fmt.Errorf("decode run from direct get: %w", err)
-			}
-			rawValue, err := rec.GetValue()
-			if err != nil {
-				return nil, fmt.Errorf("get value for detail from direct get: %w", err)
-			}
-			return &RunDetail{
-				Summary:    summarizeRun(run, *rec),
-				Raw:        rawValue,
-				RecordName: rec.Name,
-			}, nil
+			return s.detailFromRecord(*rec)
 		}
 
 		// If direct GetRecord failed for a TaskRun, it might be part of a PipelineRun.
@@ -275,30 +694,76 @@ func (s *Service) getRun(ctx context.Context, kind resourceKind, selector RunSel
 
 	// Non-UID query path: use standard filtering
 	resultParent := parentForNamespace(selector.Namespace)
-	filter := buildFilterExpression(kind, labelFilters, selector.Name, "")
+	filter, err := newFilterBuilder(kind).labels(labelFilters.equals).annotations(annotationFilters.equals).name(selector.Name).
+		createdSince(selector.CreatedAfter).createdBefore(selector.CreatedBefore).build()
+	if err != nil {
+		return nil, err
+	}
 	req := listRecordsRequest{
 		Parent:   resultParent,
 		Filter:   filter,
 		OrderBy:  "create_time desc",
-		PageSize: describePageSize,
+		PageSize: s.lookupPageSize(),
 		Fields:   nameUIDAndDataField,
 	}
 	return s.queryRecords(ctx, req, selector)
 }
 
+// lookupPageSize is the page size used when searching for a single run.
+func (s *Service) lookupPageSize() int32 {
+	if size := s.scanPageSize.Load(); size > 0 {
+		return size
+	}
+	return describePageSize
+}
+
+// lookupPageBudget is the number of pages a single-run search may fetch
+// before it gives up.
+func (s *Service) lookupPageBudget() int {
+	if pages := s.maxScanPages.Load(); pages > 0 {
+		return int(pages)
+	}
+	return defaultMaxScanPages
+}
+
 // queryRecords handles the common logic for querying and filtering records
 func (s *Service) queryRecords(ctx context.Context, req listRecordsRequest, selector RunSelector) (*RunDetail, error) {
 	labelFilters, err := parseLabelSelector(selector.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
+	annotationFilters, err := parseAnnotationSelector(selector.AnnotationSelector)
+	if err != nil {
+		return nil, err
+	}
+	nameRegex, err := compileNameRegex(selector.NameRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep collecting until the requested position is reachable; at least two
+	// matches are needed to detect ambiguity.
+	want := selector.Index + 1
+	if want < 2 {
+		want = 2
+	}
+
+	// A run is picked silently among several matches when SelectLast or an
+	// explicit index is used; in that case the rest of the page is counted to
+	// report how many runs matched. No further pages are read for the count.
+	silentPick := selector.SelectLast || selector.Index > 0
 
 	var matches []RunDetail
+	var history MatchHistory
+	pages, scanned := 0, 0
 	for {
 		resp, err := s.client.listRecords(ctx, req)
 		if err != nil {
 			return nil, err
 		}
+		s.runs.observe(resp.Records)
+		pages++
+		scanned += len(resp.Records)
 		for _, rec := range resp.Records {
 			run, err := decodeRun(rec)
 			if err != nil {
@@ -311,41 +776,72 @@ func (s *Service) queryRecords(ctx context.Context, req listRecordsRequest, sele
 					continue
 				}
 			}
-			if !matchesLabels(run.Metadata.Labels, labelFilters) {
+			if !matchesLabels(run.Metadata.Labels, labelFilters) || !matchesLabels(run.Metadata.Annotations, annotationFilters) {
 				continue
 			}
 			if selector.Prefix != "" && !strings.HasPrefix(run.Metadata.Name, selector.Prefix) {
 				continue
 			}
+			if !matchesName(nameRegex, run.Metadata.Name) {
+				continue
+			}
 			if selector.Name != "" && run.Metadata.Name != selector.Name {
 				continue
 			}
+			summary := s.summarize(run, rec)
+			history.observe(summary)
+			if len(matches) >= want {
+				if !silentPick {
+					break
+				}
+				continue
+			}
 			rawValue, err := rec.GetValue()
 			if err != nil {
 				return nil, fmt.Errorf("get value for detail: %w", err)
 			}
 			matches = append(matches, RunDetail{
-				Summary:    summarizeRun(run, rec),
+				Summary:    summary,
 				Raw:        rawValue,
 				RecordName: rec.Name,
 			})
-			if len(matches) > 1 {
-				break
-			}
 		}
-		if len(matches) > 1 || resp.NextPageToken == "" {
+		if resp.NextPageToken == "" {
+			break
+		}
+		if len(matches) >= want {
+			history.Truncated = true
 			break
+		} else if pages >= s.lookupPageBudget() {
+			// The newest match is already known when picking the latest run;
+			// anything else needs the full result, so refuse to scan further.
+			if len(matches) > 0 && selector.SelectLast && selector.Index == 0 {
+				history.Truncated = true
+				break
+			}
+			if len(matches) == 1 && selector.Index == 0 {
+				// The run was found, but another may match further back.
+				match := matches[0].Summary
+				return nil, fmt.Errorf("scanned %d records in %d pages and found %s/%s, but could not confirm it is the only run matching the filters; set selectLast to accept the most recent match, or narrow your query with a namespace, name, uid or labelSelector", scanned, pages, match.Namespace, match.Name)
+			}
+			return nil, fmt.Errorf("scanned %d records in %d pages without finding the requested run; narrow your query with a namespace, name, uid or labelSelector", scanned, pages)
 		}
 		req.PageToken = resp.NextPageToken
 	}
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("no run found that matches the provided filters")
+		return nil, ErrRunNotFound
+	}
+	if selector.Index > 0 {
+		if selector.Index >= len(matches) {
+			return nil, fmt.Errorf("index %d is out of range: only %d run(s) match the provided filters", selector.Index, len(matches))
+		}
+		return history.attach(matches[selector.Index], selector.Index), nil
 	}
 	if len(matches) > 1 {
 		// If SelectLast is enabled, return the first match (most recent due to create_time desc ordering)
 		if selector.SelectLast {
-			return &matches[0], nil
+			return history.attach(matches[0], 0), nil
 		}
 		var names []string
 		for _, match := range matches {
@@ -357,6 +853,23 @@ func (s *Service) queryRecords(ctx context.Context, req listRecordsRequest, sele
 	return &matches[0], nil
 }
 
+// detailFromRecord decodes a record fetched directly by name.
+func (s *Service) detailFromRecord(rec record) (*RunDetail, error) {
+	run, err := decodeRun(rec)
+	if err != nil {
+		return nil, fmt.Errorf("decode run from direct get: %w", err)
+	}
+	rawValue, err := rec.GetValue()
+	if err != nil {
+		return nil, fmt.Errorf("get value for detail from direct get: %w", err)
+	}
+	return &RunDetail{
+		Summary:    s.summarize(run, rec),
+		Raw:        rawValue,
+		RecordName: rec.Name,
+	}, nil
+}
+
 func decodeRun(rec record) (tektonRun, error) {
 	value, err := rec.GetValue()
 	if err != nil {
@@ -372,33 +885,117 @@ func decodeRun(rec record) (tektonRun, error) {
 	return run, nil
 }
 
+// pipelineTaskLabel is set by Tekton on TaskRuns created for a pipeline task.
+const pipelineTaskLabel = "tekton.dev/pipelineTask"
+
+// summarize summarizes a run, names the team that owns it and links it to the
+// dashboard.
+func (s *Service) summarize(run tektonRun, rec record) RunSummary {
+	summary := summarizeRun(run, rec)
+	summary.Team = s.teams.Load().owner(run.Metadata.Labels)
+	kind := run.Kind
+	if kind == "" {
+		// Record types are "<group>/<version>.<kind>", e.g. tekton.dev/v1.TaskRun.
+		kind = rec.Data.Type[strings.LastIndex(rec.Data.Type, ".")+1:]
+	}
+	summary.DashboardURL = s.dashboard.URL(kind, summary.Namespace, summary.Name, summary.UID)
+	summary.Ref = run.refName(resourceKind(strings.ToLower(kind)))
+	return summary
+}
+
 func summarizeRun(run tektonRun, rec record) RunSummary {
-	status, reason := conditionStatus(run.Status.Conditions)
+	status, reason, message := conditionStatus(run.Status.Conditions)
+	// The watcher can store a run before its controller sets the Succeeded
+	// condition. Say so instead of leaving the status blank.
+	incomplete := status == ""
+	if incomplete {
+		status = "Unknown"
+	}
+	resultName, resultUID := splitRecordName(rec.Name)
+	_, skewed, _ := elapsed(run.Status.StartTime, run.Status.CompletionTime)
+	if skewed {
+		// Every listing that reads the run summarizes it again; its
+		// clockSkew field tells the caller.
+		slog.Debug("run completed before it started; the clocks that stamped it disagree",
+			"namespace", run.Metadata.Namespace, "name", run.Metadata.Name,
+			"startTime", run.Status.StartTime, "completionTime", run.Status.CompletionTime)
+	}
 	return RunSummary{
 		Name:           run.Metadata.Name,
+		GenerateName:   run.Metadata.GenerateName,
 		Namespace:      run.Metadata.Namespace,
 		UID:            chooseString(run.Metadata.UID, rec.Uid),
 		Labels:         run.Metadata.Labels,
+		PipelineTask:   run.Metadata.Labels[pipelineTaskLabel],
 		StartTime:      run.Status.StartTime,
 		CompletionTime: run.Status.CompletionTime,
 		Status:         status,
 		Reason:         reason,
+		Message:        summaryMessage(message),
+		Incomplete:     incomplete,
+		ClockSkew:      skewed,
 		RecordName:     rec.Name,
+		ResultName:     resultName,
+		ResultUID:      resultUID,
+		Change:         sourceChange(run.Metadata.Labels, run.Metadata.Annotations),
+		Workspaces:     workspaces(run.Spec.Workspaces),
 	}
 }
 
+// splitRecordName extracts the parent Result name and its id segment from a
+// record name of the form "<namespace>/results/<id>/records/<record>".
+func splitRecordName(recordName string) (resultName, resultUID string) {
+	parent, _, found := strings.Cut(recordName, "/records/")
+	if !found {
+		return "", ""
+	}
+	namespace, id, found := strings.Cut(parent, "/results/")
+	if !found || namespace == "" || id == "" || strings.Contains(id, "/") {
+		return "", ""
+	}
+	return parent, id
+}
+
+// nameSegments splits a Results API resource name into its segments. It
+// returns nil when a segment is empty, "." or "..", or holds a backslash, any
+// of which would change the request path the name is placed in.
+func nameSegments(name string) []string {
+	parts := strings.Split(name, "/")
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." || strings.Contains(part, `\`) {
+			return nil
+		}
+	}
+	return parts
+}
+
 func conditionStatus(conditions []struct {
 	Type    string `json:"type"`
 	Status  string `json:"status"`
 	Reason  string `json:"reason"`
 	Message string `json:"message"`
-}) (string, string) {
+}) (status, reason, message string) {
 	for _, cond := range conditions {
 		if cond.Type == "Succeeded" {
-			return cond.Status, cond.Reason
+			return cond.Status, cond.Reason, cond.Message
 		}
 	}
-	return "", ""
+	return "", "", ""
+}
+
+// maxSummaryMessage bounds, in runes, the condition message kept in a
+// summary. Messages of failed steps can embed whole error outputs.
+const maxSummaryMessage = 300
+
+// summaryMessage folds a condition message onto one line and truncates it
+// to maxSummaryMessage runes, marking the cut with an ellipsis.
+func summaryMessage(message string) string {
+	message = strings.Join(strings.Fields(message), " ")
+	if utf8.RuneCountInString(message) <= maxSummaryMessage {
+		return message
+	}
+	runes := []rune(message)
+	return strings.TrimSpace(string(runes[:maxSummaryMessage-1])) + "…"
 }
 
 func chooseString(primary, fallback string) string {
@@ -1,19 +1,27 @@
 package tektonresults
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 // mockRestClient is a test double for restClient
 type mockRestClient struct {
-	getRecordFunc   func(ctx context.Context, recordName string) (*record, error)
-	listResultsFunc func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error)
-	listRecordsFunc func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error)
-	getLogFunc      func(ctx context.Context, logPath string) ([]byte, error)
+	getRecordFunc    func(ctx context.Context, recordName string) (*record, error)
+	listResultsFunc  func(ctx context.Context, req listResultsRequest) (*listResultsResponse, error)
+	listRecordsFunc  func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error)
+	getLogFunc       func(ctx context.Context, logPath string) ([]byte, error)
+	deleteResultFunc func(ctx context.Context, resultName string) error
 }
 
 func (m *mockRestClient) getRecord(ctx context.Context, recordName string) (*record, error) {
@@ -44,6 +52,13 @@ func (m *mockRestClient) getLog(ctx context.Context, logPath string) ([]byte, er
 	return nil, fmt.Errorf("getLog not mocked")
 }
 
+func (m *mockRestClient) deleteResult(ctx context.Context, resultName string) error {
+	if m.deleteResultFunc != nil {
+		return m.deleteResultFunc(ctx, resultName)
+	}
+	return fmt.Errorf("deleteResult not mocked")
+}
+
 func TestService_GetRun_PipelineRun_DirectGetSuccess(t *testing.T) {
 	prUID := "pr-test-uid-123"
 	prName := "test-pipelinerun"
@@ -228,7 +243,8 @@ func TestService_GetRun_TaskRunInPipeline_FallbackSuccess(t *testing.T) {
 					"uid": "%s",
 					"labels": {
 						"tekton.dev/pipelineRun": "test-pipelinerun",
-						"tekton.dev/pipelineRunUID": "%s"
+						"tekton.dev/pipelineRunUID": "%s",
+						"tekton.dev/pipelineTask": "task1"
 					}
 				},
 				"spec": {},
@@ -255,6 +271,13 @@ func TestService_GetRun_TaskRunInPipeline_FallbackSuccess(t *testing.T) {
 		t.Errorf("Expected UID %s, got %s", trUID, detail.Summary.UID)
 	}
 
+	if detail.Summary.ResultName != fmt.Sprintf("%s/results/%s", namespace, prUID) || detail.Summary.ResultUID != prUID {
+		t.Errorf("Expected parent Result of the PipelineRun, got %s (%s)", detail.Summary.ResultName, detail.Summary.ResultUID)
+	}
+	if detail.Summary.PipelineTask != "task1" {
+		t.Errorf("Expected pipelineTask task1, got %q", detail.Summary.PipelineTask)
+	}
+
 	// Verify TaskRun is stored under PipelineRun's Result
 	expectedRecordPrefix := fmt.Sprintf("%s/results/%s/records/", namespace, prUID)
 	if !strings.HasPrefix(detail.RecordName, expectedRecordPrefix) {
@@ -475,3 +498,816 @@ func TestService_QueryRecords_EmptyResults(t *testing.T) {
 		t.Errorf("Expected 'no run found' error, got: %v", err)
 	}
 }
+
+// testRun describes a run stored by testRecords.
+type testRun struct {
+	Name      string // "run-<i>" when empty
+	Condition string // "status/reason" of the Succeeded condition; empty stores a run without status
+}
+
+// testRecords returns a PipelineRun record in namespace per run, newest
+// first, with the UIDs uid-0, uid-1 and so on.
+func testRecords(namespace string, runs ...testRun) []record {
+	var records []record
+	for i, run := range runs {
+		uid := fmt.Sprintf("uid-%d", i)
+		rec := record{Name: fmt.Sprintf("%s/results/%s/records/%s", namespace, uid, uid), Uid: uid}
+		name := cmp.Or(run.Name, fmt.Sprintf("run-%d", i))
+		status := `{}`
+		if run.Condition != "" {
+			s, reason, _ := strings.Cut(run.Condition, "/")
+			status = fmt.Sprintf(`{"conditions":[{"type":"Succeeded","status":"%s","reason":"%s"}]}`, s, reason)
+		}
+		rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"apiVersion":"tekton.dev/v1","kind":"PipelineRun","metadata":{"name":"%s","namespace":"%s","uid":"%s"},"spec":{},"status":%s}`, name, namespace, uid, status))
+		records = append(records, rec)
+	}
+	return records
+}
+
+// namedRuns returns n runs called name.
+func namedRuns(name string, n int) []testRun {
+	return slices.Repeat([]testRun{{Name: name}}, n)
+}
+
+func TestService_GetRun_ByName_Index(t *testing.T) {
+	namespace := "foo"
+	pages := 0
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			pages++
+			// Two pages of two records each, newest first
+			records := testRecords(namespace, namedRuns("nightly", 4)...)
+			if req.PageToken == "" {
+				return &listRecordsResponse{Records: records[:2], NextPageToken: "page-2"}, nil
+			}
+			return &listRecordsResponse{Records: records[2:]}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+
+	detail, err := service.getRun(context.Background(), resourceKindPipelineRun, RunSelector{
+		Namespace: namespace,
+		Name:      "nightly",
+		Index:     2,
+	})
+	if err != nil {
+		t.Fatalf("getRun() with Index=2 failed: %v", err)
+	}
+	if detail.Summary.UID != "uid-2" {
+		t.Errorf("Expected UID 'uid-2', got %s", detail.Summary.UID)
+	}
+	if pages != 2 {
+		t.Errorf("Expected 2 pages to be read, got %d", pages)
+	}
+}
+
+func TestService_GetRun_CreationWindow(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			for _, want := range []string{`create_time>=timestamp("2024-05-01T00:00:00Z")`, `create_time<timestamp("2024-05-02T00:00:00Z")`} {
+				if !strings.Contains(req.Filter, want) {
+					t.Errorf("Expected %s in the filter, got %s", want, req.Filter)
+				}
+			}
+			return &listRecordsResponse{Records: testRecords("foo", namedRuns("nightly", 1)...)}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	_, err := service.getRun(context.Background(), resourceKindPipelineRun, RunSelector{
+		Namespace:     "foo",
+		Name:          "nightly",
+		CreatedAfter:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBefore: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("getRun() error = %v", err)
+	}
+}
+
+func TestService_GetRun_ByName_IndexOutOfRange(t *testing.T) {
+	namespace := "foo"
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords(namespace, namedRuns("nightly", 2)...)}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+
+	_, err := service.getRun(context.Background(), resourceKindPipelineRun, RunSelector{
+		Namespace: namespace,
+		Name:      "nightly",
+		Index:     5,
+	})
+	if err == nil {
+		t.Fatal("Expected out of range error, got nil")
+	}
+	if !strings.Contains(err.Error(), "only 2 run(s) match") {
+		t.Errorf("Expected out of range error, got: %v", err)
+	}
+}
+
+func TestService_GetRun_SelectLast_ReportsHistory(t *testing.T) {
+	namespace := "foo"
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords(namespace, namedRuns("nightly", 5)...)}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+
+	detail, err := service.getRun(context.Background(), resourceKindPipelineRun, RunSelector{
+		Namespace:  namespace,
+		Name:       "nightly",
+		SelectLast: true,
+	})
+	if err != nil {
+		t.Fatalf("getRun() failed: %v", err)
+	}
+	if detail.Summary.UID != "uid-0" {
+		t.Errorf("Expected most recent run uid-0, got %s", detail.Summary.UID)
+	}
+	if detail.History == nil {
+		t.Fatal("Expected match history to be set")
+	}
+	if detail.History.Matches != 5 || detail.History.Truncated {
+		t.Errorf("Expected 5 complete matches, got %+v", detail.History)
+	}
+}
+
+func TestService_GetRun_SelectLast_HistoryTruncated(t *testing.T) {
+	namespace := "foo"
+	calls := 0
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			calls++
+			// Never ending history
+			return &listRecordsResponse{Records: testRecords(namespace, namedRuns("nightly", 2)...), NextPageToken: fmt.Sprintf("page-%d", calls)}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+
+	detail, err := service.getRun(context.Background(), resourceKindPipelineRun, RunSelector{
+		Namespace:  namespace,
+		Name:       "nightly",
+		SelectLast: true,
+	})
+	if err != nil {
+		t.Fatalf("getRun() failed: %v", err)
+	}
+	if detail.History == nil || !detail.History.Truncated || detail.History.Matches != 2 {
+		t.Fatalf("Expected a lower bound of 2 matches, got %+v", detail.History)
+	}
+	// The count covers the page already read; no pages are fetched for it.
+	if calls != 1 {
+		t.Errorf("Expected 1 page to be read, got %d", calls)
+	}
+}
+
+func TestService_GetRun_SingleMatch_NoHistory(t *testing.T) {
+	namespace := "foo"
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			return &listRecordsResponse{Records: testRecords(namespace, namedRuns("nightly", 1)...)}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+
+	detail, err := service.getRun(context.Background(), resourceKindPipelineRun, RunSelector{
+		Namespace:  namespace,
+		Name:       "nightly",
+		SelectLast: true,
+	})
+	if err != nil {
+		t.Fatalf("getRun() failed: %v", err)
+	}
+	if detail.History != nil {
+		t.Errorf("Expected no history for a unique match, got %+v", detail.History)
+	}
+}
+
+func TestService_ListRuns_MultipleNamespaces(t *testing.T) {
+	starts := map[string][]string{
+		"ci":      {"2024-01-03T00:00:00Z", "2024-01-01T00:00:00Z"},
+		"staging": {"2024-01-02T00:00:00Z"},
+	}
+	var mu sync.Mutex
+	var parents []string
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			mu.Lock()
+			parents = append(parents, req.Parent)
+			mu.Unlock()
+			ns := strings.TrimSuffix(req.Parent, "/results/-")
+			var records []record
+			for i, start := range starts[ns] {
+				uid := fmt.Sprintf("%s-%d", ns, i)
+				rec := record{Name: fmt.Sprintf("%s/results/%s/records/%s", ns, uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":"%s","namespace":"%s","uid":"%s"},"status":{"startTime":"%s"}}`, uid, ns, uid, start))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "ci, staging,ci", Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+
+	if len(parents) != 2 {
+		t.Errorf("Expected one query per distinct namespace, got %v", parents)
+	}
+	if len(summaries) != 2 || summaries[0].UID != "ci-0" || summaries[1].UID != "staging-0" {
+		t.Errorf("Expected merged newest-first results limited to 2, got %+v", summaries)
+	}
+}
+
+func TestService_ListRuns_MultipleNamespacesError(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if strings.HasPrefix(req.Parent, "restricted/") {
+				return nil, fmt.Errorf(`results API GET: {"code":7,"message":"permission denied"}`)
+			}
+			return &listRecordsResponse{}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	_, err := service.ListTaskRuns(context.Background(), ListOptions{Namespace: "ci,restricted"})
+	if err == nil || !strings.Contains(err.Error(), "namespace restricted") {
+		t.Errorf("Expected error naming the failing namespace, got %v", err)
+	}
+}
+
+func TestService_ListRuns_MultipleNamespacesParallelism(t *testing.T) {
+	var inFlight, most atomic.Int32
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			return &listRecordsResponse{}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	namespaces := make([]string, MaxNamespaces)
+	for i := range namespaces {
+		namespaces[i] = fmt.Sprintf("ns-%d", i)
+	}
+	if _, err := service.ListTaskRuns(context.Background(), ListOptions{Namespace: strings.Join(namespaces, ",")}); err != nil {
+		t.Fatalf("ListTaskRuns() error = %v", err)
+	}
+	if got := most.Load(); got > namespaceParallelism {
+		t.Errorf("Expected at most %d namespaces queried at once, got %d", namespaceParallelism, got)
+	}
+}
+
+func TestService_ListRuns_NegativeLabelSelector(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if strings.Contains(req.Filter, "dogfood") || strings.Contains(req.Filter, "experimental") {
+				t.Errorf("Negative clauses must not be pushed into the CEL filter: %s", req.Filter)
+			}
+			if !strings.Contains(req.Filter, `data.metadata.labels["app"]=="web"`) {
+				t.Errorf("Expected equality clause in filter, got %s", req.Filter)
+			}
+			var records []record
+			for i, labels := range []string{`{"app":"web"}`, `{"app":"web","env":"dogfood"}`, `{"app":"web","experimental":"yes"}`} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":"run-%d","namespace":"foo","uid":"%s","labels":%s}}`, i, uid, labels))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{
+		Namespace:     "foo",
+		LabelSelector: "app=web,env!=dogfood,!experimental",
+	})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].UID != "uid-0" {
+		t.Errorf("Expected only uid-0 to match, got %+v", summaries)
+	}
+}
+
+func TestService_ListRuns_NameRegex(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			var records []record
+			for i, name := range []string{"build-1a2b3c4-x7k2p", "build-manual", "nightly-build-9f8e7d6-abcde"} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":%q,"namespace":"foo","uid":"%s"}}`, name, uid))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{
+		Namespace: "foo",
+		NameRegex: `^build-[0-9a-f]{7}-`,
+	})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].UID != "uid-0" {
+		t.Errorf("Expected only uid-0 to match, got %+v", summaries)
+	}
+
+	detail, err := service.GetPipelineRun(context.Background(), RunSelector{Namespace: "foo", NameRegex: "manual$"})
+	if err != nil {
+		t.Fatalf("GetPipelineRun() error = %v", err)
+	}
+	if detail.Summary.UID != "uid-1" {
+		t.Errorf("Expected uid-1 to match, got %+v", detail.Summary)
+	}
+}
+
+func TestService_ListRuns_AnnotationSelector(t *testing.T) {
+	const sha = "3f2a1c9e8b7d6f5a4c3b2a1908f7e6d5c4b3a291"
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if !strings.Contains(req.Filter, `data.metadata.annotations["pipelinesascode.tekton.dev/sha"]=="`+sha+`"`) {
+				t.Errorf("Expected annotation clause in filter, got %s", req.Filter)
+			}
+			var records []record
+			// The server may ignore the filter, so every record is checked again.
+			for i, annotations := range []string{`{"pipelinesascode.tekton.dev/sha":"` + sha + `"}`, `{"pipelinesascode.tekton.dev/sha":"` + sha + `","skip":"yes"}`, `{}`} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":"run-%d","namespace":"foo","uid":"%s","annotations":%s}}`, i, uid, annotations))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{
+		Namespace:          "foo",
+		AnnotationSelector: "pipelinesascode.tekton.dev/sha=" + sha + ",!skip",
+	})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].UID != "uid-0" {
+		t.Errorf("Expected only uid-0 to match, got %+v", summaries)
+	}
+
+	detail, err := service.GetPipelineRun(context.Background(), RunSelector{Namespace: "foo", AnnotationSelector: "pipelinesascode.tekton.dev/sha=" + sha, SelectLast: true})
+	if err != nil {
+		t.Fatalf("GetPipelineRun() error = %v", err)
+	}
+	if detail.Summary.UID != "uid-0" {
+		t.Errorf("Expected the newest matching run uid-0, got %+v", detail.Summary)
+	}
+}
+
+func TestService_ListRuns_RefName(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if !strings.Contains(req.Filter, `(data.spec.pipelineRef.name=="build" || data.metadata.labels["tekton.dev/pipeline"]=="build")`) {
+				t.Errorf("Expected reference clause in filter, got %s", req.Filter)
+			}
+			if !strings.Contains(req.Fields, "spec.pipelineRef.name") {
+				t.Errorf("Expected the reference name in the field mask, got %s", req.Fields)
+			}
+			var records []record
+			for i, data := range []string{
+				`"spec":{"pipelineRef":{"name":"build"}}`,
+				// Embedded spec: the label names the pipeline.
+				`"metadata":{"labels":{"tekton.dev/pipeline":"build"}}`,
+				// The reference wins over a stale label.
+				`"spec":{"pipelineRef":{"name":"deploy"}},"metadata":{"labels":{"tekton.dev/pipeline":"build"}}`,
+			} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"kind":"PipelineRun",%s}`, data))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", RefName: "build"})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].UID != "uid-0" || summaries[1].UID != "uid-1" {
+		t.Errorf("Expected uid-0 and uid-1 to match, got %+v", summaries)
+	}
+	for _, summary := range summaries {
+		if summary.Ref != "build" {
+			t.Errorf("Expected %s to reference build, got %q", summary.UID, summary.Ref)
+		}
+	}
+}
+
+func TestService_ListRuns_ServiceAccount(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if !strings.Contains(req.Fields, "spec.taskRunTemplate.serviceAccountName") {
+				t.Errorf("Expected the service account in the field mask, got %s", req.Fields)
+			}
+			var records []record
+			for i, spec := range []string{
+				`{"taskRunTemplate":{"serviceAccountName":"deployer"}}`,
+				// v1beta1 PipelineRuns name the account in the spec.
+				`{"serviceAccountName":"deployer"}`,
+				`{"taskRunTemplate":{"serviceAccountName":"default"}}`,
+				`{}`,
+			} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"kind":"PipelineRun","spec":%s}`, spec))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", ServiceAccount: "deployer"})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].UID != "uid-0" || summaries[1].UID != "uid-1" {
+		t.Errorf("Expected uid-0 and uid-1 to match, got %+v", summaries)
+	}
+}
+
+func TestService_ListRuns_LatestOnly(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			var records []record
+			for i, meta := range []string{
+				`"name":"nightly","namespace":"foo"},"status":{"startTime":"2024-05-02T00:00:00Z"`,
+				`"name":"nightly","namespace":"foo"},"status":{"startTime":"2024-05-03T00:00:00Z"`,
+				`"name":"nightly","namespace":"foo"},"status":{"startTime":"2024-05-01T00:00:00Z"`,
+				`"name":"build-x7k2p","generateName":"build-","namespace":"foo"},"status":{"startTime":"2024-05-01T00:00:00Z"`,
+				`"name":"build-9qz4d","generateName":"build-","namespace":"foo"},"status":{"startTime":"2024-04-30T00:00:00Z"`,
+				`"name":"nightly","namespace":"bar"},"status":{"startTime":"2024-05-01T00:00:00Z"`,
+			} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"kind":"PipelineRun","metadata":{%s}}`, meta))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", LatestOnly: true})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	got := map[string]int{}
+	for _, s := range summaries {
+		got[s.UID] = s.Duplicates
+	}
+	want := map[string]int{"uid-1": 2, "uid-3": 1, "uid-5": 0}
+	if len(got) != len(want) {
+		t.Fatalf("Expected runs %v, got %+v", want, summaries)
+	}
+	for uid, duplicates := range want {
+		if d, ok := got[uid]; !ok || d != duplicates {
+			t.Errorf("Expected %s with %d duplicates, got %+v", uid, duplicates, summaries)
+		}
+	}
+}
+
+func TestService_ListRunPage_Continues(t *testing.T) {
+	records := testRecords("foo", namedRuns("nightly", 5)...)
+	after := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if !strings.Contains(req.Filter, `create_time>=timestamp("2024-05-01T00:00:00Z")`) {
+				t.Errorf("Expected the window of the first page, got %s", req.Filter)
+			}
+			// Pages of three whatever the page size, so listings stop
+			// inside them. The upstream token is the offset of the page.
+			offset := 0
+			if req.PageToken != "" {
+				offset, _ = strconv.Atoi(req.PageToken)
+			}
+			end := min(offset+3, len(records))
+			resp := &listRecordsResponse{Records: records[offset:end]}
+			if end < len(records) {
+				resp.NextPageToken = strconv.Itoa(end)
+			}
+			return resp, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	var uids []string
+	opts := ListOptions{Namespace: "foo", CreatedAfter: after, Limit: 2}
+	for calls := 1; ; calls++ {
+		if calls > 3 {
+			t.Fatalf("Expected three pages, got more: %v", uids)
+		}
+		page, err := service.ListPipelineRunPage(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ListPipelineRunPage() error = %v", err)
+		}
+		for _, run := range page.Runs {
+			uids = append(uids, run.UID)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+		// A relative bound resolves to a later time on the next call; the
+		// token keeps the original window.
+		opts.CreatedAfter = after.Add(time.Hour)
+	}
+	if want := []string{"uid-0", "uid-1", "uid-2", "uid-3", "uid-4"}; !slices.Equal(uids, want) {
+		t.Errorf("Expected every run once, got %v", uids)
+	}
+
+	_, err := service.ListTaskRunPage(context.Background(), ListOptions{Namespace: "foo", PageToken: opts.PageToken})
+	if err == nil || !strings.Contains(err.Error(), "different query") {
+		t.Errorf("Expected a token of another query to be rejected, got %v", err)
+	}
+	_, err = service.ListPipelineRunPage(context.Background(), ListOptions{Namespace: "foo,bar", PageToken: opts.PageToken})
+	if err == nil || !strings.Contains(err.Error(), "several namespaces") {
+		t.Errorf("Expected page tokens to be rejected for a namespace list, got %v", err)
+	}
+}
+
+func TestService_ListRunPage_InMemoryFilterBudget(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		sizes []int32
+	)
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			sizes = append(sizes, req.PageSize)
+			// One matching run on the first page, then none.
+			spec := `{}`
+			if req.PageToken == "" {
+				spec = `{"taskRunTemplate":{"serviceAccountName":"deployer"}}`
+			}
+			uid := fmt.Sprintf("uid-%d", len(sizes))
+			rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+			rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"kind":"PipelineRun","spec":%s}`, spec))
+			return &listRecordsResponse{Records: []record{rec}, NextPageToken: strconv.Itoa(len(sizes))}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+	service.maxScanPages.Store(3)
+
+	opts := ListOptions{Namespace: "foo", ServiceAccount: "deployer", Limit: 2}
+	page, err := service.ListPipelineRunPage(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ListPipelineRunPage() error = %v", err)
+	}
+	if len(page.Runs) != 1 || !page.Partial || page.NextPageToken == "" {
+		t.Errorf("Expected a partial page with a token, got %+v", page)
+	}
+	if !slices.Equal(sizes, []int32{maxPageSize, maxPageSize, maxPageSize}) {
+		t.Errorf("Expected three full pages, got sizes %v", sizes)
+	}
+
+	// Listings without page tokens keep the runs found, and so do listings
+	// across namespaces, whose page is partial without a token.
+	if runs, err := service.ListPipelineRuns(context.Background(), opts); err != nil || len(runs) != 1 {
+		t.Errorf("Expected the run found within the budget, got %v, %v", runs, err)
+	}
+	opts.Namespace = "foo,bar"
+	page, err = service.ListPipelineRunPage(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ListPipelineRunPage() across namespaces error = %v", err)
+	}
+	if len(page.Runs) != 2 || !page.Partial || page.NextPageToken != "" {
+		t.Errorf("Expected a partial page without a token, got %+v", page)
+	}
+}
+
+func TestSplitRecordName(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantUID  string
+	}{
+		{"foo/results/pr-uid/records/tr-uid", "foo/results/pr-uid", "pr-uid"},
+		{"foo/results/pr-uid", "", ""},
+		{"/results/pr-uid/records/tr-uid", "", ""},
+		{"foo/results//records/tr-uid", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		name, uid := splitRecordName(tt.in)
+		if name != tt.wantName || uid != tt.wantUID {
+			t.Errorf("splitRecordName(%q) = (%q, %q), want (%q, %q)", tt.in, name, uid, tt.wantName, tt.wantUID)
+		}
+	}
+}
+
+func TestService_GetRun_ScanBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		selector   RunSelector
+		matchPage  int // page on which the only match appears; 0 for none
+		wantErr    string
+		wantPages  int
+		wantResult string
+	}{
+		{
+			name:      "no match aborts at budget",
+			selector:  RunSelector{Namespace: "foo", Name: "target", SelectLast: true},
+			wantErr:   "without finding the requested run",
+			wantPages: 3,
+		},
+		{
+			name:       "latest match returned when budget runs out",
+			selector:   RunSelector{Namespace: "foo", Name: "target", SelectLast: true},
+			matchPage:  2,
+			wantPages:  3,
+			wantResult: "target",
+		},
+		{
+			name:      "ambiguity check aborts at budget",
+			selector:  RunSelector{Namespace: "foo", Name: "target"},
+			matchPage: 1,
+			wantErr:   "found foo/target, but could not confirm it is the only run",
+			wantPages: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			mockClient := &mockRestClient{
+				listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+					pages++
+					if req.PageSize != 10 {
+						t.Errorf("Expected configured page size 10, got %d", req.PageSize)
+					}
+					name := "other"
+					if pages == tt.matchPage {
+						name = "target"
+					}
+					records := testRecords("foo", namedRuns(name, 1)...)
+					return &listRecordsResponse{Records: records, NextPageToken: "more"}, nil
+				},
+			}
+
+			service := &Service{client: mockClient}
+			if err := service.Reconfigure(Settings{ScanPageSize: 10, MaxScanPages: 3}); err != nil {
+				t.Fatalf("Reconfigure() error = %v", err)
+			}
+			detail, err := service.getRun(context.Background(), resourceKindPipelineRun, tt.selector)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("getRun() error = %v, wantErr %q", err, tt.wantErr)
+			}
+			if err != nil && (!strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "narrow your query")) {
+				t.Errorf("Expected %q and a narrow-your-query hint, got %v", tt.wantErr, err)
+			}
+			if pages != tt.wantPages {
+				t.Errorf("Expected %d pages to be scanned, got %d", tt.wantPages, pages)
+			}
+			if tt.wantResult != "" && detail.Summary.Name != tt.wantResult {
+				t.Errorf("Expected %s, got %s", tt.wantResult, detail.Summary.Name)
+			}
+		})
+	}
+}
+
+func TestNewService_InvalidScanSettings(t *testing.T) {
+	for _, overrides := range []Overrides{
+		{Host: "https://results.example.com", ScanPageSize: 500},
+		{Host: "https://results.example.com", MaxScanPages: -1},
+	} {
+		if _, err := NewService(nil, overrides); err == nil {
+			t.Errorf("Expected error for %+v", overrides)
+		}
+	}
+}
+
+func TestService_RejectsInvalidFilterInput(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			t.Errorf("API must not be called with invalid filter input, got filter %s", req.Filter)
+			return &listRecordsResponse{}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	if _, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", LabelSelector: `app=x") || ("`}); err == nil {
+		t.Error("Expected invalid label value to be rejected")
+	}
+	if _, err := service.GetTaskRun(context.Background(), RunSelector{Namespace: "foo", Name: "run\r\n"}); err == nil {
+		t.Error("Expected control characters in name to be rejected")
+	}
+	if _, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", NameRegex: "build-("}); err == nil || !strings.Contains(err.Error(), "invalid nameRegex") {
+		t.Errorf("Expected invalid nameRegex to be rejected, got %v", err)
+	}
+}
+
+func TestService_GetRunByRecord(t *testing.T) {
+	recordName := "foo/results/pr-uid/records/tr-uid"
+	mockClient := &mockRestClient{
+		getRecordFunc: func(ctx context.Context, name string) (*record, error) {
+			if name != recordName {
+				t.Errorf("Expected record %s, got %s", recordName, name)
+			}
+			rec := record{Name: recordName, Uid: "tr-uid"}
+			rec.Data.Value = json.RawMessage(`{"kind":"TaskRun","metadata":{"name":"build","namespace":"foo","uid":"tr-uid"}}`)
+			return &rec, nil
+		},
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			t.Error("GetRunByRecord must not search")
+			return &listRecordsResponse{}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	detail, err := service.GetRunByRecord(context.Background(), " /"+recordName+" ")
+	if err != nil {
+		t.Fatalf("GetRunByRecord() error = %v", err)
+	}
+	if detail.Summary.Name != "build" || detail.Summary.ResultUID != "pr-uid" {
+		t.Errorf("Unexpected summary: %+v", detail.Summary)
+	}
+
+	for _, invalid := range []string{
+		"", "foo/results/pr-uid", "foo/results/pr-uid/records/", "pr-uid",
+		"ci/results/x/records/../../../../../api/v1/namespaces/kube-system/secrets",
+		"ci/results/x/records/./tr-uid", `ci/results/x/records/..\secrets`, "ci//results/x/records/tr-uid",
+	} {
+		if _, err := service.GetRunByRecord(context.Background(), invalid); err == nil {
+			t.Errorf("Expected error for record name %q", invalid)
+		}
+	}
+}
+
+func TestSummarizeRun_IncompleteRecord(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		value      string
+		status     string
+		incomplete bool
+	}{
+		{"no status", `{"metadata":{"name":"pr","namespace":"ci"}}`, "Unknown", true},
+		{"no Succeeded condition", `{"metadata":{"name":"pr","namespace":"ci"},"status":{"startTime":"2025-01-01T10:00:00Z","conditions":[]}}`, "Unknown", true},
+		{"running", `{"metadata":{"name":"pr","namespace":"ci"},"status":{"conditions":[{"type":"Succeeded","status":"Unknown","reason":"Running"}]}}`, "Unknown", false},
+		{"succeeded", `{"metadata":{"name":"pr","namespace":"ci"},"status":{"conditions":[{"type":"Succeeded","status":"True","reason":"Succeeded"}]}}`, "True", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := record{Name: "ci/results/r1/records/r1", Uid: "r1"}
+			rec.Data.Value = json.RawMessage(tt.value)
+			run, err := decodeRun(rec)
+			if err != nil {
+				t.Fatalf("decodeRun() error = %v", err)
+			}
+			summary := summarizeRun(run, rec)
+			if summary.Status != tt.status || summary.Incomplete != tt.incomplete {
+				t.Errorf("Got status %q incomplete=%v, want %q incomplete=%v", summary.Status, summary.Incomplete, tt.status, tt.incomplete)
+			}
+		})
+	}
+}
+
+func TestSummarizeRun_Message(t *testing.T) {
+	rec := record{Name: "ci/results/r1/records/r1", Uid: "r1"}
+	rec.Data.Value = json.RawMessage(`{"metadata":{"name":"pr","namespace":"ci"},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"Failed","message":"Tasks Completed: 3 (Failed: 1, Cancelled 0),\n  Skipped: 0"}]}}`)
+	run, err := decodeRun(rec)
+	if err != nil {
+		t.Fatalf("decodeRun() error = %v", err)
+	}
+	if got := summarizeRun(run, rec).Message; got != "Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 0" {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
+
+func TestSummaryMessage_Truncates(t *testing.T) {
+	got := summaryMessage(strings.Repeat("é", maxSummaryMessage+50))
+	if utf8.RuneCountInString(got) != maxSummaryMessage || !strings.HasSuffix(got, "é…") {
+		t.Errorf("Expected a message of %d runes ending in an ellipsis, got %d runes", maxSummaryMessage, utf8.RuneCountInString(got))
+	}
+	if got := summaryMessage(""); got != "" {
+		t.Errorf("Expected an empty message to stay empty, got %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package tektonresults
+
+import "fmt"
+
+// Settings are the parts of the Service configuration that can change while
+// it serves requests, for example when the configuration file is reloaded.
+type Settings struct {
+	ScanPageSize    int32 // page size for single-run lookups; 0 uses the default of 50
+	MaxScanPages    int   // pages a single-run lookup may scan; 0 uses the default of 20
+	NamespaceTokens []NamespaceToken
+	Teams           []Team
+}
+
+// Reconfigure validates settings and applies them to requests started from
+// now on. Requests in flight keep the settings they started with. Nothing
+// changes when validation fails.
+func (s *Service) Reconfigure(settings Settings) error {
+	prepared, err := s.prepare(settings)
+	if err != nil {
+		return err
+	}
+	s.scanPageSize.Store(settings.ScanPageSize)
+	s.maxScanPages.Store(int64(settings.MaxScanPages))
+	s.teams.Store(prepared.teams)
+	if s.rest != nil {
+		s.rest.tokens.Store(prepared.tokens)
+	}
+	return nil
+}
+
+// ValidateSettings checks settings the way Reconfigure would, without
+// applying them.
+func (s *Service) ValidateSettings(settings Settings) error {
+	_, err := s.prepare(settings)
+	return err
+}
+
+// preparedSettings are the parsed parts of Settings.
+type preparedSettings struct {
+	tokens *namespaceTokens
+	teams  *teamMap
+}
+
+func (s *Service) prepare(settings Settings) (preparedSettings, error) {
+	if settings.ScanPageSize < 0 || settings.ScanPageSize > maxPageSize {
+		return preparedSettings{}, fmt.Errorf("scan page size must be between 1 and %d", maxPageSize)
+	}
+	if settings.MaxScanPages < 0 {
+		return preparedSettings{}, fmt.Errorf("max scan pages must be positive")
+	}
+	tokens, err := newNamespaceTokens(settings.NamespaceTokens)
+	if err != nil {
+		return preparedSettings{}, err
+	}
+	if tokens != nil && s.rest == nil {
+		return preparedSettings{}, fmt.Errorf("namespace tokens require a Results API client")
+	}
+	teams, err := newTeamMap(settings.Teams)
+	if err != nil {
+		return preparedSettings{}, err
+	}
+	return preparedSettings{tokens: tokens, teams: teams}, nil
+}
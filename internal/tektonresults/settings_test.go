@@ -0,0 +1,70 @@
+package tektonresults
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestService_Reconfigure(t *testing.T) {
+	baseURL, _ := url.Parse("https://results.example" + customAPIPath)
+	rc := &restClient{baseURL: baseURL}
+	service := &Service{client: rc, rest: rc}
+
+	if err := service.Reconfigure(Settings{
+		ScanPageSize:    100,
+		MaxScanPages:    5,
+		NamespaceTokens: []NamespaceToken{{Namespaces: []string{"ci"}, Token: "scoped"}},
+	}); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if service.lookupPageSize() != 100 || service.lookupPageBudget() != 5 {
+		t.Errorf("Expected new limits, got page size %d and budget %d", service.lookupPageSize(), service.lookupPageBudget())
+	}
+	if token, _ := rc.tokens.Load().tokenFor("ci"); token != "scoped" {
+		t.Errorf("Expected scoped token for ci, got %q", token)
+	}
+
+	invalid := []Settings{
+		{ScanPageSize: maxPageSize + 1},
+		{MaxScanPages: -1},
+		{NamespaceTokens: []NamespaceToken{{Namespaces: []string{"ci"}}}},
+	}
+	for _, settings := range invalid {
+		if err := service.Reconfigure(settings); err == nil {
+			t.Errorf("Reconfigure(%+v): expected error", settings)
+		}
+	}
+	if service.lookupPageSize() != 100 || service.lookupPageBudget() != 5 || rc.tokens.Load() == nil {
+		t.Error("Expected failed reconfiguration to keep the previous settings")
+	}
+
+	// Clearing the settings restores the defaults and the default credential.
+	if err := service.Reconfigure(Settings{}); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if service.lookupPageSize() != describePageSize || service.lookupPageBudget() != defaultMaxScanPages {
+		t.Errorf("Expected default limits, got page size %d and budget %d", service.lookupPageSize(), service.lookupPageBudget())
+	}
+	if token, _ := rc.tokens.Load().tokenFor("ci"); token != "" {
+		t.Errorf("Expected no scoped token after clearing, got %q", token)
+	}
+}
+
+func TestService_Reconfigure_TokensWithoutClient(t *testing.T) {
+	service := &Service{client: &mockRestClient{}}
+	settings := Settings{NamespaceTokens: []NamespaceToken{{Namespaces: []string{"ci"}, Token: "t"}}}
+	err := service.Reconfigure(settings)
+	if err == nil || !strings.Contains(err.Error(), "require a Results API client") {
+		t.Errorf("Expected error, got %v", err)
+	}
+	if err := service.ValidateSettings(settings); err == nil {
+		t.Error("Expected ValidateSettings to reject what Reconfigure rejects")
+	}
+	if err := service.ValidateSettings(Settings{Teams: []Team{{Name: "payments", Selectors: []string{"team=payments"}}}}); err != nil {
+		t.Errorf("ValidateSettings() error = %v", err)
+	}
+	if names := service.teams.Load().names(); len(names) != 0 {
+		t.Errorf("Expected ValidateSettings to apply nothing, got teams %v", names)
+	}
+}
@@ -0,0 +1,220 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sinceFields requests the record creation time along with the usual list
+// fields, since the cursor is built from it.
+const sinceFields = listFields + ",records.create_time"
+
+// SinceOptions selects the runs returned by RunsSince.
+type SinceOptions struct {
+	Kind          string // "pipelinerun" or "taskrun"
+	Namespace     string // a single namespace, or "-" for all namespaces
+	LabelSelector string
+	Cursor        string // from a previous RunsSince call; empty to start
+	Limit         int
+}
+
+// SinceResult holds runs created after a cursor, oldest first.
+type SinceResult struct {
+	Runs   []RunSummary `json:"runs"`
+	Cursor string       `json:"cursor"`         // pass to the next call to continue after the last run
+	More   bool         `json:"more,omitempty"` // further runs are already available
+}
+
+// sinceCursor is the decoded form of SinceResult.Cursor. Records are ordered
+// by creation time, which is not unique, so the cursor also remembers the
+// UIDs already returned for its timestamp. Records skipped by the label
+// selector still advance the cursor, so it is bound to the query hash too.
+type sinceCursor struct {
+	Kind      string    `json:"k"`
+	Namespace string    `json:"n"`
+	Query     string    `json:"q"`
+	Time      time.Time `json:"t"`
+	UIDs      []string  `json:"u,omitempty"`
+}
+
+func (c sinceCursor) encode() string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeSinceCursor(s string) (sinceCursor, error) {
+	var c sinceCursor
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(s))
+	if err == nil {
+		err = json.Unmarshal(payload, &c)
+	}
+	if err != nil || c.Time.IsZero() {
+		return sinceCursor{}, fmt.Errorf("invalid cursor; pass the cursor returned by a previous call unchanged")
+	}
+	return c, nil
+}
+
+func sinceQuery(kind resourceKind, namespace, labelSelector string) pageQuery {
+	return pageQuery{Kind: string(kind), Namespace: namespace, LabelSelector: labelSelector}
+}
+
+// advance moves the cursor past a returned record.
+func (c *sinceCursor) advance(created time.Time, uid string) {
+	if !created.Equal(c.Time) {
+		c.Time = created
+		c.UIDs = nil
+	}
+	c.UIDs = append(c.UIDs, uid)
+}
+
+func (c sinceCursor) seen(created time.Time, uid string) bool {
+	if !created.Equal(c.Time) {
+		return false
+	}
+	for _, u := range c.UIDs {
+		if u == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// RunsSince returns runs created after the cursor, oldest first, along with
+// a cursor for the next call. Without a cursor it returns the most recent
+// runs, so a polling client starts from the current state.
+func (s *Service) RunsSince(ctx context.Context, opts SinceOptions) (*SinceResult, error) {
+	kind := resourceKind(strings.ToLower(strings.TrimSpace(opts.Kind)))
+	if _, ok := resourceTypeFilters[kind]; !ok {
+		return nil, fmt.Errorf("kind must be %q or %q", resourceKindPipelineRun, resourceKindTaskRun)
+	}
+	namespaces, err := splitNamespaces(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(namespaces) > 1 {
+		return nil, fmt.Errorf("runs since a cursor can be listed for one namespace or all namespaces ('-'), not a list")
+	}
+	namespace := "-"
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	if opts.Cursor == "" {
+		return s.runsSinceStart(ctx, kind, namespace, opts.LabelSelector, limit)
+	}
+	cursor, err := decodeSinceCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Kind != string(kind) || cursor.Namespace != namespace {
+		return nil, fmt.Errorf("cursor was issued for %s in namespace %q; repeat the same kind and namespace", cursor.Kind, cursor.Namespace)
+	}
+	if cursor.Query != sinceQuery(kind, namespace, opts.LabelSelector).hash() {
+		return nil, fmt.Errorf("cursor was issued for a different labelSelector; repeat the same labelSelector or start again without a cursor")
+	}
+
+	labelFilters, err := parseLabelSelector(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := newFilterBuilder(kind).labels(labelFilters.equals).createdSince(cursor.Time).build()
+	if err != nil {
+		return nil, err
+	}
+	// Records already returned at the cursor time come back first, so they
+	// are added to the page size.
+	req := listRecordsRequest{
+		Parent:   parentForNamespace(namespace),
+		Filter:   filter,
+		OrderBy:  "create_time asc",
+		PageSize: min(int32(limit+len(cursor.UIDs)), maxPageSize),
+		Fields:   sinceFields,
+	}
+
+	result := &SinceResult{Runs: []RunSummary{}}
+	for {
+		resp, err := s.client.listRecords(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		s.runs.observe(resp.Records)
+		for _, rec := range resp.Records {
+			if cursor.seen(rec.CreateTime, rec.Uid) || rec.CreateTime.Before(cursor.Time) {
+				continue
+			}
+			if len(result.Runs) == limit {
+				result.More = true
+				break
+			}
+			run, err := decodeRun(rec)
+			if err != nil {
+				return nil, err
+			}
+			// Records filtered out locally still move the cursor so they
+			// are not scanned again.
+			cursor.advance(rec.CreateTime, rec.Uid)
+			if matchesLabels(run.Metadata.Labels, labelFilters) {
+				result.Runs = append(result.Runs, s.summarize(run, rec))
+			}
+		}
+		if result.More || resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	result.Cursor = cursor.encode()
+	return result, nil
+}
+
+// runsSinceStart returns the newest runs, oldest first, and a cursor after
+// the newest of them. With no runs at all the cursor starts at the current
+// time.
+func (s *Service) runsSinceStart(ctx context.Context, kind resourceKind, namespace, labelSelector string, limit int) (*SinceResult, error) {
+	labelFilters, err := parseLabelSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := newFilterBuilder(kind).labels(labelFilters.equals).build()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.listRecords(ctx, listRecordsRequest{
+		Parent:   parentForNamespace(namespace),
+		Filter:   filter,
+		OrderBy:  "create_time desc",
+		PageSize: min(int32(limit), maxPageSize),
+		Fields:   sinceFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.runs.observe(resp.Records)
+
+	cursor := sinceCursor{Kind: string(kind), Namespace: namespace, Query: sinceQuery(kind, namespace, labelSelector).hash(), Time: time.Now().UTC()}
+	result := &SinceResult{Runs: []RunSummary{}}
+	// Walk oldest first so the cursor ends on the newest record.
+	for i := len(resp.Records) - 1; i >= 0; i-- {
+		rec := resp.Records[i]
+		run, err := decodeRun(rec)
+		if err != nil {
+			return nil, err
+		}
+		if rec.CreateTime.IsZero() {
+			continue
+		}
+		cursor.advance(rec.CreateTime, rec.Uid)
+		if matchesLabels(run.Metadata.Labels, labelFilters) {
+			result.Runs = append(result.Runs, s.summarize(run, rec))
+		}
+	}
+	result.Cursor = cursor.encode()
+	return result, nil
+}
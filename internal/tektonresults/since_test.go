@@ -0,0 +1,170 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sinceStore serves records from memory, applying the create_time lower
+// bound and ordering of the request the way the Results API does.
+type sinceStore struct {
+	records []record
+	reqs    []listRecordsRequest
+}
+
+var createdSinceRE = regexp.MustCompile(`create_time>=timestamp\("([^"]+)"\)`)
+
+func (s *sinceStore) add(uid string, created time.Time, labels string) {
+	rec := record{Name: fmt.Sprintf("ci/results/%s/records/%s", uid, uid), Uid: uid, CreateTime: created}
+	rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":"run-%s","namespace":"ci","uid":"%s","labels":%s}}`, uid, uid, labels))
+	s.records = append(s.records, rec)
+}
+
+func (s *sinceStore) listRecords(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+	s.reqs = append(s.reqs, req)
+	var since time.Time
+	if m := createdSinceRE.FindStringSubmatch(req.Filter); m != nil {
+		since, _ = time.Parse(time.RFC3339Nano, m[1])
+	}
+	var out []record
+	for _, rec := range s.records {
+		if !rec.CreateTime.Before(since) {
+			out = append(out, rec)
+		}
+	}
+	desc := strings.HasSuffix(req.OrderBy, "desc")
+	sort.SliceStable(out, func(i, j int) bool {
+		if desc {
+			return out[i].CreateTime.After(out[j].CreateTime)
+		}
+		return out[i].CreateTime.Before(out[j].CreateTime)
+	})
+	offset := 0
+	if req.PageToken != "" {
+		offset, _ = strconv.Atoi(req.PageToken)
+	}
+	out = out[offset:]
+	resp := &listRecordsResponse{Records: out}
+	if int(req.PageSize) < len(out) {
+		resp.Records = out[:req.PageSize]
+		resp.NextPageToken = strconv.Itoa(offset + int(req.PageSize))
+	}
+	return resp, nil
+}
+
+func uidsOf(runs []RunSummary) []string {
+	var uids []string
+	for _, r := range runs {
+		uids = append(uids, r.UID)
+	}
+	return uids
+}
+
+func TestService_RunsSince(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store := &sinceStore{}
+	store.add("a", base, `{}`)
+	store.add("b", base.Add(time.Minute), `{}`)
+	service := &Service{client: &mockRestClient{listRecordsFunc: store.listRecords}}
+	ctx := context.Background()
+
+	first, err := service.RunsSince(ctx, SinceOptions{Kind: "pipelinerun", Namespace: "ci"})
+	if err != nil {
+		t.Fatalf("RunsSince() error = %v", err)
+	}
+	if got := uidsOf(first.Runs); strings.Join(got, ",") != "a,b" {
+		t.Errorf("Expected the latest runs oldest first, got %v", got)
+	}
+	if store.reqs[0].OrderBy != "create_time desc" || !strings.Contains(store.reqs[0].Fields, "records.create_time") {
+		t.Errorf("Unexpected initial request: %+v", store.reqs[0])
+	}
+
+	// Two runs share a creation time; the one already seen must not repeat.
+	store.add("c", base.Add(time.Minute), `{}`)
+	store.add("d", base.Add(2*time.Minute), `{"app":"web"}`)
+	store.add("e", base.Add(3*time.Minute), `{}`)
+
+	next, err := service.RunsSince(ctx, SinceOptions{Kind: "pipelinerun", Namespace: "ci", Cursor: first.Cursor, Limit: 2})
+	if err != nil {
+		t.Fatalf("RunsSince() error = %v", err)
+	}
+	if got := uidsOf(next.Runs); strings.Join(got, ",") != "c,d" || !next.More {
+		t.Errorf("Expected c,d with more available, got %v more=%v", got, next.More)
+	}
+	if !strings.Contains(store.reqs[1].Filter, "create_time>=") || store.reqs[1].OrderBy != "create_time asc" {
+		t.Errorf("Unexpected follow-up request: %+v", store.reqs[1])
+	}
+
+	last, err := service.RunsSince(ctx, SinceOptions{Kind: "pipelinerun", Namespace: "ci", Cursor: next.Cursor, Limit: 2})
+	if err != nil {
+		t.Fatalf("RunsSince() error = %v", err)
+	}
+	if got := uidsOf(last.Runs); strings.Join(got, ",") != "e" || last.More {
+		t.Errorf("Expected only e, got %v more=%v", got, last.More)
+	}
+
+	empty, err := service.RunsSince(ctx, SinceOptions{Kind: "pipelinerun", Namespace: "ci", Cursor: last.Cursor})
+	if err != nil {
+		t.Fatalf("RunsSince() error = %v", err)
+	}
+	if len(empty.Runs) != 0 || empty.Cursor != last.Cursor {
+		t.Errorf("Expected no runs and an unchanged cursor, got %v", uidsOf(empty.Runs))
+	}
+}
+
+func TestService_RunsSince_LabelSelector(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store := &sinceStore{}
+	store.add("a", base, `{"app":"web"}`)
+	service := &Service{client: &mockRestClient{listRecordsFunc: store.listRecords}}
+
+	first, err := service.RunsSince(context.Background(), SinceOptions{Kind: "taskrun", Namespace: "ci", LabelSelector: "app=web,env!=dogfood"})
+	if err != nil {
+		t.Fatalf("RunsSince() error = %v", err)
+	}
+	store.add("b", base.Add(time.Minute), `{"app":"web","env":"dogfood"}`)
+	store.add("c", base.Add(2*time.Minute), `{"app":"web"}`)
+
+	// Clause order and spacing do not change the query.
+	next, err := service.RunsSince(context.Background(), SinceOptions{Kind: "taskrun", Namespace: "ci", LabelSelector: "env!=dogfood, app=web", Cursor: first.Cursor})
+	if err != nil {
+		t.Fatalf("RunsSince() error = %v", err)
+	}
+	if got := uidsOf(next.Runs); strings.Join(got, ",") != "c" {
+		t.Errorf("Expected only c to match, got %v", got)
+	}
+}
+
+func TestService_RunsSince_InvalidInput(t *testing.T) {
+	service := &Service{client: &mockRestClient{}}
+	cursor := sinceCursor{Kind: "pipelinerun", Namespace: "ci", Query: sinceQuery(resourceKindPipelineRun, "ci", "app=web").hash(), Time: time.Now()}.encode()
+
+	tests := []struct {
+		name string
+		opts SinceOptions
+		want string
+	}{
+		{"kind", SinceOptions{Kind: "pod"}, "kind must be"},
+		{"namespace list", SinceOptions{Kind: "taskrun", Namespace: "a,b"}, "not a list"},
+		{"garbage cursor", SinceOptions{Kind: "pipelinerun", Namespace: "ci", Cursor: "not-a-cursor"}, "invalid cursor"},
+		{"other kind", SinceOptions{Kind: "taskrun", Namespace: "ci", Cursor: cursor}, "repeat the same kind"},
+		{"other namespace", SinceOptions{Kind: "pipelinerun", Namespace: "dev", Cursor: cursor}, "repeat the same kind"},
+		{"other labelSelector", SinceOptions{Kind: "pipelinerun", Namespace: "ci", LabelSelector: "app=api", Cursor: cursor}, "different labelSelector"},
+		{"no labelSelector", SinceOptions{Kind: "pipelinerun", Namespace: "ci", Cursor: cursor}, "different labelSelector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.RunsSince(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
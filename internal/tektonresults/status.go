@@ -0,0 +1,107 @@
+package tektonresults
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RunStatuses lists the outcomes accepted by status filters. They are
+// disjoint: a run that is still going is running even while it is being
+// cancelled, and a failed run is one that was neither cancelled nor timed out.
+var RunStatuses = []string{"succeeded", "failed", "running", "cancelled", "timedout"}
+
+// cancelledReasons and timeoutReasons are the Succeeded condition reasons of
+// finished runs that were stopped rather than failed on their own.
+var (
+	cancelledReasons = []string{"Cancelled", "CancelledRunningFinally", "StoppedRunningFinally", "TaskRunCancelled"}
+	timeoutReasons   = []string{"PipelineRunTimeout", "TaskRunTimeout"}
+)
+
+// runStatus classifies a run by its Succeeded condition into one of
+// RunStatuses, or returns "" while the run has not reported a status.
+func runStatus(status, reason string) string {
+	switch status {
+	case "True":
+		return "succeeded"
+	case "Unknown":
+		return "running"
+	case "False":
+		switch {
+		case slices.Contains(cancelledReasons, reason):
+			return "cancelled"
+		case slices.Contains(timeoutReasons, reason):
+			return "timedout"
+		}
+		return "failed"
+	}
+	return ""
+}
+
+// Outcome classifies the run into one of RunStatuses, or returns "" while it
+// has not reported a status.
+func (s RunSummary) Outcome() string {
+	if s.Incomplete {
+		return ""
+	}
+	return runStatus(s.Status, s.Reason)
+}
+
+// statusFilter matches runs whose outcome is one of a comma separated list of
+// RunStatuses, ignoring case. The empty filter matches every run.
+type statusFilter []string
+
+func parseStatusFilter(input string) (statusFilter, error) {
+	var statuses statusFilter
+	for _, part := range strings.Split(input, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if !slices.Contains(RunStatuses, part) {
+			return nil, fmt.Errorf("invalid status %q: expected %s", part, strings.Join(RunStatuses, ", "))
+		}
+		if !slices.Contains(statuses, part) {
+			statuses = append(statuses, part)
+		}
+	}
+	return statuses, nil
+}
+
+func (f statusFilter) matches(summary RunSummary) bool {
+	if len(f) == 0 {
+		return true
+	}
+	if summary.Incomplete {
+		return false
+	}
+	return slices.Contains(f, runStatus(summary.Status, summary.Reason))
+}
+
+// statusCondition is the Succeeded condition in stored runs. Tekton sets no
+// other condition on PipelineRuns and TaskRuns, so it is the first one.
+const statusCondition = "data.status.conditions[0]"
+
+// statusClause returns the CEL clause selecting runs with status.
+func statusClause(status string) string {
+	is := func(value string) string { return fmt.Sprintf("%s.status==%s", statusCondition, quoteCEL(value)) }
+	in := func(reasons []string) string {
+		quoted := make([]string, len(reasons))
+		for i, r := range reasons {
+			quoted[i] = quoteCEL(r)
+		}
+		return fmt.Sprintf("%s.reason in [%s]", statusCondition, strings.Join(quoted, ", "))
+	}
+	switch status {
+	case "succeeded":
+		return is("True")
+	case "running":
+		return is("Unknown")
+	case "cancelled":
+		return fmt.Sprintf("(%s && %s)", is("False"), in(cancelledReasons))
+	case "timedout":
+		return fmt.Sprintf("(%s && %s)", is("False"), in(timeoutReasons))
+	default:
+		return fmt.Sprintf("(%s && !(%s))", is("False"), in(slices.Concat(cancelledReasons, timeoutReasons)))
+	}
+}
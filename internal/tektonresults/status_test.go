@@ -0,0 +1,85 @@
+package tektonresults
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestService_ListRuns_Status(t *testing.T) {
+	var filter string
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			filter = req.Filter
+			// The records are returned unfiltered, as by a server ignoring
+			// the status clause, to exercise the check in memory.
+			return &listRecordsResponse{Records: testRecords("ci",
+				testRun{Condition: "True/Succeeded"}, testRun{Condition: "False/Failed"}, testRun{Condition: "Unknown/Running"}, testRun{Condition: "False/Cancelled"},
+				testRun{Condition: "False/PipelineRunTimeout"}, testRun{}, testRun{Condition: "Unknown/CancelledRunningFinally"}, testRun{Condition: "False/CouldntGetTask"})}, nil
+		},
+	}
+	service := &Service{client: mockClient}
+
+	tests := []struct {
+		status     string
+		want       []string
+		wantFilter string
+	}{
+		{"", []string{"uid-0", "uid-1", "uid-2", "uid-3", "uid-4", "uid-5", "uid-6", "uid-7"}, ""},
+		{"succeeded", []string{"uid-0"}, `data.status.conditions[0].status=="True"`},
+		{"failed", []string{"uid-1", "uid-7"}, `(data.status.conditions[0].status=="False" && !(data.status.conditions[0].reason in [`},
+		{"Running", []string{"uid-2", "uid-6"}, `data.status.conditions[0].status=="Unknown"`},
+		{"cancelled", []string{"uid-3"}, `(data.status.conditions[0].status=="False" && data.status.conditions[0].reason in ["Cancelled", `},
+		{"cancelled, timedout", []string{"uid-3", "uid-4"}, `((data.status.conditions[0].status=="False" && data.status.conditions[0].reason in [`},
+	}
+	for _, tt := range tests {
+		summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "ci", Status: tt.status})
+		if err != nil {
+			t.Fatalf("ListPipelineRuns(%q) error = %v", tt.status, err)
+		}
+		var got []string
+		for _, s := range summaries {
+			got = append(got, s.UID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ListPipelineRuns(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+		if tt.wantFilter == "" && strings.Contains(filter, "conditions") {
+			t.Errorf("Expected no status clause for %q, got filter %s", tt.status, filter)
+		}
+		if !strings.Contains(filter, tt.wantFilter) {
+			t.Errorf("Expected filter for %q to contain %s, got %s", tt.status, tt.wantFilter, filter)
+		}
+	}
+
+	if _, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "ci", Status: "broken"}); err == nil || !strings.Contains(err.Error(), "succeeded, failed, running, cancelled, timedout") {
+		t.Errorf("Expected an unknown status to list the valid ones, got %v", err)
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	tests := []struct{ status, reason, want string }{
+		{"True", "Completed", "succeeded"},
+		{"False", "TaskRunCancelled", "cancelled"},
+		{"False", "StoppedRunningFinally", "cancelled"},
+		{"False", "TaskRunTimeout", "timedout"},
+		{"False", "TaskRunImagePullFailed", "failed"},
+		{"Unknown", "PipelineRunPending", "running"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := runStatus(tt.status, tt.reason); got != tt.want {
+			t.Errorf("runStatus(%q, %q) = %q, want %q", tt.status, tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestRunSummaryOutcome(t *testing.T) {
+	if got := (RunSummary{Status: "False", Reason: "PipelineRunTimeout"}).Outcome(); got != "timedout" {
+		t.Errorf("Outcome() = %q, want timedout", got)
+	}
+	if got := (RunSummary{Status: "Unknown", Incomplete: true}).Outcome(); got != "" {
+		t.Errorf("Outcome() of an incomplete record = %q, want none", got)
+	}
+}
@@ -0,0 +1,99 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepState is the outcome of one step of a TaskRun.
+type StepState struct {
+	Name     string        `json:"name"`
+	Image    string        `json:"image,omitempty"`    // digest the step ran, or the image reference when the digest is unknown
+	ExitCode *int32        `json:"exitCode,omitempty"` // nil when the step never terminated
+	Reason   string        `json:"reason,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	// ClockSkew reports a step that finished before it started; its Duration
+	// is zero.
+	ClockSkew bool `json:"clockSkew,omitempty"`
+	// Wait is the time between the TaskRun starting, for the first step, or
+	// the previous step finishing and the step starting. Before the first
+	// step it covers pod scheduling, init containers and pulling every step
+	// image; a long wait there usually means a slow registry or a node
+	// without the images cached.
+	Wait time.Duration `json:"wait,omitempty"`
+}
+
+// Failed reports whether the step terminated with a non-zero exit code.
+func (s StepState) Failed() bool {
+	return s.ExitCode != nil && *s.ExitCode != 0
+}
+
+// stepRun holds the parts of a TaskRun manifest the step states are read from.
+type stepRun struct {
+	Status struct {
+		StartTime *metav1.Time `json:"startTime"`
+		Steps     []struct {
+			Name       string `json:"name"`
+			ImageID    string `json:"imageID"`
+			Terminated *struct {
+				ExitCode   int32        `json:"exitCode"`
+				Reason     string       `json:"reason"`
+				StartedAt  *metav1.Time `json:"startedAt"`
+				FinishedAt *metav1.Time `json:"finishedAt"`
+			} `json:"terminated"`
+		} `json:"steps"`
+		TaskSpec struct {
+			Steps []struct {
+				Name  string `json:"name"`
+				Image string `json:"image"`
+			} `json:"steps"`
+		} `json:"taskSpec"`
+	} `json:"status"`
+}
+
+// Steps returns the steps of a TaskRun in execution order. It returns nil
+// for PipelineRuns and for TaskRuns that never started a pod.
+func (d RunDetail) Steps() []StepState {
+	var run stepRun
+	if err := json.Unmarshal(d.Raw, &run); err != nil {
+		return nil
+	}
+	images := make(map[string]string, len(run.Status.TaskSpec.Steps))
+	for _, step := range run.Status.TaskSpec.Steps {
+		images[step.Name] = step.Image
+	}
+	steps := make([]StepState, 0, len(run.Status.Steps))
+	// Steps run one after the other, each waiting for the previous one.
+	ready := run.Status.StartTime
+	for _, step := range run.Status.Steps {
+		state := StepState{Name: step.Name, Image: imageDigest(step.ImageID)}
+		if state.Image == "" {
+			state.Image = images[step.Name]
+		}
+		if t := step.Terminated; t != nil {
+			exitCode := t.ExitCode
+			state.ExitCode, state.Reason = &exitCode, t.Reason
+			state.Duration, state.ClockSkew, _ = elapsed(t.StartedAt, t.FinishedAt)
+			if wait, skewed, ok := elapsed(ready, t.StartedAt); ok && !skewed {
+				state.Wait = wait
+			}
+			ready = t.FinishedAt
+		} else {
+			ready = nil
+		}
+		steps = append(steps, state)
+	}
+	return steps
+}
+
+// imageDigest strips the runtime prefix container runtimes put in front of
+// image IDs, e.g. "docker-pullable://".
+func imageDigest(imageID string) string {
+	if _, rest, ok := strings.Cut(imageID, "://"); ok {
+		return rest
+	}
+	return imageID
+}
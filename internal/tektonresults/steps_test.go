@@ -0,0 +1,53 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRunDetail_Steps(t *testing.T) {
+	raw := `{"kind":"TaskRun","status":{
+		"taskSpec":{"steps":[{"name":"fetch","image":"alpine:3"},{"name":"build","image":"golang:1.24"},{"name":"push","image":"crane"}]},
+		"steps":[
+			{"name":"fetch","terminated":{"exitCode":0,"startedAt":"2025-01-01T10:00:00Z","finishedAt":"2025-01-01T10:00:05Z"}},
+			{"name":"build","imageID":"docker-pullable://golang@sha256:abc","terminated":{"exitCode":2,"reason":"Error","startedAt":"2025-01-01T10:00:05Z","finishedAt":"2025-01-01T10:01:05Z"}},
+			{"name":"push","terminated":null}
+		]}}`
+	steps := RunDetail{Raw: json.RawMessage(raw)}.Steps()
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %+v", steps)
+	}
+	if steps[0].Image != "alpine:3" || steps[0].Failed() || steps[0].Duration != 5*time.Second {
+		t.Errorf("Unexpected fetch step: %+v", steps[0])
+	}
+	if steps[1].Image != "golang@sha256:abc" || !steps[1].Failed() || steps[1].Reason != "Error" || steps[1].Duration != time.Minute {
+		t.Errorf("Unexpected build step: %+v", steps[1])
+	}
+	if steps[2].ExitCode != nil || steps[2].Failed() {
+		t.Errorf("Expected the push step not to have terminated: %+v", steps[2])
+	}
+
+	if steps := (RunDetail{Raw: json.RawMessage(`{"kind":"PipelineRun","status":{}}`)}).Steps(); len(steps) != 0 {
+		t.Errorf("Expected no steps for a PipelineRun, got %+v", steps)
+	}
+}
+
+func TestRunDetail_StepWaits(t *testing.T) {
+	raw := `{"kind":"TaskRun","status":{
+		"startTime":"2025-01-01T09:58:30Z",
+		"steps":[
+			{"name":"fetch","terminated":{"exitCode":0,"startedAt":"2025-01-01T10:00:00Z","finishedAt":"2025-01-01T10:00:05Z"}},
+			{"name":"build","terminated":{"exitCode":0,"startedAt":"2025-01-01T10:00:45Z","finishedAt":"2025-01-01T10:01:05Z"}}
+		]}}`
+	steps := RunDetail{Raw: json.RawMessage(raw)}.Steps()
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 steps, got %+v", steps)
+	}
+	if steps[0].Wait != 90*time.Second {
+		t.Errorf("Expected the pod startup of 90s before the first step, got %s", steps[0].Wait)
+	}
+	if steps[1].Wait != 40*time.Second {
+		t.Errorf("Expected 40s between the steps, got %s", steps[1].Wait)
+	}
+}
@@ -0,0 +1,110 @@
+package tektonresults
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Team names the owners of the runs that match label selectors, so queries
+// can ask for "Payments" instead of spelling out the labels that mark the
+// team's runs.
+type Team struct {
+	Name string `json:"name"`
+	// Selectors use the labelSelector syntax, e.g. "team=payments". A run
+	// belongs to the team when any of them matches.
+	Selectors []string `json:"selectors"`
+}
+
+type teamMap struct {
+	teams []team // in configuration order; the first match names a run's team
+}
+
+type team struct {
+	name      string
+	selectors []labelSelector
+}
+
+func newTeamMap(teams []Team) (*teamMap, error) {
+	if len(teams) == 0 {
+		return nil, nil
+	}
+	m := &teamMap{}
+	seen := map[string]bool{}
+	for i, t := range teams {
+		name := strings.TrimSpace(t.Name)
+		if name == "" {
+			return nil, fmt.Errorf("team %d: name is required", i+1)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("team %d: team %q is defined twice", i+1, name)
+		}
+		seen[strings.ToLower(name)] = true
+		if len(t.Selectors) == 0 {
+			return nil, fmt.Errorf("team %q: at least one selector is required", name)
+		}
+		parsed := team{name: name}
+		for _, raw := range t.Selectors {
+			selector, err := parseLabelSelector(raw)
+			if err != nil {
+				return nil, fmt.Errorf("team %q: %w", name, err)
+			}
+			if selector.empty() {
+				return nil, fmt.Errorf("team %q: selector %q matches every run", name, raw)
+			}
+			parsed.selectors = append(parsed.selectors, selector)
+		}
+		m.teams = append(m.teams, parsed)
+	}
+	return m, nil
+}
+
+// lookup returns the team named name, ignoring case.
+func (m *teamMap) lookup(name string) (*team, error) {
+	name = strings.TrimSpace(name)
+	if m == nil {
+		return nil, fmt.Errorf("unknown team %q: no teams are configured", name)
+	}
+	for i := range m.teams {
+		if strings.EqualFold(m.teams[i].name, name) {
+			return &m.teams[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown team %q; configured teams are %s", name, strings.Join(m.names(), ", "))
+}
+
+// owner returns the name of the first team whose selectors match labels, or
+// "" when no team claims the run.
+func (m *teamMap) owner(labels map[string]string) string {
+	if m == nil {
+		return ""
+	}
+	for _, t := range m.teams {
+		if t.matches(labels) {
+			return t.name
+		}
+	}
+	return ""
+}
+
+func (t *team) matches(labels map[string]string) bool {
+	for _, selector := range t.selectors {
+		if matchesLabels(labels, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the sorted names of the configured teams.
+func (m *teamMap) names() []string {
+	if m == nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.teams))
+	for _, t := range m.teams {
+		names = append(names, t.name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,109 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewTeamMap(t *testing.T) {
+	m, err := newTeamMap([]Team{
+		{Name: "Payments", Selectors: []string{"team=payments", "app=checkout,env!=dogfood"}},
+		{Name: "Platform", Selectors: []string{"team=platform"}},
+	})
+	if err != nil {
+		t.Fatalf("newTeamMap() error = %v", err)
+	}
+	for _, tt := range []struct {
+		labels map[string]string
+		want   string
+	}{
+		{map[string]string{"team": "payments"}, "Payments"},
+		{map[string]string{"app": "checkout"}, "Payments"},
+		{map[string]string{"app": "checkout", "env": "dogfood"}, ""},
+		{map[string]string{"team": "platform"}, "Platform"},
+		{nil, ""},
+	} {
+		if got := m.owner(tt.labels); got != tt.want {
+			t.Errorf("owner(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+	if team, err := m.lookup(" payments "); err != nil || team.name != "Payments" {
+		t.Errorf("lookup() = %v, %v; want Payments", team, err)
+	}
+	if _, err := m.lookup("search"); err == nil || !strings.Contains(err.Error(), "configured teams are Payments, Platform") {
+		t.Errorf("Expected error listing the teams, got %v", err)
+	}
+	if _, err := (*teamMap)(nil).lookup("payments"); err == nil || !strings.Contains(err.Error(), "no teams are configured") {
+		t.Errorf("Expected error without teams, got %v", err)
+	}
+
+	invalid := [][]Team{
+		{{Selectors: []string{"team=a"}}},
+		{{Name: "A", Selectors: []string{"team=a"}}, {Name: "a", Selectors: []string{"team=b"}}},
+		{{Name: "A"}},
+		{{Name: "A", Selectors: []string{"team"}}},
+		{{Name: "A", Selectors: []string{" "}}},
+	}
+	for _, teams := range invalid {
+		if _, err := newTeamMap(teams); err == nil {
+			t.Errorf("newTeamMap(%+v): expected error", teams)
+		}
+	}
+}
+
+func TestService_ListRuns_Team(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			want := `((data.metadata.labels["team"]=="payments") || (data.metadata.labels["app"]=="checkout"))`
+			if !strings.Contains(req.Filter, want) {
+				t.Errorf("Expected the team selectors in the filter, got %s", req.Filter)
+			}
+			var records []record
+			for i, labels := range []string{`{"team":"payments"}`, `{"app":"checkout","env":"dogfood"}`, `{"app":"checkout"}`} {
+				uid := fmt.Sprintf("uid-%d", i)
+				rec := record{Name: fmt.Sprintf("foo/results/%s/records/%s", uid, uid), Uid: uid}
+				rec.Data.Value = json.RawMessage(fmt.Sprintf(`{"metadata":{"name":"run-%d","namespace":"foo","uid":"%s","labels":%s}}`, i, uid, labels))
+				records = append(records, rec)
+			}
+			return &listRecordsResponse{Records: records}, nil
+		},
+	}
+
+	service := &Service{client: mockClient}
+	if err := service.Reconfigure(Settings{Teams: []Team{
+		{Name: "Payments", Selectors: []string{"team=payments", "app=checkout,env!=dogfood"}},
+	}}); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	summaries, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", Team: "payments"})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	var got []string
+	for _, s := range summaries {
+		if s.Team != "Payments" {
+			t.Errorf("Expected %s to be owned by Payments, got %q", s.Name, s.Team)
+		}
+		got = append(got, s.Name)
+	}
+	if want := []string{"run-0", "run-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got runs %v, want %v", got, want)
+	}
+
+	if _, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "foo", Team: "search"}); err == nil {
+		t.Error("Expected an error for an unknown team")
+	}
+}
+
+func TestFilterBuilder_AnyOf_SkipsUnnarrowableSelectors(t *testing.T) {
+	only, _ := parseLabelSelector("app=web")
+	negative, _ := parseLabelSelector("!experimental")
+	filter, err := (&filterBuilder{}).anyOf([]labelSelector{only, negative}).build()
+	if err != nil || filter != "" {
+		t.Errorf("Expected no clause when a selector has no equality labels, got %q, %v", filter, err)
+	}
+}
@@ -0,0 +1,114 @@
+package tektonresults
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/transport"
+)
+
+// NamespaceToken assigns a bearer token to requests targeting the listed
+// namespaces. It supports Results gateways that issue tokens scoped to a
+// namespace or tenant instead of one cluster-wide credential.
+type NamespaceToken struct {
+	// Namespaces the token applies to. An entry ending in '*' matches every
+	// namespace with that prefix, e.g. "team-a-*" for a tenant.
+	Namespaces []string `json:"namespaces"`
+	// Token is the bearer token itself. Set either Token or TokenFile.
+	Token string `json:"token,omitempty"`
+	// TokenFile is read periodically, so rotated tokens are picked up.
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// namespaceTokens selects the scoped token for a namespace. Exact names take
+// precedence over prefixes, and longer prefixes over shorter ones.
+type namespaceTokens struct {
+	exact    map[string]oauth2.TokenSource
+	prefixes []tokenPrefix // longest first
+}
+
+type tokenPrefix struct {
+	prefix string
+	source oauth2.TokenSource
+}
+
+func newNamespaceTokens(scopes []NamespaceToken) (*namespaceTokens, error) {
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	nt := &namespaceTokens{exact: map[string]oauth2.TokenSource{}}
+	seen := map[string]bool{}
+	for i, scope := range scopes {
+		var source oauth2.TokenSource
+		switch {
+		case scope.Token != "" && scope.TokenFile != "":
+			return nil, fmt.Errorf("namespace token %d: set either token or tokenFile, not both", i+1)
+		case scope.Token != "":
+			source = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: scope.Token})
+		case scope.TokenFile != "":
+			source = transport.NewCachedFileTokenSource(scope.TokenFile)
+			if _, err := source.Token(); err != nil {
+				return nil, fmt.Errorf("namespace token %d: read token file %s: %w", i+1, scope.TokenFile, err)
+			}
+		default:
+			return nil, fmt.Errorf("namespace token %d: token or tokenFile is required", i+1)
+		}
+		if len(scope.Namespaces) == 0 {
+			return nil, fmt.Errorf("namespace token %d: at least one namespace is required", i+1)
+		}
+		for _, ns := range scope.Namespaces {
+			ns = strings.TrimSpace(ns)
+			if ns == "" || ns == "-" || ns == "*" {
+				return nil, fmt.Errorf("namespace token %d: invalid namespace %q; the default credential covers all other namespaces", i+1, ns)
+			}
+			if seen[ns] {
+				return nil, fmt.Errorf("namespace token %d: namespace %q is already assigned a token", i+1, ns)
+			}
+			seen[ns] = true
+			if prefix, ok := strings.CutSuffix(ns, "*"); ok {
+				nt.prefixes = append(nt.prefixes, tokenPrefix{prefix: prefix, source: source})
+			} else {
+				nt.exact[ns] = source
+			}
+		}
+	}
+	sort.SliceStable(nt.prefixes, func(i, j int) bool {
+		return len(nt.prefixes[i].prefix) > len(nt.prefixes[j].prefix)
+	})
+	return nt, nil
+}
+
+// tokenFor returns the scoped token for namespace, or "" when the default
+// credential should be used. Queries across all namespaces ("-") always use
+// the default credential.
+func (nt *namespaceTokens) tokenFor(namespace string) (string, error) {
+	if nt == nil || namespace == "" || namespace == "-" {
+		return "", nil
+	}
+	source, ok := nt.exact[namespace]
+	if !ok {
+		for _, p := range nt.prefixes {
+			if strings.HasPrefix(namespace, p.prefix) {
+				source, ok = p.source, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return "", nil
+	}
+	tok, err := source.Token()
+	if err != nil {
+		return "", fmt.Errorf("token for namespace %s: %w", namespace, err)
+	}
+	return tok.AccessToken, nil
+}
+
+// namespaceOf returns the namespace a Results API path targets, e.g. "ci" for
+// "parents/ci/results/-/records".
+func namespaceOf(relPath string) string {
+	ns, _, _ := strings.Cut(strings.TrimPrefix(relPath, "parents/"), "/")
+	return ns
+}
@@ -0,0 +1,114 @@
+package tektonresults
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceTokens_TokenFor(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+	nt, err := newNamespaceTokens([]NamespaceToken{
+		{Namespaces: []string{"payments-ci", "payments-prod"}, TokenFile: tokenFile},
+		{Namespaces: []string{"team-*"}, Token: "team-token"},
+		{Namespaces: []string{"team-a-*", "team-b"}, Token: "team-a-token"},
+	})
+	if err != nil {
+		t.Fatalf("newNamespaceTokens() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"payments-ci":   "file-token",
+		"payments-prod": "file-token",
+		"team-c":        "team-token",
+		"team-a-ci":     "team-a-token",
+		"team-b":        "team-a-token",
+		"team-b-ci":     "team-token",
+		"other":         "",
+		"-":             "",
+		"":              "",
+	}
+	for ns, want := range tests {
+		got, err := nt.tokenFor(ns)
+		if err != nil {
+			t.Fatalf("tokenFor(%q) error = %v", ns, err)
+		}
+		if got != want {
+			t.Errorf("tokenFor(%q) = %q, want %q", ns, got, want)
+		}
+	}
+
+	var none *namespaceTokens
+	if got, err := none.tokenFor("payments-ci"); got != "" || err != nil {
+		t.Errorf("Expected nil mapping to select the default credential, got %q, %v", got, err)
+	}
+}
+
+func TestNewNamespaceTokens_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []NamespaceToken
+		want   string
+	}{
+		{"no token", []NamespaceToken{{Namespaces: []string{"ci"}}}, "token or tokenFile is required"},
+		{"both tokens", []NamespaceToken{{Namespaces: []string{"ci"}, Token: "a", TokenFile: "/tmp/t"}}, "not both"},
+		{"no namespaces", []NamespaceToken{{Token: "a"}}, "at least one namespace"},
+		{"all namespaces", []NamespaceToken{{Namespaces: []string{"-"}, Token: "a"}}, "invalid namespace"},
+		{"duplicate", []NamespaceToken{{Namespaces: []string{"ci"}, Token: "a"}, {Namespaces: []string{"ci"}, Token: "b"}}, "already assigned"},
+		{"missing file", []NamespaceToken{{Namespaces: []string{"ci"}, TokenFile: filepath.Join(t.TempDir(), "missing")}}, "read token file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newNamespaceTokens(tt.scopes)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRestClient_NamespaceTokens(t *testing.T) {
+	auth := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth[r.URL.Path] = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`)) //nolint:errcheck // Writing to test HTTP response writer
+	}))
+	defer server.Close()
+
+	tokens, err := newNamespaceTokens([]NamespaceToken{{Namespaces: []string{"payments"}, Token: "scoped"}})
+	if err != nil {
+		t.Fatalf("newNamespaceTokens() error = %v", err)
+	}
+	baseURL, _ := url.Parse(server.URL + customAPIPath)
+	client := &restClient{baseURL: baseURL, httpClient: server.Client(), authToken: "default"}
+	client.tokens.Store(tokens)
+
+	for _, parent := range []string{"payments/results/-", "ci/results/-", "-/results/-"} {
+		if _, err := client.listRecords(context.Background(), listRecordsRequest{Parent: parent}); err != nil {
+			t.Fatalf("listRecords(%s) error = %v", parent, err)
+		}
+	}
+	if _, err := client.getRecord(context.Background(), "payments/results/r1/records/r1"); err != nil {
+		t.Fatalf("getRecord() error = %v", err)
+	}
+
+	want := map[string]string{
+		customAPIPath + "/parents/payments/results/-/records":     "Bearer scoped",
+		customAPIPath + "/parents/ci/results/-/records":           "Bearer default",
+		customAPIPath + "/parents/-/results/-/records":            "Bearer default",
+		customAPIPath + "/parents/payments/results/r1/records/r1": "Bearer scoped",
+	}
+	for path, header := range want {
+		if auth[path] != header {
+			t.Errorf("Authorization for %s = %q, want %q", path, auth[path], header)
+		}
+	}
+}
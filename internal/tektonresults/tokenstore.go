@@ -0,0 +1,283 @@
+package tektonresults
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultTokenRefresh   = 5 * time.Minute
+	defaultVaultAuthPath  = "kubernetes"
+	serviceAccountJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// TokenStore references a bearer token kept in an external secret store, so
+// the token itself never appears in the deployment. Set either Secret or
+// Vault. The token is read at startup and again after Refresh, or as soon as
+// the Results API rejects it, so rotations are picked up without a restart.
+type TokenStore struct {
+	Secret        string        // Kubernetes Secret key "<namespace>/<name>/<key>", read with the kubeconfig credential
+	Vault         string        // Vault secret field "<path>#<field>", e.g. "secret/data/tekton-results#token"
+	VaultAddr     string        // Vault address, required with Vault
+	VaultToken    string        // Vault token; when empty, log in with the Kubernetes auth method as VaultRole
+	VaultRole     string        // role for the Kubernetes auth method
+	VaultAuthPath string        // mount path of the Kubernetes auth method; empty uses "kubernetes"
+	VaultNS       string        // Vault Enterprise namespace, optional
+	Refresh       time.Duration // how long a token is used before it is read again; 0 uses 5 minutes
+}
+
+// Enabled reports whether a store is configured.
+func (t TokenStore) Enabled() bool {
+	return t.Secret != "" || t.Vault != ""
+}
+
+// storeTokenSource caches the token read from a TokenStore. It is safe for
+// concurrent use.
+type storeTokenSource struct {
+	fetch   func(ctx context.Context) (string, error)
+	refresh time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	cached  string
+	expires time.Time
+}
+
+func newStoreTokenSource(cfg *rest.Config, store TokenStore) (*storeTokenSource, error) {
+	src := &storeTokenSource{refresh: store.Refresh, now: time.Now}
+	if src.refresh <= 0 {
+		src.refresh = defaultTokenRefresh
+	}
+	switch {
+	case store.Secret != "" && store.Vault != "":
+		return nil, fmt.Errorf("token store: set either a Kubernetes Secret or a Vault secret, not both")
+	case store.Secret != "":
+		parts := strings.Split(store.Secret, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("token store: secret reference %q must have the form <namespace>/<name>/<key>", store.Secret)
+		}
+		if cfg == nil {
+			return nil, fmt.Errorf("token store: a Kubernetes config is required to read secret %s", store.Secret)
+		}
+		httpClient, err := rest.HTTPClientFor(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("token store: create http client: %w", err)
+		}
+		src.fetch = func(ctx context.Context) (string, error) {
+			return readSecretKey(ctx, httpClient, cfg.Host, parts[0], parts[1], parts[2])
+		}
+	case store.Vault != "":
+		secretPath, field, ok := strings.Cut(store.Vault, "#")
+		if !ok || secretPath == "" || field == "" {
+			return nil, fmt.Errorf("token store: vault reference %q must have the form <path>#<field>", store.Vault)
+		}
+		if store.VaultAddr == "" {
+			return nil, fmt.Errorf("token store: a Vault address is required to read %s", store.Vault)
+		}
+		if store.VaultToken == "" && store.VaultRole == "" {
+			return nil, fmt.Errorf("token store: a Vault token or a role for Kubernetes auth is required to read %s", store.Vault)
+		}
+		v := &vaultClient{store: store, httpClient: &http.Client{Timeout: defaultTimeout}, jwtPath: serviceAccountJWTPath}
+		if cfg != nil && cfg.BearerTokenFile != "" {
+			v.jwtPath = cfg.BearerTokenFile
+		}
+		src.fetch = func(ctx context.Context) (string, error) {
+			return v.readField(ctx, strings.Trim(secretPath, "/"), field)
+		}
+	default:
+		return nil, nil
+	}
+	return src, nil
+}
+
+// token returns the cached token, reading it from the store when it is
+// older than the refresh interval. When the store cannot be reached, the
+// previous token keeps being used.
+func (s *storeTokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != "" && s.now().Before(s.expires) {
+		return s.cached, nil
+	}
+	tok, err := s.fetch(ctx)
+	if err != nil {
+		if s.cached != "" {
+			slog.Warn("failed to refresh the bearer token from its store, using the previous one", "error", err)
+			s.expires = s.now().Add(s.refresh)
+			return s.cached, nil
+		}
+		return "", fmt.Errorf("read bearer token: %w", err)
+	}
+	s.cached, s.expires = tok, s.now().Add(s.refresh)
+	return tok, nil
+}
+
+// expire makes the next call read the token from the store again, typically
+// after the Results API rejected it because it was rotated.
+func (s *storeTokenSource) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expires = time.Time{}
+}
+
+// readSecretKey reads one key of a Kubernetes Secret.
+func readSecretKey(ctx context.Context, httpClient *http.Client, host, namespace, name, key string) (string, error) {
+	endpoint := strings.TrimSuffix(host, "/") + "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets/" + url.PathEscape(name)
+	data, err := getJSON(ctx, httpClient, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("secret %s/%s: %w", namespace, name, err)
+	}
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &secret); err != nil {
+		return "", fmt.Errorf("decode secret %s/%s: %w", namespace, name, err)
+	}
+	encoded, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", namespace, name, key)
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decode key %q of secret %s/%s: %w", key, namespace, name, err)
+	}
+	tok := strings.TrimSpace(string(value))
+	if tok == "" {
+		return "", fmt.Errorf("key %q of secret %s/%s is empty", key, namespace, name)
+	}
+	return tok, nil
+}
+
+// vaultClient reads secrets over the Vault HTTP API.
+type vaultClient struct {
+	store      TokenStore
+	httpClient *http.Client
+	jwtPath    string // service account token presented to the Kubernetes auth method
+}
+
+// readField reads field of the secret at secretPath, from either a KV
+// version 2 mount (data.data) or a version 1 mount (data).
+func (v *vaultClient) readField(ctx context.Context, secretPath, field string) (string, error) {
+	vaultToken := v.store.VaultToken
+	if vaultToken == "" {
+		var err error
+		if vaultToken, err = v.login(ctx); err != nil {
+			return "", err
+		}
+	}
+	data, err := getJSON(ctx, v.httpClient, v.url(secretPath), v.headers(vaultToken))
+	if err != nil {
+		return "", fmt.Errorf("vault secret %s: %w", secretPath, err)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", fmt.Errorf("decode vault secret %s: %w", secretPath, err)
+	}
+	values := resp.Data
+	if nested, ok := values["data"].(map[string]any); ok {
+		values = nested
+	}
+	tok, _ := values[field].(string)
+	if tok = strings.TrimSpace(tok); tok == "" {
+		return "", fmt.Errorf("vault secret %s has no string field %q", secretPath, field)
+	}
+	return tok, nil
+}
+
+// login exchanges the service account token for a Vault token with the
+// Kubernetes auth method.
+func (v *vaultClient) login(ctx context.Context) (string, error) {
+	jwt, err := os.ReadFile(v.jwtPath)
+	if err != nil {
+		return "", fmt.Errorf("read service account token for vault login: %w", err)
+	}
+	mount := v.store.VaultAuthPath
+	if mount == "" {
+		mount = defaultVaultAuthPath
+	}
+	body, err := json.Marshal(map[string]string{"role": v.store.VaultRole, "jwt": strings.TrimSpace(string(jwt))})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url("auth/"+strings.Trim(mount, "/")+"/login"), bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create vault login request: %w", err)
+	}
+	for k, val := range v.headers("") {
+		req.Header.Set(k, val)
+	}
+	data, err := doJSON(v.httpClient, req)
+	if err != nil {
+		return "", fmt.Errorf("vault login as role %s: %w", v.store.VaultRole, err)
+	}
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault login as role %s returned no client token", v.store.VaultRole)
+	}
+	return resp.Auth.ClientToken, nil
+}
+
+func (v *vaultClient) url(apiPath string) string {
+	return strings.TrimSuffix(v.store.VaultAddr, "/") + "/v1/" + apiPath
+}
+
+func (v *vaultClient) headers(vaultToken string) map[string]string {
+	h := map[string]string{}
+	if vaultToken != "" {
+		h["X-Vault-Token"] = vaultToken
+	}
+	if v.store.VaultNS != "" {
+		h["X-Vault-Namespace"] = v.store.VaultNS
+	}
+	return h
+}
+
+func getJSON(ctx context.Context, httpClient *http.Client, endpoint string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return doJSON(httpClient, req)
+}
+
+func doJSON(httpClient *http.Client, req *http.Request) ([]byte, error) {
+	req.Header.Set("Accept", "application/json")
+	if req.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
@@ -0,0 +1,163 @@
+package tektonresults
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestStoreTokenSource_KubernetesSecret(t *testing.T) {
+	value := "token-1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/tekton/secrets/results-token" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"token":%q}}`, base64.StdEncoding.EncodeToString([]byte(value+"\n")))
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	src, err := newStoreTokenSource(&rest.Config{Host: server.URL}, TokenStore{Secret: "tekton/results-token/token", Refresh: time.Minute})
+	if err != nil {
+		t.Fatalf("newStoreTokenSource() error = %v", err)
+	}
+	src.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if got, err := src.token(ctx); err != nil || got != "token-1" {
+		t.Fatalf("token() = %q, %v", got, err)
+	}
+	value = "token-2"
+	if got, _ := src.token(ctx); got != "token-1" {
+		t.Errorf("Expected the cached token within the refresh interval, got %q", got)
+	}
+	now = now.Add(time.Minute)
+	if got, _ := src.token(ctx); got != "token-2" {
+		t.Errorf("Expected the rotated token after the refresh interval, got %q", got)
+	}
+
+	src, _ = newStoreTokenSource(&rest.Config{Host: server.URL}, TokenStore{Secret: "tekton/results-token/missing"})
+	if _, err := src.token(ctx); err == nil || !strings.Contains(err.Error(), `no key "missing"`) {
+		t.Errorf("Expected a missing key error, got %v", err)
+	}
+}
+
+func TestStoreTokenSource_KeepsTokenWhenStoreFails(t *testing.T) {
+	calls := 0
+	src := &storeTokenSource{refresh: time.Minute, now: time.Now, fetch: func(context.Context) (string, error) {
+		calls++
+		if calls > 1 {
+			return "", errors.New("store unavailable")
+		}
+		return "token-1", nil
+	}}
+	if _, err := src.token(context.Background()); err != nil {
+		t.Fatalf("token() error = %v", err)
+	}
+	src.expire()
+	if got, err := src.token(context.Background()); err != nil || got != "token-1" {
+		t.Errorf("Expected the previous token while the store fails, got %q, %v", got, err)
+	}
+}
+
+func TestStoreTokenSource_Vault(t *testing.T) {
+	jwtFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(jwtFile, []byte("sa-jwt\n"), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/k8s/login":
+			w.Write([]byte(`{"auth":{"client_token":"vault-token"}}`)) //nolint:errcheck // Writing to test HTTP response writer
+		case "/v1/secret/data/tekton-results":
+			if r.Header.Get("X-Vault-Token") != "vault-token" || r.Header.Get("X-Vault-Namespace") != "ci" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"data":{"data":{"token":"results-token"},"metadata":{"version":3}}}`)) //nolint:errcheck // Writing to test HTTP response writer
+		case "/v1/kv/tekton-results":
+			w.Write([]byte(`{"data":{"token":"kv1-token"}}`)) //nolint:errcheck // Writing to test HTTP response writer
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	src, err := newStoreTokenSource(&rest.Config{BearerTokenFile: jwtFile}, TokenStore{
+		Vault: "secret/data/tekton-results#token", VaultAddr: server.URL, VaultRole: "mcp", VaultAuthPath: "k8s", VaultNS: "ci",
+	})
+	if err != nil {
+		t.Fatalf("newStoreTokenSource() error = %v", err)
+	}
+	if got, err := src.token(ctx); err != nil || got != "results-token" {
+		t.Errorf("token() with Kubernetes auth = %q, %v", got, err)
+	}
+
+	src, _ = newStoreTokenSource(nil, TokenStore{Vault: "kv/tekton-results#token", VaultAddr: server.URL, VaultToken: "static"})
+	if got, err := src.token(ctx); err != nil || got != "kv1-token" {
+		t.Errorf("token() from a KV version 1 mount = %q, %v", got, err)
+	}
+}
+
+func TestNewStoreTokenSource_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		store TokenStore
+		want  string
+	}{
+		{"both stores", TokenStore{Secret: "a/b/c", Vault: "p#f"}, "not both"},
+		{"short secret reference", TokenStore{Secret: "a/b"}, "<namespace>/<name>/<key>"},
+		{"vault without field", TokenStore{Vault: "secret/data/x", VaultAddr: "http://vault", VaultToken: "t"}, "<path>#<field>"},
+		{"vault without address", TokenStore{Vault: "p#f", VaultToken: "t"}, "Vault address is required"},
+		{"vault without credential", TokenStore{Vault: "p#f", VaultAddr: "http://vault"}, "Vault token or a role"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newStoreTokenSource(&rest.Config{}, tt.store)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRestClient_RereadsStoredTokenAfterUnauthorized(t *testing.T) {
+	current := "token-1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"results":[]}`)) //nolint:errcheck // Writing to test HTTP response writer
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL)
+	client := &restClient{baseURL: baseURL, httpClient: server.Client(), authToken: "ignored"}
+	client.store = &storeTokenSource{refresh: time.Hour, now: time.Now, fetch: func(context.Context) (string, error) {
+		return current, nil
+	}}
+	ctx := context.Background()
+	if _, err := client.store.token(ctx); err != nil {
+		t.Fatalf("token() error = %v", err)
+	}
+
+	// The store was rotated after the token was cached.
+	current = "token-2"
+	if _, err := client.listResults(ctx, listResultsRequest{Parent: "ci"}); err != nil {
+		t.Errorf("Expected the request to succeed with the re-read token, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package tektonresults
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// TracedRequest describes one request sent to the Results API on behalf of a
+// traced call.
+type TracedRequest struct {
+	Operation string `json:"operation"`          // listRecords, listResults, getRecord, getLog or deleteResult
+	Target    string `json:"target"`             // parent for listings, resource name otherwise
+	Filter    string `json:"filter,omitempty"`   // CEL filter sent upstream
+	OrderBy   string `json:"orderBy,omitempty"`  // upstream ordering
+	PageSize  int32  `json:"pageSize,omitempty"` // requested page size
+	PageToken bool   `json:"pageToken,omitempty"`
+	Items     int    `json:"items"`              // records, results or log bytes returned
+	NextPage  bool   `json:"nextPage,omitempty"` // the response had a next page token
+	Duration  string `json:"duration"`
+	Error     string `json:"error,omitempty"`
+	DryRun    bool   `json:"dryRun,omitempty"` // planned but not sent
+}
+
+// ErrDryRun is returned for requests a dry run cannot answer without sending
+// them, such as reading a record or a log.
+var ErrDryRun = errors.New("request not sent: dry run")
+
+// QueryTrace collects the requests made with a context returned by
+// WithQueryTrace. It is safe for concurrent use, since multi-namespace
+// queries issue requests in parallel.
+type QueryTrace struct {
+	mu       sync.Mutex
+	requests []TracedRequest
+	dryRun   bool
+}
+
+// Requests returns the requests recorded so far, in the order they completed.
+func (t *QueryTrace) Requests() []TracedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TracedRequest(nil), t.requests...)
+}
+
+func (t *QueryTrace) record(req TracedRequest, start time.Time, err error) {
+	req.Duration = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		req.Error = err.Error()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = append(t.requests, req)
+}
+
+type queryTraceKey struct{}
+
+// WithQueryTrace returns a context that records every Results API request
+// made with it into the returned trace.
+func WithQueryTrace(ctx context.Context) (context.Context, *QueryTrace) {
+	trace := &QueryTrace{}
+	return context.WithValue(ctx, queryTraceKey{}, trace), trace
+}
+
+// WithDryRun returns a context whose Results API requests are recorded into
+// the returned trace without being sent. Listings come back empty, so a call
+// plans the first page of each listing it would read; reading records, logs
+// and deleting fail with ErrDryRun.
+func WithDryRun(ctx context.Context) (context.Context, *QueryTrace) {
+	trace := &QueryTrace{dryRun: true}
+	return context.WithValue(ctx, queryTraceKey{}, trace), trace
+}
+
+// skip records req as planned when the trace is a dry run and reports
+// whether the request must not be sent.
+func (t *QueryTrace) skip(req TracedRequest) bool {
+	if !t.dryRun {
+		return false
+	}
+	req.DryRun = true
+	t.record(req, time.Now(), nil)
+	return true
+}
+
+func queryTraceFrom(ctx context.Context) *QueryTrace {
+	trace, _ := ctx.Value(queryTraceKey{}).(*QueryTrace)
+	return trace
+}
+
+// tracingClient decorates a resultsClient to record requests made with a
+// traced context. Untraced calls pass straight through.
+type tracingClient struct {
+	next resultsClient
+}
+
+func (c tracingClient) getRecord(ctx context.Context, recordName string) (*record, error) {
+	trace := queryTraceFrom(ctx)
+	if trace == nil {
+		return c.next.getRecord(ctx, recordName)
+	}
+	if trace.skip(TracedRequest{Operation: "getRecord", Target: recordName}) {
+		return nil, ErrDryRun
+	}
+	start := time.Now()
+	rec, err := c.next.getRecord(ctx, recordName)
+	req := TracedRequest{Operation: "getRecord", Target: recordName}
+	if rec != nil {
+		req.Items = 1
+	}
+	trace.record(req, start, err)
+	return rec, err
+}
+
+func (c tracingClient) listResults(ctx context.Context, lr listResultsRequest) (*listResultsResponse, error) {
+	trace := queryTraceFrom(ctx)
+	if trace == nil {
+		return c.next.listResults(ctx, lr)
+	}
+	req := TracedRequest{Operation: "listResults", Target: lr.Parent, Filter: lr.Filter, OrderBy: lr.OrderBy, PageSize: lr.PageSize, PageToken: lr.PageToken != ""}
+	if trace.skip(req) {
+		return &listResultsResponse{}, nil
+	}
+	start := time.Now()
+	resp, err := c.next.listResults(ctx, lr)
+	if resp != nil {
+		req.Items, req.NextPage = len(resp.Results), resp.NextPageToken != ""
+	}
+	trace.record(req, start, err)
+	return resp, err
+}
+
+func (c tracingClient) listRecords(ctx context.Context, lr listRecordsRequest) (*listRecordsResponse, error) {
+	trace := queryTraceFrom(ctx)
+	if trace == nil {
+		return c.next.listRecords(ctx, lr)
+	}
+	req := TracedRequest{Operation: "listRecords", Target: lr.Parent, Filter: lr.Filter, OrderBy: lr.OrderBy, PageSize: lr.PageSize, PageToken: lr.PageToken != ""}
+	if trace.skip(req) {
+		return &listRecordsResponse{}, nil
+	}
+	start := time.Now()
+	resp, err := c.next.listRecords(ctx, lr)
+	if resp != nil {
+		req.Items, req.NextPage = len(resp.Records), resp.NextPageToken != ""
+	}
+	trace.record(req, start, err)
+	return resp, err
+}
+
+func (c tracingClient) getLog(ctx context.Context, logPath string) ([]byte, error) {
+	trace := queryTraceFrom(ctx)
+	if trace == nil {
+		return c.next.getLog(ctx, logPath)
+	}
+	if trace.skip(TracedRequest{Operation: "getLog", Target: logPath}) {
+		return nil, ErrDryRun
+	}
+	start := time.Now()
+	data, err := c.next.getLog(ctx, logPath)
+	trace.record(TracedRequest{Operation: "getLog", Target: logPath, Items: len(data)}, start, err)
+	return data, err
+}
+
+func (c tracingClient) deleteResult(ctx context.Context, resultName string) error {
+	trace := queryTraceFrom(ctx)
+	if trace == nil {
+		return c.next.deleteResult(ctx, resultName)
+	}
+	if trace.skip(TracedRequest{Operation: "deleteResult", Target: resultName}) {
+		return ErrDryRun
+	}
+	start := time.Now()
+	err := c.next.deleteResult(ctx, resultName)
+	trace.record(TracedRequest{Operation: "deleteResult", Target: resultName}, start, err)
+	return err
+}
@@ -0,0 +1,88 @@
+package tektonresults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTracingClient(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			if req.PageToken == "" {
+				return &listRecordsResponse{Records: testRecords("ci", testRun{Condition: "False/Failed"}, testRun{Condition: "False/Succeeded"}), NextPageToken: "next"}, nil
+			}
+			return &listRecordsResponse{Records: testRecords("ci", testRun{Condition: "False/Failed"})}, nil
+		},
+		getLogFunc: func(ctx context.Context, logPath string) ([]byte, error) {
+			return nil, fmt.Errorf("log not found")
+		},
+	}
+	service := &Service{client: tracingClient{next: mockClient}}
+
+	// Untraced calls are not recorded anywhere.
+	if _, err := service.ListPipelineRuns(context.Background(), ListOptions{Namespace: "ci", Limit: 3}); err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+
+	ctx, trace := WithQueryTrace(context.Background())
+	if _, err := service.ListPipelineRuns(ctx, ListOptions{Namespace: "ci", LabelSelector: "app=web", Limit: 3}); err != nil {
+		t.Fatalf("ListPipelineRuns() error = %v", err)
+	}
+	if _, err := service.FetchLogs(ctx, "ci/results/r1/records/r1"); err == nil {
+		t.Fatal("Expected FetchLogs to fail")
+	}
+
+	requests := trace.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 traced requests, got %+v", requests)
+	}
+	first, second, logs := requests[0], requests[1], requests[2]
+	if first.Operation != "listRecords" || first.Target != "ci/results/-" || !strings.Contains(first.Filter, `data.metadata.labels["app"]`) ||
+		first.OrderBy != "create_time desc" || first.PageSize != 3 || first.PageToken || first.Items != 2 || !first.NextPage {
+		t.Errorf("Unexpected first request: %+v", first)
+	}
+	if !second.PageToken || second.PageSize != 3 || second.Items != 1 || second.NextPage {
+		t.Errorf("Unexpected second request: %+v", second)
+	}
+	if logs.Operation != "getLog" || logs.Target != "ci/results/r1/logs/r1" || logs.Error != "log not found" {
+		t.Errorf("Unexpected log request: %+v", logs)
+	}
+}
+
+func TestDryRunClient(t *testing.T) {
+	mockClient := &mockRestClient{
+		listRecordsFunc: func(ctx context.Context, req listRecordsRequest) (*listRecordsResponse, error) {
+			t.Errorf("Unexpected listRecords request in a dry run: %+v", req)
+			return &listRecordsResponse{}, nil
+		},
+	}
+	service := &Service{client: tracingClient{next: mockClient}}
+
+	ctx, trace := WithDryRun(context.Background())
+	stats, err := service.PipelineRunStats(ctx, StatsOptions{ListOptions: ListOptions{Namespace: "ci,staging", RefName: "build"}})
+	if err != nil {
+		t.Fatalf("PipelineRunStats() error = %v", err)
+	}
+	if stats.Runs != 0 {
+		t.Errorf("Expected no runs in a dry run, got %+v", stats)
+	}
+	if _, err := service.FetchLogs(ctx, "ci/results/r1/records/r1"); !errors.Is(err, ErrDryRun) {
+		t.Errorf("Expected FetchLogs to fail with ErrDryRun, got %v", err)
+	}
+
+	requests := trace.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 planned requests, got %+v", requests)
+	}
+	for i, target := range []string{"ci/results/-", "staging/results/-", "ci/results/r1/logs/r1"} {
+		if r := requests[i]; r.Target != target || !r.DryRun {
+			t.Errorf("Unexpected request %d: %+v", i, r)
+		}
+	}
+	if first := requests[0]; first.PageSize != maxPageSize || !strings.Contains(first.Filter, `data.spec.pipelineRef.name`) {
+		t.Errorf("Unexpected first request: %+v", first)
+	}
+}
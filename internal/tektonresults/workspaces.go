@@ -0,0 +1,107 @@
+package tektonresults
+
+import (
+	"strings"
+)
+
+// Volume types a workspace is bound to, as named in the run spec.
+const (
+	VolumePersistentVolumeClaim = "persistentVolumeClaim"
+	VolumeClaimTemplate         = "volumeClaimTemplate"
+	VolumeEmptyDir              = "emptyDir"
+	VolumeConfigMap             = "configMap"
+	VolumeSecret                = "secret"
+	VolumeProjected             = "projected"
+	VolumeCSI                   = "csi"
+)
+
+// Workspace is a workspace binding of a run: the volume a workspace of the
+// Pipeline or Task was given.
+type Workspace struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // one of the Volume constants; empty for bindings of an unknown type
+	// ClaimName names the PVC of a persistentVolumeClaim binding, the
+	// ConfigMap or Secret of those bindings. TaskRuns of a PipelineRun name
+	// the PVC created from a volumeClaimTemplate here.
+	ClaimName    string   `json:"claimName,omitempty"`
+	Size         string   `json:"size,omitempty"` // storage requested by a volumeClaimTemplate, or the size limit of an emptyDir
+	StorageClass string   `json:"storageClass,omitempty"`
+	AccessModes  []string `json:"accessModes,omitempty"`
+	SubPath      string   `json:"subPath,omitempty"`
+	ReadOnly     bool     `json:"readOnly,omitempty"`
+}
+
+// workspaceBinding is a Tekton WorkspaceBinding, which PipelineRuns and
+// TaskRuns share.
+type workspaceBinding struct {
+	Name                  string `json:"name"`
+	SubPath               string `json:"subPath"`
+	PersistentVolumeClaim *struct {
+		ClaimName string `json:"claimName"`
+		ReadOnly  bool   `json:"readOnly"`
+	} `json:"persistentVolumeClaim"`
+	VolumeClaimTemplate *struct {
+		Spec struct {
+			AccessModes      []string `json:"accessModes"`
+			StorageClassName string   `json:"storageClassName"`
+			Resources        struct {
+				Requests map[string]string `json:"requests"`
+			} `json:"resources"`
+		} `json:"spec"`
+	} `json:"volumeClaimTemplate"`
+	EmptyDir *struct {
+		SizeLimit string `json:"sizeLimit"`
+	} `json:"emptyDir"`
+	ConfigMap *struct {
+		Name string `json:"name"`
+	} `json:"configMap"`
+	Secret *struct {
+		SecretName string `json:"secretName"`
+	} `json:"secret"`
+	Projected *struct{} `json:"projected"`
+	CSI       *struct{} `json:"csi"`
+}
+
+func (b workspaceBinding) workspace() Workspace {
+	w := Workspace{Name: b.Name, SubPath: b.SubPath}
+	switch {
+	case b.PersistentVolumeClaim != nil:
+		w.Type, w.ClaimName, w.ReadOnly = VolumePersistentVolumeClaim, b.PersistentVolumeClaim.ClaimName, b.PersistentVolumeClaim.ReadOnly
+	case b.VolumeClaimTemplate != nil:
+		spec := b.VolumeClaimTemplate.Spec
+		w.Type, w.Size, w.StorageClass, w.AccessModes = VolumeClaimTemplate, spec.Resources.Requests["storage"], spec.StorageClassName, spec.AccessModes
+	case b.EmptyDir != nil:
+		w.Type, w.Size = VolumeEmptyDir, b.EmptyDir.SizeLimit
+	case b.ConfigMap != nil:
+		w.Type, w.ClaimName = VolumeConfigMap, b.ConfigMap.Name
+	case b.Secret != nil:
+		w.Type, w.ClaimName = VolumeSecret, b.Secret.SecretName
+	case b.Projected != nil:
+		w.Type = VolumeProjected
+	case b.CSI != nil:
+		w.Type = VolumeCSI
+	}
+	return w
+}
+
+func workspaces(bindings []workspaceBinding) []Workspace {
+	if len(bindings) == 0 {
+		return nil
+	}
+	out := make([]Workspace, 0, len(bindings))
+	for _, b := range bindings {
+		out = append(out, b.workspace())
+	}
+	return out
+}
+
+// diskFullMessage is how the kernel reports a full volume. It reaches the
+// Succeeded condition message when the failure message of a step or of its
+// pod carries it.
+const diskFullMessage = "no space left on device"
+
+// DiskFull reports whether the run failed because a volume filled up, as far
+// as its Succeeded condition message tells.
+func (s RunSummary) DiskFull() bool {
+	return strings.Contains(strings.ToLower(s.Message), diskFullMessage)
+}
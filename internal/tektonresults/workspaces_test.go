@@ -0,0 +1,51 @@
+package tektonresults
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSummarizeRun_Workspaces(t *testing.T) {
+	raw := `{"kind":"PipelineRun","metadata":{"name":"build-x7k2p"},"spec":{"workspaces":[
+		{"name":"source","subPath":"src","persistentVolumeClaim":{"claimName":"shared"}},
+		{"name":"cache","volumeClaimTemplate":{"spec":{"accessModes":["ReadWriteOnce"],"storageClassName":"fast","resources":{"requests":{"storage":"5Gi"}}}}},
+		{"name":"scratch","emptyDir":{}},
+		{"name":"docker-config","secret":{"secretName":"registry"}}
+	]},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"Failed","message":"write /workspace/out: No space left on device"}]}}`
+	var run tektonRun
+	if err := json.Unmarshal([]byte(raw), &run); err != nil {
+		t.Fatalf("decode run: %v", err)
+	}
+	summary := summarizeRun(run, record{})
+
+	want := []Workspace{
+		{Name: "source", Type: VolumePersistentVolumeClaim, ClaimName: "shared", SubPath: "src"},
+		{Name: "cache", Type: VolumeClaimTemplate, Size: "5Gi", StorageClass: "fast", AccessModes: []string{"ReadWriteOnce"}},
+		{Name: "scratch", Type: VolumeEmptyDir},
+		{Name: "docker-config", Type: VolumeSecret, ClaimName: "registry"},
+	}
+	if len(summary.Workspaces) != len(want) {
+		t.Fatalf("Workspaces = %+v, want %+v", summary.Workspaces, want)
+	}
+	for i, w := range want {
+		got := summary.Workspaces[i]
+		if got.Name != w.Name || got.Type != w.Type || got.ClaimName != w.ClaimName || got.Size != w.Size || got.StorageClass != w.StorageClass || got.SubPath != w.SubPath || len(got.AccessModes) != len(w.AccessModes) {
+			t.Errorf("Workspaces[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+	if !summary.DiskFull() {
+		t.Error("Expected the run to be reported as failing on a full volume")
+	}
+
+	out, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("encode summary: %v", err)
+	}
+	var listed map[string]any
+	if err := json.Unmarshal(out, &listed); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+	if _, ok := listed["workspaces"]; ok {
+		t.Error("Expected workspaces to be left out of list output")
+	}
+}
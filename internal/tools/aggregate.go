@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// pageLister lists one page of runs, like RunReader.ListPipelineRunPage.
+type pageLister func(context.Context, tektonresults.ListOptions) (*tektonresults.RunPage, error)
+
+// eachRun yields every run matching opts, following page tokens, so
+// aggregations can fold over a listing of any length without holding it in
+// memory. A comma separated list of namespaces is listed one namespace after
+// the other, since page tokens do not span namespaces. The first error ends
+// the sequence; callers stop early by breaking out of the loop.
+func eachRun(ctx context.Context, list pageLister, opts tektonresults.ListOptions) iter.Seq2[tektonresults.RunSummary, error] {
+	return func(yield func(tektonresults.RunSummary, error) bool) {
+		namespaces := []string{opts.Namespace}
+		if strings.Contains(opts.Namespace, ",") {
+			namespaces = strings.Split(opts.Namespace, ",")
+		}
+		for _, ns := range namespaces {
+			pageOpts := opts
+			pageOpts.Namespace = strings.TrimSpace(ns)
+			if pageOpts.Namespace == "" {
+				continue
+			}
+			pageOpts.Limit = maxListLimit
+			pageOpts.PageToken = ""
+			for {
+				page, err := list(ctx, pageOpts)
+				if err != nil {
+					yield(tektonresults.RunSummary{}, err)
+					return
+				}
+				for _, run := range page.Runs {
+					if !yield(run, nil) {
+						return
+					}
+				}
+				if page.NextPageToken == "" {
+					break
+				}
+				pageOpts.PageToken = page.NextPageToken
+			}
+		}
+	}
+}
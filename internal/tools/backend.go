@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type backendInfoParams struct {
+	Namespace string `json:"namespace"`
+}
+
+func newBackendInfoTool(svc BackendInspector) server.ServerTool {
+	tool := newTool(
+		"backend_info",
+		[]toolExample{{}, {"namespace": "tekton-pipelines"}},
+		mcp.WithDescription("Describe the Tekton Results installation behind the API: the API versions it serves, its release, where logs are stored (or whether logs are disabled) and the retention policy. Read from the Kubernetes API discovery and the installation's ConfigMaps; parts that cannot be read are explained in notes. Use it to tell why logs or old runs are missing."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Backend Info")),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace Tekton Results is installed in. When omitted, tekton-pipelines, openshift-pipelines and tekton-results are tried."),
+		),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args backendInfoParams) (*mcp.CallToolResult, error) {
+		info := svc.BackendInfo(ctx, args.Namespace)
+		payload, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode backend info: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+type cancelParams struct {
+	selectorParams
+	Mode string `json:"mode"`
+}
+
+func newPipelineRunCancelTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	modes := make([]string, 0, len(tektonresults.CancelModes))
+	for _, m := range tektonresults.CancelModes {
+		modes = append(modes, string(m))
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Cancel a PipelineRun that is still running in the cluster by setting its spec.status. Identify the run like pipelinerun_get; runs too new to be archived in Tekton Results are looked up in the cluster by name. Fails, without changing anything, when the run already finished, is already being cancelled, or its name now belongs to a newer run."),
+		mcp.WithToolAnnotation(destructiveAnnotations("Cancel PipelineRun")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+	opts = append(opts, mcp.WithString("mode",
+		mcp.Description("How to cancel: 'Cancelled' stops all TaskRuns and skips finally tasks, 'CancelledRunFinally' cancels the TaskRuns and then runs finally tasks, 'StoppedRunFinally' lets running TaskRuns complete, starts no new ones and then runs finally tasks."),
+		mcp.DefaultString(string(tektonresults.CancelNow)),
+		mcp.Enum(modes...),
+	))
+
+	tool := newTool("pipelinerun_cancel", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipeline=nightly", "namespace": namespaceDefault, "mode": "StoppedRunFinally"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args cancelParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		mode := tektonresults.CancelMode(strings.TrimSpace(args.Mode))
+		for _, m := range tektonresults.CancelModes {
+			if strings.EqualFold(string(m), string(mode)) {
+				mode = m
+			}
+		}
+		cancelled, err := deps.Service.CancelPipelineRun(ctx, args.runSelector(req, namespaceDefault), mode)
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		payload, err := json.MarshalIndent(cancelled, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunCancelTool(t *testing.T) {
+	var gotMode tektonresults.CancelMode
+	mock := &mockPipelineRunService{
+		cancelPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error) {
+			gotMode = mode
+			if selector.Name != "nightly-x7k2p" || selector.Namespace != "ci" {
+				t.Errorf("Unexpected selector %+v", selector)
+			}
+			return &tektonresults.CancelResult{Name: "nightly-x7k2p", Namespace: "ci", UID: "uid", Mode: mode, FoundIn: "results"}, nil
+		},
+	}
+	tool := newPipelineRunCancelTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "nightly-x7k2p", "mode": "stoppedrunfinally"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError || gotMode != tektonresults.StopRunFinally || !strings.Contains(getTextFromResult(result), `"mode": "StoppedRunFinally"`) {
+		t.Errorf("Unexpected result with mode %q: %s", gotMode, getTextFromResult(result))
+	}
+
+	mock.cancelPipelineRunFunc = func(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error) {
+		return nil, &testError{msg: "PipelineRun ci/nightly-x7k2p already finished (Succeeded)"}
+	}
+	result, _ = tool.Handler(context.Background(), req)
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "already finished") {
+		t.Errorf("Expected the service error, got %s", getTextFromResult(result))
+	}
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// maxCompletionValues is the most values a completion response may carry.
+const maxCompletionValues = 100
+
+// Completer suggests argument values discovered from Tekton Results.
+type Completer interface {
+	CompletionValues(ctx context.Context, field tektonresults.CompletionField, namespace string) ([]string, error)
+}
+
+var _ Completer = (*tektonresults.Service)(nil)
+
+// completionFields maps tool argument names to the values that complete them.
+var completionFields = map[string]tektonresults.CompletionField{
+	"namespace":     tektonresults.CompleteNamespace,
+	"pipeline":      tektonresults.CompletePipeline,
+	"task":          tektonresults.CompleteTask,
+	"labelSelector": tektonresults.CompleteLabelKey,
+	"reason":        tektonresults.CompleteReason,
+	"team":          tektonresults.CompleteTeam,
+}
+
+// Complete answers an MCP completion request for one of the namespace,
+// pipeline, task, reason, team or labelSelector arguments. Pipeline, task, reason
+// and label values are looked up in namespace. For labelSelector only the key of the clause
+// being typed is completed, and each value is the whole selector with that
+// key filled in. CompletionRouter routes the requests to it.
+func Complete(ctx context.Context, svc Completer, req mcp.CompleteRequest, namespace string) (*mcp.CompleteResult, error) {
+	name, typed := req.Params.Argument.Name, req.Params.Argument.Value
+	field, ok := completionFields[name]
+	if !ok {
+		return nil, fmt.Errorf("no completions for argument %q", name)
+	}
+
+	// For selectors, complete the key of the last clause and keep the rest.
+	keep, prefix := "", typed
+	if name == "labelSelector" {
+		if i := strings.LastIndex(typed, ","); i >= 0 {
+			keep, prefix = typed[:i+1], typed[i+1:]
+		}
+		if strings.HasPrefix(strings.TrimSpace(prefix), "!") {
+			keep += "!"
+			prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "!")
+		}
+		prefix = strings.TrimSpace(prefix)
+	}
+
+	candidates, err := svc.CompletionValues(ctx, field, namespace)
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate, prefix) {
+			continue
+		}
+		result.Completion.Total++
+		if len(result.Completion.Values) < maxCompletionValues {
+			result.Completion.Values = append(result.Completion.Values, keep+candidate)
+		}
+	}
+	result.Completion.HasMore = result.Completion.Total > len(result.Completion.Values)
+	return result, nil
+}
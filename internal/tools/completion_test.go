@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// completerFunc adapts a function to Completer.
+type completerFunc func(ctx context.Context, field tektonresults.CompletionField, namespace string) ([]string, error)
+
+func (f completerFunc) CompletionValues(ctx context.Context, field tektonresults.CompletionField, namespace string) ([]string, error) {
+	return f(ctx, field, namespace)
+}
+
+func completeRequest(name, value string) mcp.CompleteRequest {
+	var req mcp.CompleteRequest
+	req.Params.Argument.Name = name
+	req.Params.Argument.Value = value
+	return req
+}
+
+func TestComplete(t *testing.T) {
+	values := map[tektonresults.CompletionField][]string{
+		tektonresults.CompleteNamespace: {"ci", "ci-staging", "prod"},
+		tektonresults.CompletePipeline:  {"build", "deploy"},
+		tektonresults.CompleteLabelKey:  {"app", "tekton.dev/pipeline", "tekton.dev/task"},
+		tektonresults.CompleteReason:    {"Failed", "PipelineRunTimeout", "TaskRunTimeout"},
+	}
+	var gotNamespace string
+	svc := completerFunc(func(ctx context.Context, field tektonresults.CompletionField, namespace string) ([]string, error) {
+		gotNamespace = namespace
+		return values[field], nil
+	})
+
+	tests := []struct {
+		name, value string
+		want        []string
+	}{
+		{"namespace", "ci", []string{"ci", "ci-staging"}},
+		{"pipeline", "", []string{"build", "deploy"}},
+		{"reason", "Pipe", []string{"PipelineRunTimeout"}},
+		{"labelSelector", "tek", []string{"tekton.dev/pipeline", "tekton.dev/task"}},
+		{"labelSelector", "app=web,tekton.dev/t", []string{"app=web,tekton.dev/task"}},
+		{"labelSelector", "app=web, !a", []string{"app=web,!app"}},
+	}
+	for _, tt := range tests {
+		result, err := Complete(context.Background(), svc, completeRequest(tt.name, tt.value), "ci")
+		if err != nil {
+			t.Fatalf("Complete(%s, %q) error = %v", tt.name, tt.value, err)
+		}
+		if !reflect.DeepEqual(result.Completion.Values, tt.want) {
+			t.Errorf("Complete(%s, %q) = %v, want %v", tt.name, tt.value, result.Completion.Values, tt.want)
+		}
+		if result.Completion.Total != len(tt.want) || result.Completion.HasMore {
+			t.Errorf("Complete(%s, %q): total=%d hasMore=%v", tt.name, tt.value, result.Completion.Total, result.Completion.HasMore)
+		}
+	}
+	if gotNamespace != "ci" {
+		t.Errorf("Expected namespace to be passed through, got %q", gotNamespace)
+	}
+}
+
+func TestComplete_Limits(t *testing.T) {
+	var many []string
+	for i := 0; i < 150; i++ {
+		many = append(many, fmt.Sprintf("ns-%03d", i))
+	}
+	svc := completerFunc(func(ctx context.Context, field tektonresults.CompletionField, namespace string) ([]string, error) {
+		return many, nil
+	})
+
+	result, err := Complete(context.Background(), svc, completeRequest("namespace", "ns-"), "")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(result.Completion.Values) != maxCompletionValues || result.Completion.Total != 150 || !result.Completion.HasMore {
+		t.Errorf("Expected %d of 150 values with hasMore, got %d of %d (hasMore=%v)",
+			maxCompletionValues, len(result.Completion.Values), result.Completion.Total, result.Completion.HasMore)
+	}
+
+	if _, err := Complete(context.Background(), svc, completeRequest("output", ""), ""); err == nil {
+		t.Error("Expected error for an argument without completions")
+	}
+}
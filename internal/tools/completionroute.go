@@ -0,0 +1,271 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CompletionRouter answers completion/complete requests for the arguments
+// of the prompts and resource templates, such as the namespace and team of
+// failures_standup. mcp-go does not route the method, so the router sits in
+// front of the server on both transports and adds the completions capability
+// to initialize responses.
+type CompletionRouter struct {
+	svc       Completer
+	namespace string // looked up when the request names no namespace
+}
+
+// NewCompletionRouter returns a router completing from svc, in namespace
+// unless the request carries a namespace argument.
+func NewCompletionRouter(svc Completer, namespace string) *CompletionRouter {
+	return &CompletionRouter{svc: svc, namespace: namespace}
+}
+
+// methodComplete is the MCP method of completion requests, which mcp-go does
+// not define.
+const methodComplete = "completion/complete"
+
+// rpcMessage is the part of a JSON-RPC message the router reads.
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// completeParams are the params of completion/complete, with the context
+// arguments of newer protocol revisions that mcp.CompleteParams lacks.
+type completeParams struct {
+	Argument struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"argument"`
+	Context struct {
+		Arguments map[string]string `json:"arguments"`
+	} `json:"context"`
+}
+
+// method returns the method and id of a JSON-RPC message. The method is ""
+// for responses and anything unparsable.
+func method(msg []byte) (string, json.RawMessage) {
+	var m rpcMessage
+	if json.Unmarshal(msg, &m) != nil {
+		return "", nil
+	}
+	return m.Method, m.ID
+}
+
+// answer returns the response to msg when it is a completion/complete
+// request.
+func (r *CompletionRouter) answer(ctx context.Context, msg []byte) ([]byte, bool) {
+	var m rpcMessage
+	if json.Unmarshal(msg, &m) != nil || m.Method != methodComplete {
+		return nil, false
+	}
+	var params completeParams
+	if err := json.Unmarshal(m.Params, &params); err != nil {
+		return rpcResponse(m.ID, nil, &mcp.JSONRPCErrorDetails{Code: mcp.INVALID_PARAMS, Message: err.Error()}), true
+	}
+	if _, ok := completionFields[params.Argument.Name]; !ok {
+		// Arguments without known values have no suggestions.
+		result := mcp.CompleteResult{}
+		result.Completion.Values = []string{}
+		return rpcResponse(m.ID, result, nil), true
+	}
+	req := mcp.CompleteRequest{}
+	req.Params.Argument.Name, req.Params.Argument.Value = params.Argument.Name, params.Argument.Value
+	namespace := r.namespace
+	if ns := strings.TrimSpace(params.Context.Arguments["namespace"]); ns != "" && params.Argument.Name != "namespace" {
+		namespace = ns
+	}
+	result, err := Complete(ctx, r.svc, req, namespace)
+	if err != nil {
+		return rpcResponse(m.ID, nil, &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: err.Error()}), true
+	}
+	return rpcResponse(m.ID, result, nil), true
+}
+
+func rpcResponse(id json.RawMessage, result any, rpcErr *mcp.JSONRPCErrorDetails) []byte {
+	msg := map[string]any{"jsonrpc": mcp.JSONRPC_VERSION, "id": id}
+	if rpcErr != nil {
+		msg["error"] = rpcErr
+	} else {
+		msg["result"] = result
+	}
+	out, _ := json.Marshal(msg)
+	return out
+}
+
+// advertise adds the completions capability to an initialize response. Other
+// messages are returned unchanged.
+func advertise(msg []byte) []byte {
+	var response map[string]json.RawMessage
+	if json.Unmarshal(msg, &response) != nil || response["result"] == nil {
+		return msg
+	}
+	var result map[string]json.RawMessage
+	if json.Unmarshal(response["result"], &result) != nil {
+		return msg
+	}
+	capabilities := map[string]json.RawMessage{}
+	if raw, ok := result["capabilities"]; ok && json.Unmarshal(raw, &capabilities) != nil {
+		return msg
+	}
+	capabilities["completions"] = json.RawMessage(`{}`)
+	result["capabilities"], _ = json.Marshal(capabilities)
+	response["result"], _ = json.Marshal(result)
+	out, err := json.Marshal(response)
+	if err != nil {
+		return msg
+	}
+	return out
+}
+
+// Middleware answers completion/complete requests of sessions valid in
+// sessions ahead of next, the streamable HTTP handler of the server. Requests
+// of unknown sessions go to next, which rejects them.
+func (r *CompletionRouter) Middleware(next http.Handler, sessions server.SessionIdManager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			next.ServeHTTP(w, req)
+			return
+		}
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "read request body", http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		switch name, _ := method(body); name {
+		case methodComplete:
+			id := req.Header.Get(server.HeaderKeySessionID)
+			if terminated, err := sessions.Validate(id); id == "" || terminated || err != nil {
+				next.ServeHTTP(w, req)
+				return
+			}
+			response, _ := r.answer(req.Context(), body)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(response)
+		case string(mcp.MethodInitialize):
+			buffered := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+			next.ServeHTTP(buffered, req)
+			out := buffered.body.Bytes()
+			if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+				out = advertise(out)
+				w.Header().Del("Content-Length")
+			}
+			w.WriteHeader(buffered.status)
+			_, _ = w.Write(out)
+		default:
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// bufferedResponse holds a response back so it can be changed before it is
+// sent.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// Stdio returns the input and output of a stdio server behind the router.
+// Completion requests read from in are answered on out, initialize
+// responses written to out advertise completions, and everything else
+// passes through.
+func (r *CompletionRouter) Stdio(ctx context.Context, in io.Reader, out io.Writer) (io.Reader, io.Writer) {
+	w := &stdioWriter{out: out, initialize: map[string]bool{}}
+	pr, pw := io.Pipe()
+	go func() {
+		lines := bufio.NewReader(in)
+		for {
+			line, err := lines.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				switch name, id := method(line); name {
+				case methodComplete:
+					go func(request []byte) {
+						response, _ := r.answer(ctx, request)
+						_ = w.writeLine(response)
+					}(line)
+					line = nil
+				case string(mcp.MethodInitialize):
+					w.expect(id)
+				}
+			}
+			if len(line) > 0 {
+				if _, werr := pw.Write(line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr, w
+}
+
+// stdioWriter writes whole lines to out, so answers of the router do not
+// interleave with the responses of the server.
+type stdioWriter struct {
+	out io.Writer
+
+	mu         sync.Mutex
+	partial    []byte
+	initialize map[string]bool // ids of initialize requests not answered yet
+}
+
+func (w *stdioWriter) expect(id json.RawMessage) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.initialize[string(id)] = true
+}
+
+func (w *stdioWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.partial[:i+1]
+		if len(w.initialize) > 0 {
+			var m rpcMessage
+			if json.Unmarshal(line, &m) == nil && m.Method == "" && w.initialize[string(m.ID)] {
+				delete(w.initialize, string(m.ID))
+				line = append(advertise(line[:i]), '\n')
+			}
+		}
+		if _, err := w.out.Write(line); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+}
+
+func (w *stdioWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.out.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const (
+	initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	completeTeam      = `{"jsonrpc":"2.0","id":2,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"failures_standup"},"argument":{"name":"team","value":"p"},"context":{"arguments":{"namespace":"prod"}}}}`
+)
+
+// completionResponse is a JSON-RPC response to initialize or
+// completion/complete.
+type completionResponse struct {
+	ID     int `json:"id"`
+	Result struct {
+		Capabilities map[string]json.RawMessage `json:"capabilities"`
+		Completion   struct {
+			Values []string `json:"values"`
+		} `json:"completion"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newTestRouter(t *testing.T) (*server.MCPServer, *CompletionRouter, *string) {
+	t.Helper()
+	s, err := NewServer(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "ci"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	var namespace string
+	router := NewCompletionRouter(completerFunc(func(ctx context.Context, field tektonresults.CompletionField, ns string) ([]string, error) {
+		namespace = ns
+		if field != tektonresults.CompleteTeam {
+			t.Errorf("Expected team values, got %v", field)
+		}
+		return []string{"payments", "platform", "search"}, nil
+	}), "ci")
+	return s, router, &namespace
+}
+
+func TestCompletionRouter_Stdio(t *testing.T) {
+	s, router, namespace := newTestRouter(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clientIn, serverIn := io.Pipe()
+	serverOut, clientOut := io.Pipe()
+	in, out := router.Stdio(ctx, clientIn, clientOut)
+	go func() { _ = server.NewStdioServer(s).Listen(ctx, in, out) }()
+
+	responses := bufio.NewScanner(serverOut)
+	call := func(request string) completionResponse {
+		t.Helper()
+		if _, err := io.WriteString(serverIn, request+"\n"); err != nil {
+			t.Fatalf("write request: %v", err)
+		}
+		if !responses.Scan() {
+			t.Fatalf("no response: %v", responses.Err())
+		}
+		var response completionResponse
+		if err := json.Unmarshal(responses.Bytes(), &response); err != nil {
+			t.Fatalf("decode %s: %v", responses.Text(), err)
+		}
+		return response
+	}
+
+	if init := call(initializeRequest); init.Result.Capabilities["completions"] == nil || init.Result.Capabilities["tools"] == nil {
+		t.Errorf("Expected the completions capability next to tools, got %v", init.Result.Capabilities)
+	}
+	_, _ = io.WriteString(serverIn, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+	complete := call(completeTeam)
+	if complete.ID != 2 || complete.Error != nil || strings.Join(complete.Result.Completion.Values, ",") != "payments,platform" {
+		t.Errorf("Unexpected completion %+v", complete)
+	}
+	if *namespace != "prod" {
+		t.Errorf("Expected the namespace of the request context, got %q", *namespace)
+	}
+	// Requests the router does not answer still reach the server.
+	if ping := call(`{"jsonrpc":"2.0","id":3,"method":"ping"}`); ping.ID != 3 || ping.Error != nil {
+		t.Errorf("Unexpected ping response %+v", ping)
+	}
+}
+
+func TestCompletionRouter_HTTP(t *testing.T) {
+	s, router, _ := newTestRouter(t)
+	sessions := &server.InsecureStatefulSessionIdManager{}
+	ts := httptest.NewServer(router.Middleware(server.NewStreamableHTTPServer(s, server.WithSessionIdManager(sessions)), sessions))
+	defer ts.Close()
+
+	post := func(session, body string) (*http.Response, completionResponse) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if session != "" {
+			req.Header.Set(server.HeaderKeySessionID, session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var response completionResponse
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+		return resp, response
+	}
+
+	resp, init := post("", initializeRequest)
+	session := resp.Header.Get(server.HeaderKeySessionID)
+	if session == "" || init.Result.Capabilities["completions"] == nil {
+		t.Fatalf("Expected a session advertising completions, got session %q and %v", session, init.Result.Capabilities)
+	}
+	if _, complete := post(session, completeTeam); complete.Error != nil || len(complete.Result.Completion.Values) != 2 {
+		t.Errorf("Unexpected completion %+v", complete)
+	}
+	if resp, _ := post("mcp-session-00000000-0000-0000-0000-000000000000", completeTeam); resp.StatusCode == http.StatusOK {
+		t.Error("Expected completions of an unknown session to be rejected")
+	}
+}
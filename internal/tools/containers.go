@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func containerOption() mcp.ToolOption {
+	return mcp.WithString("container",
+		mcp.Description("Only include the output of these containers (comma separated step or sidecar names); prefix a name with ! to leave it out. 'steps' and 'sidecars' stand for all steps or all sidecars, e.g. '!sidecars' drops sidecar noise and 'dind' shows only a docker-in-docker sidecar. Logs with sidecar output are split into sections labeled per container either way."),
+		mcp.DefaultString(""),
+		examples("!sidecars", "build,test"),
+	)
+}
+
+// containerFilter selects the container sections of a log by the container
+// argument.
+type containerFilter struct {
+	include, exclude map[string]bool
+}
+
+func parseContainerFilter(input string) containerFilter {
+	f := containerFilter{include: map[string]bool{}, exclude: map[string]bool{}}
+	for _, name := range strings.Split(input, ",") {
+		name = strings.TrimSpace(name)
+		set := f.include
+		if rest, ok := strings.CutPrefix(name, "!"); ok {
+			name, set = strings.TrimSpace(rest), f.exclude
+		}
+		// Full container names are accepted too.
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "step-"), "sidecar-")
+		if name != "" {
+			set[name] = true
+		}
+	}
+	return f
+}
+
+func (f containerFilter) empty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+func (f containerFilter) matches(s tektonresults.LogSection) bool {
+	group := "steps"
+	if s.Sidecar {
+		group = "sidecars"
+	}
+	if f.exclude[s.Container] || f.exclude[group] {
+		return false
+	}
+	return len(f.include) == 0 || f.include[s.Container] || f.include[group]
+}
+
+// containerLogs labels the container sections of logs when a sidecar wrote
+// to them or a filter applies, and keeps only the sections filter matches.
+// Logs of steps alone are returned unchanged. The note explains a filter
+// that could not be applied or matched nothing.
+func containerLogs(logs string, sidecars []string, filter containerFilter) (text, note string) {
+	sections := tektonresults.SplitLogs(logs, sidecars)
+	if sections == nil {
+		if !filter.empty() {
+			note = "these logs carry no container prefixes, so container was not applied"
+		}
+		return logs, note
+	}
+	hasSidecar := false
+	for _, s := range sections {
+		hasSidecar = hasSidecar || s.Sidecar
+	}
+	if filter.empty() && !hasSidecar {
+		return logs, ""
+	}
+
+	var b strings.Builder
+	var names []string
+	seen := map[string]bool{}
+	written := ""
+	for _, s := range sections {
+		label := containerLabel(s)
+		if !seen[label] {
+			seen[label] = true
+			names = append(names, label)
+		}
+		if !filter.matches(s) {
+			continue
+		}
+		// Sections split only by output of containers left out are joined.
+		if s.Container != "" && label != written {
+			fmt.Fprintf(&b, "--- %s ---\n", label)
+		}
+		written = label
+		for _, line := range s.Lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	if b.Len() == 0 {
+		return "", fmt.Sprintf("no output from the selected containers; the logs hold %s", strings.Join(names, ", "))
+	}
+	return b.String(), ""
+}
+
+func containerLabel(s tektonresults.LogSection) string {
+	switch {
+	case s.Sidecar:
+		return "sidecar " + s.Container
+	case s.Container == "":
+		return "unlabeled output"
+	}
+	return "step " + s.Container
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func newPipelineRunCriticalPathTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Find the critical path of a PipelineRun: the chain of dependent pipeline tasks, weighted by how long their TaskRuns took, that bounds the duration of the run. For each critical task it reports how much faster the run would be if the task took no time, and for the other tasks how much slack they have. Dependencies that are only runAfter orderings, with no results flowing, are flagged as candidates to parallelize."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("PipelineRun Critical Path")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+
+	tool := newTool("pipelinerun_critical_path", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipeline=build-pipeline", "namespace": namespaceDefault},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args selectorParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		detail, err := deps.Service.GetPipelineRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		taskRuns, err := deps.childTaskRuns(ctx, detail.Summary)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list TaskRuns of %s: %v", detail.Summary.Name, err)), nil
+		}
+		path, err := detail.CriticalPath(taskRuns)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(renderCriticalPath(detail.Summary, path)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// renderCriticalPath formats the path, a table of every task and the
+// runAfter orderings on the path that could be dropped.
+func renderCriticalPath(run tektonresults.RunSummary, path *tektonresults.CriticalPath) string {
+	byName := make(map[string]tektonresults.CriticalTask, len(path.Tasks))
+	for _, t := range path.Tasks {
+		byName[t.Name] = t
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Critical path of PipelineRun %s/%s: %s\n", run.Namespace, run.Name, format.Duration(path.Total))
+	steps := make([]string, 0, len(path.Path))
+	for _, name := range path.Path {
+		steps = append(steps, fmt.Sprintf("%s (%s)", name, format.Duration(byName[name].Duration)))
+	}
+	fmt.Fprintf(&b, "%s\n", strings.Join(steps, " -> "))
+	if path.Wall > 0 {
+		fmt.Fprintf(&b, "The run took %s; %s went to scheduling pods and waiting between tasks.\n", format.Duration(path.Wall), format.Duration(max(0, path.Wall-path.Total)))
+	}
+
+	b.WriteString("\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tDURATION\tCRITICAL\tSAVING IF INSTANT\tSLACK\tWAITS FOR")
+	for _, t := range path.Tasks {
+		duration := format.Duration(t.Duration)
+		if t.NotRun {
+			duration = "not run"
+		}
+		critical, saving, slack := "no", "-", format.Duration(t.Slack)
+		if t.Critical {
+			critical, saving, slack = "yes", format.Duration(t.Saving), "-"
+		}
+		name := t.Name
+		if t.Finally {
+			name += " (finally)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name, duration, critical, saving, slack, waitsFor(t))
+	}
+	_ = w.Flush()
+
+	var hints []string
+	for i := 1; i < len(path.Path); i++ {
+		t, before := byName[path.Path[i]], path.Path[i-1]
+		if t.Finally || t.After[before] != tektonresults.DependsOnOrder {
+			continue
+		}
+		hints = append(hints, fmt.Sprintf("- %s waits for %s through runAfter only; no results flow between them, so running them in parallel could save up to %s.", t.Name, before, format.Duration(min(t.Saving, byName[before].Saving))))
+	}
+	if len(hints) > 0 {
+		b.WriteString("\nCandidates to parallelize:\n")
+		b.WriteString(strings.Join(hints, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// waitsFor lists the tasks t waits for, marking result dependencies. The
+// tasks every finally task waits for are summarized.
+func waitsFor(t tektonresults.CriticalTask) string {
+	if t.Finally {
+		return "all tasks"
+	}
+	names := make([]string, 0, len(t.After))
+	for name, kind := range t.After {
+		if kind == tektonresults.DependsOnResults {
+			name += " (results)"
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunCriticalPathTool(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) *metav1.Time {
+		t := metav1.NewTime(base.Add(time.Duration(minutes) * time.Minute))
+		return &t
+	}
+	var listed tektonresults.ListOptions
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{Name: "build-x7k2p", Namespace: "ci", UID: "pr-uid", StartTime: at(0), CompletionTime: at(20)},
+				Raw: []byte(`{"status": {"pipelineSpec": {"tasks": [
+					{"name": "clone"},
+					{"name": "build", "runAfter": ["clone"], "params": [{"name": "src", "value": "$(tasks.clone.results.path)"}]},
+					{"name": "scan", "runAfter": ["build"]}
+				]}}}`),
+			}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			listed = opts
+			return []tektonresults.RunSummary{
+				{PipelineTask: "clone", StartTime: at(0), CompletionTime: at(2)},
+				{PipelineTask: "build", StartTime: at(3), CompletionTime: at(13)},
+				{PipelineTask: "scan", StartTime: at(14), CompletionTime: at(19)},
+			}, nil
+		},
+	}
+	tool := newPipelineRunCriticalPathTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "build-x7k2p"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", text)
+	}
+	if listed.LabelSelector != "tekton.dev/pipelineRunUID=pr-uid" {
+		t.Errorf("Expected the TaskRuns of the run to be listed, got %q", listed.LabelSelector)
+	}
+	for _, want := range []string{
+		"Critical path of PipelineRun ci/build-x7k2p: 17m",
+		"clone (2m) -> build (10m) -> scan (5m)",
+		"3m went to scheduling pods and waiting between tasks",
+		"clone (results)",
+		"- scan waits for build through runAfter only",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "build waits for clone") {
+		t.Errorf("A result dependency must not be suggested for parallelizing:\n%s", text)
+	}
+}
@@ -0,0 +1,332 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// A step slowed down when it took at least slowdownRatio times as long as in
+// the baseline, and at least minSlowdown longer, so that noise in short steps
+// is not reported.
+const (
+	slowdownRatio = 1.5
+	minSlowdown   = 10 * time.Second
+)
+
+type diffParams struct {
+	selectorParams
+	Baseline string `json:"baseline"`
+}
+
+// taskSteps are the steps of the TaskRun of one pipeline task.
+type taskSteps struct {
+	taskRun tektonresults.RunSummary
+	steps   []tektonresults.StepState
+	err     error
+}
+
+func newPipelineRunDiffTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Compare a PipelineRun with a baseline run step by step: for every pipeline task both runs share, the image, duration and exit code of each step side by side, with the steps that newly failed, slowed down or run another image listed first. The baseline defaults to the previous run of the same Pipeline. Use it to find where a regression lives."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Diff PipelineRuns")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+	opts = append(opts, mcp.WithString("baseline",
+		mcp.Description("Name of the PipelineRun to compare with, in the same namespace. Defaults to the newest run of the same Pipeline (tekton.dev/pipeline label) that started before it."),
+		mcp.DefaultString(""),
+	))
+
+	tool := newTool("pipelinerun_diff", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault, "baseline": "build-pipeline-run-m4q9z"},
+		{"labelSelector": "tekton.dev/pipeline=build-pipeline", "namespace": namespaceDefault},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args diffParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		target, err := deps.Service.GetPipelineRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+
+		var baseline *tektonresults.RunSummary
+		if name := strings.TrimSpace(args.Baseline); name != "" {
+			detail, err := deps.Service.GetPipelineRun(ctx, tektonresults.RunSelector{Namespace: target.Summary.Namespace, Name: name, SelectLast: true})
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("baseline: %v", err)), nil
+			}
+			baseline = &detail.Summary
+		} else {
+			pipeline := target.Summary.Labels["tekton.dev/pipeline"]
+			if pipeline == "" {
+				return mcp.NewToolResultError(fmt.Sprintf("PipelineRun %s has no tekton.dev/pipeline label; pass baseline to name the run to compare with", target.Summary.Name)), nil
+			}
+			if baseline, err = previousRun(ctx, deps.Service, target.Summary, pipeline); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if baseline == nil {
+				return mcp.NewToolResultText(fmt.Sprintf("No earlier run of Pipeline %s to compare %s with. Pass baseline to name one.", pipeline, target.Summary.Name)), nil
+			}
+		}
+
+		targetTasks, err := pipelineRunSteps(ctx, deps.Service, target.Summary)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		baselineTasks, err := pipelineRunSteps(ctx, deps.Service, *baseline)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(renderStepDiff(target.Summary, *baseline, targetTasks, baselineTasks)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// previousRun returns the newest run of pipeline that started before run, or
+// nil when there is none.
+func previousRun(ctx context.Context, svc RunReader, run tektonresults.RunSummary, pipeline string) (*tektonresults.RunSummary, error) {
+	runs, err := svc.ListPipelineRuns(ctx, tektonresults.ListOptions{
+		Namespace:     run.Namespace,
+		LabelSelector: fmt.Sprintf("tekton.dev/pipeline=%s", pipeline),
+		Limit:         defaultListLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs of Pipeline %s: %w", pipeline, err)
+	}
+	started := timeOf(run.StartTime)
+	for i := range runs {
+		if runs[i].UID == run.UID {
+			continue
+		}
+		if start := timeOf(runs[i].StartTime); started.IsZero() || !start.IsZero() && start.Before(started) {
+			return &runs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// pipelineRunSteps returns the steps of every TaskRun of a PipelineRun, keyed
+// by pipeline task.
+func pipelineRunSteps(ctx context.Context, svc RunReader, run tektonresults.RunSummary) (map[string]taskSteps, error) {
+	taskRuns, err := svc.ListTaskRuns(ctx, tektonresults.ListOptions{
+		Namespace:     run.Namespace,
+		LabelSelector: fmt.Sprintf("tekton.dev/pipelineRunUID=%s", run.UID),
+		Limit:         maxListLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TaskRuns of %s: %w", run.Name, err)
+	}
+	tasks := make(map[string]taskSteps, len(taskRuns))
+	for _, tr := range taskRuns {
+		key := tr.PipelineTask
+		if key == "" {
+			key = tr.Name
+		}
+		entry := taskSteps{taskRun: tr}
+		if detail, err := svc.GetRunByRecord(ctx, tr.RecordName); err != nil {
+			entry.err = err
+		} else {
+			entry.steps = detail.Steps()
+		}
+		tasks[key] = entry
+	}
+	return tasks, nil
+}
+
+// stepChange is one row of the step comparison.
+type stepChange struct {
+	task, step       string
+	baseline, target *tektonresults.StepState
+	findings         []string
+	newlyFailed      bool
+	imageChanged     bool
+}
+
+// compareSteps pairs the steps of a pipeline task by name, in the order of
+// the target run followed by steps only the baseline ran.
+func compareSteps(task string, baseline, target []tektonresults.StepState) []stepChange {
+	byName := make(map[string]*tektonresults.StepState, len(baseline))
+	for i := range baseline {
+		byName[baseline[i].Name] = &baseline[i]
+	}
+	var rows []stepChange
+	seen := map[string]bool{}
+	for i := range target {
+		t := &target[i]
+		seen[t.Name] = true
+		row := stepChange{task: task, step: t.Name, baseline: byName[t.Name], target: t}
+		b := row.baseline
+		switch {
+		case b == nil:
+			row.findings = append(row.findings, "new step")
+		case t.Failed() && !b.Failed():
+			row.newlyFailed = true
+			row.findings = append(row.findings, "newly failed")
+		case b.Failed() && t.ExitCode != nil && !t.Failed():
+			row.findings = append(row.findings, "fixed")
+		}
+		if b != nil && b.Duration > 0 && t.Duration-b.Duration >= minSlowdown && float64(t.Duration) >= slowdownRatio*float64(b.Duration) {
+			row.findings = append(row.findings, fmt.Sprintf("slowed down %s (+%s)", format.Duration(t.Duration-b.Duration), percent(t.Duration, b.Duration)))
+		}
+		if b != nil && b.Image != "" && t.Image != "" && b.Image != t.Image {
+			row.imageChanged = true
+			row.findings = append(row.findings, "image changed")
+		}
+		rows = append(rows, row)
+	}
+	for i := range baseline {
+		if !seen[baseline[i].Name] {
+			rows = append(rows, stepChange{task: task, step: baseline[i].Name, baseline: &baseline[i], findings: []string{"removed step"}})
+		}
+	}
+	return rows
+}
+
+func percent(now, before time.Duration) string {
+	return fmt.Sprintf("%.0f%%", 100*float64(now-before)/float64(before))
+}
+
+// renderStepDiff lays out the step comparison of two PipelineRuns: the
+// notable changes first, then every step of the shared pipeline tasks side
+// by side.
+func renderStepDiff(target, baseline tektonresults.RunSummary, targetTasks, baselineTasks map[string]taskSteps) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PipelineRun %s (%s, started %s)\n", target.Name, runState(target), format.Timestamp(timeOf(target.StartTime)))
+	fmt.Fprintf(&b, "compared with baseline %s (%s, started %s)\n", baseline.Name, runState(baseline), format.Timestamp(timeOf(baseline.StartTime)))
+
+	var shared, onlyTarget, onlyBaseline []string
+	for _, task := range orderedTasks(targetTasks) {
+		if _, ok := baselineTasks[task]; ok {
+			shared = append(shared, task)
+		} else {
+			onlyTarget = append(onlyTarget, task)
+		}
+	}
+	for _, task := range orderedTasks(baselineTasks) {
+		if _, ok := targetTasks[task]; !ok {
+			onlyBaseline = append(onlyBaseline, task)
+		}
+	}
+
+	var rows []stepChange
+	var unreadable []string
+	for _, task := range shared {
+		t, base := targetTasks[task], baselineTasks[task]
+		for _, entry := range []taskSteps{t, base} {
+			if entry.err != nil {
+				unreadable = append(unreadable, fmt.Sprintf("%s (%s): %v", task, entry.taskRun.Name, entry.err))
+			}
+		}
+		rows = append(rows, compareSteps(task, base.steps, t.steps)...)
+	}
+
+	// Newly failed steps lead, as they are usually the regression asked about.
+	var findings []string
+	for _, pass := range []func(stepChange) bool{
+		func(r stepChange) bool { return r.newlyFailed },
+		func(r stepChange) bool { return !r.newlyFailed },
+	} {
+		for _, row := range rows {
+			if len(row.findings) == 0 || !pass(row) {
+				continue
+			}
+			line := fmt.Sprintf("- %s/%s: %s", row.task, row.step, strings.Join(row.findings, ", "))
+			if row.newlyFailed {
+				line += fmt.Sprintf(" (exit code %d", *row.target.ExitCode)
+				if row.target.Reason != "" {
+					line += ", " + row.target.Reason
+				}
+				line += ")"
+			}
+			if row.imageChanged {
+				line += fmt.Sprintf("\n  %s -> %s", row.baseline.Image, row.target.Image)
+			}
+			findings = append(findings, line)
+		}
+	}
+	for _, task := range onlyTarget {
+		findings = append(findings, fmt.Sprintf("- %s: only in %s", task, target.Name))
+	}
+	for _, task := range onlyBaseline {
+		findings = append(findings, fmt.Sprintf("- %s: only in %s", task, baseline.Name))
+	}
+
+	b.WriteString("\n")
+	if len(findings) == 0 {
+		b.WriteString("No step newly failed, slowed down or changed its image.\n")
+	} else {
+		b.WriteString("Changes:\n")
+		b.WriteString(strings.Join(findings, "\n"))
+		b.WriteString("\n")
+	}
+
+	if len(rows) > 0 {
+		b.WriteString("\n")
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TASK\tSTEP\tBASELINE\tTHIS RUN\tCHANGE")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", row.task, row.step, stepCell(row.baseline), stepCell(row.target), strings.Join(row.findings, ", "))
+		}
+		_ = w.Flush()
+	}
+	for _, line := range unreadable {
+		fmt.Fprintf(&b, "\nSteps unavailable for %s", line)
+	}
+	if len(unreadable) > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// stepCell renders the exit code and duration of a step for the comparison
+// table.
+func stepCell(s *tektonresults.StepState) string {
+	switch {
+	case s == nil:
+		return format.Placeholder
+	case s.ExitCode == nil:
+		return "not run"
+	case s.ClockSkew:
+		return fmt.Sprintf("exit %d, %s", *s.ExitCode, skewedDuration)
+	default:
+		return fmt.Sprintf("exit %d, %s", *s.ExitCode, format.Duration(s.Duration))
+	}
+}
+
+// orderedTasks returns the pipeline tasks of a run in the order their
+// TaskRuns started.
+func orderedTasks(tasks map[string]taskSteps) []string {
+	keys := make([]string, 0, len(tasks))
+	for key := range tasks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := timeOf(tasks[keys[i]].taskRun.StartTime), timeOf(tasks[keys[j]].taskRun.StartTime)
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
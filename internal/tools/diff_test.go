@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// stepJSON renders a terminated step of a TaskRun manifest.
+func stepJSON(name, imageID string, exitCode int, seconds int) string {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	return fmt.Sprintf(`{"name":%q,"imageID":%q,"terminated":{"exitCode":%d,"startedAt":%q,"finishedAt":%q}}`,
+		name, imageID, exitCode, start.Format(time.RFC3339), start.Add(time.Duration(seconds)*time.Second).Format(time.RFC3339))
+}
+
+func TestPipelineRunDiff(t *testing.T) {
+	started := func(hour int) *metav1.Time {
+		ts := metav1.NewTime(time.Date(2025, 1, 1, hour, 0, 0, 0, time.UTC))
+		return &ts
+	}
+	runs := map[string]tektonresults.RunSummary{
+		"build-new": {Name: "build-new", Namespace: "ci", UID: "uid-new", Labels: map[string]string{"tekton.dev/pipeline": "build"}, StartTime: started(12), Status: "False", Reason: "Failed"},
+		"build-old": {Name: "build-old", Namespace: "ci", UID: "uid-old", Labels: map[string]string{"tekton.dev/pipeline": "build"}, StartTime: started(10), Status: "True", Reason: "Succeeded"},
+	}
+	taskRuns := map[string][]tektonresults.RunSummary{
+		"uid-new": {
+			{Name: "build-new-compile", PipelineTask: "compile", RecordName: "new-compile", StartTime: started(12)},
+			{Name: "build-new-test", PipelineTask: "test", RecordName: "new-test", StartTime: started(13)},
+			{Name: "build-new-scan", PipelineTask: "scan", RecordName: "new-scan", StartTime: started(14)},
+		},
+		"uid-old": {
+			{Name: "build-old-compile", PipelineTask: "compile", RecordName: "old-compile", StartTime: started(10)},
+			{Name: "build-old-test", PipelineTask: "test", RecordName: "old-test", StartTime: started(11)},
+		},
+	}
+	steps := map[string]string{
+		"new-compile": stepJSON("build", "golang@sha256:bbb", 0, 40),
+		"old-compile": stepJSON("build", "golang@sha256:aaa", 0, 10),
+		"new-test":    stepJSON("unit", "golang@sha256:bbb", 1, 20) + "," + stepJSON("lint", "golangci", 0, 5),
+		"old-test":    stepJSON("unit", "golang@sha256:bbb", 0, 19) + "," + stepJSON("lint", "golangci", 0, 5),
+		"new-scan":    stepJSON("trivy", "trivy", 0, 5),
+	}
+
+	var listedLabel string
+	svc := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			run, ok := runs[selector.Name]
+			if !ok {
+				return nil, &testError{msg: "not found"}
+			}
+			return &tektonresults.RunDetail{Summary: run}, nil
+		},
+		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			listedLabel = opts.LabelSelector
+			return []tektonresults.RunSummary{runs["build-new"], runs["build-old"]}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return taskRuns[strings.TrimPrefix(opts.LabelSelector, "tekton.dev/pipelineRunUID=")], nil
+		},
+		getRunByRecordFunc: func(ctx context.Context, recordName string) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{Raw: json.RawMessage(`{"kind":"TaskRun","status":{"steps":[` + steps[recordName] + `]}}`)}, nil
+		},
+	}
+	tool := newPipelineRunDiffTool(Dependencies{Service: svc, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "build-new"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError {
+		t.Fatalf("Result is error: %s", text)
+	}
+	if listedLabel != "tekton.dev/pipeline=build" {
+		t.Errorf("Expected the previous run to be looked up by pipeline label, got %q", listedLabel)
+	}
+	for _, want := range []string{
+		"compared with baseline build-old",
+		"- test/unit: newly failed (exit code 1)",
+		"- compile/build: slowed down 30s (+300%), image changed",
+		"golang@sha256:aaa -> golang@sha256:bbb",
+		"- scan: only in build-new",
+		"exit 0, 19s",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "test/unit") > strings.Index(text, "compile/build") {
+		t.Errorf("Expected newly failed steps to be listed first:\n%s", text)
+	}
+	if strings.Contains(text, "lint:") {
+		t.Errorf("Unchanged steps should not be listed as changes:\n%s", text)
+	}
+
+	// An explicit baseline is used as is; a run without an earlier one is
+	// reported rather than compared with itself.
+	req.Params.Arguments = map[string]any{"name": "build-old", "baseline": "build-new"}
+	result, _ = tool.Handler(context.Background(), req)
+	if text := getTextFromResult(result); !strings.Contains(text, "- test/unit: fixed") || !strings.Contains(text, "- scan: only in build-new") {
+		t.Errorf("Unexpected diff against an explicit baseline:\n%s", text)
+	}
+	req.Params.Arguments = map[string]any{"name": "build-old"}
+	result, _ = tool.Handler(context.Background(), req)
+	if text := getTextFromResult(result); !strings.Contains(text, "No earlier run of Pipeline build") {
+		t.Errorf("Expected no baseline to be found, got:\n%s", text)
+	}
+}
@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const (
+	defaultDigestWindow = "24h"
+	defaultDigestGroups = 10
+	maxDigestGroups     = 50
+	// maxDigestRuns bounds the failed runs read for one digest, so a
+	// namespace failing in a loop does not page through its whole history.
+	maxDigestRuns = 1000
+	// maxDigestError bounds the error line shown for a group.
+	maxDigestError = 160
+)
+
+type digestParams struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	Team          string `json:"team"`
+	Since         string `json:"since"`
+	Limit         int    `json:"limit"`
+}
+
+func newFailuresDigestTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	tool := newTool(
+		"failures_digest",
+		[]toolExample{
+			{"namespace": namespaceDefault},
+			{"namespace": "-", "kind": "taskrun", "since": "8h", "limit": 5},
+			{"namespace": "ci,staging", "team": "Payments"},
+		},
+		mcp.WithDescription("Summarize recent failures for a standup: failed and timed out runs of the window, grouped by fingerprint (Pipeline or Task, reason and error message with run names, numbers and hashes masked) and ranked by count. Each group lists how often it failed, when it was first and last seen, a sample run UID to inspect with the get and logs tools, and a one-line error."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Failures Digest")),
+		mcp.WithString("kind",
+			mcp.Description("Kind of run to digest."),
+			mcp.DefaultString("pipelinerun"),
+			mcp.Enum("pipelinerun", "taskrun"),
+		),
+		namespacesOption(namespaceDefault),
+		labelSelectorOption("tekton.dev/pipeline=build-pipeline"),
+		teamOption(),
+		mcp.WithString("since",
+			mcp.Description("Start of the window: "+timeBoundFormats+" meaning that long ago."),
+			mcp.DefaultString(defaultDigestWindow),
+			examples("24h", "3d"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Number of failure groups to show, most frequent first (1-%d).", maxDigestGroups)),
+			mcp.DefaultNumber(defaultDigestGroups),
+			mcp.Min(1),
+			mcp.Max(maxDigestGroups),
+		),
+		dryRunOption(),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args digestParams) (*mcp.CallToolResult, error) {
+		now := time.Now()
+		since, err := parseTimeBound("since", cmp.Or(args.Since, defaultDigestWindow), now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		kind := "PipelineRun"
+		list := deps.Service.ListPipelineRunPage
+		if strings.EqualFold(args.Kind, "taskrun") {
+			kind, list = "TaskRun", deps.Service.ListTaskRunPage
+		}
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultDigestGroups
+		}
+
+		namespace := normalizeNamespace(args.Namespace, namespaceDefault)
+		page, err := list(ctx, tektonresults.ListOptions{
+			Namespace:     namespace,
+			LabelSelector: args.LabelSelector,
+			Team:          args.Team,
+			Status:        "failed,timedout",
+			CreatedAfter:  since,
+			Limit:         maxDigestRuns,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		groups := digestFailures(kind, page.Runs)
+		result := mcp.NewToolResultText(renderDigest(kind, namespace, since, groups, min(limit, maxDigestGroups), len(page.Runs) >= maxDigestRuns))
+		if page.Partial {
+			result.Content = append(result.Content, mcp.NewTextContent(partialScanNote+" Narrow the window or the labelSelector for exact counts."))
+		}
+		return result, nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// failureGroup collects the failed runs sharing a fingerprint.
+type failureGroup struct {
+	Fingerprint string
+	Owner       string // Pipeline or Task the runs belong to, "" when unknown
+	Reason      string
+	Error       string // message of the newest run, shortened
+	Count       int
+	First, Last time.Time
+	Sample      tektonresults.RunSummary // newest run of the group
+}
+
+var (
+	uuidPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hashPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{7,}\b`)
+	numberPattern = regexp.MustCompile(`[0-9]+`)
+)
+
+// failureFingerprint masks the parts of a failure message that differ
+// between runs failing the same way: the run's own name, which also prefixes
+// its pod and TaskRun names, UUIDs, commit hashes and numbers such as exit
+// codes, durations and line numbers.
+func failureFingerprint(owner, reason string, run tektonresults.RunSummary) string {
+	message := run.Message
+	if run.Name != "" {
+		message = strings.ReplaceAll(message, run.Name, "<run>")
+	}
+	message = uuidPattern.ReplaceAllString(message, "<uid>")
+	message = hashPattern.ReplaceAllString(message, "<hash>")
+	message = numberPattern.ReplaceAllString(message, "#")
+	sum := sha256.Sum256([]byte(owner + "\x00" + reason + "\x00" + message))
+	return hex.EncodeToString(sum[:4])
+}
+
+// runOwner returns the Pipeline or Task a run belongs to, from the labels
+// Tekton sets on every run.
+func runOwner(kind string, run tektonresults.RunSummary) string {
+	if kind == "TaskRun" {
+		return run.Labels["tekton.dev/task"]
+	}
+	return run.Labels["tekton.dev/pipeline"]
+}
+
+// digestFailures groups failed runs by fingerprint, most frequent first and
+// most recently seen first among equals.
+func digestFailures(kind string, runs []tektonresults.RunSummary) []*failureGroup {
+	byFingerprint := map[string]*failureGroup{}
+	var groups []*failureGroup
+	for _, run := range runs {
+		owner := runOwner(kind, run)
+		reason := cmp.Or(run.Reason, "Failed")
+		fp := failureFingerprint(owner, reason, run)
+		seen := cmp.Or(timeOf(run.CompletionTime), timeOf(run.StartTime))
+
+		g := byFingerprint[fp]
+		if g == nil {
+			g = &failureGroup{Fingerprint: fp, Owner: owner, Reason: reason, First: seen, Last: seen, Sample: run}
+			byFingerprint[fp] = g
+			groups = append(groups, g)
+		}
+		g.Count++
+		if seen.Before(g.First) {
+			g.First = seen
+		}
+		if seen.After(g.Last) {
+			g.Last, g.Sample = seen, run
+		}
+	}
+	for _, g := range groups {
+		g.Error = shortError(g.Sample.Message, maxDigestError)
+	}
+	slices.SortStableFunc(groups, func(a, b *failureGroup) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return b.Last.Compare(a.Last)
+	})
+	return groups
+}
+
+// shortError truncates a run summary message, which is already on one line,
+// to at most n bytes without splitting a character.
+func shortError(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
+
+// renderDigest formats the top groups as a ranked list suited for pasting
+// into a standup summary.
+func renderDigest(kind, namespace string, since time.Time, groups []*failureGroup, limit int, truncated bool) string {
+	total := 0
+	for _, g := range groups {
+		total += g.Count
+	}
+	var b strings.Builder
+	if total == 0 {
+		fmt.Fprintf(&b, "No failed %ss in namespace %s since %s.", kind, namespace, format.Timestamp(since))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d failed %s(s) in namespace %s since %s, in %d group(s)", total, kind, namespace, format.Timestamp(since), len(groups))
+	if len(groups) > limit {
+		fmt.Fprintf(&b, "; showing the %d most frequent", limit)
+	}
+	b.WriteString("\n")
+	if truncated {
+		fmt.Fprintf(&b, "Only the newest %d failures were read; narrow the window or add a labelSelector for exact counts.\n", maxDigestRuns)
+	}
+
+	for i, g := range groups[:min(limit, len(groups))] {
+		owner := cmp.Or(g.Owner, "(unknown "+strings.TrimSuffix(kind, "Run")+")")
+		fmt.Fprintf(&b, "\n%d. %s: %s, %d run(s) [fingerprint %s]\n", i+1, owner, g.Reason, g.Count, g.Fingerprint)
+		fmt.Fprintf(&b, "   First seen %s, last seen %s\n", format.Timestamp(g.First), format.Timestamp(g.Last))
+		fmt.Fprintf(&b, "   Sample: %s/%s (uid %s)\n", g.Sample.Namespace, g.Sample.Name, g.Sample.UID)
+		if g.Error != "" {
+			fmt.Fprintf(&b, "   Error: %s\n", g.Error)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func failedRun(name, pipeline, reason, message string, completed time.Time) tektonresults.RunSummary {
+	end := metav1.NewTime(completed)
+	return tektonresults.RunSummary{
+		Name:           name,
+		Namespace:      "ci",
+		UID:            "uid-" + name,
+		Labels:         map[string]string{"tekton.dev/pipeline": pipeline},
+		CompletionTime: &end,
+		Reason:         reason,
+		Message:        message,
+	}
+}
+
+func TestDigestFailures(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	groups := digestFailures("PipelineRun", []tektonresults.RunSummary{
+		failedRun("build-a1b2c", "build", "Failed", `Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 0; TaskRun build-a1b2c-test exited with code 2`, now),
+		failedRun("build-d3e4f", "build", "Failed", `Tasks Completed: 4 (Failed: 1, Cancelled 0), Skipped: 1; TaskRun build-d3e4f-test exited with code 1`, now.Add(-time.Hour)),
+		failedRun("build-g5h6i", "build", "PipelineRunTimeout", `PipelineRun "build-g5h6i" failed to finish within "1h0m0s"`, now.Add(-2*time.Hour)),
+		failedRun("deploy-j7k8l", "deploy", "Failed", `Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 0; TaskRun deploy-j7k8l-test exited with code 2`, now.Add(-3*time.Hour)),
+	})
+
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+	top := groups[0]
+	if top.Owner != "build" || top.Reason != "Failed" || top.Count != 2 {
+		t.Errorf("Expected the two build failures to be grouped first, got %+v", top)
+	}
+	if !top.First.Equal(now.Add(-time.Hour)) || !top.Last.Equal(now) || top.Sample.Name != "build-a1b2c" {
+		t.Errorf("Unexpected first/last seen or sample: %+v", top)
+	}
+	// The same message for another Pipeline is another failure.
+	if groups[1].Owner != "build" || groups[2].Owner != "deploy" {
+		t.Errorf("Expected single-run groups newest first, got %s then %s", groups[1].Owner, groups[2].Owner)
+	}
+}
+
+func TestFailuresDigestTool(t *testing.T) {
+	var got tektonresults.ListOptions
+	now := time.Now()
+	mock := &mockPipelineRunService{
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			got = opts
+			run := failedRun("build-a1b2c-test", "", "Failed", "\"step-test\" exited with code 1", now)
+			run.Labels = map[string]string{"tekton.dev/task": "unit-tests"}
+			return []tektonresults.RunSummary{run}, nil
+		},
+	}
+	tool := newFailuresDigestTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"kind": "taskrun", "namespace": "ci", "since": "8h"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if got.Namespace != "ci" || got.Status != "failed,timedout" || now.Sub(got.CreatedAfter).Round(time.Hour) != 8*time.Hour {
+		t.Errorf("Unexpected list options %+v", got)
+	}
+	for _, want := range []string{"1 failed TaskRun(s) in namespace ci", "1. unit-tests: Failed, 1 run(s)", "Sample: ci/build-a1b2c-test (uid uid-build-a1b2c-test)", `Error: "step-test" exited with code 1`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestFailuresStandupPrompt(t *testing.T) {
+	s, err := NewServer(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "ci"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	msg := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"failures_standup","arguments":{"team":"Payments"}}}`))
+	resp, ok := msg.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a response, got %#v", msg)
+	}
+	result, ok := resp.Result.(mcp.GetPromptResult)
+	if !ok || len(result.Messages) != 1 {
+		t.Fatalf("Expected one prompt message, got %#v", resp.Result)
+	}
+	text := result.Messages[0].Content.(mcp.TextContent).Text
+	if !strings.Contains(text, `failures_digest tool with namespace="ci" since="24h" team="Payments"`) {
+		t.Errorf("Unexpected prompt %q", text)
+	}
+}
@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DocFormats lists the formats accepted by WriteDocs.
+var DocFormats = []string{"markdown", "json"}
+
+// ToolDoc is the documented form of a tool definition.
+type ToolDoc struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description"`
+	ReadOnly    bool             `json:"readOnly"`
+	Destructive bool             `json:"destructive"`
+	Parameters  []ParameterDoc   `json:"parameters"`
+	Examples    []map[string]any `json:"examples,omitempty"`
+}
+
+// ParameterDoc describes one tool parameter.
+type ParameterDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Default     any    `json:"default,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Minimum     *int   `json:"minimum,omitempty"`
+	Maximum     *int   `json:"maximum,omitempty"`
+}
+
+// DocumentTools converts tool definitions into their documented form. Tools
+// keep their registration order; parameters are sorted with required ones
+// first.
+func DocumentTools(defs []mcp.Tool) ([]ToolDoc, error) {
+	docs := make([]ToolDoc, 0, len(defs))
+	for _, def := range defs {
+		// Round trip through JSON so structured and raw input schemas are
+		// handled alike.
+		payload, err := json.Marshal(def)
+		if err != nil {
+			return nil, fmt.Errorf("encode tool %s: %w", def.Name, err)
+		}
+		var decoded struct {
+			InputSchema struct {
+				Properties map[string]struct {
+					Type        string   `json:"type"`
+					Description string   `json:"description"`
+					Default     any      `json:"default"`
+					Enum        []any    `json:"enum"`
+					Minimum     *float64 `json:"minimum"`
+					Maximum     *float64 `json:"maximum"`
+				} `json:"properties"`
+				Required []string         `json:"required"`
+				Examples []map[string]any `json:"examples"`
+			} `json:"inputSchema"`
+		}
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return nil, fmt.Errorf("decode tool %s: %w", def.Name, err)
+		}
+
+		doc := ToolDoc{
+			Name:        def.Name,
+			Title:       def.Annotations.Title,
+			Description: def.Description,
+			ReadOnly:    def.Annotations.ReadOnlyHint != nil && *def.Annotations.ReadOnlyHint,
+			Destructive: def.Annotations.DestructiveHint != nil && *def.Annotations.DestructiveHint,
+			Parameters:  []ParameterDoc{},
+			Examples:    decoded.InputSchema.Examples,
+		}
+		required := map[string]bool{}
+		for _, name := range decoded.InputSchema.Required {
+			required[name] = true
+		}
+		for name, prop := range decoded.InputSchema.Properties {
+			doc.Parameters = append(doc.Parameters, ParameterDoc{
+				Name:        name,
+				Type:        prop.Type,
+				Description: prop.Description,
+				Required:    required[name],
+				Default:     prop.Default,
+				Enum:        prop.Enum,
+				Minimum:     intBound(prop.Minimum),
+				Maximum:     intBound(prop.Maximum),
+			})
+		}
+		sort.Slice(doc.Parameters, func(i, j int) bool {
+			a, b := doc.Parameters[i], doc.Parameters[j]
+			if a.Required != b.Required {
+				return a.Required
+			}
+			return a.Name < b.Name
+		})
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func intBound(v *float64) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+// WriteDocs renders tool definitions as markdown or JSON.
+func WriteDocs(w io.Writer, defs []mcp.Tool, format string) error {
+	docs, err := DocumentTools(defs)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "json":
+		payload, err := json.MarshalIndent(docs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode tool docs: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", payload)
+		return err
+	case "markdown", "":
+		return writeMarkdownDocs(w, docs)
+	default:
+		return fmt.Errorf("unsupported docs format %q; use one of: %s", format, strings.Join(DocFormats, ", "))
+	}
+}
+
+func writeMarkdownDocs(w io.Writer, docs []ToolDoc) error {
+	var b strings.Builder
+	b.WriteString("# Tools\n\n")
+	b.WriteString("<!-- Generated by `tekton-results-mcp-server -print-tools`; do not edit. -->\n")
+	for _, doc := range docs {
+		b.WriteString("\n## `" + doc.Name + "`")
+		if doc.Title != "" {
+			b.WriteString(" – " + doc.Title)
+		}
+		b.WriteString("\n\n" + doc.Description + "\n")
+		switch {
+		case doc.Destructive:
+			b.WriteString("\nThis tool modifies data and is only registered with `-enable-write-tools`.\n")
+		case doc.ReadOnly:
+			b.WriteString("\nRead-only.\n")
+		}
+
+		if len(doc.Parameters) > 0 {
+			b.WriteString("\n### Parameters\n\n")
+			for _, p := range doc.Parameters {
+				b.WriteString("- `" + p.Name + "`")
+				if p.Description != "" {
+					b.WriteString(": " + p.Description)
+				}
+				b.WriteString(" (" + parameterFacts(p) + ")\n")
+			}
+		}
+
+		if len(doc.Examples) > 0 {
+			b.WriteString("\n### Examples\n\n```json\n")
+			for _, example := range doc.Examples {
+				payload, err := json.Marshal(example)
+				if err != nil {
+					return fmt.Errorf("encode example for %s: %w", doc.Name, err)
+				}
+				b.Write(payload)
+				b.WriteString("\n")
+			}
+			b.WriteString("```\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// parameterFacts summarizes a parameter's type and constraints, e.g.
+// "number, optional, default: 50, range: 1-200".
+func parameterFacts(p ParameterDoc) string {
+	facts := []string{p.Type}
+	if p.Required {
+		facts = append(facts, "required")
+	} else {
+		facts = append(facts, "optional")
+	}
+	if p.Default != nil && p.Default != "" {
+		facts = append(facts, fmt.Sprintf("default: %v", p.Default))
+	}
+	if len(p.Enum) > 0 {
+		values := make([]string, len(p.Enum))
+		for i, v := range p.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		facts = append(facts, "one of: "+strings.Join(values, ", "))
+	}
+	switch {
+	case p.Minimum != nil && p.Maximum != nil:
+		facts = append(facts, fmt.Sprintf("range: %d-%d", *p.Minimum, *p.Maximum))
+	case p.Minimum != nil:
+		facts = append(facts, fmt.Sprintf("minimum: %d", *p.Minimum))
+	case p.Maximum != nil:
+		facts = append(facts, fmt.Sprintf("maximum: %d", *p.Maximum))
+	}
+	return strings.Join(facts, ", ")
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDefinitions_WithoutService(t *testing.T) {
+	defs, err := Definitions(Dependencies{DefaultNamespace: "default", AllowWrites: true, LiveCluster: true})
+	if err != nil {
+		t.Fatalf("Definitions() error = %v", err)
+	}
+	names := map[string]bool{}
+	for _, def := range defs {
+		names[def.Name] = true
+	}
+	for _, want := range []string{"pipelinerun_list", "taskrun_logs", "server_info", "results_prune", "pipelinerun_rerun", "pipelinerun_cancel"} {
+		if !names[want] {
+			t.Errorf("Expected %s in definitions", want)
+		}
+	}
+
+	defs, _ = Definitions(Dependencies{DefaultNamespace: "default"})
+	for _, def := range defs {
+		if def.Name == "results_prune" {
+			t.Error("Write tools must not be defined without AllowWrites")
+		}
+	}
+}
+
+func TestDocumentTools(t *testing.T) {
+	defs, err := Definitions(Dependencies{DefaultNamespace: "default", AllowWrites: true, LiveCluster: true})
+	if err != nil {
+		t.Fatalf("Definitions() error = %v", err)
+	}
+	docs, err := DocumentTools(defs)
+	if err != nil {
+		t.Fatalf("DocumentTools() error = %v", err)
+	}
+
+	var record, prune *ToolDoc
+	for i := range docs {
+		switch docs[i].Name {
+		case "run_get_by_record":
+			record = &docs[i]
+		case "results_prune":
+			prune = &docs[i]
+		}
+	}
+	if record == nil || prune == nil {
+		t.Fatalf("Expected run_get_by_record and results_prune docs, got %d tools", len(docs))
+	}
+	if !record.ReadOnly || record.Destructive {
+		t.Errorf("Expected run_get_by_record to be read-only, got %+v", record)
+	}
+	if first := record.Parameters[0]; first.Name != "recordName" || !first.Required {
+		t.Errorf("Expected required recordName first, got %+v", first)
+	}
+	if len(record.Examples) == 0 {
+		t.Error("Expected examples to be documented")
+	}
+	if !prune.Destructive {
+		t.Error("Expected results_prune to be marked destructive")
+	}
+	for _, p := range prune.Parameters {
+		if p.Name == "limit" && (p.Minimum == nil || p.Maximum == nil || *p.Maximum != 1000) {
+			t.Errorf("Expected limit bounds, got %+v", p)
+		}
+	}
+}
+
+func TestWriteDocs(t *testing.T) {
+	defs, _ := Definitions(Dependencies{DefaultNamespace: "default"})
+
+	var md bytes.Buffer
+	if err := WriteDocs(&md, defs, "markdown"); err != nil {
+		t.Fatalf("WriteDocs(markdown) error = %v", err)
+	}
+	for _, want := range []string{"## `taskrun_get` – Get TaskRun", "- `recordName`:", "(string, required)", "```json"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown missing %q", want)
+		}
+	}
+
+	var js bytes.Buffer
+	if err := WriteDocs(&js, defs, "json"); err != nil {
+		t.Fatalf("WriteDocs(json) error = %v", err)
+	}
+	var decoded []ToolDoc
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON docs do not decode: %v", err)
+	}
+	if len(decoded) != len(defs) {
+		t.Errorf("Expected %d tools, got %d", len(defs), len(decoded))
+	}
+
+	if err := WriteDocs(&js, defs, "html"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+// TestGeneratedDocsUpToDate fails when docs/ was not regenerated after a
+// tool definition changed. Run `make docs` to fix it.
+func TestGeneratedDocsUpToDate(t *testing.T) {
+	defs, err := Definitions(Dependencies{DefaultNamespace: "default", AllowWrites: true, LiveCluster: true})
+	if err != nil {
+		t.Fatalf("Definitions() error = %v", err)
+	}
+	for file, format := range map[string]string{"../../docs/tools.md": "markdown", "../../docs/tools.json": "json"} {
+		want, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		var got bytes.Buffer
+		if err := WriteDocs(&got, defs, format); err != nil {
+			t.Fatalf("WriteDocs(%s) error = %v", format, err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("%s is out of date; run `make docs`", file)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// dryRunMaxRuns is the most runs each tool with a dry run reads, which bounds
+// the pages its listings can take. Tools missing here read one page.
+var dryRunMaxRuns = map[string]int{
+	"pipelinerun_stats":   tektonresults.DefaultMaxStatsRuns,
+	"taskrun_stats":       tektonresults.DefaultMaxStatsRuns,
+	"failures_digest":     maxDigestRuns,
+	"failure_rate_series": maxSeriesRuns,
+	"workspace_usage":     maxWorkspaceRuns,
+	"query":               maxQueryRuns,
+	"pipelinerun_list":    maxGroupRuns,
+	"taskrun_list":        maxGroupRuns,
+}
+
+// dryRunPlan is the output of a tool called with dryRun=true.
+type dryRunPlan struct {
+	Tool      string                        `json:"tool"`
+	Arguments map[string]any                `json:"arguments"`
+	Requests  []tektonresults.TracedRequest `json:"requests"` // first request of each listing
+	Listings  int                           `json:"listings"`
+	MaxRuns   int                           `json:"maxRuns,omitempty"`
+	MaxPages  int                           `json:"maxPages,omitempty"` // list requests of the whole call when every record read matches
+	Notes     []string                      `json:"notes,omitempty"`
+}
+
+// dryRunOption declares the dryRun argument withDryRun handles. Every
+// analytics tool declares it, and the list tools declare groupDryRunOption.
+func dryRunOption() mcp.ToolOption {
+	return mcp.WithBoolean("dryRun",
+		mcp.Description("Return the Tekton Results API requests the call would make, with an estimate of the pages it would read, without sending any. Use it to check the scope of a query over a long window or many namespaces first."),
+		mcp.DefaultBool(false),
+	)
+}
+
+// groupDryRunOption declares the dryRun argument of the list tools, which
+// only grouped calls accept.
+func groupDryRunOption() mcp.ToolOption {
+	return mcp.WithBoolean("dryRun",
+		mcp.Description(fmt.Sprintf("With groupBy, return the Tekton Results API requests the grouped listing would make, with an estimate of the pages it would read for up to %d runs, without sending any. Not supported without groupBy.", maxGroupRuns)),
+		mcp.DefaultBool(false),
+	)
+}
+
+// withDryRun handles the dryRun argument of the analytics tools and of the
+// list tools, whose grouped calls are analytics. A call with
+// dryRun=true runs the tool against a Results API that records requests
+// instead of answering them, and returns the planned requests and an
+// estimate of the pages the call would read, so the scope of a query can be
+// confirmed before it spends minutes of API calls.
+func withDryRun(tools []server.ServerTool) []server.ServerTool {
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		if toolPriority(st.Tool.Name) != priorityAnalytics && !groupingTools[st.Tool.Name] {
+			wrapped = append(wrapped, st)
+			continue
+		}
+		name, next := st.Tool.Name, st.Handler
+		st.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.GetArguments()
+			if dryRun, _ := args["dryRun"].(bool); !dryRun {
+				return next(ctx, req)
+			}
+			if callPriority(name, req) != priorityAnalytics {
+				return mcp.NewToolResultError(fmt.Sprintf("dryRun of %s is only supported with groupBy; a listing without it reads a single page", name)), nil
+			}
+			planned, trace := tektonresults.WithDryRun(ctx)
+			result, err := next(planned, req)
+			if err != nil {
+				return nil, err
+			}
+			if result != nil && result.IsError && len(trace.Requests()) == 0 {
+				// Rejected before planning anything, e.g. an invalid argument.
+				return result, nil
+			}
+			arguments := maps.Clone(args)
+			delete(arguments, "dryRun")
+			payload, err := json.MarshalIndent(planDryRun(name, arguments, trace.Requests()), "", "  ")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(payload)), nil
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
+
+// planDryRun estimates the scope of a call from the requests it planned. Only
+// the first page of each listing is planned, since the dry run returns no
+// runs to continue from.
+func planDryRun(tool string, arguments map[string]any, requests []tektonresults.TracedRequest) dryRunPlan {
+	plan := dryRunPlan{Tool: tool, Arguments: arguments, Requests: requests, MaxRuns: dryRunMaxRuns[tool]}
+	if plan.Arguments == nil {
+		plan.Arguments = map[string]any{}
+	}
+	if plan.Requests == nil {
+		plan.Requests = []tektonresults.TracedRequest{}
+	}
+	pageSize := 0
+	for _, r := range requests {
+		if r.Operation == "listRecords" || r.Operation == "listResults" {
+			plan.Listings++
+			pageSize = max(pageSize, int(r.PageSize))
+		}
+	}
+	switch {
+	case plan.Listings == 0:
+		plan.Notes = append(plan.Notes, "The call plans no Results API listing; it is answered from its arguments or a cache.")
+	case plan.MaxRuns > 0 && pageSize > 0:
+		// Every listing but the last may end on a partial page.
+		plan.MaxPages = (plan.MaxRuns+pageSize-1)/pageSize + plan.Listings - 1
+		plan.Notes = append(plan.Notes, fmt.Sprintf("The call reads at most %d runs, in up to %d list requests of %d records. Filters that the Results API cannot apply, such as labelSelector exclusions, read more records than match.", plan.MaxRuns, plan.MaxPages, pageSize))
+	default:
+		plan.MaxPages = plan.Listings
+	}
+	if tool == "query" {
+		plan.Notes = append(plan.Notes, "Lookups of the TaskRuns and logs of each matching run depend on the runs found and are not planned.")
+	}
+	return plan
+}
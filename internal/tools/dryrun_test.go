@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestWithDryRun(t *testing.T) {
+	var calls []string
+	handler := func(name string) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls = append(calls, name)
+			if req.GetArguments()["namespace"] == "" {
+				return mcp.NewToolResultError("namespace is required"), nil
+			}
+			return mcp.NewToolResultText("stats"), nil
+		}
+	}
+	tools := withDryRun([]server.ServerTool{
+		{Tool: mcp.NewTool("taskrun_logs"), Handler: handler("taskrun_logs")},
+		{Tool: mcp.NewTool("pipelinerun_stats", dryRunOption()), Handler: handler("pipelinerun_stats")},
+	})
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tools[1].Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+	if got := getTextFromResult(call(map[string]any{"namespace": "ci"})); got != "stats" {
+		t.Errorf("Expected calls without dryRun to run as usual, got %q", got)
+	}
+	if result := call(map[string]any{"namespace": "", "dryRun": true}); !result.IsError || getTextFromResult(result) != "namespace is required" {
+		t.Errorf("Expected an argument error to be returned as is, got %+v", result.Content)
+	}
+
+	var plan dryRunPlan
+	if err := json.Unmarshal([]byte(getTextFromResult(call(map[string]any{"namespace": "ci", "dryRun": true}))), &plan); err != nil {
+		t.Fatalf("Expected a JSON plan: %v", err)
+	}
+	if plan.Tool != "pipelinerun_stats" || plan.Arguments["namespace"] != "ci" || plan.Arguments["dryRun"] != nil || len(plan.Requests) != 0 {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+	if len(calls) != 3 {
+		t.Errorf("Expected the tool to run for every call, got %v", calls)
+	}
+}
+
+func TestWithDryRun_GroupedListing(t *testing.T) {
+	tools := withDryRun([]server.ServerTool{{
+		Tool: mcp.NewTool("taskrun_list", groupDryRunOption()),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("runs"), nil
+		},
+	}})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tools[0].Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+	if result := call(map[string]any{"dryRun": true}); !result.IsError || !strings.Contains(getTextFromResult(result), "only supported with groupBy") {
+		t.Errorf("Expected dryRun without groupBy to be rejected, got %s", getTextFromResult(result))
+	}
+	var plan dryRunPlan
+	if err := json.Unmarshal([]byte(getTextFromResult(call(map[string]any{"groupBy": "task", "dryRun": true}))), &plan); err != nil {
+		t.Fatalf("Expected a JSON plan: %v", err)
+	}
+	if plan.Tool != "taskrun_list" || plan.MaxRuns != maxGroupRuns || plan.Arguments["groupBy"] != "task" {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+}
+
+func TestDryRunDeclared(t *testing.T) {
+	tools, err := serverTools(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default"})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	for _, st := range tools {
+		_, declared := schemaProperties(t, st.Tool)["dryRun"]
+		want := toolPriority(st.Tool.Name) == priorityAnalytics || groupingTools[st.Tool.Name]
+		if declared != want {
+			t.Errorf("%s: dryRun declared = %v, want %v", st.Tool.Name, declared, want)
+		}
+	}
+}
+
+func TestPlanDryRun(t *testing.T) {
+	requests := []tektonresults.TracedRequest{
+		{Operation: "listRecords", Target: "ci/results/-", PageSize: 200, DryRun: true},
+		{Operation: "listRecords", Target: "staging/results/-", PageSize: 200, DryRun: true},
+	}
+	plan := planDryRun("pipelinerun_stats", map[string]any{"namespace": "ci,staging"}, requests)
+	// 5000 runs take 25 full pages, plus one partial page per extra listing.
+	if plan.Listings != 2 || plan.MaxRuns != tektonresults.DefaultMaxStatsRuns || plan.MaxPages != 26 {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+	if len(plan.Notes) != 1 || !strings.Contains(plan.Notes[0], "up to 26 list requests of 200 records") {
+		t.Errorf("Unexpected notes %q", plan.Notes)
+	}
+
+	plan = planDryRun("runs_since", nil, requests[:1])
+	if plan.MaxPages != 1 || plan.MaxRuns != 0 || plan.Arguments == nil {
+		t.Errorf("Expected one page for tools without a run bound, got %+v", plan)
+	}
+	plan = planDryRun("query", nil, nil)
+	if plan.Listings != 0 || len(plan.Notes) != 2 || plan.Requests == nil {
+		t.Errorf("Unexpected plan without requests %+v", plan)
+	}
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// explainPreviewLimit bounds the part of the explained tool's output that is
+// returned along with the trace.
+const explainPreviewLimit = 2000
+
+// explainPageWarning is the number of upstream requests above which the
+// explanation suggests narrowing the query.
+const explainPageWarning = 5
+
+type explainParams struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// queryExplanation is the output of query_explain.
+type queryExplanation struct {
+	Tool      string                        `json:"tool"`
+	Arguments map[string]any                `json:"arguments"`
+	Requests  []tektonresults.TracedRequest `json:"requests"`
+	Summary   explainSummary                `json:"summary"`
+	Notes     []string                      `json:"notes,omitempty"`
+	IsError   bool                          `json:"isError,omitempty"`
+	Result    string                        `json:"result"`
+	Truncated bool                          `json:"truncated,omitempty"` // result holds only the first explainPreviewLimit bytes
+}
+
+type explainSummary struct {
+	Requests int    `json:"requests"`
+	Items    int    `json:"items"` // records, results or log bytes returned upstream
+	Duration string `json:"duration"`
+}
+
+// newQueryExplainTool runs one of tools with request tracing and reports the
+// Results API requests it made. Only read-only tools can be explained.
+func newQueryExplainTool(tools []server.ServerTool) server.ServerTool {
+	handlers := map[string]server.ToolHandlerFunc{}
+	var names []string
+	for _, st := range tools {
+		if hint := st.Tool.Annotations.ReadOnlyHint; hint == nil || !*hint {
+			continue
+		}
+		handlers[st.Tool.Name] = st.Handler
+		names = append(names, st.Tool.Name)
+	}
+	slices.Sort(names)
+
+	tool := newTool(
+		"query_explain",
+		[]toolExample{
+			{"tool": "pipelinerun_list", "arguments": map[string]any{"namespace": "-", "labelSelector": "tekton.dev/pipeline=build"}},
+			{"tool": "taskrun_get", "arguments": map[string]any{"uid": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"}},
+		},
+		mcp.WithDescription("Run another read-only tool and explain the Tekton Results API requests it made: the CEL filter, parent path, ordering and page size of each request, how many items each returned, and how long it took. Use it to understand why a query is slow or returns nothing."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Query Explain")),
+		mcp.WithString("tool",
+			mcp.Required(),
+			mcp.Description("Name of the tool to explain."),
+			mcp.Enum(names...),
+		),
+		mcp.WithObject("arguments",
+			mcp.Description("Arguments for the explained tool, exactly as they would be passed to it."),
+		),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args explainParams) (*mcp.CallToolResult, error) {
+		target, ok := handlers[args.Tool]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("cannot explain %q; use one of: %s", args.Tool, strings.Join(names, ", "))), nil
+		}
+		if args.Arguments == nil {
+			args.Arguments = map[string]any{}
+		}
+
+		traced, trace := tektonresults.WithQueryTrace(ctx)
+		req := mcp.CallToolRequest{}
+		req.Params.Name = args.Tool
+		req.Params.Arguments = args.Arguments
+		start := time.Now()
+		result, err := target(traced, req)
+		if err != nil {
+			return nil, err
+		}
+
+		out := explain(args, trace.Requests(), time.Since(start), result)
+		payload, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode explanation: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// explain assembles the explanation of a traced tool call.
+func explain(args explainParams, requests []tektonresults.TracedRequest, elapsed time.Duration, result *mcp.CallToolResult) queryExplanation {
+	out := queryExplanation{
+		Tool:      args.Tool,
+		Arguments: args.Arguments,
+		Requests:  requests,
+		Summary:   explainSummary{Requests: len(requests), Duration: elapsed.Round(time.Millisecond).String()},
+	}
+	if out.Requests == nil {
+		out.Requests = []tektonresults.TracedRequest{}
+	}
+
+	listings, empty := 0, 0
+	for _, r := range requests {
+		out.Summary.Items += r.Items
+		if r.Operation == "listRecords" || r.Operation == "listResults" {
+			listings++
+			if r.Items == 0 && r.Error == "" {
+				empty++
+			}
+		}
+	}
+	switch {
+	case len(requests) == 0:
+		out.Notes = append(out.Notes, "No Results API request was made; the call was answered from input validation or a cache.")
+	case listings > 0 && empty == listings:
+		out.Notes = append(out.Notes, "Every listing came back empty: the namespace or the upstream filter matches no records. Check the namespace (use '-' for all) and the equality clauses of labelSelector.")
+	}
+	if len(requests) > explainPageWarning {
+		out.Notes = append(out.Notes, fmt.Sprintf("%d upstream requests were needed; filters that are applied locally (prefix, reason, label exclusions) do not reduce the records fetched, so add a name, uid or labelSelector equality to narrow the query.", len(requests)))
+	}
+
+	if result != nil {
+		out.IsError = result.IsError
+		var text strings.Builder
+		for _, content := range result.Content {
+			if tc, ok := content.(mcp.TextContent); ok {
+				text.WriteString(tc.Text)
+			}
+		}
+		out.Result = text.String()
+		if len(out.Result) > explainPreviewLimit {
+			cut := explainPreviewLimit
+			for cut > 0 && !utf8.RuneStart(out.Result[cut]) {
+				cut--
+			}
+			out.Result, out.Truncated = out.Result[:cut], true
+		}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestQueryExplain_RunsTool(t *testing.T) {
+	var got tektonresults.ListOptions
+	mock := &mockPipelineRunService{
+		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			got = opts
+			return []tektonresults.RunSummary{{Name: "build-1", UID: "uid-1"}}, nil
+		},
+	}
+	tools, err := serverTools(Dependencies{Service: mock, DefaultNamespace: "default", AllowWrites: true})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	explainTool := tools[slices.IndexFunc(tools, func(st server.ServerTool) bool { return st.Tool.Name == "query_explain" })]
+
+	var schema struct {
+		InputSchema struct {
+			Properties struct {
+				Tool struct {
+					Enum []string `json:"enum"`
+				} `json:"tool"`
+			} `json:"properties"`
+		} `json:"inputSchema"`
+	}
+	payload, _ := json.Marshal(explainTool.Tool)
+	if err := json.Unmarshal(payload, &schema); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	names := schema.InputSchema.Properties.Tool.Enum
+	if !slices.Contains(names, "pipelinerun_list") || slices.Contains(names, "results_prune") || slices.Contains(names, "query_explain") {
+		t.Errorf("Expected only other read-only tools to be explainable, got %v", names)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"tool": "pipelinerun_list", "arguments": map[string]any{"namespace": "ci", "prefix": "build"}}
+	result, err := explainTool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+	if got.Namespace != "ci" || got.Prefix != "build" {
+		t.Errorf("Expected arguments to reach the explained tool, got %+v", got)
+	}
+
+	var out queryExplanation
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &out); err != nil {
+		t.Fatalf("decode explanation: %v", err)
+	}
+	if out.Tool != "pipelinerun_list" || !strings.Contains(out.Result, "build-1") || out.Summary.Requests != 0 {
+		t.Errorf("Unexpected explanation: %+v", out)
+	}
+	if len(out.Notes) != 1 || !strings.Contains(out.Notes[0], "No Results API request") {
+		t.Errorf("Expected a note about the missing requests, got %v", out.Notes)
+	}
+}
+
+func TestQueryExplain_RejectsUnknownTools(t *testing.T) {
+	tools, _ := serverTools(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default", AllowWrites: true})
+	explainTool := tools[slices.IndexFunc(tools, func(st server.ServerTool) bool { return st.Tool.Name == "query_explain" })]
+
+	for _, name := range []string{"results_prune", "query_explain", "nope"} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"tool": name}
+		result, err := explainTool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		if !result.IsError || !strings.Contains(getTextFromResult(result), "cannot explain") {
+			t.Errorf("Expected %s to be rejected, got %s", name, getTextFromResult(result))
+		}
+	}
+}
+
+func TestExplain_Notes(t *testing.T) {
+	empty := []tektonresults.TracedRequest{
+		{Operation: "listRecords", Target: "ci/results/-", Filter: `data_type in ["tekton.dev/v1.PipelineRun"]`},
+	}
+	out := explain(explainParams{Tool: "pipelinerun_list"}, empty, time.Second, mcp.NewToolResultText("[]"))
+	if len(out.Notes) != 1 || !strings.Contains(out.Notes[0], "came back empty") {
+		t.Errorf("Expected an empty listing note, got %v", out.Notes)
+	}
+
+	var many []tektonresults.TracedRequest
+	for range explainPageWarning + 1 {
+		many = append(many, tektonresults.TracedRequest{Operation: "listRecords", Items: 50})
+	}
+	out = explain(explainParams{Tool: "pipelinerun_get"}, many, time.Second, mcp.NewToolResultText(strings.Repeat("é", explainPreviewLimit)))
+	if out.Summary.Items != 50*len(many) || len(out.Notes) != 1 || !strings.Contains(out.Notes[0], "upstream requests were needed") {
+		t.Errorf("Unexpected explanation of a long scan: %+v", out.Summary)
+	}
+	if !out.Truncated || len(out.Result) > explainPreviewLimit || !strings.HasSuffix(out.Result, "é") {
+		t.Errorf("Expected the result preview to be cut on a rune boundary, got %d bytes", len(out.Result))
+	}
+}
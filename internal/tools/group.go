@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// maxGroupRuns bounds the runs read for one grouped listing; counts of a
+// truncated listing are incomplete.
+const maxGroupRuns = 2000
+
+// noGroupKey is the group of runs without a value for the grouping, such as
+// runs missing the grouped label.
+const noGroupKey = "(none)"
+
+func groupByOption(kind string) mcp.ToolOption {
+	owner := "pipeline"
+	if kind == "TaskRun" {
+		owner = "task, pipeline"
+	}
+	return mcp.WithString("groupBy",
+		mcp.Description(fmt.Sprintf("Instead of a list of runs, return the number of matching runs per group with their outcomes and the most recent run of each group, largest groups first. Group by %s, namespace, status, or label:<key> for any label. Up to %d runs are read, following pages; limit caps the number of groups and pageToken is not supported.", owner, maxGroupRuns)),
+		mcp.DefaultString(""),
+		examples(strings.Split(owner, ", ")[0], "status", "label:app"),
+	)
+}
+
+// runGroup is one group of a grouped listing.
+type runGroup struct {
+	Key      string                   `json:"key"`
+	Count    int                      `json:"count"`
+	Statuses map[string]int           `json:"statuses"` // runs per outcome; "unknown" for runs that reported none
+	Latest   tektonresults.RunSummary `json:"latest"`
+}
+
+// groupedRuns is the output of a list tool called with groupBy.
+type groupedRuns struct {
+	GroupBy   string     `json:"groupBy"`
+	Runs      int        `json:"runs"`
+	Truncated bool       `json:"truncated,omitempty"` // more than maxGroupRuns runs matched; counts are incomplete
+	Groups    []runGroup `json:"groups"`
+	Omitted   int        `json:"omittedGroups,omitempty"` // smaller groups left out by limit
+}
+
+// groupKey returns the function that files a run of kind under a group for
+// the groupBy argument.
+func groupKey(kind, groupBy string) (func(tektonresults.RunSummary) string, error) {
+	switch by := strings.TrimSpace(groupBy); {
+	case by == "namespace":
+		return func(run tektonresults.RunSummary) string { return run.Namespace }, nil
+	case by == "status":
+		return func(run tektonresults.RunSummary) string { return cmp.Or(run.Outcome(), "unknown") }, nil
+	case by == "pipeline" && kind == "PipelineRun", by == "task" && kind == "TaskRun":
+		// The Pipeline or Task the run references, read from its spec
+		// before its label.
+		return func(run tektonresults.RunSummary) string { return run.Ref }, nil
+	case by == "pipeline":
+		return func(run tektonresults.RunSummary) string { return run.Labels["tekton.dev/pipeline"] }, nil
+	case strings.HasPrefix(by, "label:") && strings.TrimSpace(by[len("label:"):]) != "":
+		key := strings.TrimSpace(by[len("label:"):])
+		return func(run tektonresults.RunSummary) string { return run.Labels[key] }, nil
+	}
+	valid := "pipeline, namespace, status or label:<key>"
+	if kind == "TaskRun" {
+		valid = "task, " + valid
+	}
+	return nil, fmt.Errorf("invalid groupBy %q: expected %s", groupBy, valid)
+}
+
+// groupRuns reads every run matching opts and counts them per group.
+func groupRuns(ctx context.Context, list pageLister, opts tektonresults.ListOptions, groupBy string, key func(tektonresults.RunSummary) string) (*groupedRuns, error) {
+	out := &groupedRuns{GroupBy: groupBy}
+	index := map[string]int{}
+	for run, err := range eachRun(ctx, list, opts) {
+		if err != nil {
+			return nil, err
+		}
+		if out.Runs == maxGroupRuns {
+			out.Truncated = true
+			break
+		}
+		out.Runs++
+		k := cmp.Or(key(run), noGroupKey)
+		i, ok := index[k]
+		if !ok {
+			i, index[k] = len(out.Groups), len(out.Groups)
+			out.Groups = append(out.Groups, runGroup{Key: k, Statuses: map[string]int{}, Latest: run})
+		}
+		g := &out.Groups[i]
+		g.Count++
+		g.Statuses[cmp.Or(run.Outcome(), "unknown")]++
+		if startedLater(run, g.Latest) {
+			g.Latest = run
+		}
+	}
+	// Runs without a value go last among groups of the same size.
+	slices.SortStableFunc(out.Groups, func(a, b runGroup) int {
+		return cmp.Or(b.Count-a.Count, compareBool(a.Key == noGroupKey, b.Key == noGroupKey), strings.Compare(a.Key, b.Key))
+	})
+	return out, nil
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// startedLater reports whether a started after b. Runs that have not started
+// count as the most recent.
+func startedLater(a, b tektonresults.RunSummary) bool {
+	switch {
+	case b.StartTime == nil:
+		return false
+	case a.StartTime == nil:
+		return true
+	}
+	return a.StartTime.After(b.StartTime.Time)
+}
+
+// groupResult renders a grouped listing for a list tool called with groupBy.
+func groupResult(ctx context.Context, req mcp.CallToolRequest, args listParams, kind string, list pageLister, opts tektonresults.ListOptions) *mcp.CallToolResult {
+	if args.PageToken != "" {
+		return mcp.NewToolResultError("pageToken is not supported with groupBy; every matching run is read at once")
+	}
+	key, err := groupKey(kind, args.GroupBy)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	grouped, err := groupRuns(ctx, list, opts, strings.TrimSpace(args.GroupBy), key)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	if len(grouped.Groups) > opts.Limit {
+		grouped.Omitted = len(grouped.Groups) - opts.Limit
+		grouped.Groups = grouped.Groups[:opts.Limit]
+	}
+	include := req.GetBool("includeLabels", true)
+	for i := range grouped.Groups {
+		latest := []tektonresults.RunSummary{grouped.Groups[i].Latest}
+		projectLabels(latest, include, args.LabelKeys)
+		grouped.Groups[i].Latest = latest[0]
+	}
+	payload, err := json.MarshalIndent(grouped, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err))
+	}
+	return mcp.NewToolResultText(string(payload))
+}
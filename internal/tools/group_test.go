@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunList_GroupBy(t *testing.T) {
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	run := func(name, pipeline string, hour int, status string) tektonresults.RunSummary {
+		start := metav1.NewTime(base.Add(time.Duration(hour) * time.Hour))
+		return tektonresults.RunSummary{Name: name, Namespace: "ci", Ref: pipeline, Labels: map[string]string{"app": "web"}, StartTime: &start, Status: status}
+	}
+	mock := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			if opts.PageToken == "" {
+				return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+					run("build-1", "build", 1, "True"),
+					run("deploy-1", "deploy", 2, "False"),
+				}, NextPageToken: "next"}, nil
+			}
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+				run("build-2", "build", 3, "False"),
+				run("adhoc", "", 0, "True"),
+			}}, nil
+		},
+	}
+	tool := newPipelineRunListTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"groupBy": "pipeline", "limit": 2, "includeLabels": false}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", getTextFromResult(result))
+	}
+	var grouped groupedRuns
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &grouped); err != nil {
+		t.Fatalf("Failed to decode groups: %v", err)
+	}
+	if grouped.Runs != 4 || grouped.Omitted != 1 || len(grouped.Groups) != 2 {
+		t.Fatalf("Expected 4 runs in 2 of 3 groups, got %+v", grouped)
+	}
+	build := grouped.Groups[0]
+	if build.Key != "build" || build.Count != 2 || build.Latest.Name != "build-2" || build.Latest.Labels != nil {
+		t.Errorf("Unexpected build group %+v", build)
+	}
+	if build.Statuses["succeeded"] != 1 || build.Statuses["failed"] != 1 {
+		t.Errorf("Unexpected build outcomes %v", build.Statuses)
+	}
+	// Groups of one run are ordered by key with runs missing the label
+	// last, so those are left out by the limit.
+	if grouped.Groups[1].Key != "deploy" {
+		t.Errorf("Expected deploy second, got %+v", grouped.Groups[1])
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	// Ref is resolved from the spec before the label, which may disagree.
+	run := tektonresults.RunSummary{Namespace: "ci", Ref: "lint", Labels: map[string]string{"tekton.dev/task": "lint-v1", "tekton.dev/pipeline": "build", "app": "web"}}
+	for _, tt := range []struct {
+		kind, groupBy, want string
+	}{
+		{"TaskRun", "task", "lint"},
+		{"TaskRun", "pipeline", "build"},
+		{"PipelineRun", "pipeline", "lint"},
+		{"PipelineRun", "namespace", "ci"},
+		{"PipelineRun", "status", "unknown"},
+		{"PipelineRun", "label:app", "web"},
+	} {
+		key, err := groupKey(tt.kind, tt.groupBy)
+		if err != nil {
+			t.Errorf("groupKey(%q, %q) error = %v", tt.kind, tt.groupBy, err)
+			continue
+		}
+		if got := key(run); got != tt.want {
+			t.Errorf("groupKey(%q, %q) = %q, want %q", tt.kind, tt.groupBy, got, tt.want)
+		}
+	}
+	for _, groupBy := range []string{"task", "label:", "owner"} {
+		if _, err := groupKey("PipelineRun", groupBy); err == nil {
+			t.Errorf("Expected groupBy %q to be rejected for PipelineRuns", groupBy)
+		}
+	}
+}
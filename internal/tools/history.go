@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const defaultHistoryLimit = 10
+
+type historyParams struct {
+	Namespace string `json:"namespace"`
+	Pipeline  string `json:"pipeline"`
+	Task      string `json:"task"`
+	Team      string `json:"team"`
+	Limit     int    `json:"limit"`
+
+	CreatedAfter  string `json:"createdAfter"`
+	CreatedBefore string `json:"createdBefore"`
+	Sample        int    `json:"sample"`
+	Buckets       int    `json:"buckets"`
+}
+
+func newRunHistoryTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Show the most recent runs of a Pipeline or Task as a compact table (start time, status, duration, run name, UID). Use it for trend questions such as 'has the nightly build been failing lately?'. Set sample to report the failure rate over a long window instead, reading a bounded number of runs per time bucket."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Run History")),
+		mcp.WithString("pipeline",
+			mcp.Description("Pipeline name (matches the tekton.dev/pipeline label). Provide either pipeline or task."),
+			mcp.DefaultString(""),
+		),
+		mcp.WithString("task",
+			mcp.Description("Task name (matches the tekton.dev/task label). Provide either pipeline or task."),
+			mcp.DefaultString(""),
+		),
+		namespacesOption(namespaceDefault),
+		teamOption(),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of most recent runs to show (1-200)."),
+			mcp.DefaultNumber(defaultHistoryLimit),
+			mcp.Min(1),
+			mcp.Max(maxListLimit),
+		),
+		mcp.WithNumber("sample",
+			mcp.Description("Report the failure rate and median duration per time bucket instead of listing runs, reading at most this many of the newest runs of each bucket (1-200). Buckets holding more runs are estimated with a 95% confidence interval, so the call stays fast on namespaces with a very long history. The window defaults to the last 7 days."),
+			mcp.Min(1),
+			mcp.Max(maxListLimit),
+		),
+		mcp.WithNumber("buckets",
+			mcp.Description("Number of equal time buckets the window is split into when sampling."),
+			mcp.DefaultNumber(defaultSampleBuckets),
+			mcp.Min(1),
+			mcp.Max(maxSampleBuckets),
+		),
+	}
+	opts = append(opts, createdRangeOptions()...)
+
+	tool := newTool(
+		"run_history",
+		[]toolExample{
+			{"pipeline": "build-pipeline", "namespace": namespaceDefault},
+			{"task": "unit-tests", "namespace": namespaceDefault, "limit": 20},
+			{"pipeline": "nightly", "namespace": "-", "createdAfter": "30d", "buckets": 30, "sample": 50},
+		},
+		opts...,
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args historyParams) (*mcp.CallToolResult, error) {
+		pipeline := strings.TrimSpace(args.Pipeline)
+		task := strings.TrimSpace(args.Task)
+		if (pipeline == "") == (task == "") {
+			return mcp.NewToolResultError("provide exactly one of pipeline or task"), nil
+		}
+
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultHistoryLimit
+		}
+		now := time.Now()
+		createdAfter, createdBefore, err := parseCreatedRange(args.CreatedAfter, args.CreatedBefore, now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts := tektonresults.ListOptions{
+			Namespace:     normalizeNamespace(args.Namespace, namespaceDefault),
+			Team:          args.Team,
+			CreatedAfter:  createdAfter,
+			CreatedBefore: createdBefore,
+			Limit:         sanitizeLimit(limit),
+		}
+
+		var (
+			list    = deps.Service.ListPipelineRuns
+			subject string
+		)
+		if pipeline != "" {
+			subject = fmt.Sprintf("Pipeline %s", pipeline)
+			opts.LabelSelector = fmt.Sprintf("tekton.dev/pipeline=%s", pipeline)
+		} else {
+			list = deps.Service.ListTaskRuns
+			subject = fmt.Sprintf("Task %s", task)
+			opts.LabelSelector = fmt.Sprintf("tekton.dev/task=%s", task)
+		}
+		if team := strings.TrimSpace(args.Team); team != "" {
+			subject += fmt.Sprintf(" owned by team %s", team)
+		}
+
+		if args.Sample > 0 {
+			to := cmp.Or(createdBefore, now)
+			from := cmp.Or(createdAfter, to.Add(-defaultSampleWindow))
+			if !from.Before(to) {
+				return mcp.NewToolResultError(fmt.Sprintf("the sampling window must end after it starts, got %s to %s", format.Timestamp(from), format.Timestamp(to))), nil
+			}
+			buckets := args.Buckets
+			if buckets <= 0 {
+				buckets = defaultSampleBuckets
+			}
+			perBucket := sanitizeLimit(args.Sample)
+			sample, err := sampleRuns(ctx, list, opts, from, to, min(buckets, maxSampleBuckets), perBucket)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return mcp.NewToolResultText(renderSample(subject, sample, perBucket)), nil
+		}
+
+		summaries, err := list(ctx, opts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(summaries) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No runs found for %s in namespace %s", subject, opts.Namespace)), nil
+		}
+
+		return mcp.NewToolResultText(renderHistory(subject, summaries)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// renderHistory formats run summaries as a fixed-width table, newest first.
+func renderHistory(subject string, summaries []tektonresults.RunSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Last %d run(s) of %s\n\n", len(summaries), subject)
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tSTATUS\tDURATION\tNAME\tUID")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			format.Timestamp(timeOf(s.StartTime)), runState(s),
+			runDuration(s), s.Name, s.UID)
+	}
+	_ = w.Flush()
+	b.WriteString(renderDurations(summaries))
+	return b.String()
+}
+
+// histogramBuckets and histogramWidth size the duration histogram of
+// run_history.
+const (
+	histogramBuckets = 5
+	histogramWidth   = 20
+)
+
+// renderDurations summarizes the durations of completed runs as a sparkline,
+// oldest first, and a histogram. Runs with skewed timestamps are left out
+// rather than counted as taking no time. Fewer than two usable durations
+// yield nothing.
+func renderDurations(summaries []tektonresults.RunSummary) string {
+	var durations []time.Duration
+	skewed := 0
+	for i := len(summaries) - 1; i >= 0; i-- {
+		s := summaries[i]
+		d, ok := s.Duration()
+		switch {
+		case !ok:
+			continue
+		case s.ClockSkew:
+			skewed++
+			continue
+		}
+		durations = append(durations, d)
+	}
+	if len(durations) < 2 {
+		return skewNote(skewed)
+	}
+
+	sorted := slices.Sorted(slices.Values(durations))
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nDurations of %d completed run(s), oldest to newest: %s\n", len(durations), format.Sparkline(durations))
+	fmt.Fprintf(&b, "min %s, median %s, max %s\n\n", format.Duration(sorted[0]), format.Duration(sorted[len(sorted)/2]), format.Duration(sorted[len(sorted)-1]))
+	b.WriteString(format.Histogram(durations, histogramBuckets, histogramWidth))
+	b.WriteString(skewNote(skewed))
+	return b.String()
+}
+
+// skewNote explains why runs with skewed timestamps are missing from the
+// duration statistics.
+func skewNote(skewed int) string {
+	if skewed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\nWarning: %d run(s) completed before they started according to their timestamps; the clocks that stamped them disagree, so their durations are left out.\n", skewed)
+}
+
+// incompleteState labels runs whose record was stored before they reported
+// any status.
+const incompleteState = "Unknown (incomplete record)"
+
+// skewedDuration is shown in place of durations whose end precedes their
+// start.
+const skewedDuration = "unknown (clock skew)"
+
+// runDuration renders how long a run took.
+func runDuration(s tektonresults.RunSummary) string {
+	if s.ClockSkew {
+		return skewedDuration
+	}
+	return format.Elapsed(timeOf(s.StartTime), timeOf(s.CompletionTime))
+}
+
+// runState returns a short human readable state for a run summary.
+func runState(s tektonresults.RunSummary) string {
+	switch {
+	case s.Incomplete:
+		return incompleteState
+	case s.Reason != "":
+		return s.Reason
+	case s.CompletionTime == nil && s.StartTime != nil:
+		return "Running"
+	case s.Status != "":
+		return s.Status
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,154 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunHistory_Pipeline(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(time.Date(2024, 1, 1, 10, 4, 30, 0, time.UTC))
+	mock := &mockPipelineRunService{
+		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			if opts.LabelSelector != "tekton.dev/pipeline=build" {
+				t.Errorf("Expected pipeline label selector, got %s", opts.LabelSelector)
+			}
+			if opts.Limit != defaultHistoryLimit {
+				t.Errorf("Expected default limit %d, got %d", defaultHistoryLimit, opts.Limit)
+			}
+			return []tektonresults.RunSummary{
+				{Name: "build-abc", UID: "uid-1", Reason: "Failed", StartTime: &start, CompletionTime: &end},
+				{Name: "build-def", UID: "uid-2", StartTime: &start},
+			}, nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "default"}
+	tool := newRunHistoryTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"pipeline": "build"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+
+	text := getTextFromResult(result)
+	for _, want := range []string{"Last 2 run(s) of Pipeline build", "STATUS", "Failed", "4m 30s", "uid-1", "Running", "2024-01-01T10:00:00Z"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected history to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderHistory_Durations(t *testing.T) {
+	at := func(min, sec int) *metav1.Time {
+		tm := metav1.NewTime(time.Date(2024, 1, 1, 10, min, sec, 0, time.UTC))
+		return &tm
+	}
+	summaries := []tektonresults.RunSummary{
+		{Name: "build-4", StartTime: at(0, 0)}, // still running
+		{Name: "build-3", StartTime: at(0, 0), CompletionTime: at(8, 0)},
+		{Name: "build-2", StartTime: at(0, 0), CompletionTime: at(4, 30)},
+		{Name: "build-1", StartTime: at(0, 0), CompletionTime: at(1, 0)},
+	}
+
+	text := renderHistory("Pipeline build", summaries)
+	for _, want := range []string{"Durations of 3 completed run(s), oldest to newest: ▁▄█", "min 1m, median 4m 30s, max 8m", "1m - 3m 20s"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected history to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	if text := renderHistory("Pipeline build", summaries[:2]); strings.Contains(text, "Durations") {
+		t.Errorf("Expected no duration summary for a single completed run, got:\n%s", text)
+	}
+
+	// A run whose clocks disagree must not drag the minimum to zero.
+	skewed := append([]tektonresults.RunSummary{{Name: "build-5", StartTime: at(2, 0), CompletionTime: at(0, 0), ClockSkew: true}}, summaries...)
+	text = renderHistory("Pipeline build", skewed)
+	for _, want := range []string{"unknown (clock skew)", "Durations of 3 completed run(s)", "min 1m, median 4m 30s, max 8m", "Warning: 1 run(s) completed before they started"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected skewed history to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestRunHistory_Team(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			if opts.Team != "Payments" || opts.LabelSelector != "tekton.dev/pipeline=build" {
+				t.Errorf("Expected the team and pipeline filters, got %+v", opts)
+			}
+			return nil, nil
+		},
+	}
+
+	tool := newRunHistoryTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"pipeline": "build", "team": "Payments"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if text := getTextFromResult(result); !strings.Contains(text, "No runs found for Pipeline build owned by team Payments") {
+		t.Errorf("Unexpected result: %s", text)
+	}
+}
+
+func TestRunHistory_Task(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			if opts.LabelSelector != "tekton.dev/task=unit-tests" {
+				t.Errorf("Expected task label selector, got %s", opts.LabelSelector)
+			}
+			return nil, nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "default"}
+	tool := newRunHistoryTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"task": "unit-tests"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if !strings.Contains(getTextFromResult(result), "No runs found for Task unit-tests") {
+		t.Errorf("Unexpected response: %s", getTextFromResult(result))
+	}
+}
+
+func TestRunHistory_RequiresExactlyOneSubject(t *testing.T) {
+	deps := Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default"}
+	tool := newRunHistoryTool(deps)
+
+	for _, args := range []map[string]any{
+		{},
+		{"pipeline": "build", "task": "unit-tests"},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected error result for args %v", args)
+		}
+	}
+}
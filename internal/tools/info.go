@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type serverInfoParams struct {
+	Refresh bool `json:"refresh"`
+}
+
+func newServerInfoTool(svc ServerInspector) server.ServerTool {
+	tool := newTool(
+		"server_info",
+		[]toolExample{{}, {"refresh": true}},
+		mcp.WithDescription("Describe the Tekton Results endpoint this server uses: API URL and version, the authenticated identity when discoverable, how many namespaces have stored results, and any connectivity error. Use it to diagnose empty or failing queries."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Server Info")),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Probe the Results API again instead of returning the result of the last probe."),
+			mcp.DefaultBool(false),
+		),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args serverInfoParams) (*mcp.CallToolResult, error) {
+		info := svc.ServerInfo(ctx, args.Refresh)
+		payload, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode server info: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// inspectorFunc adapts a function to ServerInspector.
+type inspectorFunc func(ctx context.Context, refresh bool) tektonresults.ServerInfo
+
+func (f inspectorFunc) ServerInfo(ctx context.Context, refresh bool) tektonresults.ServerInfo {
+	return f(ctx, refresh)
+}
+
+func TestServerInfo(t *testing.T) {
+	var gotRefresh bool
+	tool := newServerInfoTool(inspectorFunc(func(ctx context.Context, refresh bool) tektonresults.ServerInfo {
+		gotRefresh = refresh
+		return tektonresults.ServerInfo{
+			Endpoint:   "https://results.example.com",
+			Identity:   "system:serviceaccount:tekton:mcp",
+			Namespaces: 4,
+		}
+	}))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"refresh": true}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+	if !gotRefresh {
+		t.Error("Expected refresh to be passed to the service")
+	}
+
+	var info tektonresults.ServerInfo
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &info); err != nil {
+		t.Fatalf("Response is not JSON: %v", err)
+	}
+	if info.Namespaces != 4 || info.Identity != "system:serviceaccount:tekton:mcp" {
+		t.Errorf("Unexpected server info: %+v", info)
+	}
+}
+
+func TestBackendInfoTool(t *testing.T) {
+	var gotNamespace string
+	svc := &mockPipelineRunService{backendInfoFunc: func(ctx context.Context, namespace string) tektonresults.BackendInfo {
+		gotNamespace = namespace
+		return tektonresults.BackendInfo{
+			Namespace: namespace,
+			Version:   "v0.14.0",
+			Logs:      &tektonresults.LogStorage{Enabled: true, Type: "File", Location: "/logs"},
+		}
+	}}
+	tool := newBackendInfoTool(svc)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"namespace": "tekton-results"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+	if gotNamespace != "tekton-results" {
+		t.Errorf("Expected namespace tekton-results, got %q", gotNamespace)
+	}
+
+	var info tektonresults.BackendInfo
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &info); err != nil {
+		t.Fatalf("Response is not JSON: %v", err)
+	}
+	if info.Version != "v0.14.0" || info.Logs == nil || info.Logs.Location != "/logs" {
+		t.Errorf("Unexpected backend info: %+v", info)
+	}
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// instructionExamples pairs common questions with the calls that answer them.
+var instructionExamples = []struct{ question, call string }{
+	{"Has the nightly pipeline been failing lately?", `run_history {"pipeline": "nightly"}`},
+	{"Why did the latest build fail?", `pipelinerun_get {"labelSelector": "tekton.dev/pipeline=build", "depth": "status", "includeSummary": true}, then taskrun_logs for the failed TaskRun`},
+	{"Which runs timed out in any namespace?", `pipelinerun_list {"namespace": "-", "status": "timedout"}`},
+	{"What failed in the last 24 hours?", `pipelinerun_list {"createdAfter": "24h", "status": "failed"}`},
+	{"Chart the daily failure rate of the build pipeline", `failure_rate_series {"pipeline": "build"}`},
+	{"How is every pipeline doing today?", `pipelinerun_list {"namespace": "-", "createdAfter": "24h", "groupBy": "pipeline"}`},
+	{"How reliable and how fast was the build pipeline this month?", `pipelinerun_stats {"pipeline": "build", "createdAfter": "30d"}`},
+	{"Which task slows down or breaks the build pipeline most?", `taskrun_stats {"pipeline": "build", "createdAfter": "30d"}`},
+	{"What should I report at standup?", `failures_digest {"namespace": "-"}`},
+	{"Why do builds run out of disk space?", `workspace_usage {"pipeline": "build", "kind": "taskrun"}`},
+	{"Which PipelineRuns took longer than 30 minutes this week?", `pipelinerun_list {"createdAfter": "7d", "minDurationSeconds": 1800}`},
+	{"What should we parallelize in the build pipeline?", `pipelinerun_critical_path {"labelSelector": "tekton.dev/pipeline=build"}`},
+	{"When did each task of that run start and finish?", `pipelinerun_timeline {"name": "build-x7k2p"}`},
+	{"Which image digest did the last build produce?", `pipelinerun_results {"labelSelector": "tekton.dev/pipeline=build", "names": ["IMAGE_DIGEST"]}`},
+	{"What params did that run use?", `pipelinerun_params {"name": "build-x7k2p"}`},
+	{"Which commit did the clone task check out?", `taskrun_results {"labelSelector": "tekton.dev/pipelineTask=clone", "names": ["commit"]}`},
+	{"Which step regressed in the latest build?", `pipelinerun_diff {"labelSelector": "tekton.dev/pipeline=build"}`},
+	{"What ran since I last checked?", `runs_since {"kind": "pipelinerun"} and pass the returned cursor next time`},
+	{"Why are queries empty or failing?", `server_info {"refresh": true}`},
+	{"Why are logs or old runs missing?", `backend_info {}`},
+	{"Which tools are failing most often?", `server_stats {}`},
+	{"Whose runs are queried most?", `usage_report {}`},
+	{"Why did the last builds fail, with their logs?", `query {"request": {"filters": {"pipeline": "build", "status": "failed"}, "limit": 3, "taskRuns": {"failedOnly": true}, "logs": {}}}`},
+	{"Why is this query slow or empty?", `query_explain {"tool": "pipelinerun_list", "arguments": {"namespace": "ci"}}`},
+}
+
+// Instructions returns the server instructions sent to clients on
+// initialize: the tools Add registers for deps, the defaults they apply and
+// example queries, so a model can pick the right tool without trial calls.
+func Instructions(deps Dependencies) (string, error) {
+	defs, err := Definitions(deps)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("This server answers questions about past Tekton PipelineRuns and TaskRuns stored in Tekton Results, including runs whose resources were already deleted from the cluster.\n\n")
+
+	b.WriteString("Tools:\n")
+	for _, def := range defs {
+		title := def.Annotations.Title
+		if title == "" {
+			title = def.Description
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", def.Name, title)
+	}
+	if !deps.AllowWrites {
+		b.WriteString("Write tools are disabled; this server cannot modify stored data.\n")
+	}
+
+	b.WriteString("\nConventions:\n")
+	fmt.Fprintf(&b, "- The default namespace is %q. Pass namespace '-' to search all namespaces; list tools also accept a comma separated list.\n", deps.DefaultNamespace)
+	fmt.Fprintf(&b, "- List tools return %d runs by default and at most %d, newest first. Narrow with labelSelector (e.g. tekton.dev/pipeline=build), prefix or reason rather than raising limit.\n", defaultListLimit, maxListLimit)
+	b.WriteString("- Run names are not unique in history. Get tools return the most recent match unless selectLast is false; a uid identifies one run exactly and is the fastest lookup.\n")
+	b.WriteString("- Searches for a single run scan a bounded number of pages and fail with a request to narrow the query when the limit is reached.\n")
+	b.WriteString("- Manifests are large: prefer depth 'status' or 'spec', and labelKeys on list tools, when the full object is not needed.\n")
+
+	b.WriteString("\nExamples:\n")
+	for _, ex := range instructionExamples {
+		fmt.Fprintf(&b, "- %s %s\n", ex.question, ex.call)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstructions(t *testing.T) {
+	deps := Dependencies{DefaultNamespace: "ci"}
+	got, err := Instructions(deps)
+	if err != nil {
+		t.Fatalf("Instructions() error = %v", err)
+	}
+
+	defs, _ := Definitions(deps)
+	for _, def := range defs {
+		if !strings.Contains(got, "- "+def.Name+": ") {
+			t.Errorf("Expected tool %s in instructions", def.Name)
+		}
+	}
+	for _, want := range []string{`default namespace is "ci"`, "Write tools are disabled", "at most 200"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in instructions:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "results_prune") {
+		t.Error("Instructions must not mention write tools that are not registered")
+	}
+
+	withWrites, _ := Instructions(Dependencies{DefaultNamespace: "ci", AllowWrites: true})
+	if !strings.Contains(withWrites, "- results_prune: ") || strings.Contains(withWrites, "Write tools are disabled") {
+		t.Errorf("Expected write tools to be listed when enabled:\n%s", withWrites)
+	}
+}
+
+func TestInstructionExamples_UseRegisteredTools(t *testing.T) {
+	defs, _ := Definitions(Dependencies{DefaultNamespace: "default"})
+	names := map[string]bool{}
+	for _, def := range defs {
+		names[def.Name] = true
+	}
+	for _, ex := range instructionExamples {
+		tool, _, _ := strings.Cut(ex.call, " ")
+		if !names[tool] {
+			t.Errorf("Example %q calls unknown tool %s", ex.question, tool)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listFormats lists the output modes accepted by the list tools.
+var listFormats = []string{"json", "table", "markdown", "csv"}
+
+func listOutputOption() mcp.ToolOption {
+	return mcp.WithString("output",
+		mcp.Description("Return format: 'json' (default) for the full run summaries, 'table' for an aligned plain text table or 'markdown' for a Markdown table, both with only name, namespace, status, duration and start time per run, or 'csv' for spreadsheets and scripts, with a header row and a fixed set of columns followed by one column per labelKeys entry. groupBy listings are always JSON."),
+		mcp.DefaultString("json"),
+		mcp.Enum(listFormats...),
+	)
+}
+
+// validateListOutput checks the output argument of a list tool.
+func validateListOutput(args listParams) error {
+	output := strings.TrimSpace(args.Output)
+	if output != "" && !slices.Contains(listFormats, output) {
+		return fmt.Errorf("invalid output %q: expected %s", args.Output, strings.Join(listFormats, ", "))
+	}
+	if args.GroupBy != "" && output != "" && output != "json" {
+		return fmt.Errorf("output %q is not supported with groupBy; grouped listings are JSON", output)
+	}
+	return nil
+}
+
+// runTableHeader is the header of the table and markdown list outputs.
+var runTableHeader = []string{"NAME", "NAMESPACE", "STATUS", "DURATION", "STARTED"}
+
+func runTableRow(run tektonresults.RunSummary) []string {
+	return []string{run.Name, run.Namespace, runState(run), runDuration(run), format.Timestamp(timeOf(run.StartTime))}
+}
+
+// renderRunTable renders runs as a plain text table with aligned columns.
+func renderRunTable(runs []tektonresults.RunSummary) string {
+	if len(runs) == 0 {
+		return "No runs found."
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(runTableHeader, "\t"))
+	for _, run := range runs {
+		fmt.Fprintln(w, strings.Join(runTableRow(run), "\t"))
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderRunMarkdown renders runs as a Markdown table.
+func renderRunMarkdown(runs []tektonresults.RunSummary) string {
+	if len(runs) == 0 {
+		return "No runs found."
+	}
+	var b strings.Builder
+	header := make([]string, len(runTableHeader))
+	for i, h := range runTableHeader {
+		header[i] = strings.ToUpper(h[:1]) + strings.ToLower(h[1:])
+	}
+	writeMarkdownRow(&b, header)
+	writeMarkdownRow(&b, slices.Repeat([]string{"---"}, len(header)))
+	for _, run := range runs {
+		writeMarkdownRow(&b, runTableRow(run))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		// A pipe would end the cell early; names and reasons never span lines.
+		fmt.Fprintf(b, " %s |", strings.ReplaceAll(cell, "|", `\|`))
+	}
+	b.WriteString("\n")
+}
+
+// runCSVHeader is the fixed column set of the csv list output. Columns are
+// only ever appended, so scripts may address them by position.
+var runCSVHeader = []string{"name", "namespace", "uid", "pipelineTask", "outcome", "status", "reason", "startTime", "completionTime", "durationSeconds", "team", "recordName", "message"}
+
+// renderRunCSV renders runs as CSV with a header row, quoting values as RFC
+// 4180 requires. Each of labelKeys adds a label:<key> column. Times are RFC
+// 3339 in UTC; unknown values are left empty.
+func renderRunCSV(runs []tektonresults.RunSummary, labelKeys []string) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	header := slices.Clone(runCSVHeader)
+	for _, key := range labelKeys {
+		header = append(header, "label:"+key)
+	}
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	for _, run := range runs {
+		duration := ""
+		if d, ok := run.Duration(); ok && !run.ClockSkew {
+			duration = strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+		}
+		row := []string{run.Name, run.Namespace, run.UID, run.PipelineTask, run.Outcome(), run.Status, run.Reason,
+			csvTime(run.StartTime), csvTime(run.CompletionTime), duration, run.Team, run.RecordName, run.Message}
+		for _, key := range labelKeys {
+			row = append(row, run.Labels[key])
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return b.String(), w.Error()
+}
+
+func csvTime(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
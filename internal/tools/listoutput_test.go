@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunList_Output(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+	mock := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+				{Name: "build-1", Namespace: "ci", Status: "True", Reason: "Succeeded", StartTime: &start, CompletionTime: &end, Labels: map[string]string{"app": "web"}},
+				{Name: "build-2", Namespace: "ci", Reason: "a|b"},
+				{Name: "build-3", Namespace: "ci", Message: `step "build", exited 1`},
+			}, NextPageToken: "next"}, nil
+		},
+	}
+	tool := newPipelineRunListTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"output": "table"})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", getTextFromResult(result))
+	}
+	table := getTextFromResult(result)
+	lines := strings.Split(table, "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "NAME") || !strings.Contains(lines[1], "1m 30s") || !strings.Contains(lines[1], "2025-03-01T10:00:00Z") {
+		t.Errorf("Unexpected table:\n%s", table)
+	}
+	if strings.Contains(table, "app") {
+		t.Errorf("Expected labels left out of the table:\n%s", table)
+	}
+	if len(result.Content) != 2 {
+		t.Errorf("Expected the page token note to be kept, got %+v", result.Content)
+	}
+
+	markdown := getTextFromResult(call(map[string]any{"output": "markdown"}))
+	want := "| Name | Namespace | Status | Duration | Started |\n| --- | --- | --- | --- | --- |\n| build-1 | ci | Succeeded | 1m 30s | 2025-03-01T10:00:00Z |"
+	if !strings.HasPrefix(markdown, want) || !strings.Contains(markdown, `| a\|b |`) {
+		t.Errorf("Unexpected markdown:\n%s", markdown)
+	}
+
+	csv := getTextFromResult(call(map[string]any{"output": "csv", "labelKeys": []any{"app"}}))
+	wantCSV := "name,namespace,uid,pipelineTask,outcome,status,reason,startTime,completionTime,durationSeconds,team,recordName,message,label:app\n" +
+		"build-1,ci,,,succeeded,True,Succeeded,2025-03-01T10:00:00Z,2025-03-01T10:01:30Z,90,,,,web\n"
+	if !strings.HasPrefix(csv, wantCSV) {
+		t.Errorf("Unexpected csv:\n%s", csv)
+	}
+	if !strings.HasSuffix(csv, `,"step ""build"", exited 1",`+"\n") {
+		t.Errorf("Expected quotes and commas to be escaped:\n%s", csv)
+	}
+
+	for _, args := range []map[string]any{
+		{"output": "xml"},
+		{"output": "table", "groupBy": "pipeline"},
+	} {
+		if result := call(args); !result.IsError {
+			t.Errorf("Expected an error for %v, got %s", args, getTextFromResult(result))
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// manifestParams controls how the tools returning a single run render it.
+type manifestParams struct {
+	Output         string `json:"output"`
+	IncludeSummary bool   `json:"includeSummary"`
+	Depth          string `json:"depth"`
+}
+
+// manifestDepths lists the accepted depth values: the status subtree, the
+// spec subtree, or the whole manifest.
+var manifestDepths = []string{"full", "status", "spec"}
+
+// manifestOptions declares the manifestParams properties on a tool.
+func manifestOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("output",
+			mcp.Description("Return format: 'yaml' (default) or 'json' for the manifest, or 'slack' for a Slack mrkdwn summary with a status emoji, links to the triggering change and the failure message, instead of the manifest."),
+			mcp.DefaultString("yaml"),
+			mcp.Enum(outputFormats...),
+		),
+		mcp.WithString("depth",
+			mcp.Description("Part of the manifest to return: 'full' (default), 'status' for the outcome, conditions and child references, or 'spec' for the requested parameters and definition. Both partial depths keep apiVersion, kind and identifying metadata."),
+			mcp.DefaultString("full"),
+			mcp.Enum(manifestDepths...),
+		),
+		mcp.WithBoolean("includeSummary",
+			mcp.Description("Prepend a short human readable summary (status, start time, duration) before the manifest, so a separate list call is not needed to read the outcome."),
+			mcp.DefaultBool(false),
+		),
+	}
+}
+
+// manifestResult renders detail as the tool result: the optional summary
+// followed by the manifest. kind is used in the summary; when empty it is
+// read from the manifest.
+func manifestResult(kind string, detail *tektonresults.RunDetail, p manifestParams) (*mcp.CallToolResult, error) {
+	output := strings.ToLower(strings.TrimSpace(p.Output))
+	if output == "" {
+		output = "yaml"
+	}
+	if output == "slack" {
+		if kind == "" {
+			kind = manifestKind(detail.Raw)
+		}
+		return slackResult([]string{slackRunSummary(kind, detail)}), nil
+	}
+	trimmed, err := trimManifest(detail.Raw, p.Depth)
+	if err != nil {
+		return nil, err
+	}
+	shallow := *detail
+	shallow.Raw = trimmed
+	formatted, err := shallow.Format(output)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mcp.NewToolResultText(formatted)
+	if p.IncludeSummary {
+		if kind == "" {
+			kind = manifestKind(detail.Raw)
+		}
+		text := runSummaryText(kind, detail.Summary)
+		if failure := detail.ConfigFailure(); failure != nil {
+			text += "\n" + failure.String()
+		}
+		summary := mcp.NewTextContent(text)
+		result.Content = append([]mcp.Content{summary}, result.Content...)
+	}
+	if note := schemaNote(detail.SchemaWarnings); note != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(note))
+	}
+	return result, nil
+}
+
+// schemaNote warns that a stored manifest departs from the Tekton schema the
+// server understands, so fields it does not know may be missing from
+// summaries.
+func schemaNote(warnings []string) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	return "Warning: this run does not match the Tekton v1 schema known to the server, possibly because a newer Tekton release stored it; summaries may miss fields the server does not understand:\n- " + strings.Join(warnings, "\n- ")
+}
+
+// lookupCheck adds the GitHub check of the change that triggered the run to
+// its summary. It only runs when a summary was asked for, and a failed
+// lookup is reported in the summary instead of failing the call.
+func lookupCheck(ctx context.Context, svc ChangeInspector, detail *tektonresults.RunDetail, p manifestParams) {
+	change := detail.Summary.Change
+	summarized := p.IncludeSummary || strings.EqualFold(strings.TrimSpace(p.Output), "slack")
+	if !summarized || change == nil {
+		return
+	}
+	check, err := svc.CheckStatus(ctx, *change)
+	if err != nil {
+		change.CheckError = err.Error()
+		return
+	}
+	change.Check = check
+}
+
+// diagnosisText renders the configuration failure of a run, with a link to
+// the run in the dashboard when one is configured.
+func diagnosisText(failure *tektonresults.ConfigFailure, s tektonresults.RunSummary) string {
+	if s.DashboardURL == "" {
+		return failure.String()
+	}
+	return failure.String() + "\nDashboard: " + s.DashboardURL
+}
+
+// runSummaryText describes a run in a few lines, e.g.
+//
+//	PipelineRun ci/build-x7k2p
+//	Status: Failed
+//	Started: 2025-01-01T10:00:00Z
+//	Duration: 4m 30s
+func runSummaryText(kind string, s tektonresults.RunSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s/%s\n", kind, s.Namespace, s.Name)
+	if s.PipelineTask != "" {
+		fmt.Fprintf(&b, "Pipeline Task: %s\n", s.PipelineTask)
+	}
+	fmt.Fprintf(&b, "Status: %s\n", runState(s))
+	if s.Message != "" {
+		fmt.Fprintf(&b, "Message: %s\n", s.Message)
+	}
+	fmt.Fprintf(&b, "Started: %s\n", format.Timestamp(timeOf(s.StartTime)))
+	fmt.Fprintf(&b, "Duration: %s", runDuration(s))
+	if s.DashboardURL != "" {
+		fmt.Fprintf(&b, "\nDashboard: %s", s.DashboardURL)
+	}
+	if c := s.Change; c != nil {
+		if c.PullRequestURL != "" {
+			fmt.Fprintf(&b, "\nPull Request: %s", c.PullRequestURL)
+		}
+		if c.CommitURL != "" {
+			fmt.Fprintf(&b, "\nCommit: %s", c.CommitURL)
+		}
+		switch {
+		case c.Check != nil:
+			state := c.Check.Status
+			if c.Check.Conclusion != "" {
+				state = c.Check.Conclusion
+			}
+			fmt.Fprintf(&b, "\nCheck: %s: %s", c.Check.Name, state)
+			if c.Check.URL != "" && c.Check.URL != c.CommitURL {
+				fmt.Fprintf(&b, " (%s)", c.Check.URL)
+			}
+		case c.CheckError != "":
+			fmt.Fprintf(&b, "\nCheck: unavailable: %s", c.CheckError)
+		}
+	}
+	if s.Incomplete {
+		b.WriteString("\nNote: Tekton Results stored this run before it reported a status. Query it again later")
+		if kind != "Run" {
+			fmt.Fprintf(&b, ", or read the live resource with 'kubectl get %s %s -n %s -o yaml' if it still exists in the cluster", strings.ToLower(kind), s.Name, s.Namespace)
+		}
+		b.WriteString(".")
+	}
+	return b.String()
+}
+
+// manifestKind returns the kind field of a Tekton manifest, or "Run" when
+// it cannot be determined.
+func manifestKind(raw json.RawMessage) string {
+	var manifest struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil || manifest.Kind == "" {
+		return "Run"
+	}
+	return manifest.Kind
+}
+
+// identityMetadata are the metadata fields kept by partial depths.
+var identityMetadata = []string{"name", "namespace", "uid", "creationTimestamp", "labels"}
+
+// trimManifest keeps only the subtree selected by depth, along with
+// apiVersion, kind and the identifying metadata fields.
+func trimManifest(raw json.RawMessage, depth string) (json.RawMessage, error) {
+	depth = strings.ToLower(strings.TrimSpace(depth))
+	switch depth {
+	case "", "full":
+		return raw, nil
+	case "status", "spec":
+	default:
+		return nil, fmt.Errorf("unsupported depth %q; use one of: %s", depth, strings.Join(manifestDepths, ", "))
+	}
+
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	trimmed := map[string]any{}
+	for _, key := range []string{"apiVersion", "kind", depth} {
+		if v, ok := manifest[key]; ok {
+			trimmed[key] = v
+		}
+	}
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(manifest["metadata"], &metadata); err == nil {
+		kept := map[string]json.RawMessage{}
+		for _, key := range identityMetadata {
+			if v, ok := metadata[key]; ok {
+				kept[key] = v
+			}
+		}
+		trimmed["metadata"] = kept
+	}
+	return json.Marshal(trimmed)
+}
@@ -0,0 +1,237 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestRunSummaryText(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(4*time.Minute + 30*time.Second))
+
+	got := runSummaryText("TaskRun", tektonresults.RunSummary{
+		Name:           "build-x7k2p-compile",
+		Namespace:      "ci",
+		PipelineTask:   "compile",
+		StartTime:      &start,
+		CompletionTime: &end,
+		Reason:         "Failed",
+	})
+	want := "TaskRun ci/build-x7k2p-compile\nPipeline Task: compile\nStatus: Failed\nStarted: 2025-01-01T10:00:00Z\nDuration: 4m 30s"
+	if got != want {
+		t.Errorf("runSummaryText() =\n%s\nwant\n%s", got, want)
+	}
+
+	withMessage := runSummaryText("PipelineRun", tektonresults.RunSummary{Name: "pr", Namespace: "ci", Reason: "Failed", Message: "Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 0"})
+	if !strings.Contains(withMessage, "Status: Failed\nMessage: Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 0\n") {
+		t.Errorf("Expected the condition message after the status, got:\n%s", withMessage)
+	}
+
+	running := runSummaryText("PipelineRun", tektonresults.RunSummary{Name: "pr", Namespace: "ci", StartTime: &start})
+	if !strings.Contains(running, "Status: Running") || !strings.Contains(running, "Duration: -") {
+		t.Errorf("Unexpected summary for a running run: %s", running)
+	}
+}
+
+func TestRunSummaryText_IncompleteRecord(t *testing.T) {
+	got := runSummaryText("PipelineRun", tektonresults.RunSummary{Name: "pr", Namespace: "ci", Status: "Unknown", Incomplete: true})
+	for _, want := range []string{"Status: Unknown (incomplete record)", "Query it again later", "kubectl get pipelinerun pr -n ci -o yaml"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, got)
+		}
+	}
+	if got := runSummaryText("Run", tektonresults.RunSummary{Name: "pr", Namespace: "ci", Incomplete: true}); strings.Contains(got, "kubectl") {
+		t.Errorf("Expected no kubectl hint without a known kind, got:\n%s", got)
+	}
+}
+
+func TestManifestResult(t *testing.T) {
+	detail := &tektonresults.RunDetail{
+		Summary: tektonresults.RunSummary{Name: "pr-1", Namespace: "ci", Reason: "Succeeded"},
+		Raw:     json.RawMessage(`{"kind":"PipelineRun","metadata":{"name":"pr-1"}}`),
+	}
+
+	result, err := manifestResult("", detail, manifestParams{})
+	if err != nil {
+		t.Fatalf("manifestResult() error = %v", err)
+	}
+	if len(result.Content) != 1 || !strings.Contains(getTextFromResult(result), "kind: PipelineRun") {
+		t.Errorf("Expected only the YAML manifest by default, got %+v", result.Content)
+	}
+
+	result, err = manifestResult("", detail, manifestParams{Output: "json", IncludeSummary: true})
+	if err != nil {
+		t.Fatalf("manifestResult() error = %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected summary and manifest, got %d items", len(result.Content))
+	}
+	if summary := getTextFromResult(result); !strings.HasPrefix(summary, "PipelineRun ci/pr-1\n") || !strings.Contains(summary, "Status: Succeeded") {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+	if manifest, _ := mcp.AsTextContent(result.Content[1]); !strings.Contains(manifest.Text, `"kind": "PipelineRun"`) {
+		t.Errorf("Expected JSON manifest second, got %s", manifest.Text)
+	}
+
+	if _, err := manifestResult("", detail, manifestParams{Output: "xml"}); err == nil {
+		t.Error("Expected error for unsupported output")
+	}
+}
+
+func TestManifestResult_SchemaWarnings(t *testing.T) {
+	detail := &tektonresults.RunDetail{
+		Raw:            json.RawMessage(`{"kind":"PipelineRun","metadata":{"name":"pr-1"}}`),
+		SchemaWarnings: []string{"spec.quota: unknown field"},
+	}
+
+	result, err := manifestResult("PipelineRun", detail, manifestParams{})
+	if err != nil {
+		t.Fatalf("manifestResult() error = %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected manifest and warning, got %d items", len(result.Content))
+	}
+	if note, _ := mcp.AsTextContent(result.Content[1]); !strings.Contains(note.Text, "does not match the Tekton v1 schema") || !strings.Contains(note.Text, "- spec.quota: unknown field") {
+		t.Errorf("Unexpected warning: %s", note.Text)
+	}
+}
+
+func TestLookupCheck(t *testing.T) {
+	change := &tektonresults.SourceChange{
+		Repository:     "https://github.com/acme/widgets",
+		SHA:            "abc123",
+		CommitURL:      "https://github.com/acme/widgets/commit/abc123",
+		PullRequestURL: "https://github.com/acme/widgets/pull/42",
+	}
+	detail := &tektonresults.RunDetail{Summary: tektonresults.RunSummary{Name: "pr-1", Namespace: "ci", Change: change, DashboardURL: "https://tekton.example.com/#/namespaces/ci/pipelineruns/pr-1"}}
+	calls := 0
+	svc := &mockPipelineRunService{checkStatusFunc: func(ctx context.Context, c tektonresults.SourceChange) (*tektonresults.CheckStatus, error) {
+		calls++
+		return &tektonresults.CheckStatus{Name: "widgets-on-pull-request", Status: "completed", Conclusion: "failure", URL: "https://github.com/acme/widgets/runs/987"}, nil
+	}}
+
+	lookupCheck(context.Background(), svc, detail, manifestParams{})
+	if calls != 0 {
+		t.Error("Expected no lookup without includeSummary")
+	}
+	lookupCheck(context.Background(), svc, detail, manifestParams{IncludeSummary: true})
+	summary := runSummaryText("PipelineRun", detail.Summary)
+	for _, want := range []string{
+		"Dashboard: https://tekton.example.com/#/namespaces/ci/pipelineruns/pr-1",
+		"Pull Request: https://github.com/acme/widgets/pull/42",
+		"Commit: https://github.com/acme/widgets/commit/abc123",
+		"Check: widgets-on-pull-request: failure (https://github.com/acme/widgets/runs/987)",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+
+	svc.checkStatusFunc = func(ctx context.Context, c tektonresults.SourceChange) (*tektonresults.CheckStatus, error) {
+		return nil, &testError{msg: "HTTP 401: Bad credentials"}
+	}
+	detail.Summary.Change = &tektonresults.SourceChange{CommitURL: change.CommitURL}
+	lookupCheck(context.Background(), svc, detail, manifestParams{IncludeSummary: true})
+	if summary := runSummaryText("PipelineRun", detail.Summary); !strings.Contains(summary, "Check: unavailable: HTTP 401: Bad credentials") {
+		t.Errorf("Expected the failed lookup in the summary, got:\n%s", summary)
+	}
+}
+
+func TestManifestKind(t *testing.T) {
+	if got := manifestKind(json.RawMessage(`{"kind":"TaskRun"}`)); got != "TaskRun" {
+		t.Errorf("manifestKind() = %q, want TaskRun", got)
+	}
+	if got := manifestKind(json.RawMessage(`{}`)); got != "Run" {
+		t.Errorf("manifestKind() = %q, want Run", got)
+	}
+}
+
+func TestPipelineRunGet_IncludeSummary(t *testing.T) {
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{Name: "my-pipeline", Namespace: "test-ns", Reason: "Failed"},
+				Raw:     json.RawMessage(`{"metadata":{"name":"my-pipeline"}}`),
+			}, nil
+		},
+	}
+	tool := newPipelineRunGetTool(Dependencies{Service: mock, DefaultNamespace: "test-ns"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "my-pipeline", "includeSummary": true}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	if summary := getTextFromResult(result); !strings.HasPrefix(summary, "PipelineRun test-ns/my-pipeline") || !strings.Contains(summary, "Status: Failed") {
+		t.Errorf("Expected summary first, got %s", summary)
+	}
+}
+
+func TestTrimManifest(t *testing.T) {
+	raw := json.RawMessage(`{"apiVersion":"tekton.dev/v1","kind":"PipelineRun",` +
+		`"metadata":{"name":"pr-1","namespace":"ci","uid":"u1","managedFields":[{"manager":"controller"}],"annotations":{"big":"x"}},` +
+		`"spec":{"params":[{"name":"revision","value":"main"}]},` +
+		`"status":{"conditions":[{"type":"Succeeded","status":"False"}]}}`)
+
+	tests := []struct {
+		depth    string
+		keep     []string
+		dropKeys []string
+	}{
+		{"", []string{"managedFields", "revision", "conditions"}, nil},
+		{"full", []string{"managedFields", "revision", "conditions"}, nil},
+		{"status", []string{`"kind":"PipelineRun"`, `"name":"pr-1"`, `"uid":"u1"`, "conditions"}, []string{"managedFields", "annotations", "revision"}},
+		{"SPEC", []string{`"apiVersion":"tekton.dev/v1"`, `"namespace":"ci"`, "revision"}, []string{"managedFields", "conditions"}},
+	}
+	for _, tt := range tests {
+		got, err := trimManifest(raw, tt.depth)
+		if err != nil {
+			t.Fatalf("trimManifest(%q) error = %v", tt.depth, err)
+		}
+		for _, want := range tt.keep {
+			if !strings.Contains(string(got), want) {
+				t.Errorf("trimManifest(%q) missing %s: %s", tt.depth, want, got)
+			}
+		}
+		for _, unwanted := range tt.dropKeys {
+			if strings.Contains(string(got), unwanted) {
+				t.Errorf("trimManifest(%q) kept %s: %s", tt.depth, unwanted, got)
+			}
+		}
+	}
+
+	if _, err := trimManifest(raw, "metadata"); err == nil {
+		t.Error("Expected error for unsupported depth")
+	}
+}
+
+func TestTaskRunGet_Depth(t *testing.T) {
+	mock := &mockTaskRunService{
+		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Raw: json.RawMessage(`{"kind":"TaskRun","metadata":{"name":"tr-1"},"spec":{"timeout":"1h0m0s"},"status":{"podName":"tr-1-pod"}}`),
+			}, nil
+		},
+	}
+	tool := newTaskRunGetTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "tr-1", "depth": "status"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	text := getTextFromResult(result)
+	if !strings.Contains(text, "podName: tr-1-pod") || strings.Contains(text, "timeout") {
+		t.Errorf("Expected only the status subtree, got:\n%s", text)
+	}
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// missingSelectorError is returned by selectorParams.validate when nothing
+// identifies the run.
+type missingSelectorError struct {
+	kind string
+}
+
+func (e missingSelectorError) Error() string {
+	return (*messages.Catalog)(nil).Format(messages.MissingSelector, map[string]string{"Kind": e.kind})
+}
+
+// runError renders an error of a tool acting on one run of kind. Errors the
+// catalog has a message for are worded by it; others are returned as is.
+func (d Dependencies) runError(kind string, err error) *mcp.CallToolResult {
+	data := map[string]string{"Kind": kind}
+	var missing missingSelectorError
+	switch {
+	case errors.As(err, &missing):
+		return mcp.NewToolResultError(d.Messages.Format(messages.MissingSelector, data))
+	case errors.Is(err, tektonresults.ErrRunNotFound):
+		return mcp.NewToolResultError(d.Messages.Format(messages.RunNotFound, data))
+	case errors.Is(err, tektonresults.ErrLogsDisabled):
+		return mcp.NewToolResultError(d.Messages.Format(messages.LogsDisabled, data))
+	}
+	return mcp.NewToolResultError(err.Error())
+}
+
+// withErrorFooter wraps the handler of each tool to append the errorFooter
+// message of catalog, when set, to the error results it returns.
+func withErrorFooter(tools []server.ServerTool, catalog *messages.Catalog) []server.ServerTool {
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		name, next := st.Tool.Name, st.Handler
+		st.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err != nil || result == nil || !result.IsError {
+				return result, err
+			}
+			if footer := catalog.Format(messages.ErrorFooter, map[string]string{"Tool": name}); footer != "" {
+				result.Content = append(result.Content, mcp.NewTextContent(footer))
+			}
+			return result, nil
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestMessages_Overrides(t *testing.T) {
+	catalog, err := messages.New(map[string]string{
+		"runNotFound": "No such {{.Kind}}. Runs are kept for 30 days.",
+		"errorFooter": "Need help? See https://runbooks.example.com/tekton ({{.Tool}})",
+	})
+	if err != nil {
+		t.Fatalf("messages.New() error = %v", err)
+	}
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return nil, tektonresults.ErrRunNotFound
+		},
+	}
+	tools, err := serverTools(Dependencies{Service: mock, DefaultNamespace: "ci", Messages: catalog})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		for _, st := range tools {
+			if st.Tool.Name != name {
+				continue
+			}
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = args
+			result, err := st.Handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Handler failed: %v", err)
+			}
+			return result
+		}
+		t.Fatalf("tool %s not registered", name)
+		return nil
+	}
+
+	result := call("pipelinerun_get", map[string]any{"name": "gone"})
+	if !result.IsError || len(result.Content) != 2 {
+		t.Fatalf("Expected an error with a footer, got %+v", result.Content)
+	}
+	if got := getTextFromResult(result); got != "No such PipelineRun. Runs are kept for 30 days." {
+		t.Errorf("Unexpected error %q", got)
+	}
+	if got := result.Content[1].(mcp.TextContent).Text; got != "Need help? See https://runbooks.example.com/tekton (pipelinerun_get)" {
+		t.Errorf("Unexpected footer %q", got)
+	}
+
+	// Messages without an override keep the built-in wording.
+	result = call("taskrun_logs", map[string]any{})
+	if got := getTextFromResult(result); got != "provide at least one of name, prefix, nameRegex, uid, labelSelector or annotationSelector to identify a TaskRun" {
+		t.Errorf("Unexpected error %q", got)
+	}
+}
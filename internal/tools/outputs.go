@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
+)
+
+// outputURITemplate addresses a page of a tool result that was too large to
+// return inline, e.g. tekton-results://outputs/0b7c3b4e-...?page=2.
+const outputURITemplate = "tekton-results://outputs/{id}{?page}"
+
+const (
+	// outputTTL is how long the pages of a large result can be read.
+	outputTTL = 15 * time.Minute
+	// maxStoredOutputs bounds the results kept at once; the oldest is
+	// dropped first.
+	maxStoredOutputs = 64
+)
+
+func outputURI(id string, page int) string {
+	return fmt.Sprintf("tekton-results://outputs/%s?page=%d", id, page)
+}
+
+// outputStore keeps the pages of large tool results for outputTTL, each
+// readable only by the session that called the tool. It is safe for
+// concurrent use.
+type outputStore struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]*storedOutput
+}
+
+type storedOutput struct {
+	session string // client session of the call; empty without one
+	tool    string
+	pages   []string
+	expires time.Time
+}
+
+func newOutputStore() *outputStore {
+	return &outputStore{now: time.Now, entries: map[string]*storedOutput{}}
+}
+
+// put stores the pages of a result of tool, called in session, and returns
+// their id.
+func (s *outputStore) put(session, tool string, pages []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for id, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, id)
+		}
+	}
+	for len(s.entries) >= maxStoredOutputs {
+		oldest := ""
+		for id, e := range s.entries {
+			if oldest == "" || e.expires.Before(s.entries[oldest].expires) {
+				oldest = id
+			}
+		}
+		delete(s.entries, oldest)
+	}
+	id := uuid.NewString()
+	s.entries[id] = &storedOutput{session: session, tool: tool, pages: pages, expires: now.Add(outputTTL)}
+	return id
+}
+
+// page returns page n, counted from 1, of the output id read in session, and
+// the output. Outputs of other sessions are reported as missing.
+func (s *outputStore) page(session, id string, n int) (string, storedOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok || e.session != session || s.now().After(e.expires) {
+		return "", storedOutput{}, fmt.Errorf("output %s has expired or never existed; outputs are kept for %s, call the tool again", id, outputTTL)
+	}
+	if n < 1 || n > len(e.pages) {
+		return "", storedOutput{}, fmt.Errorf("output %s has pages 1 to %d, not %d", id, len(e.pages), n)
+	}
+	return e.pages[n-1], *e, nil
+}
+
+// sessionID returns the id of the client session of ctx, or "" without one.
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// withPagination wraps the handler of each tool so that a result with more
+// than limit bytes of text returns only its first page inline. The pages are
+// kept in store, and the result ends with a note and a resource link to the
+// second page. Error results are returned whole.
+func withPagination(tools []server.ServerTool, store *outputStore, limit int) []server.ServerTool {
+	if store == nil || limit <= 0 {
+		return tools
+	}
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		name, next := st.Tool.Name, st.Handler
+		st.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err != nil || result == nil || result.IsError || resultBytes(result) <= limit {
+				return result, err
+			}
+			// The first text item is the output; later ones are short notes.
+			i := slices.IndexFunc(result.Content, func(c mcp.Content) bool {
+				_, ok := c.(mcp.TextContent)
+				return ok
+			})
+			if i < 0 {
+				return result, nil
+			}
+			text := result.Content[i].(mcp.TextContent)
+			pages := splitPages(text.Text, limit)
+			if len(pages) < 2 {
+				return result, nil
+			}
+			id := store.put(sessionID(ctx), name, pages)
+			size := len(text.Text)
+			text.Text = pages[0]
+			result.Content[i] = text
+			result.Content = append(result.Content,
+				mcp.NewTextContent(fmt.Sprintf("Note: the output is %s, over the %s returned at once, so this is page 1 of %d. Read the resource %s, and so on up to page=%d, for the rest; the pages are kept for %s.",
+					format.Bytes(int64(size)), format.Bytes(int64(limit)), len(pages), outputURI(id, 2), len(pages), outputTTL)),
+				mcp.NewResourceLink(outputURI(id, 2), fmt.Sprintf("%s output, page 2 of %d", name, len(pages)), "The next page of this tool result.", "text/plain"),
+			)
+			return result, nil
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
+
+// splitPages splits text into pages of at most limit bytes, ending pages
+// after a newline where possible and never inside a UTF-8 sequence.
+func splitPages(text string, limit int) []string {
+	var pages []string
+	for len(text) > limit {
+		cut := strings.LastIndexByte(text[:limit], '\n') + 1
+		if cut == 0 {
+			cut = limit
+			for cut > 1 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		pages = append(pages, text[:cut])
+		text = text[cut:]
+	}
+	return append(pages, text)
+}
+
+// newOutputResource serves the pages withPagination stores. With a signer,
+// each page carries its signature in _meta, as tool results do.
+func newOutputResource(store *outputStore, signer *signing.Signer) server.ServerResourceTemplate {
+	template := mcp.NewResourceTemplate(outputURITemplate, "Tool output page",
+		mcp.WithTemplateDescription(fmt.Sprintf("A page of a tool result too large to return at once. Tools link the second page of such a result; page counts from 1 and the note of the result names the last page. Pages are kept for %s and can only be read in the session that called the tool.", outputTTL)),
+		mcp.WithTemplateMIMEType("text/plain"),
+	)
+	return server.ServerResourceTemplate{
+		Template: template,
+		Handler: func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			id := templateArgument(req, "id")
+			n, err := strconv.Atoi(cmp.Or(templateArgument(req, "page"), "1"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid page in %q: %w", req.Params.URI, err)
+			}
+			text, output, err := store.page(sessionID(ctx), id, n)
+			if err != nil {
+				return nil, err
+			}
+			pages := len(output.pages)
+			meta := map[string]any{"page": n, "pages": pages}
+			if n < pages {
+				meta["next"] = outputURI(id, n+1)
+			}
+			if signer != nil {
+				meta[signing.MetaKey] = signer.SignPage(output.tool, outputURI(id, n), text)
+			}
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{Meta: meta, URI: req.Params.URI, MIMEType: "text/plain", Text: text},
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
+)
+
+func TestSplitPages(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"line one\nline two\nline three\n", 12, []string{"line one\n", "line two\n", "line three\n"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		// "é" is two bytes and is never split.
+		{"aéééé", 4, []string{"aé", "éé", "é"}},
+	}
+	for _, tt := range tests {
+		got := splitPages(tt.text, tt.limit)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("splitPages(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+		if strings.Join(got, "") != tt.text {
+			t.Errorf("splitPages(%q, %d) lost text: %q", tt.text, tt.limit, got)
+		}
+	}
+}
+
+func TestOutputStore(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newOutputStore()
+	store.now = func() time.Time { return now }
+
+	id := store.put("s1", "taskrun_logs", []string{"one", "two"})
+	if text, output, err := store.page("s1", id, 2); err != nil || text != "two" || len(output.pages) != 2 || output.tool != "taskrun_logs" {
+		t.Errorf("page(2) = %q, %+v, %v", text, output, err)
+	}
+	if _, _, err := store.page("s2", id, 1); err == nil || !strings.Contains(err.Error(), "never existed") {
+		t.Errorf("Expected another session not to read the output, got %v", err)
+	}
+	if _, _, err := store.page("s1", id, 3); err == nil || !strings.Contains(err.Error(), "pages 1 to 2") {
+		t.Errorf("Expected page 3 to be rejected, got %v", err)
+	}
+
+	now = now.Add(outputTTL + time.Second)
+	if _, _, err := store.page("s1", id, 1); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected the output to expire, got %v", err)
+	}
+
+	first := store.put("s1", "taskrun_logs", []string{"first"})
+	for range maxStoredOutputs {
+		now = now.Add(time.Second)
+		store.put("s1", "taskrun_logs", []string{"later"})
+	}
+	if len(store.entries) != maxStoredOutputs {
+		t.Errorf("Expected %d stored outputs, got %d", maxStoredOutputs, len(store.entries))
+	}
+	if _, _, err := store.page("s1", first, 1); err == nil {
+		t.Error("Expected the oldest output to be dropped")
+	}
+}
+
+func TestPagination(t *testing.T) {
+	store := newOutputStore()
+	text := strings.Repeat("0123456789abcdef\n", 10) // 170 bytes
+	tools := withPagination([]server.ServerTool{
+		{Tool: mcp.NewTool("taskrun_logs"), Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(text), nil
+		}},
+		{Tool: mcp.NewTool("pipelinerun_get"), Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError(text), nil
+		}},
+	}, store, 64)
+
+	result, err := tools[0].Handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if len(result.Content) != 3 || getTextFromResult(result) != strings.Repeat("0123456789abcdef\n", 3) {
+		t.Fatalf("Expected the first page, a note and a link, got %+v", result.Content)
+	}
+	if note := result.Content[1].(mcp.TextContent).Text; !strings.Contains(note, "page 1 of 4") {
+		t.Errorf("Unexpected note %q", note)
+	}
+	link, ok := result.Content[2].(mcp.ResourceLink)
+	if !ok || !strings.HasPrefix(link.URI, "tekton-results://outputs/") || !strings.HasSuffix(link.URI, "?page=2") {
+		t.Fatalf("Expected a link to page 2, got %+v", result.Content[2])
+	}
+
+	// Errors are returned whole.
+	result, _ = tools[1].Handler(context.Background(), mcp.CallToolRequest{})
+	if len(result.Content) != 1 || getTextFromResult(result) != text {
+		t.Errorf("Expected the error whole, got %+v", result.Content)
+	}
+
+	s := server.NewMCPServer("test", "0", server.WithResourceCapabilities(false, false))
+	signer, err := signing.New([]byte(strings.Repeat("k", signing.MinKeyLength)))
+	if err != nil {
+		t.Fatalf("signing.New() error = %v", err)
+	}
+	s.AddResourceTemplates(newOutputResource(store, signer))
+	read := func(uri string) *mcp.ReadResourceResult {
+		msg := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+		resp, ok := msg.(mcp.JSONRPCResponse)
+		if !ok {
+			return nil
+		}
+		result := resp.Result.(mcp.ReadResourceResult)
+		return &result
+	}
+
+	page := read(link.URI)
+	if page == nil || len(page.Contents) != 1 {
+		t.Fatalf("Expected page 2 of %s", link.URI)
+	}
+	contents := page.Contents[0].(mcp.TextResourceContents)
+	if contents.Text != strings.Repeat("0123456789abcdef\n", 3) || contents.Meta["page"] != 2 || contents.Meta["pages"] != 4 ||
+		contents.Meta["next"] != strings.TrimSuffix(link.URI, "2")+"3" {
+		t.Errorf("Unexpected page %+v", contents)
+	}
+	sig, ok := contents.Meta[signing.MetaKey].(signing.Signature)
+	if !ok || sig.Tool != "taskrun_logs" || !signer.VerifyPage(sig, link.URI, contents.Text) {
+		t.Errorf("Expected a valid page signature, got %+v", contents.Meta[signing.MetaKey])
+	}
+	last := read(strings.TrimSuffix(link.URI, "2") + "4")
+	if last == nil || last.Contents[0].(mcp.TextResourceContents).Text != "0123456789abcdef\n" {
+		t.Fatalf("Expected the last page, got %+v", last)
+	}
+	if _, ok := last.Contents[0].(mcp.TextResourceContents).Meta["next"]; ok {
+		t.Error("Expected no next page after the last one")
+	}
+	if first := read(strings.TrimSuffix(link.URI, "?page=2")); first == nil || first.Contents[0].(mcp.TextResourceContents).Meta["page"] != 1 {
+		t.Errorf("Expected a link without a page to read page 1, got %+v", first)
+	}
+	if read("tekton-results://outputs/unknown?page=1") != nil {
+		t.Error("Expected an unknown output to fail")
+	}
+}
+
+func TestPaginationDisabled(t *testing.T) {
+	s, err := NewServer(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if strings.Contains(templateList(t, s), "tekton-results://outputs/") {
+		t.Error("Expected no output resource without an output limit")
+	}
+
+	s, err = NewServer(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default", OutputLimit: 1024})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if !strings.Contains(templateList(t, s), outputURITemplate) {
+		t.Error("Expected the output resource with an output limit")
+	}
+}
+
+func templateList(t *testing.T, s *server.MCPServer) string {
+	t.Helper()
+	msg := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`))
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
+	}
+	return string(data)
+}
@@ -3,13 +3,18 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
 	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
 )
 
@@ -19,87 +24,198 @@ const (
 )
 
 type listParams struct {
-	Namespace     string `json:"namespace"`
-	LabelSelector string `json:"labelSelector"`
-	Prefix        string `json:"prefix"`
-	Limit         int    `json:"limit"`
+	Namespace          string   `json:"namespace"`
+	LabelSelector      string   `json:"labelSelector"`
+	AnnotationSelector string   `json:"annotationSelector"`
+	Pipeline           string   `json:"pipeline"`
+	Task               string   `json:"task"`
+	Prefix             string   `json:"prefix"`
+	NameRegex          string   `json:"nameRegex"`
+	Reason             string   `json:"reason"`
+	Status             string   `json:"status"`
+	MinDurationSeconds int      `json:"minDurationSeconds"`
+	MaxDurationSeconds int      `json:"maxDurationSeconds"`
+	CreatedAfter       string   `json:"createdAfter"`
+	CreatedBefore      string   `json:"createdBefore"`
+	Team               string   `json:"team"`
+	ServiceAccount     string   `json:"serviceAccount"`
+	LatestOnly         bool     `json:"latestOnly"`
+	GroupBy            string   `json:"groupBy"`
+	OrderBy            string   `json:"orderBy"`
+	Limit              int      `json:"limit"`
+	LabelKeys          []string `json:"labelKeys"`
+	PageToken          string   `json:"pageToken"`
+	Output             string   `json:"output"`
 }
 
 type getParams struct {
-	Namespace     string `json:"namespace"`
-	LabelSelector string `json:"labelSelector"`
-	Prefix        string `json:"prefix"`
-	Name          string `json:"name"`
-	UID           string `json:"uid"`
-	Output        string `json:"output"`
-	SelectLast    bool   `json:"selectLast"`
+	selectorParams
+	manifestParams
 }
 
 type logsParams struct {
-	Namespace     string `json:"namespace"`
-	LabelSelector string `json:"labelSelector"`
-	Prefix        string `json:"prefix"`
-	Name          string `json:"name"`
-	UID           string `json:"uid"`
-	SelectLast    bool   `json:"selectLast"`
+	selectorParams
+	Container string `json:"container"`
 }
 
+type pipelineRunLogsParams struct {
+	selectorParams
+	Output     string   `json:"output"`
+	Tasks      []string `json:"tasks"`
+	FailedOnly bool     `json:"failedOnly"`
+	Container  string   `json:"container"`
+}
+
+// logFormats lists the output modes accepted by pipelinerun_logs.
+var logFormats = []string{"text", "json", "slack"}
+
+// taskRunLog is one TaskRun section of the pipelinerun_logs output.
+type taskRunLog struct {
+	TaskRun      string `json:"taskRun"`
+	PipelineTask string `json:"pipelineTask,omitempty"`
+	Status       string `json:"status"`
+	Started      string `json:"started,omitempty"`
+	Completed    string `json:"completed,omitempty"`
+	Logs         string `json:"logs,omitempty"`
+	Error        string `json:"error,omitempty"`
+
+	duration string
+	run      tektonresults.RunSummary
+}
+
+// logsDisabledError marks TaskRuns whose logs were not fetched because the
+// Results server stores no logs.
+const logsDisabledError = "log storage is not enabled on this Results server"
+
 func pipelineRunTools(deps Dependencies) ([]server.ServerTool, error) {
 	return []server.ServerTool{
 		newPipelineRunListTool(deps),
 		newPipelineRunGetTool(deps),
 		newPipelineRunLogsTool(deps),
+		newPipelineRunDiffTool(deps),
+		newPipelineRunCriticalPathTool(deps),
+		newPipelineRunResultsTool(deps),
+		newPipelineRunParamsTool(deps),
+		newPipelineRunTimelineTool(deps),
+		newPipelineRunStatsTool(deps),
 	}, nil
 }
 
+// childTaskRuns lists the TaskRuns of the PipelineRun run. They are selected
+// by its UID rather than its name, since names can be reused over time.
+func (deps Dependencies) childTaskRuns(ctx context.Context, run tektonresults.RunSummary) ([]tektonresults.RunSummary, error) {
+	return deps.Service.ListTaskRuns(ctx, tektonresults.ListOptions{
+		Namespace:     run.Namespace,
+		LabelSelector: fmt.Sprintf("tekton.dev/pipelineRunUID=%s", run.UID),
+		Limit:         maxListLimit,
+	})
+}
+
 func newPipelineRunListTool(deps Dependencies) server.ServerTool {
 	namespaceDefault := deps.DefaultNamespace
 	if namespaceDefault == "" {
 		namespaceDefault = "default"
 	}
 
-	tool := mcp.NewTool(
-		"pipelinerun_list",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription("List Tekton PipelineRuns stored by the Tekton Results service with optional namespace, label, and name prefix filters."),
 		mcp.WithToolAnnotation(readOnlyAnnotations("List PipelineRuns")),
-		mcp.WithString("namespace",
-			mcp.Description("Kubernetes namespace to query. Use '-' to search across all namespaces."),
-			mcp.DefaultString(namespaceDefault),
-		),
-		mcp.WithString("labelSelector",
-			mcp.Description("Comma separated key=value selectors that must match run labels."),
+		namespacesOption(namespaceDefault),
+		mcp.WithString("pipeline",
+			mcp.Description("Only return runs of this Pipeline: spec.pipelineRef.name, or the tekton.dev/pipeline label for runs with an embedded or resolved pipeline spec."),
 			mcp.DefaultString(""),
+			examples("build-pipeline"),
 		),
+		labelSelectorOption("tekton.dev/pipeline=build-pipeline", "app=frontend,env=prod", "app=frontend,env!=dogfood"),
+		annotationSelectorOption(),
 		mcp.WithString("prefix",
 			mcp.Description("Optional PipelineRun name prefix to match."),
 			mcp.DefaultString(""),
 		),
+		nameRegexOption("PipelineRun"),
+		mcp.WithString("reason",
+			mcp.Description("Only return runs whose Succeeded condition has one of these reasons (comma separated, case insensitive), e.g. 'Failed', 'PipelineRunTimeout' or 'CouldntGetTask'."),
+			mcp.DefaultString(""),
+			examples("Failed", "PipelineRunTimeout,CouldntGetTask"),
+		),
+		statusOption(),
+		teamOption(),
+		serviceAccountOption(),
+		latestOnlyOption(),
+		groupByOption("PipelineRun"),
+		groupDryRunOption(),
+		listOutputOption(),
+		orderByOption(),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of records to return (1-200)."),
 			mcp.DefaultNumber(defaultListLimit),
 			mcp.Min(1),
 			mcp.Max(maxListLimit),
 		),
-	)
-
-	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args listParams) (*mcp.CallToolResult, error) {
+	}
+	opts = append(opts, createdRangeOptions()...)
+	opts = append(opts, durationOptions()...)
+	opts = append(opts, pageTokenOption())
+	opts = append(opts, projectionOptions()...)
+
+	tool := newTool("pipelinerun_list", []toolExample{
+		{"namespace": namespaceDefault, "limit": 10},
+		{"namespace": "-", "pipeline": "build-pipeline"},
+		{"namespace": "-", "labelSelector": "tekton.dev/pipeline=build-pipeline"},
+		{"namespace": namespaceDefault, "prefix": "build-pipeline-run-"},
+		{"namespace": namespaceDefault, "nameRegex": "^build-[0-9a-f]{7}-[a-z0-9]{5}$"},
+		{"namespace": "-", "annotationSelector": "pipelinesascode.tekton.dev/sha=3f2a1c9e8b7d6f5a4c3b2a1908f7e6d5c4b3a291"},
+		{"namespace": "ci,staging", "limit": 20},
+		{"namespace": namespaceDefault, "labelKeys": []string{"tekton.dev/pipeline"}},
+		{"namespace": namespaceDefault, "reason": "PipelineRunTimeout,CouldntGetTask"},
+		{"namespace": "-", "team": "Payments", "reason": "Failed"},
+		{"namespace": namespaceDefault, "status": "failed"},
+		{"namespace": "-", "createdAfter": "24h"},
+		{"namespace": namespaceDefault, "createdAfter": "7d", "minDurationSeconds": 1800},
+		{"namespace": namespaceDefault, "orderBy": "completion_time desc", "limit": 5},
+		{"namespace": "-", "createdAfter": "24h", "groupBy": "pipeline"},
+		{"namespace": namespaceDefault, "limit": 20, "output": "markdown"},
+		{"namespace": "-", "createdAfter": "7d", "output": "csv", "labelKeys": []string{"tekton.dev/pipeline"}},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args listParams) (*mcp.CallToolResult, error) {
+		if err := validateListOutput(args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		ns := normalizeNamespace(args.Namespace, namespaceDefault)
+		createdAfter, createdBefore, err := parseCreatedRange(args.CreatedAfter, args.CreatedBefore, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		opts := tektonresults.ListOptions{
-			Namespace:     ns,
-			LabelSelector: args.LabelSelector,
-			Prefix:        args.Prefix,
-			Limit:         sanitizeLimit(args.Limit),
+			Namespace:          ns,
+			LabelSelector:      args.LabelSelector,
+			AnnotationSelector: args.AnnotationSelector,
+			RefName:            args.Pipeline,
+			ServiceAccount:     args.ServiceAccount,
+			Prefix:             args.Prefix,
+			NameRegex:          args.NameRegex,
+			Reason:             args.Reason,
+			Status:             args.Status,
+			MinDuration:        time.Duration(args.MinDurationSeconds) * time.Second,
+			MaxDuration:        time.Duration(args.MaxDurationSeconds) * time.Second,
+			CreatedAfter:       createdAfter,
+			CreatedBefore:      createdBefore,
+			Team:               args.Team,
+			LatestOnly:         args.LatestOnly,
+			Limit:              sanitizeLimit(args.Limit),
+			OrderBy:            args.OrderBy,
+			PageToken:          args.PageToken,
 		}
 
-		summaries, err := deps.Service.ListPipelineRuns(ctx, opts)
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+		if args.GroupBy != "" {
+			return groupResult(ctx, req, args, "PipelineRun", deps.Service.ListPipelineRunPage, opts), nil
 		}
-		payload, err := json.MarshalIndent(summaries, "", "  ")
+		page, err := deps.Service.ListPipelineRunPage(ctx, opts)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		return mcp.NewToolResultText(string(payload)), nil
+		return listResult(req, args, page), nil
 	})
 
 	return server.ServerTool{
@@ -108,86 +224,103 @@ func newPipelineRunListTool(deps Dependencies) server.ServerTool {
 	}
 }
 
+func pageTokenOption() mcp.ToolOption {
+	return mcp.WithString("pageToken",
+		mcp.Description("Continue a listing: pass the page token returned by the previous call, with otherwise the same arguments. limit may change between pages. Not supported with a comma separated list of namespaces."),
+		mcp.DefaultString(""),
+	)
+}
+
+// listResult renders a page of run summaries as a JSON array, or as a table
+// or CSV for the other outputs. When more runs match, a second text
+// item carries the token of the next page.
+func listResult(req mcp.CallToolRequest, args listParams, page *tektonresults.RunPage) *mcp.CallToolResult {
+	var text string
+	switch strings.TrimSpace(args.Output) {
+	case "table":
+		text = renderRunTable(page.Runs)
+	case "markdown":
+		text = renderRunMarkdown(page.Runs)
+	case "csv":
+		var err error
+		if text, err = renderRunCSV(page.Runs, args.LabelKeys); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err))
+		}
+	default:
+		projectLabels(page.Runs, req.GetBool("includeLabels", true), args.LabelKeys)
+		payload, err := json.MarshalIndent(page.Runs, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err))
+		}
+		text = string(payload)
+	}
+	result := mcp.NewToolResultText(text)
+	if note := partialNote(page); note != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(note))
+	} else if page.NextPageToken != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Note: more runs match. To continue, call again with the same arguments and pageToken %q.", page.NextPageToken)))
+	}
+	return result
+}
+
+// partialScanNote explains a page whose scan stopped after the page budget.
+const partialScanNote = "Note: the scan stopped after its page budget, as filters such as serviceAccount, nameRegex, reason, durations or key!=value and !key label clauses are applied after reading each page. More runs may match."
+
+// partialNote tells how to go on after a partial page, or returns "" for a
+// complete page.
+func partialNote(page *tektonresults.RunPage) string {
+	switch {
+	case !page.Partial:
+		return ""
+	case page.NextPageToken == "":
+		// Listings across several namespaces have no token to continue.
+		return partialScanNote + " List each namespace on its own to continue with a pageToken, or narrow the query."
+	default:
+		return fmt.Sprintf("%s To continue, call again with the same arguments and pageToken %q, or narrow the query.", partialScanNote, page.NextPageToken)
+	}
+}
+
 func newPipelineRunGetTool(deps Dependencies) server.ServerTool {
 	namespaceDefault := deps.DefaultNamespace
 	if namespaceDefault == "" {
 		namespaceDefault = "default"
 	}
 
-	tool := mcp.NewTool(
-		"pipelinerun_get",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription("Get a Tekton PipelineRun stored in Tekton Results. Provide a name for exact match or combine labelSelector/prefix to narrow results. Returns the full resource in YAML (default) or JSON format."),
 		mcp.WithToolAnnotation(readOnlyAnnotations("Get PipelineRun")),
-		mcp.WithString("name",
-			mcp.Description("Exact PipelineRun name. Optional if labelSelector/prefix uniquely identify a run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("namespace",
-			mcp.Description("Kubernetes namespace that owns the PipelineRun. Use '-' to search across namespaces."),
-			mcp.DefaultString(namespaceDefault),
-		),
-		mcp.WithString("labelSelector",
-			mcp.Description("Comma separated key=value selectors that must match run labels."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("prefix",
-			mcp.Description("Optional PipelineRun name prefix to disambiguate."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("uid",
-			mcp.Description("Exact PipelineRun UID (unique identifier in Tekton Results database). This is the most efficient way to find a specific run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("output",
-			mcp.Description("Return format: 'yaml' (default) or 'json'."),
-			mcp.DefaultString("yaml"),
-		),
-		mcp.WithBoolean("selectLast",
-			mcp.Description("If true, automatically select the last (most recent) match when multiple PipelineRuns match the filters. Defaults to true."),
-			mcp.DefaultBool(true),
-		),
-	)
-
-	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args getParams) (*mcp.CallToolResult, error) {
-		if args.Name == "" && args.Prefix == "" && args.UID == "" && strings.TrimSpace(args.LabelSelector) == "" {
-			return mcp.NewToolResultError("provide at least one of name, prefix, uid, or labelSelector to identify a PipelineRun"), nil
-		}
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+	opts = append(opts, manifestOptions()...)
 
-		// Default selectLast to true if not explicitly provided
-		selectLast := true
-		if params, ok := req.Params.Arguments.(map[string]interface{}); ok {
-			if val, exists := params["selectLast"]; exists {
-				if boolVal, ok := val.(bool); ok {
-					selectLast = boolVal
-				}
-			}
-		}
+	tool := newTool("pipelinerun_get", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"uid": "0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11", "namespace": namespaceDefault, "output": "json"},
+		{"prefix": "build-pipeline-run-", "selectLast": true},
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault, "includeSummary": true},
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault, "depth": "status"},
+	}, opts...)
 
-		ns := normalizeNamespace(args.Namespace, namespaceDefault)
-		selector := tektonresults.RunSelector{
-			Namespace:     ns,
-			LabelSelector: args.LabelSelector,
-			Prefix:        args.Prefix,
-			Name:          args.Name,
-			UID:           args.UID,
-			SelectLast:    selectLast,
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args getParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
 		}
+		selector := args.runSelector(req, namespaceDefault)
 
 		detail, err := deps.Service.GetPipelineRun(ctx, selector)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
-		}
-
-		output := strings.ToLower(strings.TrimSpace(args.Output))
-		if output == "" {
-			output = "yaml"
+			return deps.runError("PipelineRun", err), nil
 		}
 
-		formatted, err := detail.Format(output)
+		lookupCheck(ctx, deps.Service, detail, args.manifestParams)
+		result, err := manifestResult("PipelineRun", detail, args.manifestParams)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		return mcp.NewToolResultText(formatted), nil
+		if note := historyNote("PipelineRun", detail); note != "" {
+			result.Content = append(result.Content, mcp.NewTextContent(note))
+		}
+		return result, nil
 	})
 
 	return server.ServerTool{
@@ -202,86 +335,77 @@ func newPipelineRunLogsTool(deps Dependencies) server.ServerTool {
 		namespaceDefault = "default"
 	}
 
-	tool := mcp.NewTool(
-		"pipelinerun_logs",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription("Retrieve stored logs for a completed Tekton PipelineRun."),
 		mcp.WithToolAnnotation(readOnlyAnnotations("PipelineRun Logs")),
-		mcp.WithString("name",
-			mcp.Description("Exact PipelineRun name. Optional if labelSelector/prefix uniquely identify a run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("namespace",
-			mcp.Description("Kubernetes namespace for the PipelineRun. Use '-' to search all namespaces."),
-			mcp.DefaultString(namespaceDefault),
-		),
-		mcp.WithString("labelSelector",
-			mcp.Description("Comma separated key=value selectors that must match run labels."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("prefix",
-			mcp.Description("Optional PipelineRun name prefix when multiple runs share similar names."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("uid",
-			mcp.Description("Exact PipelineRun UID (unique identifier in Tekton Results database). This is the most efficient way to find a specific run."),
-			mcp.DefaultString(""),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+	opts = append(opts, mcp.WithString("output",
+		mcp.Description("Output format: 'text' concatenates TaskRun logs under headers, 'json' returns an array of {taskRun, pipelineTask, status, started, completed, logs|error} objects, 'slack' returns Slack mrkdwn with a status line per TaskRun and the end of the logs of TaskRuns that did not succeed, split into items that each fit a Slack section block."),
+		mcp.DefaultString("text"),
+		mcp.Enum(logFormats...),
+	))
+	opts = append(opts,
+		mcp.WithArray("tasks",
+			mcp.Description("Only include the TaskRuns of these pipeline tasks (the tekton.dev/pipelineTask label), e.g. ['build', 'deploy']. TaskRun names are accepted too."),
+			mcp.WithStringItems(),
+			examples([]string{"build", "deploy"}),
 		),
-		mcp.WithBoolean("selectLast",
-			mcp.Description("If true, automatically select the last (most recent) match when multiple PipelineRuns match the filters. Defaults to true."),
-			mcp.DefaultBool(true),
+		mcp.WithBoolean("failedOnly",
+			mcp.Description("Only include TaskRuns that failed, which is usually where the relevant output is."),
+			mcp.DefaultBool(false),
 		),
+		containerOption(),
 	)
 
-	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args logsParams) (*mcp.CallToolResult, error) {
-		if args.Name == "" && args.Prefix == "" && strings.TrimSpace(args.LabelSelector) == "" {
-			return mcp.NewToolResultError("provide at least one of name, prefix, uid, or labelSelector to target a PipelineRun"), nil
+	tool := newTool("pipelinerun_logs", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"uid": "0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11", "namespace": namespaceDefault, "output": "json"},
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault, "failedOnly": true},
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault, "tasks": []string{"build", "deploy"}},
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault, "container": "!sidecars"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args pipelineRunLogsParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
 		}
-
-		// Default selectLast to true if not explicitly provided
-		selectLast := true
-		if params, ok := req.Params.Arguments.(map[string]interface{}); ok {
-			if val, exists := params["selectLast"]; exists {
-				if boolVal, ok := val.(bool); ok {
-					selectLast = boolVal
-				}
-			}
+		output := strings.ToLower(strings.TrimSpace(args.Output))
+		if output == "" {
+			output = "text"
 		}
-
-		ns := normalizeNamespace(args.Namespace, namespaceDefault)
-		selector := tektonresults.RunSelector{
-			Namespace:     ns,
-			LabelSelector: args.LabelSelector,
-			Prefix:        args.Prefix,
-			Name:          args.Name,
-			UID:           args.UID,
-			SelectLast:    selectLast,
+		if !slices.Contains(logFormats, output) {
+			return mcp.NewToolResultError("output must be one of 'text', 'json' or 'slack'"), nil
 		}
+		selector := args.runSelector(req, namespaceDefault)
 
 		detail, err := deps.Service.GetPipelineRun(ctx, selector)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return deps.runError("PipelineRun", err), nil
 		}
 
 		if !detail.Completed() {
-			return mcp.NewToolResultError("logs are only available after the PipelineRun has completed"), nil
-		}
-
-		// Fetch all TaskRuns for this PipelineRun using the UID (result ID)
-		// This is more reliable than using the name, as names can be reused over time
-		taskRunOpts := tektonresults.ListOptions{
-			Namespace:     ns,
-			LabelSelector: fmt.Sprintf("tekton.dev/pipelineRunUID=%s", detail.Summary.UID),
-			Limit:         200, // Maximum allowed
+			return mcp.NewToolResultError(deps.Messages.Format(messages.LogsNotCompleted, map[string]string{"Kind": "PipelineRun"})), nil
 		}
 
-		taskRuns, err := deps.Service.ListTaskRuns(ctx, taskRunOpts)
+		taskRuns, err := deps.childTaskRuns(ctx, detail.Summary)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list TaskRuns: %v", err)), nil
 		}
 
 		if len(taskRuns) == 0 {
+			// A PipelineRun rejected for its configuration never created
+			// TaskRuns, so the condition is the whole story.
+			if failure := detail.ConfigFailure(); failure != nil {
+				return mcp.NewToolResultText(diagnosisText(failure, detail.Summary)), nil
+			}
 			return mcp.NewToolResultText("No TaskRuns found for this PipelineRun"), nil
 		}
+		selected := filterTaskRuns(taskRuns, args.Tasks, args.FailedOnly)
+		if len(selected) == 0 {
+			return mcp.NewToolResultText(noTaskRunsSelected(taskRuns, args.Tasks, args.FailedOnly)), nil
+		}
+		taskRuns = selected
 
 		// Sort TaskRuns by completion time, then by start time
 		sort.Slice(taskRuns, func(i, j int) bool {
@@ -298,38 +422,67 @@ func newPipelineRunLogsTool(deps Dependencies) server.ServerTool {
 			return false
 		})
 
-		// Fetch logs for each TaskRun
-		var logsBuilder strings.Builder
-		for i, tr := range taskRuns {
-			if i > 0 {
-				logsBuilder.WriteString("\n\n")
+		// Fetch logs for each TaskRun. Once the server turns out to store no
+		// logs, the remaining TaskRuns are listed without asking again.
+		entries := make([]taskRunLog, 0, len(taskRuns))
+		logsDisabled := false
+		containers := parseContainerFilter(args.Container)
+		var notes []string
+		for _, tr := range taskRuns {
+			entry := taskRunLog{
+				TaskRun:      tr.Name,
+				PipelineTask: tr.PipelineTask,
+				Status:       runState(tr),
+				run:          tr,
 			}
-			logsBuilder.WriteString("========================================\n")
-			logsBuilder.WriteString(fmt.Sprintf("TaskRun: %s\n", tr.Name))
-			logsBuilder.WriteString(fmt.Sprintf("Status: %s", tr.Reason))
 			if tr.StartTime != nil {
-				logsBuilder.WriteString(fmt.Sprintf(" | Started: %s", tr.StartTime.Format("2006-01-02T15:04:05Z")))
+				entry.Started = format.Timestamp(tr.StartTime.Time)
 			}
 			if tr.CompletionTime != nil {
-				logsBuilder.WriteString(fmt.Sprintf(" | Completed: %s", tr.CompletionTime.Format("2006-01-02T15:04:05Z")))
+				entry.Completed = format.Timestamp(tr.CompletionTime.Time)
+				entry.duration = runDuration(tr)
 			}
-			logsBuilder.WriteString("\n========================================\n")
 
-			taskLogs, err := deps.Service.FetchLogs(ctx, tr.RecordName)
-			if err != nil {
-				logsBuilder.WriteString(fmt.Sprintf("Error fetching logs: %v\n", err))
-			} else if taskLogs == "" {
-				logsBuilder.WriteString("(no logs available)\n")
-			} else {
-				logsBuilder.WriteString(taskLogs)
-				// Ensure logs end with newline
-				if !strings.HasSuffix(taskLogs, "\n") {
-					logsBuilder.WriteString("\n")
+			if !logsDisabled {
+				taskLogs, err := deps.Service.FetchLogs(ctx, tr.RecordName)
+				switch {
+				case errors.Is(err, tektonresults.ErrLogsDisabled):
+					logsDisabled = true
+				case err != nil:
+					entry.Error = err.Error()
+				default:
+					var note string
+					entry.Logs, note = containerLogs(taskLogs, nil, containers)
+					if note != "" {
+						notes = append(notes, fmt.Sprintf("%s: %s", tr.Name, note))
+					}
 				}
 			}
+			if logsDisabled {
+				entry.Error = logsDisabledError
+			}
+			entries = append(entries, entry)
 		}
 
-		return mcp.NewToolResultText(logsBuilder.String()), nil
+		if output == "json" {
+			payload, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to encode logs: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(payload)), nil
+		}
+		if output == "slack" {
+			lookupCheck(ctx, deps.Service, detail, manifestParams{Output: output})
+			return slackResult(append([]string{slackRunSummary("PipelineRun", detail)}, slackTaskRunLogs(entries)...)), nil
+		}
+		text := renderTaskRunLogs(entries)
+		if len(notes) > 0 {
+			text = "Note: " + strings.Join(notes, "\nNote: ") + "\n\n" + text
+		}
+		if logsDisabled {
+			text = "Note: " + deps.Messages.Format(messages.LogsDisabled, nil) + "\n\n" + text
+		}
+		return mcp.NewToolResultText(text), nil
 	})
 
 	return server.ServerTool{
@@ -338,6 +491,51 @@ func newPipelineRunLogsTool(deps Dependencies) server.ServerTool {
 	}
 }
 
+// filterTaskRuns keeps the TaskRuns whose pipeline task or name is listed in
+// tasks (all of them when tasks is empty) and, with failedOnly, that failed.
+func filterTaskRuns(taskRuns []tektonresults.RunSummary, tasks []string, failedOnly bool) []tektonresults.RunSummary {
+	wanted := map[string]bool{}
+	for _, task := range tasks {
+		if task = strings.TrimSpace(task); task != "" {
+			wanted[task] = true
+		}
+	}
+	var selected []tektonresults.RunSummary
+	for _, tr := range taskRuns {
+		if len(wanted) > 0 && !wanted[tr.PipelineTask] && !wanted[tr.Name] {
+			continue
+		}
+		if failedOnly && tr.Status != "False" {
+			continue
+		}
+		selected = append(selected, tr)
+	}
+	return selected
+}
+
+// noTaskRunsSelected explains an empty selection and lists the TaskRuns
+// that were available, so the next call can pick valid tasks.
+func noTaskRunsSelected(taskRuns []tektonresults.RunSummary, tasks []string, failedOnly bool) string {
+	var b strings.Builder
+	switch {
+	case len(tasks) > 0 && failedOnly:
+		fmt.Fprintf(&b, "No failed TaskRuns for tasks %s in this PipelineRun.", strings.Join(tasks, ", "))
+	case len(tasks) > 0:
+		fmt.Fprintf(&b, "No TaskRuns for tasks %s in this PipelineRun.", strings.Join(tasks, ", "))
+	default:
+		b.WriteString("No TaskRuns failed in this PipelineRun.")
+	}
+	b.WriteString(" Available TaskRuns:\n")
+	for _, tr := range taskRuns {
+		name := tr.Name
+		if tr.PipelineTask != "" {
+			name = fmt.Sprintf("%s (%s)", tr.PipelineTask, tr.Name)
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", name, runState(tr))
+	}
+	return b.String()
+}
+
 func sanitizeLimit(limit int) int {
 	if limit <= 0 {
 		return defaultListLimit
@@ -347,3 +545,43 @@ func sanitizeLimit(limit int) int {
 	}
 	return limit
 }
+
+// renderTaskRunLogs concatenates TaskRun logs, each under a header with its
+// status and timing.
+func renderTaskRunLogs(entries []taskRunLog) string {
+	var logsBuilder strings.Builder
+	for i, entry := range entries {
+		if i > 0 {
+			logsBuilder.WriteString("\n\n")
+		}
+		logsBuilder.WriteString("========================================\n")
+		logsBuilder.WriteString(fmt.Sprintf("TaskRun: %s\n", entry.TaskRun))
+		if entry.PipelineTask != "" {
+			logsBuilder.WriteString(fmt.Sprintf("Pipeline Task: %s\n", entry.PipelineTask))
+		}
+		logsBuilder.WriteString(fmt.Sprintf("Status: %s", entry.Status))
+		if entry.Started != "" {
+			logsBuilder.WriteString(fmt.Sprintf(" | Started: %s", entry.Started))
+		}
+		if entry.Completed != "" {
+			logsBuilder.WriteString(fmt.Sprintf(" | Completed: %s", entry.Completed))
+			logsBuilder.WriteString(fmt.Sprintf(" | Duration: %s", entry.duration))
+		}
+		logsBuilder.WriteString("\n========================================\n")
+
+		switch {
+		case entry.Error != "":
+			logsBuilder.WriteString(fmt.Sprintf("Error fetching logs: %s\n", entry.Error))
+		case entry.Logs == "":
+			logsBuilder.WriteString("(no logs available)\n")
+		default:
+			logsBuilder.WriteString(fmt.Sprintf("(%s of logs)\n", format.Bytes(int64(len(entry.Logs)))))
+			logsBuilder.WriteString(entry.Logs)
+			// Ensure logs end with newline
+			if !strings.HasSuffix(entry.Logs, "\n") {
+				logsBuilder.WriteString("\n")
+			}
+		}
+	}
+	return logsBuilder.String()
+}
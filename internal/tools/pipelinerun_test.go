@@ -3,6 +3,9 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -14,11 +17,25 @@ import (
 
 // mockService is a mock implementation of Service interface for testing
 type mockPipelineRunService struct {
-	listPipelineRunsFunc func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
-	listTaskRunsFunc     func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
-	getPipelineRunFunc   func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
-	getTaskRunFunc       func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
-	fetchLogsFunc        func(ctx context.Context, recordName string) (string, error)
+	listPipelineRunsFunc    func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
+	listTaskRunsFunc        func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
+	listPipelineRunPageFunc func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error)
+	listTaskRunPageFunc     func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error)
+	getPipelineRunFunc      func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
+	getTaskRunFunc          func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
+	getRunByRecordFunc      func(ctx context.Context, recordName string) (*tektonresults.RunDetail, error)
+	runsSinceFunc           func(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error)
+	pipelineRunStatsFunc    func(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.RunStats, error)
+	taskRunStatsFunc        func(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.TaskRunStats, error)
+	listResultRecordsFunc   func(ctx context.Context, name string) (*tektonresults.ResultRecords, error)
+	fetchLogsFunc           func(ctx context.Context, recordName string) (string, error)
+	serverInfoFunc          func(ctx context.Context, refresh bool) tektonresults.ServerInfo
+	backendInfoFunc         func(ctx context.Context, namespace string) tektonresults.BackendInfo
+	checkStatusFunc         func(ctx context.Context, change tektonresults.SourceChange) (*tektonresults.CheckStatus, error)
+	pruneResultsFunc        func(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error)
+	rerunPipelineRunFunc    func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RerunResult, error)
+	cancelPipelineRunFunc   func(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error)
+	upstreamPressureFunc    func() tektonresults.UpstreamPressure
 }
 
 func (m *mockPipelineRunService) ListPipelineRuns(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
@@ -35,6 +52,30 @@ func (m *mockPipelineRunService) ListTaskRuns(ctx context.Context, opts tektonre
 	return nil, nil
 }
 
+// ListPipelineRunPage falls back to listPipelineRunsFunc, returning a single
+// page, when the test does not page.
+func (m *mockPipelineRunService) ListPipelineRunPage(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+	if m.listPipelineRunPageFunc != nil {
+		return m.listPipelineRunPageFunc(ctx, opts)
+	}
+	runs, err := m.ListPipelineRuns(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tektonresults.RunPage{Runs: runs}, nil
+}
+
+func (m *mockPipelineRunService) ListTaskRunPage(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+	if m.listTaskRunPageFunc != nil {
+		return m.listTaskRunPageFunc(ctx, opts)
+	}
+	runs, err := m.ListTaskRuns(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tektonresults.RunPage{Runs: runs}, nil
+}
+
 func (m *mockPipelineRunService) GetPipelineRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
 	if m.getPipelineRunFunc != nil {
 		return m.getPipelineRunFunc(ctx, selector)
@@ -49,6 +90,41 @@ func (m *mockPipelineRunService) GetTaskRun(ctx context.Context, selector tekton
 	return nil, nil
 }
 
+func (m *mockPipelineRunService) GetRunByRecord(ctx context.Context, recordName string) (*tektonresults.RunDetail, error) {
+	if m.getRunByRecordFunc != nil {
+		return m.getRunByRecordFunc(ctx, recordName)
+	}
+	return nil, nil
+}
+
+func (m *mockPipelineRunService) RunsSince(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error) {
+	if m.runsSinceFunc != nil {
+		return m.runsSinceFunc(ctx, opts)
+	}
+	return &tektonresults.SinceResult{}, nil
+}
+
+func (m *mockPipelineRunService) PipelineRunStats(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.RunStats, error) {
+	if m.pipelineRunStatsFunc != nil {
+		return m.pipelineRunStatsFunc(ctx, opts)
+	}
+	return &tektonresults.RunStats{}, nil
+}
+
+func (m *mockPipelineRunService) TaskRunStats(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.TaskRunStats, error) {
+	if m.taskRunStatsFunc != nil {
+		return m.taskRunStatsFunc(ctx, opts)
+	}
+	return &tektonresults.TaskRunStats{RunStats: &tektonresults.RunStats{}}, nil
+}
+
+func (m *mockPipelineRunService) ListResultRecords(ctx context.Context, name string) (*tektonresults.ResultRecords, error) {
+	if m.listResultRecordsFunc != nil {
+		return m.listResultRecordsFunc(ctx, name)
+	}
+	return &tektonresults.ResultRecords{}, nil
+}
+
 func (m *mockPipelineRunService) FetchLogs(ctx context.Context, recordName string) (string, error) {
 	if m.fetchLogsFunc != nil {
 		return m.fetchLogsFunc(ctx, recordName)
@@ -56,6 +132,63 @@ func (m *mockPipelineRunService) FetchLogs(ctx context.Context, recordName strin
 	return "", nil
 }
 
+func (m *mockPipelineRunService) PruneResults(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error) {
+	if m.pruneResultsFunc != nil {
+		return m.pruneResultsFunc(ctx, opts)
+	}
+	return &tektonresults.PruneReport{}, nil
+}
+
+func (m *mockPipelineRunService) RerunPipelineRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RerunResult, error) {
+	if m.rerunPipelineRunFunc != nil {
+		return m.rerunPipelineRunFunc(ctx, selector)
+	}
+	return nil, nil
+}
+
+func (m *mockPipelineRunService) CancelPipelineRun(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error) {
+	if m.cancelPipelineRunFunc != nil {
+		return m.cancelPipelineRunFunc(ctx, selector, mode)
+	}
+	return nil, nil
+}
+
+func (m *mockPipelineRunService) ServerInfo(ctx context.Context, refresh bool) tektonresults.ServerInfo {
+	if m.serverInfoFunc != nil {
+		return m.serverInfoFunc(ctx, refresh)
+	}
+	return tektonresults.ServerInfo{}
+}
+
+func (m *mockPipelineRunService) UpstreamStats() tektonresults.UpstreamStats {
+	return tektonresults.UpstreamStats{}
+}
+
+func (m *mockPipelineRunService) UpstreamPressure() tektonresults.UpstreamPressure {
+	if m.upstreamPressureFunc != nil {
+		return m.upstreamPressureFunc()
+	}
+	return tektonresults.UpstreamPressure{}
+}
+
+func (m *mockPipelineRunService) BackendInfo(ctx context.Context, namespace string) tektonresults.BackendInfo {
+	if m.backendInfoFunc != nil {
+		return m.backendInfoFunc(ctx, namespace)
+	}
+	return tektonresults.BackendInfo{}
+}
+
+func (m *mockPipelineRunService) CheckStatus(ctx context.Context, change tektonresults.SourceChange) (*tektonresults.CheckStatus, error) {
+	if m.checkStatusFunc != nil {
+		return m.checkStatusFunc(ctx, change)
+	}
+	return nil, nil
+}
+
+func (m *mockPipelineRunService) CacheStats() map[string]tektonresults.CacheStats {
+	return nil
+}
+
 // getTextFromResult extracts text from CallToolResult for testing
 func getTextFromResult(result *mcp.CallToolResult) string {
 	if len(result.Content) == 0 {
@@ -116,9 +249,30 @@ func TestPipelineRunList_CustomParameters(t *testing.T) {
 			if opts.Prefix != "my-pr" {
 				t.Errorf("Expected prefix 'my-pr', got %s", opts.Prefix)
 			}
+			if opts.AnnotationSelector != "pipelinesascode.tekton.dev/branch=main" {
+				t.Errorf("Expected annotationSelector 'pipelinesascode.tekton.dev/branch=main', got %s", opts.AnnotationSelector)
+			}
+			if opts.MinDuration != 30*time.Minute || opts.MaxDuration != 2*time.Hour {
+				t.Errorf("Expected durations 30m-2h, got %s-%s", opts.MinDuration, opts.MaxDuration)
+			}
+			if opts.ServiceAccount != "deployer" {
+				t.Errorf("Expected service account 'deployer', got %s", opts.ServiceAccount)
+			}
+			if !opts.LatestOnly {
+				t.Error("Expected latestOnly to be passed through")
+			}
+			if opts.RefName != "build" {
+				t.Errorf("Expected pipeline 'build', got %s", opts.RefName)
+			}
+			if opts.NameRegex != "-[0-9]+$" {
+				t.Errorf("Expected nameRegex '-[0-9]+$', got %s", opts.NameRegex)
+			}
 			if opts.Limit != 10 {
 				t.Errorf("Expected limit 10, got %d", opts.Limit)
 			}
+			if opts.OrderBy != "update_time asc" {
+				t.Errorf("Expected orderBy 'update_time asc', got %s", opts.OrderBy)
+			}
 			return []tektonresults.RunSummary{}, nil
 		},
 	}
@@ -128,10 +282,18 @@ func TestPipelineRunList_CustomParameters(t *testing.T) {
 
 	req := mcp.CallToolRequest{}
 	req.Params.Arguments = map[string]any{
-		"namespace":     "all",
-		"labelSelector": "app=test",
-		"prefix":        "my-pr",
-		"limit":         float64(10), // JSON numbers are float64
+		"namespace":          "all",
+		"labelSelector":      "app=test",
+		"prefix":             "my-pr",
+		"nameRegex":          "-[0-9]+$",
+		"pipeline":           "build",
+		"serviceAccount":     "deployer",
+		"latestOnly":         true,
+		"minDurationSeconds": 1800,
+		"maxDurationSeconds": 7200,
+		"annotationSelector": "pipelinesascode.tekton.dev/branch=main",
+		"orderBy":            "update_time asc",
+		"limit":              float64(10), // JSON numbers are float64
 	}
 
 	_, err := tool.Handler(context.Background(), req)
@@ -177,6 +339,101 @@ func TestPipelineRunList_LimitSanitization(t *testing.T) {
 	}
 }
 
+func TestPipelineRunList_LabelProjection(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return []tektonresults.RunSummary{{
+				Name:   "pr-1",
+				Labels: map[string]string{"tekton.dev/pipeline": "build", "app": "web", "internal.ci/trigger-id": "42"},
+			}}, nil
+		},
+	}
+	tool := newPipelineRunListTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want map[string]string
+	}{
+		{"default keeps all labels", map[string]any{}, map[string]string{"tekton.dev/pipeline": "build", "app": "web", "internal.ci/trigger-id": "42"}},
+		{"includeLabels false", map[string]any{"includeLabels": false}, nil},
+		{"labelKeys", map[string]any{"labelKeys": []any{"app", "missing"}}, map[string]string{"app": "web"}},
+		{"labelKeys without matches", map[string]any{"labelKeys": []any{"missing"}}, nil},
+		{"includeLabels wins", map[string]any{"includeLabels": false, "labelKeys": []any{"app"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := tool.Handler(context.Background(), req)
+			if err != nil || result.IsError {
+				t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+			}
+			var summaries []tektonresults.RunSummary
+			if err := json.Unmarshal([]byte(getTextFromResult(result)), &summaries); err != nil {
+				t.Fatalf("Response is not JSON: %v", err)
+			}
+			if !reflect.DeepEqual(summaries[0].Labels, tt.want) {
+				t.Errorf("Labels = %v, want %v", summaries[0].Labels, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineRunList_PageToken(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			if opts.PageToken == "" {
+				return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{{Name: "pr-2"}}, NextPageToken: "next-token"}, nil
+			}
+			if opts.PageToken != "next-token" {
+				t.Errorf("Expected the returned page token, got %q", opts.PageToken)
+			}
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{{Name: "pr-1"}}}, nil
+		},
+	}
+	tool := newPipelineRunListTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"limit": 1}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, `pageToken "next-token"`) {
+		t.Fatalf("Expected a note with the next page token, got %+v", result.Content)
+	}
+
+	req.Params.Arguments = map[string]any{"limit": 1, "pageToken": "next-token"}
+	result, err = tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	if len(result.Content) != 1 || !strings.Contains(getTextFromResult(result), "pr-1") {
+		t.Errorf("Expected the last page without a note, got %+v", result.Content)
+	}
+}
+
+func TestPipelineRunList_PartialAcrossNamespaces(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{{Name: "pr-1"}}, Partial: true}, nil
+		},
+	}
+	tool := newPipelineRunListTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"namespace": "ci,prod", "labelSelector": "!canary"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	if len(result.Content) != 2 || !strings.Contains(getTextFromResult(result), "pr-1") ||
+		!strings.Contains(result.Content[1].(mcp.TextContent).Text, "List each namespace on its own") {
+		t.Errorf("Expected the runs found and a truncation note, got %+v", result.Content)
+	}
+}
+
 func TestPipelineRunList_ServiceError(t *testing.T) {
 	mock := &mockPipelineRunService{
 		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
@@ -375,6 +632,37 @@ func TestPipelineRunGet_ServiceError(t *testing.T) {
 	}
 }
 
+func TestPipelineRunGet_HistoryNote(t *testing.T) {
+	oldest := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	newest := metav1.NewTime(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Raw:     json.RawMessage(`{"metadata":{"name":"nightly"}}`),
+				History: &tektonresults.MatchHistory{Matches: 7, Oldest: &oldest, Newest: &newest},
+			}, nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "default"}
+	tool := newPipelineRunGetTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "nightly"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected manifest and history note, got %d content blocks", len(result.Content))
+	}
+	note, _ := mcp.AsTextContent(result.Content[1])
+	if note == nil || !strings.Contains(note.Text, "most recent of 7 PipelineRuns") || !strings.Contains(note.Text, "2024-01-01T00:00:00Z") {
+		t.Errorf("Unexpected history note: %+v", note)
+	}
+}
+
 func TestPipelineRunLogs_ByName(t *testing.T) {
 	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 	mock := &mockPipelineRunService{
@@ -427,6 +715,159 @@ func TestPipelineRunLogs_ByName(t *testing.T) {
 	}
 }
 
+func TestPipelineRunLogs_LogsDisabled(t *testing.T) {
+	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fetches := 0
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{Summary: tektonresults.RunSummary{UID: "pr-uid", CompletionTime: &completionTime}}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return []tektonresults.RunSummary{
+				{Name: "build", RecordName: "ns/results/pr-uid/records/tr-1"},
+				{Name: "test", RecordName: "ns/results/pr-uid/records/tr-2"},
+			}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			fetches++
+			return "", tektonresults.ErrLogsDisabled
+		},
+	}
+
+	tool := newPipelineRunLogsTool(Dependencies{Service: mock, DefaultNamespace: "ns"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "my-pipeline"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError || !strings.HasPrefix(text, "Note: log storage is not enabled on this Results server") {
+		t.Errorf("Expected a note that log storage is disabled, got: %s", text)
+	}
+	if strings.Count(text, "Error fetching logs: "+logsDisabledError) != 2 {
+		t.Errorf("Expected both TaskRuns listed without logs, got: %s", text)
+	}
+	if fetches != 1 {
+		t.Errorf("Expected fetching to stop after the first answer, got %d fetches", fetches)
+	}
+}
+
+func TestPipelineRunLogs_HeaderFormatting(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 11, 0, 0, 0, time.FixedZone("CET", 3600)))
+	end := metav1.NewTime(start.Add(time.Hour + 4*time.Minute))
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{UID: "pr-uid", CompletionTime: &end},
+			}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return []tektonresults.RunSummary{
+				{Name: "tr-1", PipelineTask: "compile", Reason: "Succeeded", StartTime: &start, CompletionTime: &end},
+			}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			return strings.Repeat("x", 2048), nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "test-ns"}
+	tool := newPipelineRunLogsTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "my-pipeline"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+
+	text := getTextFromResult(result)
+	for _, want := range []string{"Pipeline Task: compile", "Started: 2024-01-01T10:00:00Z", "Completed: 2024-01-01T11:04:00Z", "Duration: 1h 4m", "(2.0 KiB of logs)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected header to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestPipelineRunLogs_JSONOutput(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(time.Minute))
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{UID: "pr-uid", CompletionTime: &end},
+			}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return []tektonresults.RunSummary{
+				{Name: "tr-1", PipelineTask: "build", Reason: "Succeeded", RecordName: "rec-1", StartTime: &start, CompletionTime: &end},
+				{Name: "tr-2", Reason: "Failed", RecordName: "rec-2"},
+			}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			if recordName == "rec-2" {
+				return "", &testError{msg: "log not found"}
+			}
+			return "build output\n", nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "test-ns"}
+	tool := newPipelineRunLogsTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "my-pipeline", "output": "json"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+
+	var entries []map[string]string
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &entries); err != nil {
+		t.Fatalf("Response is not a JSON array: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	first := entries[0]
+	if first["taskRun"] != "tr-1" || first["pipelineTask"] != "build" || first["status"] != "Succeeded" ||
+		first["started"] != "2024-01-01T10:00:00Z" || first["completed"] != "2024-01-01T10:01:00Z" || first["logs"] != "build output\n" {
+		t.Errorf("Unexpected first entry: %v", first)
+	}
+	if _, ok := first["error"]; ok {
+		t.Errorf("Expected no error on first entry: %v", first)
+	}
+	if entries[1]["error"] != "log not found" {
+		t.Errorf("Expected error on second entry, got %v", entries[1])
+	}
+	if _, ok := entries[1]["logs"]; ok {
+		t.Errorf("Expected no logs on second entry: %v", entries[1])
+	}
+}
+
+func TestPipelineRunLogs_InvalidOutput(t *testing.T) {
+	deps := Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "test-ns"}
+	tool := newPipelineRunLogsTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "my-pipeline", "output": "xml"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("Expected error result for unsupported output")
+	}
+}
+
 func TestPipelineRunLogs_ByUID(t *testing.T) {
 	mock := &mockPipelineRunService{
 		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
@@ -494,6 +935,63 @@ func TestPipelineRunLogs_NoTaskRuns(t *testing.T) {
 	}
 }
 
+func TestPipelineRunLogs_TaskFilters(t *testing.T) {
+	end := metav1.NewTime(time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC))
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{Summary: tektonresults.RunSummary{UID: "pr-uid", CompletionTime: &end}}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return []tektonresults.RunSummary{
+				{Name: "pr-build", PipelineTask: "build", Status: "True", Reason: "Succeeded", RecordName: "rec-build"},
+				{Name: "pr-test", PipelineTask: "test", Status: "False", Reason: "Failed", RecordName: "rec-test"},
+				{Name: "pr-deploy", PipelineTask: "deploy", Status: "True", Reason: "Succeeded", RecordName: "rec-deploy"},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		fetched []string
+		want    string
+	}{
+		{"tasks", map[string]any{"tasks": []any{"build", "deploy"}}, []string{"rec-build", "rec-deploy"}, "Pipeline Task: deploy"},
+		{"taskrun name", map[string]any{"tasks": []any{"pr-test"}}, []string{"rec-test"}, "Pipeline Task: test"},
+		{"failed only", map[string]any{"failedOnly": true}, []string{"rec-test"}, "Status: Failed"},
+		{"failed among tasks", map[string]any{"tasks": []any{"build", "test"}, "failedOnly": true}, []string{"rec-test"}, "Pipeline Task: test"},
+		{"no match", map[string]any{"tasks": []any{"lint"}}, nil, "No TaskRuns for tasks lint in this PipelineRun. Available TaskRuns:\n- build (pr-build): Succeeded\n- test (pr-test): Failed"},
+		{"no failures", map[string]any{"tasks": []any{"build"}, "failedOnly": true}, nil, "No failed TaskRuns for tasks build"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetched []string
+			mock.fetchLogsFunc = func(ctx context.Context, recordName string) (string, error) {
+				fetched = append(fetched, recordName)
+				return "output\n", nil
+			}
+			tool := newPipelineRunLogsTool(Dependencies{Service: mock, DefaultNamespace: "test-ns"})
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"name": "pr"}
+			maps.Copy(req.Params.Arguments.(map[string]any), tt.args)
+			result, err := tool.Handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Handler failed: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("Result is error: %s", getTextFromResult(result))
+			}
+			if !slices.Equal(fetched, tt.fetched) {
+				t.Errorf("Fetched logs of %v, want %v", fetched, tt.fetched)
+			}
+			if text := getTextFromResult(result); !strings.Contains(text, tt.want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.want, text)
+			}
+		})
+	}
+}
+
 func TestPipelineRunLogs_ServiceError(t *testing.T) {
 	mock := &mockPipelineRunService{
 		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
@@ -525,3 +1023,33 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestPipelineRunLogs_ConfigFailure(t *testing.T) {
+	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{UID: "pr-uid", CompletionTime: &completionTime},
+				Raw:     json.RawMessage(`{"kind":"PipelineRun","spec":{"pipelineRef":{"name":"build"}},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"ParameterMissing","message":"PipelineRun ci/build-1 is missing parameters: [revision]"}]}}`),
+			}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return nil, nil
+		},
+	}
+
+	tool := newPipelineRunLogsTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "build-1"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	for _, want := range []string{"Configuration failure: ParameterMissing", "Field: spec.params", "Reference: build", "is missing parameters: [revision]"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in diagnosis, got:\n%s", want, text)
+		}
+	}
+}
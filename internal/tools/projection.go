@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// projectionOptions declares the list tool properties that trim labels from
+// run summaries. Tekton and CI systems attach many internal labels, which
+// otherwise dominate list output.
+func projectionOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithBoolean("includeLabels",
+			mcp.Description("Include run labels in the output. Set to false to drop them entirely."),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithArray("labelKeys",
+			mcp.Description("Only include these label keys, e.g. ['tekton.dev/pipeline', 'app']. Ignored when includeLabels is false."),
+			mcp.WithStringItems(),
+			examples([]string{"tekton.dev/pipeline", "tekton.dev/pipelineTask"}),
+		),
+	}
+}
+
+// projectLabels removes labels from summaries in place: all of them when
+// include is false, otherwise every label not listed in keys. An empty keys
+// list keeps all labels.
+func projectLabels(summaries []tektonresults.RunSummary, include bool, keys []string) {
+	if include && len(keys) == 0 {
+		return
+	}
+	for i := range summaries {
+		if !include {
+			summaries[i].Labels = nil
+			continue
+		}
+		kept := map[string]string{}
+		for _, key := range keys {
+			if v, ok := summaries[i].Labels[key]; ok {
+				kept[key] = v
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		summaries[i].Labels = kept
+	}
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// prompts returns the prompts Add registers.
+func prompts(deps Dependencies) []server.ServerPrompt {
+	return []server.ServerPrompt{newFailuresStandupPrompt(deps)}
+}
+
+func newFailuresStandupPrompt(deps Dependencies) server.ServerPrompt {
+	namespaceDefault := cmp.Or(deps.DefaultNamespace, "default")
+	prompt := mcp.NewPrompt("failures_standup",
+		mcp.WithPromptDescription("Summarize the CI failures of the last day for a standup, using the failures_digest tool."),
+		mcp.WithArgument("namespace",
+			mcp.ArgumentDescription(fmt.Sprintf("Namespace to summarize, a comma separated list, or '-' for all namespaces (default %s).", namespaceDefault)),
+		),
+		mcp.WithArgument("since",
+			mcp.ArgumentDescription("Start of the window, such as 24h or 3d (default "+defaultDigestWindow+")."),
+		),
+		mcp.WithArgument("team",
+			mcp.ArgumentDescription("Only summarize runs owned by this configured team."),
+		),
+	)
+
+	return server.ServerPrompt{
+		Prompt: prompt,
+		Handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			args := map[string]string{
+				"namespace": cmp.Or(strings.TrimSpace(req.Params.Arguments["namespace"]), namespaceDefault),
+				"since":     cmp.Or(strings.TrimSpace(req.Params.Arguments["since"]), defaultDigestWindow),
+			}
+			if team := strings.TrimSpace(req.Params.Arguments["team"]); team != "" {
+				args["team"] = team
+			}
+			text := standupInstructions(args)
+			return mcp.NewGetPromptResult("Failures digest for a standup", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			}), nil
+		},
+	}
+}
+
+// standupInstructions asks the model to call failures_digest for PipelineRuns
+// with args and to turn the groups into a short standup update.
+func standupInstructions(args map[string]string) string {
+	var b strings.Builder
+	b.WriteString("Call the failures_digest tool with")
+	for _, key := range []string{"namespace", "since", "team"} {
+		if v, ok := args[key]; ok {
+			fmt.Fprintf(&b, " %s=%q", key, v)
+		}
+	}
+	b.WriteString(" and write a standup update from its output:\n")
+	b.WriteString("- Open with one sentence giving the number of failed runs and failure groups.\n")
+	b.WriteString("- List the groups most frequent first, one bullet each: Pipeline, reason, count, when it was last seen and the error in plain words.\n")
+	b.WriteString("- Call out groups first seen in the window as new, and groups still failing in the last hours as ongoing.\n")
+	b.WriteString("- Name the sample run of each group so readers can open it; fetch its logs with pipelinerun_logs only when the error line does not explain the failure.\n")
+	b.WriteString("Keep it under 15 lines. If there were no failures, say so in one line.")
+	return b.String()
+}
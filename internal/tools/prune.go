@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+type pruneParams struct {
+	Namespace string `json:"namespace"`
+	OlderThan string `json:"olderThan"`
+	DryRun    bool   `json:"dryRun"`
+	Limit     int    `json:"limit"`
+}
+
+func newResultsPruneTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	tool := newTool(
+		"results_prune",
+		[]toolExample{
+			{"namespace": namespaceDefault, "olderThan": "30d"},
+			{"namespace": namespaceDefault, "olderThan": "720h", "dryRun": false, "limit": 200},
+		},
+		mcp.WithDescription("Delete Tekton Results (runs with their records and logs) in a namespace that were last updated longer ago than olderThan. Runs as a dry run by default and only reports what would be deleted; set dryRun=false to delete."),
+		mcp.WithToolAnnotation(destructiveAnnotations("Prune Results")),
+		mcp.WithString("namespace",
+			mcp.Description("Single Kubernetes namespace to prune. Pruning across namespaces is not supported."),
+			mcp.DefaultString(namespaceDefault),
+		),
+		mcp.WithString("olderThan",
+			mcp.Required(),
+			mcp.Description("Minimum age since the last update, as a duration such as '720h' or a number of days such as '30d'."),
+			examples("30d", "720h"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report the Results that would be deleted, with the Results API requests made to find them and the number of deletions a real run would send. Defaults to true; set to false to delete."),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of Results to prune in this call (1-1000). Oldest Results are pruned first."),
+			mcp.DefaultNumber(100),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args pruneParams) (*mcp.CallToolResult, error) {
+		age, err := parseAge(args.OlderThan)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Never delete unless dryRun=false was passed explicitly.
+		dryRun := true
+		if params, ok := req.Params.Arguments.(map[string]interface{}); ok {
+			if val, ok := params["dryRun"].(bool); ok {
+				dryRun = val
+			}
+		}
+
+		opts := tektonresults.PruneOptions{
+			Namespace: normalizeNamespace(args.Namespace, namespaceDefault),
+			OlderThan: age,
+			DryRun:    dryRun,
+			Limit:     args.Limit,
+			Progress:  progressReporter(ctx, req, "Pruning Results"),
+		}
+		var trace *tektonresults.QueryTrace
+		if dryRun {
+			ctx, trace = tektonresults.WithQueryTrace(ctx)
+		}
+		report, err := deps.Service.PruneResults(ctx, opts)
+		if err != nil && report == nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var out any = report
+		if trace != nil {
+			out = pruneDryRun{PruneReport: report, Requests: append([]tektonresults.TracedRequest{}, trace.Requests()...), PlannedDeletes: len(report.Candidates)}
+		}
+		payload, marshalErr := json.MarshalIndent(out, "", "  ")
+		if marshalErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", marshalErr)), nil
+		}
+		result := mcp.NewToolResultText(string(payload))
+		if err != nil {
+			// Interrupted part way through; report what was already deleted.
+			result.IsError = true
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Pruning stopped early: %v", err)))
+		}
+		return result, nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// pruneDryRun is the output of results_prune with dryRun: the candidates,
+// the requests made to find them and the deletions a real run would send.
+type pruneDryRun struct {
+	*tektonresults.PruneReport
+	Requests       []tektonresults.TracedRequest `json:"requests"`
+	PlannedDeletes int                           `json:"plannedDeletes"` // deleteResult requests, one per candidate
+}
+
+// parseAge parses a Go duration or a whole number of days such as "30d".
+func parseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("olderThan is required")
+	}
+	age, ok := parseDays(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid olderThan %q: expected a duration like 720h or a number of days like 30d", value)
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("olderThan must be positive")
+	}
+	return age, nil
+}
+
+// parseDays parses a Go duration or a whole number of days such as "30d".
+func parseDays(value string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err == nil
+	}
+	d, err := time.ParseDuration(value)
+	return d, err == nil
+}
+
+// progressReporter returns a callback that emits MCP progress notifications
+// when the client asked for them with a progress token, or nil otherwise.
+func progressReporter(ctx context.Context, req mcp.CallToolRequest, message string) func(done, total int) {
+	if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	token := req.Params.Meta.ProgressToken
+	return func(done, total int) {
+		err := srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      done,
+			"total":         total,
+			"message":       message,
+		})
+		if err != nil {
+			slog.Debug("failed to send progress notification", "error", err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResultsPrune_DefaultsToDryRun(t *testing.T) {
+	var got tektonresults.PruneOptions
+	mock := &mockPipelineRunService{
+		pruneResultsFunc: func(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error) {
+			got = opts
+			return &tektonresults.PruneReport{
+				Namespace:  opts.Namespace,
+				DryRun:     opts.DryRun,
+				Candidates: []tektonresults.PruneCandidate{{Name: "ci/results/old"}},
+			}, nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "ci", AllowWrites: true}
+	tool := newResultsPruneTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"olderThan": "30d"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+	if !got.DryRun || got.Namespace != "ci" || got.OlderThan != 30*24*time.Hour {
+		t.Errorf("Unexpected prune options: %+v", got)
+	}
+
+	var report tektonresults.PruneReport
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &report); err != nil {
+		t.Fatalf("Response is not JSON: %v", err)
+	}
+	if !report.DryRun || len(report.Candidates) != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	var plan pruneDryRun
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &plan); err != nil || plan.PlannedDeletes != 1 || plan.Requests == nil {
+		t.Errorf("Expected the planned deletions and the requests made, got %v: %s", err, getTextFromResult(result))
+	}
+}
+
+func TestResultsPrune_ExplicitDelete(t *testing.T) {
+	var got tektonresults.PruneOptions
+	mock := &mockPipelineRunService{
+		pruneResultsFunc: func(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error) {
+			got = opts
+			return &tektonresults.PruneReport{Deleted: 1}, nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "ci", AllowWrites: true}
+	tool := newResultsPruneTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"olderThan": "48h", "dryRun": false, "limit": 5}
+
+	if _, err := tool.Handler(context.Background(), req); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if got.DryRun || got.Limit != 5 || got.OlderThan != 48*time.Hour {
+		t.Errorf("Unexpected prune options: %+v", got)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"720h", 720 * time.Hour, false},
+		{" 90m ", 90 * time.Minute, false},
+		{"", 0, true},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
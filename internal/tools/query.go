@@ -0,0 +1,509 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"sigs.k8s.io/yaml"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// Bounds of one query. They keep a single call from turning into hundreds
+// of Results API lookups.
+const (
+	defaultQueryRuns     = 5
+	maxQueryRuns         = 20
+	defaultQueryTaskRuns = 50
+	maxQueryTaskRuns     = 200
+	defaultQueryTail     = 30
+	maxQueryTail         = 200
+	maxQueryLogs         = 40 // logs fetched per query
+	queryParallelism     = 8  // lookups in flight at once
+)
+
+// queryFields are the fields a query can return for runs and TaskRuns.
+var queryFields = []string{"name", "namespace", "uid", "pipelineTask", "status", "outcome", "reason", "message", "startTime", "completionTime", "duration", "labels", "team", "recordName", "dashboardUrl", "change", "results"}
+
+var (
+	defaultRunFields     = []string{"name", "namespace", "status", "reason", "startTime", "duration"}
+	defaultTaskRunFields = []string{"name", "pipelineTask", "status", "duration"}
+)
+
+type queryParams struct {
+	Request json.RawMessage `json:"request"`
+}
+
+// queryRequest is the declarative request of the query tool.
+type queryRequest struct {
+	Kind     string          `json:"kind"`
+	Filters  queryFilters    `json:"filters"`
+	Fields   []string        `json:"fields"`
+	Limit    int             `json:"limit"`
+	TaskRuns *queryTaskRuns  `json:"taskRuns"`
+	Logs     *queryLogFields `json:"logs"`
+}
+
+type queryFilters struct {
+	Namespace          string `json:"namespace"`
+	Pipeline           string `json:"pipeline"`
+	Task               string `json:"task"`
+	LabelSelector      string `json:"labelSelector"`
+	AnnotationSelector string `json:"annotationSelector"`
+	Prefix             string `json:"prefix"`
+	NameRegex          string `json:"nameRegex"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason"`
+	CreatedAfter       string `json:"createdAfter"`
+	CreatedBefore      string `json:"createdBefore"`
+	Team               string `json:"team"`
+}
+
+type queryTaskRuns struct {
+	Fields     []string `json:"fields"`
+	Tasks      []string `json:"tasks"`
+	FailedOnly bool     `json:"failedOnly"`
+	Limit      int      `json:"limit"`
+}
+
+type queryLogFields struct {
+	TailLines  int    `json:"tailLines"`
+	FailedOnly bool   `json:"failedOnly"`
+	Container  string `json:"container"`
+}
+
+// queryResult is the output of the query tool.
+type queryResult struct {
+	Kind    string           `json:"kind"`
+	Runs    []map[string]any `json:"runs"`
+	Lookups int64            `json:"lookups"` // Results API lookups the query made
+	Notes   []string         `json:"notes,omitempty"`
+}
+
+func newQueryTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	tool := newTool("query", []toolExample{
+		{"request": map[string]any{
+			"filters":  map[string]any{"pipeline": "build-pipeline", "status": "failed", "createdAfter": "24h"},
+			"limit":    3,
+			"taskRuns": map[string]any{"failedOnly": true},
+			"logs":     map[string]any{"tailLines": 40},
+		}},
+		{"request": map[string]any{
+			"filters": map[string]any{"namespace": "-", "labelSelector": "app=web"},
+			"fields":  []string{"name", "namespace", "outcome", "duration", "results"},
+		}},
+		{"request": map[string]any{
+			"kind":    "TaskRun",
+			"filters": map[string]any{"task": "git-clone", "status": "failed"},
+			"logs":    map[string]any{"tailLines": 20},
+		}},
+	},
+		mcp.WithDescription(fmt.Sprintf("Answer a whole investigation in one call: list runs, and for each run its TaskRuns, their results and the end of their logs, executed as one batch on the server. Use it instead of chaining list, get and logs calls. The request selects runs with filters (as for pipelinerun_list and taskrun_list), picks the fields to return (%s) and optionally nests taskRuns (PipelineRuns only) and logs tails. At most %d runs, %d TaskRuns per run and %d logs are read per call.", strings.Join(queryFields, ", "), maxQueryRuns, maxQueryTaskRuns, maxQueryLogs)),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Query")),
+		mcp.WithObject("request",
+			mcp.Required(),
+			mcp.Description("The query, as an object or as a JSON or YAML string. kind: PipelineRun (default) or TaskRun. filters: namespace ('-' for all), pipeline, task, labelSelector, annotationSelector, prefix, nameRegex, status, reason, createdAfter, createdBefore, team. fields: fields of each run (default name, namespace, status, reason, startTime, duration; 'results' reads each run's emitted results). limit: runs to return (default 5). taskRuns: {fields, tasks, failedOnly, limit} to nest the TaskRuns of each PipelineRun. logs: {tailLines (default 30), failedOnly, container} to add the end of the logs of each TaskRun, or of each run for kind TaskRun."),
+			mcp.Properties(map[string]any{
+				"kind":     map[string]any{"type": "string", "enum": []string{"PipelineRun", "TaskRun"}},
+				"filters":  map[string]any{"type": "object"},
+				"fields":   map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": queryFields}},
+				"limit":    map[string]any{"type": "integer", "minimum": 1, "maximum": maxQueryRuns},
+				"taskRuns": map[string]any{"type": "object"},
+				"logs":     map[string]any{"type": "object"},
+			}),
+		),
+		dryRunOption(),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args queryParams) (*mcp.CallToolResult, error) {
+		q, err := parseQueryRequest(args.Request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		out, err := runQuery(ctx, deps, q, namespaceDefault)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		payload, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// parseQueryRequest decodes the request argument, given as an object or as a
+// JSON or YAML string, and checks it. Unknown keys are rejected so typos do
+// not silently widen a query.
+func parseQueryRequest(raw json.RawMessage) (queryRequest, error) {
+	var q queryRequest
+	doc := []byte(raw)
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		doc = []byte(text)
+	}
+	if len(strings.TrimSpace(string(doc))) == 0 || string(raw) == "null" {
+		return q, fmt.Errorf("request is required")
+	}
+	if err := yaml.UnmarshalStrict(doc, &q); err != nil {
+		return q, fmt.Errorf("invalid request: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(q.Kind)) {
+	case "", "pipelinerun":
+		q.Kind = "PipelineRun"
+	case "taskrun":
+		q.Kind = "TaskRun"
+	default:
+		return q, fmt.Errorf("invalid kind %q: expected PipelineRun or TaskRun", q.Kind)
+	}
+	if q.TaskRuns != nil && q.Kind != "PipelineRun" {
+		return q, fmt.Errorf("taskRuns can only be nested in PipelineRuns")
+	}
+	// Logs of a PipelineRun are the logs of its TaskRuns.
+	if q.Logs != nil && q.Kind == "PipelineRun" && q.TaskRuns == nil {
+		q.TaskRuns = &queryTaskRuns{}
+	}
+	if q.Limit < 0 || q.Limit > maxQueryRuns {
+		return q, fmt.Errorf("limit must be between 1 and %d", maxQueryRuns)
+	}
+	q.Limit = cmp.Or(q.Limit, defaultQueryRuns)
+	var err error
+	if q.Fields, err = queryFieldList(q.Fields, defaultRunFields); err != nil {
+		return q, err
+	}
+	if q.TaskRuns != nil {
+		if q.TaskRuns.Limit < 0 || q.TaskRuns.Limit > maxQueryTaskRuns {
+			return q, fmt.Errorf("taskRuns.limit must be between 1 and %d", maxQueryTaskRuns)
+		}
+		q.TaskRuns.Limit = cmp.Or(q.TaskRuns.Limit, defaultQueryTaskRuns)
+		if q.TaskRuns.Fields, err = queryFieldList(q.TaskRuns.Fields, defaultTaskRunFields); err != nil {
+			return q, fmt.Errorf("taskRuns: %w", err)
+		}
+	}
+	if q.Logs != nil {
+		if q.Logs.TailLines < 0 || q.Logs.TailLines > maxQueryTail {
+			return q, fmt.Errorf("logs.tailLines must be between 1 and %d", maxQueryTail)
+		}
+		q.Logs.TailLines = cmp.Or(q.Logs.TailLines, defaultQueryTail)
+	}
+	return q, nil
+}
+
+func queryFieldList(fields, defaults []string) ([]string, error) {
+	if len(fields) == 0 {
+		return defaults, nil
+	}
+	for _, f := range fields {
+		if !slices.Contains(queryFields, f) {
+			return nil, fmt.Errorf("unknown field %q; fields are %s", f, strings.Join(queryFields, ", "))
+		}
+	}
+	return fields, nil
+}
+
+// queryRun is a run being assembled by a query.
+type queryRun struct {
+	summary  tektonresults.RunSummary
+	results  []tektonresults.RunResult
+	taskRuns []*queryRun
+	logs     string
+	logsErr  string
+}
+
+// queryBatch runs the lookups of one query with bounded parallelism and
+// counts them.
+type queryBatch struct {
+	deps    Dependencies
+	lookups atomic.Int64
+	logs    atomic.Int64 // logs fetched or skipped for the maxQueryLogs budget
+
+	mu           sync.Mutex
+	notes        []string
+	logsDisabled bool
+}
+
+func (b *queryBatch) note(format string, args ...any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.notes = append(b.notes, fmt.Sprintf(format, args...))
+}
+
+// each calls fn for every index below n, with at most queryParallelism
+// calls in flight.
+func each(n int, fn func(i int)) {
+	sem := make(chan struct{}, queryParallelism)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+func runQuery(ctx context.Context, deps Dependencies, q queryRequest, namespaceDefault string) (*queryResult, error) {
+	createdAfter, createdBefore, err := parseCreatedRange(q.Filters.CreatedAfter, q.Filters.CreatedBefore, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	opts := tektonresults.ListOptions{
+		Namespace:          normalizeNamespace(q.Filters.Namespace, namespaceDefault),
+		LabelSelector:      q.Filters.LabelSelector,
+		AnnotationSelector: q.Filters.AnnotationSelector,
+		RefName:            q.Filters.Pipeline,
+		Prefix:             q.Filters.Prefix,
+		NameRegex:          q.Filters.NameRegex,
+		Reason:             q.Filters.Reason,
+		Status:             q.Filters.Status,
+		CreatedAfter:       createdAfter,
+		CreatedBefore:      createdBefore,
+		Team:               q.Filters.Team,
+		Limit:              q.Limit,
+	}
+	list := deps.Service.ListPipelineRunPage
+	if q.Kind == "TaskRun" {
+		opts.RefName = q.Filters.Task
+		list = deps.Service.ListTaskRunPage
+	} else if q.Filters.Task != "" {
+		return nil, fmt.Errorf("filters.task only applies to kind TaskRun; nest taskRuns with tasks to pick pipeline tasks")
+	}
+
+	b := &queryBatch{deps: deps}
+	b.lookups.Add(1)
+	page, err := list(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*queryRun, len(page.Runs))
+	for i, summary := range page.Runs {
+		runs[i] = &queryRun{summary: summary}
+	}
+	if page.Partial {
+		b.note("Stopped after scanning the page budget with %d of %d runs found; more runs may match, so narrow the filters.", len(page.Runs), q.Limit)
+	} else if page.NextPageToken != "" {
+		b.note("More runs match than the limit of %d; narrow the filters or raise limit.", q.Limit)
+	}
+
+	// First the details and TaskRuns of every run, then what the TaskRuns
+	// need, each level in parallel.
+	wantResults := slices.Contains(q.Fields, "results")
+	each(len(runs), func(i int) {
+		run := runs[i]
+		if wantResults {
+			b.readResults(ctx, run)
+		}
+		if q.TaskRuns != nil {
+			b.readTaskRuns(ctx, run, q.TaskRuns)
+		}
+	})
+	var leaves []*queryRun
+	if q.Kind == "TaskRun" {
+		leaves = runs
+	} else {
+		for _, run := range runs {
+			leaves = append(leaves, run.taskRuns...)
+		}
+	}
+	wantTaskRunResults := q.TaskRuns != nil && slices.Contains(q.TaskRuns.Fields, "results")
+	each(len(leaves), func(i int) {
+		leaf := leaves[i]
+		if wantTaskRunResults {
+			b.readResults(ctx, leaf)
+		}
+		if q.Logs != nil {
+			b.readLogs(ctx, leaf, q.Logs)
+		}
+	})
+	if skipped := b.logs.Load() - maxQueryLogs; skipped > 0 && !b.logsDisabled {
+		b.note("Logs of %d more runs were left out after %d; use logs.failedOnly or fewer runs.", skipped, maxQueryLogs)
+	}
+	if b.logsDisabled {
+		b.note("%s", deps.Messages.Format(messages.LogsDisabled, nil))
+	}
+
+	out := &queryResult{Kind: q.Kind, Runs: make([]map[string]any, 0, len(runs))}
+	for _, run := range runs {
+		entry := queryFieldValues(run, q.Fields)
+		if q.TaskRuns != nil {
+			taskRuns := make([]map[string]any, 0, len(run.taskRuns))
+			for _, tr := range run.taskRuns {
+				taskRuns = append(taskRuns, withLogs(queryFieldValues(tr, q.TaskRuns.Fields), tr))
+			}
+			entry["taskRuns"] = taskRuns
+		} else {
+			entry = withLogs(entry, run)
+		}
+		out.Runs = append(out.Runs, entry)
+	}
+	out.Lookups = b.lookups.Load()
+	out.Notes = b.notes
+	return out, nil
+}
+
+func (b *queryBatch) readResults(ctx context.Context, run *queryRun) {
+	b.lookups.Add(1)
+	detail, err := b.deps.Service.GetRunByRecord(ctx, run.summary.RecordName)
+	if err != nil {
+		b.note("results of %s: %v", run.summary.Name, err)
+		return
+	}
+	run.results = detail.Results()
+}
+
+func (b *queryBatch) readTaskRuns(ctx context.Context, run *queryRun, spec *queryTaskRuns) {
+	b.lookups.Add(1)
+	taskRuns, err := b.deps.Service.ListTaskRuns(ctx, tektonresults.ListOptions{
+		Namespace:     run.summary.Namespace,
+		LabelSelector: "tekton.dev/pipelineRunUID=" + run.summary.UID,
+		Limit:         maxQueryTaskRuns,
+	})
+	if err != nil {
+		b.note("TaskRuns of %s: %v", run.summary.Name, err)
+		return
+	}
+	taskRuns = filterTaskRuns(taskRuns, spec.Tasks, spec.FailedOnly)
+	// In execution order.
+	slices.SortStableFunc(taskRuns, func(x, y tektonresults.RunSummary) int {
+		return timeOf(x.StartTime).Compare(timeOf(y.StartTime))
+	})
+	if len(taskRuns) > spec.Limit {
+		b.note("%s has %d matching TaskRuns; only the first %d are included.", run.summary.Name, len(taskRuns), spec.Limit)
+		taskRuns = taskRuns[:spec.Limit]
+	}
+	for _, tr := range taskRuns {
+		run.taskRuns = append(run.taskRuns, &queryRun{summary: tr})
+	}
+}
+
+func (b *queryBatch) readLogs(ctx context.Context, run *queryRun, spec *queryLogFields) {
+	if spec.FailedOnly && run.summary.Outcome() != "failed" {
+		return
+	}
+	if b.logs.Add(1) > maxQueryLogs {
+		return
+	}
+	b.mu.Lock()
+	disabled := b.logsDisabled
+	b.mu.Unlock()
+	if disabled {
+		return
+	}
+	b.lookups.Add(1)
+	logs, err := b.deps.Service.FetchLogs(ctx, run.summary.RecordName)
+	switch {
+	case errors.Is(err, tektonresults.ErrLogsDisabled):
+		b.mu.Lock()
+		b.logsDisabled = true
+		b.mu.Unlock()
+		return
+	case err != nil:
+		run.logsErr = err.Error()
+		return
+	}
+	logs, note := containerLogs(logs, nil, parseContainerFilter(spec.Container))
+	if note != "" {
+		b.note("%s: %s", run.summary.Name, note)
+	}
+	run.logs = tailLines(logs, spec.TailLines)
+}
+
+// tailLines returns the last n lines of text, marking how many were left
+// out.
+func tailLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+	return fmt.Sprintf("[... %d earlier lines]\n%s", len(lines)-n, strings.Join(lines[len(lines)-n:], "\n"))
+}
+
+func withLogs(entry map[string]any, run *queryRun) map[string]any {
+	if run.logs != "" {
+		entry["logs"] = run.logs
+	}
+	if run.logsErr != "" {
+		entry["logsError"] = run.logsErr
+	}
+	return entry
+}
+
+// queryFieldValues returns the requested fields of run. Fields without a
+// value are left out, except name and status.
+func queryFieldValues(run *queryRun, fields []string) map[string]any {
+	s := run.summary
+	out := make(map[string]any, len(fields))
+	set := func(key string, value any, ok bool) {
+		if ok {
+			out[key] = value
+		}
+	}
+	for _, f := range fields {
+		switch f {
+		case "name":
+			out[f] = s.Name
+		case "namespace":
+			set(f, s.Namespace, s.Namespace != "")
+		case "uid":
+			set(f, s.UID, s.UID != "")
+		case "pipelineTask":
+			set(f, s.PipelineTask, s.PipelineTask != "")
+		case "status":
+			out[f] = runState(s)
+		case "outcome":
+			set(f, s.Outcome(), s.Outcome() != "")
+		case "reason":
+			set(f, s.Reason, s.Reason != "")
+		case "message":
+			set(f, s.Message, s.Message != "")
+		case "startTime":
+			set(f, s.StartTime, s.StartTime != nil)
+		case "completionTime":
+			set(f, s.CompletionTime, s.CompletionTime != nil)
+		case "duration":
+			set(f, runDuration(s), s.StartTime != nil && s.CompletionTime != nil)
+		case "labels":
+			set(f, s.Labels, len(s.Labels) > 0)
+		case "team":
+			set(f, s.Team, s.Team != "")
+		case "recordName":
+			set(f, s.RecordName, s.RecordName != "")
+		case "dashboardUrl":
+			set(f, s.DashboardURL, s.DashboardURL != "")
+		case "change":
+			set(f, s.Change, s.Change != nil)
+		case "results":
+			results := run.results
+			if results == nil {
+				results = []tektonresults.RunResult{}
+			}
+			out[f] = results
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestParseQueryRequest(t *testing.T) {
+	q, err := parseQueryRequest(json.RawMessage(`"filters: {pipeline: build}\nlogs: {}\n"`))
+	if err != nil {
+		t.Fatalf("parseQueryRequest failed: %v", err)
+	}
+	if q.Kind != "PipelineRun" || q.Limit != defaultQueryRuns || q.TaskRuns == nil || q.Logs.TailLines != defaultQueryTail {
+		t.Errorf("Unexpected defaults %+v", q)
+	}
+
+	for _, raw := range []string{
+		`null`,
+		`{"kind":"Pipeline"}`,
+		`{"filter":{}}`,
+		`{"fields":["name","size"]}`,
+		`{"limit":50}`,
+		`{"kind":"TaskRun","taskRuns":{}}`,
+		`{"logs":{"tailLines":1000}}`,
+	} {
+		if _, err := parseQueryRequest(json.RawMessage(raw)); err == nil {
+			t.Errorf("Expected %s to be rejected", raw)
+		}
+	}
+}
+
+func TestQueryTool(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(start.Add(d))
+		return &t
+	}
+	var listOpts tektonresults.ListOptions
+	mock := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			listOpts = opts
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+				{Name: "build-1", Namespace: "ci", UID: "uid-1", Status: "False", Reason: "Failed", StartTime: at(0), CompletionTime: at(90 * time.Second), RecordName: "ci/results/r1/records/uid-1"},
+			}}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			if opts.LabelSelector != "tekton.dev/pipelineRunUID=uid-1" {
+				t.Errorf("Unexpected TaskRun selector %q", opts.LabelSelector)
+			}
+			return []tektonresults.RunSummary{
+				{Name: "build-1-test", PipelineTask: "test", Status: "False", Reason: "Failed", StartTime: at(30 * time.Second), RecordName: "ci/results/r1/records/tr-2"},
+				{Name: "build-1-clone", PipelineTask: "clone", Status: "True", Reason: "Succeeded", StartTime: at(0), RecordName: "ci/results/r1/records/tr-1"},
+			}, nil
+		},
+		getRunByRecordFunc: func(ctx context.Context, recordName string) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{Raw: json.RawMessage(`{"status":{"results":[{"name":"IMAGE_DIGEST","value":"sha256:abc"}]}}`)}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			if recordName != "ci/results/r1/records/tr-2" {
+				t.Errorf("Logs of %s were fetched for a failedOnly query", recordName)
+			}
+			return "one\ntwo\nthree\nFAIL: TestX\n", nil
+		},
+	}
+	tool := newQueryTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"request": map[string]any{
+		"filters":  map[string]any{"pipeline": "build", "status": "failed"},
+		"fields":   []any{"name", "status", "duration", "results"},
+		"taskRuns": map[string]any{"fields": []any{"pipelineTask", "status"}},
+		"logs":     map[string]any{"tailLines": 2, "failedOnly": true},
+	}}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	if listOpts.RefName != "build" || listOpts.Namespace != "ci" || listOpts.Status != "failed" || listOpts.Limit != defaultQueryRuns {
+		t.Errorf("Unexpected list options %+v", listOpts)
+	}
+
+	var out struct {
+		Kind string `json:"kind"`
+		Runs []struct {
+			Name     string                    `json:"name"`
+			Status   string                    `json:"status"`
+			Duration string                    `json:"duration"`
+			Results  []tektonresults.RunResult `json:"results"`
+			TaskRuns []map[string]string       `json:"taskRuns"`
+		} `json:"runs"`
+		Lookups int `json:"lookups"`
+	}
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &out); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if out.Kind != "PipelineRun" || len(out.Runs) != 1 || out.Lookups != 4 {
+		t.Fatalf("Unexpected result %+v", out)
+	}
+	run := out.Runs[0]
+	if run.Name != "build-1" || run.Status != "Failed" || run.Duration != "1m 30s" || len(run.Results) != 1 || run.Results[0].String() != "sha256:abc" {
+		t.Errorf("Unexpected run %+v", run)
+	}
+	if len(run.TaskRuns) != 2 || run.TaskRuns[0]["pipelineTask"] != "clone" || run.TaskRuns[1]["pipelineTask"] != "test" {
+		t.Fatalf("Expected the TaskRuns in execution order, got %+v", run.TaskRuns)
+	}
+	if logs := run.TaskRuns[1]["logs"]; logs != "[... 2 earlier lines]\nthree\nFAIL: TestX" {
+		t.Errorf("Unexpected logs tail %q", logs)
+	}
+	if _, ok := run.TaskRuns[0]["logs"]; ok {
+		t.Error("Expected no logs for the succeeded TaskRun")
+	}
+}
+
+func TestQueryToolLogsDisabled(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listTaskRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			if opts.RefName != "git-clone" {
+				t.Errorf("Expected the task filter as RefName, got %+v", opts)
+			}
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{{Name: "a"}, {Name: "b"}}, NextPageToken: "next"}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			return "", tektonresults.ErrLogsDisabled
+		},
+	}
+	tool := newQueryTool(Dependencies{Service: mock})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"request": `{"kind":"TaskRun","filters":{"task":"git-clone"},"limit":2,"logs":{}}`}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	var out queryResult
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &out); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(out.Runs) != 2 || len(out.Notes) != 2 || !strings.Contains(out.Notes[0], "More runs match") {
+		t.Errorf("Unexpected result %+v", out)
+	}
+	if _, ok := out.Runs[0]["logsError"]; ok {
+		t.Error("Expected disabled logs to be a note, not a per-run error")
+	}
+}
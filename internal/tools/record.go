@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type recordParams struct {
+	manifestParams
+	RecordName string `json:"recordName"`
+}
+
+func newRunGetByRecordTool(deps Dependencies) server.ServerTool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Get a PipelineRun or TaskRun by the recordName returned by the list tools. This is a single direct lookup with no searching, so prefer it for follow-up calls after listing runs."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Get Run by Record")),
+		mcp.WithString("recordName",
+			mcp.Required(),
+			mcp.Description("Record name exactly as returned in the recordName field of list results: <namespace>/results/<result>/records/<record>."),
+		),
+	}
+	opts = append(opts, manifestOptions()...)
+
+	tool := newTool("run_get_by_record", []toolExample{
+		{"recordName": "default/results/0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11/records/0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11"},
+		{"recordName": "default/results/0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11/records/5d2e9c1a-7f3b-4c8e-b6a4-2e1f0d9c8b7a", "output": "json", "includeSummary": true},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args recordParams) (*mcp.CallToolResult, error) {
+		if strings.TrimSpace(args.RecordName) == "" {
+			return mcp.NewToolResultError("recordName is required"), nil
+		}
+
+		detail, err := deps.Service.GetRunByRecord(ctx, args.RecordName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		lookupCheck(ctx, deps.Service, detail, args.manifestParams)
+		result, err := manifestResult("", detail, args.manifestParams)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return result, nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRunGetByRecord(t *testing.T) {
+	recordName := "test-ns/results/pr-uid/records/tr-uid"
+	mock := &mockPipelineRunService{
+		getRunByRecordFunc: func(ctx context.Context, name string) (*tektonresults.RunDetail, error) {
+			if name != recordName {
+				t.Errorf("Expected record name %s, got %s", recordName, name)
+			}
+			return &tektonresults.RunDetail{
+				Raw:        json.RawMessage(`{"kind":"TaskRun","metadata":{"name":"build-task"}}`),
+				RecordName: recordName,
+			}, nil
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "default"}
+	tool := newRunGetByRecordTool(deps)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"recordName": recordName}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+	if text := getTextFromResult(result); !strings.Contains(text, "name: build-task") {
+		t.Errorf("Expected YAML manifest, got: %s", text)
+	}
+}
+
+func TestRunGetByRecord_Errors(t *testing.T) {
+	mock := &mockPipelineRunService{
+		getRunByRecordFunc: func(ctx context.Context, name string) (*tektonresults.RunDetail, error) {
+			return nil, &testError{msg: "record not found"}
+		},
+	}
+
+	deps := Dependencies{Service: mock, DefaultNamespace: "default"}
+	tool := newRunGetByRecordTool(deps)
+
+	for _, args := range []map[string]any{
+		{"recordName": "  "},
+		{"recordName": "test-ns/results/a/records/b"},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected error result for %v", args)
+		}
+	}
+}
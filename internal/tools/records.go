@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type runRecordsParams struct {
+	Name string `json:"name"`
+}
+
+func newRunRecordsTool(svc ResultReader) server.ServerTool {
+	tool := newTool(
+		"run_records",
+		[]toolExample{
+			{"name": "default/results/0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11"},
+			{"name": "default/results/0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11/records/5d2e9c1a-7f3b-4c8e-b6a4-2e1f0d9c8b7a"},
+		},
+		mcp.WithDescription("List every record stored under a run's Result (PipelineRun and TaskRun manifests, log metadata, events and custom types) with its data type and size. Use it to see what archived data exists for a run before choosing which tool to call next."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Run Records")),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The resultName of a run (<namespace>/results/<result>) as returned by the list tools, or any recordName under it. TaskRuns of a PipelineRun share the PipelineRun's Result."),
+		),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args runRecordsParams) (*mcp.CallToolResult, error) {
+		if strings.TrimSpace(args.Name) == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+		records, err := svc.ListResultRecords(ctx, args.Name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		payload, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRunRecords(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listResultRecordsFunc: func(ctx context.Context, name string) (*tektonresults.ResultRecords, error) {
+			if name != "ci/results/pr-1" {
+				t.Errorf("Expected name to be passed through, got %s", name)
+			}
+			return &tektonresults.ResultRecords{
+				Result:  name,
+				Records: []tektonresults.RecordInfo{{Name: name + "/records/pr-1", Type: "tekton.dev/v1.PipelineRun", Size: 2048, Kind: "PipelineRun"}},
+				Types:   map[string]int{"tekton.dev/v1.PipelineRun": 1},
+			}, nil
+		},
+	}
+	tool := newRunRecordsTool(mock)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "ci/results/pr-1"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+	text := getTextFromResult(result)
+	for _, want := range []string{`"type": "tekton.dev/v1.PipelineRun"`, `"size": 2048`, `"types"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain %s, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestRunRecords_Errors(t *testing.T) {
+	mock := &mockPipelineRunService{
+		listResultRecordsFunc: func(ctx context.Context, name string) (*tektonresults.ResultRecords, error) {
+			return nil, &testError{msg: "invalid name"}
+		},
+	}
+	tool := newRunRecordsTool(mock)
+
+	for _, name := range []string{" ", "ci"} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"name": name}
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected tool error for name %q, got %s", name, getTextFromResult(result))
+		}
+	}
+}
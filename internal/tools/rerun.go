@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newPipelineRunRerunTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Run an archived PipelineRun again: create a new PipelineRun in the cluster from its spec, with a generated name, without the original status and without the labels and annotations Tekton manages. Pipeline references are resolved again, so the current Pipeline definition runs. Identify the run like pipelinerun_get; the most recent match is used. Returns the name and UID of the new PipelineRun."),
+		mcp.WithToolAnnotation(createAnnotations("Re-run PipelineRun")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+
+	tool := newTool("pipelinerun_rerun", []toolExample{
+		{"labelSelector": "tekton.dev/pipeline=nightly", "namespace": namespaceDefault},
+		{"uid": "0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11", "namespace": namespaceDefault},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args selectorParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		rerun, err := deps.Service.RerunPipelineRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		payload, err := json.MarshalIndent(rerun, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunRerunTool(t *testing.T) {
+	mock := &mockPipelineRunService{
+		rerunPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RerunResult, error) {
+			if selector.Namespace != "ci" || selector.LabelSelector != "tekton.dev/pipeline=nightly" || !selector.SelectLast {
+				t.Errorf("Unexpected selector %+v", selector)
+			}
+			return &tektonresults.RerunResult{Name: "nightly-q9z4d", Namespace: "ci", UID: "new-uid", RerunOf: tektonresults.RunSummary{Name: "nightly-x7k2p"}}, nil
+		},
+	}
+	tool := newPipelineRunRerunTool(Dependencies{Service: mock, DefaultNamespace: "ci", AllowWrites: true, LiveCluster: true})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"labelSelector": "tekton.dev/pipeline=nightly"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError || !strings.Contains(text, `"name": "nightly-q9z4d"`) || !strings.Contains(text, `"rerunOf"`) {
+		t.Errorf("Unexpected result: %s", text)
+	}
+
+	req.Params.Arguments = map[string]any{}
+	if result, _ := tool.Handler(context.Background(), req); !result.IsError {
+		t.Error("Expected an error without a run selector")
+	}
+}
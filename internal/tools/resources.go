@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// dashboardURITemplate addresses the newest run of a name, e.g.
+// tekton://dashboard/ci/pipelinerun/build-x7k2p. Clients that render
+// previews of resource links read it to show a card for the run.
+const dashboardURITemplate = "tekton://dashboard/{namespace}/{kind}/{name}"
+
+// resourceTemplates returns the resource templates Add registers.
+func resourceTemplates(deps Dependencies) []server.ServerResourceTemplate {
+	templates := []server.ServerResourceTemplate{newDashboardResource(deps)}
+	if deps.outputs != nil {
+		templates = append(templates, newOutputResource(deps.outputs, deps.Signer))
+	}
+	return templates
+}
+
+func newDashboardResource(deps Dependencies) server.ServerResourceTemplate {
+	template := mcp.NewResourceTemplate(dashboardURITemplate, "Run preview",
+		mcp.WithTemplateDescription("Preview of the newest PipelineRun or TaskRun with this name: status, start time, duration, the link to the configured dashboard and the triggering change. kind is pipelinerun or taskrun. Read as a short text card and as the JSON run summary, whose dashboardUrl is set when the server has a dashboard URL template."),
+		mcp.WithTemplateMIMEType("text/plain"),
+	)
+	return server.ServerResourceTemplate{
+		Template: template,
+		Handler: func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			namespace, name := templateArgument(req, "namespace"), templateArgument(req, "name")
+			if namespace == "" || name == "" {
+				return nil, fmt.Errorf("invalid run URI %q: expected %s", req.Params.URI, dashboardURITemplate)
+			}
+			kind, err := resourceKind(templateArgument(req, "kind"))
+			if err != nil {
+				return nil, err
+			}
+
+			get := deps.Service.GetPipelineRun
+			if kind == "TaskRun" {
+				get = deps.Service.GetTaskRun
+			}
+			detail, err := get(ctx, tektonresults.RunSelector{Namespace: namespace, Name: name, SelectLast: true})
+			if err != nil {
+				return nil, err
+			}
+			summary, err := json.Marshal(detail.Summary)
+			if err != nil {
+				return nil, fmt.Errorf("encode run summary: %w", err)
+			}
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "text/plain", Text: runSummaryText(kind, detail.Summary)},
+				mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(summary)},
+			}, nil
+		},
+	}
+}
+
+// templateArgument returns a variable matched from the resource URI. The
+// server passes matched variables as string lists.
+func templateArgument(req mcp.ReadResourceRequest, name string) string {
+	switch v := req.Params.Arguments[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// resourceKind maps the kind segment of a run URI, such as "pipelinerun" or
+// "TaskRuns", to the kind of the run.
+func resourceKind(segment string) (string, error) {
+	switch strings.TrimSuffix(strings.ToLower(segment), "s") {
+	case "pipelinerun":
+		return "PipelineRun", nil
+	case "taskrun":
+		return "TaskRun", nil
+	default:
+		return "", fmt.Errorf("invalid run kind %q: expected pipelinerun or taskrun", segment)
+	}
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestDashboardResource(t *testing.T) {
+	var got tektonresults.RunSelector
+	mock := &mockPipelineRunService{
+		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			got = selector
+			return &tektonresults.RunDetail{Summary: tektonresults.RunSummary{
+				Name:         "build-x7k2p-compile",
+				Namespace:    "ci",
+				Status:       "True",
+				Reason:       "Succeeded",
+				DashboardURL: "https://tekton.example.com/#/namespaces/ci/taskruns/build-x7k2p-compile",
+			}}, nil
+		},
+	}
+	s, err := NewServer(Dependencies{Service: mock, DefaultNamespace: "default"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	uri := "tekton://dashboard/ci/taskrun/build-x7k2p-compile"
+	msg := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+	resp, ok := msg.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a response, got %#v", msg)
+	}
+	result, ok := resp.Result.(mcp.ReadResourceResult)
+	if !ok || len(result.Contents) != 2 {
+		t.Fatalf("Expected a text and a JSON content, got %#v", resp.Result)
+	}
+	if got.Namespace != "ci" || got.Name != "build-x7k2p-compile" || !got.SelectLast {
+		t.Errorf("Unexpected selector %+v", got)
+	}
+
+	card := result.Contents[0].(mcp.TextResourceContents)
+	if card.URI != uri || !strings.Contains(card.Text, "TaskRun ci/build-x7k2p-compile") ||
+		!strings.Contains(card.Text, "Dashboard: https://tekton.example.com/#/namespaces/ci/taskruns/build-x7k2p-compile") {
+		t.Errorf("Unexpected card %+v", card)
+	}
+	var summary tektonresults.RunSummary
+	if err := json.Unmarshal([]byte(result.Contents[1].(mcp.TextResourceContents).Text), &summary); err != nil || summary.DashboardURL == "" {
+		t.Errorf("Expected the JSON summary with the dashboard URL, got %v: %+v", err, summary)
+	}
+}
+
+func TestResourceKind(t *testing.T) {
+	for segment, want := range map[string]string{"pipelinerun": "PipelineRun", "PipelineRuns": "PipelineRun", "taskrun": "TaskRun"} {
+		if got, err := resourceKind(segment); err != nil || got != want {
+			t.Errorf("resourceKind(%q) = %q, %v; want %q", segment, got, err, want)
+		}
+	}
+	if _, err := resourceKind("pod"); err == nil {
+		t.Error("Expected an unknown kind to be rejected")
+	}
+}
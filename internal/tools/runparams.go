@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// runParams is the output of pipelinerun_params.
+type runParams struct {
+	Run    string                   `json:"run"` // namespace/name
+	Status string                   `json:"status"`
+	Params []tektonresults.RunParam `json:"params"`
+}
+
+func newPipelineRunParamsTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Read the parameter values a PipelineRun executed with, as name, type and value: those it passed in spec.params and, when the resolved Pipeline is recorded, the defaults it left in place (marked default). Use it to answer what params a run used without fetching the whole manifest."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("PipelineRun Params")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+
+	tool := newTool("pipelinerun_params", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipeline=build-pipeline", "namespace": namespaceDefault},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args selectorParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		detail, err := deps.Service.GetPipelineRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		return runParamsResult(detail), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+func runParamsResult(detail *tektonresults.RunDetail) *mcp.CallToolResult {
+	run := detail.Summary
+	params, resolved := detail.Params()
+	var result *mcp.CallToolResult
+	if len(params) == 0 {
+		result = mcp.NewToolResultText(fmt.Sprintf("PipelineRun %s/%s executed without parameters (state: %s).", run.Namespace, run.Name, runState(run)))
+	} else {
+		payload, err := json.MarshalIndent(runParams{Run: run.Namespace + "/" + run.Name, Status: runState(run), Params: params}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err))
+		}
+		result = mcp.NewToolResultText(string(payload))
+	}
+	if !resolved {
+		result.Content = append(result.Content, mcp.NewTextContent("Note: the resolved Pipeline is not recorded in this run's status, so parameters it left to their defaults are not listed."))
+	}
+	if note := historyNote("PipelineRun", detail); note != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(note))
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunParamsTool(t *testing.T) {
+	runs := map[string]string{
+		"build-1": `{"spec":{"params":[{"name":"revision","value":"main"}]},"status":{"pipelineSpec":{"params":[{"name":"revision","type":"string"},{"name":"skip-tests","type":"string","default":"false"}]}}}`,
+		"legacy":  `{"spec":{"params":[{"name":"revision","value":"v1.2"}]},"status":{}}`,
+		"bare":    `{"spec":{},"status":{"pipelineSpec":{}}}`,
+	}
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{Name: selector.Name, Namespace: "ci", Status: "True", Reason: "Succeeded"},
+				Raw:     json.RawMessage(runs[selector.Name]),
+			}, nil
+		},
+	}
+	tool := newPipelineRunParamsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"name": "build-1"})
+	var out runParams
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &out); err != nil {
+		t.Fatalf("Failed to decode params: %v", err)
+	}
+	if out.Run != "ci/build-1" || len(out.Params) != 2 || out.Params[0].Default || !out.Params[1].Default || string(out.Params[1].Value) != `"false"` {
+		t.Errorf("Unexpected params %+v", out)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("Expected no notes for a resolved run, got %+v", result.Content)
+	}
+
+	result = call(map[string]any{"name": "legacy"})
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "defaults are not listed") {
+		t.Errorf("Expected a note on the missing defaults, got %+v", result.Content)
+	}
+	if text := getTextFromResult(call(map[string]any{"name": "bare"})); !strings.Contains(text, "executed without parameters") {
+		t.Errorf("Unexpected text for a run without params: %s", text)
+	}
+	if result := call(map[string]any{}); !result.IsError {
+		t.Error("Expected an error without a selector")
+	}
+}
@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+type runResultsParams struct {
+	selectorParams
+	Names []string `json:"names"`
+}
+
+func resultNamesOption() mcp.ToolOption {
+	return mcp.WithArray("names",
+		mcp.Description("Only return these results, e.g. [\"IMAGE_DIGEST\"]. Names are matched exactly; names the run did not emit are listed in a note."),
+		mcp.WithStringItems(),
+		examples([]string{"IMAGE_DIGEST"}, []string{"IMAGE_URL", "IMAGE_DIGEST"}),
+	)
+}
+
+// runResults is the output of the tools that read the results of a run.
+type runResults struct {
+	Run         string                      `json:"run"` // namespace/name
+	Status      string                      `json:"status"`
+	Results     []tektonresults.RunResult   `json:"results"`
+	StepResults []tektonresults.StepResults `json:"stepResults,omitempty"` // TaskRuns only
+}
+
+func newPipelineRunResultsTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Read the results a PipelineRun emitted, such as IMAGE_URL and IMAGE_DIGEST, as name, type and value, without fetching the whole manifest. String results are JSON strings; array and object results keep their JSON shape."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("PipelineRun Results")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+	opts = append(opts, resultNamesOption())
+
+	tool := newTool("pipelinerun_results", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipeline=build-pipeline", "namespace": namespaceDefault, "names": []string{"IMAGE_DIGEST"}},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args runResultsParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		detail, err := deps.Service.GetPipelineRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		return runResultsResult("PipelineRun", detail, args.Names), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+func newTaskRunResultsTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Read the results a TaskRun emitted, such as a commit SHA or IMAGE_DIGEST, as name, type and value, without fetching the whole manifest. Results emitted by individual steps are listed per step under stepResults. String results are JSON strings; array and object results keep their JSON shape."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("TaskRun Results")),
+	}
+	opts = append(opts, selectorOptions("TaskRun", namespaceDefault)...)
+	opts = append(opts, resultNamesOption())
+
+	tool := newTool("taskrun_results", []toolExample{
+		{"name": "build-pipeline-run-x7k2p-clone", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipelineTask=clone", "namespace": namespaceDefault, "names": []string{"commit"}},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args runResultsParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("TaskRun"); err != nil {
+			return deps.runError("TaskRun", err), nil
+		}
+		detail, err := deps.Service.GetTaskRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("TaskRun", err), nil
+		}
+		return runResultsResult("TaskRun", detail, args.Names), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// runResultsResult renders the results of a run of kind, and those of its
+// steps, keeping only names when given. Notes name the results that were
+// asked for but not emitted and which of several matching runs was read.
+func runResultsResult(kind string, detail *tektonresults.RunDetail, names []string) *mcp.CallToolResult {
+	run := detail.Summary
+	all, steps := detail.Results(), detail.StepResults()
+	if len(all) == 0 && len(steps) == 0 {
+		text := fmt.Sprintf("%s %s/%s emitted no results (state: %s).", kind, run.Namespace, run.Name, runState(run))
+		if run.Outcome() != "succeeded" {
+			text += " Results are only written when the run gets that far; a failed or cancelled run may have emitted none."
+		}
+		result := mcp.NewToolResultText(text)
+		if note := historyNote(kind, detail); note != "" {
+			result.Content = append(result.Content, mcp.NewTextContent(note))
+		}
+		return result
+	}
+
+	out := runResults{Run: run.Namespace + "/" + run.Name, Status: runState(run), Results: all, StepResults: steps}
+	if out.Results == nil {
+		out.Results = []tektonresults.RunResult{}
+	}
+	var missing []string
+	if len(names) > 0 {
+		wanted := func(r tektonresults.RunResult) bool { return slices.Contains(names, r.Name) }
+		out.Results = filterResults(all, wanted)
+		out.StepResults = nil
+		for _, step := range steps {
+			if results := filterResults(step.Results, wanted); len(results) > 0 {
+				out.StepResults = append(out.StepResults, tektonresults.StepResults{Step: step.Step, Results: results})
+			}
+		}
+		for _, name := range names {
+			if !slices.ContainsFunc(out.Results, func(r tektonresults.RunResult) bool { return r.Name == name }) &&
+				!slices.ContainsFunc(out.StepResults, func(s tektonresults.StepResults) bool {
+					return slices.ContainsFunc(s.Results, func(r tektonresults.RunResult) bool { return r.Name == name })
+				}) {
+				missing = append(missing, name)
+			}
+		}
+	}
+	payload, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err))
+	}
+	result := mcp.NewToolResultText(string(payload))
+	if len(missing) > 0 {
+		var emitted []string
+		for _, r := range all {
+			emitted = append(emitted, r.Name)
+		}
+		for _, step := range steps {
+			for _, r := range step.Results {
+				emitted = append(emitted, step.Step+"/"+r.Name)
+			}
+		}
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Note: the %s did not emit %s; it emitted %s.", kind, strings.Join(missing, ", "), strings.Join(emitted, ", "))))
+	}
+	if note := historyNote(kind, detail); note != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(note))
+	}
+	return result
+}
+
+// filterResults returns the results keep accepts, in order, and an empty
+// slice rather than nil when there are none.
+func filterResults(results []tektonresults.RunResult, keep func(tektonresults.RunResult) bool) []tektonresults.RunResult {
+	out := []tektonresults.RunResult{}
+	for _, r := range results {
+		if keep(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunResultsTool(t *testing.T) {
+	raw := `{"kind":"PipelineRun","status":{"results":[{"name":"IMAGE_URL","value":"quay.io/org/app:v1"},{"name":"IMAGE_DIGEST","value":"sha256:abc"}]}}`
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			if selector.Name == "failed-run" {
+				return &tektonresults.RunDetail{
+					Summary: tektonresults.RunSummary{Name: "failed-run", Namespace: "ci", Status: "False", Reason: "Failed"},
+					Raw:     json.RawMessage(`{"kind":"PipelineRun","status":{}}`),
+				}, nil
+			}
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{Name: selector.Name, Namespace: "ci", Status: "True", Reason: "Succeeded"},
+				Raw:     json.RawMessage(raw),
+			}, nil
+		},
+	}
+	tool := newPipelineRunResultsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]any{"name": "build-1", "names": []any{"IMAGE_DIGEST", "SBOM"}})
+	var out runResults
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &out); err != nil {
+		t.Fatalf("Failed to decode results: %v", err)
+	}
+	if out.Run != "ci/build-1" || out.Status != "Succeeded" || len(out.Results) != 1 || out.Results[0].String() != "sha256:abc" {
+		t.Errorf("Unexpected results %+v", out)
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "did not emit SBOM; it emitted IMAGE_URL, IMAGE_DIGEST") {
+		t.Errorf("Expected a note on the missing result, got %+v", result.Content)
+	}
+
+	if text := getTextFromResult(call(map[string]any{"name": "failed-run"})); !strings.Contains(text, "emitted no results (state: Failed)") {
+		t.Errorf("Unexpected text for a run without results: %s", text)
+	}
+	if result := call(map[string]any{}); !result.IsError {
+		t.Error("Expected an error without a selector")
+	}
+}
+
+func TestTaskRunResultsTool(t *testing.T) {
+	raw := `{"kind":"TaskRun","status":{
+		"results":[{"name":"commit","type":"string","value":"3f2a1c9"}],
+		"steps":[{"name":"clone","results":[{"name":"url","type":"string","value":"https://git.example.com/app"}]},{"name":"report"}]
+	}}`
+	mock := &mockPipelineRunService{
+		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{Name: selector.Name, Namespace: "ci", Status: "True", Reason: "Succeeded"},
+				Raw:     json.RawMessage(raw),
+			}, nil
+		},
+	}
+	tool := newTaskRunResultsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	var out runResults
+	if err := json.Unmarshal([]byte(getTextFromResult(call(map[string]any{"name": "build-1-clone"}))), &out); err != nil {
+		t.Fatalf("Failed to decode results: %v", err)
+	}
+	if out.Run != "ci/build-1-clone" || len(out.Results) != 1 || len(out.StepResults) != 1 || out.StepResults[0].Step != "clone" || out.StepResults[0].Results[0].String() != "https://git.example.com/app" {
+		t.Errorf("Unexpected results %+v", out)
+	}
+
+	result := call(map[string]any{"name": "build-1-clone", "names": []any{"url", "digest"}})
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &out); err != nil {
+		t.Fatalf("Failed to decode results: %v", err)
+	}
+	if len(out.Results) != 0 || len(out.StepResults) != 1 || out.StepResults[0].Results[0].Name != "url" {
+		t.Errorf("Expected only the step result, got %+v", out)
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "did not emit digest; it emitted commit, clone/url") {
+		t.Errorf("Expected a note on the missing result, got %+v", result.Content)
+	}
+}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// defaultStatsWindow is the window of the stats tools without createdAfter.
+const defaultStatsWindow = 7 * 24 * time.Hour
+
+type runStatsParams struct {
+	Pipeline      string `json:"pipeline"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	Team          string `json:"team"`
+	CreatedAfter  string `json:"createdAfter"`
+	CreatedBefore string `json:"createdBefore"`
+}
+
+type taskRunStatsParams struct {
+	runStatsParams
+	Task   string `json:"task"`
+	SortBy string `json:"sortBy"`
+}
+
+// runStatsOutput is the output of the stats tools: the statistics and what
+// they cover.
+type runStatsOutput struct {
+	Subject string    `json:"subject"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	*tektonresults.RunStats
+	Tasks []tektonresults.TaskStats `json:"tasks,omitempty"` // taskrun_stats only
+}
+
+// taskSortOrders order the tasks of taskrun_stats, most significant first.
+var taskSortOrders = map[string]func(a, b tektonresults.TaskStats) int{
+	"duration": func(a, b tektonresults.TaskStats) int {
+		return cmp.Compare(totalSeconds(b.RunStats), totalSeconds(a.RunStats))
+	},
+	"failures": func(a, b tektonresults.TaskStats) int { return cmp.Compare(b.Failed+b.TimedOut, a.Failed+a.TimedOut) },
+	"runs":     func(a, b tektonresults.TaskStats) int { return cmp.Compare(b.Runs, a.Runs) },
+	"name":     func(a, b tektonresults.TaskStats) int { return 0 },
+}
+
+// totalSeconds is the time the finished runs of stats took together.
+func totalSeconds(stats *tektonresults.RunStats) float64 {
+	if stats.Duration == nil {
+		return 0
+	}
+	return stats.Duration.AverageSeconds * float64(stats.Duration.Samples)
+}
+
+func newPipelineRunStatsTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Compute statistics over the PipelineRuns of a window: run counts per outcome, success and failure rate, and the average, median and 95th percentile duration. Rates and durations cover finished runs (succeeded, failed or timed out); cancelled and running runs are only counted. Every matching run of the window is read, so narrow it with pipeline or labelSelector on busy namespaces."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("PipelineRun Statistics")),
+		mcp.WithString("pipeline",
+			mcp.Description("Only count runs of this Pipeline."),
+			mcp.DefaultString(""),
+			examples("build-pipeline"),
+		),
+		namespacesOption(namespaceDefault),
+		labelSelectorOption("app=frontend"),
+		teamOption(),
+	}
+	opts = append(opts, createdRangeOptions()...)
+	opts = append(opts, dryRunOption())
+
+	tool := newTool("pipelinerun_stats", []toolExample{
+		{"pipeline": "build-pipeline", "namespace": namespaceDefault},
+		{"namespace": "-", "createdAfter": "30d"},
+		{"labelSelector": "app=frontend", "namespace": namespaceDefault, "createdAfter": "2024-05-01", "createdBefore": "2024-06-01"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args runStatsParams) (*mcp.CallToolResult, error) {
+		from, to, err := statsWindow(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		statsOpts := tektonresults.StatsOptions{ListOptions: tektonresults.ListOptions{
+			Namespace:     normalizeNamespace(args.Namespace, namespaceDefault),
+			LabelSelector: args.LabelSelector,
+			Team:          args.Team,
+			RefName:       strings.TrimSpace(args.Pipeline),
+			CreatedAfter:  from,
+			CreatedBefore: to,
+		}}
+		subject := "PipelineRuns in namespace " + statsOpts.Namespace
+		if statsOpts.RefName != "" {
+			subject = fmt.Sprintf("Pipeline %s in namespace %s", statsOpts.RefName, statsOpts.Namespace)
+		}
+		stats, err := deps.Service.PipelineRunStats(ctx, statsOpts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return runStatsResult(runStatsOutput{Subject: subject, From: from, To: to, RunStats: stats})
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+func newTaskRunStatsTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Compute statistics over the TaskRuns of a window, overall and per task: run counts per outcome, success and failure rate, and the average, median and 95th percentile duration. A TaskRun belongs to its pipeline task (the tekton.dev/pipelineTask label), or to the Task it references when it ran outside a pipeline. Use it to find the task that dominates pipeline duration or fails most often. Rates and durations cover finished runs (succeeded, failed or timed out); cancelled and running runs are only counted."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("TaskRun Statistics")),
+		mcp.WithString("pipeline",
+			mcp.Description("Only count TaskRuns of this Pipeline's runs (the tekton.dev/pipeline label). Pipeline task names are only unique within a pipeline, so set it to tell tasks of the same name apart."),
+			mcp.DefaultString(""),
+			examples("build-pipeline"),
+		),
+		mcp.WithString("task",
+			mcp.Description("Only count runs of this Task: spec.taskRef.name, or the tekton.dev/task label for runs with an embedded or resolved task spec."),
+			mcp.DefaultString(""),
+			examples("git-clone"),
+		),
+		namespacesOption(namespaceDefault),
+		labelSelectorOption("app=frontend"),
+		teamOption(),
+		mcp.WithString("sortBy",
+			mcp.Description("Order of the tasks: 'duration' puts the task whose finished runs took the longest in total first, 'failures' the task with the most failed or timed out runs, 'runs' the task run most often, and 'name' sorts by task name."),
+			mcp.DefaultString("duration"),
+			mcp.Enum("duration", "failures", "runs", "name"),
+		),
+	}
+	opts = append(opts, createdRangeOptions()...)
+	opts = append(opts, dryRunOption())
+
+	tool := newTool("taskrun_stats", []toolExample{
+		{"pipeline": "build-pipeline", "namespace": namespaceDefault},
+		{"pipeline": "build-pipeline", "namespace": namespaceDefault, "sortBy": "failures", "createdAfter": "30d"},
+		{"task": "git-clone", "namespace": "-"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args taskRunStatsParams) (*mcp.CallToolResult, error) {
+		order, ok := taskSortOrders[cmp.Or(args.SortBy, "duration")]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid sortBy %q: use duration, failures, runs or name", args.SortBy)), nil
+		}
+		from, to, err := statsWindow(args.runStatsParams)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		pipeline := strings.TrimSpace(args.Pipeline)
+		statsOpts := tektonresults.StatsOptions{ListOptions: tektonresults.ListOptions{
+			Namespace:     normalizeNamespace(args.Namespace, namespaceDefault),
+			LabelSelector: args.LabelSelector,
+			Team:          args.Team,
+			RefName:       strings.TrimSpace(args.Task),
+			CreatedAfter:  from,
+			CreatedBefore: to,
+		}}
+		subject := "TaskRuns"
+		if statsOpts.RefName != "" {
+			subject = "Task " + statsOpts.RefName
+		}
+		if pipeline != "" {
+			statsOpts.LabelSelector = strings.Trim(statsOpts.LabelSelector+",tekton.dev/pipeline="+pipeline, ",")
+			subject += " of Pipeline " + pipeline
+		}
+		subject += " in namespace " + statsOpts.Namespace
+
+		stats, err := deps.Service.TaskRunStats(ctx, statsOpts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		// The service orders tasks by name, which breaks ties.
+		slices.SortStableFunc(stats.Tasks, order)
+		return runStatsResult(runStatsOutput{Subject: subject, From: from, To: to, RunStats: stats.RunStats, Tasks: stats.Tasks})
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// statsWindow returns the creation window of args, by default the
+// defaultStatsWindow up to now.
+func statsWindow(args runStatsParams) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	createdAfter, createdBefore, err := parseCreatedRange(args.CreatedAfter, args.CreatedBefore, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to := cmp.Or(createdBefore, now)
+	return cmp.Or(createdAfter, to.Add(-defaultStatsWindow)), to, nil
+}
+
+// runStatsResult renders out as JSON, with a note when not every run of the
+// window was read.
+func runStatsResult(out runStatsOutput) (*mcp.CallToolResult, error) {
+	payload, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+	}
+	result := mcp.NewToolResultText(string(payload))
+	if out.Truncated {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Note: more than %d runs matched, so the statistics cover the first %d read, newest first in each namespace. Narrow the window or the selector for exact figures.", out.MaxRuns, out.MaxRuns)))
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunStatsTool(t *testing.T) {
+	var got tektonresults.StatsOptions
+	rate := 0.75
+	mock := &mockPipelineRunService{
+		pipelineRunStatsFunc: func(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.RunStats, error) {
+			got = opts
+			return &tektonresults.RunStats{Runs: 4, Succeeded: 3, Failed: 1, SuccessRate: &rate, Truncated: true, MaxRuns: 4}, nil
+		},
+	}
+	tool := newPipelineRunStatsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"pipeline": "build", "createdAfter": "2025-01-01", "createdBefore": "2025-01-08"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", text)
+	}
+	if got.Namespace != "ci" || got.RefName != "build" ||
+		!got.CreatedAfter.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !got.CreatedBefore.Equal(time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected options %+v", got)
+	}
+
+	var out struct {
+		Subject     string   `json:"subject"`
+		Runs        int      `json:"runs"`
+		SuccessRate *float64 `json:"successRate"`
+	}
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatalf("Expected JSON, got %v: %s", err, text)
+	}
+	if out.Subject != "Pipeline build in namespace ci" || out.Runs != 4 || out.SuccessRate == nil || *out.SuccessRate != 0.75 {
+		t.Errorf("Unexpected output %s", text)
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "more than 4 runs matched") {
+		t.Errorf("Expected a truncation note, got %+v", result.Content)
+	}
+}
+
+func TestPipelineRunStatsTool_DefaultWindow(t *testing.T) {
+	var got tektonresults.StatsOptions
+	mock := &mockPipelineRunService{
+		pipelineRunStatsFunc: func(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.RunStats, error) {
+			got = opts
+			return &tektonresults.RunStats{}, nil
+		},
+	}
+	tool := newPipelineRunStatsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"namespace": "all"}
+	if _, err := tool.Handler(context.Background(), req); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if got.Namespace != "-" || got.CreatedBefore.Sub(got.CreatedAfter) != defaultStatsWindow {
+		t.Errorf("Expected the last 7 days across all namespaces, got %+v", got)
+	}
+}
+
+func TestTaskRunStatsTool(t *testing.T) {
+	var got tektonresults.StatsOptions
+	task := func(name string, runs, failed int, seconds float64) tektonresults.TaskStats {
+		stats := &tektonresults.RunStats{Runs: runs, Failed: failed}
+		if seconds > 0 {
+			stats.Duration = &tektonresults.DurationStats{Samples: runs, AverageSeconds: seconds}
+		}
+		return tektonresults.TaskStats{Task: name, RunStats: stats}
+	}
+	mock := &mockPipelineRunService{
+		taskRunStatsFunc: func(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.TaskRunStats, error) {
+			got = opts
+			return &tektonresults.TaskRunStats{
+				RunStats: &tektonresults.RunStats{Runs: 9, Failed: 3},
+				Tasks:    []tektonresults.TaskStats{task("build", 3, 0, 300), task("clone", 3, 0, 20), task("test", 3, 3, 200)},
+			}, nil
+		},
+	}
+	tool := newTaskRunStatsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	call := func(args map[string]any) string {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		text := getTextFromResult(result)
+		if result.IsError {
+			t.Fatalf("Unexpected error: %s", text)
+		}
+		return text
+	}
+	order := func(text string) string {
+		var out struct {
+			Subject string `json:"subject"`
+			Tasks   []struct {
+				Task string `json:"task"`
+			} `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(text), &out); err != nil {
+			t.Fatalf("Expected JSON, got %v: %s", err, text)
+		}
+		names := []string{out.Subject}
+		for _, task := range out.Tasks {
+			names = append(names, task.Task)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := order(call(map[string]any{"pipeline": "build-pipeline", "labelSelector": "app=web"})); got != "TaskRuns of Pipeline build-pipeline in namespace ci,build,test,clone" {
+		t.Errorf("Expected tasks by total duration, got %s", got)
+	}
+	if got.LabelSelector != "app=web,tekton.dev/pipeline=build-pipeline" || got.RefName != "" {
+		t.Errorf("Unexpected options %+v", got)
+	}
+	if got := order(call(map[string]any{"task": "go-test", "sortBy": "failures"})); got != "Task go-test in namespace ci,test,build,clone" {
+		t.Errorf("Expected tasks by failures, got %s", got)
+	}
+	if got.RefName != "go-test" || got.LabelSelector != "" {
+		t.Errorf("Unexpected options %+v", got)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"sortBy": "slowest"}
+	if result, _ := tool.Handler(context.Background(), req); !result.IsError {
+		t.Error("Expected an invalid sortBy to be rejected")
+	}
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const (
+	defaultSampleWindow  = 7 * 24 * time.Hour
+	defaultSampleBuckets = 7
+	maxSampleBuckets     = 31
+)
+
+// sampleBucket holds the runs sampled from one time bucket. Full is set when
+// the bucket held no more runs than the sample size, so its statistics are
+// exact rather than estimated.
+type sampleBucket struct {
+	from, to time.Time
+	runs     []tektonresults.RunSummary
+	full     bool
+}
+
+// sampleRuns splits [from, to) into equal buckets and lists at most perBucket
+// of the newest runs created in each, querying the buckets in parallel. The
+// cost is bounded by buckets and perBucket however many runs the window
+// holds.
+func sampleRuns(ctx context.Context, list func(context.Context, tektonresults.ListOptions) ([]tektonresults.RunSummary, error), opts tektonresults.ListOptions, from, to time.Time, buckets, perBucket int) ([]sampleBucket, error) {
+	width := to.Sub(from) / time.Duration(buckets)
+	out := make([]sampleBucket, buckets)
+	errs := make([]error, buckets)
+
+	var wg sync.WaitGroup
+	for i := range out {
+		out[i].from = from.Add(time.Duration(i) * width)
+		out[i].to = out[i].from.Add(width)
+		if i == buckets-1 {
+			out[i].to = to
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bucketOpts := opts
+			bucketOpts.CreatedAfter, bucketOpts.CreatedBefore = out[i].from, out[i].to
+			// One run more than the sample tells a full bucket from a
+			// sampled one.
+			bucketOpts.Limit = perBucket + 1
+			runs, err := list(ctx, bucketOpts)
+			if err != nil {
+				errs[i] = fmt.Errorf("bucket starting %s: %w", format.Timestamp(out[i].from), err)
+				return
+			}
+			out[i].full = len(runs) <= perBucket
+			out[i].runs = runs[:min(len(runs), perBucket)]
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// wilsonInterval returns the 95% Wilson score interval of a proportion of
+// failures among n trials. It stays within [0, 1] and behaves for the small
+// samples and extreme rates common in CI history.
+func wilsonInterval(failures, n int) (low, high float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	const z = 1.96
+	p := float64(failures) / float64(n)
+	nf := float64(n)
+	denominator := 1 + z*z/nf
+	center := (p + z*z/(2*nf)) / denominator
+	half := z * math.Sqrt(p*(1-p)/nf+z*z/(4*nf*nf)) / denominator
+	return max(0, center-half), min(1, center+half)
+}
+
+// renderSample formats the failure rate and median duration of each bucket.
+// Cancelled and running runs count toward neither successes nor failures;
+// timed out runs count as failures.
+func renderSample(subject string, buckets []sampleBucket, perBucket int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Failure rate of %s from %s to %s, sampling up to %d run(s) per bucket\n\n",
+		subject, format.Timestamp(buckets[0].from), format.Timestamp(buckets[len(buckets)-1].to), perBucket)
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FROM\tRUNS\tFINISHED\tFAILED\tFAILURE RATE\t95% CI\tMEDIAN DURATION")
+	sampled := 0
+	for _, bucket := range buckets {
+		var finished, failed int
+		var durations []time.Duration
+		for _, s := range bucket.runs {
+			switch s.Outcome() {
+			case "succeeded":
+				finished++
+			case "failed", "timedout":
+				finished++
+				failed++
+			default:
+				continue
+			}
+			if d, ok := s.Duration(); ok && !s.ClockSkew {
+				durations = append(durations, d)
+			}
+		}
+
+		runs := fmt.Sprint(len(bucket.runs))
+		if !bucket.full {
+			runs += "+"
+			sampled++
+		}
+		rate, interval, median := "-", "-", "-"
+		if finished > 0 {
+			rate = ratePercent(float64(failed) / float64(finished))
+			interval = "exact"
+			if !bucket.full {
+				low, high := wilsonInterval(failed, finished)
+				interval = ratePercent(low) + "-" + ratePercent(high)
+			}
+		}
+		if len(durations) > 0 {
+			slices.Sort(durations)
+			median = format.Duration(durations[len(durations)/2])
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n", format.Timestamp(bucket.from), runs, finished, failed, rate, interval, median)
+	}
+	_ = w.Flush()
+
+	if sampled > 0 {
+		fmt.Fprintf(&b, "\n%d bucket(s) marked + hold more runs than were read. Their figures are estimated from the newest %d run(s) of the bucket, which are not a random draw; the interval assumes runs within a bucket behave alike. Raise sample to narrow them. Other buckets were read in full.\n", sampled, perBucket)
+	}
+	return b.String()
+}
+
+// ratePercent renders a proportion as a whole percentage.
+func ratePercent(p float64) string {
+	return fmt.Sprintf("%.0f%%", p*100)
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		failures, n int
+		low, high   float64
+	}{
+		{0, 0, 0, 1},
+		{0, 10, 0, 0.2775},
+		{5, 10, 0.2366, 0.7634},
+		{10, 10, 0.7225, 1},
+	}
+	for _, tt := range tests {
+		low, high := wilsonInterval(tt.failures, tt.n)
+		if math.Abs(low-tt.low) > 1e-4 || math.Abs(high-tt.high) > 1e-4 {
+			t.Errorf("wilsonInterval(%d, %d) = %.4f, %.4f; want %.4f, %.4f", tt.failures, tt.n, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestRunHistory_Sample(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(2 * time.Minute))
+	run := func(status, reason string) tektonresults.RunSummary {
+		return tektonresults.RunSummary{Status: status, Reason: reason, StartTime: &start, CompletionTime: &end}
+	}
+
+	var (
+		mu     sync.Mutex
+		bounds []time.Time
+	)
+	mock := &mockPipelineRunService{
+		listPipelineRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			if opts.Limit != 5 {
+				t.Errorf("Expected one run more than the sample, got limit %d", opts.Limit)
+			}
+			mu.Lock()
+			bounds = append(bounds, opts.CreatedAfter)
+			mu.Unlock()
+			if opts.CreatedAfter.Day() == 1 {
+				// A busy day: more runs than the sample.
+				return []tektonresults.RunSummary{
+					run("False", "Failed"), run("True", "Succeeded"), run("True", "Succeeded"),
+					run("False", "Cancelled"), run("True", "Succeeded"),
+				}, nil
+			}
+			return []tektonresults.RunSummary{run("True", "Succeeded"), run("False", "PipelineRunTimeout")}, nil
+		},
+	}
+
+	tool := newRunHistoryTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"pipeline":      "build",
+		"createdAfter":  "2024-05-01",
+		"createdBefore": "2024-05-03",
+		"buckets":       2,
+		"sample":        4,
+	}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Result is error: %s", getTextFromResult(result))
+	}
+	if len(bounds) != 2 {
+		t.Fatalf("Expected one query per bucket, got %v", bounds)
+	}
+
+	text := getTextFromResult(result)
+	for _, want := range []string{
+		"Failure rate of Pipeline build from 2024-05-01T00:00:00Z to 2024-05-03T00:00:00Z",
+		"4+    3         1       33%           6%-79%",
+		"2     2         1       50%           exact",
+		"1 bucket(s) marked + hold more runs than were read",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected sample to contain %q, got:\n%s", want, text)
+		}
+	}
+}
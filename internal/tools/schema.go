@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// outputFormats lists the manifest formats accepted by the get tools.
+var outputFormats = []string{"yaml", "json", "slack"}
+
+// toolExample is one example argument set rendered in a tool's input schema.
+type toolExample map[string]any
+
+// newTool builds a tool like mcp.NewTool and embeds the given example argument
+// sets in the input schema's "examples" keyword, which MCP clients and the
+// Inspector use to prefill forms.
+func newTool(name string, examples []toolExample, opts ...mcp.ToolOption) mcp.Tool {
+	tool := mcp.NewTool(name, opts...)
+	if len(examples) == 0 {
+		return tool
+	}
+
+	schema := map[string]any{
+		"type":       tool.InputSchema.Type,
+		"properties": tool.InputSchema.Properties,
+		"examples":   examples,
+	}
+	if len(tool.InputSchema.Required) > 0 {
+		schema["required"] = tool.InputSchema.Required
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		slog.Warn("failed to embed tool examples, using plain schema", "tool", name, "error", err)
+		return tool
+	}
+	tool.InputSchema = mcp.ToolInputSchema{}
+	tool.RawInputSchema = raw
+	return tool
+}
+
+// examples sets sample values for a single property.
+func examples(values ...any) mcp.PropertyOption {
+	return func(schema map[string]any) {
+		schema["examples"] = values
+	}
+}
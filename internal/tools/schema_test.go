@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewTool_EmbedsExamples(t *testing.T) {
+	tool := newTool("sample",
+		[]toolExample{{"name": "run-1"}},
+		mcp.WithString("name", mcp.Required()),
+		mcp.WithString("output", mcp.Enum(outputFormats...), examples("yaml")),
+	)
+
+	payload, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("Marshal tool failed: %v", err)
+	}
+
+	var decoded struct {
+		InputSchema struct {
+			Type       string                    `json:"type"`
+			Properties map[string]map[string]any `json:"properties"`
+			Required   []string                  `json:"required"`
+			Examples   []map[string]any          `json:"examples"`
+		} `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Unmarshal tool failed: %v", err)
+	}
+
+	schema := decoded.InputSchema
+	if schema.Type != "object" {
+		t.Errorf("Expected object schema, got %q", schema.Type)
+	}
+	if len(schema.Examples) != 1 || schema.Examples[0]["name"] != "run-1" {
+		t.Errorf("Expected embedded example, got %v", schema.Examples)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "name" {
+		t.Errorf("Expected required name, got %v", schema.Required)
+	}
+	if enum, ok := schema.Properties["output"]["enum"].([]any); !ok || len(enum) != len(outputFormats) {
+		t.Errorf("Expected output enum, got %v", schema.Properties["output"]["enum"])
+	}
+	if ex, ok := schema.Properties["output"]["examples"].([]any); !ok || len(ex) != 1 {
+		t.Errorf("Expected property examples, got %v", schema.Properties["output"]["examples"])
+	}
+}
+
+func TestNewTool_WithoutExamples(t *testing.T) {
+	tool := newTool("sample", nil, mcp.WithString("name"))
+	if tool.RawInputSchema != nil {
+		t.Errorf("Expected structured schema when no examples are given")
+	}
+	if _, ok := tool.InputSchema.Properties["name"]; !ok {
+		t.Errorf("Expected name property in structured schema")
+	}
+}
+
+func TestAllTools_HaveExamples(t *testing.T) {
+	deps := Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default"}
+	prTools, _ := pipelineRunTools(deps)
+	trTools, _ := taskRunTools(deps)
+
+	all := append(prTools, trTools...)
+	all = append(all, newRunGetByRecordTool(deps), newRunHistoryTool(deps), newRunsSinceTool(deps), newRunRecordsTool(deps.Service), newServerInfoTool(deps.Service), newBackendInfoTool(deps.Service), newResultsPruneTool(deps), newPipelineRunRerunTool(deps), newPipelineRunCancelTool(deps))
+	all = append(all, newQueryExplainTool(all), newServerStatsTool(newToolStats(), deps.Service))
+
+	for _, st := range all {
+		payload, err := json.Marshal(st.Tool)
+		if err != nil {
+			t.Fatalf("Marshal %s failed: %v", st.Tool.Name, err)
+		}
+		var decoded struct {
+			InputSchema struct {
+				Examples []map[string]any `json:"examples"`
+			} `json:"inputSchema"`
+		}
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			t.Fatalf("Unmarshal %s failed: %v", st.Tool.Name, err)
+		}
+		if len(decoded.InputSchema.Examples) == 0 {
+			t.Errorf("Tool %s has no examples in its input schema", st.Tool.Name)
+		}
+	}
+}
+
+// schemaProperties returns the input schema properties of a tool, whether it
+// uses a structured or raw schema.
+func schemaProperties(t *testing.T, tool mcp.Tool) map[string]any {
+	t.Helper()
+	payload, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("Marshal %s failed: %v", tool.Name, err)
+	}
+	var decoded struct {
+		InputSchema struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Unmarshal %s failed: %v", tool.Name, err)
+	}
+	return decoded.InputSchema.Properties
+}
@@ -0,0 +1,364 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"sigs.k8s.io/yaml"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// selectorParams holds the identification options shared by every tool that
+// targets a single PipelineRun or TaskRun.
+type selectorParams struct {
+	Namespace          string `json:"namespace"`
+	LabelSelector      string `json:"labelSelector"`
+	AnnotationSelector string `json:"annotationSelector"`
+	Prefix             string `json:"prefix"`
+	NameRegex          string `json:"nameRegex"`
+	Name               string `json:"name"`
+	UID                string `json:"uid"`
+	SelectLast         bool   `json:"selectLast"`
+	Index              int    `json:"index"`
+	CreatedAfter       string `json:"createdAfter"`
+	CreatedBefore      string `json:"createdBefore"`
+	SelectorYAML       string `json:"selectorYaml"`
+
+	selectLast                  *bool     // set by selectorYaml; wins over the selectLast argument
+	createdAfter, createdBefore time.Time // parsed by validate
+}
+
+// selectorYAML is the document accepted by selectorYaml. labelSelector and
+// annotationSelector may also be maps, as in a Kubernetes matchLabels block.
+type selectorYAML struct {
+	Namespace          *string `json:"namespace"`
+	LabelSelector      any     `json:"labelSelector"`
+	AnnotationSelector any     `json:"annotationSelector"`
+	Prefix             *string `json:"prefix"`
+	NameRegex          *string `json:"nameRegex"`
+	Name               *string `json:"name"`
+	UID                *string `json:"uid"`
+	SelectLast         *bool   `json:"selectLast"`
+	Index              *int    `json:"index"`
+	CreatedAfter       *string `json:"createdAfter"`
+	CreatedBefore      *string `json:"createdBefore"`
+}
+
+// selectorOptions declares the selectorParams properties on a tool. kind is the
+// human readable resource kind, e.g. "PipelineRun".
+func selectorOptions(kind, namespaceDefault string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("name",
+			mcp.Description(fmt.Sprintf("Exact %s name. Optional if uid, labelSelector or prefix identify a run.", kind)),
+			mcp.DefaultString(""),
+		),
+		mcp.WithString("namespace",
+			mcp.Description(fmt.Sprintf("Kubernetes namespace that owns the %s. Use '-' to search across namespaces.", kind)),
+			mcp.DefaultString(namespaceDefault),
+		),
+		labelSelectorOption("tekton.dev/pipeline=build-pipeline", "app=frontend,env=prod", "app=frontend,env!=dogfood"),
+		annotationSelectorOption(),
+		mcp.WithString("prefix",
+			mcp.Description(fmt.Sprintf("Optional %s name prefix to disambiguate when multiple runs share similar names.", kind)),
+			mcp.DefaultString(""),
+		),
+		nameRegexOption(kind),
+		mcp.WithString("uid",
+			mcp.Description(fmt.Sprintf("Exact %s UID (unique identifier in Tekton Results database). This is the most efficient way to find a specific run.", kind)),
+			mcp.DefaultString(""),
+		),
+		mcp.WithBoolean("selectLast",
+			mcp.Description(fmt.Sprintf("If true, automatically select the last (most recent) match when multiple %ss match the filters. Defaults to true.", kind)),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("index",
+			mcp.Description(fmt.Sprintf("Position among matching %ss ordered newest first: 0 = latest (default), 1 = the one before, and so on.", kind)),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
+		),
+		mcp.WithString("createdAfter",
+			mcp.Description(fmt.Sprintf("Only consider %ss created at or after this time: %s meaning that long ago. Filtered by the Results API; ignored when uid finds the run directly.", kind, timeBoundFormats)),
+			mcp.DefaultString(""),
+			examples("24h", "2024-05-01T10:00:00Z"),
+		),
+		mcp.WithString("createdBefore",
+			mcp.Description(fmt.Sprintf("Only consider %ss created before this time: %s meaning that long ago. With index, counts runs back from this time.", kind, timeBoundFormats)),
+			mcp.DefaultString(""),
+			examples("7d", "2024-05-02"),
+		),
+		mcp.WithString("selectorYaml",
+			mcp.Description("The fields above as a YAML document, for clients that garble structured arguments. Fields it sets override the individual parameters. labelSelector and annotationSelector may be a string or a map."),
+			mcp.DefaultString(""),
+			examples("name: build-x7k2p\nnamespace: ci", "labelSelector:\n  tekton.dev/pipeline: build\n  app: web\nindex: 1"),
+		),
+	}
+}
+
+// nameRegexOption declares the run name pattern shared by the selector and
+// list tools.
+func nameRegexOption(kind string) mcp.ToolOption {
+	return mcp.WithString("nameRegex",
+		mcp.Description(fmt.Sprintf("Regular expression (Go RE2 syntax) the %s name must match, unanchored: use ^ and $ to match the whole name. Applied after fetching records, so it narrows but does not speed up the search; combine it with labelSelector on busy namespaces.", kind)),
+		mcp.DefaultString(""),
+		examples("^build-[0-9a-f]{7}-[a-z0-9]{5}$", "nightly|weekly"),
+	)
+}
+
+// annotationSelectorOption declares the annotation filter shared by the
+// selector and list tools.
+func annotationSelectorOption() mcp.ToolOption {
+	return mcp.WithString("annotationSelector",
+		mcp.Description("Comma separated annotation selectors with the syntax of labelSelector, e.g. to find runs by the commit or repository Pipelines as Code records in annotations. Values cannot contain commas."),
+		mcp.DefaultString(""),
+		examples("pipelinesascode.tekton.dev/sha=3f2a1c9e8b7d6f5a4c3b2a1908f7e6d5c4b3a291", "pipelinesascode.tekton.dev/event-type=pull_request,pipelinesascode.tekton.dev/branch=main"),
+	)
+}
+
+// labelSelectorDescription describes the labelSelector argument of the tools
+// that filter runs by label.
+const labelSelectorDescription = "Comma separated label selectors: key=value must match, key!=value excludes a value and !key excludes runs carrying the label."
+
+// labelSelectorOption declares the label filter of the selector and list
+// tools, with example selectors.
+func labelSelectorOption(selectors ...any) mcp.ToolOption {
+	opts := []mcp.PropertyOption{mcp.Description(labelSelectorDescription), mcp.DefaultString("")}
+	if len(selectors) > 0 {
+		opts = append(opts, examples(selectors...))
+	}
+	return mcp.WithString("labelSelector", opts...)
+}
+
+// namespacesOption declares the namespace argument of the tools that query
+// one or more namespaces, defaulting to namespaceDefault.
+func namespacesOption(namespaceDefault string) mcp.ToolOption {
+	return mcp.WithString("namespace",
+		mcp.Description(fmt.Sprintf("Kubernetes namespace to query. Accepts a comma separated list of up to %d namespaces (e.g. 'ci,staging') to query several at once, or '-' to search across all namespaces.", tektonresults.MaxNamespaces)),
+		mcp.DefaultString(namespaceDefault),
+		examples(namespaceDefault, "-"),
+	)
+}
+
+// teamOption declares the team filter of the tools that list runs.
+func teamOption() mcp.ToolOption {
+	return mcp.WithString("team",
+		mcp.Description("Only return runs owned by this team, as mapped to label selectors in the server configuration (case insensitive). Combines with labelSelector. An unknown team fails with the list of configured teams."),
+		mcp.DefaultString(""),
+		examples("Payments"),
+	)
+}
+
+func serviceAccountOption() mcp.ToolOption {
+	return mcp.WithString("serviceAccount",
+		mcp.Description("Only return runs that executed as this Kubernetes service account (spec.serviceAccountName, or spec.taskRunTemplate.serviceAccountName for PipelineRuns). Applied after records are fetched, so pair it with other filters on busy namespaces."),
+		mcp.DefaultString(""),
+		examples("pipeline", "deployer"),
+	)
+}
+
+func latestOnlyOption() mcp.ToolOption {
+	return mcp.WithBoolean("latestOnly",
+		mcp.Description("Keep only the most recent run of each name, or of each generateName prefix for generated names, with the number of older runs left out in duplicates. Shows the latest state of each pipeline or task instead of its whole history. Runs are collapsed within the returned page."),
+		mcp.DefaultBool(false),
+	)
+}
+
+// durationOptions declares the list tool properties that bound how long the
+// returned runs took.
+func durationOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithNumber("minDurationSeconds",
+			mcp.Description("Only return completed runs that took at least this many seconds, e.g. 1800 for runs longer than 30 minutes. Running runs and runs with skewed timestamps are left out. Applied after records are fetched, so pair it with createdAfter or other filters on busy namespaces."),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("maxDurationSeconds",
+			mcp.Description("Only return completed runs that took at most this many seconds."),
+			mcp.Min(0),
+		),
+	}
+}
+
+func statusOption() mcp.ToolOption {
+	return mcp.WithString("status",
+		mcp.Description("Only return runs with one of these outcomes (comma separated): succeeded, failed, running, cancelled or timedout. Failed excludes cancelled and timed out runs. Filtered by the Results API, so no paging through other runs is needed."),
+		mcp.DefaultString(""),
+		mcp.Pattern(statusPattern()),
+		examples("failed", "cancelled,timedout"),
+	)
+}
+
+// statusPattern matches an empty status or a comma separated list of
+// tektonresults.RunStatuses. The status stays a string rather than an array
+// so list tools, query filters and prompts share one form.
+func statusPattern() string {
+	status := "(" + strings.Join(tektonresults.RunStatuses, "|") + ")"
+	return `^(\s*` + status + `\s*(,\s*` + status + `\s*)*)?$`
+}
+
+func orderByOption() mcp.ToolOption {
+	return mcp.WithString("orderBy",
+		mcp.Description("Order of the returned runs: create_time, update_time or completion_time, followed by asc or desc. completion_time is fetched in update order and sorted within each page."),
+		mcp.DefaultString("create_time desc"),
+		mcp.Enum(orderByValues()...),
+	)
+}
+
+// orderByValues lists every tektonresults.OrderFields entry in both
+// directions.
+func orderByValues() []string {
+	var values []string
+	for _, field := range tektonresults.OrderFields {
+		values = append(values, field+" asc", field+" desc")
+	}
+	return values
+}
+
+// validate merges selectorYaml over the individual parameters, rejects
+// negative indexes and invalid time bounds, and ensures at least one
+// identification option is set. The creation window narrows a selector but
+// does not identify a run on its own.
+func (p *selectorParams) validate(kind string) error {
+	if err := p.mergeYAML(); err != nil {
+		return err
+	}
+	if p.Index < 0 {
+		return fmt.Errorf("index must be zero or positive")
+	}
+	var err error
+	if p.createdAfter, p.createdBefore, err = parseCreatedRange(p.CreatedAfter, p.CreatedBefore, time.Now()); err != nil {
+		return err
+	}
+	if p.Name == "" && p.Prefix == "" && p.NameRegex == "" && p.UID == "" && strings.TrimSpace(p.LabelSelector) == "" && strings.TrimSpace(p.AnnotationSelector) == "" {
+		return missingSelectorError{kind: kind}
+	}
+	return nil
+}
+
+// mergeYAML copies the fields set in selectorYaml over the parameters.
+func (p *selectorParams) mergeYAML() error {
+	if strings.TrimSpace(p.SelectorYAML) == "" {
+		return nil
+	}
+	var doc selectorYAML
+	if err := yaml.UnmarshalStrict([]byte(p.SelectorYAML), &doc); err != nil {
+		return fmt.Errorf("invalid selectorYaml: %w", err)
+	}
+	labels, err := selectorString("labelSelector", doc.LabelSelector)
+	if err != nil {
+		return err
+	}
+	annotations, err := selectorString("annotationSelector", doc.AnnotationSelector)
+	if err != nil {
+		return err
+	}
+	for _, f := range []struct {
+		from *string
+		to   *string
+	}{
+		{doc.Namespace, &p.Namespace},
+		{doc.Prefix, &p.Prefix},
+		{doc.NameRegex, &p.NameRegex},
+		{doc.Name, &p.Name},
+		{doc.UID, &p.UID},
+		{doc.CreatedAfter, &p.CreatedAfter},
+		{doc.CreatedBefore, &p.CreatedBefore},
+	} {
+		if f.from != nil {
+			*f.to = strings.TrimSpace(*f.from)
+		}
+	}
+	if doc.LabelSelector != nil {
+		p.LabelSelector = labels
+	}
+	if doc.AnnotationSelector != nil {
+		p.AnnotationSelector = annotations
+	}
+	if doc.Index != nil {
+		p.Index = *doc.Index
+	}
+	if doc.SelectLast != nil {
+		p.selectLast = doc.SelectLast
+	}
+	return nil
+}
+
+// selectorString accepts the label or annotation selector field written as
+// a string or as a map, which becomes equality clauses in key order.
+func selectorString(field string, v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(v), nil
+	case map[string]any:
+		clauses := make([]string, 0, len(v))
+		for key, value := range v {
+			switch value.(type) {
+			case string, bool, float64:
+				clauses = append(clauses, fmt.Sprintf("%s=%v", key, value))
+			default:
+				return "", fmt.Errorf("invalid selectorYaml: %s key %s must have a scalar value", field, key)
+			}
+		}
+		sort.Strings(clauses)
+		return strings.Join(clauses, ","), nil
+	default:
+		return "", fmt.Errorf("invalid selectorYaml: %s must be a string or a map", field)
+	}
+}
+
+// runSelector converts the parameters into a service selector. selectLast
+// defaults to true unless the caller explicitly passed a boolean, in
+// selectorYaml or as an argument.
+func (p selectorParams) runSelector(req mcp.CallToolRequest, namespaceDefault string) tektonresults.RunSelector {
+	selectLast := true
+	if p.selectLast != nil {
+		selectLast = *p.selectLast
+	} else if params, ok := req.Params.Arguments.(map[string]interface{}); ok {
+		if val, exists := params["selectLast"]; exists {
+			if boolVal, ok := val.(bool); ok {
+				selectLast = boolVal
+			}
+		}
+	}
+
+	return tektonresults.RunSelector{
+		Namespace:          normalizeNamespace(p.Namespace, namespaceDefault),
+		LabelSelector:      p.LabelSelector,
+		AnnotationSelector: p.AnnotationSelector,
+		Prefix:             p.Prefix,
+		NameRegex:          p.NameRegex,
+		Name:               p.Name,
+		UID:                p.UID,
+		SelectLast:         selectLast,
+		Index:              p.Index,
+		CreatedAfter:       p.createdAfter,
+		CreatedBefore:      p.createdBefore,
+	}
+}
+
+// historyNote describes which of several matching runs was returned, so the
+// caller knows a run was picked among many. It returns "" for unique matches.
+func historyNote(kind string, detail *tektonresults.RunDetail) string {
+	h := detail.History
+	if h == nil {
+		return ""
+	}
+	count := fmt.Sprintf("%d", h.Matches)
+	if h.Truncated {
+		count = "at least " + count
+	}
+	picked := "the most recent"
+	if h.Selected > 0 {
+		picked = fmt.Sprintf("run #%d (newest first)", h.Selected)
+	}
+	note := fmt.Sprintf("Note: selected %s of %s %ss matching the filters", picked, count, kind)
+	if h.Oldest != nil && h.Newest != nil {
+		note += fmt.Sprintf(", started between %s and %s", format.Timestamp(h.Oldest.Time), format.Timestamp(h.Newest.Time))
+	}
+	return note + ". Use index to select another run."
+}
@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestSelectorParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  selectorParams
+		wantErr bool
+	}{
+		{"empty", selectorParams{}, true},
+		{"whitespace label selector", selectorParams{LabelSelector: "  "}, true},
+		{"name", selectorParams{Name: "run"}, false},
+		{"prefix", selectorParams{Prefix: "run-"}, false},
+		{"uid", selectorParams{UID: "uid-1"}, false},
+		{"label selector", selectorParams{LabelSelector: "app=web"}, false},
+		{"annotation selector", selectorParams{AnnotationSelector: "pipelinesascode.tekton.dev/sha=abc"}, false},
+		{"negative index", selectorParams{Name: "run", Index: -1}, true},
+		{"window ending before it starts", selectorParams{Name: "run", CreatedAfter: "7d", CreatedBefore: "2024-05-02"}, true},
+		{"creation window alone", selectorParams{CreatedAfter: "24h"}, true},
+		{"invalid createdAfter", selectorParams{Name: "run", CreatedAfter: "yesterday"}, true},
+		{"bounded by time", selectorParams{Name: "run", CreatedAfter: "2024-05-01", CreatedBefore: "2024-05-02"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.validate("PipelineRun")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectorParams_RunSelector(t *testing.T) {
+	params := selectorParams{Namespace: " all ", Name: "run", UID: "uid-1"}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{}
+	selector := params.runSelector(req, "default")
+	if selector.Namespace != "-" {
+		t.Errorf("Expected normalized namespace '-', got %s", selector.Namespace)
+	}
+	if !selector.SelectLast {
+		t.Error("Expected selectLast to default to true")
+	}
+	if selector.Name != "run" || selector.UID != "uid-1" {
+		t.Errorf("Unexpected selector: %+v", selector)
+	}
+
+	req.Params.Arguments = map[string]any{"selectLast": false}
+	if params.runSelector(req, "default").SelectLast {
+		t.Error("Expected explicit selectLast=false to be honored")
+	}
+
+	params.Index = 2
+	if got := params.runSelector(req, "default").Index; got != 2 {
+		t.Errorf("Expected index 2, got %d", got)
+	}
+}
+
+func TestSelectorParams_SelectorYAML(t *testing.T) {
+	params := selectorParams{
+		Namespace:    "default",
+		Name:         "ignored",
+		SelectorYAML: "namespace: ci\nlabelSelector:\n  tekton.dev/pipeline: build\n  app: web\nname: build-x7k2p\nselectLast: false\nindex: 1\n",
+	}
+	if err := params.validate("PipelineRun"); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"selectLast": true}
+	selector := params.runSelector(req, "default")
+	if selector.Namespace != "ci" || selector.Name != "build-x7k2p" || selector.Index != 1 {
+		t.Errorf("Expected YAML fields to override the parameters, got %+v", selector)
+	}
+	if selector.LabelSelector != "app=web,tekton.dev/pipeline=build" {
+		t.Errorf("Expected labels joined in key order, got %q", selector.LabelSelector)
+	}
+	if selector.SelectLast {
+		t.Error("Expected selectLast from YAML to win over the argument")
+	}
+
+	params = selectorParams{SelectorYAML: "annotationSelector:\n  pipelinesascode.tekton.dev/sha: 3f2a1c9\n"}
+	if err := params.validate("PipelineRun"); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	if params.AnnotationSelector != "pipelinesascode.tekton.dev/sha=3f2a1c9" {
+		t.Errorf("Expected annotation map as a selector, got %q", params.AnnotationSelector)
+	}
+
+	params = selectorParams{Prefix: "build-", SelectorYAML: "labelSelector: app=web,env!=dogfood"}
+	if err := params.validate("PipelineRun"); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	if params.LabelSelector != "app=web,env!=dogfood" || params.Prefix != "build-" {
+		t.Errorf("Expected string selector and untouched prefix, got %+v", params)
+	}
+
+	params = selectorParams{Name: "run", CreatedAfter: "2024-01-01", SelectorYAML: "createdAfter: 2024-05-01\ncreatedBefore: 2024-05-02T12:00:00Z"}
+	if err := params.validate("PipelineRun"); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	selector = params.runSelector(req, "default")
+	if !selector.CreatedAfter.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) || !selector.CreatedBefore.Equal(time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the creation window of the YAML, got %v to %v", selector.CreatedAfter, selector.CreatedBefore)
+	}
+
+	for _, doc := range []string{"nmae: build", "labelSelector: [a, b]", "labelSelector:\n  app: {x: 1}", "annotationSelector: [a]", "name: [", "index: -1"} {
+		params := selectorParams{Name: "run", SelectorYAML: doc}
+		if err := params.validate("PipelineRun"); err == nil {
+			t.Errorf("Expected error for selectorYaml %q", doc)
+		}
+	}
+}
+
+func TestPipelineRunGet_SelectorYAML(t *testing.T) {
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			if selector.Namespace != "ci" || selector.UID != "uid-1" {
+				t.Errorf("Unexpected selector %+v", selector)
+			}
+			return &tektonresults.RunDetail{Raw: []byte(`{"kind":"PipelineRun"}`)}, nil
+		},
+	}
+	tool := newPipelineRunGetTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"selectorYaml": "namespace: ci\nuid: uid-1"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError || !strings.Contains(getTextFromResult(result), "kind: PipelineRun") {
+		t.Errorf("Unexpected result: %s", getTextFromResult(result))
+	}
+}
+
+func TestRunTools_ShareSelectorSchema(t *testing.T) {
+	// Every field of selectorParams is a property of every tool that targets
+	// a single run, so the tools accept the same identification options.
+	var keys []string
+	params := reflect.TypeOf(selectorParams{})
+	for i := range params.NumField() {
+		if tag := params.Field(i).Tag.Get("json"); tag != "" {
+			keys = append(keys, tag)
+		}
+	}
+	tools, err := Definitions(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default", AllowWrites: true, LiveCluster: true})
+	if err != nil {
+		t.Fatalf("Definitions() error = %v", err)
+	}
+	selecting := map[string]bool{}
+	for _, tool := range tools {
+		props := schemaProperties(t, tool)
+		if _, ok := props["selectorYaml"]; !ok {
+			continue
+		}
+		selecting[tool.Name] = true
+		for _, key := range keys {
+			if _, ok := props[key]; !ok {
+				t.Errorf("Tool %s is missing selector property %q", tool.Name, key)
+			}
+		}
+	}
+	for _, name := range []string{"pipelinerun_get", "pipelinerun_logs", "taskrun_get", "taskrun_logs", "taskrun_steps", "pipelinerun_diff"} {
+		if !selecting[name] {
+			t.Errorf("Expected %s to take the shared selector", name)
+		}
+	}
+}
+
+func TestListTools_StatusAndOrderSchema(t *testing.T) {
+	tools, err := Definitions(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "default"})
+	if err != nil {
+		t.Fatalf("Definitions() error = %v", err)
+	}
+	checked := 0
+	for _, tool := range tools {
+		if tool.Name != "pipelinerun_list" && tool.Name != "taskrun_list" {
+			continue
+		}
+		checked++
+		props := schemaProperties(t, tool)
+		status := props["status"].(map[string]any)
+		pattern := regexp.MustCompile(status["pattern"].(string))
+		for _, value := range []string{"", "failed", "cancelled,timedout", " running , succeeded "} {
+			if !pattern.MatchString(value) {
+				t.Errorf("%s: expected status %q to match %s", tool.Name, value, pattern)
+			}
+		}
+		for _, value := range []string{"broken", "failed,", "failed timedout"} {
+			if pattern.MatchString(value) {
+				t.Errorf("%s: expected status %q not to match %s", tool.Name, value, pattern)
+			}
+		}
+
+		orderBy := props["orderBy"].(map[string]any)
+		values, _ := orderBy["enum"].([]any)
+		if len(values) != 2*len(tektonresults.OrderFields) || !slices.Contains(values, orderBy["default"]) {
+			t.Errorf("%s: unexpected orderBy values %v with default %v", tool.Name, values, orderBy["default"])
+		}
+	}
+	if checked != 2 {
+		t.Errorf("Expected pipelinerun_list and taskrun_list, checked %d tools", checked)
+	}
+}
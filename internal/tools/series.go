@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const (
+	// maxSeriesBuckets allows a month of hourly buckets.
+	maxSeriesBuckets = 31 * 24
+	// maxSeriesRuns bounds the runs read for one series; later buckets of a
+	// truncated series undercount.
+	maxSeriesRuns = 5000
+)
+
+// seriesIntervals maps the interval argument to the bucket width and the
+// default window.
+var seriesIntervals = map[string]struct{ width, window time.Duration }{
+	"hour": {time.Hour, 24 * time.Hour},
+	"day":  {24 * time.Hour, 14 * 24 * time.Hour},
+}
+
+type seriesParams struct {
+	Pipeline      string `json:"pipeline"`
+	Task          string `json:"task"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	Team          string `json:"team"`
+	Interval      string `json:"interval"`
+	CreatedAfter  string `json:"createdAfter"`
+	CreatedBefore string `json:"createdBefore"`
+}
+
+// failureSeries is the output of failure_rate_series. The arrays are
+// parallel, one entry per bucket, so clients can pass them to a chart
+// unchanged.
+type failureSeries struct {
+	Subject   string    `json:"subject"`
+	Interval  string    `json:"interval"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Truncated bool      `json:"truncated,omitempty"` // more than maxSeriesRuns runs matched; counts are incomplete
+
+	Timestamps  []time.Time `json:"timestamps"`  // start of each bucket
+	Runs        []int       `json:"runs"`        // runs started in the bucket
+	Finished    []int       `json:"finished"`    // of those, runs that succeeded, failed or timed out
+	Failures    []int       `json:"failures"`    // of those, runs that failed or timed out
+	FailureRate []*float64  `json:"failureRate"` // failures / finished; null for buckets without finished runs
+}
+
+func newFailureRateSeriesTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Return the run count and failure rate of a Pipeline, a Task or a whole namespace per hour or per day as parallel JSON arrays, for clients that render charts. Without pipeline or task, PipelineRuns of the namespace are counted. Every matching run of the window is read, so prefer run_history with sample for a quick estimate over long windows of busy namespaces."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Failure Rate Series")),
+		mcp.WithString("pipeline",
+			mcp.Description("Only count runs of this Pipeline."),
+			mcp.DefaultString(""),
+			examples("build-pipeline"),
+		),
+		mcp.WithString("task",
+			mcp.Description("Count TaskRuns of this Task instead of PipelineRuns."),
+			mcp.DefaultString(""),
+			examples("unit-tests"),
+		),
+		namespacesOption(namespaceDefault),
+		labelSelectorOption(),
+		teamOption(),
+		mcp.WithString("interval",
+			mcp.Description("Width of the buckets. The window defaults to the last 24 hours for hour and the last 14 days for day; buckets are aligned to UTC hours or days."),
+			mcp.DefaultString("day"),
+			mcp.Enum("hour", "day"),
+		),
+	}
+	opts = append(opts, createdRangeOptions()...)
+	opts = append(opts, dryRunOption())
+
+	tool := newTool("failure_rate_series", []toolExample{
+		{"pipeline": "build-pipeline", "namespace": namespaceDefault},
+		{"namespace": "-", "interval": "hour", "createdAfter": "48h"},
+		{"task": "unit-tests", "namespace": namespaceDefault, "createdAfter": "30d"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args seriesParams) (*mcp.CallToolResult, error) {
+		pipeline, task := strings.TrimSpace(args.Pipeline), strings.TrimSpace(args.Task)
+		if pipeline != "" && task != "" {
+			return mcp.NewToolResultError("provide at most one of pipeline or task"), nil
+		}
+		interval := cmp.Or(strings.ToLower(strings.TrimSpace(args.Interval)), "day")
+		bucket, ok := seriesIntervals[interval]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid interval %q: expected hour or day", args.Interval)), nil
+		}
+
+		now := time.Now().UTC()
+		createdAfter, createdBefore, err := parseCreatedRange(args.CreatedAfter, args.CreatedBefore, now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		to := cmp.Or(createdBefore, now)
+		from := cmp.Or(createdAfter, to.Add(-bucket.window)).Truncate(bucket.width)
+		buckets := int((to.Sub(from) + bucket.width - 1) / bucket.width)
+		if buckets > maxSeriesBuckets {
+			return mcp.NewToolResultError(fmt.Sprintf("the window holds %d %s buckets, more than the %d allowed; shorten it or use interval day", buckets, interval, maxSeriesBuckets)), nil
+		}
+
+		listOpts := tektonresults.ListOptions{
+			Namespace:     normalizeNamespace(args.Namespace, namespaceDefault),
+			LabelSelector: args.LabelSelector,
+			Team:          args.Team,
+			CreatedAfter:  from,
+			CreatedBefore: to,
+		}
+		list, subject := deps.Service.ListPipelineRunPage, "PipelineRuns in namespace "+listOpts.Namespace
+		switch {
+		case pipeline != "":
+			listOpts.RefName, subject = pipeline, fmt.Sprintf("Pipeline %s in namespace %s", pipeline, listOpts.Namespace)
+		case task != "":
+			list = deps.Service.ListTaskRunPage
+			listOpts.RefName, subject = task, fmt.Sprintf("Task %s in namespace %s", task, listOpts.Namespace)
+		}
+
+		series := newFailureSeries(subject, interval, from, to, bucket.width, buckets)
+		read := 0
+		for run, err := range eachRun(ctx, list, listOpts) {
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if read == maxSeriesRuns {
+				series.Truncated = true
+				break
+			}
+			read++
+			series.add(run, bucket.width)
+		}
+		series.rates()
+
+		payload, err := json.MarshalIndent(series, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+func newFailureSeries(subject, interval string, from, to time.Time, width time.Duration, buckets int) *failureSeries {
+	s := &failureSeries{
+		Subject:     subject,
+		Interval:    interval,
+		From:        from,
+		To:          to,
+		Timestamps:  make([]time.Time, buckets),
+		Runs:        make([]int, buckets),
+		Finished:    make([]int, buckets),
+		Failures:    make([]int, buckets),
+		FailureRate: make([]*float64, buckets),
+	}
+	for i := range s.Timestamps {
+		s.Timestamps[i] = from.Add(time.Duration(i) * width)
+	}
+	return s
+}
+
+// add counts run in the bucket it started in. Runs that never started are
+// placed by their completion time; runs outside the window are ignored.
+// Cancelled and running runs count toward neither successes nor failures,
+// like in run_history.
+func (s *failureSeries) add(run tektonresults.RunSummary, width time.Duration) {
+	at := cmp.Or(timeOf(run.StartTime), timeOf(run.CompletionTime))
+	if at.Before(s.From) || !at.Before(s.To) {
+		return
+	}
+	i := int(at.Sub(s.From) / width)
+	s.Runs[i]++
+	switch run.Outcome() {
+	case "succeeded":
+		s.Finished[i]++
+	case "failed", "timedout":
+		s.Finished[i]++
+		s.Failures[i]++
+	}
+}
+
+func (s *failureSeries) rates() {
+	for i, finished := range s.Finished {
+		if finished > 0 {
+			rate := float64(s.Failures[i]) / float64(finished)
+			s.FailureRate[i] = &rate
+		}
+	}
+}
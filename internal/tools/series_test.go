@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestFailureRateSeriesTool(t *testing.T) {
+	run := func(started time.Time, reason string) tektonresults.RunSummary {
+		start, end := metav1.NewTime(started), metav1.NewTime(started.Add(time.Minute))
+		status := "True"
+		if reason != "Succeeded" {
+			status = "False"
+		}
+		return tektonresults.RunSummary{StartTime: &start, CompletionTime: &end, Status: status, Reason: reason}
+	}
+	hour := time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	var calls []tektonresults.ListOptions
+	mock := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			calls = append(calls, opts)
+			// Two pages: the series must follow the page token.
+			if opts.PageToken == "" {
+				return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+					run(hour.Add(5*time.Minute), "Succeeded"),
+					run(hour.Add(10*time.Minute), "Failed"),
+				}, NextPageToken: "next"}, nil
+			}
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+				run(hour.Add(-50*time.Minute), "PipelineRunTimeout"),
+				run(hour.Add(-40*time.Minute), "Cancelled"),
+			}}, nil
+		},
+	}
+	tool := newFailureRateSeriesTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"pipeline": "build", "interval": "hour", "createdAfter": "2025-03-01T00:00:00Z", "createdBefore": "2025-03-01T02:30:00Z"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", getTextFromResult(result))
+	}
+	var series failureSeries
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &series); err != nil {
+		t.Fatalf("Failed to decode series: %v", err)
+	}
+
+	if len(calls) != 2 || calls[0].RefName != "build" || calls[1].PageToken != "next" {
+		t.Errorf("Unexpected list calls %+v", calls)
+	}
+	n := len(series.Timestamps)
+	if n != 3 || len(series.Runs) != n || len(series.FailureRate) != n {
+		t.Fatalf("Expected parallel arrays of 3 hourly buckets, got %+v", series)
+	}
+	if !series.Timestamps[n-1].Equal(hour) {
+		t.Errorf("Expected the last bucket to start at %s, got %s", hour, series.Timestamps[n-1])
+	}
+	// The previous hour: a timeout and a cancellation, which is not finished.
+	if series.Runs[n-2] != 2 || series.Finished[n-2] != 1 || series.FailureRate[n-2] == nil || *series.FailureRate[n-2] != 1 {
+		t.Errorf("Unexpected previous hour: runs %d, finished %d, rate %v", series.Runs[n-2], series.Finished[n-2], series.FailureRate[n-2])
+	}
+	if series.Runs[n-1] != 2 || series.Failures[n-1] != 1 || *series.FailureRate[n-1] != 0.5 {
+		t.Errorf("Unexpected current hour: runs %d, failures %d", series.Runs[n-1], series.Failures[n-1])
+	}
+	if series.FailureRate[0] != nil {
+		t.Errorf("Expected no rate for an empty bucket, got %v", *series.FailureRate[0])
+	}
+}
+
+func TestFailureRateSeriesTool_RejectsLongWindows(t *testing.T) {
+	tool := newFailureRateSeriesTool(Dependencies{Service: &mockPipelineRunService{}})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"interval": "hour", "createdAfter": "90d"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("Expected an error for 90 days of hourly buckets, got %s", getTextFromResult(result))
+	}
+}
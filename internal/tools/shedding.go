@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// Priority classes of tools. Interactive tools look up single runs and
+// their logs, which someone is waiting for; analytics tools read many runs
+// and can wait while the Results API is under pressure.
+const (
+	priorityInteractive = "interactive"
+	priorityAnalytics   = "analytics"
+)
+
+// priorityMetaKey is the key of the priority class in the _meta object of
+// tool definitions.
+const priorityMetaKey = "io.github.enarha.tekton-results-mcp/priority"
+
+// analyticsTools are the tools of priorityAnalytics.
+var analyticsTools = map[string]bool{
+	"failures_digest":     true,
+	"failure_rate_series": true,
+	"workspace_usage":     true,
+	"runs_since":          true,
+	"query":               true,
+	"pipelinerun_stats":   true,
+	"taskrun_stats":       true,
+}
+
+func toolPriority(name string) string {
+	if analyticsTools[name] {
+		return priorityAnalytics
+	}
+	return priorityInteractive
+}
+
+// groupingTools are the interactive tools whose calls with groupBy read up to
+// maxGroupRuns runs. Those calls are of priorityAnalytics.
+var groupingTools = map[string]bool{
+	"pipelinerun_list": true,
+	"taskrun_list":     true,
+}
+
+// callPriority returns the priority class of a call of the tool name, which
+// is the class of the tool except for grouped listings.
+func callPriority(name string, req mcp.CallToolRequest) string {
+	if groupingTools[name] && strings.TrimSpace(req.GetString("groupBy", "")) != "" {
+		return priorityAnalytics
+	}
+	return toolPriority(name)
+}
+
+// minPressureRequests is the number of requests within the pressure window
+// below which failures are not taken as pressure, so one failed lookup does
+// not hold back analytics.
+const minPressureRequests = 5
+
+// pressureReason explains why the Results API is under pressure, or returns
+// "" when it is not: it rate limited a request recently, or failed at least
+// half of them.
+func pressureReason(p tektonresults.UpstreamPressure) string {
+	switch {
+	case p.Throttled > 0:
+		return fmt.Sprintf("the Tekton Results API rate limited %d of the %d requests of the last %s", p.Throttled, p.Requests, p.Window)
+	case p.Requests >= minPressureRequests && 2*p.Failed >= p.Requests:
+		return fmt.Sprintf("the Tekton Results API failed %d of the %d requests of the last %s", p.Failed, p.Requests, p.Window)
+	}
+	return ""
+}
+
+// loadShedder holds back calls of analytics tools while the Results API is
+// under pressure. A call waits up to wait for the pressure to ease and is
+// deferred with an explanation otherwise. Calls that waited run one at a
+// time, so they do not bring the pressure back together.
+type loadShedder struct {
+	svc      StatsReporter
+	wait     time.Duration
+	poll     time.Duration
+	queue    chan struct{} // admits calls that waited
+	messages *messages.Catalog
+}
+
+// withLoadShedding tags every tool with its priority class and sheds calls
+// of analytics tools, and grouped listings, under upstream pressure.
+func withLoadShedding(tools []server.ServerTool, deps Dependencies) []server.ServerTool {
+	s := &loadShedder{
+		svc:      deps.Service,
+		wait:     deps.ShedWait,
+		poll:     500 * time.Millisecond,
+		queue:    make(chan struct{}, 1),
+		messages: deps.Messages,
+	}
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		priority := toolPriority(st.Tool.Name)
+		meta := &mcp.Meta{AdditionalFields: map[string]any{}}
+		if st.Tool.Meta != nil {
+			for k, v := range st.Tool.Meta.AdditionalFields {
+				meta.AdditionalFields[k] = v
+			}
+		}
+		meta.AdditionalFields[priorityMetaKey] = priority
+		st.Tool.Meta = meta
+		if priority == priorityAnalytics || groupingTools[st.Tool.Name] {
+			st.Handler = s.shed(st.Tool.Name, st.Handler)
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
+
+func (s *loadShedder) shed(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if callPriority(name, req) != priorityAnalytics {
+			return next(ctx, req)
+		}
+		p := s.svc.UpstreamPressure()
+		reason := pressureReason(p)
+		if reason == "" {
+			return next(ctx, req)
+		}
+		if reason = s.await(ctx); reason != "" {
+			slog.Warn("Deferred an analytics tool call under upstream pressure", "tool", name, "reason", reason)
+			return mcp.NewToolResultError(s.messages.Format(messages.Deferred, map[string]string{
+				"Tool":       name,
+				"Reason":     reason,
+				"RetryAfter": p.Window.String(),
+			})), nil
+		}
+		defer func() { <-s.queue }()
+		return next(ctx, req)
+	}
+}
+
+// await waits up to s.wait for the pressure to ease and for the queue to
+// admit the call. It returns "" once the call may run, holding a queue slot
+// the caller releases, and why it may not otherwise.
+func (s *loadShedder) await(ctx context.Context) string {
+	reason := pressureReason(s.svc.UpstreamPressure())
+	if reason != "" && s.wait <= 0 {
+		return reason
+	}
+	deadline := time.NewTimer(max(s.wait, 0))
+	defer deadline.Stop()
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+	for reason != "" {
+		select {
+		case <-ctx.Done():
+			return reason
+		case <-deadline.C:
+			return reason
+		case <-ticker.C:
+			reason = pressureReason(s.svc.UpstreamPressure())
+		}
+	}
+	select {
+	case s.queue <- struct{}{}:
+		return ""
+	default:
+	}
+	select {
+	case s.queue <- struct{}{}:
+		return ""
+	case <-ctx.Done():
+	case <-deadline.C:
+	}
+	return "other analytics calls that waited for the Tekton Results API to recover are still running"
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPressureReason(t *testing.T) {
+	tests := []struct {
+		pressure tektonresults.UpstreamPressure
+		want     string
+	}{
+		{tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 40}, ""},
+		{tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 40, Throttled: 2}, "rate limited 2 of the 40 requests of the last 30s"},
+		{tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 10, Failed: 5}, "failed 5 of the 10 requests of the last 30s"},
+		{tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 10, Failed: 4}, ""},
+		{tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 2, Failed: 2}, ""},
+	}
+	for _, tt := range tests {
+		if got := pressureReason(tt.pressure); !strings.HasSuffix(got, tt.want) || (tt.want == "") != (got == "") {
+			t.Errorf("pressureReason(%+v) = %q, want %q", tt.pressure, got, tt.want)
+		}
+	}
+}
+
+func TestLoadShedding(t *testing.T) {
+	var throttled atomic.Int32
+	mock := &mockPipelineRunService{
+		upstreamPressureFunc: func() tektonresults.UpstreamPressure {
+			return tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 10, Throttled: int(throttled.Load())}
+		},
+	}
+	var ran []string
+	handler := func(name string) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ran = append(ran, name)
+			return mcp.NewToolResultText("ok"), nil
+		}
+	}
+	tools := withLoadShedding([]server.ServerTool{
+		{Tool: mcp.NewTool("taskrun_logs"), Handler: handler("taskrun_logs")},
+		{Tool: mcp.NewTool("failures_digest"), Handler: handler("failures_digest")},
+	}, Dependencies{Service: mock})
+	for _, st := range tools {
+		if got := st.Tool.Meta.AdditionalFields[priorityMetaKey]; got != toolPriority(st.Tool.Name) {
+			t.Errorf("Expected %s to be tagged %s, got %v", st.Tool.Name, toolPriority(st.Tool.Name), got)
+		}
+	}
+	call := func(st server.ServerTool) *mcp.CallToolResult {
+		t.Helper()
+		result, err := st.Handler(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	call(tools[1])
+	throttled.Store(3)
+	call(tools[0])
+	result := call(tools[1])
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "failures_digest was deferred because the Tekton Results API rate limited 3 of the 10 requests") || !strings.Contains(getTextFromResult(result), "Retry in 30s") {
+		t.Errorf("Expected a deferral, got %s", getTextFromResult(result))
+	}
+	if strings.Join(ran, ",") != "failures_digest,taskrun_logs" {
+		t.Errorf("Expected interactive calls to run under pressure and analytics calls to be shed, ran %v", ran)
+	}
+
+	// A queued call runs once the pressure eases within the wait.
+	shedder := &loadShedder{svc: mock, wait: time.Second, poll: time.Millisecond, queue: make(chan struct{}, 1)}
+	queued := shedder.shed("failures_digest", handler("queued"))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		throttled.Store(0)
+	}()
+	if result, _ := queued(context.Background(), mcp.CallToolRequest{}); result.IsError || ran[len(ran)-1] != "queued" {
+		t.Errorf("Expected the queued call to run, got %s", getTextFromResult(result))
+	}
+	if len(shedder.queue) != 0 {
+		t.Error("Expected the queue slot to be released")
+	}
+
+	// Calls that waited run one at a time.
+	shedder.queue <- struct{}{}
+	throttled.Store(1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		throttled.Store(0)
+	}()
+	shedder.wait = 50 * time.Millisecond
+	if result, _ := queued(context.Background(), mcp.CallToolRequest{}); !result.IsError || !strings.Contains(getTextFromResult(result), "still running") {
+		t.Errorf("Expected a deferral behind the running call, got %s", getTextFromResult(result))
+	}
+}
+
+func TestLoadShedding_GroupedListing(t *testing.T) {
+	mock := &mockPipelineRunService{
+		upstreamPressureFunc: func() tektonresults.UpstreamPressure {
+			return tektonresults.UpstreamPressure{Window: 30 * time.Second, Requests: 10, Throttled: 2}
+		},
+	}
+	tools := withLoadShedding([]server.ServerTool{{
+		Tool: mcp.NewTool("pipelinerun_list"),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		},
+	}}, Dependencies{Service: mock})
+	if got := tools[0].Tool.Meta.AdditionalFields[priorityMetaKey]; got != priorityInteractive {
+		t.Errorf("Expected pipelinerun_list to be tagged interactive, got %v", got)
+	}
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tools[0].Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+	if result := call(map[string]any{"namespace": "ci"}); result.IsError {
+		t.Errorf("Expected a plain listing to run under pressure, got %s", getTextFromResult(result))
+	}
+	if result := call(map[string]any{"namespace": "ci", "groupBy": "pipeline"}); !result.IsError || !strings.Contains(getTextFromResult(result), "pipelinerun_list was deferred") {
+		t.Errorf("Expected a grouped listing to be shed, got %s", getTextFromResult(result))
+	}
+}
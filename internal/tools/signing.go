@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
+)
+
+// withSignature wraps the handler of each tool to sign the results it
+// returns with signer, when set.
+func withSignature(tools []server.ServerTool, signer *signing.Signer) []server.ServerTool {
+	if signer == nil {
+		return tools
+	}
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		name, next := st.Tool.Name, st.Handler
+		st.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err == nil && result != nil {
+				signer.Sign(name, result)
+			}
+			return result, err
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
+)
+
+func TestWithSignature(t *testing.T) {
+	signer, err := signing.New([]byte(strings.Repeat("k", signing.MinKeyLength)))
+	if err != nil {
+		t.Fatalf("signing.New() error = %v", err)
+	}
+	tools, err := serverTools(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "ci", Signer: signer})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	call := func(name string) *mcp.CallToolResult {
+		t.Helper()
+		st := tools[slices.IndexFunc(tools, func(st server.ServerTool) bool { return st.Tool.Name == name })]
+		result, err := st.Handler(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		return result
+	}
+
+	for _, name := range []string{"pipelinerun_list", "taskrun_get"} {
+		result := call(name)
+		sig, ok := result.Meta.AdditionalFields[signing.MetaKey].(signing.Signature)
+		if !ok || sig.Tool != name || !signer.Verify(sig, result) {
+			t.Errorf("Expected %s to return a valid signature, got %+v", name, result.Meta)
+		}
+	}
+
+	unsigned, err := serverTools(Dependencies{Service: &mockPipelineRunService{}, DefaultNamespace: "ci"})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	result, err := unsigned[0].Handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil || result.Meta != nil {
+		t.Errorf("Expected unsigned results without a signer, got %+v %v", result.Meta, err)
+	}
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+type sinceParams struct {
+	Kind          string   `json:"kind"`
+	Namespace     string   `json:"namespace"`
+	LabelSelector string   `json:"labelSelector"`
+	Cursor        string   `json:"cursor"`
+	Limit         int      `json:"limit"`
+	LabelKeys     []string `json:"labelKeys"`
+}
+
+func newRunsSinceTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Poll for new PipelineRuns or TaskRuns. Without a cursor it returns the most recent runs and a cursor; pass that cursor back to receive only runs created after it, oldest first, along with the next cursor. Use it to watch for new runs without re-reading ones already seen."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Runs Since Cursor")),
+		mcp.WithString("kind",
+			mcp.Description("Kind of run to return."),
+			mcp.Required(),
+			mcp.Enum("pipelinerun", "taskrun"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to query, or '-' for all namespaces. Must match the namespace the cursor was issued for."),
+			mcp.DefaultString(namespaceDefault),
+			examples(namespaceDefault, "-"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description(labelSelectorDescription+" Must match the selector the cursor was issued for."),
+			mcp.DefaultString(""),
+			examples("tekton.dev/pipeline=build-pipeline"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Opaque cursor returned by a previous call. Leave empty to start from the most recent runs."),
+			mcp.DefaultString(""),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of runs to return (1-200). When more runs are available the result sets 'more' and the cursor continues after the last returned run."),
+			mcp.DefaultNumber(defaultListLimit),
+			mcp.Min(1),
+			mcp.Max(maxListLimit),
+		),
+	}
+	opts = append(opts, projectionOptions()...)
+	opts = append(opts, dryRunOption())
+
+	tool := newTool("runs_since", []toolExample{
+		{"kind": "pipelinerun", "namespace": namespaceDefault},
+		{"kind": "taskrun", "namespace": "-", "cursor": "eyJrIjoidGFza3J1biIsIm4iOiItIiwidCI6IjIwMjUtMDEtMDFUMTA6MDA6MDBaIn0", "limit": 50},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args sinceParams) (*mcp.CallToolResult, error) {
+		result, err := deps.Service.RunsSince(ctx, tektonresults.SinceOptions{
+			Kind:          args.Kind,
+			Namespace:     normalizeNamespace(args.Namespace, namespaceDefault),
+			LabelSelector: args.LabelSelector,
+			Cursor:        args.Cursor,
+			Limit:         sanitizeLimit(args.Limit),
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		projectLabels(result.Runs, req.GetBool("includeLabels", true), args.LabelKeys)
+		payload, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRunsSince(t *testing.T) {
+	mock := &mockPipelineRunService{
+		runsSinceFunc: func(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error) {
+			if opts.Kind != "taskrun" || opts.Namespace != "test-ns" || opts.Cursor != "abc" {
+				t.Errorf("Unexpected options: %+v", opts)
+			}
+			if opts.Limit != defaultListLimit {
+				t.Errorf("Expected default limit %d, got %d", defaultListLimit, opts.Limit)
+			}
+			return &tektonresults.SinceResult{
+				Runs: []tektonresults.RunSummary{
+					{Name: "tr-1", UID: "uid-1", Labels: map[string]string{"app": "web", "tekton.dev/task": "build"}},
+				},
+				Cursor: "def",
+				More:   true,
+			}, nil
+		},
+	}
+	tool := newRunsSinceTool(Dependencies{Service: mock, DefaultNamespace: "test-ns"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"kind": "taskrun", "cursor": "abc", "labelKeys": []any{"app"}}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %v %s", err, getTextFromResult(result))
+	}
+
+	var got tektonresults.SinceResult
+	if err := json.Unmarshal([]byte(getTextFromResult(result)), &got); err != nil {
+		t.Fatalf("Expected JSON result: %v", err)
+	}
+	if got.Cursor != "def" || !got.More || len(got.Runs) != 1 {
+		t.Errorf("Unexpected result: %+v", got)
+	}
+	if labels := got.Runs[0].Labels; len(labels) != 1 || labels["app"] != "web" {
+		t.Errorf("Expected labels projected to app, got %v", labels)
+	}
+}
+
+func TestRunsSince_Error(t *testing.T) {
+	mock := &mockPipelineRunService{
+		runsSinceFunc: func(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error) {
+			return nil, &testError{msg: "invalid cursor"}
+		},
+	}
+	tool := newRunsSinceTool(Dependencies{Service: mock})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"kind": "pipelinerun", "cursor": "garbage"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if !result.IsError || !strings.Contains(getTextFromResult(result), "invalid cursor") {
+		t.Errorf("Expected tool error, got %s", getTextFromResult(result))
+	}
+}
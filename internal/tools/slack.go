@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// Slack limits the text of a section block to 3000 characters and a message
+// to 50 blocks. Slack output is split into text items that each fit in one
+// section block, so a bot can relay every item as a block unchanged.
+const (
+	slackBlockLimit = 3000
+	slackMaxBlocks  = 50
+	// slackExcerptLimit bounds the log excerpt of one TaskRun, leaving room
+	// for its heading within the block.
+	slackExcerptLimit = 2000
+)
+
+// slackEmoji returns the status emoji of a run.
+func slackEmoji(s tektonresults.RunSummary) string {
+	switch {
+	case strings.Contains(s.Reason, "Cancelled"):
+		return ":no_entry_sign:"
+	case strings.Contains(s.Reason, "Timeout"):
+		return ":alarm_clock:"
+	case s.Status == "True":
+		return ":white_check_mark:"
+	case s.Status == "False":
+		return ":x:"
+	default:
+		return ":hourglass_flowing_sand:"
+	}
+}
+
+// slackEscape escapes the characters Slack reserves for links and mentions.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// slackCode fences text as a code block. Fences inside the text would end
+// the block early, so they are broken up.
+func slackCode(text string) string {
+	text = strings.ReplaceAll(slackEscape(strings.TrimRight(text, "\n")), "```", "` ` `")
+	return "```\n" + text + "\n```"
+}
+
+// slackTail returns the last lines of text that fit in limit characters,
+// marking the cut.
+func slackTail(text string, limit int) string {
+	text = strings.TrimRight(text, "\n")
+	if len(text) <= limit {
+		return text
+	}
+	text = strings.ToValidUTF8(text[len(text)-limit:], "")
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[i+1:]
+	}
+	return "…\n" + text
+}
+
+// slackRunSummary renders the summary of a run in Slack mrkdwn.
+func slackRunSummary(kind string, detail *tektonresults.RunDetail) string {
+	s := detail.Summary
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s *%s %s/%s*: %s\n", slackEmoji(s), kind, slackEscape(s.Namespace), slackEscape(s.Name), slackEscape(runState(s)))
+	if s.PipelineTask != "" {
+		fmt.Fprintf(&b, "Pipeline task: *%s*\n", slackEscape(s.PipelineTask))
+	}
+	fmt.Fprintf(&b, "Started %s, took %s\n", format.Timestamp(timeOf(s.StartTime)), runDuration(s))
+	var links []string
+	if s.DashboardURL != "" {
+		links = append(links, fmt.Sprintf("<%s|Open in dashboard>", s.DashboardURL))
+	}
+	if c := s.Change; c != nil {
+		if c.PullRequestURL != "" {
+			links = append(links, fmt.Sprintf("<%s|Pull request #%d>", c.PullRequestURL, c.PullRequest))
+		}
+		if c.CommitURL != "" {
+			links = append(links, fmt.Sprintf("<%s|Commit %s>", c.CommitURL, shortSHA(c.SHA)))
+		}
+		if c.Check != nil && c.Check.URL != "" && c.Check.URL != c.CommitURL {
+			links = append(links, fmt.Sprintf("<%s|Check %s>", c.Check.URL, slackEscape(c.Check.Name)))
+		}
+	}
+	if len(links) > 0 {
+		b.WriteString(strings.Join(links, " · ") + "\n")
+	}
+	if failure := detail.ConfigFailure(); failure != nil {
+		fmt.Fprintf(&b, "Configuration failure *%s* in `%s`:\n%s", failure.Reason, failure.Field, slackCode(slackTail(failure.Message, slackExcerptLimit)))
+	} else if s.Message != "" {
+		b.WriteString(slackCode(s.Message))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// slackTaskRunLogs renders the TaskRuns of a PipelineRun in Slack mrkdwn:
+// one status line per TaskRun and, for TaskRuns that did not succeed, a code
+// block with the end of their logs.
+func slackTaskRunLogs(entries []taskRunLog) []string {
+	blocks := make([]string, 0, len(entries))
+	for _, e := range entries {
+		var b strings.Builder
+		name := e.TaskRun
+		if e.PipelineTask != "" {
+			name = e.PipelineTask
+		}
+		fmt.Fprintf(&b, "%s *%s* `%s`: %s", slackEmoji(e.run), slackEscape(name), slackEscape(e.TaskRun), slackEscape(e.Status))
+		if e.duration != "" {
+			fmt.Fprintf(&b, ", %s", e.duration)
+		}
+		switch {
+		case e.Error != "":
+			fmt.Fprintf(&b, "\n_Logs unavailable: %s_", slackEscape(e.Error))
+		case e.run.Status != "True" && strings.TrimSpace(e.Logs) != "":
+			b.WriteString("\n" + slackCode(slackTail(e.Logs, slackExcerptLimit)))
+		}
+		blocks = append(blocks, b.String())
+	}
+	return blocks
+}
+
+// slackResult packs mrkdwn blocks into text items of at most slackBlockLimit
+// characters, merging short blocks and truncating the rest once the block
+// budget is spent.
+func slackResult(blocks []string) *mcp.CallToolResult {
+	var items []string
+	for _, block := range blocks {
+		if len(block) > slackBlockLimit {
+			block = strings.ToValidUTF8(block[:slackBlockLimit-len("…")], "") + "…"
+		}
+		if n := len(items); n > 0 && len(items[n-1])+1+len(block) <= slackBlockLimit {
+			items[n-1] += "\n" + block
+			continue
+		}
+		items = append(items, block)
+	}
+	if len(items) > slackMaxBlocks {
+		items = append(items[:slackMaxBlocks-1], fmt.Sprintf("_%d more sections omitted to stay within Slack's limit of %d blocks._", len(items)-slackMaxBlocks+1, slackMaxBlocks))
+	}
+	result := &mcp.CallToolResult{}
+	for _, item := range items {
+		result.Content = append(result.Content, mcp.NewTextContent(item))
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestSlackRunSummary(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+	detail := &tektonresults.RunDetail{
+		Summary: tektonresults.RunSummary{
+			Name: "build-x7k2p", Namespace: "ci", Status: "False", Reason: "Failed",
+			Message:   "Tasks Completed: 2 (Failed: 1), <skipped> & more",
+			StartTime: &start, CompletionTime: &end,
+			DashboardURL: "https://tekton.example.com/#/namespaces/ci/pipelineruns/build-x7k2p",
+			Change: &tektonresults.SourceChange{
+				SHA: "abc1234def", CommitURL: "https://github.com/acme/widgets/commit/abc1234def",
+				PullRequest: 42, PullRequestURL: "https://github.com/acme/widgets/pull/42",
+			},
+		},
+		Raw: json.RawMessage(`{"kind":"PipelineRun"}`),
+	}
+
+	result, err := manifestResult("", detail, manifestParams{Output: "slack"})
+	if err != nil {
+		t.Fatalf("manifestResult() error = %v", err)
+	}
+	text := getTextFromResult(result)
+	for _, want := range []string{
+		":x: *PipelineRun ci/build-x7k2p*: Failed",
+		"took 1m 30s",
+		"<https://tekton.example.com/#/namespaces/ci/pipelineruns/build-x7k2p|Open in dashboard> · <https://github.com/acme/widgets/pull/42|Pull request #42> · <https://github.com/acme/widgets/commit/abc1234def|Commit abc1234>",
+		"```\nTasks Completed: 2 (Failed: 1), &lt;skipped&gt; &amp; more\n```",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "kind:") {
+		t.Errorf("Expected no manifest in Slack output:\n%s", text)
+	}
+}
+
+func TestSlackResult_FitsBlockLimits(t *testing.T) {
+	var entries []taskRunLog
+	for i := 0; i < 80; i++ {
+		entries = append(entries, taskRunLog{
+			TaskRun: "tr", PipelineTask: "task", Status: "Failed",
+			Logs: strings.Repeat("line with ``` fence\n", 500),
+			run:  tektonresults.RunSummary{Status: "False"},
+		})
+	}
+	entries = append(entries, taskRunLog{TaskRun: "ok", Status: "Succeeded", Logs: "fine", run: tektonresults.RunSummary{Status: "True"}})
+
+	result := slackResult(slackTaskRunLogs(entries))
+	if len(result.Content) != slackMaxBlocks {
+		t.Fatalf("Expected %d items, got %d", slackMaxBlocks, len(result.Content))
+	}
+	for i, c := range result.Content {
+		text := c.(mcp.TextContent).Text
+		if len(text) > slackBlockLimit {
+			t.Errorf("Item %d has %d characters, over the block limit", i, len(text))
+		}
+		if i < len(result.Content)-1 && strings.Count(text, "```")%2 != 0 {
+			t.Errorf("Item %d has an unbalanced code fence:\n%s", i, text)
+		}
+	}
+	first := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasPrefix(first, ":x: *task* `tr`: Failed\n```\n…\n") || !strings.Contains(first, "` ` `") {
+		t.Errorf("Unexpected first item:\n%s", first)
+	}
+	if last := result.Content[slackMaxBlocks-1].(mcp.TextContent).Text; !strings.Contains(last, "31 more sections omitted") {
+		t.Errorf("Expected a note about omitted sections, got %q", last)
+	}
+}
+
+func TestPipelineRunLogs_SlackOutput(t *testing.T) {
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			completed := metav1.Now()
+			return &tektonresults.RunDetail{Summary: tektonresults.RunSummary{Name: "pr", Namespace: "ci", UID: "uid", Status: "False", Reason: "Failed", CompletionTime: &completed}}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return []tektonresults.RunSummary{
+				{Name: "pr-build", PipelineTask: "build", Status: "True", Reason: "Succeeded", RecordName: "build"},
+				{Name: "pr-test", PipelineTask: "test", Status: "False", Reason: "Failed", RecordName: "test"},
+			}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			return recordName + " output\n", nil
+		},
+	}
+	tool := newPipelineRunLogsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "pr", "output": "slack"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError {
+		t.Fatalf("Result is error: %s", text)
+	}
+	for _, want := range []string{":x: *PipelineRun ci/pr*", ":white_check_mark: *build* `pr-build`: Succeeded", ":x: *test* `pr-test`: Failed\n```\ntest output\n```"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "build output") {
+		t.Errorf("Expected no excerpt for succeeded TaskRuns:\n%s", text)
+	}
+}
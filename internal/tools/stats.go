@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// toolStats counts the calls of every registered tool since the server
+// started. It is safe for concurrent use.
+type toolStats struct {
+	started time.Time
+	mu      sync.Mutex
+	calls   map[string]*toolCounter
+}
+
+type toolCounter struct {
+	calls, errors int
+	elapsed       time.Duration
+}
+
+func newToolStats() *toolStats {
+	return &toolStats{started: time.Now(), calls: map[string]*toolCounter{}}
+}
+
+// instrument wraps the handler of each tool to count its calls. A call that
+// returns an error result counts as an error, like one that fails outright.
+// Tools wrapped again, such as upstream tools mounted on every sync, keep
+// their counts.
+func (s *toolStats) instrument(tools []server.ServerTool) []server.ServerTool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		name, next := st.Tool.Name, st.Handler
+		if s.calls[name] == nil {
+			s.calls[name] = &toolCounter{}
+		}
+		st.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, req)
+			s.record(name, time.Since(start), err != nil || result != nil && result.IsError)
+			return result, err
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
+
+func (s *toolStats) record(name string, elapsed time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.calls[name]
+	c.calls++
+	c.elapsed += elapsed
+	if failed {
+		c.errors++
+	}
+}
+
+// toolCallStats is the per tool entry of server_stats.
+type toolCallStats struct {
+	Name        string  `json:"name"`
+	Calls       int     `json:"calls"`
+	Errors      int     `json:"errors"`
+	ErrorRate   float64 `json:"errorRate"`
+	AvgDuration string  `json:"avgDuration,omitempty"`
+}
+
+func (s *toolStats) snapshot() []toolCallStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]toolCallStats, 0, len(s.calls))
+	for name, c := range s.calls {
+		entry := toolCallStats{Name: name, Calls: c.calls, Errors: c.errors}
+		if c.calls > 0 {
+			entry.ErrorRate = float64(c.errors) / float64(c.calls)
+			entry.AvgDuration = (c.elapsed / time.Duration(c.calls)).Round(time.Millisecond).String()
+		}
+		out = append(out, entry)
+	}
+	// Busiest tools first.
+	slices.SortFunc(out, func(a, b toolCallStats) int {
+		if a.Calls != b.Calls {
+			return b.Calls - a.Calls
+		}
+		if a.Name < b.Name {
+			return -1
+		}
+		return 1
+	})
+	return out
+}
+
+// serverStats is the output of server_stats.
+type serverStats struct {
+	Uptime   string                              `json:"uptime"`
+	Tools    []toolCallStats                     `json:"tools"`
+	Caches   map[string]tektonresults.CacheStats `json:"caches"`
+	Upstream tektonresults.UpstreamStats         `json:"upstream"`
+}
+
+func newServerStatsTool(stats *toolStats, svc StatsReporter) server.ServerTool {
+	tool := newTool(
+		"server_stats",
+		[]toolExample{{}},
+		mcp.WithDescription("Report how this server has been used since it started: calls, error rate and average duration per tool, hit rates of the internal caches, and request counts and average latency per Tekton Results API endpoint. Use it to spot failing tools or a slow upstream without scraping metrics."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Server Stats")),
+	)
+
+	handler := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		out := serverStats{
+			Uptime:   time.Since(stats.started).Round(time.Second).String(),
+			Tools:    stats.snapshot(),
+			Caches:   svc.CacheStats(),
+			Upstream: svc.UpstreamStats(),
+		}
+		payload, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode server stats: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	}
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
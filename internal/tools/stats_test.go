@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+type statsService struct {
+	mockPipelineRunService
+}
+
+func (statsService) UpstreamStats() tektonresults.UpstreamStats {
+	return tektonresults.UpstreamStats{TotalRequests: map[string]int{"listRecords": 3}, AvgLatency: map[string]string{"listRecords": "120ms"}}
+}
+
+func (statsService) CacheStats() map[string]tektonresults.CacheStats {
+	return map[string]tektonresults.CacheStats{"runIndex": {Hits: 3, Misses: 1, HitRate: 0.75}}
+}
+
+func TestServerStats(t *testing.T) {
+	svc := &statsService{}
+	svc.listPipelineRunsFunc = func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+		if opts.Namespace == "broken" {
+			return nil, &testError{msg: "boom"}
+		}
+		return nil, nil
+	}
+	tools, err := serverTools(Dependencies{Service: svc, DefaultNamespace: "default"})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		st := tools[slices.IndexFunc(tools, func(st server.ServerTool) bool { return st.Tool.Name == name })]
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := st.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		return result
+	}
+
+	call("pipelinerun_list", map[string]any{"namespace": "ci"})
+	call("pipelinerun_list", map[string]any{"namespace": "broken"})
+	call("pipelinerun_list", map[string]any{"namespace": "ci"})
+	call("taskrun_get", map[string]any{}) // rejected by validation
+
+	var out serverStats
+	if err := json.Unmarshal([]byte(getTextFromResult(call("server_stats", nil))), &out); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if len(out.Tools) != len(tools) {
+		t.Errorf("Expected every registered tool to be listed, got %d of %d", len(out.Tools), len(tools))
+	}
+	first := out.Tools[0]
+	if first.Name != "pipelinerun_list" || first.Calls != 3 || first.Errors != 1 || first.AvgDuration == "" {
+		t.Errorf("Unexpected stats for the busiest tool: %+v", first)
+	}
+	if i := slices.IndexFunc(out.Tools, func(s toolCallStats) bool { return s.Name == "taskrun_get" }); i < 0 || out.Tools[i].ErrorRate != 1 {
+		t.Errorf("Expected error results to count as errors, got %+v", out.Tools)
+	}
+	if out.Caches["runIndex"].HitRate != 0.75 || out.Upstream.AvgLatency["listRecords"] != "120ms" {
+		t.Errorf("Expected service counters to be passed through, got %+v %+v", out.Caches, out.Upstream)
+	}
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// slowStart is the wait before a step from which it is flagged. Starting a
+// pod with cached images takes a few seconds; pulling an image from a
+// registry usually takes longer.
+const slowStart = 30 * time.Second
+
+func newTaskRunStepsTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("List the steps of a TaskRun with their image, exit code, duration and the wait before each started. The wait before the first step covers pod scheduling, init containers and image pulls, so long waits point at slow registries or nodes without cached images, even for runs whose pods are long gone."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("TaskRun Steps")),
+	}
+	opts = append(opts, selectorOptions("TaskRun", namespaceDefault)...)
+
+	tool := newTool("taskrun_steps", []toolExample{
+		{"name": "build-pipeline-run-x7k2p-compile", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipelineTask=compile", "namespace": namespaceDefault},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args selectorParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("TaskRun"); err != nil {
+			return deps.runError("TaskRun", err), nil
+		}
+		detail, err := deps.Service.GetTaskRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("TaskRun", err), nil
+		}
+		steps := detail.Steps()
+		if len(steps) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("TaskRun %s/%s has no step states; its pod never started.", detail.Summary.Namespace, detail.Summary.Name)), nil
+		}
+		return mcp.NewToolResultText(renderSteps(detail.Summary, steps)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// renderSteps formats the steps of a TaskRun in execution order, followed by
+// the steps that waited long enough to suggest an image pull.
+func renderSteps(run tektonresults.RunSummary, steps []tektonresults.StepState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Steps of TaskRun %s/%s\n", run.Namespace, run.Name)
+	if first := steps[0]; first.ExitCode != nil {
+		fmt.Fprintf(&b, "Pod startup before the first step (scheduling, init containers and image pulls): %s\n", format.Duration(first.Wait))
+	}
+	b.WriteString("\n")
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tIMAGE\tRESULT\tWAIT\tDURATION")
+	var slow []string
+	for _, s := range steps {
+		result, wait, duration := "not run", format.Placeholder, format.Placeholder
+		if s.ExitCode != nil {
+			result, wait, duration = fmt.Sprintf("exit %d", *s.ExitCode), format.Duration(s.Wait), format.Duration(s.Duration)
+			if s.ClockSkew {
+				duration = skewedDuration
+			}
+			if s.Wait >= slowStart {
+				slow = append(slow, fmt.Sprintf("- %s waited %s before starting (image %s)", s.Name, format.Duration(s.Wait), cmp.Or(s.Image, format.Placeholder)))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, cmp.Or(s.Image, format.Placeholder), result, wait, duration)
+	}
+	_ = w.Flush()
+
+	if len(slow) > 0 {
+		fmt.Fprintf(&b, "\nSlow starts of %s or more, likely image pulls:\n%s\n", format.Duration(slowStart), strings.Join(slow, "\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestTaskRunStepsTool(t *testing.T) {
+	mock := &mockPipelineRunService{
+		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary: tektonresults.RunSummary{Name: "build-x7k2p-compile", Namespace: "ci"},
+				Raw: json.RawMessage(`{"kind":"TaskRun","status":{
+					"startTime":"2025-01-01T09:58:00Z",
+					"taskSpec":{"steps":[{"name":"compile","image":"golang:1.24"},{"name":"upload","image":"crane"}]},
+					"steps":[
+						{"name":"compile","terminated":{"exitCode":0,"startedAt":"2025-01-01T10:00:00Z","finishedAt":"2025-01-01T10:03:00Z"}},
+						{"name":"upload","terminated":{"exitCode":1,"startedAt":"2025-01-01T10:03:01Z","finishedAt":"2025-01-01T10:03:11Z"}}
+					]}}`),
+			}, nil
+		},
+	}
+	tool := newTaskRunStepsTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "build-x7k2p-compile"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	for _, want := range []string{
+		"Pod startup before the first step (scheduling, init containers and image pulls): 2m",
+		"upload   crane",
+		"- compile waited 2m before starting (image golang:1.24)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "- upload waited") {
+		t.Errorf("A one second gap must not be flagged:\n%s", text)
+	}
+}
@@ -2,13 +2,12 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
 	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
 )
 
@@ -17,6 +16,9 @@ func taskRunTools(deps Dependencies) ([]server.ServerTool, error) {
 		newTaskRunListTool(deps),
 		newTaskRunGetTool(deps),
 		newTaskRunLogsTool(deps),
+		newTaskRunStepsTool(deps),
+		newTaskRunResultsTool(deps),
+		newTaskRunStatsTool(deps),
 	}, nil
 }
 
@@ -26,48 +28,100 @@ func newTaskRunListTool(deps Dependencies) server.ServerTool {
 		namespaceDefault = "default"
 	}
 
-	tool := mcp.NewTool(
-		"taskrun_list",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription("List Tekton TaskRuns stored by the Tekton Results service with optional namespace, label, and name prefix filters."),
 		mcp.WithToolAnnotation(readOnlyAnnotations("List TaskRuns")),
-		mcp.WithString("namespace",
-			mcp.Description("Kubernetes namespace to query. Use '-' to search across all namespaces."),
-			mcp.DefaultString(namespaceDefault),
-		),
-		mcp.WithString("labelSelector",
-			mcp.Description("Comma separated key=value selectors that must match run labels."),
+		namespacesOption(namespaceDefault),
+		mcp.WithString("task",
+			mcp.Description("Only return runs of this Task: spec.taskRef.name, or the tekton.dev/task label for runs with an embedded or resolved task spec."),
 			mcp.DefaultString(""),
+			examples("git-clone"),
 		),
+		labelSelectorOption("tekton.dev/pipeline=build-pipeline", "app=frontend,env=prod", "app=frontend,env!=dogfood"),
+		annotationSelectorOption(),
 		mcp.WithString("prefix",
 			mcp.Description("Optional TaskRun name prefix to match."),
 			mcp.DefaultString(""),
 		),
+		nameRegexOption("TaskRun"),
+		mcp.WithString("reason",
+			mcp.Description("Only return runs whose Succeeded condition has one of these reasons (comma separated, case insensitive), e.g. 'Failed', 'TaskRunTimeout' or 'CouldntGetTask'."),
+			mcp.DefaultString(""),
+			examples("Failed", "TaskRunTimeout,CouldntGetTask"),
+		),
+		statusOption(),
+		teamOption(),
+		serviceAccountOption(),
+		latestOnlyOption(),
+		groupByOption("TaskRun"),
+		groupDryRunOption(),
+		listOutputOption(),
+		orderByOption(),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of records to return (1-200)."),
 			mcp.DefaultNumber(defaultListLimit),
 			mcp.Min(1),
 			mcp.Max(maxListLimit),
 		),
-	)
-
-	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args listParams) (*mcp.CallToolResult, error) {
+	}
+	opts = append(opts, createdRangeOptions()...)
+	opts = append(opts, durationOptions()...)
+	opts = append(opts, pageTokenOption())
+	opts = append(opts, projectionOptions()...)
+
+	tool := newTool("taskrun_list", []toolExample{
+		{"namespace": namespaceDefault, "limit": 10},
+		{"namespace": "-", "labelSelector": "tekton.dev/pipeline=build-pipeline"},
+		{"namespace": namespaceDefault, "task": "git-clone", "status": "failed"},
+		{"namespace": namespaceDefault, "prefix": "build-pipeline-run-"},
+		{"namespace": "ci,staging", "limit": 20},
+		{"namespace": namespaceDefault, "labelKeys": []string{"tekton.dev/pipeline"}},
+		{"namespace": namespaceDefault, "reason": "Failed"},
+		{"namespace": "-", "team": "Payments"},
+		{"namespace": "-", "status": "running"},
+		{"namespace": namespaceDefault, "createdAfter": "2024-05-01", "createdBefore": "2024-05-02"},
+		{"namespace": namespaceDefault, "createdAfter": "7d", "status": "failed", "groupBy": "task"},
+		{"namespace": "-", "status": "failed", "output": "table"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args listParams) (*mcp.CallToolResult, error) {
+		if err := validateListOutput(args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		ns := normalizeNamespace(args.Namespace, namespaceDefault)
+		createdAfter, createdBefore, err := parseCreatedRange(args.CreatedAfter, args.CreatedBefore, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		opts := tektonresults.ListOptions{
-			Namespace:     ns,
-			LabelSelector: args.LabelSelector,
-			Prefix:        args.Prefix,
-			Limit:         sanitizeLimit(args.Limit),
+			Namespace:          ns,
+			LabelSelector:      args.LabelSelector,
+			AnnotationSelector: args.AnnotationSelector,
+			RefName:            args.Task,
+			ServiceAccount:     args.ServiceAccount,
+			Prefix:             args.Prefix,
+			NameRegex:          args.NameRegex,
+			Reason:             args.Reason,
+			Status:             args.Status,
+			MinDuration:        time.Duration(args.MinDurationSeconds) * time.Second,
+			MaxDuration:        time.Duration(args.MaxDurationSeconds) * time.Second,
+			CreatedAfter:       createdAfter,
+			CreatedBefore:      createdBefore,
+			Team:               args.Team,
+			LatestOnly:         args.LatestOnly,
+			Limit:              sanitizeLimit(args.Limit),
+			OrderBy:            args.OrderBy,
+			PageToken:          args.PageToken,
 		}
 
-		summaries, err := deps.Service.ListTaskRuns(ctx, opts)
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+		if args.GroupBy != "" {
+			return groupResult(ctx, req, args, "TaskRun", deps.Service.ListTaskRunPage, opts), nil
 		}
-		payload, err := json.MarshalIndent(summaries, "", "  ")
+		page, err := deps.Service.ListTaskRunPage(ctx, opts)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		return mcp.NewToolResultText(string(payload)), nil
+		return listResult(req, args, page), nil
 	})
 
 	return server.ServerTool{
@@ -82,80 +136,41 @@ func newTaskRunGetTool(deps Dependencies) server.ServerTool {
 		namespaceDefault = "default"
 	}
 
-	tool := mcp.NewTool(
-		"taskrun_get",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription("Get a Tekton TaskRun stored in Tekton Results. Provide a name for exact match or combine labelSelector/prefix to narrow results. Returns the full resource in YAML (default) or JSON format."),
 		mcp.WithToolAnnotation(readOnlyAnnotations("Get TaskRun")),
-		mcp.WithString("name",
-			mcp.Description("Exact TaskRun name. Optional if labelSelector/prefix uniquely identify a run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("namespace",
-			mcp.Description("Kubernetes namespace that owns the TaskRun. Use '-' to search across namespaces."),
-			mcp.DefaultString(namespaceDefault),
-		),
-		mcp.WithString("labelSelector",
-			mcp.Description("Comma separated key=value selectors that must match run labels."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("prefix",
-			mcp.Description("Optional TaskRun name prefix to disambiguate."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("uid",
-			mcp.Description("Exact TaskRun UID (unique identifier in Tekton Results database). This is the most efficient way to find a specific run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("output",
-			mcp.Description("Return format: 'yaml' (default) or 'json'."),
-			mcp.DefaultString("yaml"),
-		),
-		mcp.WithBoolean("selectLast",
-			mcp.Description("If true, automatically select the last (most recent) match when multiple TaskRuns match the filters. Defaults to true."),
-			mcp.DefaultBool(true),
-		),
-	)
-
-	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args getParams) (*mcp.CallToolResult, error) {
-		if args.Name == "" && args.Prefix == "" && args.UID == "" && strings.TrimSpace(args.LabelSelector) == "" {
-			return mcp.NewToolResultError("provide at least one of name, prefix, uid, or labelSelector to identify a TaskRun"), nil
-		}
+	}
+	opts = append(opts, selectorOptions("TaskRun", namespaceDefault)...)
+	opts = append(opts, manifestOptions()...)
 
-		// Default selectLast to true if not explicitly provided
-		selectLast := true
-		if params, ok := req.Params.Arguments.(map[string]interface{}); ok {
-			if val, exists := params["selectLast"]; exists {
-				if boolVal, ok := val.(bool); ok {
-					selectLast = boolVal
-				}
-			}
-		}
+	tool := newTool("taskrun_get", []toolExample{
+		{"name": "build-pipeline-run-x7k2p-compile", "namespace": namespaceDefault},
+		{"uid": "0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11", "namespace": namespaceDefault, "output": "json"},
+		{"prefix": "build-pipeline-run-", "selectLast": true},
+		{"name": "build-pipeline-run-x7k2p-compile", "namespace": namespaceDefault, "includeSummary": true},
+		{"name": "build-pipeline-run-x7k2p-compile", "namespace": namespaceDefault, "depth": "spec"},
+	}, opts...)
 
-		ns := normalizeNamespace(args.Namespace, namespaceDefault)
-		selector := tektonresults.RunSelector{
-			Namespace:     ns,
-			LabelSelector: args.LabelSelector,
-			Prefix:        args.Prefix,
-			Name:          args.Name,
-			UID:           args.UID,
-			SelectLast:    selectLast,
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args getParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("TaskRun"); err != nil {
+			return deps.runError("TaskRun", err), nil
 		}
+		selector := args.runSelector(req, namespaceDefault)
 
 		detail, err := deps.Service.GetTaskRun(ctx, selector)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return deps.runError("TaskRun", err), nil
 		}
 
-		output := strings.ToLower(strings.TrimSpace(args.Output))
-		if output == "" {
-			output = "yaml"
-		}
-
-		formatted, err := detail.Format(output)
+		lookupCheck(ctx, deps.Service, detail, args.manifestParams)
+		result, err := manifestResult("TaskRun", detail, args.manifestParams)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		return mcp.NewToolResultText(formatted), nil
+		if note := historyNote("TaskRun", detail); note != "" {
+			result.Content = append(result.Content, mcp.NewTextContent(note))
+		}
+		return result, nil
 	})
 
 	return server.ServerTool{
@@ -170,75 +185,47 @@ func newTaskRunLogsTool(deps Dependencies) server.ServerTool {
 		namespaceDefault = "default"
 	}
 
-	tool := mcp.NewTool(
-		"taskrun_logs",
+	opts := []mcp.ToolOption{
 		mcp.WithDescription("Retrieve stored logs for a completed Tekton TaskRun."),
 		mcp.WithToolAnnotation(readOnlyAnnotations("TaskRun Logs")),
-		mcp.WithString("name",
-			mcp.Description("Exact TaskRun name. Optional if labelSelector/prefix uniquely identify a run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("namespace",
-			mcp.Description("Kubernetes namespace for the TaskRun. Use '-' to search all namespaces."),
-			mcp.DefaultString(namespaceDefault),
-		),
-		mcp.WithString("labelSelector",
-			mcp.Description("Comma separated key=value selectors that must match run labels."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("prefix",
-			mcp.Description("Optional TaskRun name prefix when multiple runs share similar names."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithString("uid",
-			mcp.Description("Exact TaskRun UID (unique identifier in Tekton Results database). This is the most efficient way to find a specific run."),
-			mcp.DefaultString(""),
-		),
-		mcp.WithBoolean("selectLast",
-			mcp.Description("If true, automatically select the last (most recent) match when multiple TaskRuns match the filters. Defaults to true."),
-			mcp.DefaultBool(true),
-		),
-	)
-
-	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args logsParams) (*mcp.CallToolResult, error) {
-		if args.Name == "" && args.Prefix == "" && strings.TrimSpace(args.LabelSelector) == "" {
-			return mcp.NewToolResultError("provide at least one of name, prefix, uid, or labelSelector to target a TaskRun"), nil
-		}
+	}
+	opts = append(opts, selectorOptions("TaskRun", namespaceDefault)...)
+	opts = append(opts, containerOption())
 
-		// Default selectLast to true if not explicitly provided
-		selectLast := true
-		if params, ok := req.Params.Arguments.(map[string]interface{}); ok {
-			if val, exists := params["selectLast"]; exists {
-				if boolVal, ok := val.(bool); ok {
-					selectLast = boolVal
-				}
-			}
-		}
+	tool := newTool("taskrun_logs", []toolExample{
+		{"name": "build-pipeline-run-x7k2p-compile", "namespace": namespaceDefault},
+		{"uid": "0b7c3b4e-4a8f-4e7d-9a55-0f4d1c6e2a11", "namespace": namespaceDefault},
+		{"name": "build-pipeline-run-x7k2p-compile", "namespace": namespaceDefault, "container": "!sidecars"},
+	}, opts...)
 
-		ns := normalizeNamespace(args.Namespace, namespaceDefault)
-		selector := tektonresults.RunSelector{
-			Namespace:     ns,
-			LabelSelector: args.LabelSelector,
-			Prefix:        args.Prefix,
-			Name:          args.Name,
-			UID:           args.UID,
-			SelectLast:    selectLast,
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args logsParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("TaskRun"); err != nil {
+			return deps.runError("TaskRun", err), nil
 		}
+		selector := args.runSelector(req, namespaceDefault)
 
 		detail, err := deps.Service.GetTaskRun(ctx, selector)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return deps.runError("TaskRun", err), nil
 		}
 
 		if !detail.Completed() {
-			return mcp.NewToolResultError("logs are only available after the TaskRun has completed"), nil
+			return mcp.NewToolResultError(deps.Messages.Format(messages.LogsNotCompleted, map[string]string{"Kind": "TaskRun"})), nil
+		}
+		if failure := detail.ConfigFailure(); failure != nil {
+			return mcp.NewToolResultText(diagnosisText(failure, detail.Summary)), nil
 		}
 
 		logs, err := deps.Service.FetchLogs(ctx, detail.RecordName)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return deps.runError("TaskRun", err), nil
+		}
+		logs, note := containerLogs(logs, detail.Sidecars(), parseContainerFilter(args.Container))
+		result := mcp.NewToolResultText(logs)
+		if note != "" {
+			result.Content = append(result.Content, mcp.NewTextContent("Note: "+note+"."))
 		}
-		return mcp.NewToolResultText(logs), nil
+		return result, nil
 	})
 
 	return server.ServerTool{
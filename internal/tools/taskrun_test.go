@@ -14,11 +14,22 @@ import (
 
 // mockTaskRunService is a mock implementation of Service interface for testing TaskRun tools
 type mockTaskRunService struct {
-	listPipelineRunsFunc func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
-	listTaskRunsFunc     func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
-	getPipelineRunFunc   func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
-	getTaskRunFunc       func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
-	fetchLogsFunc        func(ctx context.Context, recordName string) (string, error)
+	listPipelineRunsFunc    func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
+	listTaskRunsFunc        func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
+	listPipelineRunPageFunc func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error)
+	listTaskRunPageFunc     func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error)
+	getPipelineRunFunc      func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
+	getTaskRunFunc          func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
+	getRunByRecordFunc      func(ctx context.Context, recordName string) (*tektonresults.RunDetail, error)
+	runsSinceFunc           func(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error)
+	listResultRecordsFunc   func(ctx context.Context, name string) (*tektonresults.ResultRecords, error)
+	fetchLogsFunc           func(ctx context.Context, recordName string) (string, error)
+	serverInfoFunc          func(ctx context.Context, refresh bool) tektonresults.ServerInfo
+	backendInfoFunc         func(ctx context.Context, namespace string) tektonresults.BackendInfo
+	checkStatusFunc         func(ctx context.Context, change tektonresults.SourceChange) (*tektonresults.CheckStatus, error)
+	pruneResultsFunc        func(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error)
+	rerunPipelineRunFunc    func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RerunResult, error)
+	cancelPipelineRunFunc   func(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error)
 }
 
 func (m *mockTaskRunService) ListPipelineRuns(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
@@ -35,6 +46,30 @@ func (m *mockTaskRunService) ListTaskRuns(ctx context.Context, opts tektonresult
 	return nil, nil
 }
 
+// ListPipelineRunPage falls back to listPipelineRunsFunc, returning a single
+// page, when the test does not page.
+func (m *mockTaskRunService) ListPipelineRunPage(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+	if m.listPipelineRunPageFunc != nil {
+		return m.listPipelineRunPageFunc(ctx, opts)
+	}
+	runs, err := m.ListPipelineRuns(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tektonresults.RunPage{Runs: runs}, nil
+}
+
+func (m *mockTaskRunService) ListTaskRunPage(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+	if m.listTaskRunPageFunc != nil {
+		return m.listTaskRunPageFunc(ctx, opts)
+	}
+	runs, err := m.ListTaskRuns(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tektonresults.RunPage{Runs: runs}, nil
+}
+
 func (m *mockTaskRunService) GetPipelineRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
 	if m.getPipelineRunFunc != nil {
 		return m.getPipelineRunFunc(ctx, selector)
@@ -49,6 +84,27 @@ func (m *mockTaskRunService) GetTaskRun(ctx context.Context, selector tektonresu
 	return nil, nil
 }
 
+func (m *mockTaskRunService) GetRunByRecord(ctx context.Context, recordName string) (*tektonresults.RunDetail, error) {
+	if m.getRunByRecordFunc != nil {
+		return m.getRunByRecordFunc(ctx, recordName)
+	}
+	return nil, nil
+}
+
+func (m *mockTaskRunService) RunsSince(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error) {
+	if m.runsSinceFunc != nil {
+		return m.runsSinceFunc(ctx, opts)
+	}
+	return &tektonresults.SinceResult{}, nil
+}
+
+func (m *mockTaskRunService) ListResultRecords(ctx context.Context, name string) (*tektonresults.ResultRecords, error) {
+	if m.listResultRecordsFunc != nil {
+		return m.listResultRecordsFunc(ctx, name)
+	}
+	return &tektonresults.ResultRecords{}, nil
+}
+
 func (m *mockTaskRunService) FetchLogs(ctx context.Context, recordName string) (string, error) {
 	if m.fetchLogsFunc != nil {
 		return m.fetchLogsFunc(ctx, recordName)
@@ -56,6 +112,68 @@ func (m *mockTaskRunService) FetchLogs(ctx context.Context, recordName string) (
 	return "", nil
 }
 
+func (m *mockTaskRunService) PruneResults(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error) {
+	if m.pruneResultsFunc != nil {
+		return m.pruneResultsFunc(ctx, opts)
+	}
+	return &tektonresults.PruneReport{}, nil
+}
+
+func (m *mockTaskRunService) RerunPipelineRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RerunResult, error) {
+	if m.rerunPipelineRunFunc != nil {
+		return m.rerunPipelineRunFunc(ctx, selector)
+	}
+	return nil, nil
+}
+
+func (m *mockTaskRunService) CancelPipelineRun(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error) {
+	if m.cancelPipelineRunFunc != nil {
+		return m.cancelPipelineRunFunc(ctx, selector, mode)
+	}
+	return nil, nil
+}
+
+func (m *mockTaskRunService) ServerInfo(ctx context.Context, refresh bool) tektonresults.ServerInfo {
+	if m.serverInfoFunc != nil {
+		return m.serverInfoFunc(ctx, refresh)
+	}
+	return tektonresults.ServerInfo{}
+}
+
+func (m *mockTaskRunService) UpstreamStats() tektonresults.UpstreamStats {
+	return tektonresults.UpstreamStats{}
+}
+
+func (m *mockTaskRunService) PipelineRunStats(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.RunStats, error) {
+	return &tektonresults.RunStats{}, nil
+}
+
+func (m *mockTaskRunService) TaskRunStats(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.TaskRunStats, error) {
+	return &tektonresults.TaskRunStats{RunStats: &tektonresults.RunStats{}}, nil
+}
+
+func (m *mockTaskRunService) UpstreamPressure() tektonresults.UpstreamPressure {
+	return tektonresults.UpstreamPressure{}
+}
+
+func (m *mockTaskRunService) BackendInfo(ctx context.Context, namespace string) tektonresults.BackendInfo {
+	if m.backendInfoFunc != nil {
+		return m.backendInfoFunc(ctx, namespace)
+	}
+	return tektonresults.BackendInfo{}
+}
+
+func (m *mockTaskRunService) CheckStatus(ctx context.Context, change tektonresults.SourceChange) (*tektonresults.CheckStatus, error) {
+	if m.checkStatusFunc != nil {
+		return m.checkStatusFunc(ctx, change)
+	}
+	return nil, nil
+}
+
+func (m *mockTaskRunService) CacheStats() map[string]tektonresults.CacheStats {
+	return nil
+}
+
 func TestTaskRunList_DefaultParameters(t *testing.T) {
 	mock := &mockTaskRunService{
 		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
@@ -104,6 +222,9 @@ func TestTaskRunList_CustomParameters(t *testing.T) {
 			if opts.Prefix != "my-task" {
 				t.Errorf("Expected prefix 'my-task', got %s", opts.Prefix)
 			}
+			if opts.Reason != "TaskRunTimeout" {
+				t.Errorf("Expected reason 'TaskRunTimeout', got %s", opts.Reason)
+			}
 			if opts.Limit != 15 {
 				t.Errorf("Expected limit 15, got %d", opts.Limit)
 			}
@@ -119,6 +240,7 @@ func TestTaskRunList_CustomParameters(t *testing.T) {
 		"namespace":     "-",
 		"labelSelector": "type=test",
 		"prefix":        "my-task",
+		"reason":        "TaskRunTimeout",
 		"limit":         float64(15),
 	}
 
@@ -465,17 +587,59 @@ func TestTaskRunLogs_ByName(t *testing.T) {
 	}
 }
 
+func TestTaskRunLogs_Container(t *testing.T) {
+	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	logs := "[build] compiling\n[dind] daemon ready\n[build] done\n"
+	mock := &mockTaskRunService{
+		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary:    tektonresults.RunSummary{CompletionTime: &completionTime},
+				RecordName: "test-ns/results/tr-uid/records/tr-uid",
+				Raw:        []byte(`{"kind":"TaskRun","status":{"sidecars":[{"name":"dind"}]}}`),
+			}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			return logs, nil
+		},
+	}
+	tool := newTaskRunLogsTool(Dependencies{Service: mock, DefaultNamespace: "test-ns"})
+
+	for _, tt := range []struct {
+		container, want string
+	}{
+		{"", "--- step build ---\ncompiling\n--- sidecar dind ---\ndaemon ready\n--- step build ---\ndone\n"},
+		{"!sidecars", "--- step build ---\ncompiling\ndone\n"},
+		{"sidecar-dind", "--- sidecar dind ---\ndaemon ready\n"},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"name": "my-task", "container": tt.container}
+		result, err := tool.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		if text := getTextFromResult(result); text != tt.want {
+			t.Errorf("container %q: got\n%s\nwant\n%s", tt.container, text, tt.want)
+		}
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "my-task", "container": "push"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "the logs hold step build, sidecar dind") {
+		t.Errorf("Expected a note naming the containers, got %+v", result.Content)
+	}
+}
+
 func TestTaskRunLogs_ByUID(t *testing.T) {
-	// Note: The validation logic in taskrun_logs doesn't check for UID,
-	// so we need to provide at least one of name/prefix/labelSelector as well.
-	// This is a known limitation in the current implementation.
 	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 	mock := &mockTaskRunService{
 		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
 			if selector.UID != "task-uid-abc" {
 				t.Errorf("Expected UID 'task-uid-abc', got %s", selector.UID)
 			}
-			// Name can be anything since UID takes precedence
 			return &tektonresults.RunDetail{
 				Summary: tektonresults.RunSummary{
 					RecordName:     "test-ns/results/task-uid-abc/records/task-uid-abc",
@@ -494,8 +658,7 @@ func TestTaskRunLogs_ByUID(t *testing.T) {
 
 	req := mcp.CallToolRequest{}
 	req.Params.Arguments = map[string]any{
-		"uid":  "task-uid-abc",
-		"name": "placeholder", // Required due to validation logic
+		"uid": "task-uid-abc",
 	}
 
 	result, err := tool.Handler(context.Background(), req)
@@ -602,3 +765,35 @@ func TestTaskRunLogs_FetchLogsError(t *testing.T) {
 		t.Errorf("Error message doesn't contain expected text: %s", getTextFromResult(result))
 	}
 }
+
+func TestTaskRunLogs_ConfigFailure(t *testing.T) {
+	completionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	mock := &mockTaskRunService{
+		getTaskRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{
+				Summary:    tektonresults.RunSummary{CompletionTime: &completionTime, DashboardURL: "https://tekton.example.com/#/namespaces/test-ns/taskruns/test"},
+				Raw:        json.RawMessage(`{"kind":"TaskRun","spec":{"taskRef":{"name":"compile"}},"status":{"conditions":[{"type":"Succeeded","status":"False","reason":"CouldntGetTask","message":"tasks.tekton.dev \"compile\" not found"}]}}`),
+				RecordName: "test-ns/results/tr-uid/records/tr-uid",
+			}, nil
+		},
+		fetchLogsFunc: func(ctx context.Context, recordName string) (string, error) {
+			t.Error("Expected no log fetch for a configuration failure")
+			return "", nil
+		},
+	}
+
+	tool := newTaskRunLogsTool(Dependencies{Service: mock, DefaultNamespace: "default"})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "test"}
+
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	for _, want := range []string{"Configuration failure: CouldntGetTask", "Field: spec.taskRef", `tasks.tekton.dev "compile" not found`, "Dashboard: https://tekton.example.com/#/namespaces/test-ns/taskruns/test"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in diagnosis, got:\n%s", want, text)
+		}
+	}
+}
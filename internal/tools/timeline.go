@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+// timeline is the output of pipelinerun_timeline. Offsets and durations are
+// in seconds so they can be plotted without parsing durations.
+type timeline struct {
+	PipelineRun     string         `json:"pipelineRun"` // namespace/name
+	Status          string         `json:"status"`
+	StartTime       *metav1.Time   `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time   `json:"completionTime,omitempty"`
+	DurationSeconds *float64       `json:"durationSeconds,omitempty"` // unset while running or when the clocks disagree
+	Tasks           []timelineTask `json:"tasks"`
+}
+
+type timelineTask struct {
+	TaskRun        string       `json:"taskRun"`
+	PipelineTask   string       `json:"pipelineTask,omitempty"`
+	Status         string       `json:"status"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// OffsetSeconds is the time from the PipelineRun starting to the
+	// TaskRun starting, unset when either has no start time.
+	OffsetSeconds   *float64 `json:"offsetSeconds,omitempty"`
+	DurationSeconds *float64 `json:"durationSeconds,omitempty"`
+	ClockSkew       bool     `json:"clockSkew,omitempty"`
+}
+
+func newPipelineRunTimelineTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("List the TaskRuns of a PipelineRun in the order they started, with the start and completion time, the offset from the start of the PipelineRun, the duration and the status of each, as JSON suitable for drawing a Gantt chart. TaskRuns that never started come last."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("PipelineRun Timeline")),
+	}
+	opts = append(opts, selectorOptions("PipelineRun", namespaceDefault)...)
+
+	tool := newTool("pipelinerun_timeline", []toolExample{
+		{"name": "build-pipeline-run-x7k2p", "namespace": namespaceDefault},
+		{"labelSelector": "tekton.dev/pipeline=build-pipeline", "namespace": namespaceDefault},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, req mcp.CallToolRequest, args selectorParams) (*mcp.CallToolResult, error) {
+		if err := args.validate("PipelineRun"); err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		detail, err := deps.Service.GetPipelineRun(ctx, args.runSelector(req, namespaceDefault))
+		if err != nil {
+			return deps.runError("PipelineRun", err), nil
+		}
+		taskRuns, err := deps.childTaskRuns(ctx, detail.Summary)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list TaskRuns of %s: %v", detail.Summary.Name, err)), nil
+		}
+		if len(taskRuns) == 0 {
+			if failure := detail.ConfigFailure(); failure != nil {
+				return mcp.NewToolResultText(diagnosisText(failure, detail.Summary)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("PipelineRun %s/%s has no TaskRuns (state: %s).", detail.Summary.Namespace, detail.Summary.Name, runState(detail.Summary))), nil
+		}
+
+		payload, err := json.MarshalIndent(buildTimeline(detail.Summary, taskRuns), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("format response: %v", err)), nil
+		}
+		result := mcp.NewToolResultText(string(payload))
+		if len(taskRuns) >= maxListLimit {
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Note: only the first %d TaskRuns of the PipelineRun are listed.", maxListLimit)))
+		}
+		if note := historyNote("PipelineRun", detail); note != "" {
+			result.Content = append(result.Content, mcp.NewTextContent(note))
+		}
+		return result, nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// buildTimeline orders the TaskRuns of run by start time, then by name;
+// TaskRuns without a start time come last.
+func buildTimeline(run tektonresults.RunSummary, taskRuns []tektonresults.RunSummary) timeline {
+	taskRuns = slices.Clone(taskRuns)
+	slices.SortStableFunc(taskRuns, func(a, b tektonresults.RunSummary) int {
+		switch {
+		case a.StartTime == nil && b.StartTime == nil:
+		case a.StartTime == nil:
+			return 1
+		case b.StartTime == nil:
+			return -1
+		default:
+			if c := a.StartTime.Compare(b.StartTime.Time); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	out := timeline{
+		PipelineRun:     run.Namespace + "/" + run.Name,
+		Status:          runState(run),
+		StartTime:       run.StartTime,
+		CompletionTime:  run.CompletionTime,
+		DurationSeconds: durationSeconds(run),
+		Tasks:           make([]timelineTask, 0, len(taskRuns)),
+	}
+	for _, tr := range taskRuns {
+		task := timelineTask{
+			TaskRun:         tr.Name,
+			PipelineTask:    tr.PipelineTask,
+			Status:          runState(tr),
+			StartTime:       tr.StartTime,
+			CompletionTime:  tr.CompletionTime,
+			DurationSeconds: durationSeconds(tr),
+			ClockSkew:       tr.ClockSkew,
+		}
+		if run.StartTime != nil && tr.StartTime != nil {
+			offset, _ := tektonresults.Elapsed(run.StartTime.Time, tr.StartTime.Time)
+			seconds := offset.Seconds()
+			task.OffsetSeconds = &seconds
+		}
+		out.Tasks = append(out.Tasks, task)
+	}
+	return out
+}
+
+// durationSeconds returns how long the run took, or nil while it runs or
+// when its timestamps are skewed.
+func durationSeconds(s tektonresults.RunSummary) *float64 {
+	d, ok := s.Duration()
+	if !ok || s.ClockSkew {
+		return nil
+	}
+	seconds := d.Seconds()
+	return &seconds
+}
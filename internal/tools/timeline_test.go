@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestPipelineRunTimelineTool(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) *metav1.Time {
+		t := metav1.NewTime(base.Add(time.Duration(seconds) * time.Second))
+		return &t
+	}
+	var listed tektonresults.ListOptions
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{Summary: tektonresults.RunSummary{
+				Name: "build-x7k2p", Namespace: "ci", UID: "pr-uid", Status: "False", Reason: "Failed", StartTime: at(0), CompletionTime: at(300),
+			}}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			listed = opts
+			return []tektonresults.RunSummary{
+				{Name: "build-x7k2p-deploy", PipelineTask: "deploy", Status: "False", Reason: "TaskRunCancelled"},
+				{Name: "build-x7k2p-test", PipelineTask: "test", Status: "False", Reason: "Failed", StartTime: at(70), CompletionTime: at(290)},
+				{Name: "build-x7k2p-lint", PipelineTask: "lint", Status: "True", Reason: "Succeeded", StartTime: at(70), CompletionTime: at(100)},
+				{Name: "build-x7k2p-clone", PipelineTask: "clone", Status: "True", Reason: "Succeeded", StartTime: at(5), CompletionTime: at(65)},
+			}, nil
+		},
+	}
+	tool := newPipelineRunTimelineTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "build-x7k2p"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", text)
+	}
+	if listed.Namespace != "ci" || listed.LabelSelector != "tekton.dev/pipelineRunUID=pr-uid" {
+		t.Errorf("Expected the TaskRuns of the run to be listed, got %+v", listed)
+	}
+
+	var got timeline
+	if err := json.Unmarshal([]byte(text), &got); err != nil {
+		t.Fatalf("Expected JSON, got %v: %s", err, text)
+	}
+	if got.PipelineRun != "ci/build-x7k2p" || got.Status != "Failed" || got.DurationSeconds == nil || *got.DurationSeconds != 300 {
+		t.Errorf("Unexpected run %+v", got)
+	}
+	var order []string
+	for _, task := range got.Tasks {
+		order = append(order, task.PipelineTask)
+	}
+	if strings.Join(order, ",") != "clone,lint,test,deploy" {
+		t.Fatalf("Expected the TaskRuns by start time, then name, got %v", order)
+	}
+	test := got.Tasks[2]
+	if test.OffsetSeconds == nil || *test.OffsetSeconds != 70 || test.DurationSeconds == nil || *test.DurationSeconds != 220 || test.Status != "Failed" {
+		t.Errorf("Unexpected test task %+v", test)
+	}
+	if deploy := got.Tasks[3]; deploy.OffsetSeconds != nil || deploy.DurationSeconds != nil || deploy.Status != "TaskRunCancelled" {
+		t.Errorf("Expected the TaskRun that never started without times, got %+v", deploy)
+	}
+}
+
+func TestPipelineRunTimelineTool_NoTaskRuns(t *testing.T) {
+	mock := &mockPipelineRunService{
+		getPipelineRunFunc: func(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error) {
+			return &tektonresults.RunDetail{Summary: tektonresults.RunSummary{Name: "build-x7k2p", Namespace: "ci", UID: "pr-uid", Status: "Unknown", Reason: "Pending"}}, nil
+		},
+		listTaskRunsFunc: func(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error) {
+			return nil, nil
+		},
+	}
+	tool := newPipelineRunTimelineTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "build-x7k2p"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if got := getTextFromResult(result); result.IsError || got != "PipelineRun ci/build-x7k2p has no TaskRuns (state: Pending)." {
+		t.Errorf("Unexpected result %q", got)
+	}
+}
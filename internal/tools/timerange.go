@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// timeBoundFormats lists the accepted time bound forms for tool descriptions
+// and error messages.
+const timeBoundFormats = "an RFC 3339 time like 2024-05-01T10:00:00Z, a date like 2024-05-01 (UTC) or an age like 24h or 7d"
+
+// createdRangeOptions declares the list tool properties that bound the
+// creation time of the returned runs.
+func createdRangeOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("createdAfter",
+			mcp.Description("Only return runs created at or after this time: "+timeBoundFormats+" meaning that long ago. Filtered by the Results API, so older history is not paged through."),
+			mcp.DefaultString(""),
+			examples("24h", "2024-05-01T10:00:00Z"),
+		),
+		mcp.WithString("createdBefore",
+			mcp.Description("Only return runs created before this time: "+timeBoundFormats+" meaning that long ago."),
+			mcp.DefaultString(""),
+			examples("7d", "2024-05-02"),
+		),
+	}
+}
+
+// parseCreatedRange parses the createdAfter and createdBefore arguments
+// relative to now. Unset bounds are zero.
+func parseCreatedRange(after, before string, now time.Time) (time.Time, time.Time, error) {
+	from, err := parseTimeBound("createdAfter", after, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parseTimeBound("createdBefore", before, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("createdAfter (%s) must be earlier than createdBefore (%s)", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+// parseTimeBound parses an RFC 3339 time, a date or an age before now.
+func parseTimeBound(name, value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	if age, ok := parseDays(value); ok && age > 0 {
+		return now.Add(-age).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q: expected %s", name, value, timeBoundFormats)
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCreatedRange(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		after, before string
+		wantFrom      time.Time
+		wantTo        time.Time
+		wantErr       string
+	}{
+		{"", "", time.Time{}, time.Time{}, ""},
+		{"24h", "", now.Add(-24 * time.Hour), time.Time{}, ""},
+		{"7d", "1d", now.Add(-7 * 24 * time.Hour), now.Add(-24 * time.Hour), ""},
+		{"2024-05-01T10:00:00+02:00", "2024-05-02", time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), ""},
+		{"yesterday", "", time.Time{}, time.Time{}, `invalid createdAfter "yesterday"`},
+		{"", "-1h", time.Time{}, time.Time{}, `invalid createdBefore "-1h"`},
+		{"1h", "2d", time.Time{}, time.Time{}, "must be earlier than createdBefore"},
+	}
+	for _, tt := range tests {
+		from, to, err := parseCreatedRange(tt.after, tt.before, now)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseCreatedRange(%q, %q) error = %v, want %q", tt.after, tt.before, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+			t.Errorf("parseCreatedRange(%q, %q) = %v, %v, %v; want %v, %v", tt.after, tt.before, from, to, err, tt.wantFrom, tt.wantTo)
+		}
+	}
+}
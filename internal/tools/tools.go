@@ -4,45 +4,254 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/enarha/tekton-results-mcp-server/internal/accounting"
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
 	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/enarha/tekton-results-mcp-server/internal/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// Service interface defines the methods that tools use from tektonresults.Service
-type Service interface {
+// RunReader lists and fetches PipelineRuns and TaskRuns.
+type RunReader interface {
 	ListPipelineRuns(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
 	ListTaskRuns(ctx context.Context, opts tektonresults.ListOptions) ([]tektonresults.RunSummary, error)
+	ListPipelineRunPage(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error)
+	ListTaskRunPage(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error)
 	GetPipelineRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
 	GetTaskRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RunDetail, error)
+	GetRunByRecord(ctx context.Context, recordName string) (*tektonresults.RunDetail, error)
+	RunsSince(ctx context.Context, opts tektonresults.SinceOptions) (*tektonresults.SinceResult, error)
+}
+
+// Aggregator computes statistics over many runs on the service side.
+type Aggregator interface {
+	PipelineRunStats(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.RunStats, error)
+	TaskRunStats(ctx context.Context, opts tektonresults.StatsOptions) (*tektonresults.TaskRunStats, error)
+}
+
+// ResultReader inspects the Results that group the records of a run.
+type ResultReader interface {
+	ListResultRecords(ctx context.Context, name string) (*tektonresults.ResultRecords, error)
+}
+
+// LogReader fetches the stored logs of a run.
+type LogReader interface {
 	FetchLogs(ctx context.Context, recordName string) (string, error)
 }
 
+// ServerInspector reports on the Tekton Results endpoint itself.
+type ServerInspector interface {
+	ServerInfo(ctx context.Context, refresh bool) tektonresults.ServerInfo
+}
+
+// BackendInspector reports on the Tekton Results installation.
+type BackendInspector interface {
+	BackendInfo(ctx context.Context, namespace string) tektonresults.BackendInfo
+}
+
+// ChangeInspector looks up the source change that triggered a run.
+type ChangeInspector interface {
+	CheckStatus(ctx context.Context, change tektonresults.SourceChange) (*tektonresults.CheckStatus, error)
+}
+
+// StatsReporter reports counters the service keeps since start.
+type StatsReporter interface {
+	UpstreamStats() tektonresults.UpstreamStats
+	UpstreamPressure() tektonresults.UpstreamPressure
+	CacheStats() map[string]tektonresults.CacheStats
+}
+
+// ResultPruner deletes stored Results. Only write tools depend on it.
+type ResultPruner interface {
+	PruneResults(ctx context.Context, opts tektonresults.PruneOptions) (*tektonresults.PruneReport, error)
+}
+
+// RunController changes live PipelineRuns in the cluster. Only live cluster
+// write tools depend on it.
+type RunController interface {
+	RerunPipelineRun(ctx context.Context, selector tektonresults.RunSelector) (*tektonresults.RerunResult, error)
+	CancelPipelineRun(ctx context.Context, selector tektonresults.RunSelector, mode tektonresults.CancelMode) (*tektonresults.CancelResult, error)
+}
+
+// Service combines every capability the tools use from tektonresults.Service.
+// New code should depend on the narrowest interface it needs.
+type Service interface {
+	RunReader
+	Aggregator
+	ResultReader
+	LogReader
+	ServerInspector
+	BackendInspector
+	ChangeInspector
+	StatsReporter
+	ResultPruner
+	RunController
+}
+
+var _ Service = (*tektonresults.Service)(nil)
+
 // Dependencies bundles the shared objects every tool relies on.
 type Dependencies struct {
 	Service          Service
 	DefaultNamespace string
+	AllowWrites      bool // register tools that modify or delete data in Tekton Results
+	LiveCluster      bool // register tools that act on live PipelineRuns through the Kubernetes API
+	// Messages words the errors tools return; nil uses the built-in wording.
+	Messages *messages.Catalog
+	// Usage accounts tool calls per namespace; nil counts them in a recorder
+	// only usage_report reads.
+	Usage *accounting.Recorder
+	// Signer signs every tool result; nil leaves results unsigned.
+	Signer *signing.Signer
+	// ShedWait is how long calls of analytics tools wait for the Results
+	// API to recover from throttling or failures before they are deferred;
+	// zero defers them at once.
+	ShedWait time.Duration
+	// OutputLimit is the most bytes of text a tool result returns at once.
+	// Larger results return their first page and a resource link to the
+	// rest. Zero returns every result whole.
+	OutputLimit int
+
+	outputs *outputStore // pages of large results; set by Add when OutputLimit is positive
 }
 
-// Add registers all Tekton Results tools with the MCP server.
+// Wrapper wraps tools served next to the Tekton Results tools, such as the
+// tools of upstream MCP servers, in the handlers every Tekton Results tool
+// goes through: signing, usage accounting, call statistics, error footers,
+// pagination, load shedding and dry runs. The tools share their state, so
+// server_stats and usage_report count them too.
+type Wrapper func(tools []server.ServerTool) []server.ServerTool
+
+// Add registers all Tekton Results tools, resource templates and prompts with
+// the MCP server.
 func Add(s *server.MCPServer, deps Dependencies) error {
+	_, err := add(s, deps)
+	return err
+}
+
+func add(s *server.MCPServer, deps Dependencies) (Wrapper, error) {
 	if deps.Service == nil {
-		return fmt.Errorf("tekton results service dependency is required")
+		return nil, fmt.Errorf("tekton results service dependency is required")
+	}
+	if deps.OutputLimit > 0 {
+		deps.outputs = newOutputStore()
+	}
+
+	tools, chain, err := chainedTools(deps)
+	if err != nil {
+		return nil, err
+	}
+	s.AddTools(tools...)
+	s.AddResourceTemplates(resourceTemplates(deps)...)
+	s.AddPrompts(prompts(deps)...)
+	return chain.wrap, nil
+}
+
+// ServerName is the server name reported to clients on initialize.
+const ServerName = "Tekton Results MCP Server"
+
+// ServerVersion is the server version reported to clients on initialize,
+// from version.String.
+var ServerVersion = version.String()
+
+// NewServer creates an MCP server that sends the Instructions for deps and
+// serves every tool Add registers.
+func NewServer(deps Dependencies) (*server.MCPServer, error) {
+	s, _, err := NewMountingServer(deps)
+	return s, err
+}
+
+// NewMountingServer creates the server NewServer creates, and returns the
+// Wrapper for the tools of other MCP servers mounted in it.
+func NewMountingServer(deps Dependencies) (*server.MCPServer, Wrapper, error) {
+	instructions, err := Instructions(deps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build server instructions: %w", err)
+	}
+	s := server.NewMCPServer(
+		ServerName,
+		ServerVersion,
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithLogging(),
+		server.WithInstructions(instructions),
+	)
+	wrap, err := add(s, deps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("add tools: %w", err)
+	}
+	return s, wrap, nil
+}
+
+// Definitions returns the definitions of the tools Add would register for
+// deps. The service is not needed, so it can be used to document the tools
+// without a cluster.
+func Definitions(deps Dependencies) ([]mcp.Tool, error) {
+	tools, err := serverTools(deps)
+	if err != nil {
+		return nil, err
 	}
+	defs := make([]mcp.Tool, 0, len(tools))
+	for _, st := range tools {
+		defs = append(defs, st.Tool)
+	}
+	return defs, nil
+}
 
+func serverTools(deps Dependencies) ([]server.ServerTool, error) {
+	tools, _, err := chainedTools(deps)
+	return tools, err
+}
+
+// toolChain holds the state of the handlers every served tool is wrapped in.
+type toolChain struct {
+	deps     Dependencies
+	stats    *toolStats
+	recorder *accounting.Recorder
+}
+
+func (c *toolChain) wrap(tools []server.ServerTool) []server.ServerTool {
+	tools = withDryRun(withLoadShedding(tools, c.deps))
+	tools = withPagination(withErrorFooter(tools, c.deps.Messages), c.deps.outputs, c.deps.OutputLimit)
+	return withSignature(withUsage(c.stats.instrument(tools), c.recorder), c.deps.Signer)
+}
+
+// chainedTools returns the tools of deps, wrapped, and the chain that wrapped
+// them.
+func chainedTools(deps Dependencies) ([]server.ServerTool, *toolChain, error) {
 	tools, err := pipelineRunTools(deps)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	taskTools, err := taskRunTools(deps)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	s.AddTools(append(tools, taskTools...)...)
-	return nil
+	tools = append(tools, taskTools...)
+	tools = append(tools, newRunGetByRecordTool(deps), newRunHistoryTool(deps), newRunsSinceTool(deps), newFailuresDigestTool(deps), newFailureRateSeriesTool(deps), newWorkspaceUsageTool(deps), newRunRecordsTool(deps.Service), newServerInfoTool(deps.Service), newBackendInfoTool(deps.Service))
+	tools = append(tools, newQueryTool(deps))
+	tools = append(tools, newQueryExplainTool(tools))
+	chain := &toolChain{deps: deps, stats: newToolStats(), recorder: deps.Usage}
+	if chain.recorder == nil {
+		chain.recorder = accounting.New(false)
+	}
+	tools = append(tools, newServerStatsTool(chain.stats, deps.Service))
+	tools = append(tools, newUsageReportTool(chain.recorder))
+	if deps.AllowWrites {
+		tools = append(tools, newResultsPruneTool(deps))
+	}
+	if deps.AllowWrites && deps.LiveCluster {
+		tools = append(tools, newPipelineRunRerunTool(deps), newPipelineRunCancelTool(deps))
+	}
+	return chain.wrap(tools), chain, nil
 }
 
 func readOnlyAnnotations(title string) mcp.ToolAnnotation {
@@ -55,6 +264,28 @@ func readOnlyAnnotations(title string) mcp.ToolAnnotation {
 	}
 }
 
+// createAnnotations describe tools that create objects without changing or
+// deleting existing ones.
+func createAnnotations(title string) mcp.ToolAnnotation {
+	return mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(false),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	}
+}
+
+func destructiveAnnotations(title string) mcp.ToolAnnotation {
+	return mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(true),
+		IdempotentHint:  mcp.ToBoolPtr(false),
+		OpenWorldHint:   mcp.ToBoolPtr(true),
+	}
+}
+
 func normalizeNamespace(input, def string) string {
 	ns := strings.TrimSpace(input)
 	switch strings.ToLower(ns) {
@@ -66,3 +297,12 @@ func normalizeNamespace(input, def string) string {
 		return ns
 	}
 }
+
+// timeOf unwraps an optional API timestamp; nil becomes the zero time, which
+// the format helpers render as a placeholder.
+func timeOf(t *metav1.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newTestServer returns an MCP server with all tools registered from deps.
+func newTestServer(t *testing.T, deps Dependencies) *server.MCPServer {
+	t.Helper()
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	if err := Add(s, deps); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return s
+}
+
+func TestAdd_RequiresService(t *testing.T) {
+	s := server.NewMCPServer("test", "0.0.0")
+	if err := Add(s, Dependencies{}); err == nil {
+		t.Error("Expected error without a service")
+	}
+}
+
+func TestAdd_WriteToolsGated(t *testing.T) {
+	for _, allow := range []bool{false, true} {
+		s := newTestServer(t, Dependencies{Service: &mockPipelineRunService{}, AllowWrites: allow})
+		if registered := s.GetTool("results_prune") != nil; registered != allow {
+			t.Errorf("AllowWrites=%v: results_prune registered=%v", allow, registered)
+		}
+		if s.GetTool("pipelinerun_list") == nil {
+			t.Errorf("AllowWrites=%v: read-only tools must always be registered", allow)
+		}
+	}
+}
+
+func TestAdd_LiveClusterToolsGated(t *testing.T) {
+	for _, tt := range []struct{ writes, cluster, want bool }{
+		{false, false, false},
+		{true, false, false},
+		{false, true, false},
+		{true, true, true},
+	} {
+		s := newTestServer(t, Dependencies{Service: &mockPipelineRunService{}, AllowWrites: tt.writes, LiveCluster: tt.cluster})
+		for _, name := range []string{"pipelinerun_rerun", "pipelinerun_cancel"} {
+			if registered := s.GetTool(name) != nil; registered != tt.want {
+				t.Errorf("AllowWrites=%v LiveCluster=%v: %s registered=%v", tt.writes, tt.cluster, name, registered)
+			}
+		}
+	}
+}
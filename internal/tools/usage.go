@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/accounting"
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+)
+
+// withUsage wraps the handler of each tool to account its calls to the
+// namespaces they read.
+func withUsage(tools []server.ServerTool, recorder *accounting.Recorder) []server.ServerTool {
+	wrapped := make([]server.ServerTool, 0, len(tools))
+	for _, st := range tools {
+		name, next, namespaceDefault := st.Tool.Name, st.Handler, declaredNamespace(st.Tool)
+		st.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, req)
+			call := accounting.Call{
+				Tool:    name,
+				Spaces:  callNamespaces(req, namespaceDefault),
+				Bytes:   resultBytes(result),
+				Elapsed: time.Since(start),
+				Failed:  err != nil || result != nil && result.IsError,
+				Session: sessionID(ctx),
+			}
+			recorder.Record(call)
+			return result, err
+		}
+		wrapped = append(wrapped, st)
+	}
+	return wrapped
+}
+
+// declaredNamespace returns the default of the namespace argument of tool,
+// or "" for tools without one.
+func declaredNamespace(tool mcp.Tool) string {
+	properties := tool.InputSchema.Properties
+	if tool.RawInputSchema != nil {
+		var schema struct {
+			Properties map[string]any `json:"properties"`
+		}
+		if err := json.Unmarshal(tool.RawInputSchema, &schema); err != nil {
+			return ""
+		}
+		properties = schema.Properties
+	}
+	prop, _ := properties["namespace"].(map[string]any)
+	def, _ := prop["default"].(string)
+	return def
+}
+
+// callNamespaces returns the namespaces a call reads: its namespace
+// argument, or the default namespace of the tool, or the namespace of its
+// recordName. Searches of all namespaces are reported as
+// accounting.AllNamespaces.
+func callNamespaces(req mcp.CallToolRequest, namespaceDefault string) []string {
+	input := cmp.Or(req.GetString("namespace", ""), namespaceDefault)
+	if input == "" {
+		if record := req.GetString("recordName", ""); record != "" {
+			input, _, _ = strings.Cut(record, "/")
+		}
+	}
+	var spaces []string
+	for _, ns := range strings.Split(input, ",") {
+		if ns = normalizeNamespace(ns, ""); ns != "" {
+			spaces = append(spaces, ns)
+		}
+	}
+	return spaces
+}
+
+// resultBytes returns the size of the text of result.
+func resultBytes(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+	n := 0
+	for _, content := range result.Content {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			n += len(c.Text)
+		case mcp.EmbeddedResource:
+			if text, ok := c.Resource.(mcp.TextResourceContents); ok {
+				n += len(text.Text)
+			}
+		}
+	}
+	return n
+}
+
+type usageReportParams struct {
+	Namespace string `json:"namespace"`
+}
+
+// namespaceUsage is the per namespace entry of usage_report.
+type namespaceUsage struct {
+	accounting.Usage
+	Served string `json:"served"`
+}
+
+// usageReport is the output of usage_report.
+type usageReport struct {
+	Since      string           `json:"since"`
+	Queries    int              `json:"queries"`
+	Served     string           `json:"served"`
+	Namespaces []namespaceUsage `json:"namespaces"`
+}
+
+func newUsageReportTool(recorder *accounting.Recorder) server.ServerTool {
+	tool := newTool("usage_report", []toolExample{
+		{},
+		{"namespace": "payments-ci"},
+	},
+		mcp.WithDescription("Report how much Tekton Results data clients of this server read per namespace since it started: tool calls, errors and bytes of results served, with the calls per tool. Namespaces serving the most data come first. Calls searching all namespaces are reported under '-', and calls reading no namespace, such as server_info, under '(none)'. Use it to see which teams' data assistants query most, for capacity planning or chargeback."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Usage Report")),
+		mcp.WithString("namespace",
+			mcp.Description("Only report these namespaces (comma separated)."),
+			mcp.DefaultString(""),
+		),
+	)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args usageReportParams) (*mcp.CallToolResult, error) {
+		only := map[string]bool{}
+		for _, ns := range strings.Split(args.Namespace, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				only[ns] = true
+			}
+		}
+		report := usageReport{
+			Since:      format.Timestamp(recorder.Started()),
+			Namespaces: []namespaceUsage{},
+		}
+		var served int64
+		for _, ns := range recorder.Snapshot() {
+			if len(only) > 0 && !only[ns.Namespace] {
+				continue
+			}
+			report.Queries += ns.Queries
+			served += ns.Bytes
+			report.Namespaces = append(report.Namespaces, namespaceUsage{Usage: ns, Served: format.Bytes(ns.Bytes)})
+		}
+		report.Served = format.Bytes(served)
+		payload, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode usage report: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/accounting"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestUsageReport(t *testing.T) {
+	svc := &mockPipelineRunService{
+		listPipelineRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{{Name: "build-1", Namespace: "ci"}}}, nil
+		},
+		getRunByRecordFunc: func(ctx context.Context, recordName string) (*tektonresults.RunDetail, error) {
+			return nil, &testError{msg: "not found"}
+		},
+	}
+	recorder := accounting.New(false)
+	tools, err := serverTools(Dependencies{Service: svc, DefaultNamespace: "ci", Usage: recorder})
+	if err != nil {
+		t.Fatalf("serverTools() error = %v", err)
+	}
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		st := tools[slices.IndexFunc(tools, func(st server.ServerTool) bool { return st.Tool.Name == name })]
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := st.Handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		return result
+	}
+
+	call("pipelinerun_list", nil) // the default namespace of the tool
+	call("pipelinerun_list", map[string]any{"namespace": "all"})
+	call("pipelinerun_list", map[string]any{"namespace": "ci, staging"})
+	call("run_get_by_record", map[string]any{"recordName": "team-a/results/x/records/y"})
+
+	var report usageReport
+	if err := json.Unmarshal([]byte(getTextFromResult(call("usage_report", nil))), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	queries := map[string]int{}
+	for _, ns := range report.Namespaces {
+		queries[ns.Namespace] = ns.Queries
+		if ns.Served == "" {
+			t.Errorf("Expected a readable size for %s", ns.Namespace)
+		}
+	}
+	want := map[string]int{"ci": 2, accounting.AllNamespaces: 1, "staging": 1, "team-a": 1}
+	for ns, n := range want {
+		if queries[ns] != n {
+			t.Errorf("Expected %d queries of %s, got %v", n, ns, queries)
+		}
+	}
+	if report.Namespaces[0].Namespace != "ci" || report.Namespaces[0].Bytes == 0 {
+		t.Errorf("Expected ci, which served the most, first: %+v", report.Namespaces)
+	}
+
+	if err := json.Unmarshal([]byte(getTextFromResult(call("usage_report", map[string]any{"namespace": "team-a"}))), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(report.Namespaces) != 1 || report.Namespaces[0].Tools["run_get_by_record"] != 1 {
+		t.Errorf("Expected only team-a, got %+v", report.Namespaces)
+	}
+}
@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/format"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+const (
+	defaultWorkspaceWindow  = 7 * 24 * time.Hour
+	defaultWorkspaceVolumes = 20
+	maxWorkspaceVolumes     = 100
+	// maxWorkspaceRuns bounds the runs read for one report; counts of a
+	// truncated report are incomplete.
+	maxWorkspaceRuns = 2000
+)
+
+type workspaceParams struct {
+	Pipeline      string `json:"pipeline"`
+	Task          string `json:"task"`
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	Team          string `json:"team"`
+	CreatedAfter  string `json:"createdAfter"`
+	CreatedBefore string `json:"createdBefore"`
+	Limit         int    `json:"limit"`
+}
+
+// volumeUsage aggregates the runs that bound workspaces to one volume: a
+// PVC, or the volumes a volumeClaimTemplate or emptyDir workspace created.
+type volumeUsage struct {
+	Volume     string
+	Type       string
+	Workspaces map[string]bool
+	Sizes      map[string]bool
+	SubPaths   map[string]bool
+	Runs       int
+	Failed     int
+	DiskFull   int
+	// Overlapping counts runs that held a shared PVC while a run of another
+	// PipelineRun held it too.
+	Overlapping int
+	spans       []volumeSpan
+}
+
+type volumeSpan struct {
+	owner      string
+	start, end time.Time
+}
+
+func newWorkspaceUsageTool(deps Dependencies) server.ServerTool {
+	namespaceDefault := deps.DefaultNamespace
+	if namespaceDefault == "" {
+		namespaceDefault = "default"
+	}
+
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Report how runs bind their workspaces to volumes: PVCs, volumeClaimTemplates and emptyDirs with their requested sizes and subPaths, how many runs used each, how many failed with \"no space left on device\", and how many held a shared PVC at the same time as runs of another PipelineRun. Helps debug full volumes and PVC contention. ConfigMap, Secret and projected workspaces are left out."),
+		mcp.WithToolAnnotation(readOnlyAnnotations("Workspace Usage")),
+		mcp.WithString("kind",
+			mcp.Description("Kind of run to read the bindings of. TaskRuns name the PVC each PipelineRun created from a volumeClaimTemplate and carry the step failure messages disk full errors show in."),
+			mcp.DefaultString("pipelinerun"),
+			mcp.Enum("pipelinerun", "taskrun"),
+		),
+		mcp.WithString("pipeline",
+			mcp.Description("Only read runs of this Pipeline; with kind taskrun, the TaskRuns of its PipelineRuns."),
+			mcp.DefaultString(""),
+			examples("build-pipeline"),
+		),
+		mcp.WithString("task",
+			mcp.Description("Only read TaskRuns of this Task. Implies kind taskrun."),
+			mcp.DefaultString(""),
+			examples("git-clone"),
+		),
+		namespacesOption(namespaceDefault),
+		labelSelectorOption(),
+		teamOption(),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Number of volumes to show, those with disk full failures and contention first (1-%d).", maxWorkspaceVolumes)),
+			mcp.DefaultNumber(defaultWorkspaceVolumes),
+			mcp.Min(1),
+			mcp.Max(maxWorkspaceVolumes),
+		),
+	}
+	opts = append(opts, createdRangeOptions()...)
+	opts = append(opts, dryRunOption())
+
+	tool := newTool("workspace_usage", []toolExample{
+		{"namespace": namespaceDefault},
+		{"pipeline": "build-pipeline", "kind": "taskrun", "createdAfter": "3d"},
+		{"namespace": "-", "task": "git-clone"},
+	}, opts...)
+
+	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, args workspaceParams) (*mcp.CallToolResult, error) {
+		kind := cmp.Or(strings.ToLower(strings.TrimSpace(args.Kind)), "pipelinerun")
+		if args.Task != "" {
+			kind = "taskrun"
+		}
+		if kind != "pipelinerun" && kind != "taskrun" {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid kind %q: expected pipelinerun or taskrun", args.Kind)), nil
+		}
+		now := time.Now()
+		createdAfter, createdBefore, err := parseCreatedRange(args.CreatedAfter, args.CreatedBefore, now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		createdAfter = cmp.Or(createdAfter, now.Add(-defaultWorkspaceWindow))
+
+		listOpts := tektonresults.ListOptions{
+			Namespace:     normalizeNamespace(args.Namespace, namespaceDefault),
+			LabelSelector: args.LabelSelector,
+			Team:          args.Team,
+			CreatedAfter:  createdAfter,
+			CreatedBefore: createdBefore,
+		}
+		list, subject := deps.Service.ListPipelineRunPage, "PipelineRuns"
+		switch {
+		case kind == "taskrun":
+			list, subject, listOpts.RefName = deps.Service.ListTaskRunPage, "TaskRuns", args.Task
+			if args.Pipeline != "" {
+				listOpts.LabelSelector = strings.Trim(listOpts.LabelSelector+",tekton.dev/pipeline="+args.Pipeline, ",")
+			}
+		case args.Pipeline != "":
+			listOpts.RefName = args.Pipeline
+		}
+
+		usage, runs, bound, truncated := map[string]*volumeUsage{}, 0, 0, false
+		for run, err := range eachRun(ctx, list, listOpts) {
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if runs == maxWorkspaceRuns {
+				truncated = true
+				break
+			}
+			runs++
+			if addWorkspaceUsage(usage, run, now) {
+				bound++
+			}
+		}
+		volumes := rankVolumes(usage)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Workspace usage of %s in namespace %s since %s: %d runs read, %d bound workspaces to volumes.\n", subject, listOpts.Namespace, createdAfter.UTC().Format(time.RFC3339), runs, bound)
+		if truncated {
+			fmt.Fprintf(&b, "Only the first %d runs were read; narrow the window or the selectors for complete counts.\n", maxWorkspaceRuns)
+		}
+		if len(volumes) == 0 {
+			return mcp.NewToolResultText(b.String()), nil
+		}
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultWorkspaceVolumes
+		}
+		limit = min(limit, maxWorkspaceVolumes)
+		b.WriteString(renderWorkspaceUsage(volumes, limit))
+		return mcp.NewToolResultText(b.String()), nil
+	})
+
+	return server.ServerTool{
+		Tool:    tool,
+		Handler: handler,
+	}
+}
+
+// addWorkspaceUsage files the volumes run bound its workspaces to and
+// reports whether it bound any. Runs still going hold their volumes until
+// now.
+func addWorkspaceUsage(usage map[string]*volumeUsage, run tektonresults.RunSummary, now time.Time) bool {
+	bound := false
+	seen := map[string]bool{}
+	for _, w := range run.Workspaces {
+		var volume string
+		switch w.Type {
+		case tektonresults.VolumePersistentVolumeClaim:
+			volume = fmt.Sprintf("pvc %s/%s", run.Namespace, w.ClaimName)
+		case tektonresults.VolumeClaimTemplate, tektonresults.VolumeEmptyDir, tektonresults.VolumeCSI:
+			volume = fmt.Sprintf("%s %s/%s", w.Type, run.Namespace, w.Name)
+		default:
+			continue
+		}
+		bound = true
+		u := usage[volume]
+		if u == nil {
+			u = &volumeUsage{Volume: volume, Type: w.Type, Workspaces: map[string]bool{}, Sizes: map[string]bool{}, SubPaths: map[string]bool{}}
+			usage[volume] = u
+		}
+		u.Workspaces[w.Name] = true
+		if w.Size != "" {
+			u.Sizes[w.Size] = true
+		}
+		if w.SubPath != "" {
+			u.SubPaths[w.SubPath] = true
+		}
+		// A run binding one volume to several workspaces counts once.
+		if seen[volume] {
+			continue
+		}
+		seen[volume] = true
+		u.Runs++
+		switch run.Outcome() {
+		case "failed", "timedout":
+			u.Failed++
+			if run.DiskFull() {
+				u.DiskFull++
+			}
+		}
+		if w.Type == tektonresults.VolumePersistentVolumeClaim && run.StartTime != nil {
+			end := now
+			if run.CompletionTime != nil {
+				end = run.CompletionTime.Time
+			}
+			owner := cmp.Or(run.Labels["tekton.dev/pipelineRun"], run.Name)
+			u.spans = append(u.spans, volumeSpan{owner: owner, start: run.StartTime.Time, end: end})
+		}
+	}
+	return bound
+}
+
+// rankVolumes counts overlapping runs and orders the volumes with disk full
+// failures first, then contention, then by use.
+func rankVolumes(usage map[string]*volumeUsage) []*volumeUsage {
+	volumes := slices.Collect(maps.Values(usage))
+	for _, u := range volumes {
+		u.Overlapping = overlappingSpans(u.spans)
+	}
+	slices.SortFunc(volumes, func(a, b *volumeUsage) int {
+		return cmp.Or(b.DiskFull-a.DiskFull, b.Overlapping-a.Overlapping, b.Runs-a.Runs, strings.Compare(a.Volume, b.Volume))
+	})
+	return volumes
+}
+
+// overlappingSpans counts the spans that overlap a span of another owner.
+// TaskRuns of one PipelineRun share its volumes by design.
+func overlappingSpans(spans []volumeSpan) int {
+	slices.SortFunc(spans, func(a, b volumeSpan) int { return a.start.Compare(b.start) })
+	overlaps := make([]bool, len(spans))
+	for i := range spans {
+		for j := i + 1; j < len(spans) && spans[j].start.Before(spans[i].end); j++ {
+			if spans[j].owner != spans[i].owner {
+				overlaps[i], overlaps[j] = true, true
+			}
+		}
+	}
+	count := 0
+	for _, o := range overlaps {
+		if o {
+			count++
+		}
+	}
+	return count
+}
+
+// renderWorkspaceUsage formats the first limit volumes as a table, followed by
+// advice for the volumes that filled up or were contended.
+func renderWorkspaceUsage(volumes []*volumeUsage, limit int) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VOLUME\tWORKSPACES\tSIZE\tSUBPATHS\tRUNS\tFAILED\tDISK FULL\tOVERLAPPING")
+	var advice []string
+	for i, u := range volumes {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n", u.Volume, joinKeys(u.Workspaces), joinKeys(u.Sizes), joinKeys(u.SubPaths), u.Runs, u.Failed, u.DiskFull, u.Overlapping)
+		if u.DiskFull > 0 {
+			advice = append(advice, fmt.Sprintf("- %s: %d runs failed with \"no space left on device\"; %s", u.Volume, u.DiskFull, diskFullAdvice(u)))
+		}
+		if u.Overlapping > 0 {
+			advice = append(advice, fmt.Sprintf("- %s: %d runs held the claim at the same time as runs of another PipelineRun. A ReadWriteOnce claim makes them wait for each other or fail to schedule on other nodes, and shared files can be overwritten; a volumeClaimTemplate gives every run its own volume.", u.Volume, u.Overlapping))
+		}
+	}
+	_ = w.Flush()
+	if len(volumes) > limit {
+		fmt.Fprintf(&b, "%d more volumes not shown.\n", len(volumes)-limit)
+	}
+	if len(advice) > 0 {
+		b.WriteString("\nFindings:\n")
+		b.WriteString(strings.Join(advice, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func diskFullAdvice(u *volumeUsage) string {
+	switch u.Type {
+	case tektonresults.VolumeClaimTemplate:
+		return fmt.Sprintf("request more storage in the volumeClaimTemplate (requested: %s).", joinKeys(u.Sizes))
+	case tektonresults.VolumeEmptyDir:
+		return "emptyDir volumes share the ephemeral storage of the node; raise the sizeLimit or move the workspace to a volumeClaimTemplate."
+	}
+	return "grow the claim or clean it up between runs, since every run writes to the same volume."
+}
+
+func joinKeys(set map[string]bool) string {
+	if len(set) == 0 {
+		return format.Placeholder
+	}
+	return strings.Join(slices.Sorted(maps.Keys(set)), ",")
+}
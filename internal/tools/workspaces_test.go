@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestWorkspaceUsageTool(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	run := func(name, pipelineRun string, start, minutes int, message string, workspaces ...tektonresults.Workspace) tektonresults.RunSummary {
+		s, e := metav1.NewTime(base.Add(time.Duration(start)*time.Minute)), metav1.NewTime(base.Add(time.Duration(start+minutes)*time.Minute))
+		status := "True"
+		if message != "" {
+			status = "False"
+		}
+		return tektonresults.RunSummary{
+			Name: name, Namespace: "ci", Labels: map[string]string{"tekton.dev/pipelineRun": pipelineRun},
+			StartTime: &s, CompletionTime: &e, Status: status, Reason: "Failed", Message: message, Workspaces: workspaces,
+		}
+	}
+	shared := tektonresults.Workspace{Name: "source", Type: tektonresults.VolumePersistentVolumeClaim, ClaimName: "shared"}
+	cache := tektonresults.Workspace{Name: "cache", Type: tektonresults.VolumeClaimTemplate, Size: "1Gi"}
+
+	var listed tektonresults.ListOptions
+	mock := &mockPipelineRunService{
+		listTaskRunPageFunc: func(ctx context.Context, opts tektonresults.ListOptions) (*tektonresults.RunPage, error) {
+			listed = opts
+			return &tektonresults.RunPage{Runs: []tektonresults.RunSummary{
+				// TaskRuns of one PipelineRun share its PVC by design.
+				run("a-clone", "a", 0, 5, "", shared),
+				run("a-build", "a", 2, 5, "", shared),
+				run("b-clone", "b", 4, 5, "", shared),
+				run("c-build", "c", 30, 5, "step build: write /cache/x: no space left on device", cache),
+				run("d-notify", "d", 30, 1, "", tektonresults.Workspace{Name: "token", Type: tektonresults.VolumeSecret, ClaimName: "slack"}),
+			}}, nil
+		},
+	}
+	tool := newWorkspaceUsageTool(Dependencies{Service: mock, DefaultNamespace: "ci"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"pipeline": "build", "kind": "taskrun", "createdAfter": "2025-03-01T00:00:00Z"}
+	result, err := tool.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	text := getTextFromResult(result)
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", text)
+	}
+	if listed.LabelSelector != "tekton.dev/pipeline=build" {
+		t.Errorf("Expected the TaskRuns of the Pipeline to be listed, got %q", listed.LabelSelector)
+	}
+	for _, want := range []string{
+		"5 runs read, 4 bound workspaces to volumes",
+		"volumeClaimTemplate ci/cache: 1 runs failed with \"no space left on device\"; request more storage in the volumeClaimTemplate (requested: 1Gi).",
+		"pvc ci/shared: 3 runs held the claim at the same time as runs of another PipelineRun.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "slack") {
+		t.Errorf("Expected Secret workspaces to be left out:\n%s", text)
+	}
+	// The disk full volume ranks first.
+	if strings.Index(text, "volumeClaimTemplate ci/cache") > strings.Index(text, "pvc ci/shared") {
+		t.Errorf("Expected the full volume first:\n%s", text)
+	}
+}
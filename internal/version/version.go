@@ -0,0 +1,23 @@
+// Package version reports the version of the server binary, which the server
+// sends to clients on initialize and the proxy sends to upstream servers.
+package version
+
+import "runtime/debug"
+
+// version is set by release builds:
+//
+//	go build -ldflags "-X github.com/enarha/tekton-results-mcp-server/internal/version.version=v0.2.0"
+var version string
+
+// String returns the version set at build time, or else the module version
+// the go command recorded, as for go install ...@v0.2.0, or "devel" for
+// builds from a checkout.
+func String() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
@@ -0,0 +1,14 @@
+package version
+
+import "testing"
+
+func TestString(t *testing.T) {
+	if got := String(); got == "" {
+		t.Error("Expected a version without build flags")
+	}
+	t.Cleanup(func() { version = "" })
+	version = "v1.2.3"
+	if got := String(); got != "v1.2.3" {
+		t.Errorf("String() = %q, want the version set at build time", got)
+	}
+}
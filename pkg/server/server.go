@@ -0,0 +1,334 @@
+// Package server builds the Tekton Results MCP server for embedding in other
+// binaries. NewServer does the wiring of the tekton-results-mcp-server
+// command and returns a ready MCP server with every tool registered; the
+// command only adds its flags, logging and transports. AddTools registers the
+// tools with an MCP server the caller already runs, such as a composite
+// server exposing tools of several backends.
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"k8s.io/client-go/rest"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/accounting"
+	"github.com/enarha/tekton-results-mcp-server/internal/config"
+	"github.com/enarha/tekton-results-mcp-server/internal/export"
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/proxy"
+	"github.com/enarha/tekton-results-mcp-server/internal/signing"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+	"github.com/enarha/tekton-results-mcp-server/internal/tools"
+)
+
+// Types of the configuration file settings a Config carries.
+type (
+	// NamespaceToken is a bearer token scoped to some namespaces.
+	NamespaceToken = tektonresults.NamespaceToken
+	// Team maps a team name to the label selectors of its runs.
+	Team = tektonresults.Team
+	// TokenStore reads the bearer token from a Kubernetes Secret or Vault.
+	TokenStore = tektonresults.TokenStore
+	// Upstream is an MCP server whose tools are mounted alongside these.
+	Upstream = proxy.Upstream
+)
+
+// Config configures an embedded server. Zero fields take the defaults of the
+// tekton-results-mcp-server command, so a Config holding only RESTConfig
+// serves the read-only tools through the Kubernetes API server.
+type Config struct {
+	// RESTConfig authenticates to the Kubernetes API server, which proxies
+	// the Results API. Required unless ResultsURL is set.
+	RESTConfig *rest.Config
+	// DefaultNamespace is queried when a tool call names no namespace;
+	// "default" when empty.
+	DefaultNamespace string
+
+	// ResultsURL reaches the Results API directly instead of through the
+	// Kubernetes API server, authenticating with BearerToken, or the token
+	// TokenStore reads.
+	ResultsURL         string
+	BearerToken        string
+	InsecureSkipVerify bool
+	TokenStore         TokenStore
+	// NamespaceTokens replace the bearer token for requests to their
+	// namespaces.
+	NamespaceTokens []NamespaceToken
+
+	// EnableWriteTools registers tools that delete data from Tekton
+	// Results; EnableClusterTools together with it registers tools that
+	// act on live PipelineRuns.
+	EnableWriteTools   bool
+	EnableClusterTools bool
+
+	ScanPageSize    int           // records per page of single-run lookups (default 50)
+	MaxScanPages    int           // pages a single-run lookup may scan (default 20)
+	UpstreamTimeout time.Duration // deadline of one Results API request (default 30s)
+	ShedWait        time.Duration // wait of analytics calls under upstream pressure before they are deferred (default 10s; negative defers them at once)
+	MaxOutputSize   string        // most text a tool result returns at once, as a quantity such as "64Ki" (the default), or "0" for no limit
+	MaxResponseSize string        // largest Results API response, as a quantity such as "64Mi" (the default)
+	DashboardURL    string        // template linking run summaries to a dashboard
+	ValidateSchemas bool          // report where stored runs depart from the Tekton v1 schema
+	LogArchive      string        // directory of exported logs read when the Results API cannot serve a log
+	Teams           []Team        // teams the team filter and run summaries name
+	GitHubToken     string        // looks up check runs of Pipelines as Code runs
+	GitHubAPIURL    string        // GitHub REST API for GitHub Enterprise Server (default https://api.github.com)
+	AccessLog       bool          // log every tool call at info level
+	FaultInjection  string        // synthetic Results API faults, for testing only
+
+	// Upstreams are MCP servers whose tools are mounted, prefixed with
+	// their name. Only NewServer mounts them.
+	Upstreams []Upstream
+
+	// ExportSink, when set, exports summaries of finished runs to a
+	// webhook or BigQuery table like -export-sink, authenticating with
+	// ExportToken. Only NewServer exports runs, from Run.
+	ExportSink      string
+	ExportToken     string
+	ExportInterval  time.Duration // time between exports (default 5m)
+	ExportNamespace string        // namespace whose runs are exported (default "-", all)
+	ExportState     string        // file keeping the export position across restarts
+
+	// Messages replaces the wording of tool errors, by message ID, like the
+	// messages of the configuration file.
+	Messages map[string]string
+
+	// SigningKey, when set, signs every tool result with HMAC-SHA256 like
+	// TEKTON_RESULTS_MCP_SIGNING_KEY. It must be at least 32 bytes.
+	SigningKey []byte
+}
+
+// Server is a Tekton Results MCP server with the background work of the
+// command: exporting runs and keeping the tools of upstreams mounted.
+type Server struct {
+	*mcpserver.MCPServer
+
+	svc       *tektonresults.Service
+	catalog   *messages.Catalog
+	recorder  *accounting.Recorder
+	upstreams *proxy.Proxy
+	exporter  *export.Exporter // nil without an export sink
+
+	completions *tools.CompletionRouter
+}
+
+// NewServer creates an MCP server serving the Tekton Results tools
+// configured by cfg, with the same instructions the command sends, and
+// mounts the tools of cfg.Upstreams. Call Run to export runs and keep the
+// upstream tools current.
+func NewServer(cfg Config) (*Server, error) {
+	srv, deps, conf, err := wire(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s, wrap, err := tools.NewMountingServer(deps)
+	if err != nil {
+		return nil, err
+	}
+	srv.MCPServer = s
+	srv.completions = tools.NewCompletionRouter(srv.svc, deps.DefaultNamespace)
+
+	srv.upstreams = proxy.New(s, conf.EnableWriteTools, wrap)
+	if len(conf.Upstreams) > 0 {
+		// Validate has checked the upstreams already.
+		_ = srv.upstreams.Configure(context.Background(), conf.Upstreams)
+		slog.Info("Mounted tools of upstream MCP servers", "upstreams", len(conf.Upstreams))
+	}
+
+	if conf.ExportSink != "" {
+		// Validate has parsed the sink already.
+		sink, _ := export.ParseSink(conf.ExportSink, conf.ExportToken)
+		srv.exporter, err = export.New(srv.svc, sink, export.Options{
+			Interval:  conf.ExportInterval,
+			Namespace: conf.ExportNamespace,
+			StateFile: conf.ExportState,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("start the run exporter: %w", err)
+		}
+		slog.Info("Exporting finished runs", "sink", sink.Name(), "interval", conf.ExportInterval, "namespace", conf.ExportNamespace)
+	}
+	return srv, nil
+}
+
+// AddTools registers the Tekton Results tools configured by cfg with s.
+// Tool names are not prefixed, so they must not clash with tools s already
+// serves. Upstreams and run export need NewServer.
+func AddTools(s *mcpserver.MCPServer, cfg Config) error {
+	if len(cfg.Upstreams) > 0 || cfg.ExportSink != "" {
+		return fmt.Errorf("upstreams and run export require NewServer")
+	}
+	_, deps, _, err := wire(cfg)
+	if err != nil {
+		return err
+	}
+	return tools.Add(s, deps)
+}
+
+// Run probes the Results API and logs the outcome, then exports runs and
+// syncs the tools of the upstreams until ctx is done.
+func (s *Server) Run(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	info := s.svc.Probe(probeCtx)
+	cancel()
+	if info.Error != "" {
+		slog.Warn("Tekton Results API probe failed", "endpoint", info.Endpoint, "error", info.Error)
+	} else {
+		slog.Info("Tekton Results API reachable", "endpoint", info.Endpoint, "apiVersion", info.APIVersion,
+			"identity", info.Identity, "namespaces", info.Namespaces, "latency", info.Latency)
+	}
+
+	if s.exporter != nil {
+		go s.exporter.Run(ctx)
+	}
+	s.upstreams.Run(ctx, proxy.SyncInterval)
+}
+
+// Reconfigure applies the settings of cfg the command reloads from its
+// configuration file: the lookup limits, namespace tokens, teams, upstreams
+// and messages. Other fields are ignored. Every setting is validated before
+// any is applied, so an invalid cfg leaves the running configuration as it
+// was.
+func (s *Server) Reconfigure(ctx context.Context, cfg Config) error {
+	conf := cfg.command()
+	// Validate covers the upstreams and messages, the service checks the
+	// namespace tokens and teams.
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+	settings := conf.Settings()
+	if err := s.svc.ValidateSettings(settings); err != nil {
+		return err
+	}
+	if err := s.svc.Reconfigure(settings); err != nil {
+		return err
+	}
+	if err := s.upstreams.Configure(ctx, conf.Upstreams); err != nil {
+		return err
+	}
+	return s.catalog.Set(conf.Messages)
+}
+
+// CompletionHandler answers completion/complete requests ahead of next, the
+// streamable HTTP handler of s, for the sessions valid in sessions. mcp-go
+// does not route these requests itself.
+func (s *Server) CompletionHandler(next http.Handler, sessions mcpserver.SessionIdManager) http.Handler {
+	return s.completions.Middleware(next, sessions)
+}
+
+// CompletionStdio returns the input and output to serve s over stdio with,
+// answering completion/complete requests read from in on out.
+func (s *Server) CompletionStdio(ctx context.Context, in io.Reader, out io.Writer) (io.Reader, io.Writer) {
+	return s.completions.Stdio(ctx, in, out)
+}
+
+// MetricsHandler serves the Prometheus metrics of the Results API requests,
+// the tool calls and the run export.
+func (s *Server) MetricsHandler() http.Handler {
+	results := s.svc.MetricsHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results.ServeHTTP(w, r)
+		s.recorder.WritePrometheus(w)
+		if s.exporter != nil {
+			s.exporter.WritePrometheus(w)
+		}
+	})
+}
+
+// wire validates cfg like the command validates its flags, connects to
+// Tekton Results and returns the dependencies of the tools.
+func wire(cfg Config) (*Server, tools.Dependencies, config.Config, error) {
+	if cfg.RESTConfig == nil && cfg.ResultsURL == "" {
+		return nil, tools.Dependencies{}, config.Config{}, fmt.Errorf("either RESTConfig or ResultsURL is required")
+	}
+	conf := cfg.command()
+	if err := conf.Validate(); err != nil {
+		return nil, tools.Dependencies{}, config.Config{}, err
+	}
+
+	svc, err := tektonresults.NewService(cfg.RESTConfig, conf.Overrides())
+	if err != nil {
+		return nil, tools.Dependencies{}, config.Config{}, fmt.Errorf("initialize Tekton Results client: %w", err)
+	}
+	// Validate has checked the messages and the signing key already.
+	catalog, _ := messages.New(conf.Messages)
+	var signer *signing.Signer
+	if conf.SigningKey != "" {
+		signer, _ = signing.New([]byte(conf.SigningKey))
+		slog.Info("Signing tool results", "keyId", signer.KeyID())
+	}
+	srv := &Server{svc: svc, catalog: catalog, recorder: accounting.New(conf.AccessLog)}
+	namespace := cfg.DefaultNamespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return srv, tools.Dependencies{
+		Service:          svc,
+		DefaultNamespace: namespace,
+		AllowWrites:      conf.EnableWriteTools,
+		LiveCluster:      conf.EnableClusterTools,
+		Messages:         catalog,
+		Usage:            srv.recorder,
+		Signer:           signer,
+		ShedWait:         conf.ShedWait,
+		OutputLimit:      conf.OutputLimit(),
+	}, conf, nil
+}
+
+// command returns the command configuration cfg amounts to, with the
+// command's defaults for zero fields.
+func (cfg Config) command() config.Config {
+	conf := config.Defaults()
+	conf.BaseURL = cfg.ResultsURL
+	conf.BearerToken = cfg.BearerToken
+	conf.InsecureSkipVerify = cfg.InsecureSkipVerify
+	conf.TokenStore = cfg.TokenStore
+	conf.NamespaceTokens = cfg.NamespaceTokens
+	conf.EnableWriteTools = cfg.EnableWriteTools
+	conf.EnableClusterTools = cfg.EnableClusterTools
+	conf.DashboardURL = cfg.DashboardURL
+	conf.ValidateSchemas = cfg.ValidateSchemas
+	conf.LogArchive = cfg.LogArchive
+	conf.Teams = cfg.Teams
+	conf.GitHubToken = cfg.GitHubToken
+	conf.GitHubAPIURL = cfg.GitHubAPIURL
+	conf.AccessLog = cfg.AccessLog
+	conf.FaultInjection = cfg.FaultInjection
+	conf.Upstreams = cfg.Upstreams
+	conf.ExportSink = cfg.ExportSink
+	conf.ExportToken = cfg.ExportToken
+	conf.ExportState = cfg.ExportState
+	conf.Messages = cfg.Messages
+	conf.SigningKey = string(cfg.SigningKey)
+	if cfg.ScanPageSize != 0 {
+		conf.ScanPageSize = cfg.ScanPageSize
+	}
+	if cfg.MaxScanPages != 0 {
+		conf.MaxScanPages = cfg.MaxScanPages
+	}
+	if cfg.UpstreamTimeout != 0 {
+		conf.UpstreamTimeout = cfg.UpstreamTimeout
+	}
+	if cfg.ShedWait != 0 {
+		conf.ShedWait = max(cfg.ShedWait, 0)
+	}
+	if cfg.MaxOutputSize != "" {
+		conf.MaxOutputSize = cfg.MaxOutputSize
+	}
+	if cfg.MaxResponseSize != "" {
+		conf.MaxResponseSize = cfg.MaxResponseSize
+	}
+	if cfg.ExportInterval != 0 {
+		conf.ExportInterval = cfg.ExportInterval
+	}
+	if cfg.ExportNamespace != "" {
+		conf.ExportNamespace = cfg.ExportNamespace
+	}
+	return conf
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/enarha/tekton-results-mcp-server/internal/config"
+	"github.com/enarha/tekton-results-mcp-server/internal/messages"
+	"github.com/enarha/tekton-results-mcp-server/internal/tektonresults"
+)
+
+func TestNewServer(t *testing.T) {
+	s, err := NewServer(Config{ResultsURL: "https://results.example.com", BearerToken: "token"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if s.GetTool("pipelinerun_list") == nil {
+		t.Error("Expected the read tools to be registered")
+	}
+	if s.GetTool("results_prune") != nil {
+		t.Error("Expected write tools to stay off unless enabled")
+	}
+}
+
+func TestAddTools(t *testing.T) {
+	s := mcpserver.NewMCPServer("composite", "1.0.0", mcpserver.WithToolCapabilities(true))
+	if err := AddTools(s, Config{ResultsURL: "https://results.example.com", EnableWriteTools: true}); err != nil {
+		t.Fatalf("AddTools() error = %v", err)
+	}
+	if s.GetTool("taskrun_list") == nil || s.GetTool("results_prune") == nil {
+		t.Errorf("Expected read and write tools, got %d tools", len(s.ListTools()))
+	}
+}
+
+func TestNewServer_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"no endpoint", Config{}, "either RESTConfig or ResultsURL is required"},
+		{"timeout", Config{ResultsURL: "https://results.example.com", UpstreamTimeout: -time.Second}, "upstream timeout"},
+		{"size", Config{ResultsURL: "https://results.example.com", MaxResponseSize: "lots"}, "max response size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServer(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestConfigCoversCommand keeps Config in step with the settings of the
+// command, which main.go passes through Config.
+func TestConfigCoversCommand(t *testing.T) {
+	// Settings of the command's flags, logging and transports.
+	commandOnly := map[string]bool{
+		"Transport": true, "Address": true, "SessionIdleTimeout": true, "MaxSessions": true,
+		"LogLevel": true, "KlogVerbosity": true, "StrictStdio": true, "ConfigFile": true, "ConfigPollInterval": true,
+	}
+	renamed := map[string]string{"BaseURL": "ResultsURL"}
+	cfg, command := reflect.TypeOf(Config{}), reflect.TypeOf(config.Config{})
+	for i := range command.NumField() {
+		f := command.Field(i)
+		if commandOnly[f.Name] {
+			continue
+		}
+		if _, ok := cfg.FieldByName(cmp.Or(renamed[f.Name], f.Name)); !ok {
+			t.Errorf("Config has no field for the command setting %s", f.Name)
+		}
+	}
+}
+
+func TestServer_Reconfigure(t *testing.T) {
+	s, err := NewServer(Config{ResultsURL: "https://results.example.com"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if err := s.Reconfigure(context.Background(), Config{ResultsURL: "https://results.example.com", Teams: []Team{{Name: "payments", Selectors: []string{"team=payments"}}}}); err != nil {
+		t.Errorf("Reconfigure() error = %v", err)
+	}
+	if err := s.Reconfigure(context.Background(), Config{Messages: map[string]string{"no-such-message": "x"}}); err == nil {
+		t.Error("Expected invalid messages to be rejected")
+	}
+
+	// A setting that fails validation leaves every other one as it was.
+	teams := func() string {
+		names, _ := s.svc.CompletionValues(context.Background(), tektonresults.CompleteTeam, "")
+		return strings.Join(names, ",")
+	}
+	if err := s.Reconfigure(context.Background(), Config{Teams: []Team{{Name: "platform", Selectors: []string{"team=platform"}}}, Messages: map[string]string{"no-such-message": "x"}}); err == nil {
+		t.Error("Expected invalid messages to be rejected")
+	}
+	if got := teams(); got != "payments" {
+		t.Errorf("Expected the teams to be kept, got %q", got)
+	}
+	duplicate := []Team{{Name: "platform", Selectors: []string{"team=platform"}}, {Name: "platform", Selectors: []string{"team=infra"}}}
+	if err := s.Reconfigure(context.Background(), Config{Teams: duplicate, Messages: map[string]string{string(messages.RunNotFound): "nothing here"}}); err == nil {
+		t.Error("Expected a team defined twice to be rejected")
+	}
+	if got := s.catalog.Format(messages.RunNotFound, nil); got != messages.Default(messages.RunNotFound) {
+		t.Errorf("Expected the messages to be kept, got %q", got)
+	}
+	if got := teams(); got != "payments" {
+		t.Errorf("Expected the teams to be kept, got %q", got)
+	}
+}
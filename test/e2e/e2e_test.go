@@ -0,0 +1,408 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const callTimeout = 60 * time.Second
+
+// stdioClient is a minimal MCP client speaking newline delimited JSON-RPC to
+// the server binary over stdin/stdout, exactly like a real MCP host would.
+type stdioClient struct {
+	t      *testing.T
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan string
+	nextID int
+	mu     sync.Mutex
+
+	initialized json.RawMessage // result of the initialize request
+}
+
+type rpcResponse struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+// first returns the first content item, which holds the payload of tools
+// that add notes as further items.
+func (r toolResult) first() string {
+	if len(r.Content) == 0 {
+		return ""
+	}
+	return r.Content[0].Text
+}
+
+func (r toolResult) text() string {
+	var parts []string
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func startServer(t *testing.T) *stdioClient {
+	t.Helper()
+	binary := os.Getenv("E2E_SERVER_BINARY")
+	if binary == "" {
+		t.Skip("E2E_SERVER_BINARY is not set; run hack/e2e.sh to execute the e2e suite")
+	}
+
+	cmd := exec.Command(binary, "-transport", "stdio")
+	cmd.Env = os.Environ()
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("stdin pipe: %v", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+
+	c := &stdioClient{t: t, cmd: cmd, stdin: stdin, lines: make(chan string, 16)}
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+		for scanner.Scan() {
+			c.lines <- scanner.Text()
+		}
+		close(c.lines)
+	}()
+	t.Cleanup(func() {
+		_ = stdin.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	c.initialized = c.call("initialize", map[string]any{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "e2e", "version": "0.0.1"},
+	})
+	c.notify("notifications/initialized")
+	return c
+}
+
+func (c *stdioClient) send(msg map[string]any) {
+	c.t.Helper()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("marshal request: %v", err)
+	}
+	if _, err := c.stdin.Write(append(data, '\n')); err != nil {
+		c.t.Fatalf("write request: %v", err)
+	}
+}
+
+func (c *stdioClient) notify(method string) {
+	c.t.Helper()
+	c.send(map[string]any{"jsonrpc": "2.0", "method": method})
+}
+
+func (c *stdioClient) call(method string, params any) json.RawMessage {
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	id := c.nextID
+	c.send(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+
+	deadline := time.After(callTimeout)
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				c.t.Fatalf("server closed stdout while waiting for %s", method)
+			}
+			var resp rpcResponse
+			if err := json.Unmarshal([]byte(line), &resp); err != nil {
+				c.t.Fatalf("server wrote non JSON-RPC output to stdout: %q", line)
+			}
+			if resp.ID == nil || *resp.ID != id {
+				continue // notification or unrelated response
+			}
+			if resp.Error != nil {
+				c.t.Fatalf("%s failed: %d %s", method, resp.Error.Code, resp.Error.Message)
+			}
+			return resp.Result
+		case <-deadline:
+			c.t.Fatalf("timed out waiting for %s", method)
+		}
+	}
+}
+
+func (c *stdioClient) callTool(name string, args map[string]any) toolResult {
+	c.t.Helper()
+	raw := c.call("tools/call", map[string]any{"name": name, "arguments": args})
+	var res toolResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		c.t.Fatalf("decode %s result: %v", name, err)
+	}
+	if res.IsError {
+		c.t.Fatalf("%s returned an error: %s", name, res.text())
+	}
+	return res
+}
+
+func namespace() string {
+	if ns := os.Getenv("E2E_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return "results-e2e"
+}
+
+func TestE2E_ToolsListed(t *testing.T) {
+	c := startServer(t)
+
+	var listed struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(c.call("tools/list", map[string]any{}), &listed); err != nil {
+		t.Fatalf("decode tools/list: %v", err)
+	}
+	names := map[string]bool{}
+	for _, tool := range listed.Tools {
+		names[tool.Name] = true
+	}
+	for _, want := range []string{"pipelinerun_list", "pipelinerun_get", "pipelinerun_logs", "pipelinerun_diff", "taskrun_list", "taskrun_get", "taskrun_logs", "run_get_by_record", "run_history", "runs_since", "run_records", "server_info", "backend_info", "query_explain", "server_stats"} {
+		if !names[want] {
+			t.Errorf("Expected tool %s to be registered", want)
+		}
+	}
+}
+
+// TestE2E_EveryTool calls each tool the server lists, so a tool added
+// without arguments here fails the suite until it is covered.
+func TestE2E_EveryTool(t *testing.T) {
+	c := startServer(t)
+	ns := namespace()
+
+	list := c.callTool("pipelinerun_list", map[string]any{"namespace": ns, "labelSelector": "e2e.tekton.dev/suite=mcp"})
+	var summaries []struct {
+		ResultName string `json:"resultName"`
+		RecordName string `json:"recordName"`
+	}
+	if err := json.Unmarshal([]byte(list.first()), &summaries); err != nil || len(summaries) == 0 {
+		t.Fatalf("decode pipelinerun_list output: %v, %s", err, list.first())
+	}
+
+	pipelineRun := map[string]any{"namespace": ns, "name": "e2e-sample-run"}
+	taskRun := map[string]any{"namespace": ns, "name": "e2e-standalone-run"}
+	arguments := map[string]map[string]any{
+		"pipelinerun_list":          {"namespace": ns},
+		"pipelinerun_get":           pipelineRun,
+		"pipelinerun_logs":          pipelineRun,
+		"pipelinerun_diff":          pipelineRun,
+		"pipelinerun_critical_path": pipelineRun,
+		"pipelinerun_results":       pipelineRun,
+		"pipelinerun_params":        pipelineRun,
+		"pipelinerun_timeline":      pipelineRun,
+		"pipelinerun_stats":         {"namespace": ns, "pipeline": "e2e-pipeline"},
+		"taskrun_list":              {"namespace": ns},
+		"taskrun_get":               taskRun,
+		"taskrun_logs":              taskRun,
+		"taskrun_steps":             taskRun,
+		"taskrun_results":           taskRun,
+		"taskrun_stats":             {"namespace": ns, "task": "e2e-echo"},
+		"run_get_by_record":         {"recordName": summaries[0].RecordName},
+		"run_history":               {"namespace": ns, "pipeline": "e2e-pipeline"},
+		"runs_since":                {"namespace": ns, "kind": "pipelinerun"},
+		"failures_digest":           {"namespace": ns},
+		"failure_rate_series":       {"namespace": ns, "pipeline": "e2e-pipeline"},
+		"workspace_usage":           {"namespace": ns},
+		"run_records":               {"name": summaries[0].ResultName},
+		"server_info":               {},
+		"backend_info":              {"namespace": ns},
+		"query":                     {"request": map[string]any{"filters": map[string]any{"namespace": ns, "pipeline": "e2e-pipeline"}, "limit": 1}},
+		"query_explain":             {"tool": "pipelinerun_list", "arguments": map[string]any{"namespace": ns}},
+		"server_stats":              {},
+		"usage_report":              {},
+		"results_prune":             {"namespace": ns, "olderThan": "8760h", "dryRun": true},
+	}
+	// Tools that change the cluster are only registered with -allow-writes
+	// and a live cluster, which the suite does not enable.
+	writes := map[string]bool{"pipelinerun_rerun": true, "pipelinerun_cancel": true}
+
+	var listed struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(c.call("tools/list", map[string]any{}), &listed); err != nil {
+		t.Fatalf("decode tools/list: %v", err)
+	}
+	for _, tool := range listed.Tools {
+		if writes[tool.Name] {
+			t.Errorf("Expected %s not to be registered without -allow-writes", tool.Name)
+			continue
+		}
+		args, ok := arguments[tool.Name]
+		if !ok {
+			t.Errorf("Tool %s has no e2e arguments; add it to this test", tool.Name)
+			continue
+		}
+		t.Run(tool.Name, func(t *testing.T) {
+			parent := c.t
+			c.t = t
+			defer func() { c.t = parent }()
+			if res := c.callTool(tool.Name, args); len(res.Content) == 0 {
+				t.Errorf("Expected %s to return content", tool.Name)
+			}
+		})
+	}
+}
+
+func TestE2E_Instructions(t *testing.T) {
+	c := startServer(t)
+
+	var result struct {
+		Instructions string `json:"instructions"`
+	}
+	if err := json.Unmarshal(c.initialized, &result); err != nil {
+		t.Fatalf("decode initialize result: %v", err)
+	}
+	for _, want := range []string{"pipelinerun_list", "default namespace"} {
+		if !strings.Contains(result.Instructions, want) {
+			t.Errorf("Expected %q in server instructions, got %q", want, result.Instructions)
+		}
+	}
+}
+
+func TestE2E_PipelineRunTools(t *testing.T) {
+	c := startServer(t)
+	ns := namespace()
+
+	list := c.callTool("pipelinerun_list", map[string]any{"namespace": ns, "labelSelector": "e2e.tekton.dev/suite=mcp"})
+	var summaries []struct {
+		Name       string `json:"name"`
+		UID        string `json:"uid"`
+		RecordName string `json:"recordName"`
+	}
+	if err := json.Unmarshal([]byte(list.first()), &summaries); err != nil {
+		t.Fatalf("decode pipelinerun_list output: %v", err)
+	}
+	if len(summaries) == 0 || summaries[0].Name != "e2e-sample-run" {
+		t.Fatalf("Expected e2e-sample-run in list, got %+v", summaries)
+	}
+
+	byRecord := c.callTool("run_get_by_record", map[string]any{"recordName": summaries[0].RecordName})
+	if !strings.Contains(byRecord.text(), "e2e-sample-run") {
+		t.Errorf("Expected manifest for record %s, got: %s", summaries[0].RecordName, byRecord.text())
+	}
+
+	byName := c.callTool("pipelinerun_get", map[string]any{"namespace": ns, "name": "e2e-sample-run"})
+	if !strings.Contains(byName.text(), "e2e-pipeline") {
+		t.Errorf("Expected manifest to reference e2e-pipeline, got: %s", byName.text())
+	}
+
+	byUID := c.callTool("pipelinerun_get", map[string]any{"namespace": ns, "uid": summaries[0].UID, "output": "json"})
+	if !strings.Contains(byUID.text(), summaries[0].UID) {
+		t.Errorf("Expected manifest for UID %s, got: %s", summaries[0].UID, byUID.text())
+	}
+
+	logs := c.callTool("pipelinerun_logs", map[string]any{"namespace": ns, "name": "e2e-sample-run"})
+	for _, marker := range []string{"e2e-marker: first", "e2e-marker: second"} {
+		if !strings.Contains(logs.text(), marker) {
+			t.Errorf("Expected logs to contain %q, got: %s", marker, logs.text())
+		}
+	}
+}
+
+func TestE2E_TaskRunTools(t *testing.T) {
+	c := startServer(t)
+	ns := namespace()
+
+	list := c.callTool("taskrun_list", map[string]any{"namespace": ns})
+	var summaries []struct {
+		Name string `json:"name"`
+		UID  string `json:"uid"`
+	}
+	if err := json.Unmarshal([]byte(list.first()), &summaries); err != nil {
+		t.Fatalf("decode taskrun_list output: %v", err)
+	}
+	// Two pipeline tasks plus the standalone TaskRun.
+	if len(summaries) < 3 {
+		t.Fatalf("Expected at least 3 TaskRuns, got %+v", summaries)
+	}
+
+	standalone := c.callTool("taskrun_get", map[string]any{"namespace": ns, "name": "e2e-standalone-run"})
+	if !strings.Contains(standalone.text(), "e2e-echo") {
+		t.Errorf("Expected manifest to reference e2e-echo, got: %s", standalone.text())
+	}
+
+	// TaskRuns that belong to a PipelineRun are stored under the parent's
+	// Result, which exercises the UID fallback search.
+	for _, s := range summaries {
+		if s.Name == "e2e-standalone-run" {
+			continue
+		}
+		res := c.callTool("taskrun_get", map[string]any{"namespace": ns, "uid": s.UID})
+		if !strings.Contains(res.text(), s.UID) {
+			t.Errorf("Expected manifest for UID %s, got: %s", s.UID, res.text())
+		}
+		break
+	}
+
+	logs := c.callTool("taskrun_logs", map[string]any{"namespace": ns, "name": "e2e-standalone-run"})
+	if !strings.Contains(logs.text(), "e2e-marker: standalone") {
+		t.Errorf("Expected standalone logs, got: %s", logs.text())
+	}
+}
+
+func TestE2E_ServerInfo(t *testing.T) {
+	c := startServer(t)
+
+	res := c.callTool("server_info", map[string]any{})
+	var info struct {
+		Endpoint   string `json:"endpoint"`
+		Namespaces int    `json:"namespaces"`
+		Error      string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(res.first()), &info); err != nil {
+		t.Fatalf("decode server_info output: %v", err)
+	}
+	if info.Error != "" {
+		t.Fatalf("Expected a successful probe, got error: %s", info.Error)
+	}
+	if info.Endpoint == "" || info.Namespaces < 1 {
+		t.Errorf("Expected endpoint and at least one namespace, got %+v", info)
+	}
+}
+
+func TestE2E_StdoutIsProtocolOnly(t *testing.T) {
+	c := startServer(t)
+	// Any stray non JSON-RPC line written to stdout fails call() above; a
+	// ping round trip after startup is enough to flush earlier output.
+	c.call("ping", map[string]any{})
+}